@@ -0,0 +1,139 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// breaker states
+const (
+	BreakerClosed   = "closed"
+	BreakerOpen     = "open"
+	BreakerHalfOpen = "halfOpen"
+)
+
+// ErrCircuitOpen endpoint failing, request short-circuited until cooldown
+var ErrCircuitOpen = errors.New("sd endpoint circuit open, too many consecutive failures, please retry later")
+
+// BreakerStatus circuit breaker state of endpoint
+type BreakerStatus struct {
+	Endpoint string
+	State    string
+	Failures int
+	// open until, zero when not open
+	OpenUntil time.Time
+}
+
+// breakerDoer open after threshold consecutive failures (transport error or 502/503/504),
+// fast fail in cooldown, then half open let one probe request through
+type breakerDoer struct {
+	doer      HttpRequestDoer
+	endpoint  string
+	threshold int
+	cooldown  time.Duration
+
+	lock      sync.Mutex
+	state     string
+	failures  int
+	openUntil time.Time
+	probing   bool
+}
+
+func newBreakerDoer(doer HttpRequestDoer, endpoint string, threshold int, cooldown time.Duration) *breakerDoer {
+	return &breakerDoer{
+		doer:      doer,
+		endpoint:  endpoint,
+		threshold: threshold,
+		cooldown:  cooldown,
+		state:     BreakerClosed,
+	}
+}
+
+func (b *breakerDoer) Do(req *http.Request) (*http.Response, error) {
+	if !b.allow() {
+		return nil, ErrCircuitOpen
+	}
+	resp, err := b.doer.Do(req)
+	// client gone, not endpoint fault
+	if err != nil && errors.Is(err, context.Canceled) {
+		b.release()
+		return resp, err
+	}
+	b.record(err == nil && !isGatewayFailure(resp.StatusCode))
+	return resp, err
+}
+
+// isGatewayFailure endpoint unreachable or overloaded, 500 of bad request (e.g. invalid params) not count
+func isGatewayFailure(statusCode int) bool {
+	switch statusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// allow request when closed, or as the only probe after cooldown
+func (b *breakerDoer) allow() bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	switch b.state {
+	case BreakerOpen:
+		if time.Now().Before(b.openUntil) {
+			return false
+		}
+		b.state = BreakerHalfOpen
+		b.probing = true
+		return true
+	case BreakerHalfOpen:
+		if b.probing {
+			return false
+		}
+		b.probing = true
+		return true
+	}
+	return true
+}
+
+// release probe without result
+func (b *breakerDoer) release() {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if b.state == BreakerHalfOpen {
+		b.probing = false
+	}
+}
+
+func (b *breakerDoer) record(success bool) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if success {
+		b.state = BreakerClosed
+		b.failures = 0
+		b.probing = false
+		return
+	}
+	b.failures++
+	if b.state == BreakerHalfOpen || b.failures >= b.threshold {
+		b.state = BreakerOpen
+		b.openUntil = time.Now().Add(b.cooldown)
+		b.probing = false
+	}
+}
+
+func (b *breakerDoer) status() BreakerStatus {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	status := BreakerStatus{Endpoint: b.endpoint, State: b.state, Failures: b.failures}
+	if b.state == BreakerOpen {
+		// cooldown passed, next request probe
+		if !time.Now().Before(b.openUntil) {
+			status.State = BreakerHalfOpen
+		} else {
+			status.OpenUntil = b.openUntil
+		}
+	}
+	return status
+}
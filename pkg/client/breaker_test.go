@@ -0,0 +1,122 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBreaker(t *testing.T) {
+	var fail, calls int32
+	sd := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		switch atomic.LoadInt32(&fail) {
+		case 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		case 2:
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer sd.Close()
+	m := NewManagerClient()
+	m.EnableBreaker(2, 100*time.Millisecond)
+	client := m.GetClient(sd.URL)
+	do := func() error {
+		req, _ := http.NewRequest(http.MethodGet, sd.URL, nil)
+		resp, err := client.Client.Do(req)
+		if err == nil {
+			resp.Body.Close()
+		}
+		return err
+	}
+
+	assert.Nil(t, do())
+	assert.Equal(t, BreakerClosed, m.BreakerStatus()[0].State)
+
+	// 500 of request itself not count
+	atomic.StoreInt32(&fail, 2)
+	assert.Nil(t, do())
+	assert.Nil(t, do())
+	assert.Nil(t, do())
+	assert.Equal(t, BreakerClosed, m.BreakerStatus()[0].State)
+	assert.Equal(t, 0, m.BreakerStatus()[0].Failures)
+	atomic.StoreInt32(&calls, 1)
+
+	// open after consecutive failures, not reach endpoint
+	atomic.StoreInt32(&fail, 1)
+	assert.Nil(t, do())
+	assert.Nil(t, do())
+	status := m.BreakerStatus()[0]
+	assert.Equal(t, BreakerOpen, status.State)
+	assert.Equal(t, 2, status.Failures)
+	assert.False(t, status.OpenUntil.IsZero())
+	assert.ErrorIs(t, do(), ErrCircuitOpen)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+
+	// half open probe fail, open again
+	time.Sleep(150 * time.Millisecond)
+	assert.Equal(t, BreakerHalfOpen, m.BreakerStatus()[0].State)
+	assert.Nil(t, do())
+	assert.ErrorIs(t, do(), ErrCircuitOpen)
+	assert.Equal(t, int32(4), atomic.LoadInt32(&calls))
+
+	// probe success close
+	atomic.StoreInt32(&fail, 0)
+	time.Sleep(150 * time.Millisecond)
+	assert.Nil(t, do())
+	assert.Nil(t, do())
+	status = m.BreakerStatus()[0]
+	assert.Equal(t, BreakerClosed, status.State)
+	assert.Equal(t, 0, status.Failures)
+
+	// disabled no breaker
+	m = NewManagerClient()
+	m.GetClient(sd.URL)
+	assert.Empty(t, m.BreakerStatus())
+}
+
+func TestBreakerHalfOpenSingleProbe(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	doer := doerFunc(func(req *http.Request) (*http.Response, error) {
+		close(started)
+		<-release
+		return nil, errors.New("connection refused")
+	})
+	b := newBreakerDoer(doer, "sd", 1, 0)
+	b.record(false)
+	done := make(chan error)
+	go func() {
+		_, err := b.Do(httptest.NewRequest(http.MethodGet, "/", nil))
+		done <- err
+	}()
+	// probe in flight, others fast fail
+	<-started
+	_, err := b.Do(httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	close(release)
+	assert.NotErrorIs(t, <-done, ErrCircuitOpen)
+
+	// canceled request not count
+	canceled := newBreakerDoer(doerFunc(func(req *http.Request) (*http.Response, error) {
+		return nil, context.Canceled
+	}), "sd", 1, time.Minute)
+	_, err = canceled.Do(httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, BreakerClosed, canceled.status().State)
+}
+
+type doerFunc func(req *http.Request) (*http.Response, error)
+
+func (f doerFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
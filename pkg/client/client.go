@@ -90,6 +90,59 @@ func WithRequestEditorFn(fn RequestEditorFn) ClientOption {
 
 // The interface specification for the client above.
 type ClientInterface interface {
+	// GetEnv request
+	GetEnv(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// TailSdLogs request
+	TailSdLogs(ctx context.Context, params *TailSdLogsParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetMaintenance request
+	GetMaintenance(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// SetMaintenanceWithBody request with any body
+	SetMaintenanceWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	SetMaintenance(ctx context.Context, body SetMaintenanceJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// ReconcileModels request
+	ReconcileModels(ctx context.Context, params *ReconcileModelsParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetModelDefaults request
+	GetModelDefaults(ctx context.Context, modelName string, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// UpdateModelDefaultsWithBody request with any body
+	UpdateModelDefaultsWithBody(ctx context.Context, modelName string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	UpdateModelDefaults(ctx context.Context, modelName string, body UpdateModelDefaultsJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// ListProfiles request
+	ListProfiles(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// DeleteProfile request
+	DeleteProfile(ctx context.Context, profileName string, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetProfile request
+	GetProfile(ctx context.Context, profileName string, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// UpdateProfileWithBody request with any body
+	UpdateProfileWithBody(ctx context.Context, profileName string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	UpdateProfile(ctx context.Context, profileName string, body UpdateProfileJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// SelfTestWithBody request with any body
+	SelfTestWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	SelfTest(ctx context.Context, body SelfTestJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetStats request
+	GetStats(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetStorage request
+	GetStorage(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetUsage request
+	GetUsage(ctx context.Context, params *GetUsageParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
 	// BatchUpdateResourceWithBody request with any body
 	BatchUpdateResourceWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
 
@@ -100,6 +153,11 @@ type ClientInterface interface {
 
 	DelSDFunc(ctx context.Context, body DelSDFuncJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
 
+	// ExtraBatchImagesWithBody request with any body
+	ExtraBatchImagesWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	ExtraBatchImages(ctx context.Context, body ExtraBatchImagesJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
 	// ExtraImagesWithBody request with any body
 	ExtraImagesWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
 
@@ -110,6 +168,11 @@ type ClientInterface interface {
 
 	Img2Img(ctx context.Context, body Img2ImgJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
 
+	// InterrogateWithBody request with any body
+	InterrogateWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	Interrogate(ctx context.Context, body InterrogateJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
 	// ListSdFunc request
 	ListSdFunc(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
 
@@ -126,6 +189,11 @@ type ClientInterface interface {
 
 	RegisterModel(ctx context.Context, body RegisterModelJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
 
+	// BatchDeleteModelsWithBody request with any body
+	BatchDeleteModelsWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	BatchDeleteModels(ctx context.Context, body BatchDeleteModelsJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
 	// DeleteModel request
 	DeleteModel(ctx context.Context, modelName string, reqEditors ...RequestEditorFn) (*http.Response, error)
 
@@ -137,31 +205,92 @@ type ClientInterface interface {
 
 	UpdateModel(ctx context.Context, modelName string, body UpdateModelJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
 
+	// DistributeModel request
+	DistributeModel(ctx context.Context, modelName string, reqEditors ...RequestEditorFn) (*http.Response, error)
+
 	// UpdateOptionsWithBody request with any body
 	UpdateOptionsWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
 
 	UpdateOptions(ctx context.Context, body UpdateOptionsJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
 
+	// ListPromptTemplates request
+	ListPromptTemplates(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// DeletePromptTemplate request
+	DeletePromptTemplate(ctx context.Context, templateName string, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetPromptTemplate request
+	GetPromptTemplate(ctx context.Context, templateName string, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// UpdatePromptTemplateWithBody request with any body
+	UpdatePromptTemplateWithBody(ctx context.Context, templateName string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	UpdatePromptTemplate(ctx context.Context, templateName string, body UpdatePromptTemplateJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
 	// Restart request
 	Restart(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
 
+	// GetCapabilities request
+	GetCapabilities(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetSharedTaskResult request
+	GetSharedTaskResult(ctx context.Context, token string, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// ListTasks request
+	ListTasks(ctx context.Context, params *ListTasksParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetTaskResultsWithBody request with any body
+	GetTaskResultsWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	GetTaskResults(ctx context.Context, body GetTaskResultsJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
 	// CancelTask request
 	CancelTask(ctx context.Context, taskId string, reqEditors ...RequestEditorFn) (*http.Response, error)
 
 	// GetTaskProgress request
 	GetTaskProgress(ctx context.Context, taskId string, reqEditors ...RequestEditorFn) (*http.Response, error)
 
+	// GetTaskReproduce request
+	GetTaskReproduce(ctx context.Context, taskId string, reqEditors ...RequestEditorFn) (*http.Response, error)
+
 	// GetTaskResult request
 	GetTaskResult(ctx context.Context, taskId string, reqEditors ...RequestEditorFn) (*http.Response, error)
 
+	// ShareTask request
+	ShareTask(ctx context.Context, taskId string, params *ShareTaskParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
 	// Txt2ImgWithBody request with any body
 	Txt2ImgWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
 
 	Txt2Img(ctx context.Context, body Txt2ImgJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// Txt2ImgCachedWithBody request with any body
+	Txt2ImgCachedWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	Txt2ImgCached(ctx context.Context, body Txt2ImgCachedJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// Txt2ImgMultiWithBody request with any body
+	Txt2ImgMultiWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	Txt2ImgMulti(ctx context.Context, body Txt2ImgMultiJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// DeleteUserImagesWithBody request with any body
+	DeleteUserImagesWithBody(ctx context.Context, user string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	DeleteUserImages(ctx context.Context, user string, body DeleteUserImagesJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// ListUserImages request
+	ListUserImages(ctx context.Context, user string, params *ListUserImagesParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetUserStats request
+	GetUserStats(ctx context.Context, user string, params *GetUserStatsParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetVersion request
+	GetVersion(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
 }
 
-func (c *Client) BatchUpdateResourceWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
-	req, err := NewBatchUpdateResourceRequestWithBody(c.Server, contentType, body)
+func (c *Client) GetEnv(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetEnvRequest(c.Server)
 	if err != nil {
 		return nil, err
 	}
@@ -172,8 +301,8 @@ func (c *Client) BatchUpdateResourceWithBody(ctx context.Context, contentType st
 	return c.Client.Do(req)
 }
 
-func (c *Client) BatchUpdateResource(ctx context.Context, body BatchUpdateResourceJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
-	req, err := NewBatchUpdateResourceRequest(c.Server, body)
+func (c *Client) TailSdLogs(ctx context.Context, params *TailSdLogsParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewTailSdLogsRequest(c.Server, params)
 	if err != nil {
 		return nil, err
 	}
@@ -184,8 +313,8 @@ func (c *Client) BatchUpdateResource(ctx context.Context, body BatchUpdateResour
 	return c.Client.Do(req)
 }
 
-func (c *Client) DelSDFuncWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
-	req, err := NewDelSDFuncRequestWithBody(c.Server, contentType, body)
+func (c *Client) GetMaintenance(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetMaintenanceRequest(c.Server)
 	if err != nil {
 		return nil, err
 	}
@@ -196,8 +325,8 @@ func (c *Client) DelSDFuncWithBody(ctx context.Context, contentType string, body
 	return c.Client.Do(req)
 }
 
-func (c *Client) DelSDFunc(ctx context.Context, body DelSDFuncJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
-	req, err := NewDelSDFuncRequest(c.Server, body)
+func (c *Client) SetMaintenanceWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewSetMaintenanceRequestWithBody(c.Server, contentType, body)
 	if err != nil {
 		return nil, err
 	}
@@ -208,8 +337,8 @@ func (c *Client) DelSDFunc(ctx context.Context, body DelSDFuncJSONRequestBody, r
 	return c.Client.Do(req)
 }
 
-func (c *Client) ExtraImagesWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
-	req, err := NewExtraImagesRequestWithBody(c.Server, contentType, body)
+func (c *Client) SetMaintenance(ctx context.Context, body SetMaintenanceJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewSetMaintenanceRequest(c.Server, body)
 	if err != nil {
 		return nil, err
 	}
@@ -220,8 +349,8 @@ func (c *Client) ExtraImagesWithBody(ctx context.Context, contentType string, bo
 	return c.Client.Do(req)
 }
 
-func (c *Client) ExtraImages(ctx context.Context, body ExtraImagesJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
-	req, err := NewExtraImagesRequest(c.Server, body)
+func (c *Client) ReconcileModels(ctx context.Context, params *ReconcileModelsParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewReconcileModelsRequest(c.Server, params)
 	if err != nil {
 		return nil, err
 	}
@@ -232,8 +361,8 @@ func (c *Client) ExtraImages(ctx context.Context, body ExtraImagesJSONRequestBod
 	return c.Client.Do(req)
 }
 
-func (c *Client) Img2ImgWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
-	req, err := NewImg2ImgRequestWithBody(c.Server, contentType, body)
+func (c *Client) GetModelDefaults(ctx context.Context, modelName string, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetModelDefaultsRequest(c.Server, modelName)
 	if err != nil {
 		return nil, err
 	}
@@ -244,8 +373,8 @@ func (c *Client) Img2ImgWithBody(ctx context.Context, contentType string, body i
 	return c.Client.Do(req)
 }
 
-func (c *Client) Img2Img(ctx context.Context, body Img2ImgJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
-	req, err := NewImg2ImgRequest(c.Server, body)
+func (c *Client) UpdateModelDefaultsWithBody(ctx context.Context, modelName string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewUpdateModelDefaultsRequestWithBody(c.Server, modelName, contentType, body)
 	if err != nil {
 		return nil, err
 	}
@@ -256,8 +385,8 @@ func (c *Client) Img2Img(ctx context.Context, body Img2ImgJSONRequestBody, reqEd
 	return c.Client.Do(req)
 }
 
-func (c *Client) ListSdFunc(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
-	req, err := NewListSdFuncRequest(c.Server)
+func (c *Client) UpdateModelDefaults(ctx context.Context, modelName string, body UpdateModelDefaultsJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewUpdateModelDefaultsRequest(c.Server, modelName, body)
 	if err != nil {
 		return nil, err
 	}
@@ -268,8 +397,8 @@ func (c *Client) ListSdFunc(ctx context.Context, reqEditors ...RequestEditorFn)
 	return c.Client.Do(req)
 }
 
-func (c *Client) LoginWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
-	req, err := NewLoginRequestWithBody(c.Server, contentType, body)
+func (c *Client) ListProfiles(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewListProfilesRequest(c.Server)
 	if err != nil {
 		return nil, err
 	}
@@ -280,8 +409,8 @@ func (c *Client) LoginWithBody(ctx context.Context, contentType string, body io.
 	return c.Client.Do(req)
 }
 
-func (c *Client) Login(ctx context.Context, body LoginJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
-	req, err := NewLoginRequest(c.Server, body)
+func (c *Client) DeleteProfile(ctx context.Context, profileName string, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewDeleteProfileRequest(c.Server, profileName)
 	if err != nil {
 		return nil, err
 	}
@@ -292,8 +421,8 @@ func (c *Client) Login(ctx context.Context, body LoginJSONRequestBody, reqEditor
 	return c.Client.Do(req)
 }
 
-func (c *Client) ListModels(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
-	req, err := NewListModelsRequest(c.Server)
+func (c *Client) GetProfile(ctx context.Context, profileName string, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetProfileRequest(c.Server, profileName)
 	if err != nil {
 		return nil, err
 	}
@@ -304,8 +433,8 @@ func (c *Client) ListModels(ctx context.Context, reqEditors ...RequestEditorFn)
 	return c.Client.Do(req)
 }
 
-func (c *Client) RegisterModelWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
-	req, err := NewRegisterModelRequestWithBody(c.Server, contentType, body)
+func (c *Client) UpdateProfileWithBody(ctx context.Context, profileName string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewUpdateProfileRequestWithBody(c.Server, profileName, contentType, body)
 	if err != nil {
 		return nil, err
 	}
@@ -316,8 +445,8 @@ func (c *Client) RegisterModelWithBody(ctx context.Context, contentType string,
 	return c.Client.Do(req)
 }
 
-func (c *Client) RegisterModel(ctx context.Context, body RegisterModelJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
-	req, err := NewRegisterModelRequest(c.Server, body)
+func (c *Client) UpdateProfile(ctx context.Context, profileName string, body UpdateProfileJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewUpdateProfileRequest(c.Server, profileName, body)
 	if err != nil {
 		return nil, err
 	}
@@ -328,8 +457,8 @@ func (c *Client) RegisterModel(ctx context.Context, body RegisterModelJSONReques
 	return c.Client.Do(req)
 }
 
-func (c *Client) DeleteModel(ctx context.Context, modelName string, reqEditors ...RequestEditorFn) (*http.Response, error) {
-	req, err := NewDeleteModelRequest(c.Server, modelName)
+func (c *Client) SelfTestWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewSelfTestRequestWithBody(c.Server, contentType, body)
 	if err != nil {
 		return nil, err
 	}
@@ -340,8 +469,8 @@ func (c *Client) DeleteModel(ctx context.Context, modelName string, reqEditors .
 	return c.Client.Do(req)
 }
 
-func (c *Client) GetModel(ctx context.Context, modelName string, reqEditors ...RequestEditorFn) (*http.Response, error) {
-	req, err := NewGetModelRequest(c.Server, modelName)
+func (c *Client) SelfTest(ctx context.Context, body SelfTestJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewSelfTestRequest(c.Server, body)
 	if err != nil {
 		return nil, err
 	}
@@ -352,8 +481,8 @@ func (c *Client) GetModel(ctx context.Context, modelName string, reqEditors ...R
 	return c.Client.Do(req)
 }
 
-func (c *Client) UpdateModelWithBody(ctx context.Context, modelName string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
-	req, err := NewUpdateModelRequestWithBody(c.Server, modelName, contentType, body)
+func (c *Client) GetStats(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetStatsRequest(c.Server)
 	if err != nil {
 		return nil, err
 	}
@@ -364,8 +493,8 @@ func (c *Client) UpdateModelWithBody(ctx context.Context, modelName string, cont
 	return c.Client.Do(req)
 }
 
-func (c *Client) UpdateModel(ctx context.Context, modelName string, body UpdateModelJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
-	req, err := NewUpdateModelRequest(c.Server, modelName, body)
+func (c *Client) GetStorage(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetStorageRequest(c.Server)
 	if err != nil {
 		return nil, err
 	}
@@ -376,8 +505,8 @@ func (c *Client) UpdateModel(ctx context.Context, modelName string, body UpdateM
 	return c.Client.Do(req)
 }
 
-func (c *Client) UpdateOptionsWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
-	req, err := NewUpdateOptionsRequestWithBody(c.Server, contentType, body)
+func (c *Client) GetUsage(ctx context.Context, params *GetUsageParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetUsageRequest(c.Server, params)
 	if err != nil {
 		return nil, err
 	}
@@ -388,8 +517,8 @@ func (c *Client) UpdateOptionsWithBody(ctx context.Context, contentType string,
 	return c.Client.Do(req)
 }
 
-func (c *Client) UpdateOptions(ctx context.Context, body UpdateOptionsJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
-	req, err := NewUpdateOptionsRequest(c.Server, body)
+func (c *Client) BatchUpdateResourceWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewBatchUpdateResourceRequestWithBody(c.Server, contentType, body)
 	if err != nil {
 		return nil, err
 	}
@@ -400,8 +529,8 @@ func (c *Client) UpdateOptions(ctx context.Context, body UpdateOptionsJSONReques
 	return c.Client.Do(req)
 }
 
-func (c *Client) Restart(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
-	req, err := NewRestartRequest(c.Server)
+func (c *Client) BatchUpdateResource(ctx context.Context, body BatchUpdateResourceJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewBatchUpdateResourceRequest(c.Server, body)
 	if err != nil {
 		return nil, err
 	}
@@ -412,8 +541,8 @@ func (c *Client) Restart(ctx context.Context, reqEditors ...RequestEditorFn) (*h
 	return c.Client.Do(req)
 }
 
-func (c *Client) CancelTask(ctx context.Context, taskId string, reqEditors ...RequestEditorFn) (*http.Response, error) {
-	req, err := NewCancelTaskRequest(c.Server, taskId)
+func (c *Client) DelSDFuncWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewDelSDFuncRequestWithBody(c.Server, contentType, body)
 	if err != nil {
 		return nil, err
 	}
@@ -424,8 +553,8 @@ func (c *Client) CancelTask(ctx context.Context, taskId string, reqEditors ...Re
 	return c.Client.Do(req)
 }
 
-func (c *Client) GetTaskProgress(ctx context.Context, taskId string, reqEditors ...RequestEditorFn) (*http.Response, error) {
-	req, err := NewGetTaskProgressRequest(c.Server, taskId)
+func (c *Client) DelSDFunc(ctx context.Context, body DelSDFuncJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewDelSDFuncRequest(c.Server, body)
 	if err != nil {
 		return nil, err
 	}
@@ -436,8 +565,8 @@ func (c *Client) GetTaskProgress(ctx context.Context, taskId string, reqEditors
 	return c.Client.Do(req)
 }
 
-func (c *Client) GetTaskResult(ctx context.Context, taskId string, reqEditors ...RequestEditorFn) (*http.Response, error) {
-	req, err := NewGetTaskResultRequest(c.Server, taskId)
+func (c *Client) ExtraBatchImagesWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewExtraBatchImagesRequestWithBody(c.Server, contentType, body)
 	if err != nil {
 		return nil, err
 	}
@@ -448,8 +577,8 @@ func (c *Client) GetTaskResult(ctx context.Context, taskId string, reqEditors ..
 	return c.Client.Do(req)
 }
 
-func (c *Client) Txt2ImgWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
-	req, err := NewTxt2ImgRequestWithBody(c.Server, contentType, body)
+func (c *Client) ExtraBatchImages(ctx context.Context, body ExtraBatchImagesJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewExtraBatchImagesRequest(c.Server, body)
 	if err != nil {
 		return nil, err
 	}
@@ -460,8 +589,8 @@ func (c *Client) Txt2ImgWithBody(ctx context.Context, contentType string, body i
 	return c.Client.Do(req)
 }
 
-func (c *Client) Txt2Img(ctx context.Context, body Txt2ImgJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
-	req, err := NewTxt2ImgRequest(c.Server, body)
+func (c *Client) ExtraImagesWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewExtraImagesRequestWithBody(c.Server, contentType, body)
 	if err != nil {
 		return nil, err
 	}
@@ -472,457 +601,572 @@ func (c *Client) Txt2Img(ctx context.Context, body Txt2ImgJSONRequestBody, reqEd
 	return c.Client.Do(req)
 }
 
-// NewBatchUpdateResourceRequest calls the generic BatchUpdateResource builder with application/json body
-func NewBatchUpdateResourceRequest(server string, body BatchUpdateResourceJSONRequestBody) (*http.Request, error) {
-	var bodyReader io.Reader
-	buf, err := json.Marshal(body)
+func (c *Client) ExtraImages(ctx context.Context, body ExtraImagesJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewExtraImagesRequest(c.Server, body)
 	if err != nil {
 		return nil, err
 	}
-	bodyReader = bytes.NewReader(buf)
-	return NewBatchUpdateResourceRequestWithBody(server, "application/json", bodyReader)
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
 }
 
-// NewBatchUpdateResourceRequestWithBody generates requests for BatchUpdateResource with any type of body
-func NewBatchUpdateResourceRequestWithBody(server string, contentType string, body io.Reader) (*http.Request, error) {
-	var err error
-
-	serverURL, err := url.Parse(server)
+func (c *Client) Img2ImgWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewImg2ImgRequestWithBody(c.Server, contentType, body)
 	if err != nil {
 		return nil, err
 	}
-
-	operationPath := fmt.Sprintf("/batch_update_sd_resource")
-	if operationPath[0] == '/' {
-		operationPath = "." + operationPath
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
 	}
+	return c.Client.Do(req)
+}
 
-	queryURL, err := serverURL.Parse(operationPath)
+func (c *Client) Img2Img(ctx context.Context, body Img2ImgJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewImg2ImgRequest(c.Server, body)
 	if err != nil {
 		return nil, err
 	}
-
-	req, err := http.NewRequest("POST", queryURL.String(), body)
-	if err != nil {
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
 		return nil, err
 	}
-
-	req.Header.Add("Content-Type", contentType)
-
-	return req, nil
+	return c.Client.Do(req)
 }
 
-// NewDelSDFuncRequest calls the generic DelSDFunc builder with application/json body
-func NewDelSDFuncRequest(server string, body DelSDFuncJSONRequestBody) (*http.Request, error) {
-	var bodyReader io.Reader
-	buf, err := json.Marshal(body)
+func (c *Client) InterrogateWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewInterrogateRequestWithBody(c.Server, contentType, body)
 	if err != nil {
 		return nil, err
 	}
-	bodyReader = bytes.NewReader(buf)
-	return NewDelSDFuncRequestWithBody(server, "application/json", bodyReader)
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
 }
 
-// NewDelSDFuncRequestWithBody generates requests for DelSDFunc with any type of body
-func NewDelSDFuncRequestWithBody(server string, contentType string, body io.Reader) (*http.Request, error) {
-	var err error
-
-	serverURL, err := url.Parse(server)
+func (c *Client) Interrogate(ctx context.Context, body InterrogateJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewInterrogateRequest(c.Server, body)
 	if err != nil {
 		return nil, err
 	}
-
-	operationPath := fmt.Sprintf("/del/sd/functions")
-	if operationPath[0] == '/' {
-		operationPath = "." + operationPath
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
 	}
+	return c.Client.Do(req)
+}
 
-	queryURL, err := serverURL.Parse(operationPath)
+func (c *Client) ListSdFunc(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewListSdFuncRequest(c.Server)
 	if err != nil {
 		return nil, err
 	}
-
-	req, err := http.NewRequest("POST", queryURL.String(), body)
-	if err != nil {
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
 		return nil, err
 	}
-
-	req.Header.Add("Content-Type", contentType)
-
-	return req, nil
+	return c.Client.Do(req)
 }
 
-// NewExtraImagesRequest calls the generic ExtraImages builder with application/json body
-func NewExtraImagesRequest(server string, body ExtraImagesJSONRequestBody) (*http.Request, error) {
-	var bodyReader io.Reader
-	buf, err := json.Marshal(body)
+func (c *Client) LoginWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewLoginRequestWithBody(c.Server, contentType, body)
 	if err != nil {
 		return nil, err
 	}
-	bodyReader = bytes.NewReader(buf)
-	return NewExtraImagesRequestWithBody(server, "application/json", bodyReader)
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
 }
 
-// NewExtraImagesRequestWithBody generates requests for ExtraImages with any type of body
-func NewExtraImagesRequestWithBody(server string, contentType string, body io.Reader) (*http.Request, error) {
-	var err error
-
-	serverURL, err := url.Parse(server)
+func (c *Client) Login(ctx context.Context, body LoginJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewLoginRequest(c.Server, body)
 	if err != nil {
 		return nil, err
 	}
-
-	operationPath := fmt.Sprintf("/extra_images")
-	if operationPath[0] == '/' {
-		operationPath = "." + operationPath
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
 	}
+	return c.Client.Do(req)
+}
 
-	queryURL, err := serverURL.Parse(operationPath)
+func (c *Client) ListModels(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewListModelsRequest(c.Server)
 	if err != nil {
 		return nil, err
 	}
-
-	req, err := http.NewRequest("POST", queryURL.String(), body)
-	if err != nil {
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
 		return nil, err
 	}
-
-	req.Header.Add("Content-Type", contentType)
-
-	return req, nil
+	return c.Client.Do(req)
 }
 
-// NewImg2ImgRequest calls the generic Img2Img builder with application/json body
-func NewImg2ImgRequest(server string, body Img2ImgJSONRequestBody) (*http.Request, error) {
-	var bodyReader io.Reader
-	buf, err := json.Marshal(body)
+func (c *Client) RegisterModelWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewRegisterModelRequestWithBody(c.Server, contentType, body)
 	if err != nil {
 		return nil, err
 	}
-	bodyReader = bytes.NewReader(buf)
-	return NewImg2ImgRequestWithBody(server, "application/json", bodyReader)
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
 }
 
-// NewImg2ImgRequestWithBody generates requests for Img2Img with any type of body
-func NewImg2ImgRequestWithBody(server string, contentType string, body io.Reader) (*http.Request, error) {
-	var err error
-
-	serverURL, err := url.Parse(server)
+func (c *Client) RegisterModel(ctx context.Context, body RegisterModelJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewRegisterModelRequest(c.Server, body)
 	if err != nil {
 		return nil, err
 	}
-
-	operationPath := fmt.Sprintf("/img2img")
-	if operationPath[0] == '/' {
-		operationPath = "." + operationPath
-	}
-
-	queryURL, err := serverURL.Parse(operationPath)
-	if err != nil {
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
 		return nil, err
 	}
+	return c.Client.Do(req)
+}
 
-	req, err := http.NewRequest("POST", queryURL.String(), body)
+func (c *Client) BatchDeleteModelsWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewBatchDeleteModelsRequestWithBody(c.Server, contentType, body)
 	if err != nil {
 		return nil, err
 	}
-
-	req.Header.Add("Content-Type", contentType)
-
-	return req, nil
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
 }
 
-// NewListSdFuncRequest generates requests for ListSdFunc
-func NewListSdFuncRequest(server string) (*http.Request, error) {
-	var err error
-
-	serverURL, err := url.Parse(server)
+func (c *Client) BatchDeleteModels(ctx context.Context, body BatchDeleteModelsJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewBatchDeleteModelsRequest(c.Server, body)
 	if err != nil {
 		return nil, err
 	}
-
-	operationPath := fmt.Sprintf("/list/sdapi/functions")
-	if operationPath[0] == '/' {
-		operationPath = "." + operationPath
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
 	}
+	return c.Client.Do(req)
+}
 
-	queryURL, err := serverURL.Parse(operationPath)
+func (c *Client) DeleteModel(ctx context.Context, modelName string, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewDeleteModelRequest(c.Server, modelName)
 	if err != nil {
 		return nil, err
 	}
-
-	req, err := http.NewRequest("GET", queryURL.String(), nil)
-	if err != nil {
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
 		return nil, err
 	}
-
-	return req, nil
+	return c.Client.Do(req)
 }
 
-// NewLoginRequest calls the generic Login builder with application/json body
-func NewLoginRequest(server string, body LoginJSONRequestBody) (*http.Request, error) {
-	var bodyReader io.Reader
-	buf, err := json.Marshal(body)
+func (c *Client) GetModel(ctx context.Context, modelName string, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetModelRequest(c.Server, modelName)
 	if err != nil {
 		return nil, err
 	}
-	bodyReader = bytes.NewReader(buf)
-	return NewLoginRequestWithBody(server, "application/json", bodyReader)
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
 }
 
-// NewLoginRequestWithBody generates requests for Login with any type of body
-func NewLoginRequestWithBody(server string, contentType string, body io.Reader) (*http.Request, error) {
-	var err error
-
-	serverURL, err := url.Parse(server)
+func (c *Client) UpdateModelWithBody(ctx context.Context, modelName string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewUpdateModelRequestWithBody(c.Server, modelName, contentType, body)
 	if err != nil {
 		return nil, err
 	}
-
-	operationPath := fmt.Sprintf("/login")
-	if operationPath[0] == '/' {
-		operationPath = "." + operationPath
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
 	}
+	return c.Client.Do(req)
+}
 
-	queryURL, err := serverURL.Parse(operationPath)
+func (c *Client) UpdateModel(ctx context.Context, modelName string, body UpdateModelJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewUpdateModelRequest(c.Server, modelName, body)
 	if err != nil {
 		return nil, err
 	}
-
-	req, err := http.NewRequest("POST", queryURL.String(), body)
-	if err != nil {
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
 		return nil, err
 	}
-
-	req.Header.Add("Content-Type", contentType)
-
-	return req, nil
+	return c.Client.Do(req)
 }
 
-// NewListModelsRequest generates requests for ListModels
-func NewListModelsRequest(server string) (*http.Request, error) {
-	var err error
-
-	serverURL, err := url.Parse(server)
+func (c *Client) DistributeModel(ctx context.Context, modelName string, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewDistributeModelRequest(c.Server, modelName)
 	if err != nil {
 		return nil, err
 	}
-
-	operationPath := fmt.Sprintf("/models")
-	if operationPath[0] == '/' {
-		operationPath = "." + operationPath
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
 	}
+	return c.Client.Do(req)
+}
 
-	queryURL, err := serverURL.Parse(operationPath)
+func (c *Client) UpdateOptionsWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewUpdateOptionsRequestWithBody(c.Server, contentType, body)
 	if err != nil {
 		return nil, err
 	}
-
-	req, err := http.NewRequest("GET", queryURL.String(), nil)
-	if err != nil {
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
 		return nil, err
 	}
-
-	return req, nil
+	return c.Client.Do(req)
 }
 
-// NewRegisterModelRequest calls the generic RegisterModel builder with application/json body
-func NewRegisterModelRequest(server string, body RegisterModelJSONRequestBody) (*http.Request, error) {
-	var bodyReader io.Reader
-	buf, err := json.Marshal(body)
+func (c *Client) UpdateOptions(ctx context.Context, body UpdateOptionsJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewUpdateOptionsRequest(c.Server, body)
 	if err != nil {
 		return nil, err
 	}
-	bodyReader = bytes.NewReader(buf)
-	return NewRegisterModelRequestWithBody(server, "application/json", bodyReader)
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
 }
 
-// NewRegisterModelRequestWithBody generates requests for RegisterModel with any type of body
-func NewRegisterModelRequestWithBody(server string, contentType string, body io.Reader) (*http.Request, error) {
-	var err error
-
-	serverURL, err := url.Parse(server)
+func (c *Client) ListPromptTemplates(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewListPromptTemplatesRequest(c.Server)
 	if err != nil {
 		return nil, err
 	}
-
-	operationPath := fmt.Sprintf("/models")
-	if operationPath[0] == '/' {
-		operationPath = "." + operationPath
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
 	}
+	return c.Client.Do(req)
+}
 
-	queryURL, err := serverURL.Parse(operationPath)
+func (c *Client) DeletePromptTemplate(ctx context.Context, templateName string, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewDeletePromptTemplateRequest(c.Server, templateName)
 	if err != nil {
 		return nil, err
 	}
-
-	req, err := http.NewRequest("POST", queryURL.String(), body)
-	if err != nil {
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
 		return nil, err
 	}
-
-	req.Header.Add("Content-Type", contentType)
-
-	return req, nil
+	return c.Client.Do(req)
 }
 
-// NewDeleteModelRequest generates requests for DeleteModel
-func NewDeleteModelRequest(server string, modelName string) (*http.Request, error) {
-	var err error
-
-	var pathParam0 string
-
-	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "model_name", runtime.ParamLocationPath, modelName)
+func (c *Client) GetPromptTemplate(ctx context.Context, templateName string, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetPromptTemplateRequest(c.Server, templateName)
 	if err != nil {
 		return nil, err
 	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
 
-	serverURL, err := url.Parse(server)
+func (c *Client) UpdatePromptTemplateWithBody(ctx context.Context, templateName string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewUpdatePromptTemplateRequestWithBody(c.Server, templateName, contentType, body)
 	if err != nil {
 		return nil, err
 	}
-
-	operationPath := fmt.Sprintf("/models/%s", pathParam0)
-	if operationPath[0] == '/' {
-		operationPath = "." + operationPath
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
 	}
+	return c.Client.Do(req)
+}
 
-	queryURL, err := serverURL.Parse(operationPath)
+func (c *Client) UpdatePromptTemplate(ctx context.Context, templateName string, body UpdatePromptTemplateJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewUpdatePromptTemplateRequest(c.Server, templateName, body)
 	if err != nil {
 		return nil, err
 	}
-
-	req, err := http.NewRequest("DELETE", queryURL.String(), nil)
-	if err != nil {
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
 		return nil, err
 	}
-
-	return req, nil
+	return c.Client.Do(req)
 }
 
-// NewGetModelRequest generates requests for GetModel
-func NewGetModelRequest(server string, modelName string) (*http.Request, error) {
-	var err error
-
-	var pathParam0 string
-
-	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "model_name", runtime.ParamLocationPath, modelName)
+func (c *Client) Restart(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewRestartRequest(c.Server)
 	if err != nil {
 		return nil, err
 	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
 
-	serverURL, err := url.Parse(server)
+func (c *Client) GetCapabilities(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetCapabilitiesRequest(c.Server)
 	if err != nil {
 		return nil, err
 	}
-
-	operationPath := fmt.Sprintf("/models/%s", pathParam0)
-	if operationPath[0] == '/' {
-		operationPath = "." + operationPath
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
 	}
+	return c.Client.Do(req)
+}
 
-	queryURL, err := serverURL.Parse(operationPath)
+func (c *Client) GetSharedTaskResult(ctx context.Context, token string, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetSharedTaskResultRequest(c.Server, token)
 	if err != nil {
 		return nil, err
 	}
-
-	req, err := http.NewRequest("GET", queryURL.String(), nil)
-	if err != nil {
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
 		return nil, err
 	}
-
-	return req, nil
+	return c.Client.Do(req)
 }
 
-// NewUpdateModelRequest calls the generic UpdateModel builder with application/json body
-func NewUpdateModelRequest(server string, modelName string, body UpdateModelJSONRequestBody) (*http.Request, error) {
-	var bodyReader io.Reader
-	buf, err := json.Marshal(body)
+func (c *Client) ListTasks(ctx context.Context, params *ListTasksParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewListTasksRequest(c.Server, params)
 	if err != nil {
 		return nil, err
 	}
-	bodyReader = bytes.NewReader(buf)
-	return NewUpdateModelRequestWithBody(server, modelName, "application/json", bodyReader)
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
 }
 
-// NewUpdateModelRequestWithBody generates requests for UpdateModel with any type of body
-func NewUpdateModelRequestWithBody(server string, modelName string, contentType string, body io.Reader) (*http.Request, error) {
-	var err error
-
-	var pathParam0 string
-
-	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "model_name", runtime.ParamLocationPath, modelName)
+func (c *Client) GetTaskResultsWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetTaskResultsRequestWithBody(c.Server, contentType, body)
 	if err != nil {
 		return nil, err
 	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
 
-	serverURL, err := url.Parse(server)
+func (c *Client) GetTaskResults(ctx context.Context, body GetTaskResultsJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetTaskResultsRequest(c.Server, body)
 	if err != nil {
 		return nil, err
 	}
-
-	operationPath := fmt.Sprintf("/models/%s", pathParam0)
-	if operationPath[0] == '/' {
-		operationPath = "." + operationPath
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
 	}
+	return c.Client.Do(req)
+}
 
-	queryURL, err := serverURL.Parse(operationPath)
+func (c *Client) CancelTask(ctx context.Context, taskId string, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewCancelTaskRequest(c.Server, taskId)
 	if err != nil {
 		return nil, err
 	}
-
-	req, err := http.NewRequest("PUT", queryURL.String(), body)
-	if err != nil {
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
 		return nil, err
 	}
+	return c.Client.Do(req)
+}
 
-	req.Header.Add("Content-Type", contentType)
-
-	return req, nil
+func (c *Client) GetTaskProgress(ctx context.Context, taskId string, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetTaskProgressRequest(c.Server, taskId)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
 }
 
-// NewUpdateOptionsRequest calls the generic UpdateOptions builder with application/json body
-func NewUpdateOptionsRequest(server string, body UpdateOptionsJSONRequestBody) (*http.Request, error) {
-	var bodyReader io.Reader
-	buf, err := json.Marshal(body)
+func (c *Client) GetTaskReproduce(ctx context.Context, taskId string, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetTaskReproduceRequest(c.Server, taskId)
 	if err != nil {
 		return nil, err
 	}
-	bodyReader = bytes.NewReader(buf)
-	return NewUpdateOptionsRequestWithBody(server, "application/json", bodyReader)
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
 }
 
-// NewUpdateOptionsRequestWithBody generates requests for UpdateOptions with any type of body
-func NewUpdateOptionsRequestWithBody(server string, contentType string, body io.Reader) (*http.Request, error) {
-	var err error
+func (c *Client) GetTaskResult(ctx context.Context, taskId string, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetTaskResultRequest(c.Server, taskId)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
 
-	serverURL, err := url.Parse(server)
+func (c *Client) ShareTask(ctx context.Context, taskId string, params *ShareTaskParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewShareTaskRequest(c.Server, taskId, params)
 	if err != nil {
 		return nil, err
 	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
 
-	operationPath := fmt.Sprintf("/options")
-	if operationPath[0] == '/' {
-		operationPath = "." + operationPath
+func (c *Client) Txt2ImgWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewTxt2ImgRequestWithBody(c.Server, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
 	}
+	return c.Client.Do(req)
+}
 
-	queryURL, err := serverURL.Parse(operationPath)
+func (c *Client) Txt2Img(ctx context.Context, body Txt2ImgJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewTxt2ImgRequest(c.Server, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) Txt2ImgCachedWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewTxt2ImgCachedRequestWithBody(c.Server, contentType, body)
 	if err != nil {
 		return nil, err
 	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
 
-	req, err := http.NewRequest("POST", queryURL.String(), body)
+func (c *Client) Txt2ImgCached(ctx context.Context, body Txt2ImgCachedJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewTxt2ImgCachedRequest(c.Server, body)
 	if err != nil {
 		return nil, err
 	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
 
-	req.Header.Add("Content-Type", contentType)
+func (c *Client) Txt2ImgMultiWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewTxt2ImgMultiRequestWithBody(c.Server, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
 
-	return req, nil
+func (c *Client) Txt2ImgMulti(ctx context.Context, body Txt2ImgMultiJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewTxt2ImgMultiRequest(c.Server, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
 }
 
-// NewRestartRequest generates requests for Restart
-func NewRestartRequest(server string) (*http.Request, error) {
+func (c *Client) DeleteUserImagesWithBody(ctx context.Context, user string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewDeleteUserImagesRequestWithBody(c.Server, user, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) DeleteUserImages(ctx context.Context, user string, body DeleteUserImagesJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewDeleteUserImagesRequest(c.Server, user, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) ListUserImages(ctx context.Context, user string, params *ListUserImagesParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewListUserImagesRequest(c.Server, user, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetUserStats(ctx context.Context, user string, params *GetUserStatsParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetUserStatsRequest(c.Server, user, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetVersion(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetVersionRequest(c.Server)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+// NewGetEnvRequest generates requests for GetEnv
+func NewGetEnvRequest(server string) (*http.Request, error) {
 	var err error
 
 	serverURL, err := url.Parse(server)
@@ -930,7 +1174,7 @@ func NewRestartRequest(server string) (*http.Request, error) {
 		return nil, err
 	}
 
-	operationPath := fmt.Sprintf("/restart")
+	operationPath := fmt.Sprintf("/admin/env")
 	if operationPath[0] == '/' {
 		operationPath = "." + operationPath
 	}
@@ -940,7 +1184,7 @@ func NewRestartRequest(server string) (*http.Request, error) {
 		return nil, err
 	}
 
-	req, err := http.NewRequest("POST", queryURL.String(), nil)
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -948,23 +1192,65 @@ func NewRestartRequest(server string) (*http.Request, error) {
 	return req, nil
 }
 
-// NewCancelTaskRequest generates requests for CancelTask
-func NewCancelTaskRequest(server string, taskId string) (*http.Request, error) {
+// NewTailSdLogsRequest generates requests for TailSdLogs
+func NewTailSdLogsRequest(server string, params *TailSdLogsParams) (*http.Request, error) {
 	var err error
 
-	var pathParam0 string
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
 
-	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "taskId", runtime.ParamLocationPath, taskId)
+	operationPath := fmt.Sprintf("/admin/logs/sd")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if params.Tail != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "tail", runtime.ParamLocationQuery, *params.Tail); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
 	if err != nil {
 		return nil, err
 	}
 
+	return req, nil
+}
+
+// NewGetMaintenanceRequest generates requests for GetMaintenance
+func NewGetMaintenanceRequest(server string) (*http.Request, error) {
+	var err error
+
 	serverURL, err := url.Parse(server)
 	if err != nil {
 		return nil, err
 	}
 
-	operationPath := fmt.Sprintf("/tasks/%s/cancellation", pathParam0)
+	operationPath := fmt.Sprintf("/admin/maintenance")
 	if operationPath[0] == '/' {
 		operationPath = "." + operationPath
 	}
@@ -974,7 +1260,7 @@ func NewCancelTaskRequest(server string, taskId string) (*http.Request, error) {
 		return nil, err
 	}
 
-	req, err := http.NewRequest("POST", queryURL.String(), nil)
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -982,23 +1268,56 @@ func NewCancelTaskRequest(server string, taskId string) (*http.Request, error) {
 	return req, nil
 }
 
-// NewGetTaskProgressRequest generates requests for GetTaskProgress
-func NewGetTaskProgressRequest(server string, taskId string) (*http.Request, error) {
+// NewSetMaintenanceRequest calls the generic SetMaintenance builder with application/json body
+func NewSetMaintenanceRequest(server string, body SetMaintenanceJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewSetMaintenanceRequestWithBody(server, "application/json", bodyReader)
+}
+
+// NewSetMaintenanceRequestWithBody generates requests for SetMaintenance with any type of body
+func NewSetMaintenanceRequestWithBody(server string, contentType string, body io.Reader) (*http.Request, error) {
 	var err error
 
-	var pathParam0 string
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
 
-	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "taskId", runtime.ParamLocationPath, taskId)
+	operationPath := fmt.Sprintf("/admin/maintenance")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), body)
 	if err != nil {
 		return nil, err
 	}
 
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
+}
+
+// NewReconcileModelsRequest generates requests for ReconcileModels
+func NewReconcileModelsRequest(server string, params *ReconcileModelsParams) (*http.Request, error) {
+	var err error
+
 	serverURL, err := url.Parse(server)
 	if err != nil {
 		return nil, err
 	}
 
-	operationPath := fmt.Sprintf("/tasks/%s/progress", pathParam0)
+	operationPath := fmt.Sprintf("/admin/models/reconcile")
 	if operationPath[0] == '/' {
 		operationPath = "." + operationPath
 	}
@@ -1008,7 +1327,29 @@ func NewGetTaskProgressRequest(server string, taskId string) (*http.Request, err
 		return nil, err
 	}
 
-	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if params.DryRun != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "dryRun", runtime.ParamLocationQuery, *params.DryRun); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -1016,13 +1357,13 @@ func NewGetTaskProgressRequest(server string, taskId string) (*http.Request, err
 	return req, nil
 }
 
-// NewGetTaskResultRequest generates requests for GetTaskResult
-func NewGetTaskResultRequest(server string, taskId string) (*http.Request, error) {
+// NewGetModelDefaultsRequest generates requests for GetModelDefaults
+func NewGetModelDefaultsRequest(server string, modelName string) (*http.Request, error) {
 	var err error
 
 	var pathParam0 string
 
-	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "taskId", runtime.ParamLocationPath, taskId)
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "model_name", runtime.ParamLocationPath, modelName)
 	if err != nil {
 		return nil, err
 	}
@@ -1032,7 +1373,7 @@ func NewGetTaskResultRequest(server string, taskId string) (*http.Request, error
 		return nil, err
 	}
 
-	operationPath := fmt.Sprintf("/tasks/%s/result", pathParam0)
+	operationPath := fmt.Sprintf("/admin/models/%s/defaults", pathParam0)
 	if operationPath[0] == '/' {
 		operationPath = "." + operationPath
 	}
@@ -1050,27 +1391,34 @@ func NewGetTaskResultRequest(server string, taskId string) (*http.Request, error
 	return req, nil
 }
 
-// NewTxt2ImgRequest calls the generic Txt2Img builder with application/json body
-func NewTxt2ImgRequest(server string, body Txt2ImgJSONRequestBody) (*http.Request, error) {
+// NewUpdateModelDefaultsRequest calls the generic UpdateModelDefaults builder with application/json body
+func NewUpdateModelDefaultsRequest(server string, modelName string, body UpdateModelDefaultsJSONRequestBody) (*http.Request, error) {
 	var bodyReader io.Reader
 	buf, err := json.Marshal(body)
 	if err != nil {
 		return nil, err
 	}
 	bodyReader = bytes.NewReader(buf)
-	return NewTxt2ImgRequestWithBody(server, "application/json", bodyReader)
+	return NewUpdateModelDefaultsRequestWithBody(server, modelName, "application/json", bodyReader)
 }
 
-// NewTxt2ImgRequestWithBody generates requests for Txt2Img with any type of body
-func NewTxt2ImgRequestWithBody(server string, contentType string, body io.Reader) (*http.Request, error) {
+// NewUpdateModelDefaultsRequestWithBody generates requests for UpdateModelDefaults with any type of body
+func NewUpdateModelDefaultsRequestWithBody(server string, modelName string, contentType string, body io.Reader) (*http.Request, error) {
 	var err error
 
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "model_name", runtime.ParamLocationPath, modelName)
+	if err != nil {
+		return nil, err
+	}
+
 	serverURL, err := url.Parse(server)
 	if err != nil {
 		return nil, err
 	}
 
-	operationPath := fmt.Sprintf("/txt2img")
+	operationPath := fmt.Sprintf("/admin/models/%s/defaults", pathParam0)
 	if operationPath[0] == '/' {
 		operationPath = "." + operationPath
 	}
@@ -1080,7 +1428,7 @@ func NewTxt2ImgRequestWithBody(server string, contentType string, body io.Reader
 		return nil, err
 	}
 
-	req, err := http.NewRequest("POST", queryURL.String(), body)
+	req, err := http.NewRequest("PUT", queryURL.String(), body)
 	if err != nil {
 		return nil, err
 	}
@@ -1090,761 +1438,5022 @@ func NewTxt2ImgRequestWithBody(server string, contentType string, body io.Reader
 	return req, nil
 }
 
-func (c *Client) applyEditors(ctx context.Context, req *http.Request, additionalEditors []RequestEditorFn) error {
-	for _, r := range c.RequestEditors {
-		if err := r(ctx, req); err != nil {
-			return err
-		}
-	}
-	for _, r := range additionalEditors {
-		if err := r(ctx, req); err != nil {
-			return err
-		}
+// NewListProfilesRequest generates requests for ListProfiles
+func NewListProfilesRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
 	}
-	return nil
-}
 
-// ClientWithResponses builds on ClientInterface to offer response payloads
-type ClientWithResponses struct {
-	ClientInterface
-}
+	operationPath := fmt.Sprintf("/admin/profiles")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
 
-// NewClientWithResponses creates a new ClientWithResponses, which wraps
-// Client with return type handling
-func NewClientWithResponses(server string, opts ...ClientOption) (*ClientWithResponses, error) {
-	client, err := NewClient(server, opts...)
+	queryURL, err := serverURL.Parse(operationPath)
 	if err != nil {
 		return nil, err
 	}
-	return &ClientWithResponses{client}, nil
-}
 
-// WithBaseURL overrides the baseURL.
-func WithBaseURL(baseURL string) ClientOption {
-	return func(c *Client) error {
-		newBaseURL, err := url.Parse(baseURL)
-		if err != nil {
-			return err
-		}
-		c.Server = newBaseURL.String()
-		return nil
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
 	}
+
+	return req, nil
 }
 
-// ClientWithResponsesInterface is the interface specification for the client with responses above.
-type ClientWithResponsesInterface interface {
-	// BatchUpdateResourceWithBodyWithResponse request with any body
-	BatchUpdateResourceWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*BatchUpdateResourceResponse, error)
+// NewDeleteProfileRequest generates requests for DeleteProfile
+func NewDeleteProfileRequest(server string, profileName string) (*http.Request, error) {
+	var err error
 
-	BatchUpdateResourceWithResponse(ctx context.Context, body BatchUpdateResourceJSONRequestBody, reqEditors ...RequestEditorFn) (*BatchUpdateResourceResponse, error)
+	var pathParam0 string
 
-	// DelSDFuncWithBodyWithResponse request with any body
-	DelSDFuncWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*DelSDFuncResponse, error)
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "profile_name", runtime.ParamLocationPath, profileName)
+	if err != nil {
+		return nil, err
+	}
 
-	DelSDFuncWithResponse(ctx context.Context, body DelSDFuncJSONRequestBody, reqEditors ...RequestEditorFn) (*DelSDFuncResponse, error)
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
 
-	// ExtraImagesWithBodyWithResponse request with any body
-	ExtraImagesWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*ExtraImagesResponse, error)
+	operationPath := fmt.Sprintf("/admin/profiles/%s", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
 
-	ExtraImagesWithResponse(ctx context.Context, body ExtraImagesJSONRequestBody, reqEditors ...RequestEditorFn) (*ExtraImagesResponse, error)
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
 
-	// Img2ImgWithBodyWithResponse request with any body
-	Img2ImgWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*Img2ImgResponse, error)
+	req, err := http.NewRequest("DELETE", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
 
-	Img2ImgWithResponse(ctx context.Context, body Img2ImgJSONRequestBody, reqEditors ...RequestEditorFn) (*Img2ImgResponse, error)
+	return req, nil
+}
 
-	// ListSdFuncWithResponse request
-	ListSdFuncWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*ListSdFuncResponse, error)
+// NewGetProfileRequest generates requests for GetProfile
+func NewGetProfileRequest(server string, profileName string) (*http.Request, error) {
+	var err error
 
-	// LoginWithBodyWithResponse request with any body
-	LoginWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*LoginResponse, error)
+	var pathParam0 string
 
-	LoginWithResponse(ctx context.Context, body LoginJSONRequestBody, reqEditors ...RequestEditorFn) (*LoginResponse, error)
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "profile_name", runtime.ParamLocationPath, profileName)
+	if err != nil {
+		return nil, err
+	}
 
-	// ListModelsWithResponse request
-	ListModelsWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*ListModelsResponse, error)
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
 
-	// RegisterModelWithBodyWithResponse request with any body
-	RegisterModelWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*RegisterModelResponse, error)
+	operationPath := fmt.Sprintf("/admin/profiles/%s", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
 
-	RegisterModelWithResponse(ctx context.Context, body RegisterModelJSONRequestBody, reqEditors ...RequestEditorFn) (*RegisterModelResponse, error)
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
 
-	// DeleteModelWithResponse request
-	DeleteModelWithResponse(ctx context.Context, modelName string, reqEditors ...RequestEditorFn) (*DeleteModelResponse, error)
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
 
-	// GetModelWithResponse request
-	GetModelWithResponse(ctx context.Context, modelName string, reqEditors ...RequestEditorFn) (*GetModelResponse, error)
+	return req, nil
+}
 
-	// UpdateModelWithBodyWithResponse request with any body
-	UpdateModelWithBodyWithResponse(ctx context.Context, modelName string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*UpdateModelResponse, error)
+// NewUpdateProfileRequest calls the generic UpdateProfile builder with application/json body
+func NewUpdateProfileRequest(server string, profileName string, body UpdateProfileJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewUpdateProfileRequestWithBody(server, profileName, "application/json", bodyReader)
+}
 
-	UpdateModelWithResponse(ctx context.Context, modelName string, body UpdateModelJSONRequestBody, reqEditors ...RequestEditorFn) (*UpdateModelResponse, error)
+// NewUpdateProfileRequestWithBody generates requests for UpdateProfile with any type of body
+func NewUpdateProfileRequestWithBody(server string, profileName string, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
 
-	// UpdateOptionsWithBodyWithResponse request with any body
-	UpdateOptionsWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*UpdateOptionsResponse, error)
+	var pathParam0 string
 
-	UpdateOptionsWithResponse(ctx context.Context, body UpdateOptionsJSONRequestBody, reqEditors ...RequestEditorFn) (*UpdateOptionsResponse, error)
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "profile_name", runtime.ParamLocationPath, profileName)
+	if err != nil {
+		return nil, err
+	}
 
-	// RestartWithResponse request
-	RestartWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*RestartResponse, error)
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
 
-	// CancelTaskWithResponse request
-	CancelTaskWithResponse(ctx context.Context, taskId string, reqEditors ...RequestEditorFn) (*CancelTaskResponse, error)
+	operationPath := fmt.Sprintf("/admin/profiles/%s", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
 
-	// GetTaskProgressWithResponse request
-	GetTaskProgressWithResponse(ctx context.Context, taskId string, reqEditors ...RequestEditorFn) (*GetTaskProgressResponse, error)
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
 
-	// GetTaskResultWithResponse request
-	GetTaskResultWithResponse(ctx context.Context, taskId string, reqEditors ...RequestEditorFn) (*GetTaskResultResponse, error)
+	req, err := http.NewRequest("PUT", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
 
-	// Txt2ImgWithBodyWithResponse request with any body
-	Txt2ImgWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*Txt2ImgResponse, error)
+	req.Header.Add("Content-Type", contentType)
 
-	Txt2ImgWithResponse(ctx context.Context, body Txt2ImgJSONRequestBody, reqEditors ...RequestEditorFn) (*Txt2ImgResponse, error)
+	return req, nil
 }
 
-type BatchUpdateResourceResponse struct {
-	Body         []byte
-	HTTPResponse *http.Response
-	JSON200      *BatchUpdateSdResourceResponse
-	JSON500      *BatchUpdateSdResourceResponse
-	JSONDefault  *Error
+// NewSelfTestRequest calls the generic SelfTest builder with application/json body
+func NewSelfTestRequest(server string, body SelfTestJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewSelfTestRequestWithBody(server, "application/json", bodyReader)
 }
 
-// Status returns HTTPResponse.Status
-func (r BatchUpdateResourceResponse) Status() string {
-	if r.HTTPResponse != nil {
-		return r.HTTPResponse.Status
+// NewSelfTestRequestWithBody generates requests for SelfTest with any type of body
+func NewSelfTestRequestWithBody(server string, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
 	}
-	return http.StatusText(0)
-}
 
-// StatusCode returns HTTPResponse.StatusCode
-func (r BatchUpdateResourceResponse) StatusCode() int {
-	if r.HTTPResponse != nil {
-		return r.HTTPResponse.StatusCode
+	operationPath := fmt.Sprintf("/admin/selftest")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
 	}
-	return 0
-}
 
-type DelSDFuncResponse struct {
-	Body         []byte
-	HTTPResponse *http.Response
-	JSON200      *DelSDFunctionResponse
-	JSON500      *DelSDFunctionResponse
-	JSONDefault  *Error
-}
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
 
-// Status returns HTTPResponse.Status
-func (r DelSDFuncResponse) Status() string {
-	if r.HTTPResponse != nil {
-		return r.HTTPResponse.Status
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
 	}
-	return http.StatusText(0)
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
 }
 
-// StatusCode returns HTTPResponse.StatusCode
-func (r DelSDFuncResponse) StatusCode() int {
-	if r.HTTPResponse != nil {
-		return r.HTTPResponse.StatusCode
+// NewGetStatsRequest generates requests for GetStats
+func NewGetStatsRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
 	}
-	return 0
-}
 
-type ExtraImagesResponse struct {
-	Body         []byte
-	HTTPResponse *http.Response
-	JSON200      *SubmitTaskResponse
-	JSONDefault  *Error
-}
+	operationPath := fmt.Sprintf("/admin/stats")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
 
-// Status returns HTTPResponse.Status
-func (r ExtraImagesResponse) Status() string {
-	if r.HTTPResponse != nil {
-		return r.HTTPResponse.Status
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
 	}
-	return http.StatusText(0)
-}
 
-// StatusCode returns HTTPResponse.StatusCode
-func (r ExtraImagesResponse) StatusCode() int {
-	if r.HTTPResponse != nil {
-		return r.HTTPResponse.StatusCode
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
 	}
-	return 0
+
+	return req, nil
 }
 
-type Img2ImgResponse struct {
-	Body         []byte
-	HTTPResponse *http.Response
-	JSON200      *SubmitTaskResponse
-	JSONDefault  *Error
-}
+// NewGetStorageRequest generates requests for GetStorage
+func NewGetStorageRequest(server string) (*http.Request, error) {
+	var err error
 
-// Status returns HTTPResponse.Status
-func (r Img2ImgResponse) Status() string {
-	if r.HTTPResponse != nil {
-		return r.HTTPResponse.Status
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
 	}
-	return http.StatusText(0)
-}
 
-// StatusCode returns HTTPResponse.StatusCode
-func (r Img2ImgResponse) StatusCode() int {
-	if r.HTTPResponse != nil {
-		return r.HTTPResponse.StatusCode
+	operationPath := fmt.Sprintf("/admin/storage")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
 	}
-	return 0
-}
-
-type ListSdFuncResponse struct {
-	Body         []byte
-	HTTPResponse *http.Response
-	JSON200      *ListSDFunctionResponse
-	JSON500      *ListSDFunctionResponse
-	JSONDefault  *Error
-}
 
-// Status returns HTTPResponse.Status
-func (r ListSdFuncResponse) Status() string {
-	if r.HTTPResponse != nil {
-		return r.HTTPResponse.Status
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
 	}
-	return http.StatusText(0)
-}
 
-// StatusCode returns HTTPResponse.StatusCode
-func (r ListSdFuncResponse) StatusCode() int {
-	if r.HTTPResponse != nil {
-		return r.HTTPResponse.StatusCode
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
 	}
-	return 0
-}
 
-type LoginResponse struct {
-	Body         []byte
-	HTTPResponse *http.Response
-	JSON200      *UserLoginResponse
-	JSONDefault  *Error
+	return req, nil
 }
 
-// Status returns HTTPResponse.Status
-func (r LoginResponse) Status() string {
-	if r.HTTPResponse != nil {
-		return r.HTTPResponse.Status
+// NewGetUsageRequest generates requests for GetUsage
+func NewGetUsageRequest(server string, params *GetUsageParams) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
 	}
-	return http.StatusText(0)
-}
 
-// StatusCode returns HTTPResponse.StatusCode
-func (r LoginResponse) StatusCode() int {
-	if r.HTTPResponse != nil {
-		return r.HTTPResponse.StatusCode
+	operationPath := fmt.Sprintf("/admin/usage")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
 	}
-	return 0
-}
 
-type ListModelsResponse struct {
-	Body         []byte
-	HTTPResponse *http.Response
-	JSON200      *[]ModelAttributes
-	JSONDefault  *Error
-}
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
 
-// Status returns HTTPResponse.Status
-func (r ListModelsResponse) Status() string {
-	if r.HTTPResponse != nil {
-		return r.HTTPResponse.Status
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if params.User != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "user", runtime.ParamLocationQuery, *params.User); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
 	}
-	return http.StatusText(0)
-}
 
-// StatusCode returns HTTPResponse.StatusCode
-func (r ListModelsResponse) StatusCode() int {
-	if r.HTTPResponse != nil {
-		return r.HTTPResponse.StatusCode
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
 	}
-	return 0
-}
 
-type RegisterModelResponse struct {
-	Body         []byte
-	HTTPResponse *http.Response
-	JSONDefault  *Error
+	return req, nil
 }
 
-// Status returns HTTPResponse.Status
-func (r RegisterModelResponse) Status() string {
-	if r.HTTPResponse != nil {
-		return r.HTTPResponse.Status
+// NewBatchUpdateResourceRequest calls the generic BatchUpdateResource builder with application/json body
+func NewBatchUpdateResourceRequest(server string, body BatchUpdateResourceJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
 	}
-	return http.StatusText(0)
+	bodyReader = bytes.NewReader(buf)
+	return NewBatchUpdateResourceRequestWithBody(server, "application/json", bodyReader)
 }
 
-// StatusCode returns HTTPResponse.StatusCode
-func (r RegisterModelResponse) StatusCode() int {
-	if r.HTTPResponse != nil {
-		return r.HTTPResponse.StatusCode
+// NewBatchUpdateResourceRequestWithBody generates requests for BatchUpdateResource with any type of body
+func NewBatchUpdateResourceRequestWithBody(server string, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
 	}
-	return 0
-}
 
-type DeleteModelResponse struct {
-	Body         []byte
-	HTTPResponse *http.Response
-	JSONDefault  *Error
-}
+	operationPath := fmt.Sprintf("/batch_update_sd_resource")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
 
-// Status returns HTTPResponse.Status
-func (r DeleteModelResponse) Status() string {
-	if r.HTTPResponse != nil {
-		return r.HTTPResponse.Status
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
 	}
-	return http.StatusText(0)
-}
 
-// StatusCode returns HTTPResponse.StatusCode
-func (r DeleteModelResponse) StatusCode() int {
-	if r.HTTPResponse != nil {
-		return r.HTTPResponse.StatusCode
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
 	}
-	return 0
-}
 
-type GetModelResponse struct {
-	Body         []byte
-	HTTPResponse *http.Response
-	JSON200      *ModelAttributes
-	JSONDefault  *Error
-}
+	req.Header.Add("Content-Type", contentType)
 
-// Status returns HTTPResponse.Status
-func (r GetModelResponse) Status() string {
-	if r.HTTPResponse != nil {
-		return r.HTTPResponse.Status
-	}
-	return http.StatusText(0)
+	return req, nil
 }
 
-// StatusCode returns HTTPResponse.StatusCode
-func (r GetModelResponse) StatusCode() int {
-	if r.HTTPResponse != nil {
-		return r.HTTPResponse.StatusCode
+// NewDelSDFuncRequest calls the generic DelSDFunc builder with application/json body
+func NewDelSDFuncRequest(server string, body DelSDFuncJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
 	}
-	return 0
+	bodyReader = bytes.NewReader(buf)
+	return NewDelSDFuncRequestWithBody(server, "application/json", bodyReader)
 }
 
-type UpdateModelResponse struct {
-	Body         []byte
-	HTTPResponse *http.Response
-	JSONDefault  *Error
-}
+// NewDelSDFuncRequestWithBody generates requests for DelSDFunc with any type of body
+func NewDelSDFuncRequestWithBody(server string, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
 
-// Status returns HTTPResponse.Status
-func (r UpdateModelResponse) Status() string {
-	if r.HTTPResponse != nil {
-		return r.HTTPResponse.Status
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
 	}
-	return http.StatusText(0)
-}
 
-// StatusCode returns HTTPResponse.StatusCode
-func (r UpdateModelResponse) StatusCode() int {
-	if r.HTTPResponse != nil {
-		return r.HTTPResponse.StatusCode
+	operationPath := fmt.Sprintf("/del/sd/functions")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
 	}
-	return 0
-}
 
-type UpdateOptionsResponse struct {
-	Body         []byte
-	HTTPResponse *http.Response
-	JSON200      *ResponseMessage
-	JSONDefault  *Error
-}
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
 
-// Status returns HTTPResponse.Status
-func (r UpdateOptionsResponse) Status() string {
-	if r.HTTPResponse != nil {
-		return r.HTTPResponse.Status
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
 	}
-	return http.StatusText(0)
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
 }
 
-// StatusCode returns HTTPResponse.StatusCode
-func (r UpdateOptionsResponse) StatusCode() int {
-	if r.HTTPResponse != nil {
-		return r.HTTPResponse.StatusCode
+// NewExtraBatchImagesRequest calls the generic ExtraBatchImages builder with application/json body
+func NewExtraBatchImagesRequest(server string, body ExtraBatchImagesJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
 	}
-	return 0
+	bodyReader = bytes.NewReader(buf)
+	return NewExtraBatchImagesRequestWithBody(server, "application/json", bodyReader)
 }
 
-type RestartResponse struct {
-	Body         []byte
-	HTTPResponse *http.Response
-	JSONDefault  *Error
-}
+// NewExtraBatchImagesRequestWithBody generates requests for ExtraBatchImages with any type of body
+func NewExtraBatchImagesRequestWithBody(server string, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
 
-// Status returns HTTPResponse.Status
-func (r RestartResponse) Status() string {
-	if r.HTTPResponse != nil {
-		return r.HTTPResponse.Status
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
 	}
-	return http.StatusText(0)
-}
 
-// StatusCode returns HTTPResponse.StatusCode
-func (r RestartResponse) StatusCode() int {
-	if r.HTTPResponse != nil {
-		return r.HTTPResponse.StatusCode
+	operationPath := fmt.Sprintf("/extra_batch_images")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
 	}
-	return 0
-}
 
-type CancelTaskResponse struct {
-	Body         []byte
-	HTTPResponse *http.Response
-	JSONDefault  *Error
-}
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
 
-// Status returns HTTPResponse.Status
-func (r CancelTaskResponse) Status() string {
-	if r.HTTPResponse != nil {
-		return r.HTTPResponse.Status
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
 	}
-	return http.StatusText(0)
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
 }
 
-// StatusCode returns HTTPResponse.StatusCode
-func (r CancelTaskResponse) StatusCode() int {
-	if r.HTTPResponse != nil {
-		return r.HTTPResponse.StatusCode
+// NewExtraImagesRequest calls the generic ExtraImages builder with application/json body
+func NewExtraImagesRequest(server string, body ExtraImagesJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
 	}
-	return 0
+	bodyReader = bytes.NewReader(buf)
+	return NewExtraImagesRequestWithBody(server, "application/json", bodyReader)
 }
 
-type GetTaskProgressResponse struct {
-	Body         []byte
-	HTTPResponse *http.Response
-	JSON200      *TaskProgressResponse
-	JSONDefault  *Error
+// NewExtraImagesRequestWithBody generates requests for ExtraImages with any type of body
+func NewExtraImagesRequestWithBody(server string, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/extra_images")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
 }
 
-// Status returns HTTPResponse.Status
-func (r GetTaskProgressResponse) Status() string {
-	if r.HTTPResponse != nil {
-		return r.HTTPResponse.Status
+// NewImg2ImgRequest calls the generic Img2Img builder with application/json body
+func NewImg2ImgRequest(server string, body Img2ImgJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
 	}
-	return http.StatusText(0)
+	bodyReader = bytes.NewReader(buf)
+	return NewImg2ImgRequestWithBody(server, "application/json", bodyReader)
 }
 
-// StatusCode returns HTTPResponse.StatusCode
-func (r GetTaskProgressResponse) StatusCode() int {
-	if r.HTTPResponse != nil {
-		return r.HTTPResponse.StatusCode
+// NewImg2ImgRequestWithBody generates requests for Img2Img with any type of body
+func NewImg2ImgRequestWithBody(server string, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
 	}
-	return 0
+
+	operationPath := fmt.Sprintf("/img2img")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
 }
 
-type GetTaskResultResponse struct {
-	Body         []byte
-	HTTPResponse *http.Response
-	JSON200      *TaskResultResponse
-	JSONDefault  *Error
+// NewInterrogateRequest calls the generic Interrogate builder with application/json body
+func NewInterrogateRequest(server string, body InterrogateJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewInterrogateRequestWithBody(server, "application/json", bodyReader)
 }
 
-// Status returns HTTPResponse.Status
-func (r GetTaskResultResponse) Status() string {
-	if r.HTTPResponse != nil {
-		return r.HTTPResponse.Status
+// NewInterrogateRequestWithBody generates requests for Interrogate with any type of body
+func NewInterrogateRequestWithBody(server string, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
 	}
-	return http.StatusText(0)
+
+	operationPath := fmt.Sprintf("/interrogate")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
 }
 
-// StatusCode returns HTTPResponse.StatusCode
-func (r GetTaskResultResponse) StatusCode() int {
-	if r.HTTPResponse != nil {
-		return r.HTTPResponse.StatusCode
+// NewListSdFuncRequest generates requests for ListSdFunc
+func NewListSdFuncRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
 	}
-	return 0
+
+	operationPath := fmt.Sprintf("/list/sdapi/functions")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
 }
 
-type Txt2ImgResponse struct {
-	Body         []byte
-	HTTPResponse *http.Response
-	JSON200      *SubmitTaskResponse
-	JSONDefault  *Error
+// NewLoginRequest calls the generic Login builder with application/json body
+func NewLoginRequest(server string, body LoginJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewLoginRequestWithBody(server, "application/json", bodyReader)
 }
 
-// Status returns HTTPResponse.Status
-func (r Txt2ImgResponse) Status() string {
-	if r.HTTPResponse != nil {
-		return r.HTTPResponse.Status
+// NewLoginRequestWithBody generates requests for Login with any type of body
+func NewLoginRequestWithBody(server string, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
 	}
-	return http.StatusText(0)
+
+	operationPath := fmt.Sprintf("/login")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
 }
 
-// StatusCode returns HTTPResponse.StatusCode
-func (r Txt2ImgResponse) StatusCode() int {
-	if r.HTTPResponse != nil {
-		return r.HTTPResponse.StatusCode
+// NewListModelsRequest generates requests for ListModels
+func NewListModelsRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/models")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewRegisterModelRequest calls the generic RegisterModel builder with application/json body
+func NewRegisterModelRequest(server string, body RegisterModelJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewRegisterModelRequestWithBody(server, "application/json", bodyReader)
+}
+
+// NewRegisterModelRequestWithBody generates requests for RegisterModel with any type of body
+func NewRegisterModelRequestWithBody(server string, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/models")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
+}
+
+// NewBatchDeleteModelsRequest calls the generic BatchDeleteModels builder with application/json body
+func NewBatchDeleteModelsRequest(server string, body BatchDeleteModelsJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewBatchDeleteModelsRequestWithBody(server, "application/json", bodyReader)
+}
+
+// NewBatchDeleteModelsRequestWithBody generates requests for BatchDeleteModels with any type of body
+func NewBatchDeleteModelsRequestWithBody(server string, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/models/batch-delete")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
+}
+
+// NewDeleteModelRequest generates requests for DeleteModel
+func NewDeleteModelRequest(server string, modelName string) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "model_name", runtime.ParamLocationPath, modelName)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/models/%s", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("DELETE", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewGetModelRequest generates requests for GetModel
+func NewGetModelRequest(server string, modelName string) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "model_name", runtime.ParamLocationPath, modelName)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/models/%s", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewUpdateModelRequest calls the generic UpdateModel builder with application/json body
+func NewUpdateModelRequest(server string, modelName string, body UpdateModelJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewUpdateModelRequestWithBody(server, modelName, "application/json", bodyReader)
+}
+
+// NewUpdateModelRequestWithBody generates requests for UpdateModel with any type of body
+func NewUpdateModelRequestWithBody(server string, modelName string, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "model_name", runtime.ParamLocationPath, modelName)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/models/%s", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("PUT", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
+}
+
+// NewDistributeModelRequest generates requests for DistributeModel
+func NewDistributeModelRequest(server string, modelName string) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "model_name", runtime.ParamLocationPath, modelName)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/models/%s/distribute", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewUpdateOptionsRequest calls the generic UpdateOptions builder with application/json body
+func NewUpdateOptionsRequest(server string, body UpdateOptionsJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewUpdateOptionsRequestWithBody(server, "application/json", bodyReader)
+}
+
+// NewUpdateOptionsRequestWithBody generates requests for UpdateOptions with any type of body
+func NewUpdateOptionsRequestWithBody(server string, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/options")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
+}
+
+// NewListPromptTemplatesRequest generates requests for ListPromptTemplates
+func NewListPromptTemplatesRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/prompt_templates")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewDeletePromptTemplateRequest generates requests for DeletePromptTemplate
+func NewDeletePromptTemplateRequest(server string, templateName string) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "template_name", runtime.ParamLocationPath, templateName)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/prompt_templates/%s", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("DELETE", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewGetPromptTemplateRequest generates requests for GetPromptTemplate
+func NewGetPromptTemplateRequest(server string, templateName string) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "template_name", runtime.ParamLocationPath, templateName)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/prompt_templates/%s", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewUpdatePromptTemplateRequest calls the generic UpdatePromptTemplate builder with application/json body
+func NewUpdatePromptTemplateRequest(server string, templateName string, body UpdatePromptTemplateJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewUpdatePromptTemplateRequestWithBody(server, templateName, "application/json", bodyReader)
+}
+
+// NewUpdatePromptTemplateRequestWithBody generates requests for UpdatePromptTemplate with any type of body
+func NewUpdatePromptTemplateRequestWithBody(server string, templateName string, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "template_name", runtime.ParamLocationPath, templateName)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/prompt_templates/%s", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("PUT", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
+}
+
+// NewRestartRequest generates requests for Restart
+func NewRestartRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/restart")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewGetCapabilitiesRequest generates requests for GetCapabilities
+func NewGetCapabilitiesRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/sdapi/capabilities")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewGetSharedTaskResultRequest generates requests for GetSharedTaskResult
+func NewGetSharedTaskResultRequest(server string, token string) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "token", runtime.ParamLocationPath, token)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/shared/%s", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewListTasksRequest generates requests for ListTasks
+func NewListTasksRequest(server string, params *ListTasksParams) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/tasks")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if params.User != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "user", runtime.ParamLocationQuery, *params.User); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Status != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "status", runtime.ParamLocationQuery, *params.Status); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.From != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "from", runtime.ParamLocationQuery, *params.From); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.To != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "to", runtime.ParamLocationQuery, *params.To); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Model != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "model", runtime.ParamLocationQuery, *params.Model); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Limit != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "limit", runtime.ParamLocationQuery, *params.Limit); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Cursor != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "cursor", runtime.ParamLocationQuery, *params.Cursor); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewGetTaskResultsRequest calls the generic GetTaskResults builder with application/json body
+func NewGetTaskResultsRequest(server string, body GetTaskResultsJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewGetTaskResultsRequestWithBody(server, "application/json", bodyReader)
+}
+
+// NewGetTaskResultsRequestWithBody generates requests for GetTaskResults with any type of body
+func NewGetTaskResultsRequestWithBody(server string, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/tasks/results")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
+}
+
+// NewCancelTaskRequest generates requests for CancelTask
+func NewCancelTaskRequest(server string, taskId string) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "taskId", runtime.ParamLocationPath, taskId)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/tasks/%s/cancellation", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewGetTaskProgressRequest generates requests for GetTaskProgress
+func NewGetTaskProgressRequest(server string, taskId string) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "taskId", runtime.ParamLocationPath, taskId)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/tasks/%s/progress", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewGetTaskReproduceRequest generates requests for GetTaskReproduce
+func NewGetTaskReproduceRequest(server string, taskId string) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "taskId", runtime.ParamLocationPath, taskId)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/tasks/%s/reproduce", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewGetTaskResultRequest generates requests for GetTaskResult
+func NewGetTaskResultRequest(server string, taskId string) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "taskId", runtime.ParamLocationPath, taskId)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/tasks/%s/result", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewShareTaskRequest generates requests for ShareTask
+func NewShareTaskRequest(server string, taskId string, params *ShareTaskParams) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "taskId", runtime.ParamLocationPath, taskId)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/tasks/%s/share", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if params.ExpiresIn != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "expiresIn", runtime.ParamLocationQuery, *params.ExpiresIn); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewTxt2ImgRequest calls the generic Txt2Img builder with application/json body
+func NewTxt2ImgRequest(server string, body Txt2ImgJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewTxt2ImgRequestWithBody(server, "application/json", bodyReader)
+}
+
+// NewTxt2ImgRequestWithBody generates requests for Txt2Img with any type of body
+func NewTxt2ImgRequestWithBody(server string, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/txt2img")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
+}
+
+// NewTxt2ImgCachedRequest calls the generic Txt2ImgCached builder with application/json body
+func NewTxt2ImgCachedRequest(server string, body Txt2ImgCachedJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewTxt2ImgCachedRequestWithBody(server, "application/json", bodyReader)
+}
+
+// NewTxt2ImgCachedRequestWithBody generates requests for Txt2ImgCached with any type of body
+func NewTxt2ImgCachedRequestWithBody(server string, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/txt2img/cached")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
+}
+
+// NewTxt2ImgMultiRequest calls the generic Txt2ImgMulti builder with application/json body
+func NewTxt2ImgMultiRequest(server string, body Txt2ImgMultiJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewTxt2ImgMultiRequestWithBody(server, "application/json", bodyReader)
+}
+
+// NewTxt2ImgMultiRequestWithBody generates requests for Txt2ImgMulti with any type of body
+func NewTxt2ImgMultiRequestWithBody(server string, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/txt2img/multi")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
+}
+
+// NewDeleteUserImagesRequest calls the generic DeleteUserImages builder with application/json body
+func NewDeleteUserImagesRequest(server string, user string, body DeleteUserImagesJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewDeleteUserImagesRequestWithBody(server, user, "application/json", bodyReader)
+}
+
+// NewDeleteUserImagesRequestWithBody generates requests for DeleteUserImages with any type of body
+func NewDeleteUserImagesRequestWithBody(server string, user string, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "user", runtime.ParamLocationPath, user)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/users/%s/images", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("DELETE", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
+}
+
+// NewListUserImagesRequest generates requests for ListUserImages
+func NewListUserImagesRequest(server string, user string, params *ListUserImagesParams) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "user", runtime.ParamLocationPath, user)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/users/%s/images", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if params.Limit != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "limit", runtime.ParamLocationQuery, *params.Limit); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Cursor != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "cursor", runtime.ParamLocationQuery, *params.Cursor); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.OutputPrefix != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "output_prefix", runtime.ParamLocationQuery, *params.OutputPrefix); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewGetUserStatsRequest generates requests for GetUserStats
+func NewGetUserStatsRequest(server string, user string, params *GetUserStatsParams) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "user", runtime.ParamLocationPath, user)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/users/%s/stats", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if params.Days != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "days", runtime.ParamLocationQuery, *params.Days); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewGetVersionRequest generates requests for GetVersion
+func NewGetVersionRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/version")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+func (c *Client) applyEditors(ctx context.Context, req *http.Request, additionalEditors []RequestEditorFn) error {
+	for _, r := range c.RequestEditors {
+		if err := r(ctx, req); err != nil {
+			return err
+		}
+	}
+	for _, r := range additionalEditors {
+		if err := r(ctx, req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ClientWithResponses builds on ClientInterface to offer response payloads
+type ClientWithResponses struct {
+	ClientInterface
+}
+
+// NewClientWithResponses creates a new ClientWithResponses, which wraps
+// Client with return type handling
+func NewClientWithResponses(server string, opts ...ClientOption) (*ClientWithResponses, error) {
+	client, err := NewClient(server, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &ClientWithResponses{client}, nil
+}
+
+// WithBaseURL overrides the baseURL.
+func WithBaseURL(baseURL string) ClientOption {
+	return func(c *Client) error {
+		newBaseURL, err := url.Parse(baseURL)
+		if err != nil {
+			return err
+		}
+		c.Server = newBaseURL.String()
+		return nil
+	}
+}
+
+// ClientWithResponsesInterface is the interface specification for the client with responses above.
+type ClientWithResponsesInterface interface {
+	// GetEnvWithResponse request
+	GetEnvWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetEnvResponse, error)
+
+	// TailSdLogsWithResponse request
+	TailSdLogsWithResponse(ctx context.Context, params *TailSdLogsParams, reqEditors ...RequestEditorFn) (*TailSdLogsResponse, error)
+
+	// GetMaintenanceWithResponse request
+	GetMaintenanceWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetMaintenanceResponse, error)
+
+	// SetMaintenanceWithBodyWithResponse request with any body
+	SetMaintenanceWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*SetMaintenanceResponse, error)
+
+	SetMaintenanceWithResponse(ctx context.Context, body SetMaintenanceJSONRequestBody, reqEditors ...RequestEditorFn) (*SetMaintenanceResponse, error)
+
+	// ReconcileModelsWithResponse request
+	ReconcileModelsWithResponse(ctx context.Context, params *ReconcileModelsParams, reqEditors ...RequestEditorFn) (*ReconcileModelsResponse, error)
+
+	// GetModelDefaultsWithResponse request
+	GetModelDefaultsWithResponse(ctx context.Context, modelName string, reqEditors ...RequestEditorFn) (*GetModelDefaultsResponse, error)
+
+	// UpdateModelDefaultsWithBodyWithResponse request with any body
+	UpdateModelDefaultsWithBodyWithResponse(ctx context.Context, modelName string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*UpdateModelDefaultsResponse, error)
+
+	UpdateModelDefaultsWithResponse(ctx context.Context, modelName string, body UpdateModelDefaultsJSONRequestBody, reqEditors ...RequestEditorFn) (*UpdateModelDefaultsResponse, error)
+
+	// ListProfilesWithResponse request
+	ListProfilesWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*ListProfilesResponse, error)
+
+	// DeleteProfileWithResponse request
+	DeleteProfileWithResponse(ctx context.Context, profileName string, reqEditors ...RequestEditorFn) (*DeleteProfileResponse, error)
+
+	// GetProfileWithResponse request
+	GetProfileWithResponse(ctx context.Context, profileName string, reqEditors ...RequestEditorFn) (*GetProfileResponse, error)
+
+	// UpdateProfileWithBodyWithResponse request with any body
+	UpdateProfileWithBodyWithResponse(ctx context.Context, profileName string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*UpdateProfileResponse, error)
+
+	UpdateProfileWithResponse(ctx context.Context, profileName string, body UpdateProfileJSONRequestBody, reqEditors ...RequestEditorFn) (*UpdateProfileResponse, error)
+
+	// SelfTestWithBodyWithResponse request with any body
+	SelfTestWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*SelfTestResponse, error)
+
+	SelfTestWithResponse(ctx context.Context, body SelfTestJSONRequestBody, reqEditors ...RequestEditorFn) (*SelfTestResponse, error)
+
+	// GetStatsWithResponse request
+	GetStatsWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetStatsResponse, error)
+
+	// GetStorageWithResponse request
+	GetStorageWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetStorageResponse, error)
+
+	// GetUsageWithResponse request
+	GetUsageWithResponse(ctx context.Context, params *GetUsageParams, reqEditors ...RequestEditorFn) (*GetUsageResponse, error)
+
+	// BatchUpdateResourceWithBodyWithResponse request with any body
+	BatchUpdateResourceWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*BatchUpdateResourceResponse, error)
+
+	BatchUpdateResourceWithResponse(ctx context.Context, body BatchUpdateResourceJSONRequestBody, reqEditors ...RequestEditorFn) (*BatchUpdateResourceResponse, error)
+
+	// DelSDFuncWithBodyWithResponse request with any body
+	DelSDFuncWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*DelSDFuncResponse, error)
+
+	DelSDFuncWithResponse(ctx context.Context, body DelSDFuncJSONRequestBody, reqEditors ...RequestEditorFn) (*DelSDFuncResponse, error)
+
+	// ExtraBatchImagesWithBodyWithResponse request with any body
+	ExtraBatchImagesWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*ExtraBatchImagesResponse, error)
+
+	ExtraBatchImagesWithResponse(ctx context.Context, body ExtraBatchImagesJSONRequestBody, reqEditors ...RequestEditorFn) (*ExtraBatchImagesResponse, error)
+
+	// ExtraImagesWithBodyWithResponse request with any body
+	ExtraImagesWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*ExtraImagesResponse, error)
+
+	ExtraImagesWithResponse(ctx context.Context, body ExtraImagesJSONRequestBody, reqEditors ...RequestEditorFn) (*ExtraImagesResponse, error)
+
+	// Img2ImgWithBodyWithResponse request with any body
+	Img2ImgWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*Img2ImgResponse, error)
+
+	Img2ImgWithResponse(ctx context.Context, body Img2ImgJSONRequestBody, reqEditors ...RequestEditorFn) (*Img2ImgResponse, error)
+
+	// InterrogateWithBodyWithResponse request with any body
+	InterrogateWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*InterrogateResponse, error)
+
+	InterrogateWithResponse(ctx context.Context, body InterrogateJSONRequestBody, reqEditors ...RequestEditorFn) (*InterrogateResponse, error)
+
+	// ListSdFuncWithResponse request
+	ListSdFuncWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*ListSdFuncResponse, error)
+
+	// LoginWithBodyWithResponse request with any body
+	LoginWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*LoginResponse, error)
+
+	LoginWithResponse(ctx context.Context, body LoginJSONRequestBody, reqEditors ...RequestEditorFn) (*LoginResponse, error)
+
+	// ListModelsWithResponse request
+	ListModelsWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*ListModelsResponse, error)
+
+	// RegisterModelWithBodyWithResponse request with any body
+	RegisterModelWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*RegisterModelResponse, error)
+
+	RegisterModelWithResponse(ctx context.Context, body RegisterModelJSONRequestBody, reqEditors ...RequestEditorFn) (*RegisterModelResponse, error)
+
+	// BatchDeleteModelsWithBodyWithResponse request with any body
+	BatchDeleteModelsWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*BatchDeleteModelsResponse, error)
+
+	BatchDeleteModelsWithResponse(ctx context.Context, body BatchDeleteModelsJSONRequestBody, reqEditors ...RequestEditorFn) (*BatchDeleteModelsResponse, error)
+
+	// DeleteModelWithResponse request
+	DeleteModelWithResponse(ctx context.Context, modelName string, reqEditors ...RequestEditorFn) (*DeleteModelResponse, error)
+
+	// GetModelWithResponse request
+	GetModelWithResponse(ctx context.Context, modelName string, reqEditors ...RequestEditorFn) (*GetModelResponse, error)
+
+	// UpdateModelWithBodyWithResponse request with any body
+	UpdateModelWithBodyWithResponse(ctx context.Context, modelName string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*UpdateModelResponse, error)
+
+	UpdateModelWithResponse(ctx context.Context, modelName string, body UpdateModelJSONRequestBody, reqEditors ...RequestEditorFn) (*UpdateModelResponse, error)
+
+	// DistributeModelWithResponse request
+	DistributeModelWithResponse(ctx context.Context, modelName string, reqEditors ...RequestEditorFn) (*DistributeModelResponse, error)
+
+	// UpdateOptionsWithBodyWithResponse request with any body
+	UpdateOptionsWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*UpdateOptionsResponse, error)
+
+	UpdateOptionsWithResponse(ctx context.Context, body UpdateOptionsJSONRequestBody, reqEditors ...RequestEditorFn) (*UpdateOptionsResponse, error)
+
+	// ListPromptTemplatesWithResponse request
+	ListPromptTemplatesWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*ListPromptTemplatesResponse, error)
+
+	// DeletePromptTemplateWithResponse request
+	DeletePromptTemplateWithResponse(ctx context.Context, templateName string, reqEditors ...RequestEditorFn) (*DeletePromptTemplateResponse, error)
+
+	// GetPromptTemplateWithResponse request
+	GetPromptTemplateWithResponse(ctx context.Context, templateName string, reqEditors ...RequestEditorFn) (*GetPromptTemplateResponse, error)
+
+	// UpdatePromptTemplateWithBodyWithResponse request with any body
+	UpdatePromptTemplateWithBodyWithResponse(ctx context.Context, templateName string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*UpdatePromptTemplateResponse, error)
+
+	UpdatePromptTemplateWithResponse(ctx context.Context, templateName string, body UpdatePromptTemplateJSONRequestBody, reqEditors ...RequestEditorFn) (*UpdatePromptTemplateResponse, error)
+
+	// RestartWithResponse request
+	RestartWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*RestartResponse, error)
+
+	// GetCapabilitiesWithResponse request
+	GetCapabilitiesWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetCapabilitiesResponse, error)
+
+	// GetSharedTaskResultWithResponse request
+	GetSharedTaskResultWithResponse(ctx context.Context, token string, reqEditors ...RequestEditorFn) (*GetSharedTaskResultResponse, error)
+
+	// ListTasksWithResponse request
+	ListTasksWithResponse(ctx context.Context, params *ListTasksParams, reqEditors ...RequestEditorFn) (*ListTasksResponse, error)
+
+	// GetTaskResultsWithBodyWithResponse request with any body
+	GetTaskResultsWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*GetTaskResultsResponse, error)
+
+	GetTaskResultsWithResponse(ctx context.Context, body GetTaskResultsJSONRequestBody, reqEditors ...RequestEditorFn) (*GetTaskResultsResponse, error)
+
+	// CancelTaskWithResponse request
+	CancelTaskWithResponse(ctx context.Context, taskId string, reqEditors ...RequestEditorFn) (*CancelTaskResponse, error)
+
+	// GetTaskProgressWithResponse request
+	GetTaskProgressWithResponse(ctx context.Context, taskId string, reqEditors ...RequestEditorFn) (*GetTaskProgressResponse, error)
+
+	// GetTaskReproduceWithResponse request
+	GetTaskReproduceWithResponse(ctx context.Context, taskId string, reqEditors ...RequestEditorFn) (*GetTaskReproduceResponse, error)
+
+	// GetTaskResultWithResponse request
+	GetTaskResultWithResponse(ctx context.Context, taskId string, reqEditors ...RequestEditorFn) (*GetTaskResultResponse, error)
+
+	// ShareTaskWithResponse request
+	ShareTaskWithResponse(ctx context.Context, taskId string, params *ShareTaskParams, reqEditors ...RequestEditorFn) (*ShareTaskResponse, error)
+
+	// Txt2ImgWithBodyWithResponse request with any body
+	Txt2ImgWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*Txt2ImgResponse, error)
+
+	Txt2ImgWithResponse(ctx context.Context, body Txt2ImgJSONRequestBody, reqEditors ...RequestEditorFn) (*Txt2ImgResponse, error)
+
+	// Txt2ImgCachedWithBodyWithResponse request with any body
+	Txt2ImgCachedWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*Txt2ImgCachedResponse, error)
+
+	Txt2ImgCachedWithResponse(ctx context.Context, body Txt2ImgCachedJSONRequestBody, reqEditors ...RequestEditorFn) (*Txt2ImgCachedResponse, error)
+
+	// Txt2ImgMultiWithBodyWithResponse request with any body
+	Txt2ImgMultiWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*Txt2ImgMultiResponse, error)
+
+	Txt2ImgMultiWithResponse(ctx context.Context, body Txt2ImgMultiJSONRequestBody, reqEditors ...RequestEditorFn) (*Txt2ImgMultiResponse, error)
+
+	// DeleteUserImagesWithBodyWithResponse request with any body
+	DeleteUserImagesWithBodyWithResponse(ctx context.Context, user string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*DeleteUserImagesResponse, error)
+
+	DeleteUserImagesWithResponse(ctx context.Context, user string, body DeleteUserImagesJSONRequestBody, reqEditors ...RequestEditorFn) (*DeleteUserImagesResponse, error)
+
+	// ListUserImagesWithResponse request
+	ListUserImagesWithResponse(ctx context.Context, user string, params *ListUserImagesParams, reqEditors ...RequestEditorFn) (*ListUserImagesResponse, error)
+
+	// GetUserStatsWithResponse request
+	GetUserStatsWithResponse(ctx context.Context, user string, params *GetUserStatsParams, reqEditors ...RequestEditorFn) (*GetUserStatsResponse, error)
+
+	// GetVersionWithResponse request
+	GetVersionWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetVersionResponse, error)
+}
+
+type GetEnvResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *EnvInfo
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r GetEnvResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetEnvResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type TailSdLogsResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *SdLogs
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r TailSdLogsResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r TailSdLogsResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetMaintenanceResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *MaintenanceStatus
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r GetMaintenanceResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetMaintenanceResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type SetMaintenanceResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *MaintenanceStatus
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r SetMaintenanceResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r SetMaintenanceResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type ReconcileModelsResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *ReconcileModelsResult
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r ReconcileModelsResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r ReconcileModelsResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetModelDefaultsResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *ModelDefaults
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r GetModelDefaultsResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetModelDefaultsResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type UpdateModelDefaultsResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r UpdateModelDefaultsResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r UpdateModelDefaultsResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type ListProfilesResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *[]ModelProfile
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r ListProfilesResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r ListProfilesResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type DeleteProfileResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r DeleteProfileResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r DeleteProfileResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetProfileResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *ModelProfile
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r GetProfileResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetProfileResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type UpdateProfileResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r UpdateProfileResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r UpdateProfileResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type SelfTestResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *SelfTestResult
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r SelfTestResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r SelfTestResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetStatsResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *Stats
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r GetStatsResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetStatsResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetStorageResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *StorageInfo
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r GetStorageResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetStorageResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetUsageResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *UsageList
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r GetUsageResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetUsageResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type BatchUpdateResourceResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *BatchUpdateSdResourceResponse
+	JSON500      *BatchUpdateSdResourceResponse
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r BatchUpdateResourceResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r BatchUpdateResourceResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type DelSDFuncResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *DelSDFunctionResponse
+	JSON500      *DelSDFunctionResponse
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r DelSDFuncResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r DelSDFuncResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type ExtraBatchImagesResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *SubmitTaskResponse
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r ExtraBatchImagesResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r ExtraBatchImagesResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type ExtraImagesResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *SubmitTaskResponse
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r ExtraImagesResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r ExtraImagesResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type Img2ImgResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *SubmitTaskResponse
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r Img2ImgResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r Img2ImgResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type InterrogateResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *InterrogateResult
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r InterrogateResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r InterrogateResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type ListSdFuncResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *ListSDFunctionResponse
+	JSON500      *ListSDFunctionResponse
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r ListSdFuncResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r ListSdFuncResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type LoginResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *UserLoginResponse
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r LoginResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r LoginResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type ListModelsResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *[]ModelAttributes
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r ListModelsResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r ListModelsResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type RegisterModelResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r RegisterModelResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r RegisterModelResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type BatchDeleteModelsResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *BatchDeleteModelsResult
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r BatchDeleteModelsResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r BatchDeleteModelsResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type DeleteModelResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r DeleteModelResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r DeleteModelResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetModelResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *ModelAttributes
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r GetModelResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetModelResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type UpdateModelResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r UpdateModelResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r UpdateModelResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type DistributeModelResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *DistributeModelResult
+	JSON500      *DistributeModelResult
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r DistributeModelResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r DistributeModelResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type UpdateOptionsResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *ResponseMessage
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r UpdateOptionsResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r UpdateOptionsResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type ListPromptTemplatesResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *[]PromptTemplate
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r ListPromptTemplatesResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r ListPromptTemplatesResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type DeletePromptTemplateResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r DeletePromptTemplateResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r DeletePromptTemplateResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetPromptTemplateResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *PromptTemplate
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r GetPromptTemplateResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetPromptTemplateResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type UpdatePromptTemplateResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r UpdatePromptTemplateResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r UpdatePromptTemplateResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type RestartResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r RestartResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r RestartResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetCapabilitiesResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *SdCapabilities
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r GetCapabilitiesResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetCapabilitiesResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetSharedTaskResultResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *TaskResultResponse
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r GetSharedTaskResultResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetSharedTaskResultResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type ListTasksResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *TaskList
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r ListTasksResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r ListTasksResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetTaskResultsResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *TaskResultsResult
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r GetTaskResultsResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetTaskResultsResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type CancelTaskResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r CancelTaskResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r CancelTaskResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetTaskProgressResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *TaskProgressResponse
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r GetTaskProgressResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetTaskProgressResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetTaskReproduceResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *TaskReproduceResponse
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r GetTaskReproduceResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetTaskReproduceResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetTaskResultResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *TaskResultResponse
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r GetTaskResultResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetTaskResultResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type ShareTaskResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *ShareLink
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r ShareTaskResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r ShareTaskResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type Txt2ImgResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *SubmitTaskResponse
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r Txt2ImgResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r Txt2ImgResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type Txt2ImgCachedResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *SubmitTaskResponse
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r Txt2ImgCachedResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r Txt2ImgCachedResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type Txt2ImgMultiResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *Txt2ImgMultiResult
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r Txt2ImgMultiResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r Txt2ImgMultiResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type DeleteUserImagesResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *DeleteUserImagesResponse
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r DeleteUserImagesResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r DeleteUserImagesResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type ListUserImagesResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *UserImageList
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r ListUserImagesResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r ListUserImagesResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetUserStatsResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *UserStats
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r GetUserStatsResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetUserStatsResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetVersionResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *VersionInfo
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r GetVersionResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetVersionResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+// GetEnvWithResponse request returning *GetEnvResponse
+func (c *ClientWithResponses) GetEnvWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetEnvResponse, error) {
+	rsp, err := c.GetEnv(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetEnvResponse(rsp)
+}
+
+// TailSdLogsWithResponse request returning *TailSdLogsResponse
+func (c *ClientWithResponses) TailSdLogsWithResponse(ctx context.Context, params *TailSdLogsParams, reqEditors ...RequestEditorFn) (*TailSdLogsResponse, error) {
+	rsp, err := c.TailSdLogs(ctx, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseTailSdLogsResponse(rsp)
+}
+
+// GetMaintenanceWithResponse request returning *GetMaintenanceResponse
+func (c *ClientWithResponses) GetMaintenanceWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetMaintenanceResponse, error) {
+	rsp, err := c.GetMaintenance(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetMaintenanceResponse(rsp)
+}
+
+// SetMaintenanceWithBodyWithResponse request with arbitrary body returning *SetMaintenanceResponse
+func (c *ClientWithResponses) SetMaintenanceWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*SetMaintenanceResponse, error) {
+	rsp, err := c.SetMaintenanceWithBody(ctx, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseSetMaintenanceResponse(rsp)
+}
+
+func (c *ClientWithResponses) SetMaintenanceWithResponse(ctx context.Context, body SetMaintenanceJSONRequestBody, reqEditors ...RequestEditorFn) (*SetMaintenanceResponse, error) {
+	rsp, err := c.SetMaintenance(ctx, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseSetMaintenanceResponse(rsp)
+}
+
+// ReconcileModelsWithResponse request returning *ReconcileModelsResponse
+func (c *ClientWithResponses) ReconcileModelsWithResponse(ctx context.Context, params *ReconcileModelsParams, reqEditors ...RequestEditorFn) (*ReconcileModelsResponse, error) {
+	rsp, err := c.ReconcileModels(ctx, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseReconcileModelsResponse(rsp)
+}
+
+// GetModelDefaultsWithResponse request returning *GetModelDefaultsResponse
+func (c *ClientWithResponses) GetModelDefaultsWithResponse(ctx context.Context, modelName string, reqEditors ...RequestEditorFn) (*GetModelDefaultsResponse, error) {
+	rsp, err := c.GetModelDefaults(ctx, modelName, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetModelDefaultsResponse(rsp)
+}
+
+// UpdateModelDefaultsWithBodyWithResponse request with arbitrary body returning *UpdateModelDefaultsResponse
+func (c *ClientWithResponses) UpdateModelDefaultsWithBodyWithResponse(ctx context.Context, modelName string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*UpdateModelDefaultsResponse, error) {
+	rsp, err := c.UpdateModelDefaultsWithBody(ctx, modelName, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseUpdateModelDefaultsResponse(rsp)
+}
+
+func (c *ClientWithResponses) UpdateModelDefaultsWithResponse(ctx context.Context, modelName string, body UpdateModelDefaultsJSONRequestBody, reqEditors ...RequestEditorFn) (*UpdateModelDefaultsResponse, error) {
+	rsp, err := c.UpdateModelDefaults(ctx, modelName, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseUpdateModelDefaultsResponse(rsp)
+}
+
+// ListProfilesWithResponse request returning *ListProfilesResponse
+func (c *ClientWithResponses) ListProfilesWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*ListProfilesResponse, error) {
+	rsp, err := c.ListProfiles(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseListProfilesResponse(rsp)
+}
+
+// DeleteProfileWithResponse request returning *DeleteProfileResponse
+func (c *ClientWithResponses) DeleteProfileWithResponse(ctx context.Context, profileName string, reqEditors ...RequestEditorFn) (*DeleteProfileResponse, error) {
+	rsp, err := c.DeleteProfile(ctx, profileName, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseDeleteProfileResponse(rsp)
+}
+
+// GetProfileWithResponse request returning *GetProfileResponse
+func (c *ClientWithResponses) GetProfileWithResponse(ctx context.Context, profileName string, reqEditors ...RequestEditorFn) (*GetProfileResponse, error) {
+	rsp, err := c.GetProfile(ctx, profileName, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetProfileResponse(rsp)
+}
+
+// UpdateProfileWithBodyWithResponse request with arbitrary body returning *UpdateProfileResponse
+func (c *ClientWithResponses) UpdateProfileWithBodyWithResponse(ctx context.Context, profileName string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*UpdateProfileResponse, error) {
+	rsp, err := c.UpdateProfileWithBody(ctx, profileName, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseUpdateProfileResponse(rsp)
+}
+
+func (c *ClientWithResponses) UpdateProfileWithResponse(ctx context.Context, profileName string, body UpdateProfileJSONRequestBody, reqEditors ...RequestEditorFn) (*UpdateProfileResponse, error) {
+	rsp, err := c.UpdateProfile(ctx, profileName, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseUpdateProfileResponse(rsp)
+}
+
+// SelfTestWithBodyWithResponse request with arbitrary body returning *SelfTestResponse
+func (c *ClientWithResponses) SelfTestWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*SelfTestResponse, error) {
+	rsp, err := c.SelfTestWithBody(ctx, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseSelfTestResponse(rsp)
+}
+
+func (c *ClientWithResponses) SelfTestWithResponse(ctx context.Context, body SelfTestJSONRequestBody, reqEditors ...RequestEditorFn) (*SelfTestResponse, error) {
+	rsp, err := c.SelfTest(ctx, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseSelfTestResponse(rsp)
+}
+
+// GetStatsWithResponse request returning *GetStatsResponse
+func (c *ClientWithResponses) GetStatsWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetStatsResponse, error) {
+	rsp, err := c.GetStats(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetStatsResponse(rsp)
+}
+
+// GetStorageWithResponse request returning *GetStorageResponse
+func (c *ClientWithResponses) GetStorageWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetStorageResponse, error) {
+	rsp, err := c.GetStorage(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetStorageResponse(rsp)
+}
+
+// GetUsageWithResponse request returning *GetUsageResponse
+func (c *ClientWithResponses) GetUsageWithResponse(ctx context.Context, params *GetUsageParams, reqEditors ...RequestEditorFn) (*GetUsageResponse, error) {
+	rsp, err := c.GetUsage(ctx, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetUsageResponse(rsp)
+}
+
+// BatchUpdateResourceWithBodyWithResponse request with arbitrary body returning *BatchUpdateResourceResponse
+func (c *ClientWithResponses) BatchUpdateResourceWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*BatchUpdateResourceResponse, error) {
+	rsp, err := c.BatchUpdateResourceWithBody(ctx, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseBatchUpdateResourceResponse(rsp)
+}
+
+func (c *ClientWithResponses) BatchUpdateResourceWithResponse(ctx context.Context, body BatchUpdateResourceJSONRequestBody, reqEditors ...RequestEditorFn) (*BatchUpdateResourceResponse, error) {
+	rsp, err := c.BatchUpdateResource(ctx, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseBatchUpdateResourceResponse(rsp)
+}
+
+// DelSDFuncWithBodyWithResponse request with arbitrary body returning *DelSDFuncResponse
+func (c *ClientWithResponses) DelSDFuncWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*DelSDFuncResponse, error) {
+	rsp, err := c.DelSDFuncWithBody(ctx, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseDelSDFuncResponse(rsp)
+}
+
+func (c *ClientWithResponses) DelSDFuncWithResponse(ctx context.Context, body DelSDFuncJSONRequestBody, reqEditors ...RequestEditorFn) (*DelSDFuncResponse, error) {
+	rsp, err := c.DelSDFunc(ctx, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseDelSDFuncResponse(rsp)
+}
+
+// ExtraBatchImagesWithBodyWithResponse request with arbitrary body returning *ExtraBatchImagesResponse
+func (c *ClientWithResponses) ExtraBatchImagesWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*ExtraBatchImagesResponse, error) {
+	rsp, err := c.ExtraBatchImagesWithBody(ctx, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseExtraBatchImagesResponse(rsp)
+}
+
+func (c *ClientWithResponses) ExtraBatchImagesWithResponse(ctx context.Context, body ExtraBatchImagesJSONRequestBody, reqEditors ...RequestEditorFn) (*ExtraBatchImagesResponse, error) {
+	rsp, err := c.ExtraBatchImages(ctx, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseExtraBatchImagesResponse(rsp)
+}
+
+// ExtraImagesWithBodyWithResponse request with arbitrary body returning *ExtraImagesResponse
+func (c *ClientWithResponses) ExtraImagesWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*ExtraImagesResponse, error) {
+	rsp, err := c.ExtraImagesWithBody(ctx, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseExtraImagesResponse(rsp)
+}
+
+func (c *ClientWithResponses) ExtraImagesWithResponse(ctx context.Context, body ExtraImagesJSONRequestBody, reqEditors ...RequestEditorFn) (*ExtraImagesResponse, error) {
+	rsp, err := c.ExtraImages(ctx, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseExtraImagesResponse(rsp)
+}
+
+// Img2ImgWithBodyWithResponse request with arbitrary body returning *Img2ImgResponse
+func (c *ClientWithResponses) Img2ImgWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*Img2ImgResponse, error) {
+	rsp, err := c.Img2ImgWithBody(ctx, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseImg2ImgResponse(rsp)
+}
+
+func (c *ClientWithResponses) Img2ImgWithResponse(ctx context.Context, body Img2ImgJSONRequestBody, reqEditors ...RequestEditorFn) (*Img2ImgResponse, error) {
+	rsp, err := c.Img2Img(ctx, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseImg2ImgResponse(rsp)
+}
+
+// InterrogateWithBodyWithResponse request with arbitrary body returning *InterrogateResponse
+func (c *ClientWithResponses) InterrogateWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*InterrogateResponse, error) {
+	rsp, err := c.InterrogateWithBody(ctx, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseInterrogateResponse(rsp)
+}
+
+func (c *ClientWithResponses) InterrogateWithResponse(ctx context.Context, body InterrogateJSONRequestBody, reqEditors ...RequestEditorFn) (*InterrogateResponse, error) {
+	rsp, err := c.Interrogate(ctx, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseInterrogateResponse(rsp)
+}
+
+// ListSdFuncWithResponse request returning *ListSdFuncResponse
+func (c *ClientWithResponses) ListSdFuncWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*ListSdFuncResponse, error) {
+	rsp, err := c.ListSdFunc(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseListSdFuncResponse(rsp)
+}
+
+// LoginWithBodyWithResponse request with arbitrary body returning *LoginResponse
+func (c *ClientWithResponses) LoginWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*LoginResponse, error) {
+	rsp, err := c.LoginWithBody(ctx, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseLoginResponse(rsp)
+}
+
+func (c *ClientWithResponses) LoginWithResponse(ctx context.Context, body LoginJSONRequestBody, reqEditors ...RequestEditorFn) (*LoginResponse, error) {
+	rsp, err := c.Login(ctx, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseLoginResponse(rsp)
+}
+
+// ListModelsWithResponse request returning *ListModelsResponse
+func (c *ClientWithResponses) ListModelsWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*ListModelsResponse, error) {
+	rsp, err := c.ListModels(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseListModelsResponse(rsp)
+}
+
+// RegisterModelWithBodyWithResponse request with arbitrary body returning *RegisterModelResponse
+func (c *ClientWithResponses) RegisterModelWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*RegisterModelResponse, error) {
+	rsp, err := c.RegisterModelWithBody(ctx, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseRegisterModelResponse(rsp)
+}
+
+func (c *ClientWithResponses) RegisterModelWithResponse(ctx context.Context, body RegisterModelJSONRequestBody, reqEditors ...RequestEditorFn) (*RegisterModelResponse, error) {
+	rsp, err := c.RegisterModel(ctx, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseRegisterModelResponse(rsp)
+}
+
+// BatchDeleteModelsWithBodyWithResponse request with arbitrary body returning *BatchDeleteModelsResponse
+func (c *ClientWithResponses) BatchDeleteModelsWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*BatchDeleteModelsResponse, error) {
+	rsp, err := c.BatchDeleteModelsWithBody(ctx, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseBatchDeleteModelsResponse(rsp)
+}
+
+func (c *ClientWithResponses) BatchDeleteModelsWithResponse(ctx context.Context, body BatchDeleteModelsJSONRequestBody, reqEditors ...RequestEditorFn) (*BatchDeleteModelsResponse, error) {
+	rsp, err := c.BatchDeleteModels(ctx, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseBatchDeleteModelsResponse(rsp)
+}
+
+// DeleteModelWithResponse request returning *DeleteModelResponse
+func (c *ClientWithResponses) DeleteModelWithResponse(ctx context.Context, modelName string, reqEditors ...RequestEditorFn) (*DeleteModelResponse, error) {
+	rsp, err := c.DeleteModel(ctx, modelName, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseDeleteModelResponse(rsp)
+}
+
+// GetModelWithResponse request returning *GetModelResponse
+func (c *ClientWithResponses) GetModelWithResponse(ctx context.Context, modelName string, reqEditors ...RequestEditorFn) (*GetModelResponse, error) {
+	rsp, err := c.GetModel(ctx, modelName, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetModelResponse(rsp)
+}
+
+// UpdateModelWithBodyWithResponse request with arbitrary body returning *UpdateModelResponse
+func (c *ClientWithResponses) UpdateModelWithBodyWithResponse(ctx context.Context, modelName string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*UpdateModelResponse, error) {
+	rsp, err := c.UpdateModelWithBody(ctx, modelName, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseUpdateModelResponse(rsp)
+}
+
+func (c *ClientWithResponses) UpdateModelWithResponse(ctx context.Context, modelName string, body UpdateModelJSONRequestBody, reqEditors ...RequestEditorFn) (*UpdateModelResponse, error) {
+	rsp, err := c.UpdateModel(ctx, modelName, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseUpdateModelResponse(rsp)
+}
+
+// DistributeModelWithResponse request returning *DistributeModelResponse
+func (c *ClientWithResponses) DistributeModelWithResponse(ctx context.Context, modelName string, reqEditors ...RequestEditorFn) (*DistributeModelResponse, error) {
+	rsp, err := c.DistributeModel(ctx, modelName, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseDistributeModelResponse(rsp)
+}
+
+// UpdateOptionsWithBodyWithResponse request with arbitrary body returning *UpdateOptionsResponse
+func (c *ClientWithResponses) UpdateOptionsWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*UpdateOptionsResponse, error) {
+	rsp, err := c.UpdateOptionsWithBody(ctx, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseUpdateOptionsResponse(rsp)
+}
+
+func (c *ClientWithResponses) UpdateOptionsWithResponse(ctx context.Context, body UpdateOptionsJSONRequestBody, reqEditors ...RequestEditorFn) (*UpdateOptionsResponse, error) {
+	rsp, err := c.UpdateOptions(ctx, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseUpdateOptionsResponse(rsp)
+}
+
+// ListPromptTemplatesWithResponse request returning *ListPromptTemplatesResponse
+func (c *ClientWithResponses) ListPromptTemplatesWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*ListPromptTemplatesResponse, error) {
+	rsp, err := c.ListPromptTemplates(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseListPromptTemplatesResponse(rsp)
+}
+
+// DeletePromptTemplateWithResponse request returning *DeletePromptTemplateResponse
+func (c *ClientWithResponses) DeletePromptTemplateWithResponse(ctx context.Context, templateName string, reqEditors ...RequestEditorFn) (*DeletePromptTemplateResponse, error) {
+	rsp, err := c.DeletePromptTemplate(ctx, templateName, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseDeletePromptTemplateResponse(rsp)
+}
+
+// GetPromptTemplateWithResponse request returning *GetPromptTemplateResponse
+func (c *ClientWithResponses) GetPromptTemplateWithResponse(ctx context.Context, templateName string, reqEditors ...RequestEditorFn) (*GetPromptTemplateResponse, error) {
+	rsp, err := c.GetPromptTemplate(ctx, templateName, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetPromptTemplateResponse(rsp)
+}
+
+// UpdatePromptTemplateWithBodyWithResponse request with arbitrary body returning *UpdatePromptTemplateResponse
+func (c *ClientWithResponses) UpdatePromptTemplateWithBodyWithResponse(ctx context.Context, templateName string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*UpdatePromptTemplateResponse, error) {
+	rsp, err := c.UpdatePromptTemplateWithBody(ctx, templateName, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseUpdatePromptTemplateResponse(rsp)
+}
+
+func (c *ClientWithResponses) UpdatePromptTemplateWithResponse(ctx context.Context, templateName string, body UpdatePromptTemplateJSONRequestBody, reqEditors ...RequestEditorFn) (*UpdatePromptTemplateResponse, error) {
+	rsp, err := c.UpdatePromptTemplate(ctx, templateName, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseUpdatePromptTemplateResponse(rsp)
+}
+
+// RestartWithResponse request returning *RestartResponse
+func (c *ClientWithResponses) RestartWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*RestartResponse, error) {
+	rsp, err := c.Restart(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseRestartResponse(rsp)
+}
+
+// GetCapabilitiesWithResponse request returning *GetCapabilitiesResponse
+func (c *ClientWithResponses) GetCapabilitiesWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetCapabilitiesResponse, error) {
+	rsp, err := c.GetCapabilities(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetCapabilitiesResponse(rsp)
+}
+
+// GetSharedTaskResultWithResponse request returning *GetSharedTaskResultResponse
+func (c *ClientWithResponses) GetSharedTaskResultWithResponse(ctx context.Context, token string, reqEditors ...RequestEditorFn) (*GetSharedTaskResultResponse, error) {
+	rsp, err := c.GetSharedTaskResult(ctx, token, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetSharedTaskResultResponse(rsp)
+}
+
+// ListTasksWithResponse request returning *ListTasksResponse
+func (c *ClientWithResponses) ListTasksWithResponse(ctx context.Context, params *ListTasksParams, reqEditors ...RequestEditorFn) (*ListTasksResponse, error) {
+	rsp, err := c.ListTasks(ctx, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseListTasksResponse(rsp)
+}
+
+// GetTaskResultsWithBodyWithResponse request with arbitrary body returning *GetTaskResultsResponse
+func (c *ClientWithResponses) GetTaskResultsWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*GetTaskResultsResponse, error) {
+	rsp, err := c.GetTaskResultsWithBody(ctx, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetTaskResultsResponse(rsp)
+}
+
+func (c *ClientWithResponses) GetTaskResultsWithResponse(ctx context.Context, body GetTaskResultsJSONRequestBody, reqEditors ...RequestEditorFn) (*GetTaskResultsResponse, error) {
+	rsp, err := c.GetTaskResults(ctx, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetTaskResultsResponse(rsp)
+}
+
+// CancelTaskWithResponse request returning *CancelTaskResponse
+func (c *ClientWithResponses) CancelTaskWithResponse(ctx context.Context, taskId string, reqEditors ...RequestEditorFn) (*CancelTaskResponse, error) {
+	rsp, err := c.CancelTask(ctx, taskId, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseCancelTaskResponse(rsp)
+}
+
+// GetTaskProgressWithResponse request returning *GetTaskProgressResponse
+func (c *ClientWithResponses) GetTaskProgressWithResponse(ctx context.Context, taskId string, reqEditors ...RequestEditorFn) (*GetTaskProgressResponse, error) {
+	rsp, err := c.GetTaskProgress(ctx, taskId, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetTaskProgressResponse(rsp)
+}
+
+// GetTaskReproduceWithResponse request returning *GetTaskReproduceResponse
+func (c *ClientWithResponses) GetTaskReproduceWithResponse(ctx context.Context, taskId string, reqEditors ...RequestEditorFn) (*GetTaskReproduceResponse, error) {
+	rsp, err := c.GetTaskReproduce(ctx, taskId, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetTaskReproduceResponse(rsp)
+}
+
+// GetTaskResultWithResponse request returning *GetTaskResultResponse
+func (c *ClientWithResponses) GetTaskResultWithResponse(ctx context.Context, taskId string, reqEditors ...RequestEditorFn) (*GetTaskResultResponse, error) {
+	rsp, err := c.GetTaskResult(ctx, taskId, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetTaskResultResponse(rsp)
+}
+
+// ShareTaskWithResponse request returning *ShareTaskResponse
+func (c *ClientWithResponses) ShareTaskWithResponse(ctx context.Context, taskId string, params *ShareTaskParams, reqEditors ...RequestEditorFn) (*ShareTaskResponse, error) {
+	rsp, err := c.ShareTask(ctx, taskId, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseShareTaskResponse(rsp)
+}
+
+// Txt2ImgWithBodyWithResponse request with arbitrary body returning *Txt2ImgResponse
+func (c *ClientWithResponses) Txt2ImgWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*Txt2ImgResponse, error) {
+	rsp, err := c.Txt2ImgWithBody(ctx, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseTxt2ImgResponse(rsp)
+}
+
+func (c *ClientWithResponses) Txt2ImgWithResponse(ctx context.Context, body Txt2ImgJSONRequestBody, reqEditors ...RequestEditorFn) (*Txt2ImgResponse, error) {
+	rsp, err := c.Txt2Img(ctx, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseTxt2ImgResponse(rsp)
+}
+
+// Txt2ImgCachedWithBodyWithResponse request with arbitrary body returning *Txt2ImgCachedResponse
+func (c *ClientWithResponses) Txt2ImgCachedWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*Txt2ImgCachedResponse, error) {
+	rsp, err := c.Txt2ImgCachedWithBody(ctx, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseTxt2ImgCachedResponse(rsp)
+}
+
+func (c *ClientWithResponses) Txt2ImgCachedWithResponse(ctx context.Context, body Txt2ImgCachedJSONRequestBody, reqEditors ...RequestEditorFn) (*Txt2ImgCachedResponse, error) {
+	rsp, err := c.Txt2ImgCached(ctx, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseTxt2ImgCachedResponse(rsp)
+}
+
+// Txt2ImgMultiWithBodyWithResponse request with arbitrary body returning *Txt2ImgMultiResponse
+func (c *ClientWithResponses) Txt2ImgMultiWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*Txt2ImgMultiResponse, error) {
+	rsp, err := c.Txt2ImgMultiWithBody(ctx, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseTxt2ImgMultiResponse(rsp)
+}
+
+func (c *ClientWithResponses) Txt2ImgMultiWithResponse(ctx context.Context, body Txt2ImgMultiJSONRequestBody, reqEditors ...RequestEditorFn) (*Txt2ImgMultiResponse, error) {
+	rsp, err := c.Txt2ImgMulti(ctx, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseTxt2ImgMultiResponse(rsp)
+}
+
+// DeleteUserImagesWithBodyWithResponse request with arbitrary body returning *DeleteUserImagesResponse
+func (c *ClientWithResponses) DeleteUserImagesWithBodyWithResponse(ctx context.Context, user string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*DeleteUserImagesResponse, error) {
+	rsp, err := c.DeleteUserImagesWithBody(ctx, user, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseDeleteUserImagesResponse(rsp)
+}
+
+func (c *ClientWithResponses) DeleteUserImagesWithResponse(ctx context.Context, user string, body DeleteUserImagesJSONRequestBody, reqEditors ...RequestEditorFn) (*DeleteUserImagesResponse, error) {
+	rsp, err := c.DeleteUserImages(ctx, user, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseDeleteUserImagesResponse(rsp)
+}
+
+// ListUserImagesWithResponse request returning *ListUserImagesResponse
+func (c *ClientWithResponses) ListUserImagesWithResponse(ctx context.Context, user string, params *ListUserImagesParams, reqEditors ...RequestEditorFn) (*ListUserImagesResponse, error) {
+	rsp, err := c.ListUserImages(ctx, user, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseListUserImagesResponse(rsp)
+}
+
+// GetUserStatsWithResponse request returning *GetUserStatsResponse
+func (c *ClientWithResponses) GetUserStatsWithResponse(ctx context.Context, user string, params *GetUserStatsParams, reqEditors ...RequestEditorFn) (*GetUserStatsResponse, error) {
+	rsp, err := c.GetUserStats(ctx, user, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetUserStatsResponse(rsp)
+}
+
+// GetVersionWithResponse request returning *GetVersionResponse
+func (c *ClientWithResponses) GetVersionWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetVersionResponse, error) {
+	rsp, err := c.GetVersion(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetVersionResponse(rsp)
+}
+
+// ParseGetEnvResponse parses an HTTP response from a GetEnvWithResponse call
+func ParseGetEnvResponse(rsp *http.Response) (*GetEnvResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetEnvResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest EnvInfo
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseTailSdLogsResponse parses an HTTP response from a TailSdLogsWithResponse call
+func ParseTailSdLogsResponse(rsp *http.Response) (*TailSdLogsResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &TailSdLogsResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest SdLogs
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetMaintenanceResponse parses an HTTP response from a GetMaintenanceWithResponse call
+func ParseGetMaintenanceResponse(rsp *http.Response) (*GetMaintenanceResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetMaintenanceResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest MaintenanceStatus
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseSetMaintenanceResponse parses an HTTP response from a SetMaintenanceWithResponse call
+func ParseSetMaintenanceResponse(rsp *http.Response) (*SetMaintenanceResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &SetMaintenanceResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest MaintenanceStatus
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseReconcileModelsResponse parses an HTTP response from a ReconcileModelsWithResponse call
+func ParseReconcileModelsResponse(rsp *http.Response) (*ReconcileModelsResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ReconcileModelsResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest ReconcileModelsResult
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetModelDefaultsResponse parses an HTTP response from a GetModelDefaultsWithResponse call
+func ParseGetModelDefaultsResponse(rsp *http.Response) (*GetModelDefaultsResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetModelDefaultsResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest ModelDefaults
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseUpdateModelDefaultsResponse parses an HTTP response from a UpdateModelDefaultsWithResponse call
+func ParseUpdateModelDefaultsResponse(rsp *http.Response) (*UpdateModelDefaultsResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &UpdateModelDefaultsResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseListProfilesResponse parses an HTTP response from a ListProfilesWithResponse call
+func ParseListProfilesResponse(rsp *http.Response) (*ListProfilesResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ListProfilesResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest []ModelProfile
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseDeleteProfileResponse parses an HTTP response from a DeleteProfileWithResponse call
+func ParseDeleteProfileResponse(rsp *http.Response) (*DeleteProfileResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &DeleteProfileResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetProfileResponse parses an HTTP response from a GetProfileWithResponse call
+func ParseGetProfileResponse(rsp *http.Response) (*GetProfileResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetProfileResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest ModelProfile
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseUpdateProfileResponse parses an HTTP response from a UpdateProfileWithResponse call
+func ParseUpdateProfileResponse(rsp *http.Response) (*UpdateProfileResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &UpdateProfileResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseSelfTestResponse parses an HTTP response from a SelfTestWithResponse call
+func ParseSelfTestResponse(rsp *http.Response) (*SelfTestResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &SelfTestResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest SelfTestResult
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetStatsResponse parses an HTTP response from a GetStatsWithResponse call
+func ParseGetStatsResponse(rsp *http.Response) (*GetStatsResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetStatsResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest Stats
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetStorageResponse parses an HTTP response from a GetStorageWithResponse call
+func ParseGetStorageResponse(rsp *http.Response) (*GetStorageResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetStorageResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest StorageInfo
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetUsageResponse parses an HTTP response from a GetUsageWithResponse call
+func ParseGetUsageResponse(rsp *http.Response) (*GetUsageResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetUsageResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest UsageList
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseBatchUpdateResourceResponse parses an HTTP response from a BatchUpdateResourceWithResponse call
+func ParseBatchUpdateResourceResponse(rsp *http.Response) (*BatchUpdateResourceResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &BatchUpdateResourceResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest BatchUpdateSdResourceResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest BatchUpdateSdResourceResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseDelSDFuncResponse parses an HTTP response from a DelSDFuncWithResponse call
+func ParseDelSDFuncResponse(rsp *http.Response) (*DelSDFuncResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &DelSDFuncResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest DelSDFunctionResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest DelSDFunctionResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseExtraBatchImagesResponse parses an HTTP response from a ExtraBatchImagesWithResponse call
+func ParseExtraBatchImagesResponse(rsp *http.Response) (*ExtraBatchImagesResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ExtraBatchImagesResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest SubmitTaskResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseExtraImagesResponse parses an HTTP response from a ExtraImagesWithResponse call
+func ParseExtraImagesResponse(rsp *http.Response) (*ExtraImagesResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ExtraImagesResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest SubmitTaskResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseImg2ImgResponse parses an HTTP response from a Img2ImgWithResponse call
+func ParseImg2ImgResponse(rsp *http.Response) (*Img2ImgResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &Img2ImgResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest SubmitTaskResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseInterrogateResponse parses an HTTP response from a InterrogateWithResponse call
+func ParseInterrogateResponse(rsp *http.Response) (*InterrogateResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &InterrogateResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest InterrogateResult
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseListSdFuncResponse parses an HTTP response from a ListSdFuncWithResponse call
+func ParseListSdFuncResponse(rsp *http.Response) (*ListSdFuncResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ListSdFuncResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest ListSDFunctionResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest ListSDFunctionResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseLoginResponse parses an HTTP response from a LoginWithResponse call
+func ParseLoginResponse(rsp *http.Response) (*LoginResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &LoginResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest UserLoginResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseListModelsResponse parses an HTTP response from a ListModelsWithResponse call
+func ParseListModelsResponse(rsp *http.Response) (*ListModelsResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ListModelsResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest []ModelAttributes
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
 	}
-	return 0
+
+	return response, nil
 }
 
-// BatchUpdateResourceWithBodyWithResponse request with arbitrary body returning *BatchUpdateResourceResponse
-func (c *ClientWithResponses) BatchUpdateResourceWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*BatchUpdateResourceResponse, error) {
-	rsp, err := c.BatchUpdateResourceWithBody(ctx, contentType, body, reqEditors...)
+// ParseRegisterModelResponse parses an HTTP response from a RegisterModelWithResponse call
+func ParseRegisterModelResponse(rsp *http.Response) (*RegisterModelResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
 	if err != nil {
 		return nil, err
 	}
-	return ParseBatchUpdateResourceResponse(rsp)
-}
 
-func (c *ClientWithResponses) BatchUpdateResourceWithResponse(ctx context.Context, body BatchUpdateResourceJSONRequestBody, reqEditors ...RequestEditorFn) (*BatchUpdateResourceResponse, error) {
-	rsp, err := c.BatchUpdateResource(ctx, body, reqEditors...)
-	if err != nil {
-		return nil, err
+	response := &RegisterModelResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
 	}
-	return ParseBatchUpdateResourceResponse(rsp)
-}
 
-// DelSDFuncWithBodyWithResponse request with arbitrary body returning *DelSDFuncResponse
-func (c *ClientWithResponses) DelSDFuncWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*DelSDFuncResponse, error) {
-	rsp, err := c.DelSDFuncWithBody(ctx, contentType, body, reqEditors...)
-	if err != nil {
-		return nil, err
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
 	}
-	return ParseDelSDFuncResponse(rsp)
+
+	return response, nil
 }
 
-func (c *ClientWithResponses) DelSDFuncWithResponse(ctx context.Context, body DelSDFuncJSONRequestBody, reqEditors ...RequestEditorFn) (*DelSDFuncResponse, error) {
-	rsp, err := c.DelSDFunc(ctx, body, reqEditors...)
+// ParseBatchDeleteModelsResponse parses an HTTP response from a BatchDeleteModelsWithResponse call
+func ParseBatchDeleteModelsResponse(rsp *http.Response) (*BatchDeleteModelsResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
 	if err != nil {
 		return nil, err
 	}
-	return ParseDelSDFuncResponse(rsp)
-}
 
-// ExtraImagesWithBodyWithResponse request with arbitrary body returning *ExtraImagesResponse
-func (c *ClientWithResponses) ExtraImagesWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*ExtraImagesResponse, error) {
-	rsp, err := c.ExtraImagesWithBody(ctx, contentType, body, reqEditors...)
-	if err != nil {
-		return nil, err
+	response := &BatchDeleteModelsResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
 	}
-	return ParseExtraImagesResponse(rsp)
-}
 
-func (c *ClientWithResponses) ExtraImagesWithResponse(ctx context.Context, body ExtraImagesJSONRequestBody, reqEditors ...RequestEditorFn) (*ExtraImagesResponse, error) {
-	rsp, err := c.ExtraImages(ctx, body, reqEditors...)
-	if err != nil {
-		return nil, err
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest BatchDeleteModelsResult
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
 	}
-	return ParseExtraImagesResponse(rsp)
+
+	return response, nil
 }
 
-// Img2ImgWithBodyWithResponse request with arbitrary body returning *Img2ImgResponse
-func (c *ClientWithResponses) Img2ImgWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*Img2ImgResponse, error) {
-	rsp, err := c.Img2ImgWithBody(ctx, contentType, body, reqEditors...)
+// ParseDeleteModelResponse parses an HTTP response from a DeleteModelWithResponse call
+func ParseDeleteModelResponse(rsp *http.Response) (*DeleteModelResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
 	if err != nil {
 		return nil, err
 	}
-	return ParseImg2ImgResponse(rsp)
-}
 
-func (c *ClientWithResponses) Img2ImgWithResponse(ctx context.Context, body Img2ImgJSONRequestBody, reqEditors ...RequestEditorFn) (*Img2ImgResponse, error) {
-	rsp, err := c.Img2Img(ctx, body, reqEditors...)
-	if err != nil {
-		return nil, err
+	response := &DeleteModelResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
 	}
-	return ParseImg2ImgResponse(rsp)
-}
 
-// ListSdFuncWithResponse request returning *ListSdFuncResponse
-func (c *ClientWithResponses) ListSdFuncWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*ListSdFuncResponse, error) {
-	rsp, err := c.ListSdFunc(ctx, reqEditors...)
-	if err != nil {
-		return nil, err
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
 	}
-	return ParseListSdFuncResponse(rsp)
+
+	return response, nil
 }
 
-// LoginWithBodyWithResponse request with arbitrary body returning *LoginResponse
-func (c *ClientWithResponses) LoginWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*LoginResponse, error) {
-	rsp, err := c.LoginWithBody(ctx, contentType, body, reqEditors...)
+// ParseGetModelResponse parses an HTTP response from a GetModelWithResponse call
+func ParseGetModelResponse(rsp *http.Response) (*GetModelResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
 	if err != nil {
 		return nil, err
 	}
-	return ParseLoginResponse(rsp)
-}
 
-func (c *ClientWithResponses) LoginWithResponse(ctx context.Context, body LoginJSONRequestBody, reqEditors ...RequestEditorFn) (*LoginResponse, error) {
-	rsp, err := c.Login(ctx, body, reqEditors...)
-	if err != nil {
-		return nil, err
+	response := &GetModelResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
 	}
-	return ParseLoginResponse(rsp)
-}
 
-// ListModelsWithResponse request returning *ListModelsResponse
-func (c *ClientWithResponses) ListModelsWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*ListModelsResponse, error) {
-	rsp, err := c.ListModels(ctx, reqEditors...)
-	if err != nil {
-		return nil, err
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest ModelAttributes
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
 	}
-	return ParseListModelsResponse(rsp)
+
+	return response, nil
 }
 
-// RegisterModelWithBodyWithResponse request with arbitrary body returning *RegisterModelResponse
-func (c *ClientWithResponses) RegisterModelWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*RegisterModelResponse, error) {
-	rsp, err := c.RegisterModelWithBody(ctx, contentType, body, reqEditors...)
+// ParseUpdateModelResponse parses an HTTP response from a UpdateModelWithResponse call
+func ParseUpdateModelResponse(rsp *http.Response) (*UpdateModelResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
 	if err != nil {
 		return nil, err
 	}
-	return ParseRegisterModelResponse(rsp)
-}
 
-func (c *ClientWithResponses) RegisterModelWithResponse(ctx context.Context, body RegisterModelJSONRequestBody, reqEditors ...RequestEditorFn) (*RegisterModelResponse, error) {
-	rsp, err := c.RegisterModel(ctx, body, reqEditors...)
-	if err != nil {
-		return nil, err
+	response := &UpdateModelResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
 	}
-	return ParseRegisterModelResponse(rsp)
-}
 
-// DeleteModelWithResponse request returning *DeleteModelResponse
-func (c *ClientWithResponses) DeleteModelWithResponse(ctx context.Context, modelName string, reqEditors ...RequestEditorFn) (*DeleteModelResponse, error) {
-	rsp, err := c.DeleteModel(ctx, modelName, reqEditors...)
-	if err != nil {
-		return nil, err
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
 	}
-	return ParseDeleteModelResponse(rsp)
+
+	return response, nil
 }
 
-// GetModelWithResponse request returning *GetModelResponse
-func (c *ClientWithResponses) GetModelWithResponse(ctx context.Context, modelName string, reqEditors ...RequestEditorFn) (*GetModelResponse, error) {
-	rsp, err := c.GetModel(ctx, modelName, reqEditors...)
+// ParseDistributeModelResponse parses an HTTP response from a DistributeModelWithResponse call
+func ParseDistributeModelResponse(rsp *http.Response) (*DistributeModelResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
 	if err != nil {
 		return nil, err
 	}
-	return ParseGetModelResponse(rsp)
-}
 
-// UpdateModelWithBodyWithResponse request with arbitrary body returning *UpdateModelResponse
-func (c *ClientWithResponses) UpdateModelWithBodyWithResponse(ctx context.Context, modelName string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*UpdateModelResponse, error) {
-	rsp, err := c.UpdateModelWithBody(ctx, modelName, contentType, body, reqEditors...)
-	if err != nil {
-		return nil, err
+	response := &DistributeModelResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
 	}
-	return ParseUpdateModelResponse(rsp)
-}
 
-func (c *ClientWithResponses) UpdateModelWithResponse(ctx context.Context, modelName string, body UpdateModelJSONRequestBody, reqEditors ...RequestEditorFn) (*UpdateModelResponse, error) {
-	rsp, err := c.UpdateModel(ctx, modelName, body, reqEditors...)
-	if err != nil {
-		return nil, err
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest DistributeModelResult
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest DistributeModelResult
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
 	}
-	return ParseUpdateModelResponse(rsp)
+
+	return response, nil
 }
 
-// UpdateOptionsWithBodyWithResponse request with arbitrary body returning *UpdateOptionsResponse
-func (c *ClientWithResponses) UpdateOptionsWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*UpdateOptionsResponse, error) {
-	rsp, err := c.UpdateOptionsWithBody(ctx, contentType, body, reqEditors...)
+// ParseUpdateOptionsResponse parses an HTTP response from a UpdateOptionsWithResponse call
+func ParseUpdateOptionsResponse(rsp *http.Response) (*UpdateOptionsResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
 	if err != nil {
 		return nil, err
 	}
-	return ParseUpdateOptionsResponse(rsp)
-}
 
-func (c *ClientWithResponses) UpdateOptionsWithResponse(ctx context.Context, body UpdateOptionsJSONRequestBody, reqEditors ...RequestEditorFn) (*UpdateOptionsResponse, error) {
-	rsp, err := c.UpdateOptions(ctx, body, reqEditors...)
-	if err != nil {
-		return nil, err
+	response := &UpdateOptionsResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest ResponseMessage
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
 	}
-	return ParseUpdateOptionsResponse(rsp)
+
+	return response, nil
 }
 
-// RestartWithResponse request returning *RestartResponse
-func (c *ClientWithResponses) RestartWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*RestartResponse, error) {
-	rsp, err := c.Restart(ctx, reqEditors...)
+// ParseListPromptTemplatesResponse parses an HTTP response from a ListPromptTemplatesWithResponse call
+func ParseListPromptTemplatesResponse(rsp *http.Response) (*ListPromptTemplatesResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
 	if err != nil {
 		return nil, err
 	}
-	return ParseRestartResponse(rsp)
-}
 
-// CancelTaskWithResponse request returning *CancelTaskResponse
-func (c *ClientWithResponses) CancelTaskWithResponse(ctx context.Context, taskId string, reqEditors ...RequestEditorFn) (*CancelTaskResponse, error) {
-	rsp, err := c.CancelTask(ctx, taskId, reqEditors...)
-	if err != nil {
-		return nil, err
+	response := &ListPromptTemplatesResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
 	}
-	return ParseCancelTaskResponse(rsp)
-}
 
-// GetTaskProgressWithResponse request returning *GetTaskProgressResponse
-func (c *ClientWithResponses) GetTaskProgressWithResponse(ctx context.Context, taskId string, reqEditors ...RequestEditorFn) (*GetTaskProgressResponse, error) {
-	rsp, err := c.GetTaskProgress(ctx, taskId, reqEditors...)
-	if err != nil {
-		return nil, err
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest []PromptTemplate
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
 	}
-	return ParseGetTaskProgressResponse(rsp)
+
+	return response, nil
 }
 
-// GetTaskResultWithResponse request returning *GetTaskResultResponse
-func (c *ClientWithResponses) GetTaskResultWithResponse(ctx context.Context, taskId string, reqEditors ...RequestEditorFn) (*GetTaskResultResponse, error) {
-	rsp, err := c.GetTaskResult(ctx, taskId, reqEditors...)
+// ParseDeletePromptTemplateResponse parses an HTTP response from a DeletePromptTemplateWithResponse call
+func ParseDeletePromptTemplateResponse(rsp *http.Response) (*DeletePromptTemplateResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
 	if err != nil {
 		return nil, err
 	}
-	return ParseGetTaskResultResponse(rsp)
-}
 
-// Txt2ImgWithBodyWithResponse request with arbitrary body returning *Txt2ImgResponse
-func (c *ClientWithResponses) Txt2ImgWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*Txt2ImgResponse, error) {
-	rsp, err := c.Txt2ImgWithBody(ctx, contentType, body, reqEditors...)
-	if err != nil {
-		return nil, err
+	response := &DeletePromptTemplateResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
 	}
-	return ParseTxt2ImgResponse(rsp)
-}
 
-func (c *ClientWithResponses) Txt2ImgWithResponse(ctx context.Context, body Txt2ImgJSONRequestBody, reqEditors ...RequestEditorFn) (*Txt2ImgResponse, error) {
-	rsp, err := c.Txt2Img(ctx, body, reqEditors...)
-	if err != nil {
-		return nil, err
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
 	}
-	return ParseTxt2ImgResponse(rsp)
+
+	return response, nil
 }
 
-// ParseBatchUpdateResourceResponse parses an HTTP response from a BatchUpdateResourceWithResponse call
-func ParseBatchUpdateResourceResponse(rsp *http.Response) (*BatchUpdateResourceResponse, error) {
+// ParseGetPromptTemplateResponse parses an HTTP response from a GetPromptTemplateWithResponse call
+func ParseGetPromptTemplateResponse(rsp *http.Response) (*GetPromptTemplateResponse, error) {
 	bodyBytes, err := io.ReadAll(rsp.Body)
 	defer func() { _ = rsp.Body.Close() }()
 	if err != nil {
 		return nil, err
 	}
 
-	response := &BatchUpdateResourceResponse{
+	response := &GetPromptTemplateResponse{
 		Body:         bodyBytes,
 		HTTPResponse: rsp,
 	}
 
 	switch {
 	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
-		var dest BatchUpdateSdResourceResponse
+		var dest PromptTemplate
 		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
 			return nil, err
 		}
 		response.JSON200 = &dest
 
-	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
-		var dest BatchUpdateSdResourceResponse
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
 		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
 			return nil, err
 		}
-		response.JSON500 = &dest
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseUpdatePromptTemplateResponse parses an HTTP response from a UpdatePromptTemplateWithResponse call
+func ParseUpdatePromptTemplateResponse(rsp *http.Response) (*UpdatePromptTemplateResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &UpdatePromptTemplateResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
 
+	switch {
 	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
 		var dest Error
 		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
@@ -1857,33 +6466,52 @@ func ParseBatchUpdateResourceResponse(rsp *http.Response) (*BatchUpdateResourceR
 	return response, nil
 }
 
-// ParseDelSDFuncResponse parses an HTTP response from a DelSDFuncWithResponse call
-func ParseDelSDFuncResponse(rsp *http.Response) (*DelSDFuncResponse, error) {
+// ParseRestartResponse parses an HTTP response from a RestartWithResponse call
+func ParseRestartResponse(rsp *http.Response) (*RestartResponse, error) {
 	bodyBytes, err := io.ReadAll(rsp.Body)
 	defer func() { _ = rsp.Body.Close() }()
 	if err != nil {
 		return nil, err
 	}
 
-	response := &DelSDFuncResponse{
+	response := &RestartResponse{
 		Body:         bodyBytes,
 		HTTPResponse: rsp,
 	}
 
 	switch {
-	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
-		var dest DelSDFunctionResponse
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
 		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
 			return nil, err
 		}
-		response.JSON200 = &dest
+		response.JSONDefault = &dest
 
-	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
-		var dest DelSDFunctionResponse
+	}
+
+	return response, nil
+}
+
+// ParseGetCapabilitiesResponse parses an HTTP response from a GetCapabilitiesWithResponse call
+func ParseGetCapabilitiesResponse(rsp *http.Response) (*GetCapabilitiesResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetCapabilitiesResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest SdCapabilities
 		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
 			return nil, err
 		}
-		response.JSON500 = &dest
+		response.JSON200 = &dest
 
 	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
 		var dest Error
@@ -1897,22 +6525,22 @@ func ParseDelSDFuncResponse(rsp *http.Response) (*DelSDFuncResponse, error) {
 	return response, nil
 }
 
-// ParseExtraImagesResponse parses an HTTP response from a ExtraImagesWithResponse call
-func ParseExtraImagesResponse(rsp *http.Response) (*ExtraImagesResponse, error) {
+// ParseGetSharedTaskResultResponse parses an HTTP response from a GetSharedTaskResultWithResponse call
+func ParseGetSharedTaskResultResponse(rsp *http.Response) (*GetSharedTaskResultResponse, error) {
 	bodyBytes, err := io.ReadAll(rsp.Body)
 	defer func() { _ = rsp.Body.Close() }()
 	if err != nil {
 		return nil, err
 	}
 
-	response := &ExtraImagesResponse{
+	response := &GetSharedTaskResultResponse{
 		Body:         bodyBytes,
 		HTTPResponse: rsp,
 	}
 
 	switch {
 	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
-		var dest SubmitTaskResponse
+		var dest TaskResultResponse
 		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
 			return nil, err
 		}
@@ -1930,22 +6558,22 @@ func ParseExtraImagesResponse(rsp *http.Response) (*ExtraImagesResponse, error)
 	return response, nil
 }
 
-// ParseImg2ImgResponse parses an HTTP response from a Img2ImgWithResponse call
-func ParseImg2ImgResponse(rsp *http.Response) (*Img2ImgResponse, error) {
+// ParseListTasksResponse parses an HTTP response from a ListTasksWithResponse call
+func ParseListTasksResponse(rsp *http.Response) (*ListTasksResponse, error) {
 	bodyBytes, err := io.ReadAll(rsp.Body)
 	defer func() { _ = rsp.Body.Close() }()
 	if err != nil {
 		return nil, err
 	}
 
-	response := &Img2ImgResponse{
+	response := &ListTasksResponse{
 		Body:         bodyBytes,
 		HTTPResponse: rsp,
 	}
 
 	switch {
 	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
-		var dest SubmitTaskResponse
+		var dest TaskList
 		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
 			return nil, err
 		}
@@ -1963,34 +6591,27 @@ func ParseImg2ImgResponse(rsp *http.Response) (*Img2ImgResponse, error) {
 	return response, nil
 }
 
-// ParseListSdFuncResponse parses an HTTP response from a ListSdFuncWithResponse call
-func ParseListSdFuncResponse(rsp *http.Response) (*ListSdFuncResponse, error) {
+// ParseGetTaskResultsResponse parses an HTTP response from a GetTaskResultsWithResponse call
+func ParseGetTaskResultsResponse(rsp *http.Response) (*GetTaskResultsResponse, error) {
 	bodyBytes, err := io.ReadAll(rsp.Body)
 	defer func() { _ = rsp.Body.Close() }()
 	if err != nil {
 		return nil, err
 	}
 
-	response := &ListSdFuncResponse{
+	response := &GetTaskResultsResponse{
 		Body:         bodyBytes,
 		HTTPResponse: rsp,
 	}
 
 	switch {
 	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
-		var dest ListSDFunctionResponse
+		var dest TaskResultsResult
 		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
 			return nil, err
 		}
 		response.JSON200 = &dest
 
-	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
-		var dest ListSDFunctionResponse
-		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
-			return nil, err
-		}
-		response.JSON500 = &dest
-
 	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
 		var dest Error
 		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
@@ -2003,27 +6624,20 @@ func ParseListSdFuncResponse(rsp *http.Response) (*ListSdFuncResponse, error) {
 	return response, nil
 }
 
-// ParseLoginResponse parses an HTTP response from a LoginWithResponse call
-func ParseLoginResponse(rsp *http.Response) (*LoginResponse, error) {
+// ParseCancelTaskResponse parses an HTTP response from a CancelTaskWithResponse call
+func ParseCancelTaskResponse(rsp *http.Response) (*CancelTaskResponse, error) {
 	bodyBytes, err := io.ReadAll(rsp.Body)
 	defer func() { _ = rsp.Body.Close() }()
 	if err != nil {
 		return nil, err
 	}
 
-	response := &LoginResponse{
+	response := &CancelTaskResponse{
 		Body:         bodyBytes,
 		HTTPResponse: rsp,
 	}
 
 	switch {
-	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
-		var dest UserLoginResponse
-		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
-			return nil, err
-		}
-		response.JSON200 = &dest
-
 	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
 		var dest Error
 		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
@@ -2036,22 +6650,22 @@ func ParseLoginResponse(rsp *http.Response) (*LoginResponse, error) {
 	return response, nil
 }
 
-// ParseListModelsResponse parses an HTTP response from a ListModelsWithResponse call
-func ParseListModelsResponse(rsp *http.Response) (*ListModelsResponse, error) {
+// ParseGetTaskProgressResponse parses an HTTP response from a GetTaskProgressWithResponse call
+func ParseGetTaskProgressResponse(rsp *http.Response) (*GetTaskProgressResponse, error) {
 	bodyBytes, err := io.ReadAll(rsp.Body)
 	defer func() { _ = rsp.Body.Close() }()
 	if err != nil {
 		return nil, err
 	}
 
-	response := &ListModelsResponse{
+	response := &GetTaskProgressResponse{
 		Body:         bodyBytes,
 		HTTPResponse: rsp,
 	}
 
 	switch {
 	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
-		var dest []ModelAttributes
+		var dest TaskProgressResponse
 		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
 			return nil, err
 		}
@@ -2069,20 +6683,27 @@ func ParseListModelsResponse(rsp *http.Response) (*ListModelsResponse, error) {
 	return response, nil
 }
 
-// ParseRegisterModelResponse parses an HTTP response from a RegisterModelWithResponse call
-func ParseRegisterModelResponse(rsp *http.Response) (*RegisterModelResponse, error) {
+// ParseGetTaskReproduceResponse parses an HTTP response from a GetTaskReproduceWithResponse call
+func ParseGetTaskReproduceResponse(rsp *http.Response) (*GetTaskReproduceResponse, error) {
 	bodyBytes, err := io.ReadAll(rsp.Body)
 	defer func() { _ = rsp.Body.Close() }()
 	if err != nil {
 		return nil, err
 	}
 
-	response := &RegisterModelResponse{
+	response := &GetTaskReproduceResponse{
 		Body:         bodyBytes,
 		HTTPResponse: rsp,
 	}
 
 	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest TaskReproduceResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
 	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
 		var dest Error
 		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
@@ -2095,20 +6716,27 @@ func ParseRegisterModelResponse(rsp *http.Response) (*RegisterModelResponse, err
 	return response, nil
 }
 
-// ParseDeleteModelResponse parses an HTTP response from a DeleteModelWithResponse call
-func ParseDeleteModelResponse(rsp *http.Response) (*DeleteModelResponse, error) {
+// ParseGetTaskResultResponse parses an HTTP response from a GetTaskResultWithResponse call
+func ParseGetTaskResultResponse(rsp *http.Response) (*GetTaskResultResponse, error) {
 	bodyBytes, err := io.ReadAll(rsp.Body)
 	defer func() { _ = rsp.Body.Close() }()
 	if err != nil {
 		return nil, err
 	}
 
-	response := &DeleteModelResponse{
+	response := &GetTaskResultResponse{
 		Body:         bodyBytes,
 		HTTPResponse: rsp,
 	}
 
 	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest TaskResultResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
 	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
 		var dest Error
 		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
@@ -2121,22 +6749,22 @@ func ParseDeleteModelResponse(rsp *http.Response) (*DeleteModelResponse, error)
 	return response, nil
 }
 
-// ParseGetModelResponse parses an HTTP response from a GetModelWithResponse call
-func ParseGetModelResponse(rsp *http.Response) (*GetModelResponse, error) {
+// ParseShareTaskResponse parses an HTTP response from a ShareTaskWithResponse call
+func ParseShareTaskResponse(rsp *http.Response) (*ShareTaskResponse, error) {
 	bodyBytes, err := io.ReadAll(rsp.Body)
 	defer func() { _ = rsp.Body.Close() }()
 	if err != nil {
 		return nil, err
 	}
 
-	response := &GetModelResponse{
+	response := &ShareTaskResponse{
 		Body:         bodyBytes,
 		HTTPResponse: rsp,
 	}
 
 	switch {
 	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
-		var dest ModelAttributes
+		var dest ShareLink
 		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
 			return nil, err
 		}
@@ -2154,20 +6782,27 @@ func ParseGetModelResponse(rsp *http.Response) (*GetModelResponse, error) {
 	return response, nil
 }
 
-// ParseUpdateModelResponse parses an HTTP response from a UpdateModelWithResponse call
-func ParseUpdateModelResponse(rsp *http.Response) (*UpdateModelResponse, error) {
+// ParseTxt2ImgResponse parses an HTTP response from a Txt2ImgWithResponse call
+func ParseTxt2ImgResponse(rsp *http.Response) (*Txt2ImgResponse, error) {
 	bodyBytes, err := io.ReadAll(rsp.Body)
 	defer func() { _ = rsp.Body.Close() }()
 	if err != nil {
 		return nil, err
 	}
 
-	response := &UpdateModelResponse{
+	response := &Txt2ImgResponse{
 		Body:         bodyBytes,
 		HTTPResponse: rsp,
 	}
 
 	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest SubmitTaskResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
 	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
 		var dest Error
 		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
@@ -2180,22 +6815,22 @@ func ParseUpdateModelResponse(rsp *http.Response) (*UpdateModelResponse, error)
 	return response, nil
 }
 
-// ParseUpdateOptionsResponse parses an HTTP response from a UpdateOptionsWithResponse call
-func ParseUpdateOptionsResponse(rsp *http.Response) (*UpdateOptionsResponse, error) {
+// ParseTxt2ImgCachedResponse parses an HTTP response from a Txt2ImgCachedWithResponse call
+func ParseTxt2ImgCachedResponse(rsp *http.Response) (*Txt2ImgCachedResponse, error) {
 	bodyBytes, err := io.ReadAll(rsp.Body)
 	defer func() { _ = rsp.Body.Close() }()
 	if err != nil {
 		return nil, err
 	}
 
-	response := &UpdateOptionsResponse{
+	response := &Txt2ImgCachedResponse{
 		Body:         bodyBytes,
 		HTTPResponse: rsp,
 	}
 
 	switch {
 	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
-		var dest ResponseMessage
+		var dest SubmitTaskResponse
 		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
 			return nil, err
 		}
@@ -2213,20 +6848,27 @@ func ParseUpdateOptionsResponse(rsp *http.Response) (*UpdateOptionsResponse, err
 	return response, nil
 }
 
-// ParseRestartResponse parses an HTTP response from a RestartWithResponse call
-func ParseRestartResponse(rsp *http.Response) (*RestartResponse, error) {
+// ParseTxt2ImgMultiResponse parses an HTTP response from a Txt2ImgMultiWithResponse call
+func ParseTxt2ImgMultiResponse(rsp *http.Response) (*Txt2ImgMultiResponse, error) {
 	bodyBytes, err := io.ReadAll(rsp.Body)
 	defer func() { _ = rsp.Body.Close() }()
 	if err != nil {
 		return nil, err
 	}
 
-	response := &RestartResponse{
+	response := &Txt2ImgMultiResponse{
 		Body:         bodyBytes,
 		HTTPResponse: rsp,
 	}
 
 	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest Txt2ImgMultiResult
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
 	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
 		var dest Error
 		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
@@ -2239,20 +6881,27 @@ func ParseRestartResponse(rsp *http.Response) (*RestartResponse, error) {
 	return response, nil
 }
 
-// ParseCancelTaskResponse parses an HTTP response from a CancelTaskWithResponse call
-func ParseCancelTaskResponse(rsp *http.Response) (*CancelTaskResponse, error) {
+// ParseDeleteUserImagesResponse parses an HTTP response from a DeleteUserImagesWithResponse call
+func ParseDeleteUserImagesResponse(rsp *http.Response) (*DeleteUserImagesResponse, error) {
 	bodyBytes, err := io.ReadAll(rsp.Body)
 	defer func() { _ = rsp.Body.Close() }()
 	if err != nil {
 		return nil, err
 	}
 
-	response := &CancelTaskResponse{
+	response := &DeleteUserImagesResponse{
 		Body:         bodyBytes,
 		HTTPResponse: rsp,
 	}
 
 	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest DeleteUserImagesResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
 	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
 		var dest Error
 		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
@@ -2265,22 +6914,22 @@ func ParseCancelTaskResponse(rsp *http.Response) (*CancelTaskResponse, error) {
 	return response, nil
 }
 
-// ParseGetTaskProgressResponse parses an HTTP response from a GetTaskProgressWithResponse call
-func ParseGetTaskProgressResponse(rsp *http.Response) (*GetTaskProgressResponse, error) {
+// ParseListUserImagesResponse parses an HTTP response from a ListUserImagesWithResponse call
+func ParseListUserImagesResponse(rsp *http.Response) (*ListUserImagesResponse, error) {
 	bodyBytes, err := io.ReadAll(rsp.Body)
 	defer func() { _ = rsp.Body.Close() }()
 	if err != nil {
 		return nil, err
 	}
 
-	response := &GetTaskProgressResponse{
+	response := &ListUserImagesResponse{
 		Body:         bodyBytes,
 		HTTPResponse: rsp,
 	}
 
 	switch {
 	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
-		var dest TaskProgressResponse
+		var dest UserImageList
 		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
 			return nil, err
 		}
@@ -2298,22 +6947,22 @@ func ParseGetTaskProgressResponse(rsp *http.Response) (*GetTaskProgressResponse,
 	return response, nil
 }
 
-// ParseGetTaskResultResponse parses an HTTP response from a GetTaskResultWithResponse call
-func ParseGetTaskResultResponse(rsp *http.Response) (*GetTaskResultResponse, error) {
+// ParseGetUserStatsResponse parses an HTTP response from a GetUserStatsWithResponse call
+func ParseGetUserStatsResponse(rsp *http.Response) (*GetUserStatsResponse, error) {
 	bodyBytes, err := io.ReadAll(rsp.Body)
 	defer func() { _ = rsp.Body.Close() }()
 	if err != nil {
 		return nil, err
 	}
 
-	response := &GetTaskResultResponse{
+	response := &GetUserStatsResponse{
 		Body:         bodyBytes,
 		HTTPResponse: rsp,
 	}
 
 	switch {
 	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
-		var dest TaskResultResponse
+		var dest UserStats
 		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
 			return nil, err
 		}
@@ -2331,22 +6980,22 @@ func ParseGetTaskResultResponse(rsp *http.Response) (*GetTaskResultResponse, err
 	return response, nil
 }
 
-// ParseTxt2ImgResponse parses an HTTP response from a Txt2ImgWithResponse call
-func ParseTxt2ImgResponse(rsp *http.Response) (*Txt2ImgResponse, error) {
+// ParseGetVersionResponse parses an HTTP response from a GetVersionWithResponse call
+func ParseGetVersionResponse(rsp *http.Response) (*GetVersionResponse, error) {
 	bodyBytes, err := io.ReadAll(rsp.Body)
 	defer func() { _ = rsp.Body.Close() }()
 	if err != nil {
 		return nil, err
 	}
 
-	response := &Txt2ImgResponse{
+	response := &GetVersionResponse{
 		Body:         bodyBytes,
 		HTTPResponse: rsp,
 	}
 
 	switch {
 	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
-		var dest SubmitTaskResponse
+		var dest VersionInfo
 		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
 			return nil, err
 		}
@@ -1,25 +1,61 @@
 package client
 
-import "sync"
+import (
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
 
 var ManagerClientGlobal *ManagerClient = NewManagerClient()
 
 type ManagerClient struct {
 	clients *sync.Map
+	// endpoint -> *breakerDoer, empty when breaker disabled
+	breakers *sync.Map
+	// consecutive failures open breaker, <=0 disable
+	breakerThreshold int
+	breakerCooldown  time.Duration
 }
 
 func NewManagerClient() *ManagerClient {
 	return &ManagerClient{
-		clients: new(sync.Map),
+		clients:  new(sync.Map),
+		breakers: new(sync.Map),
 	}
 }
 
+// EnableBreaker circuit breaker per endpoint for clients created later, threshold <= 0 disable
+func (c *ManagerClient) EnableBreaker(threshold int, cooldown time.Duration) {
+	c.breakerThreshold = threshold
+	c.breakerCooldown = cooldown
+}
+
 func (c *ManagerClient) GetClient(endPoint string) *Client {
 	val, existed := c.clients.Load(endPoint)
 	if existed {
 		return val.(*Client)
 	}
-	client, _ := NewClient(endPoint)
+	var opts []ClientOption
+	if c.breakerThreshold > 0 {
+		breaker, _ := c.breakers.LoadOrStore(endPoint, newBreakerDoer(&http.Client{}, endPoint,
+			c.breakerThreshold, c.breakerCooldown))
+		opts = append(opts, WithHTTPClient(breaker.(*breakerDoer)))
+	}
+	client, _ := NewClient(endPoint, opts...)
 	val, _ = c.clients.LoadOrStore(endPoint, client)
 	return val.(*Client)
 }
+
+// BreakerStatus circuit breaker of endpoints, sort by endpoint
+func (c *ManagerClient) BreakerStatus() []BreakerStatus {
+	status := make([]BreakerStatus, 0)
+	c.breakers.Range(func(key, value any) bool {
+		status = append(status, value.(*breakerDoer).status())
+		return true
+	})
+	sort.Slice(status, func(i, j int) bool {
+		return status[i].Endpoint < status[j].Endpoint
+	})
+	return status
+}
@@ -0,0 +1,96 @@
+package concurrency
+
+import (
+	"sync"
+	"time"
+)
+
+const budgetWaitInterval = 100 * time.Millisecond
+
+// TokenBucket limit events count per window, refill continuously
+// capacity <= 0 means no limit
+type TokenBucket struct {
+	lock     sync.Mutex
+	capacity float64
+	tokens   float64
+	rate     float64 // tokens per second
+	window   time.Duration
+	last     time.Time
+}
+
+// BudgetStatus token bucket current status
+type BudgetStatus struct {
+	Capacity  int
+	Available int
+	Window    time.Duration
+}
+
+func NewTokenBucket(capacity int, window time.Duration) *TokenBucket {
+	bucket := &TokenBucket{
+		capacity: float64(capacity),
+		tokens:   float64(capacity),
+		window:   window,
+		last:     time.Now(),
+	}
+	if capacity > 0 && window > 0 {
+		bucket.rate = float64(capacity) / window.Seconds()
+	}
+	return bucket
+}
+
+func (t *TokenBucket) unlimited() bool {
+	return t.capacity <= 0
+}
+
+// refill by elapsed time, need lock
+func (t *TokenBucket) refill(now time.Time) {
+	t.tokens += now.Sub(t.last).Seconds() * t.rate
+	if t.tokens > t.capacity {
+		t.tokens = t.capacity
+	}
+	t.last = now
+}
+
+// TryTake take one token, return false if exhausted
+func (t *TokenBucket) TryTake() bool {
+	if t.unlimited() {
+		return true
+	}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.refill(time.Now())
+	if t.tokens < 1 {
+		return false
+	}
+	t.tokens--
+	return true
+}
+
+// Wait take one token, wait at most timeout
+func (t *TokenBucket) Wait(timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for {
+		if t.TryTake() {
+			return true
+		}
+		if time.Now().Add(budgetWaitInterval).After(deadline) {
+			return false
+		}
+		time.Sleep(budgetWaitInterval)
+	}
+}
+
+// Status current capacity and available tokens
+func (t *TokenBucket) Status() BudgetStatus {
+	if t.unlimited() {
+		return BudgetStatus{Capacity: 0, Available: 0, Window: t.window}
+	}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.refill(time.Now())
+	return BudgetStatus{
+		Capacity:  int(t.capacity),
+		Available: int(t.tokens),
+		Window:    t.window,
+	}
+}
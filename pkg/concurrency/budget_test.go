@@ -0,0 +1,27 @@
+package concurrency
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenBucket(t *testing.T) {
+	bucket := NewTokenBucket(2, time.Second)
+	assert.True(t, bucket.TryTake())
+	assert.True(t, bucket.TryTake())
+	assert.False(t, bucket.TryTake())
+	assert.Equal(t, 0, bucket.Status().Available)
+
+	// refill 1 token per 500ms
+	assert.True(t, bucket.Wait(time.Second))
+	assert.False(t, bucket.Wait(0))
+
+	// no limit
+	bucket = NewTokenBucket(0, time.Minute)
+	for i := 0; i < 100; i++ {
+		assert.True(t, bucket.TryTake())
+	}
+	assert.Equal(t, 0, bucket.Status().Capacity)
+}
@@ -6,9 +6,14 @@ import (
 	"github.com/sirupsen/logrus"
 	"gopkg.in/yaml.v2"
 	"io/ioutil"
+	"net/http"
+	"net/url"
 	"os"
+	"path/filepath"
+	"regexp"
 	"strconv"
 	"strings"
+	"time"
 )
 
 var ConfigGlobal *Config
@@ -24,12 +29,39 @@ type ConfigYaml struct {
 	Bucket      string `yaml:"bucket"`
 	OssPath     string `yaml:"ossPath""`
 	OssMode     string `yaml:"ossMode"`
+	// read models from modelBucket, write output images to outputBucket, default bucket
+	ModelBucket  string `yaml:"modelBucket"`
+	OutputBucket string `yaml:"outputBucket"`
+	// image upload concurrency and multipart upload threshold (MB)
+	OssUploadConcurrency  int   `yaml:"ossUploadConcurrency"`
+	OssMultipartThreshold int64 `yaml:"ossMultipartThreshold"`
+	// attempts of oss request when transient error, default 3, 1 no retry
+	OssRetryAttempts int `yaml:"ossRetryAttempts"`
+	// output image key ext and content type follow real image format, default true
+	DetectImageType *bool `yaml:"detectImageType"`
+	// lossless compression of png before upload to oss, none(default)|png, level fast|default|best(default)
+	ImageCompression      string `yaml:"imageCompression"`
+	ImageCompressionLevel string `yaml:"imageCompressionLevel"`
+	// img2img init/mask oss images passed to webui as base64(default) or signed url loaded by webui itself,
+	// url fall back to base64 when oss not support url
+	InitImageSource string `yaml:"initImageSource"`
+	// inline images as data uri in response when requested and total size (KB) not exceed, default 256
+	InlineImageMaxSize int64 `yaml:"inlineImageMaxSize"`
+
+	// mns publish task event when task finished, failed or cancelled, only one of topic/queue
+	// endpoint default https://{accountId}.mns.{region}.aliyuncs.com
+	MnsEndpoint string `yaml:"mnsEndpoint"`
+	MnsTopic    string `yaml:"mnsTopic"`
+	MnsQueue    string `yaml:"mnsQueue"`
 
 	// db
 	DbSqlite string `yaml:"dbSqlite"`
 
 	// listen
 	ListenInterval int32 `yaml:"listenInterval"`
+	// adaptive poll bounds(ms): min when tasks pending, back off to max when idle
+	ListenMinInterval int32 `yaml:"listenMinInterval"`
+	ListenMaxInterval int32 `yaml:"listenMaxInterval"`
 
 	// function
 	Image               string  `yaml:"image"`
@@ -42,6 +74,44 @@ type ConfigYaml struct {
 	MemorySize          int32   `yaml:"memorySize"`
 	InstanceConcurrency int32   `yaml:"instanceConcurrency"`
 	InstanceType        string  `yaml:"instanceType"`
+	// try in order when instanceType capacity not enough
+	InstanceTypeFallbacks []string `yaml:"instanceTypeFallbacks"`
+	// max function creations per window(s) across all models, 0 means no limit
+	ColdStartBudget       int `yaml:"coldStartBudget"`
+	ColdStartBudgetWindow int `yaml:"coldStartBudgetWindow"`
+	// sd predict request timeout(s), default function timeout
+	PredictTimeout int32 `yaml:"predictTimeout"`
+	// max concurrent sd predict of proxy direct call, excess wait predictQueueTimeout(s), 0 means no limit
+	PredictConcurrency  int `yaml:"predictConcurrency"`
+	PredictQueueTimeout int `yaml:"predictQueueTimeout"`
+	// max concurrent oss image downloads(to base64) across all requests, excess wait ossDownloadTimeout(s)
+	// 0 means no limit
+	OssDownloadConcurrency int `yaml:"ossDownloadConcurrency"`
+	OssDownloadTimeout     int `yaml:"ossDownloadTimeout"`
+	// max pending tasks (queued or rendering, async counted until terminal) across all models and per model,
+	// excess reject 429, 0 means no limit
+	MaxQueueLength      int            `yaml:"maxQueueLength"`
+	ModelMaxQueueLength map[string]int `yaml:"modelMaxQueueLength"`
+	// max unfinished tasks (queued or rendering, async counted until terminal) of each user across proxies,
+	// user setting cover maxUserTasks, excess reject 429, 0 means no limit
+	MaxUserTasks int            `yaml:"maxUserTasks"`
+	UserMaxTasks map[string]int `yaml:"userMaxTasks"`
+	// downstream sd endpoint circuit open after breakerThreshold consecutive failures for breakerCooldown(s)
+	// <=0 disable
+	BreakerThreshold int `yaml:"breakerThreshold"`
+	BreakerCooldown  int `yaml:"breakerCooldown"`
+	// sd model -> min warm instances keep alive by control, probe every warmPoolInterval(s)
+	WarmPool         map[string]int `yaml:"warmPool"`
+	WarmPoolInterval int            `yaml:"warmPoolInterval"`
+	// sd model -> extra args merged into extraArgs of model function, only modelExtraArgsAllowed flags
+	ModelExtraArgs map[string]string `yaml:"modelExtraArgs"`
+	// sd model -> function timeout(s) cover timeout when model function created or resource updated without timeout
+	ModelTimeouts map[string]int32 `yaml:"modelTimeouts"`
+	// control refresh function endpoints from db every funcRefreshInterval(s), <0 disable
+	FuncRefreshInterval int `yaml:"funcRefreshInterval"`
+	// custom container web server mode, default true, and image acceleration type: Default|None
+	WebServerMode    *bool  `yaml:"webServerMode"`
+	AccelerationType string `yaml:"accelerationType"`
 
 	// user
 	SessionExpire             int64  `yaml:"sessionExpire"`
@@ -52,9 +122,65 @@ type ConfigYaml struct {
 	SdUrlPrefix string `yaml:"sdUrlPrefix"`
 	SdPath      string `yaml:"sdPath"`
 	SdShell     string `yaml:"sdShell"`
+	// webui api credentials user:pass, webui started with --api-auth and sd requests carry basic auth, empty off
+	SdApiAuth string `yaml:"sdApiAuth"`
+	// model type -> model dir relative to sdPath
+	ModelDirs map[string]string `yaml:"modelDirs"`
+	// agent exit on startup and refuse to restart webui when model of env SD_MODEL not found under sdPath,
+	// default only log error
+	FailOnMissingSdModel bool `yaml:"failOnMissingSdModel"`
 
 	// model
 	UseLocalModels string `yaml:"useLocalModel"`
+	// sd model used when request not set
+	DefaultModel string `yaml:"defaultModel"`
+	// sd model render queued task whose model deleted before predict, empty fail fast
+	DeletedModelFallback string `yaml:"deletedModelFallback"`
+	// multiFunc proxy register sd model missing on nas from oss {modelAutoRegisterOssDir}/{model name} before
+	// render, request wait at most modelAutoRegisterTimeout(s) then 503 while download keep running
+	ModelAutoRegister        bool   `yaml:"modelAutoRegister"`
+	ModelAutoRegisterOssDir  string `yaml:"modelAutoRegisterOssDir"`
+	ModelAutoRegisterTimeout int    `yaml:"modelAutoRegisterTimeout"`
+	// identical render result reuse ttl(s), request opt in, /txt2img/cached too
+	RenderCacheTTL int `yaml:"renderCacheTTL"`
+	// default window(days) of GET /users/{user}/stats
+	UserStatsDays int `yaml:"userStatsDays"`
+	// hmac key of task result share link, empty disable share, default link valid shareLinkTTL(s)
+	ShareLinkSecret string `yaml:"shareLinkSecret"`
+	ShareLinkTTL    int    `yaml:"shareLinkTTL"`
+	// days keep images of terminal tasks in oss / task rows, 0 keep forever, janitor purge every
+	// retentionInterval(s) on one instance holding lease, row deleted with its images, row kept after images purged,
+	// images shared by render cache copies purged after last reference expired
+	ImageRetentionDays int `yaml:"imageRetentionDays"`
+	TaskRetentionDays  int `yaml:"taskRetentionDays"`
+	RetentionInterval  int `yaml:"retentionInterval"`
+	// output image oss key, placeholder: {user} {taskId} {index} {seed} {date} {timestamp}
+	ImageNameTemplate string `yaml:"imageNameTemplate"`
+	// user -> allowed request output_prefix, "*" for all users, placeholder: {user}
+	OutputPrefixes map[string][]string `yaml:"outputPrefixes"`
+	// user -> embedding models append to negative prompt, "*" for users not set
+	DefaultNegativeEmbeddings map[string][]string `yaml:"defaultNegativeEmbeddings"`
+	// sd model -> default request params, admin api update cover it
+	ModelDefaults map[string]map[string]interface{} `yaml:"modelDefaults"`
+	// preset name -> width/height/batch_size filled into predict request select it, request params cover preset
+	DimensionPresets map[string]*DimensionPreset `yaml:"dimensionPresets"`
+	// user -> soft limit of predict params, user setting cover "*"
+	RequestLimits map[string]*RequestLimit `yaml:"requestLimits"`
+	// sd model keyword -> vram estimate coefficient, "*" for others, reject request estimate exceed
+	// gpu memory of target instance, not set no guard
+	GpuMemoryGuard map[string]*GpuMemoryCoefficient `yaml:"gpuMemoryGuard"`
+	// extension route path -> model field of json body, dot path and array index like args.0.model,
+	// path end with * match prefix, unmapped route use StableDiffusionModel field then defaultModel
+	RouteModelFields map[string]string `yaml:"routeModelFields"`
+	// request headers forwarded by passthrough routes(webui api, extensions), not set strip credentials only
+	ForwardHeaders *ForwardHeaders `yaml:"forwardHeaders"`
+	// upload kind(image|model) -> accepted mime types and extensions, not set accept all
+	UploadAllowLists map[string]*UploadAllowList `yaml:"uploadAllowLists"`
+	// known override_settings keys of user config(POST /options), unknown key dropped or task rejected,
+	// not set merge all keys
+	OverrideSettingsKeys *OverrideSettingsKeys `yaml:"overrideSettingsKeys"`
+	// check prompt syntax before task queued, default false since extensions may use custom syntax
+	ValidatePrompt bool `yaml:"validatePrompt"`
 
 	// flex mode
 	FlexMode string `yaml:"flexMode"`
@@ -63,10 +189,87 @@ type ConfigYaml struct {
 	LogRemoteService   string `yaml:"logRemoteService"`
 	EnableCollect      string `yaml:"enableCollect"`
 	DisableHealthCheck string `yaml:"disableHealthCheck"`
+	// remote log batch size, flush interval(s), queue size
+	LogBatchSize     int `yaml:"logBatchSize"`
+	LogFlushInterval int `yaml:"logFlushInterval"`
+	LogQueueSize     int `yaml:"logQueueSize"`
 
 	// proxy or control or agent
 	ServerName string `yaml:"serverName"`
 	Downstream string `yaml:"downstream"`
+	// route to it directly when control routing fail or control downstream unreachable, empty disable
+	RoutingFallbackEndpoint string `yaml:"routingFallbackEndpoint"`
+
+	// request body limit (MB), <0 means no limit
+	MaxRequestBodySize int64 `yaml:"maxRequestBodySize"`
+	// maintenance mode auto disable after seconds, 0 means never
+	MaintenanceMaxDuration int `yaml:"maintenanceMaxDuration"`
+}
+
+// RequestLimit soft limit of txt2img/img2img params, 0 no limit
+type RequestLimit struct {
+	MaxSteps    int64   `yaml:"maxSteps"`
+	MaxCfgScale float32 `yaml:"maxCfgScale"`
+	// clamp(default) param to limit or reject request exceed limit
+	Mode string `yaml:"mode"`
+}
+
+// IsReject reject request exceed limit instead of clamp
+func (l *RequestLimit) IsReject() bool {
+	return l.Mode == RequestLimitReject
+}
+
+// DimensionPreset output size of predict request, 0 not fill
+type DimensionPreset struct {
+	Width     int64 `yaml:"width"`
+	Height    int64 `yaml:"height"`
+	BatchSize int64 `yaml:"batchSize"`
+}
+
+// GpuMemoryCoefficient vram estimate(MB) = baseMB + perMegapixelMB * width * height * batch_size / 1e6
+type GpuMemoryCoefficient struct {
+	// model weights and runtime
+	BaseMB         float64 `yaml:"baseMB"`
+	PerMegapixelMB float64 `yaml:"perMegapixelMB"`
+}
+
+// ForwardHeaders header forward policy of passthrough routes, deny cover allow
+type ForwardHeaders struct {
+	// only these and proxy internal headers forwarded, empty forward all not denied
+	Allow []string `yaml:"allow"`
+	// never forwarded, not set Token, Cookie, Authorization, Proxy-Authorization
+	Deny []string `yaml:"deny"`
+}
+
+// UploadAllowList accepted upload types, empty list not check
+type UploadAllowList struct {
+	// sniffed mime type of content, like image/png
+	MimeTypes []string `yaml:"mimeTypes"`
+	// file name ext with dot, like .png, case insensitive
+	Extensions []string `yaml:"extensions"`
+}
+
+// OverrideSettingsKeys allow list of user config keys merged to override_settings
+type OverrideSettingsKeys struct {
+	// webui option names, like CLIP_stop_at_last_layers
+	Known []string `yaml:"known"`
+	// drop(default) unknown key with warn log or reject task
+	Mode string `yaml:"mode"`
+}
+
+// IsReject reject task with unknown key instead of drop
+func (k *OverrideSettingsKeys) IsReject() bool {
+	return k.Mode == UnknownSettingReject
+}
+
+// IsKnown key in allow list
+func (k *OverrideSettingsKeys) IsKnown(key string) bool {
+	for _, known := range k.Known {
+		if key == known {
+			return true
+		}
+	}
+	return false
 }
 
 type ConfigEnv struct {
@@ -122,17 +325,376 @@ func (c *Config) DisableProgress() bool {
 	return os.Getenv("DISABLE_PROGRESS") != ""
 }
 
+// GetModelDir return the abs dir of modelType models, empty if modelType not support
+func (c *Config) GetModelDir(modelType string) string {
+	dir, ok := c.ModelDirs[modelType]
+	if !ok {
+		return ""
+	}
+	return filepath.Join(c.SdPath, dir)
+}
+
+// IsWebServerMode custom container run as web server, default true
+func (c *Config) IsWebServerMode() bool {
+	return c.WebServerMode == nil || *c.WebServerMode
+}
+
+// GetInstanceTypes instanceType first, then fallbacks, no duplicate
+func (c *Config) GetInstanceTypes() []string {
+	instanceTypes := make([]string, 0, len(c.InstanceTypeFallbacks)+1)
+	exist := make(map[string]struct{})
+	for _, instanceType := range append([]string{c.InstanceType}, c.InstanceTypeFallbacks...) {
+		instanceType = strings.TrimSpace(instanceType)
+		if _, ok := exist[instanceType]; ok || instanceType == "" {
+			continue
+		}
+		exist[instanceType] = struct{}{}
+		instanceTypes = append(instanceTypes, instanceType)
+	}
+	return instanceTypes
+}
+
+// GetPredictTimeout sd predict request timeout, default function timeout
+func (c *Config) GetPredictTimeout() time.Duration {
+	timeout := c.PredictTimeout
+	if timeout <= 0 {
+		timeout = c.Timeout
+	}
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return time.Duration(timeout) * time.Second
+}
+
+// GetPredictQueueTimeout max wait for predict slot when predictConcurrency set
+func (c *Config) GetPredictQueueTimeout() time.Duration {
+	return time.Duration(c.PredictQueueTimeout) * time.Second
+}
+
+// GetOssDownloadTimeout max wait for oss download slot when ossDownloadConcurrency set
+func (c *Config) GetOssDownloadTimeout() time.Duration {
+	return time.Duration(c.OssDownloadTimeout) * time.Second
+}
+
+// GetModelAutoRegisterTimeout max wait of request for sd model auto register
+func (c *Config) GetModelAutoRegisterTimeout() time.Duration {
+	return time.Duration(c.ModelAutoRegisterTimeout) * time.Second
+}
+
+// GetMaxQueueLength max pending tasks of sd model, 0 means no limit
+func (c *Config) GetMaxQueueLength(sdModel string) int {
+	return c.ModelMaxQueueLength[sdModel]
+}
+
+// GetBreakerCooldown fast fail duration of open circuit
+func (c *Config) GetBreakerCooldown() time.Duration {
+	return time.Duration(c.BreakerCooldown) * time.Second
+}
+
+// IsDetectImageType detect output image format from bytes, default true
+func (c *Config) IsDetectImageType() bool {
+	return c.DetectImageType == nil || *c.DetectImageType
+}
+
+// EnableRetention janitor purge expired images or task rows
+func (c *Config) EnableRetention() bool {
+	return c.ImageRetentionDays > 0 || c.TaskRetentionDays > 0
+}
+
+// IsInitImageUrl pass img2img oss images to webui as signed url
+func (c *Config) IsInitImageUrl() bool {
+	return c.InitImageSource == InitImageUrl
+}
+
+// IsImageCompression compress png images before upload
+func (c *Config) IsImageCompression() bool {
+	return c.ImageCompression == ImageCompressionPng
+}
+
+// GetMnsEndpoint mns endpoint, default public endpoint of account region
+func (c *Config) GetMnsEndpoint() string {
+	if c.MnsEndpoint != "" {
+		return strings.TrimSuffix(c.MnsEndpoint, "/")
+	}
+	return fmt.Sprintf("https://%s.mns.%s.aliyuncs.com", c.AccountId, c.Region)
+}
+
+// GetOutputPrefixes allowed output prefixes of user, {user} replaced
+func (c *Config) GetOutputPrefixes(user string) []string {
+	prefixes := make([]string, 0, len(c.OutputPrefixes[user])+len(c.OutputPrefixes[AllUsers]))
+	for _, prefix := range append(c.OutputPrefixes[user], c.OutputPrefixes[AllUsers]...) {
+		prefixes = append(prefixes, strings.ReplaceAll(prefix, "{user}", user))
+	}
+	return prefixes
+}
+
+// GetMaxUserTasks max unfinished tasks of user, user setting cover maxUserTasks, 0 means no limit
+func (c *Config) GetMaxUserTasks(user string) int {
+	if max, ok := c.UserMaxTasks[user]; ok {
+		return max
+	}
+	return c.MaxUserTasks
+}
+
+// GetRequestLimit predict params limit of user, user setting cover "*", nil no limit
+func (c *Config) GetRequestLimit(user string) *RequestLimit {
+	if limit, ok := c.RequestLimits[user]; ok {
+		return limit
+	}
+	return c.RequestLimits[AllUsers]
+}
+
+// GetRouteModelField model field of route body, exact path first, then longest prefix ends with *,
+// empty if unmapped
+func (c *Config) GetRouteModelField(path string) string {
+	if field, ok := c.RouteModelFields[path]; ok {
+		return field
+	}
+	field, matched := "", 0
+	for route, val := range c.RouteModelFields {
+		prefix, ok := strings.CutSuffix(route, "*")
+		if ok && strings.HasPrefix(path, prefix) && len(prefix) >= matched {
+			field, matched = val, len(prefix)
+		}
+	}
+	return field
+}
+
+// GetGpuMemoryCoefficient vram estimate coefficient of sd model, longest keyword contained in model name
+// (case insensitive) first, then "*", nil no guard
+func (c *Config) GetGpuMemoryCoefficient(sdModel string) *GpuMemoryCoefficient {
+	name := strings.ToLower(sdModel)
+	coefficient, matched := c.GpuMemoryGuard[AllModels], 0
+	for keyword, val := range c.GpuMemoryGuard {
+		if keyword != AllModels && len(keyword) > matched && strings.Contains(name, strings.ToLower(keyword)) {
+			coefficient, matched = val, len(keyword)
+		}
+	}
+	return coefficient
+}
+
+// GetUploadAllowList accepted types of upload kind, nil accept all
+func (c *Config) GetUploadAllowList(kind string) *UploadAllowList {
+	return c.UploadAllowLists[kind]
+}
+
+// GetNegativeEmbeddings default negative embeddings of user, user setting cover "*"
+func (c *Config) GetNegativeEmbeddings(user string) []string {
+	if embeddings, ok := c.DefaultNegativeEmbeddings[user]; ok {
+		return embeddings
+	}
+	return c.DefaultNegativeEmbeddings[AllUsers]
+}
+
+// GetInlineImageMaxBytes max total size of inline images in bytes, 0 means disable
+func (c *Config) GetInlineImageMaxBytes() int64 {
+	if c.InlineImageMaxSize <= 0 {
+		return 0
+	}
+	return c.InlineImageMaxSize << 10
+}
+
+// GetOssMultipartThresholdBytes oss multipart upload threshold in bytes, 0 means disable
+func (c *Config) GetOssMultipartThresholdBytes() int64 {
+	if c.OssMultipartThreshold <= 0 {
+		return 0
+	}
+	return c.OssMultipartThreshold << 20
+}
+
+// GetMaxRequestBodyBytes request body limit in bytes, 0 means no limit
+func (c *Config) GetMaxRequestBodyBytes() int64 {
+	if c.MaxRequestBodySize <= 0 {
+		return 0
+	}
+	return c.MaxRequestBodySize << 20
+}
+
+// GetModelBucket bucket read models from, default bucket
+func (c *Config) GetModelBucket() string {
+	if c.ModelBucket != "" {
+		return c.ModelBucket
+	}
+	return c.Bucket
+}
+
+// GetOutputBucket bucket write output images to, default bucket
+func (c *Config) GetOutputBucket() string {
+	if c.OutputBucket != "" {
+		return c.OutputBucket
+	}
+	return c.Bucket
+}
+
+// GetSDPort sd port of sdUrlPrefix, scheme default port if not set
 func (c *Config) GetSDPort() string {
 	if c.SdUrlPrefix == "" {
 		return DefaultSdPort
 	}
-	items := strings.Split(c.SdUrlPrefix, ":")
-	if len(items) == 3 {
-		return items[2]
+	u, err := url.Parse(c.SdUrlPrefix)
+	if err != nil {
+		return ""
+	}
+	if port := u.Port(); port != "" {
+		return port
+	}
+	switch u.Scheme {
+	case "http":
+		return "80"
+	case "https":
+		return "443"
 	}
 	return ""
 }
 
+// normalizeSdUrlPrefix check sdUrlPrefix like http://host:port[/path], trim trailing /
+// api path append to it directly
+func normalizeSdUrlPrefix(prefix string) (string, error) {
+	u, err := url.Parse(prefix)
+	if err != nil {
+		return "", fmt.Errorf("sdUrlPrefix %s invalid: %s", prefix, err.Error())
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", fmt.Errorf("sdUrlPrefix %s scheme need http|https", prefix)
+	}
+	if u.Hostname() == "" {
+		return "", fmt.Errorf("sdUrlPrefix %s need host", prefix)
+	}
+	if port := u.Port(); port != "" {
+		if val, err := strconv.Atoi(port); err != nil || val <= 0 || val > 65535 {
+			return "", fmt.Errorf("sdUrlPrefix %s port invalid", prefix)
+		}
+	} else if strings.HasSuffix(u.Host, ":") {
+		return "", fmt.Errorf("sdUrlPrefix %s port empty", prefix)
+	}
+	if u.User != nil || u.RawQuery != "" || u.Fragment != "" {
+		return "", fmt.Errorf("sdUrlPrefix %s can not contain user, query or fragment", prefix)
+	}
+	return strings.TrimRight(u.String(), "/"), nil
+}
+
+// modelExtraArgsAllowed flags allowed in modelExtraArgs -> flag take value
+var modelExtraArgsAllowed = map[string]bool{
+	"--medvram":                  false,
+	"--medvram-sdxl":             false,
+	"--lowvram":                  false,
+	"--xformers":                 false,
+	"--opt-sdp-attention":        false,
+	"--opt-sdp-no-mem-attention": false,
+	"--opt-split-attention":      false,
+	"--no-half":                  false,
+	"--no-half-vae":              false,
+	"--upcast-sampling":          false,
+	"--disable-nan-check":        false,
+	"--opt-channelslast":         false,
+	"--no-hashing":               false,
+	"--precision":                true,
+}
+
+var extraArgValueRegex = regexp.MustCompile(`^[\w.-]+$`)
+
+// parseModelExtraArgs split model extra args to flag -> arg string like "--precision full",
+// error when flag not allowed or value invalid
+func parseModelExtraArgs(args string) ([]string, map[string]string, error) {
+	flags := make([]string, 0)
+	ret := make(map[string]string)
+	fields := strings.Fields(args)
+	for i := 0; i < len(fields); i++ {
+		flag, value, hasValue := strings.Cut(fields[i], "=")
+		takeValue, ok := modelExtraArgsAllowed[flag]
+		if !ok {
+			return nil, nil, fmt.Errorf("flag %s not allowed", flag)
+		}
+		if takeValue && !hasValue {
+			if i+1 >= len(fields) {
+				return nil, nil, fmt.Errorf("flag %s need value", flag)
+			}
+			i++
+			value, hasValue = fields[i], true
+		}
+		if !takeValue && hasValue {
+			return nil, nil, fmt.Errorf("flag %s not take value", flag)
+		}
+		if hasValue && !extraArgValueRegex.MatchString(value) {
+			return nil, nil, fmt.Errorf("flag %s value %s invalid", flag, value)
+		}
+		if _, ok := ret[flag]; !ok {
+			flags = append(flags, flag)
+		}
+		ret[flag] = flag
+		if hasValue {
+			ret[flag] = fmt.Sprintf("%s %s", flag, value)
+		}
+	}
+	return flags, ret, nil
+}
+
+// GetFuncTimeout function timeout(s) of sd model, model timeout cover timeout
+func (c *Config) GetFuncTimeout(sdModel string) int32 {
+	if timeout, ok := c.ModelTimeouts[sdModel]; ok && timeout > 0 {
+		return timeout
+	}
+	return c.Timeout
+}
+
+// GetExtraArgs sd start args of model function, model extra args cover same flag of extraArgs
+func (c *Config) GetExtraArgs(sdModel string) string {
+	flags, args, err := parseModelExtraArgs(c.ModelExtraArgs[sdModel])
+	if err != nil || len(flags) == 0 {
+		return c.withApiAuth(c.ExtraArgs)
+	}
+	fields := strings.Fields(c.ExtraArgs)
+	merged := make([]string, 0, len(fields)+len(flags))
+	for i := 0; i < len(fields); i++ {
+		flag, _, hasValue := strings.Cut(fields[i], "=")
+		if _, ok := args[flag]; !ok {
+			merged = append(merged, fields[i])
+			continue
+		}
+		// drop value of covered flag too
+		if modelExtraArgsAllowed[flag] && !hasValue && i+1 < len(fields) && !strings.HasPrefix(fields[i+1], "-") {
+			i++
+		}
+	}
+	for _, flag := range flags {
+		merged = append(merged, args[flag])
+	}
+	return c.withApiAuth(strings.Join(merged, " "))
+}
+
+// stripArg remove flag and its value from args
+func stripArg(args, flag string) string {
+	fields := strings.Fields(args)
+	ret := make([]string, 0, len(fields))
+	for i := 0; i < len(fields); i++ {
+		name, _, hasValue := strings.Cut(fields[i], "=")
+		if name != flag {
+			ret = append(ret, fields[i])
+			continue
+		}
+		if !hasValue && i+1 < len(fields) && !strings.HasPrefix(fields[i+1], "-") {
+			i++
+		}
+	}
+	return strings.Join(ret, " ")
+}
+
+// withApiAuth append --api-auth of sdApiAuth to sd start args
+func (c *Config) withApiAuth(args string) string {
+	if c.SdApiAuth == "" {
+		return args
+	}
+	return fmt.Sprintf("%s --api-auth %s", args, c.SdApiAuth)
+}
+
+// GetSdApiAuth webui api basic auth credentials, ok false when sdApiAuth not set
+func (c *Config) GetSdApiAuth() (user, passwd string, ok bool) {
+	if c.SdApiAuth == "" {
+		return "", "", false
+	}
+	return strings.Cut(c.SdApiAuth, ":")
+}
+
 func (c *Config) EnableProgressImg() bool {
 	return c.ProgressImageOutputSwitch == "on"
 }
@@ -162,6 +724,12 @@ func (c *Config) updateFromEnv() {
 		c.OssEndpoint = ossEndpoint
 		c.Bucket = bucket
 	}
+	if modelBucket := os.Getenv(OSS_MODEL_BUCKET); modelBucket != "" {
+		c.ModelBucket = modelBucket
+	}
+	if outputBucket := os.Getenv(OSS_OUTPUT_BUCKET); outputBucket != "" {
+		c.OutputBucket = outputBucket
+	}
 
 	// login
 	loginSwitch := os.Getenv(LOGINSWITCH)
@@ -175,6 +743,14 @@ func (c *Config) updateFromEnv() {
 		c.UseLocalModels = useLocalModel
 	}
 
+	// default model cover
+	if defaultModel := os.Getenv(DEFAULT_MODEL); defaultModel != "" {
+		c.DefaultModel = defaultModel
+	}
+	if fallback := os.Getenv(DELETED_MODEL_FALLBACK); fallback != "" {
+		c.DeletedModelFallback = fallback
+	}
+
 	// sd image cover
 	sdImage := os.Getenv(SD_IMAGE)
 	if sdImage != "" {
@@ -202,6 +778,9 @@ func (c *Config) updateFromEnv() {
 	if downstream != "" {
 		c.Downstream = downstream
 	}
+	if fallback := os.Getenv(ROUTING_FALLBACK); fallback != "" {
+		c.RoutingFallbackEndpoint = fallback
+	}
 
 	coldStartConcurrency := os.Getenv(COLD_START_CONCURRENCY)
 	c.ColdStartConcurrency = ColdStartConcurrency
@@ -224,11 +803,254 @@ func (c *Config) updateFromEnv() {
 		c.LogRemoteService = logRemoteService
 	}
 
+	if logBatchSize := os.Getenv(LOG_BATCH_SIZE); logBatchSize != "" {
+		if size, err := strconv.Atoi(logBatchSize); err == nil {
+			c.LogBatchSize = size
+		}
+	}
+
+	if logFlushInterval := os.Getenv(LOG_FLUSH_INTERVAL); logFlushInterval != "" {
+		if interval, err := strconv.Atoi(logFlushInterval); err == nil {
+			c.LogFlushInterval = interval
+		}
+	}
+
+	if logQueueSize := os.Getenv(LOG_QUEUE_SIZE); logQueueSize != "" {
+		if size, err := strconv.Atoi(logQueueSize); err == nil {
+			c.LogQueueSize = size
+		}
+	}
+
 	enableCollect := os.Getenv(ENABLE_COLLECT)
 	if enableCollect != "" {
 		c.EnableCollect = enableCollect
 	}
 
+	if maxBodySize := os.Getenv(MAX_REQUEST_BODY_SIZE); maxBodySize != "" {
+		if size, err := strconv.ParseInt(maxBodySize, 10, 64); err == nil {
+			c.MaxRequestBodySize = size
+		}
+	}
+
+	if coldStartBudget := os.Getenv(COLD_START_BUDGET); coldStartBudget != "" {
+		if budget, err := strconv.Atoi(coldStartBudget); err == nil {
+			c.ColdStartBudget = budget
+		}
+	}
+
+	if budgetWindow := os.Getenv(COLD_START_BUDGET_WINDOW); budgetWindow != "" {
+		if window, err := strconv.Atoi(budgetWindow); err == nil {
+			c.ColdStartBudgetWindow = window
+		}
+	}
+
+	if imageNameTemplate := os.Getenv(IMAGE_NAME_TEMPLATE); imageNameTemplate != "" {
+		c.ImageNameTemplate = imageNameTemplate
+	}
+
+	if maxDuration := os.Getenv(MAINTENANCE_MAX_DURATION); maxDuration != "" {
+		if duration, err := strconv.Atoi(maxDuration); err == nil {
+			c.MaintenanceMaxDuration = duration
+		}
+	}
+
+	if minInterval := os.Getenv(LISTEN_MIN_INTERVAL); minInterval != "" {
+		if interval, err := strconv.ParseInt(minInterval, 10, 32); err == nil {
+			c.ListenMinInterval = int32(interval)
+		}
+	}
+
+	if maxInterval := os.Getenv(LISTEN_MAX_INTERVAL); maxInterval != "" {
+		if interval, err := strconv.ParseInt(maxInterval, 10, 32); err == nil {
+			c.ListenMaxInterval = int32(interval)
+		}
+	}
+
+	// model1:2,model2:1
+	if warmPool := os.Getenv(WARM_POOL); warmPool != "" {
+		c.WarmPool = make(map[string]int)
+		for _, item := range strings.Split(warmPool, ",") {
+			idx := strings.LastIndex(item, ":")
+			if idx <= 0 {
+				continue
+			}
+			if minWarm, err := strconv.Atoi(strings.TrimSpace(item[idx+1:])); err == nil {
+				c.WarmPool[strings.TrimSpace(item[:idx])] = minWarm
+			}
+		}
+	}
+
+	if warmPoolInterval := os.Getenv(WARM_POOL_INTERVAL); warmPoolInterval != "" {
+		if interval, err := strconv.Atoi(warmPoolInterval); err == nil {
+			c.WarmPoolInterval = interval
+		}
+	}
+
+	if predictTimeout := os.Getenv(PREDICT_TIMEOUT); predictTimeout != "" {
+		if timeout, err := strconv.ParseInt(predictTimeout, 10, 32); err == nil {
+			c.PredictTimeout = int32(timeout)
+		}
+	}
+
+	if predictConcurrency := os.Getenv(PREDICT_CONCURRENCY); predictConcurrency != "" {
+		if concurrency, err := strconv.Atoi(predictConcurrency); err == nil {
+			c.PredictConcurrency = concurrency
+		}
+	}
+	if ossDownloadConcurrency := os.Getenv(OSS_DOWNLOAD_CONCURRENCY); ossDownloadConcurrency != "" {
+		if concurrency, err := strconv.Atoi(ossDownloadConcurrency); err == nil {
+			c.OssDownloadConcurrency = concurrency
+		}
+	}
+	if maxQueueLength := os.Getenv(MAX_QUEUE_LENGTH); maxQueueLength != "" {
+		if length, err := strconv.Atoi(maxQueueLength); err == nil {
+			c.MaxQueueLength = length
+		}
+	}
+	if maxUserTasks := os.Getenv(MAX_USER_TASKS); maxUserTasks != "" {
+		if max, err := strconv.Atoi(maxUserTasks); err == nil {
+			c.MaxUserTasks = max
+		}
+	}
+	if predictQueueTimeout := os.Getenv(PREDICT_QUEUE_TIMEOUT); predictQueueTimeout != "" {
+		if timeout, err := strconv.Atoi(predictQueueTimeout); err == nil {
+			c.PredictQueueTimeout = timeout
+		}
+	}
+
+	if breakerThreshold := os.Getenv(BREAKER_THRESHOLD); breakerThreshold != "" {
+		if threshold, err := strconv.Atoi(breakerThreshold); err == nil {
+			c.BreakerThreshold = threshold
+		}
+	}
+	if breakerCooldown := os.Getenv(BREAKER_COOLDOWN); breakerCooldown != "" {
+		if cooldown, err := strconv.Atoi(breakerCooldown); err == nil {
+			c.BreakerCooldown = cooldown
+		}
+	}
+
+	if funcRefreshInterval := os.Getenv(FUNC_REFRESH_INTERVAL); funcRefreshInterval != "" {
+		if interval, err := strconv.Atoi(funcRefreshInterval); err == nil {
+			c.FuncRefreshInterval = interval
+		}
+	}
+
+	if renderCacheTTL := os.Getenv(RENDER_CACHE_TTL); renderCacheTTL != "" {
+		if ttl, err := strconv.Atoi(renderCacheTTL); err == nil {
+			c.RenderCacheTTL = ttl
+		}
+	}
+
+	if shareLinkSecret := os.Getenv(SHARE_LINK_SECRET); shareLinkSecret != "" {
+		c.ShareLinkSecret = shareLinkSecret
+	}
+
+	if imageRetention := os.Getenv(IMAGE_RETENTION_DAYS); imageRetention != "" {
+		if days, err := strconv.Atoi(imageRetention); err == nil {
+			c.ImageRetentionDays = days
+		}
+	}
+
+	if taskRetention := os.Getenv(TASK_RETENTION_DAYS); taskRetention != "" {
+		if days, err := strconv.Atoi(taskRetention); err == nil {
+			c.TaskRetentionDays = days
+		}
+	}
+
+	if caPort := os.Getenv(CA_PORT); caPort != "" {
+		if port, err := strconv.ParseInt(caPort, 10, 32); err == nil {
+			c.CAPort = int32(port)
+		}
+	}
+
+	if webServerMode := os.Getenv(WEB_SERVER_MODE); webServerMode != "" {
+		if mode, err := strconv.ParseBool(webServerMode); err == nil {
+			c.WebServerMode = &mode
+		}
+	}
+
+	if detectImageType := os.Getenv(DETECT_IMAGE_TYPE); detectImageType != "" {
+		if detect, err := strconv.ParseBool(detectImageType); err == nil {
+			c.DetectImageType = &detect
+		}
+	}
+
+	if apiAuth := os.Getenv(SD_API_AUTH); apiAuth != "" {
+		c.SdApiAuth = apiAuth
+	}
+
+	if source := os.Getenv(INIT_IMAGE_SOURCE); source != "" {
+		c.InitImageSource = source
+	}
+
+	if compression := os.Getenv(IMAGE_COMPRESSION); compression != "" {
+		c.ImageCompression = compression
+	}
+
+	if level := os.Getenv(IMAGE_COMPRESSION_LEVEL); level != "" {
+		c.ImageCompressionLevel = level
+	}
+
+	if mnsEndpoint := os.Getenv(MNS_ENDPOINT); mnsEndpoint != "" {
+		c.MnsEndpoint = mnsEndpoint
+	}
+	if mnsTopic := os.Getenv(MNS_TOPIC); mnsTopic != "" {
+		c.MnsTopic = mnsTopic
+	}
+	if mnsQueue := os.Getenv(MNS_QUEUE); mnsQueue != "" {
+		c.MnsQueue = mnsQueue
+	}
+
+	if validatePrompt := os.Getenv(VALIDATE_PROMPT); validatePrompt != "" {
+		if validate, err := strconv.ParseBool(validatePrompt); err == nil {
+			c.ValidatePrompt = validate
+		}
+	}
+
+	if failOnMissing := os.Getenv(FAIL_ON_MISSING_SD_MODEL); failOnMissing != "" {
+		if fail, err := strconv.ParseBool(failOnMissing); err == nil {
+			c.FailOnMissingSdModel = fail
+		}
+	}
+
+	if autoRegister := os.Getenv(MODEL_AUTO_REGISTER); autoRegister != "" {
+		if register, err := strconv.ParseBool(autoRegister); err == nil {
+			c.ModelAutoRegister = register
+		}
+	}
+
+	if accelerationType := os.Getenv(ACCELERATION_TYPE); accelerationType != "" {
+		c.AccelerationType = accelerationType
+	}
+
+	if fallbacks := os.Getenv(INSTANCE_TYPE_FALLBACKS); fallbacks != "" {
+		c.InstanceTypeFallbacks = strings.Split(fallbacks, ",")
+	}
+
+	if uploadConcurrency := os.Getenv(OSS_UPLOAD_CONCURRENCY); uploadConcurrency != "" {
+		if concurrency, err := strconv.Atoi(uploadConcurrency); err == nil {
+			c.OssUploadConcurrency = concurrency
+		}
+	}
+
+	if multipartThreshold := os.Getenv(OSS_MULTIPART_THRESHOLD); multipartThreshold != "" {
+		if threshold, err := strconv.ParseInt(multipartThreshold, 10, 64); err == nil {
+			c.OssMultipartThreshold = threshold
+		}
+	}
+
+	if retryAttempts := os.Getenv(OSS_RETRY_ATTEMPTS); retryAttempts != "" {
+		if attempts, err := strconv.Atoi(retryAttempts); err == nil {
+			c.OssRetryAttempts = attempts
+		}
+	}
+
+	if inlineMaxSize := os.Getenv(INLINE_IMAGE_MAX_SIZE); inlineMaxSize != "" {
+		if size, err := strconv.ParseInt(inlineMaxSize, 10, 64); err == nil {
+			c.InlineImageMaxSize = size
+		}
+	}
+
 	disableHealthCheck := os.Getenv(DISABLE_HF_CHECK)
 	if disableHealthCheck != "" {
 		c.DisableHealthCheck = disableHealthCheck
@@ -244,26 +1066,255 @@ func (c *Config) check() error {
 	if !strings.Contains(c.ExtraArgs, "--nowebui") {
 		c.ExtraArgs = fmt.Sprintf("%s %s", c.ExtraArgs, "--nowebui")
 	}
+	// webui api auth only from sdApiAuth, proxy not know credentials in extraArgs
 	if strings.Contains(c.ExtraArgs, "--api-auth") {
-		c.ExtraArgs = strings.ReplaceAll(c.ExtraArgs, "--api-auth", "")
+		c.ExtraArgs = stripArg(c.ExtraArgs, "--api-auth")
 	}
 	if (c.ServerName == CONTROL || c.ServerName == AGENT) && c.OssMode == REMOTE {
-		if c.Bucket == "" || c.OssEndpoint == "" {
+		if c.GetModelBucket() == "" || c.GetOutputBucket() == "" || c.OssEndpoint == "" {
 			logrus.Error("oss remote mode need set oss bucket and endpoint, please check it")
 			return errors.New("oss remote mode need set oss bucket and endpoint, please check it")
 		}
 	}
+	if c.MnsTopic != "" && c.MnsQueue != "" {
+		return errors.New("mnsTopic and mnsQueue only one can be set")
+	}
+	// yaml nested map decoded with interface{} keys, json marshal of model defaults need string keys
+	for sdModel, defaults := range c.ModelDefaults {
+		c.ModelDefaults[sdModel] = normalizeYamlValue(defaults).(map[string]interface{})
+	}
+	// function container
+	if c.AccelerationType != AccelerationDefault && c.AccelerationType != AccelerationNone {
+		return fmt.Errorf("accelerationType %s not support, value: %s|%s", c.AccelerationType,
+			AccelerationDefault, AccelerationNone)
+	}
+	for _, instanceType := range c.GetInstanceTypes() {
+		if strings.HasPrefix(instanceType, gpuInstanceTypePrefix) && c.AccelerationType != AccelerationDefault {
+			return fmt.Errorf("gpu instanceType %s need accelerationType %s", instanceType, AccelerationDefault)
+		}
+	}
+	if c.IsWebServerMode() && (c.CAPort <= 0 || c.CAPort > 65535) {
+		return fmt.Errorf("web server mode need valid caPort, current %d", c.CAPort)
+	}
+	// image name unique per task image
+	if !strings.Contains(c.ImageNameTemplate, "{taskId}") || !strings.Contains(c.ImageNameTemplate, "{index}") {
+		return fmt.Errorf("imageNameTemplate %s need contain {taskId} and {index}", c.ImageNameTemplate)
+	}
+	if strings.Contains(c.ImageNameTemplate, "..") {
+		return fmt.Errorf("imageNameTemplate %s can not contain ..", c.ImageNameTemplate)
+	}
+	for user, prefixes := range c.OutputPrefixes {
+		for _, prefix := range prefixes {
+			if prefix == "" || strings.HasPrefix(prefix, "/") || strings.Contains(prefix, "..") {
+				return fmt.Errorf("outputPrefixes %s:%s invalid, need relative path without ..", user, prefix)
+			}
+		}
+	}
+	for user, limit := range c.RequestLimits {
+		if limit == nil {
+			continue
+		}
+		if limit.MaxSteps < 0 || limit.MaxCfgScale < 0 {
+			return fmt.Errorf("requestLimits %s invalid, maxSteps and maxCfgScale need >= 0", user)
+		}
+		if limit.Mode != "" && limit.Mode != RequestLimitClamp && limit.Mode != RequestLimitReject {
+			return fmt.Errorf("requestLimits %s mode %s invalid, need %s or %s", user, limit.Mode,
+				RequestLimitClamp, RequestLimitReject)
+		}
+	}
+	for keyword, coefficient := range c.GpuMemoryGuard {
+		if coefficient != nil && (coefficient.BaseMB < 0 || coefficient.PerMegapixelMB < 0) {
+			return fmt.Errorf("gpuMemoryGuard %s invalid, baseMB and perMegapixelMB need >= 0", keyword)
+		}
+	}
+	for route, field := range c.RouteModelFields {
+		if !strings.HasPrefix(route, "/") || field == "" {
+			return fmt.Errorf("routeModelFields %s:%s invalid, need path start with / and field", route, field)
+		}
+	}
+	if headers := c.ForwardHeaders; headers != nil {
+		for _, list := range [][]string{headers.Allow, headers.Deny} {
+			for i, name := range list {
+				if name == "" || strings.ContainsAny(name, " :") {
+					return fmt.Errorf("forwardHeaders header %q invalid", name)
+				}
+				list[i] = http.CanonicalHeaderKey(name)
+			}
+		}
+	}
+	for kind, allowList := range c.UploadAllowLists {
+		if kind != UploadImage && kind != UploadModel {
+			return fmt.Errorf("uploadAllowLists %s invalid, need %s or %s", kind, UploadImage, UploadModel)
+		}
+		if allowList == nil {
+			continue
+		}
+		for i, ext := range allowList.Extensions {
+			if !strings.HasPrefix(ext, ".") {
+				return fmt.Errorf("uploadAllowLists %s extension %s invalid, need start with .", kind, ext)
+			}
+			allowList.Extensions[i] = strings.ToLower(ext)
+		}
+		for i, mimeType := range allowList.MimeTypes {
+			if !strings.Contains(mimeType, "/") {
+				return fmt.Errorf("uploadAllowLists %s mime type %s invalid", kind, mimeType)
+			}
+			allowList.MimeTypes[i] = strings.ToLower(mimeType)
+		}
+	}
+	if keys := c.OverrideSettingsKeys; keys != nil {
+		if len(keys.Known) == 0 {
+			return errors.New("overrideSettingsKeys known keys empty")
+		}
+		if keys.Mode != "" && keys.Mode != UnknownSettingDrop && keys.Mode != UnknownSettingReject {
+			return fmt.Errorf("overrideSettingsKeys mode %s invalid, need %s or %s", keys.Mode,
+				UnknownSettingDrop, UnknownSettingReject)
+		}
+	}
+	if c.ImageRetentionDays < 0 || c.TaskRetentionDays < 0 {
+		return fmt.Errorf("imageRetentionDays %d taskRetentionDays %d invalid, need >= 0", c.ImageRetentionDays,
+			c.TaskRetentionDays)
+	}
+	if c.PredictConcurrency < 0 {
+		return fmt.Errorf("predictConcurrency %d invalid, need >= 0", c.PredictConcurrency)
+	}
+	if c.OssDownloadConcurrency < 0 {
+		return fmt.Errorf("ossDownloadConcurrency %d invalid, need >= 0", c.OssDownloadConcurrency)
+	}
+	if c.ModelAutoRegister && strings.Trim(c.ModelAutoRegisterOssDir, "/") == "" {
+		return errors.New("modelAutoRegisterOssDir empty, need oss dir of models when modelAutoRegister enabled")
+	}
+	if strings.Contains(c.DeletedModelFallback, "..") {
+		return fmt.Errorf("deletedModelFallback %s can not contain ..", c.DeletedModelFallback)
+	}
+	if c.SdApiAuth != "" {
+		if user, passwd, ok := strings.Cut(c.SdApiAuth, ":"); !ok || user == "" || passwd == "" ||
+			strings.ContainsAny(c.SdApiAuth, " ,") {
+			return errors.New("sdApiAuth invalid, need user:pass without space or comma")
+		}
+	}
+	if c.InitImageSource != InitImageBase64 && c.InitImageSource != InitImageUrl {
+		return fmt.Errorf("initImageSource %s invalid, need %s or %s", c.InitImageSource,
+			InitImageBase64, InitImageUrl)
+	}
+	if c.ImageCompression != ImageCompressionNone && c.ImageCompression != ImageCompressionPng {
+		return fmt.Errorf("imageCompression %s invalid, need %s or %s", c.ImageCompression,
+			ImageCompressionNone, ImageCompressionPng)
+	}
+	switch c.ImageCompressionLevel {
+	case ImageCompressionFast, ImageCompressionDefault, ImageCompressionBest:
+	default:
+		return fmt.Errorf("imageCompressionLevel %s invalid, need %s|%s|%s", c.ImageCompressionLevel,
+			ImageCompressionFast, ImageCompressionDefault, ImageCompressionBest)
+	}
+	if c.MaxQueueLength < 0 {
+		return fmt.Errorf("maxQueueLength %d invalid, need >= 0", c.MaxQueueLength)
+	}
+	for sdModel, length := range c.ModelMaxQueueLength {
+		if sdModel == "" || length < 0 {
+			return fmt.Errorf("modelMaxQueueLength %s:%d invalid, need model and length >= 0", sdModel, length)
+		}
+	}
+	for name, preset := range c.DimensionPresets {
+		if err := checkDimensionPreset(name, preset); err != nil {
+			return err
+		}
+	}
+	if c.ShareLinkTTL > MaxShareLinkTTL {
+		return fmt.Errorf("shareLinkTTL %d invalid, need <= %d", c.ShareLinkTTL, MaxShareLinkTTL)
+	}
+	if c.MaxUserTasks < 0 {
+		return fmt.Errorf("maxUserTasks %d invalid, need >= 0", c.MaxUserTasks)
+	}
+	for user, max := range c.UserMaxTasks {
+		if user == "" || max < 0 {
+			return fmt.Errorf("userMaxTasks %s:%d invalid, need user and max >= 0", user, max)
+		}
+	}
+	if c.ListenMaxInterval < c.ListenMinInterval {
+		return fmt.Errorf("listenMaxInterval %d less than listenMinInterval %d", c.ListenMaxInterval,
+			c.ListenMinInterval)
+	}
+	for sdModel, minWarm := range c.WarmPool {
+		if sdModel == "" || minWarm < 0 {
+			return fmt.Errorf("warmPool %s:%d invalid, need model and minWarm >= 0", sdModel, minWarm)
+		}
+	}
+	for sdModel, args := range c.ModelExtraArgs {
+		if _, _, err := parseModelExtraArgs(args); err != nil {
+			return fmt.Errorf("modelExtraArgs %s invalid: %s", sdModel, err.Error())
+		}
+	}
+	if c.Timeout > MaxFuncTimeout {
+		return fmt.Errorf("timeout %d invalid, need <= %d", c.Timeout, MaxFuncTimeout)
+	}
+	for sdModel, timeout := range c.ModelTimeouts {
+		if sdModel == "" || timeout <= 0 || timeout > MaxFuncTimeout {
+			return fmt.Errorf("modelTimeouts %s:%d invalid, need model and 0 < timeout <= %d", sdModel, timeout,
+				MaxFuncTimeout)
+		}
+	}
+	if c.RoutingFallbackEndpoint != "" {
+		u, err := url.Parse(c.RoutingFallbackEndpoint)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("routingFallbackEndpoint %s invalid, need http|https://host[:port]",
+				c.RoutingFallbackEndpoint)
+		}
+	}
+	sdUrlPrefix, err := normalizeSdUrlPrefix(c.SdUrlPrefix)
+	if err != nil {
+		return err
+	}
+	c.SdUrlPrefix = sdUrlPrefix
+	return nil
+}
+
+var dimensionPresetNameRegex = regexp.MustCompile(`^[\w.-]+$`)
+
+// width/height multiple of 8 within webui range, batchSize within webui range
+func checkDimensionPreset(name string, preset *DimensionPreset) error {
+	if !dimensionPresetNameRegex.MatchString(name) {
+		return fmt.Errorf("dimensionPresets name %s invalid, only support letters, digits, _ - .", name)
+	}
+	if preset == nil || (preset.Width == 0 && preset.Height == 0 && preset.BatchSize == 0) {
+		return fmt.Errorf("dimensionPresets %s empty", name)
+	}
+	for _, size := range []int64{preset.Width, preset.Height} {
+		if size != 0 && (size < MinImageSize || size > MaxImageSize || size%8 != 0) {
+			return fmt.Errorf("dimensionPresets %s size %d invalid, need multiple of 8 in [%d, %d]", name, size,
+				MinImageSize, MaxImageSize)
+		}
+	}
+	if preset.BatchSize < 0 || preset.BatchSize > MaxBatchSize {
+		return fmt.Errorf("dimensionPresets %s batchSize %d invalid, need in [0, %d]", name, preset.BatchSize,
+			MaxBatchSize)
+	}
 	return nil
 }
 
 // set default
 func (c *Config) setDefaults() {
+	if c.InitImageSource == "" {
+		c.InitImageSource = InitImageBase64
+	}
+	if c.ImageCompression == "" {
+		c.ImageCompression = ImageCompressionNone
+	}
+	if c.ImageCompressionLevel == "" {
+		c.ImageCompressionLevel = ImageCompressionBest
+	}
 	if c.OtsTimeToAlive == 0 {
 		c.OtsTimeToAlive = -1
 	}
 	if c.ListenInterval == 0 {
 		c.ListenInterval = 1
 	}
+	if c.ListenMinInterval <= 0 {
+		c.ListenMinInterval = DefaultListenMinInterval
+	}
+	if c.ListenMaxInterval <= 0 {
+		c.ListenMaxInterval = DefaultListenMaxInterval
+	}
 	if c.SessionExpire == 0 {
 		c.SessionExpire = DefaultSessionExpire
 	}
@@ -288,6 +1339,15 @@ func (c *Config) setDefaults() {
 	if c.LogRemoteService == "" {
 		c.LogRemoteService = DefaultLogService
 	}
+	if c.LogBatchSize <= 0 {
+		c.LogBatchSize = DefaultLogBatchSize
+	}
+	if c.LogFlushInterval <= 0 {
+		c.LogFlushInterval = DefaultLogFlushInterval
+	}
+	if c.LogQueueSize <= 0 {
+		c.LogQueueSize = DefaultLogQueueSize
+	}
 	if c.SdPath == "" {
 		if os.Getenv(SERVER_NAME) == PROXY || os.Getenv(SERVER_NAME) == CONTROL {
 			c.SdPath = DefaultSdPathProxy
@@ -298,6 +1358,39 @@ func (c *Config) setDefaults() {
 	if c.CAPort == 0 {
 		c.CAPort = DefaultCaPort
 	}
+	if c.AccelerationType == "" {
+		c.AccelerationType = AccelerationDefault
+	}
+	if c.ColdStartBudgetWindow <= 0 {
+		c.ColdStartBudgetWindow = DefaultColdStartBudgetWindow
+	}
+	if c.ImageNameTemplate == "" {
+		c.ImageNameTemplate = DefaultImageNameTemplate
+	}
+	if c.RenderCacheTTL <= 0 {
+		c.RenderCacheTTL = DefaultRenderCacheTTL
+	}
+	if c.UserStatsDays <= 0 {
+		c.UserStatsDays = DefaultUserStatsDays
+	}
+	if c.ShareLinkTTL <= 0 {
+		c.ShareLinkTTL = DefaultShareLinkTTL
+	}
+	if c.ForwardHeaders == nil {
+		c.ForwardHeaders = new(ForwardHeaders)
+	}
+	if c.ForwardHeaders.Deny == nil {
+		c.ForwardHeaders.Deny = append([]string(nil), DefaultDenyHeaders...)
+	}
+	if c.RetentionInterval <= 0 {
+		c.RetentionInterval = DefaultRetentionInterval
+	}
+	if c.WarmPoolInterval <= 0 {
+		c.WarmPoolInterval = DefaultWarmPoolInterval
+	}
+	if c.FuncRefreshInterval == 0 {
+		c.FuncRefreshInterval = DefaultFuncRefreshInterval
+	}
 	if c.CPU == 0 {
 		c.CPU = DefaultCpu
 	}
@@ -322,6 +1415,41 @@ func (c *Config) setDefaults() {
 	if c.SdUrlPrefix == "" {
 		c.SdUrlPrefix = fmt.Sprintf("http://localhost:%s", DefaultSdPort)
 	}
+	if c.MaxRequestBodySize == 0 {
+		c.MaxRequestBodySize = DefaultMaxRequestBodySize
+	}
+	if c.OssUploadConcurrency <= 0 {
+		c.OssUploadConcurrency = DefaultOssUploadConcurrency
+	}
+	if c.OssMultipartThreshold == 0 {
+		c.OssMultipartThreshold = DefaultOssMultipartThreshold
+	}
+	if c.OssRetryAttempts <= 0 {
+		c.OssRetryAttempts = DefaultOssRetryAttempts
+	}
+	if c.InlineImageMaxSize == 0 {
+		c.InlineImageMaxSize = DefaultInlineImageMaxSize
+	}
+	if c.PredictQueueTimeout <= 0 {
+		c.PredictQueueTimeout = DefaultPredictQueueTimeout
+	}
+	if c.OssDownloadTimeout <= 0 {
+		c.OssDownloadTimeout = DefaultOssDownloadTimeout
+	}
+	if c.ModelAutoRegisterTimeout <= 0 {
+		c.ModelAutoRegisterTimeout = DefaultAutoRegisterTimeout
+	}
+	if c.BreakerCooldown <= 0 {
+		c.BreakerCooldown = DefaultBreakerCooldown
+	}
+	if c.ModelDirs == nil {
+		c.ModelDirs = make(map[string]string)
+	}
+	for modelType, dir := range DefaultModelDirs {
+		if c.ModelDirs[modelType] == "" {
+			c.ModelDirs[modelType] = dir
+		}
+	}
 }
 
 func InitConfig(fn string) error {
@@ -367,3 +1495,28 @@ func InitConfig(fn string) error {
 	}
 	return nil
 }
+
+// normalizeYamlValue convert yaml decoded map[interface{}]interface{} to map[string]interface{} recursively
+func normalizeYamlValue(val interface{}) interface{} {
+	switch v := val.(type) {
+	case map[interface{}]interface{}:
+		ret := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			ret[fmt.Sprintf("%v", key)] = normalizeYamlValue(item)
+		}
+		return ret
+	case map[string]interface{}:
+		ret := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			ret[key] = normalizeYamlValue(item)
+		}
+		return ret
+	case []interface{}:
+		ret := make([]interface{}, len(v))
+		for i, item := range v {
+			ret[i] = normalizeYamlValue(item)
+		}
+		return ret
+	}
+	return val
+}
@@ -0,0 +1,521 @@
+package config
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+)
+
+func TestCheckFunctionContainer(t *testing.T) {
+	newConfig := func() *Config {
+		c := &Config{ConfigYaml: ConfigYaml{ExtraArgs: DefaultExtraArgs, InstanceType: DefaultInstanceType}}
+		c.setDefaults()
+		return c
+	}
+	c := newConfig()
+	assert.Nil(t, c.check())
+	assert.True(t, c.IsWebServerMode())
+	assert.True(t, c.IsDetectImageType())
+
+	// gpu need image acceleration
+	c = newConfig()
+	c.AccelerationType = AccelerationNone
+	assert.NotNil(t, c.check())
+
+	c = newConfig()
+	c.AccelerationType = "Unknown"
+	assert.NotNil(t, c.check())
+
+	// web server mode need valid port
+	c = newConfig()
+	c.CAPort = -1
+	assert.NotNil(t, c.check())
+	webServerMode := false
+	c.WebServerMode = &webServerMode
+	assert.Nil(t, c.check())
+}
+
+func TestCheckImageNameTemplate(t *testing.T) {
+	c := &Config{ConfigYaml: ConfigYaml{ExtraArgs: DefaultExtraArgs}}
+	c.setDefaults()
+	assert.Equal(t, DefaultImageNameTemplate, c.ImageNameTemplate)
+	assert.Nil(t, c.check())
+
+	c.ImageNameTemplate = "images/{date}/{user}/{taskId}_{index}_{seed}.png"
+	assert.Nil(t, c.check())
+
+	// same name for every image
+	c.ImageNameTemplate = "images/{user}/{seed}.png"
+	assert.NotNil(t, c.check())
+
+	c.ImageNameTemplate = "../{taskId}_{index}.png"
+	assert.NotNil(t, c.check())
+}
+
+func TestOutputPrefixes(t *testing.T) {
+	c := &Config{ConfigYaml: ConfigYaml{ExtraArgs: DefaultExtraArgs}}
+	c.setDefaults()
+	c.OutputPrefixes = map[string][]string{AllUsers: {"users/{user}"}, "admin": {"projects"}}
+	assert.Nil(t, c.check())
+	assert.Equal(t, []string{"projects", "users/admin"}, c.GetOutputPrefixes("admin"))
+	assert.Equal(t, []string{"users/u1"}, c.GetOutputPrefixes("u1"))
+
+	c.OutputPrefixes["u1"] = []string{"../other"}
+	assert.NotNil(t, c.check())
+	c.OutputPrefixes["u1"] = []string{"/abs"}
+	assert.NotNil(t, c.check())
+}
+
+func TestSdUrlPrefix(t *testing.T) {
+	c := &Config{ConfigYaml: ConfigYaml{ExtraArgs: DefaultExtraArgs}}
+	c.setDefaults()
+	assert.Nil(t, c.check())
+	assert.Equal(t, DefaultSdPort, c.GetSDPort())
+
+	c.SdUrlPrefix = "http://127.0.0.1:7861/"
+	assert.Nil(t, c.check())
+	assert.Equal(t, "http://127.0.0.1:7861", c.SdUrlPrefix)
+	assert.Equal(t, "7861", c.GetSDPort())
+
+	c.SdUrlPrefix = "https://sd.example.com/webui/"
+	assert.Nil(t, c.check())
+	assert.Equal(t, "https://sd.example.com/webui", c.SdUrlPrefix)
+	assert.Equal(t, "443", c.GetSDPort())
+
+	for _, prefix := range []string{"localhost:7860", "ftp://localhost:7860", "http://:7860",
+		"http://localhost:", "http://localhost:abc", "http://localhost:70000", "http://localhost:7860?a=b"} {
+		c.SdUrlPrefix = prefix
+		assert.NotNil(t, c.check(), prefix)
+	}
+}
+
+func TestWarmPool(t *testing.T) {
+	t.Setenv(WARM_POOL, "sd_xl.safetensors:2, v1-5:1,invalid,bad:x")
+	c := &Config{ConfigYaml: ConfigYaml{ExtraArgs: DefaultExtraArgs}}
+	c.updateFromEnv()
+	c.setDefaults()
+	assert.Equal(t, map[string]int{"sd_xl.safetensors": 2, "v1-5": 1}, c.WarmPool)
+	assert.Equal(t, DefaultWarmPoolInterval, c.WarmPoolInterval)
+	assert.Equal(t, DefaultFuncRefreshInterval, c.FuncRefreshInterval)
+	assert.Nil(t, c.check())
+
+	c.WarmPool["v1-5"] = -1
+	assert.NotNil(t, c.check())
+}
+
+func TestListenInterval(t *testing.T) {
+	c := &Config{ConfigYaml: ConfigYaml{ExtraArgs: DefaultExtraArgs}}
+	c.setDefaults()
+	assert.Equal(t, int32(DefaultListenMinInterval), c.ListenMinInterval)
+	assert.Equal(t, int32(DefaultListenMaxInterval), c.ListenMaxInterval)
+	assert.Nil(t, c.check())
+
+	c.ListenMaxInterval = 100
+	assert.NotNil(t, c.check())
+}
+
+func TestOssBuckets(t *testing.T) {
+	c := &Config{ConfigYaml: ConfigYaml{ExtraArgs: DefaultExtraArgs, ServerName: CONTROL, OssMode: REMOTE,
+		OssEndpoint: "oss-cn-hangzhou.aliyuncs.com", Bucket: "sd"}}
+	c.setDefaults()
+	// default bucket for compatibility
+	assert.Equal(t, "sd", c.GetModelBucket())
+	assert.Equal(t, "sd", c.GetOutputBucket())
+	assert.Nil(t, c.check())
+
+	t.Setenv(OSS_OUTPUT_BUCKET, "sd-output")
+	c.ModelBucket = "sd-models"
+	c.updateFromEnv()
+	assert.Equal(t, "sd-models", c.GetModelBucket())
+	assert.Equal(t, "sd-output", c.GetOutputBucket())
+
+	c.Bucket = ""
+	assert.Nil(t, c.check())
+	c.ModelBucket = ""
+	assert.NotNil(t, c.check())
+}
+
+func TestPredictTimeout(t *testing.T) {
+	c := &Config{ConfigYaml: ConfigYaml{ExtraArgs: DefaultExtraArgs}}
+	c.setDefaults()
+	assert.Equal(t, DefaultTimeout*time.Second, c.GetPredictTimeout())
+
+	// follow function timeout
+	c.Timeout = 300
+	assert.Equal(t, 300*time.Second, c.GetPredictTimeout())
+
+	t.Setenv(PREDICT_TIMEOUT, "120")
+	c.updateFromEnv()
+	assert.Equal(t, 120*time.Second, c.GetPredictTimeout())
+}
+
+func TestLogQueueSize(t *testing.T) {
+	c := &Config{ConfigYaml: ConfigYaml{ExtraArgs: DefaultExtraArgs}}
+	c.setDefaults()
+	assert.Equal(t, DefaultLogQueueSize, c.LogQueueSize)
+
+	t.Setenv(LOG_QUEUE_SIZE, "128")
+	c.updateFromEnv()
+	assert.Equal(t, 128, c.LogQueueSize)
+}
+
+func TestPredictConcurrency(t *testing.T) {
+	c := &Config{ConfigYaml: ConfigYaml{ExtraArgs: DefaultExtraArgs}}
+	c.setDefaults()
+	assert.Equal(t, 0, c.PredictConcurrency)
+	assert.Equal(t, DefaultPredictQueueTimeout*time.Second, c.GetPredictQueueTimeout())
+	assert.Nil(t, c.check())
+
+	t.Setenv(PREDICT_CONCURRENCY, "2")
+	t.Setenv(PREDICT_QUEUE_TIMEOUT, "30")
+	c.updateFromEnv()
+	assert.Equal(t, 2, c.PredictConcurrency)
+	assert.Equal(t, 30*time.Second, c.GetPredictQueueTimeout())
+
+	c.PredictConcurrency = -1
+	assert.NotNil(t, c.check())
+}
+
+func TestModelExtraArgs(t *testing.T) {
+	c := &Config{ConfigYaml: ConfigYaml{ExtraArgs: "--api --nowebui --precision autocast --xformers"}}
+	c.setDefaults()
+	c.ModelExtraArgs = map[string]string{
+		"sd_xl.safetensors": "--medvram-sdxl --precision=full --no-half-vae",
+		"v1-5":              "",
+	}
+	assert.Nil(t, c.check())
+	assert.Equal(t, "--api --nowebui --xformers --medvram-sdxl --precision full --no-half-vae",
+		c.GetExtraArgs("sd_xl.safetensors"))
+	assert.Equal(t, c.ExtraArgs, c.GetExtraArgs("v1-5"))
+	assert.Equal(t, c.ExtraArgs, c.GetExtraArgs("other"))
+
+	// only allow-list flags with safe value
+	for _, args := range []string{
+		"--medvram --api-auth user:pass",
+		"--ckpt-dir /tmp",
+		"--precision",
+		"--precision full;rm",
+		"--medvram=1",
+	} {
+		c.ModelExtraArgs = map[string]string{"sd_xl.safetensors": args}
+		assert.NotNil(t, c.check(), args)
+	}
+}
+
+func TestMaxQueueLength(t *testing.T) {
+	t.Setenv(MAX_QUEUE_LENGTH, "50")
+	c := &Config{ConfigYaml: ConfigYaml{ExtraArgs: DefaultExtraArgs,
+		ModelMaxQueueLength: map[string]int{"sd_xl.safetensors": 10}}}
+	c.updateFromEnv()
+	c.setDefaults()
+	assert.Nil(t, c.check())
+	assert.Equal(t, 50, c.MaxQueueLength)
+	assert.Equal(t, 10, c.GetMaxQueueLength("sd_xl.safetensors"))
+	assert.Equal(t, 0, c.GetMaxQueueLength("v1-5"))
+
+	c.ModelMaxQueueLength["v1-5"] = -1
+	assert.NotNil(t, c.check())
+	c.ModelMaxQueueLength["v1-5"] = 0
+	c.MaxQueueLength = -1
+	assert.NotNil(t, c.check())
+}
+
+func TestImageCompression(t *testing.T) {
+	c := &Config{ConfigYaml: ConfigYaml{ExtraArgs: DefaultExtraArgs}}
+	c.setDefaults()
+	assert.Nil(t, c.check())
+	assert.False(t, c.IsImageCompression())
+	assert.Equal(t, ImageCompressionBest, c.ImageCompressionLevel)
+
+	t.Setenv(IMAGE_COMPRESSION, ImageCompressionPng)
+	t.Setenv(IMAGE_COMPRESSION_LEVEL, ImageCompressionFast)
+	c.updateFromEnv()
+	assert.Nil(t, c.check())
+	assert.True(t, c.IsImageCompression())
+	assert.Equal(t, ImageCompressionFast, c.ImageCompressionLevel)
+
+	c.ImageCompressionLevel = "9"
+	assert.NotNil(t, c.check())
+	c.ImageCompressionLevel = ImageCompressionDefault
+	c.ImageCompression = "webp"
+	assert.NotNil(t, c.check())
+}
+
+func TestForwardHeaders(t *testing.T) {
+	c := &Config{ConfigYaml: ConfigYaml{ExtraArgs: DefaultExtraArgs}}
+	c.setDefaults()
+	assert.Nil(t, c.check())
+	assert.Equal(t, DefaultDenyHeaders, c.ForwardHeaders.Deny)
+	assert.Empty(t, c.ForwardHeaders.Allow)
+
+	c.ForwardHeaders = &ForwardHeaders{Allow: []string{"x-request-id"}, Deny: []string{}}
+	c.setDefaults()
+	assert.Nil(t, c.check())
+	assert.Equal(t, []string{"X-Request-Id"}, c.ForwardHeaders.Allow)
+	assert.Empty(t, c.ForwardHeaders.Deny)
+	c.ForwardHeaders.Deny = []string{"X-Bad: 1"}
+	assert.NotNil(t, c.check())
+}
+
+func TestRoutingFallbackEndpoint(t *testing.T) {
+	c := &Config{ConfigYaml: ConfigYaml{ExtraArgs: DefaultExtraArgs}}
+	c.setDefaults()
+	assert.Nil(t, c.check())
+	assert.Empty(t, c.RoutingFallbackEndpoint)
+
+	t.Setenv(ROUTING_FALLBACK, "http://127.0.0.1:7861")
+	c.updateFromEnv()
+	assert.Nil(t, c.check())
+	assert.Equal(t, "http://127.0.0.1:7861", c.RoutingFallbackEndpoint)
+	c.RoutingFallbackEndpoint = "127.0.0.1:7861"
+	assert.NotNil(t, c.check())
+}
+
+func TestRetention(t *testing.T) {
+	c := &Config{ConfigYaml: ConfigYaml{ExtraArgs: DefaultExtraArgs}}
+	c.setDefaults()
+	assert.Nil(t, c.check())
+	assert.False(t, c.EnableRetention())
+	assert.Equal(t, DefaultRetentionInterval, c.RetentionInterval)
+
+	t.Setenv(IMAGE_RETENTION_DAYS, "7")
+	t.Setenv(TASK_RETENTION_DAYS, "90")
+	c.updateFromEnv()
+	assert.Nil(t, c.check())
+	assert.True(t, c.EnableRetention())
+	assert.Equal(t, 7, c.ImageRetentionDays)
+	assert.Equal(t, 90, c.TaskRetentionDays)
+	c.TaskRetentionDays = -1
+	assert.NotNil(t, c.check())
+}
+
+func TestInitImageSource(t *testing.T) {
+	c := &Config{ConfigYaml: ConfigYaml{ExtraArgs: DefaultExtraArgs}}
+	c.setDefaults()
+	assert.Nil(t, c.check())
+	assert.Equal(t, InitImageBase64, c.InitImageSource)
+	assert.False(t, c.IsInitImageUrl())
+
+	t.Setenv(INIT_IMAGE_SOURCE, InitImageUrl)
+	c.updateFromEnv()
+	assert.Nil(t, c.check())
+	assert.True(t, c.IsInitImageUrl())
+	c.InitImageSource = "nas"
+	assert.NotNil(t, c.check())
+}
+
+func TestSdApiAuth(t *testing.T) {
+	c := &Config{ConfigYaml: ConfigYaml{ExtraArgs: "--api --api-auth old:pw --xformers",
+		ModelExtraArgs: map[string]string{"sd_xl.safetensors": "--medvram-sdxl"}}}
+	c.setDefaults()
+	assert.Nil(t, c.check())
+	_, _, ok := c.GetSdApiAuth()
+	assert.False(t, ok)
+	// credentials in extraArgs dropped with value
+	assert.Equal(t, "--api --xformers --nowebui", c.GetExtraArgs("v1-5"))
+
+	t.Setenv(SD_API_AUTH, "sdapi:secret")
+	c.updateFromEnv()
+	assert.Nil(t, c.check())
+	user, passwd, ok := c.GetSdApiAuth()
+	assert.True(t, ok)
+	assert.Equal(t, "sdapi", user)
+	assert.Equal(t, "secret", passwd)
+	// webui started with configured credentials only
+	for _, sdModel := range []string{"v1-5", "sd_xl.safetensors"} {
+		args := c.GetExtraArgs(sdModel)
+		assert.True(t, strings.HasSuffix(args, "--api-auth sdapi:secret"), args)
+		assert.Equal(t, 1, strings.Count(args, "--api-auth"), args)
+	}
+
+	for _, auth := range []string{"sdapi", ":secret", "sdapi:", "a:b c", "a:b,c:d"} {
+		c.SdApiAuth = auth
+		assert.NotNil(t, c.check(), auth)
+	}
+}
+
+func TestMaxUserTasks(t *testing.T) {
+	t.Setenv(MAX_USER_TASKS, "4")
+	c := &Config{ConfigYaml: ConfigYaml{ExtraArgs: DefaultExtraArgs,
+		UserMaxTasks: map[string]int{"admin": 0, "vip": 10}}}
+	c.updateFromEnv()
+	c.setDefaults()
+	assert.Nil(t, c.check())
+	assert.Equal(t, 4, c.GetMaxUserTasks("alice"))
+	assert.Equal(t, 0, c.GetMaxUserTasks("admin"))
+	assert.Equal(t, 10, c.GetMaxUserTasks("vip"))
+
+	c.UserMaxTasks["vip"] = -1
+	assert.NotNil(t, c.check())
+	c.UserMaxTasks["vip"] = 10
+	c.MaxUserTasks = -1
+	assert.NotNil(t, c.check())
+}
+
+func TestRequestLimits(t *testing.T) {
+	c := &Config{ConfigYaml: ConfigYaml{RequestLimits: map[string]*RequestLimit{
+		AllUsers: {MaxSteps: 50, MaxCfgScale: 15},
+		"strict": {MaxSteps: 30, Mode: RequestLimitReject},
+	}}}
+	c.setDefaults()
+	assert.Nil(t, c.check())
+	assert.Equal(t, int64(30), c.GetRequestLimit("strict").MaxSteps)
+	assert.True(t, c.GetRequestLimit("strict").IsReject())
+	assert.False(t, c.GetRequestLimit("user").IsReject())
+	assert.Equal(t, float32(15), c.GetRequestLimit("user").MaxCfgScale)
+
+	c.RequestLimits["bad"] = &RequestLimit{MaxSteps: -1}
+	assert.NotNil(t, c.check())
+	c.RequestLimits["bad"] = &RequestLimit{MaxSteps: 20, Mode: "drop"}
+	assert.NotNil(t, c.check())
+}
+
+func TestUploadAllowLists(t *testing.T) {
+	c := &Config{ConfigYaml: ConfigYaml{UploadAllowLists: map[string]*UploadAllowList{
+		UploadImage: {MimeTypes: []string{"Image/PNG"}, Extensions: []string{".PNG"}},
+		UploadModel: nil,
+	}}}
+	c.setDefaults()
+	assert.Nil(t, c.check())
+	assert.Equal(t, []string{"image/png"}, c.GetUploadAllowList(UploadImage).MimeTypes)
+	assert.Equal(t, []string{".png"}, c.GetUploadAllowList(UploadImage).Extensions)
+	assert.Nil(t, c.GetUploadAllowList(UploadModel))
+
+	c.UploadAllowLists[UploadModel] = &UploadAllowList{Extensions: []string{"safetensors"}}
+	assert.NotNil(t, c.check())
+	c.UploadAllowLists[UploadModel] = &UploadAllowList{MimeTypes: []string{"png"}}
+	assert.NotNil(t, c.check())
+	delete(c.UploadAllowLists, UploadModel)
+	c.UploadAllowLists["video"] = &UploadAllowList{}
+	assert.NotNil(t, c.check())
+}
+
+func TestOverrideSettingsKeys(t *testing.T) {
+	c := &Config{ConfigYaml: ConfigYaml{OverrideSettingsKeys: &OverrideSettingsKeys{
+		Known: []string{"CLIP_stop_at_last_layers"},
+	}}}
+	c.setDefaults()
+	assert.Nil(t, c.check())
+	assert.True(t, c.OverrideSettingsKeys.IsKnown("CLIP_stop_at_last_layers"))
+	assert.False(t, c.OverrideSettingsKeys.IsKnown("clip_stop_at_last_layers"))
+	assert.False(t, c.OverrideSettingsKeys.IsReject())
+
+	c.OverrideSettingsKeys.Mode = UnknownSettingReject
+	assert.Nil(t, c.check())
+	assert.True(t, c.OverrideSettingsKeys.IsReject())
+	c.OverrideSettingsKeys.Mode = "clamp"
+	assert.NotNil(t, c.check())
+	c.OverrideSettingsKeys = &OverrideSettingsKeys{}
+	assert.NotNil(t, c.check())
+}
+
+func TestRouteModelFields(t *testing.T) {
+	c := &Config{ConfigYaml: ConfigYaml{RouteModelFields: map[string]string{
+		"/reactor/image": "sd_model",
+		"/adetailer/*":   "args.0.model",
+		"/adetailer/v2*": "model",
+	}}}
+	c.setDefaults()
+	assert.Nil(t, c.check())
+	assert.Equal(t, "sd_model", c.GetRouteModelField("/reactor/image"))
+	assert.Equal(t, "args.0.model", c.GetRouteModelField("/adetailer/v1/detect"))
+	// longest prefix
+	assert.Equal(t, "model", c.GetRouteModelField("/adetailer/v2/detect"))
+	assert.Empty(t, c.GetRouteModelField("/reactor/image/x"))
+
+	c.RouteModelFields["reactor"] = "sd_model"
+	assert.NotNil(t, c.check())
+	delete(c.RouteModelFields, "reactor")
+	c.RouteModelFields["/reactor/face"] = ""
+	assert.NotNil(t, c.check())
+}
+
+func TestGpuMemoryGuard(t *testing.T) {
+	c := &Config{ConfigYaml: ConfigYaml{GpuMemoryGuard: map[string]*GpuMemoryCoefficient{
+		"xl":       {BaseMB: 9000, PerMegapixelMB: 3500},
+		"xl_turbo": {BaseMB: 8000},
+	}}}
+	c.setDefaults()
+	assert.Nil(t, c.check())
+	assert.Nil(t, c.GetGpuMemoryCoefficient("v1-5-pruned"))
+	assert.Equal(t, float64(9000), c.GetGpuMemoryCoefficient("SD_XL_base").BaseMB)
+	// longest keyword
+	assert.Equal(t, float64(8000), c.GetGpuMemoryCoefficient("sd_xl_turbo").BaseMB)
+
+	c.GpuMemoryGuard[AllModels] = &GpuMemoryCoefficient{BaseMB: 4000}
+	assert.Equal(t, float64(4000), c.GetGpuMemoryCoefficient("v1-5-pruned").BaseMB)
+	c.GpuMemoryGuard[AllModels].PerMegapixelMB = -1
+	assert.NotNil(t, c.check())
+}
+
+func TestDimensionPresets(t *testing.T) {
+	c := &Config{ConfigYaml: ConfigYaml{DimensionPresets: map[string]*DimensionPreset{
+		"portrait":     {Width: 512, Height: 768},
+		"landscape-xl": {Width: 1344, Height: 768, BatchSize: 2},
+	}}}
+	c.setDefaults()
+	assert.Nil(t, c.check())
+
+	c.DimensionPresets["odd"] = &DimensionPreset{Width: 500, Height: 512}
+	assert.NotNil(t, c.check())
+	c.DimensionPresets["odd"] = &DimensionPreset{Width: 8192}
+	assert.NotNil(t, c.check())
+	c.DimensionPresets["odd"] = &DimensionPreset{Width: 512, BatchSize: -1}
+	assert.NotNil(t, c.check())
+	c.DimensionPresets["odd"] = &DimensionPreset{}
+	assert.NotNil(t, c.check())
+	delete(c.DimensionPresets, "odd")
+	c.DimensionPresets["bad name"] = &DimensionPreset{Width: 512}
+	assert.NotNil(t, c.check())
+}
+
+func TestModelAutoRegister(t *testing.T) {
+	c := &Config{ConfigYaml: ConfigYaml{ModelAutoRegister: true}}
+	c.setDefaults()
+	assert.Equal(t, DefaultAutoRegisterTimeout*time.Second, c.GetModelAutoRegisterTimeout())
+	// oss dir required
+	assert.NotNil(t, c.check())
+	c.ModelAutoRegisterOssDir = "/"
+	assert.NotNil(t, c.check())
+	c.ModelAutoRegisterOssDir = "models/Stable-diffusion"
+	assert.Nil(t, c.check())
+}
+
+func TestModelTimeouts(t *testing.T) {
+	c := &Config{ConfigYaml: ConfigYaml{ModelTimeouts: map[string]int32{"sd_xl_base_1.0.safetensors": 1800}}}
+	c.setDefaults()
+	assert.Nil(t, c.check())
+	assert.Equal(t, int32(1800), c.GetFuncTimeout("sd_xl_base_1.0.safetensors"))
+	assert.Equal(t, int32(DefaultTimeout), c.GetFuncTimeout("v1-5-pruned"))
+
+	c.ModelTimeouts["slow"] = MaxFuncTimeout + 1
+	assert.NotNil(t, c.check())
+	c.ModelTimeouts["slow"] = 0
+	assert.NotNil(t, c.check())
+	delete(c.ModelTimeouts, "slow")
+	c.Timeout = MaxFuncTimeout + 1
+	assert.NotNil(t, c.check())
+}
+
+func TestModelDefaultsYaml(t *testing.T) {
+	c := &Config{ConfigYaml: ConfigYaml{ExtraArgs: DefaultExtraArgs, InstanceType: DefaultInstanceType}}
+	assert.Nil(t, yaml.Unmarshal([]byte(`
+modelDefaults:
+  sdxl.safetensors:
+    steps: 30
+    override_settings:
+      sd_vae: sdxl_vae.safetensors
+    styles:
+      - name: base
+`), &c.ConfigYaml))
+	c.setDefaults()
+	assert.Nil(t, c.check())
+	body, err := json.Marshal(c.ModelDefaults["sdxl.safetensors"])
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"steps":30,"override_settings":{"sd_vae":"sdxl_vae.safetensors"},"styles":[{"name":"base"}]}`,
+		string(body))
+}
@@ -15,6 +15,16 @@ const (
 	TASK_FAILED     = "failed"
 	TASK_QUEUE      = "waiting"
 	TASK_FINISH     = "succeeded"
+	TASK_CANCELLED  = "cancelled"
+	// webui succeeded without images
+	TASK_NO_OUTPUT = "no_output"
+	// some images of batch upload failed, uploaded images kept
+	TASK_PARTIAL_UPLOAD = "partial_upload"
+
+	// selftest status
+	SELFTEST_PASSED    = "passed"
+	SELFTEST_FAILED    = "failed"
+	SELFTEST_REFERENCE = "reference_created"
 
 	HTTPTIMEOUT = 10 * 60 * time.Second
 
@@ -42,6 +52,7 @@ const (
 	SD_VAE           = "sdVae"
 	LORA_MODEL       = "lora"
 	CONTORLNET_MODEL = "controlNet"
+	EMBEDDING_MODEL  = "embedding"
 )
 
 // sd api path
@@ -58,6 +69,12 @@ const (
 	IMG2IMG            = "/sdapi/v1/img2img"
 	PROGRESS           = "/sdapi/v1/progress"
 	EXTRAIMAGES        = "/sdapi/v1/extra-single-image"
+	EXTRABATCHIMAGES   = "/sdapi/v1/extra-batch-images"
+	INTERROGATE        = "/sdapi/v1/interrogate"
+	GET_SAMPLERS       = "/sdapi/v1/samplers"
+	GET_SCRIPTS        = "/sdapi/v1/scripts"
+	GET_EXTENSIONS     = "/sdapi/v1/extensions"
+	SYSINFO            = "/internal/sysinfo"
 )
 
 // ots
@@ -67,60 +84,156 @@ const (
 
 // env
 const (
-	ACCOUNT_ID              = "FC_ACCOUNT_ID"
-	ACCESS_KEY_ID           = "ALIBABA_CLOUD_ACCESS_KEY_ID"
-	ACCESS_KEY_SECRET       = "ALIBABA_CLOUD_ACCESS_KEY_SECRET"
-	ACCESS_KET_TOKEN        = "ALIBABA_CLOUD_SECURITY_TOKEN"
-	REGION                  = "FC_REGION"
-	SERVICE_NAME            = "FC_SERVICE_NAME"
-	OTS_ENDPOINT            = "OTS_ENDPOINT"
-	OTS_INSTANCE            = "OTS_INSTANCE"
-	OSS_ENDPOINT            = "OSS_ENDPOINT"
-	OSS_BUCKET              = "OSS_BUCKET"
-	OSS_PATH                = "OSS_PATH"
-	OSS_MODE                = "OSS_MODE"
-	LOGINSWITCH             = "LOGIN_SWITCH"
-	USER_LOCAL_MODEL        = "USE_LOCAL_MODEL"
-	SD_IMAGE                = "SD_IMAGE"
-	FLEX_MODE               = "FLEX_MODE"
-	EXPOSE_TO_USER          = "EXPOSE_TO_USER"
-	SERVER_NAME             = "SERVER_NAME"
-	DOWNSTREAM              = "DOWNSTREAM"
-	GPU_MEMORY_SIZE         = "GPU_MEMORY_SIZE"
-	COLD_START_CONCURRENCY  = "COLD_START_CONCURRENCY"
-	MODEL_COLD_START_SERIAL = "MODEL_COLD_START_SERIAL"
-	LOG_REMOTE_SERVICE      = "LOG_REMOTE_SERVICE"
-	FC_ACCOUNT_ID           = "FC_ACCOUNT_ID"
-	FC_FUNCTION_NAME        = "FC_FUNCTION_NAME"
-	ENABLE_COLLECT          = "ENABLE_COLLECT"
-	DISABLE_HF_CHECK        = "DISABLE_HF_CHECK"
-	CHECK_MODEL_LOAD        = "CHECK_MODEL_LOAD"
-	DISABLE_PROGRESS        = "DISABLE_PROGRESS"
+	ACCOUNT_ID               = "FC_ACCOUNT_ID"
+	ACCESS_KEY_ID            = "ALIBABA_CLOUD_ACCESS_KEY_ID"
+	ACCESS_KEY_SECRET        = "ALIBABA_CLOUD_ACCESS_KEY_SECRET"
+	ACCESS_KET_TOKEN         = "ALIBABA_CLOUD_SECURITY_TOKEN"
+	CREDENTIALS_URI          = "ALIBABA_CLOUD_CREDENTIALS_URI"
+	REGION                   = "FC_REGION"
+	SERVICE_NAME             = "FC_SERVICE_NAME"
+	OTS_ENDPOINT             = "OTS_ENDPOINT"
+	OTS_INSTANCE             = "OTS_INSTANCE"
+	OSS_ENDPOINT             = "OSS_ENDPOINT"
+	OSS_BUCKET               = "OSS_BUCKET"
+	OSS_MODEL_BUCKET         = "OSS_MODEL_BUCKET"
+	OSS_OUTPUT_BUCKET        = "OSS_OUTPUT_BUCKET"
+	OSS_PATH                 = "OSS_PATH"
+	OSS_MODE                 = "OSS_MODE"
+	LOGINSWITCH              = "LOGIN_SWITCH"
+	USER_LOCAL_MODEL         = "USE_LOCAL_MODEL"
+	SD_IMAGE                 = "SD_IMAGE"
+	FLEX_MODE                = "FLEX_MODE"
+	EXPOSE_TO_USER           = "EXPOSE_TO_USER"
+	SERVER_NAME              = "SERVER_NAME"
+	DOWNSTREAM               = "DOWNSTREAM"
+	GPU_MEMORY_SIZE          = "GPU_MEMORY_SIZE"
+	COLD_START_CONCURRENCY   = "COLD_START_CONCURRENCY"
+	MODEL_COLD_START_SERIAL  = "MODEL_COLD_START_SERIAL"
+	LOG_REMOTE_SERVICE       = "LOG_REMOTE_SERVICE"
+	LOG_BATCH_SIZE           = "LOG_BATCH_SIZE"
+	LOG_FLUSH_INTERVAL       = "LOG_FLUSH_INTERVAL"
+	LOG_QUEUE_SIZE           = "LOG_QUEUE_SIZE"
+	FC_ACCOUNT_ID            = "FC_ACCOUNT_ID"
+	FC_FUNCTION_NAME         = "FC_FUNCTION_NAME"
+	ENABLE_COLLECT           = "ENABLE_COLLECT"
+	DISABLE_HF_CHECK         = "DISABLE_HF_CHECK"
+	CHECK_MODEL_LOAD         = "CHECK_MODEL_LOAD"
+	DISABLE_PROGRESS         = "DISABLE_PROGRESS"
+	MAX_REQUEST_BODY_SIZE    = "MAX_REQUEST_BODY_SIZE"
+	OSS_UPLOAD_CONCURRENCY   = "OSS_UPLOAD_CONCURRENCY"
+	INSTANCE_TYPE_FALLBACKS  = "INSTANCE_TYPE_FALLBACKS"
+	CA_PORT                  = "CA_PORT"
+	COLD_START_BUDGET        = "COLD_START_BUDGET"
+	COLD_START_BUDGET_WINDOW = "COLD_START_BUDGET_WINDOW"
+	WEB_SERVER_MODE          = "WEB_SERVER_MODE"
+	ACCELERATION_TYPE        = "ACCELERATION_TYPE"
+	OSS_MULTIPART_THRESHOLD  = "OSS_MULTIPART_THRESHOLD"
+	DEFAULT_MODEL            = "DEFAULT_MODEL"
+	DELETED_MODEL_FALLBACK   = "DELETED_MODEL_FALLBACK"
+	RENDER_CACHE_TTL         = "RENDER_CACHE_TTL"
+	IMAGE_RETENTION_DAYS     = "IMAGE_RETENTION_DAYS"
+	TASK_RETENTION_DAYS      = "TASK_RETENTION_DAYS"
+	MAINTENANCE_MAX_DURATION = "MAINTENANCE_MAX_DURATION"
+	IMAGE_NAME_TEMPLATE      = "IMAGE_NAME_TEMPLATE"
+	WARM_POOL                = "WARM_POOL"
+	WARM_POOL_INTERVAL       = "WARM_POOL_INTERVAL"
+	LISTEN_MIN_INTERVAL      = "LISTEN_MIN_INTERVAL"
+	LISTEN_MAX_INTERVAL      = "LISTEN_MAX_INTERVAL"
+	DETECT_IMAGE_TYPE        = "DETECT_IMAGE_TYPE"
+	IMAGE_COMPRESSION        = "IMAGE_COMPRESSION"
+	SD_API_AUTH              = "SD_API_AUTH"
+	IMAGE_COMPRESSION_LEVEL  = "IMAGE_COMPRESSION_LEVEL"
+	INIT_IMAGE_SOURCE        = "INIT_IMAGE_SOURCE"
+	FUNC_REFRESH_INTERVAL    = "FUNC_REFRESH_INTERVAL"
+	PREDICT_TIMEOUT          = "PREDICT_TIMEOUT"
+	OSS_RETRY_ATTEMPTS       = "OSS_RETRY_ATTEMPTS"
+	INLINE_IMAGE_MAX_SIZE    = "INLINE_IMAGE_MAX_SIZE"
+	VALIDATE_PROMPT          = "VALIDATE_PROMPT"
+	MNS_ENDPOINT             = "MNS_ENDPOINT"
+	MNS_TOPIC                = "MNS_TOPIC"
+	MNS_QUEUE                = "MNS_QUEUE"
+	PREDICT_CONCURRENCY      = "PREDICT_CONCURRENCY"
+	PREDICT_QUEUE_TIMEOUT    = "PREDICT_QUEUE_TIMEOUT"
+	BREAKER_THRESHOLD        = "BREAKER_THRESHOLD"
+	BREAKER_COOLDOWN         = "BREAKER_COOLDOWN"
+	MAX_QUEUE_LENGTH         = "MAX_QUEUE_LENGTH"
+	ROUTING_FALLBACK         = "ROUTING_FALLBACK_ENDPOINT"
+	MAX_USER_TASKS           = "MAX_USER_TASKS"
+	SHARE_LINK_SECRET        = "SHARE_LINK_SECRET"
+	FAIL_ON_MISSING_SD_MODEL = "FAIL_ON_MISSING_SD_MODEL"
+	OSS_DOWNLOAD_CONCURRENCY = "OSS_DOWNLOAD_CONCURRENCY"
+	MODEL_AUTO_REGISTER      = "MODEL_AUTO_REGISTER"
 )
 
 // default value
 const (
-	DefaultSdPort              = "7860"
-	DefaultSdPath              = "/stable-diffusion-webui"
-	DefaultSdPathProxy         = "/mnt/auto/sd"
-	DefaultExtraArgs           = "--api"
-	DefaultSessionExpire       = 3600
-	DefaultLoginSwitch         = "off"       // value: off|on
-	DefaultUseLocalModel       = "yes"       // value: yes|no
-	DefaultFlexMode            = "multiFunc" // value: singleFunc|multiFunc
-	DefaultOssPath             = "/mnt/oss"
-	DefaultLogService          = "http://server-ai-backend-agwwspzdwb.cn-hangzhou.devsapp.net"
-	DefaultCaPort              = 7861
-	DefaultCpu                 = 8
-	DefaultDisk                = 512
-	DefaultInstanceConcurrency = 1
-	DefaultInstanceType        = "fc.gpu.tesla.1"
-	DefaultMemorySize          = 32768
-	DefaultGpuMemorySize       = 16384
-	DefaultTimeout             = 600
-	DefaultOssMode             = REMOTE
+	DefaultSdPort                = "7860"
+	DefaultSdPath                = "/stable-diffusion-webui"
+	DefaultSdPathProxy           = "/mnt/auto/sd"
+	DefaultExtraArgs             = "--api"
+	DefaultSessionExpire         = 3600
+	DefaultLoginSwitch           = "off"       // value: off|on
+	DefaultUseLocalModel         = "yes"       // value: yes|no
+	DefaultFlexMode              = "multiFunc" // value: singleFunc|multiFunc
+	DefaultOssPath               = "/mnt/oss"
+	DefaultLogService            = "http://server-ai-backend-agwwspzdwb.cn-hangzhou.devsapp.net"
+	DefaultLogBatchSize          = 64
+	DefaultLogFlushInterval      = 5 // second
+	DefaultLogQueueSize          = 4096
+	DefaultColdStartBudgetWindow = 60   // second
+	DefaultRenderCacheTTL        = 3600 // second
+	DefaultWarmPoolInterval      = 60   // second
+	DefaultImageNameTemplate     = "images/{user}/{taskId}_{index}.png"
+	DefaultCaPort                = 7861
+	DefaultCpu                   = 8
+	DefaultDisk                  = 512
+	DefaultInstanceConcurrency   = 1
+	DefaultInstanceType          = "fc.gpu.tesla.1"
+	DefaultMemorySize            = 32768
+	DefaultGpuMemorySize         = 16384
+	DefaultTimeout               = 600
+	MaxFuncTimeout               = 86400 // second, fc function max timeout
+	DefaultOssMode               = REMOTE
+	DefaultMaxRequestBodySize    = 64 // MB
+	DefaultOssUploadConcurrency  = 4
+	DefaultOssMultipartThreshold = 8 // MB
+	DefaultOssMultipartPartSize  = 1 << 20
+	DefaultListenMinInterval     = 200   // ms
+	DefaultListenMaxInterval     = 10000 // ms
+	DefaultFuncRefreshInterval   = 300   // second
+	DefaultOssRetryAttempts      = 3
+	DefaultInlineImageMaxSize    = 256 // KB
+	DefaultPredictQueueTimeout   = 60  // second
+	DefaultOssDownloadTimeout    = 30  // second
+	DefaultAutoRegisterTimeout   = 60  // second
+	DefaultBreakerCooldown       = 30  // second
+	DefaultUserStatsDays         = 30
+	DefaultRetentionInterval     = 3600   // second
+	DefaultShareLinkTTL          = 86400  // second
+	MaxShareLinkTTL              = 604800 // second
+	MinImageSize                 = 64
+	MaxImageSize                 = 4096
+	MaxBatchSize                 = 100
 )
 
+// request headers carry client credentials, not forwarded by default
+var DefaultDenyHeaders = []string{"Token", "Cookie", "Authorization", "Proxy-Authorization"}
+
+// per user config key apply to all users
+const AllUsers = "*"
+
+// per model config key apply to models not matched
+const AllModels = "*"
+
+// default model dir relative to sdPath
+var DefaultModelDirs = map[string]string{
+	SD_MODEL:         "models/Stable-diffusion",
+	SD_VAE:           "models/VAE",
+	LORA_MODEL:       "models/Lora",
+	CONTORLNET_MODEL: "models/ControlNet",
+	EMBEDDING_MODEL:  "embeddings",
+}
+
 // function http trigger
 const (
 	TRIGGER_TYPE         = "http"
@@ -135,12 +248,52 @@ const (
 	SD_START_PARAMS      = "EXTRA_ARGS"
 )
 
+// function container acceleration type
+const (
+	AccelerationDefault   = "Default"
+	AccelerationNone      = "None"
+	gpuInstanceTypePrefix = "fc.gpu."
+)
+
 // oss mode
 const (
 	LOCAL  = "local"
 	REMOTE = "remote"
 )
 
+// request limit mode
+const (
+	RequestLimitClamp  = "clamp"
+	RequestLimitReject = "reject"
+)
+
+// unknown override_settings key mode
+const (
+	UnknownSettingDrop   = "drop"
+	UnknownSettingReject = "reject"
+)
+
+// upload kind of uploadAllowLists
+const (
+	UploadImage = "image"
+	UploadModel = "model"
+)
+
+// img2img init image source passed to webui
+const (
+	InitImageBase64 = "base64"
+	InitImageUrl    = "url"
+)
+
+// image compression before upload
+const (
+	ImageCompressionNone    = "none"
+	ImageCompressionPng     = "png"
+	ImageCompressionFast    = "fast"
+	ImageCompressionDefault = "default"
+	ImageCompressionBest    = "best"
+)
+
 type FlexMode int32
 
 const (
@@ -163,3 +316,10 @@ const (
 	TrackerKeyStableDiffusionStartup = "stable_diffusion_startup"
 	FcRequestID                      = "x-fc-request-id"
 )
+
+// build info, inject by -ldflags "-X github.com/devsapp/serverless-stable-diffusion-api/pkg/config.Version=v1"
+var (
+	Version   = "dev"
+	Commit    = "unknown"
+	BuildTime = "unknown"
+)
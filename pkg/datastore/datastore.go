@@ -16,6 +16,8 @@ type Config struct {
 	PrimaryKeyColumnName string
 	TimeToAlive          int
 	MaxVersion           int
+	// map of indexed column name to columns readable by ListIndexRange
+	IndexColumns map[string][]string
 }
 
 type Datastore interface {
@@ -33,6 +35,16 @@ type Datastore interface {
 	// If the key does not exist, the returned map and error are both nil.
 	Get(key string, columns []string) (map[string]interface{}, error)
 
+	// CompareAndSwap updates the column values only if the current value of column equals expected.
+	// It takes a key, the condition column with its expected value, and a map of column names to values.
+	// It returns true if updated, false with nil error when the key does not exist or the value not match.
+	// Note: nil expected means the column is not set.
+	CompareAndSwap(key string, column string, expected interface{}, values map[string]interface{}) (bool, error)
+
+	// PutIfAbsent inserts the column values only if the key does not exist.
+	// It returns true if inserted, false with nil error when the key already exists.
+	PutIfAbsent(key string, values map[string]interface{}) (bool, error)
+
 	//Put(key string, value string) error
 	//Get(key string) (string, error)
 
@@ -46,6 +58,16 @@ type Datastore interface {
 	// Note: since it reads all data and store them in memory, so do not call this function on a large datastore.
 	ListAll(columns []string) (map[string]map[string]interface{}, error)
 
+	// ListRange read at most limit rows with primary key >= startKey in primary key order.
+	// It returns rows like ListAll and the start key of next range, which is empty when no more data.
+	// Note: empty startKey means read from the first row.
+	ListRange(startKey string, limit int, columns []string) (map[string]map[string]interface{}, string, error)
+
+	// ListIndexRange read rows with startValue <= value of indexed column <= endValue.
+	// It returns rows like ListAll, only columns of Config.IndexColumns readable.
+	// Note: empty endValue means no upper bound, do not call it on a large range.
+	ListIndexRange(column, startValue, endValue string, columns []string) (map[string]map[string]interface{}, error)
+
 	// Close close the datastore.
 	Close() error
 }
@@ -7,6 +7,17 @@ import (
 
 type DatastoreFactory struct{}
 
+// taskIndexColumns task rows read by status(active tasks) and create time(recent tasks)
+var taskIndexColumns = map[string][]string{
+	KTaskStatus:     {KTaskIdColumnName, KTaskUser, KTaskModel, KTaskStatus, KTaskCreateTime, KTaskGpuSeconds},
+	KTaskCreateTime: {KTaskIdColumnName, KTaskUser, KTaskModel, KTaskStatus, KTaskCreateTime, KTaskGpuSeconds},
+}
+
+// indexName name of index on column of table
+func indexName(tableName, column string) string {
+	return fmt.Sprintf("%s_%s_index", tableName, column)
+}
+
 func (f *DatastoreFactory) NewTable(dbType DatastoreType, tableName string) Datastore {
 	switch dbType {
 	case SQLite:
@@ -46,8 +57,17 @@ func NewSQLiteConfig(tableName string) *Config {
 			KTaskStatus:             "TEXT",
 			KTaskCreateTime:         "TEXT",
 			KTaskModifyTime:         "TEXT",
+			KTaskGpuSeconds:         "FLOAT",
+			KTaskEffectiveSettings:  "TEXT",
+			KTaskWebuiJobId:         "TEXT",
+			KTaskModel:              "TEXT",
+			KTaskLabels:             "TEXT",
+			KTaskImagesExpired:      "INT",
+			KTaskRequest:            "TEXT",
+			KTaskFailedImages:       "TEXT",
 		}
 		config.PrimaryKeyColumnName = KTaskIdColumnName
+		config.IndexColumns = taskIndexColumns
 	case KModelTableName:
 		config.ColumnConfig = map[string]string{
 			KModelName:       "TEXT PRIMARY KEY NOT NULL",
@@ -55,6 +75,7 @@ func NewSQLiteConfig(tableName string) *Config {
 			KModelOssPath:    "TEXT",
 			KModelEtag:       "TEXT",
 			KModelStatus:     "TEXT",
+			KModelLocalPath:  "TEXT",
 			KModelCreateTime: "TEXT",
 			KModelModifyTime: "TEXT",
 		}
@@ -69,6 +90,7 @@ func NewSQLiteConfig(tableName string) *Config {
 			KModelServiceCreateTime:     "TEXT",
 			KModelServiceLastModifyTime: "TEXT",
 			KModelServiceMessage:        "TEXT",
+			KModelServiceInstanceType:   "TEXT",
 		}
 		config.PrimaryKeyColumnName = KModelServiceKey
 	case KUserTableName:
@@ -118,8 +140,17 @@ func NewOtsConfig(tableName string) *Config {
 			KTaskStatus:             "TEXT",
 			KTaskCreateTime:         "TEXT",
 			KTaskModifyTime:         "TEXT",
+			KTaskGpuSeconds:         "FLOAT",
+			KTaskEffectiveSettings:  "TEXT",
+			KTaskWebuiJobId:         "TEXT",
+			KTaskModel:              "TEXT",
+			KTaskLabels:             "TEXT",
+			KTaskImagesExpired:      "INT",
+			KTaskRequest:            "TEXT",
+			KTaskFailedImages:       "TEXT",
 		}
 		config.PrimaryKeyColumnName = KTaskIdColumnName
+		config.IndexColumns = taskIndexColumns
 	case KModelTableName:
 		config.ColumnConfig = map[string]string{
 			KModelName:       "TEXT",
@@ -127,6 +158,7 @@ func NewOtsConfig(tableName string) *Config {
 			KModelOssPath:    "TEXT",
 			KModelEtag:       "TEXT",
 			KModelStatus:     "TEXT",
+			KModelLocalPath:  "TEXT",
 			KModelCreateTime: "TEXT",
 			KModelModifyTime: "TEXT",
 		}
@@ -141,6 +173,7 @@ func NewOtsConfig(tableName string) *Config {
 			KModelServiceCreateTime:     "TEXT",
 			KModelServiceLastModifyTime: "TEXT",
 			KModelServiceMessage:        "TEXT",
+			KModelServiceInstanceType:   "TEXT",
 		}
 		config.PrimaryKeyColumnName = KModelServiceKey
 	case KUserTableName:
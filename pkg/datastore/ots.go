@@ -1,17 +1,23 @@
 package datastore
 
 import (
+	"errors"
+	"fmt"
 	"github.com/aliyun/aliyun-tablestore-go-sdk/tablestore"
 	conf "github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
 	"sync"
 )
 
+// ots error code when row or column condition not met
+const otsConditionCheckFail = "OTSConditionCheckFail"
+
 var (
 	otsClient    *tablestore.TableStoreClient
 	once         sync.Once
 	strToOtsType = map[string]tablestore.DefinedColumnType{
-		"TEXT": tablestore.DefinedColumn_STRING,
-		"INT":  tablestore.DefinedColumn_INTEGER,
+		"TEXT":  tablestore.DefinedColumn_STRING,
+		"INT":   tablestore.DefinedColumn_INTEGER,
+		"FLOAT": tablestore.DefinedColumn_DOUBLE,
 	}
 )
 
@@ -40,6 +46,9 @@ func NewOtsDatastore(config *Config) (*OtsStore, error) {
 	}
 	// check table is exist or not
 	if tableInfo, err := otsClient.DescribeTable(describeTableRequest); err == nil && tableInfo.TableMeta != nil {
+		if err := ensureOtsIndexes(config, tableInfo.TableMeta, tableInfo.IndexMetas); err != nil {
+			return nil, err
+		}
 		return &OtsStore{config: config}, nil
 	}
 	// create table
@@ -63,9 +72,58 @@ func NewOtsDatastore(config *Config) (*OtsStore, error) {
 	if _, err := otsClient.CreateTable(createTableRequest); err != nil {
 		return nil, err
 	}
+	if err := ensureOtsIndexes(config, tableMeta, nil); err != nil {
+		return nil, err
+	}
 	return &OtsStore{config: config}, nil
 }
 
+// ensureOtsIndexes add defined columns and global indexes of config missing in table created by old version
+func ensureOtsIndexes(config *Config, tableMeta *tablestore.TableMeta, indexMetas []*tablestore.IndexMeta) error {
+	if len(config.IndexColumns) == 0 {
+		return nil
+	}
+	defined := make(map[string]struct{}, len(tableMeta.DefinedColumns))
+	for _, column := range tableMeta.DefinedColumns {
+		defined[column.Name] = struct{}{}
+	}
+	missing := make([]*tablestore.DefinedColumnSchema, 0)
+	for field, cate := range config.ColumnConfig {
+		if _, ok := defined[field]; !ok {
+			missing = append(missing, &tablestore.DefinedColumnSchema{Name: field, ColumnType: getOtsType(cate)})
+		}
+	}
+	if len(missing) > 0 {
+		if _, err := otsClient.AddDefinedColumn(&tablestore.AddDefinedColumnRequest{
+			TableName: config.TableName, DefinedColumns: missing}); err != nil {
+			return err
+		}
+	}
+	indexes := make(map[string]struct{}, len(indexMetas))
+	for _, meta := range indexMetas {
+		indexes[meta.IndexName] = struct{}{}
+	}
+	for column, covered := range config.IndexColumns {
+		name := indexName(config.TableName, column)
+		if _, ok := indexes[name]; ok {
+			continue
+		}
+		indexMeta := &tablestore.IndexMeta{IndexName: name}
+		indexMeta.AddPrimaryKeyColumn(column)
+		indexMeta.AddPrimaryKeyColumn(conf.COLPK)
+		for _, field := range covered {
+			if field != column {
+				indexMeta.AddDefinedColumn(field)
+			}
+		}
+		if _, err := otsClient.CreateIndex(&tablestore.CreateIndexRequest{MainTableName: config.TableName,
+			IndexMeta: indexMeta, IncludeBaseData: true}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (o *OtsStore) Get(key string, columns []string) (map[string]interface{}, error) {
 	getRowRequest := new(tablestore.GetRowRequest)
 	pk := new(tablestore.PrimaryKey)
@@ -110,6 +168,29 @@ func (o *OtsStore) Put(key string, datas map[string]interface{}) error {
 	return nil
 }
 
+func (o *OtsStore) PutIfAbsent(key string, datas map[string]interface{}) (bool, error) {
+	putRowRequest := new(tablestore.PutRowRequest)
+	putRowChange := new(tablestore.PutRowChange)
+	putRowChange.TableName = o.config.TableName
+	putPk := new(tablestore.PrimaryKey)
+	putPk.AddPrimaryKeyColumn(conf.COLPK, key)
+
+	putRowChange.PrimaryKey = putPk
+	for col, data := range datas {
+		putRowChange.AddColumn(col, data)
+	}
+	putRowChange.SetCondition(tablestore.RowExistenceExpectation_EXPECT_NOT_EXIST)
+	putRowRequest.PutRowChange = putRowChange
+	if _, err := otsClient.PutRow(putRowRequest); err != nil {
+		var otsErr *tablestore.OtsError
+		if errors.As(err, &otsErr) && otsErr.Code == otsConditionCheckFail {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
 func (o *OtsStore) Update(key string, datas map[string]interface{}) error {
 	updateRowRequest := new(tablestore.UpdateRowRequest)
 	updateRowChange := new(tablestore.UpdateRowChange)
@@ -128,6 +209,45 @@ func (o *OtsStore) Update(key string, datas map[string]interface{}) error {
 	return nil
 }
 
+func (o *OtsStore) CompareAndSwap(key string, column string, expected interface{},
+	datas map[string]interface{}) (bool, error) {
+	updateRowRequest := new(tablestore.UpdateRowRequest)
+	updateRowChange := new(tablestore.UpdateRowChange)
+	updateRowChange.TableName = o.config.TableName
+	updatePk := new(tablestore.PrimaryKey)
+	updatePk.AddPrimaryKeyColumn(conf.COLPK, key)
+	updateRowChange.PrimaryKey = updatePk
+	for col, data := range datas {
+		updateRowChange.PutColumn(col, data)
+	}
+	updateRowChange.SetCondition(tablestore.RowExistenceExpectation_EXPECT_EXIST)
+	if expected == nil {
+		// column missing pass both, existing value can't be equal and not equal to ""
+		missing := tablestore.NewCompositeColumnCondition(tablestore.LO_AND)
+		for _, comparator := range []tablestore.ComparatorType{tablestore.CT_EQUAL, tablestore.CT_NOT_EQUAL} {
+			condition := tablestore.NewSingleColumnCondition(column, comparator, "")
+			condition.FilterIfMissing = false
+			condition.LatestVersionOnly = true
+			missing.AddFilter(condition)
+		}
+		updateRowChange.SetColumnCondition(missing)
+	} else {
+		condition := tablestore.NewSingleColumnCondition(column, tablestore.CT_EQUAL, expected)
+		condition.FilterIfMissing = true
+		condition.LatestVersionOnly = true
+		updateRowChange.SetColumnCondition(condition)
+	}
+	updateRowRequest.UpdateRowChange = updateRowChange
+	if _, err := otsClient.UpdateRow(updateRowRequest); err != nil {
+		var otsErr *tablestore.OtsError
+		if errors.As(err, &otsErr) && otsErr.Code == otsConditionCheckFail {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
 func (o *OtsStore) Delete(key string) error {
 	deletePk := new(tablestore.PrimaryKey)
 	deletePk.AddPrimaryKeyColumn(conf.COLPK, key)
@@ -177,6 +297,95 @@ func (o *OtsStore) ListAll(columns []string) (map[string]map[string]interface{},
 	return resp, nil
 }
 
+func (o *OtsStore) ListRange(startKey string, limit int, columns []string) (
+	map[string]map[string]interface{}, string, error) {
+	startPK := new(tablestore.PrimaryKey)
+	if startKey == "" {
+		startPK.AddPrimaryKeyColumnWithMinValue(conf.COLPK)
+	} else {
+		startPK.AddPrimaryKeyColumn(conf.COLPK, startKey)
+	}
+	endPK := new(tablestore.PrimaryKey)
+	endPK.AddPrimaryKeyColumnWithMaxValue(conf.COLPK)
+
+	rangeRowQueryCriteria := &tablestore.RangeRowQueryCriteria{
+		TableName:       o.config.TableName,
+		StartPrimaryKey: startPK,
+		EndPrimaryKey:   endPK,
+		Direction:       tablestore.FORWARD,
+		MaxVersion:      1,
+		Limit:           int32(limit),
+		ColumnsToGet:    columns,
+	}
+	getRangeResp, err := otsClient.GetRange(&tablestore.GetRangeRequest{
+		RangeRowQueryCriteria: rangeRowQueryCriteria,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	resp := make(map[string]map[string]interface{})
+	for _, row := range getRangeResp.Rows {
+		result := make(map[string]interface{})
+		key := row.PrimaryKey.PrimaryKeys[0].Value.(string)
+		for _, col := range row.Columns {
+			result[col.ColumnName] = col.Value
+		}
+		resp[key] = result
+	}
+	nextKey := ""
+	if next := getRangeResp.NextStartPrimaryKey; next != nil && len(next.PrimaryKeys) > 0 {
+		nextKey, _ = next.PrimaryKeys[0].Value.(string)
+	}
+	return resp, nextKey, nil
+}
+
+func (o *OtsStore) ListIndexRange(column, startValue, endValue string, columns []string) (
+	map[string]map[string]interface{}, error) {
+	if _, ok := o.config.IndexColumns[column]; !ok {
+		return nil, fmt.Errorf("column %s of table %s not indexed", column, o.config.TableName)
+	}
+	startPK := new(tablestore.PrimaryKey)
+	startPK.AddPrimaryKeyColumn(column, startValue)
+	startPK.AddPrimaryKeyColumnWithMinValue(conf.COLPK)
+	endPK := new(tablestore.PrimaryKey)
+	if endValue == "" {
+		endPK.AddPrimaryKeyColumnWithMaxValue(column)
+	} else {
+		endPK.AddPrimaryKeyColumn(column, endValue)
+	}
+	endPK.AddPrimaryKeyColumnWithMaxValue(conf.COLPK)
+
+	resp := make(map[string]map[string]interface{})
+	for startPK != nil {
+		getRangeResp, err := otsClient.GetRange(&tablestore.GetRangeRequest{
+			RangeRowQueryCriteria: &tablestore.RangeRowQueryCriteria{
+				TableName:       indexName(o.config.TableName, column),
+				StartPrimaryKey: startPK,
+				EndPrimaryKey:   endPK,
+				Direction:       tablestore.FORWARD,
+				MaxVersion:      1,
+				Limit:           1000,
+				ColumnsToGet:    columns,
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range getRangeResp.Rows {
+			result := make(map[string]interface{})
+			// index primary key: indexed column, main table key
+			result[column] = row.PrimaryKey.PrimaryKeys[0].Value
+			key := row.PrimaryKey.PrimaryKeys[1].Value.(string)
+			for _, col := range row.Columns {
+				result[col.ColumnName] = col.Value
+			}
+			resp[key] = result
+		}
+		startPK = getRangeResp.NextStartPrimaryKey
+	}
+	return resp, nil
+}
+
 func (o *OtsStore) Close() error {
 	// do nothing
 	return nil
@@ -2,8 +2,9 @@ package datastore
 
 import (
 	"database/sql"
+	"database/sql/driver"
 	"fmt"
-	"reflect"
+	"sort"
 	"strings"
 
 	_ "github.com/mattn/go-sqlite3"
@@ -34,12 +35,55 @@ func NewSQLiteDatastore(config *Config) *SQLiteDatastore {
 	if err != nil {
 		panic(fmt.Errorf("failed to create table %s: %v", config.TableName, err))
 	}
+	// table created by old version, add new columns
+	if err := addMissingColumns(db, config); err != nil {
+		panic(fmt.Errorf("failed to add columns to table %s: %v", config.TableName, err))
+	}
+	for column := range config.IndexColumns {
+		if _, err := db.Exec(fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s (%s)",
+			indexName(config.TableName, column), config.TableName, column)); err != nil {
+			panic(fmt.Errorf("failed to create index of %s on table %s: %v", column, config.TableName, err))
+		}
+	}
 	return &SQLiteDatastore{
 		db:     db,
 		config: config,
 	}
 }
 
+func addMissingColumns(db *sql.DB, config *Config) error {
+	rows, err := db.Query(fmt.Sprintf("PRAGMA table_info(%s)", config.TableName))
+	if err != nil {
+		return err
+	}
+	existColumns := make(map[string]struct{})
+	for rows.Next() {
+		var (
+			cid        int
+			name, typ  string
+			notNull    int
+			defaultVal sql.NullString
+			pk         int
+		)
+		if err := rows.Scan(&cid, &name, &typ, &notNull, &defaultVal, &pk); err != nil {
+			rows.Close()
+			return err
+		}
+		existColumns[name] = struct{}{}
+	}
+	rows.Close()
+	for name, typ := range config.ColumnConfig {
+		if _, ok := existColumns[name]; ok {
+			continue
+		}
+		if _, err := db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", config.TableName, name,
+			typ)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (ds *SQLiteDatastore) Close() error {
 	return ds.db.Close()
 }
@@ -56,14 +100,20 @@ func (ds *SQLiteDatastore) Get(key string, columns []string) (map[string]interfa
 	for i, column := range columns {
 		// We use the type information stored in the Config to create a variable of the correct type.
 		var value interface{}
-		switch ds.config.ColumnConfig[column] {
+		// Only the type name matters, e.g. primary key column "TEXT PRIMARY KEY NOT NULL".
+		columnType := strings.ToUpper(ds.config.ColumnConfig[column])
+		if fields := strings.Fields(columnType); len(fields) > 0 {
+			columnType = fields[0]
+		}
+		switch columnType {
+		// Use nullable types, columns added later are NULL in old rows.
 		case "TEXT":
-			value = new(string)
+			value = new(sql.NullString)
 		case "INT":
 			// For simplicity, we use int64 for all integers.
-			value = new(int64)
+			value = new(sql.NullInt64)
 		case "FLOAT":
-			value = new(float64)
+			value = new(sql.NullFloat64)
 		default:
 			// If the column type is not supported, we return an error.
 			return nil, fmt.Errorf("unsupported column type: %s", ds.config.ColumnConfig[column])
@@ -81,12 +131,16 @@ func (ds *SQLiteDatastore) Get(key string, columns []string) (map[string]interfa
 		return nil, err
 	}
 
-	// Prepare the result map and fill it with values.
+	// Prepare the result map and fill it with values, NULL columns are omitted like ots.
 	result := make(map[string]interface{})
 	for i, column := range columns {
-		// We use the reflect package to dereference the pointer.
-		value := reflect.ValueOf(values[i]).Elem().Interface()
-		result[column] = value
+		value, err := values[i].(driver.Valuer).Value()
+		if err != nil {
+			return nil, err
+		}
+		if value != nil {
+			result[column] = value
+		}
 	}
 
 	return result, nil
@@ -111,6 +165,32 @@ func (ds *SQLiteDatastore) Put(key string, values map[string]interface{}) error
 	return err
 }
 
+func (ds *SQLiteDatastore) PutIfAbsent(key string, values map[string]interface{}) (bool, error) {
+	columns := []string{ds.config.PrimaryKeyColumnName}
+	placeholders := []string{"?"}
+	args := []interface{}{key}
+	for column, value := range values {
+		columns = append(columns, column)
+		placeholders = append(placeholders, "?")
+		args = append(args, value)
+	}
+	query := fmt.Sprintf(
+		"INSERT OR IGNORE INTO %s (%s) VALUES (%s)",
+		ds.config.TableName,
+		strings.Join(columns, ", "),
+		strings.Join(placeholders, ", "),
+	)
+	result, err := ds.db.Exec(query, args...)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
 func (ds *SQLiteDatastore) Update(key string, values map[string]interface{}) error {
 	columns := make([]string, 0)
 	args := make([]interface{}, 0)
@@ -129,6 +209,34 @@ func (ds *SQLiteDatastore) Update(key string, values map[string]interface{}) err
 	return err
 }
 
+func (ds *SQLiteDatastore) CompareAndSwap(key string, column string, expected interface{},
+	values map[string]interface{}) (bool, error) {
+	columns := make([]string, 0)
+	args := make([]interface{}, 0)
+	for col, value := range values {
+		columns = append(columns, fmt.Sprintf("%s=?", col))
+		args = append(args, value)
+	}
+	// single statement is atomic, "IS ?" match NULL when expected nil
+	args = append(args, key, expected)
+	query := fmt.Sprintf(
+		"UPDATE %s SET %s WHERE %s = ? AND %s IS ?",
+		ds.config.TableName,
+		strings.Join(columns, ", "),
+		ds.config.PrimaryKeyColumnName,
+		column,
+	)
+	result, err := ds.db.Exec(query, args...)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
 func (ds *SQLiteDatastore) Delete(key string) error {
 	_, err := ds.db.Exec(
 		fmt.Sprintf(
@@ -142,6 +250,52 @@ func (ds *SQLiteDatastore) ListAll(columns []string) (map[string]map[string]inte
 	if err != nil {
 		return nil, err
 	}
+	return ds.scanRows(rows)
+}
+
+func (ds *SQLiteDatastore) ListRange(startKey string, limit int, columns []string) (
+	map[string]map[string]interface{}, string, error) {
+	// read one more row as next start key
+	rows, err := ds.db.Query(fmt.Sprintf("SELECT %s FROM %s WHERE %s >= ? ORDER BY %s LIMIT ?",
+		strings.Join(columns, ","), ds.config.TableName, ds.config.PrimaryKeyColumnName,
+		ds.config.PrimaryKeyColumnName), startKey, limit+1)
+	if err != nil {
+		return nil, "", err
+	}
+	results, err := ds.scanRows(rows)
+	if err != nil || len(results) <= limit {
+		return results, "", err
+	}
+	keys := make([]string, 0, len(results))
+	for key := range results {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	nextKey := keys[len(keys)-1]
+	delete(results, nextKey)
+	return results, nextKey, nil
+}
+
+func (ds *SQLiteDatastore) ListIndexRange(column, startValue, endValue string, columns []string) (
+	map[string]map[string]interface{}, error) {
+	if _, ok := ds.config.IndexColumns[column]; !ok {
+		return nil, fmt.Errorf("column %s of table %s not indexed", column, ds.config.TableName)
+	}
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE %s >= ?", strings.Join(columns, ","), ds.config.TableName,
+		column)
+	args := []interface{}{startValue}
+	if endValue != "" {
+		query += fmt.Sprintf(" AND %s <= ?", column)
+		args = append(args, endValue)
+	}
+	rows, err := ds.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return ds.scanRows(rows)
+}
+
+func (ds *SQLiteDatastore) scanRows(rows *sql.Rows) (map[string]map[string]interface{}, error) {
 	defer rows.Close()
 
 	cols, err := rows.Columns()
@@ -1,6 +1,8 @@
 package datastore
 
 import (
+	"path/filepath"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -135,3 +137,205 @@ func TestListAll(t *testing.T) {
 	assert.Equal(t, 0, len(result))
 
 }
+
+func TestListRange(t *testing.T) {
+	primaryKeyColumnName := "primaryKey"
+	config := &Config{
+		DBName:    ":memory:", // the memory database for testing purposes
+		TableName: "TestListRange",
+		ColumnConfig: map[string]string{
+			primaryKeyColumnName: "text primary key not null",
+			"value":              "text",
+		},
+		PrimaryKeyColumnName: primaryKeyColumnName,
+	}
+	ds := NewSQLiteDatastore(config)
+	defer ds.Close()
+	for _, k := range []string{"key1", "key2", "key3"} {
+		assert.NoError(t, ds.Put(k, map[string]interface{}{"value": k}))
+	}
+
+	// first range
+	result, nextKey, err := ds.ListRange("", 2, []string{primaryKeyColumnName, "value"})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(result))
+	assert.Equal(t, "key1", result["key1"]["value"])
+	assert.Contains(t, result, "key2")
+	assert.Equal(t, "key3", nextKey)
+
+	// last range, no next key
+	result, nextKey, err = ds.ListRange(nextKey, 2, []string{primaryKeyColumnName, "value"})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(result))
+	assert.Contains(t, result, "key3")
+	assert.Equal(t, "", nextKey)
+}
+
+func TestListIndexRange(t *testing.T) {
+	primaryKeyColumnName := "primaryKey"
+	config := &Config{
+		DBName:    ":memory:",
+		TableName: "TestListIndexRange",
+		ColumnConfig: map[string]string{
+			primaryKeyColumnName: "text primary key not null",
+			"status":             "text",
+			"createTime":         "text",
+		},
+		PrimaryKeyColumnName: primaryKeyColumnName,
+		IndexColumns:         map[string][]string{"status": {primaryKeyColumnName, "status", "createTime"}},
+	}
+	ds := NewSQLiteDatastore(config)
+	defer ds.Close()
+	rows := map[string][2]string{"key1": {"running", "100"}, "key2": {"waiting", "200"}, "key3": {"succeeded", "300"}}
+	for key, row := range rows {
+		assert.NoError(t, ds.Put(key, map[string]interface{}{"status": row[0], "createTime": row[1]}))
+	}
+
+	// equal value
+	result, err := ds.ListIndexRange("status", "waiting", "waiting", []string{primaryKeyColumnName, "createTime"})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(result))
+	assert.Equal(t, "200", result["key2"]["createTime"])
+
+	// no upper bound
+	result, err = ds.ListIndexRange("status", "succeeded", "", []string{primaryKeyColumnName})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(result))
+	assert.Contains(t, result, "key2")
+	assert.Contains(t, result, "key3")
+
+	// column not indexed
+	_, err = ds.ListIndexRange("createTime", "100", "", []string{primaryKeyColumnName})
+	assert.Error(t, err)
+}
+
+func TestSQLiteAddMissingColumns(t *testing.T) {
+	dbName := filepath.Join(t.TempDir(), "sqlite3")
+	config := &Config{
+		DBName:    dbName,
+		TableName: "TestSQLiteAddMissingColumns",
+		ColumnConfig: map[string]string{
+			"primaryKey": "TEXT primary key not null",
+			"value":      "TEXT",
+		},
+		PrimaryKeyColumnName: "primaryKey",
+	}
+	ds := NewSQLiteDatastore(config)
+	assert.NoError(t, ds.Put("key", map[string]interface{}{"value": "old"}))
+	ds.Close()
+
+	// new version add column
+	config.ColumnConfig["newCol"] = "TEXT"
+	ds = NewSQLiteDatastore(config)
+	defer ds.Close()
+	assert.NoError(t, ds.Put("key", map[string]interface{}{"value": "new", "newCol": "newVal"}))
+	result, err := ds.Get("key", []string{"value", "newCol"})
+	assert.NoError(t, err)
+	assert.Equal(t, "newVal", result["newCol"].(string))
+
+	// primary key column readable
+	result, err = ds.Get("key", []string{"primaryKey"})
+	assert.NoError(t, err)
+	assert.Equal(t, "key", result["primaryKey"])
+
+	// old row column NULL, omitted
+	assert.NoError(t, ds.Put("old", map[string]interface{}{"value": "old"}))
+	result, err = ds.Get("old", []string{"value", "newCol"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"value": "old"}, result)
+}
+
+func TestSQLiteCompareAndSwap(t *testing.T) {
+	// file db, memory db per connection
+	config := &Config{
+		DBName:    filepath.Join(t.TempDir(), "sqlite3"),
+		TableName: "TestSQLiteCompareAndSwap",
+		ColumnConfig: map[string]string{
+			"primaryKey": "TEXT primary key not null",
+			"value":      "TEXT",
+			"counter":    "INT",
+		},
+		PrimaryKeyColumnName: "primaryKey",
+	}
+	ds := NewSQLiteDatastore(config)
+	defer ds.Close()
+
+	// key not exist
+	swapped, err := ds.CompareAndSwap("key", "value", nil, map[string]interface{}{"value": "v1"})
+	assert.NoError(t, err)
+	assert.False(t, swapped)
+
+	// nil expected match column not set
+	assert.NoError(t, ds.Put("key", map[string]interface{}{"counter": 0}))
+	swapped, err = ds.CompareAndSwap("key", "value", nil, map[string]interface{}{"value": "v1"})
+	assert.NoError(t, err)
+	assert.True(t, swapped)
+	swapped, err = ds.CompareAndSwap("key", "value", nil, map[string]interface{}{"value": "v2"})
+	assert.NoError(t, err)
+	assert.False(t, swapped)
+
+	// value not match
+	swapped, err = ds.CompareAndSwap("key", "value", "v0", map[string]interface{}{"value": "v2"})
+	assert.NoError(t, err)
+	assert.False(t, swapped)
+	swapped, err = ds.CompareAndSwap("key", "value", "v1", map[string]interface{}{"value": "v2"})
+	assert.NoError(t, err)
+	assert.True(t, swapped)
+	result, err := ds.Get("key", []string{"value"})
+	assert.NoError(t, err)
+	assert.Equal(t, "v2", result["value"])
+
+	// concurrent increase, no lost update
+	const workers, times = 8, 10
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < times; {
+				result, err := ds.Get("key", []string{"counter"})
+				if !assert.NoError(t, err) {
+					return
+				}
+				counter := result["counter"].(int64)
+				swapped, err := ds.CompareAndSwap("key", "counter", counter,
+					map[string]interface{}{"counter": counter + 1})
+				if !assert.NoError(t, err) {
+					return
+				}
+				if swapped {
+					j++
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	result, err = ds.Get("key", []string{"counter"})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(workers*times), result["counter"])
+}
+
+func TestSQLitePutIfAbsent(t *testing.T) {
+	config := &Config{
+		DBName:    filepath.Join(t.TempDir(), "sqlite3"),
+		TableName: "TestSQLitePutIfAbsent",
+		ColumnConfig: map[string]string{
+			"primaryKey": "TEXT primary key not null",
+			"value":      "TEXT",
+		},
+		PrimaryKeyColumnName: "primaryKey",
+	}
+	ds := NewSQLiteDatastore(config)
+	defer ds.Close()
+
+	put, err := ds.PutIfAbsent("key", map[string]interface{}{"value": "v1"})
+	assert.NoError(t, err)
+	assert.True(t, put)
+	// existing row kept
+	put, err = ds.PutIfAbsent("key", map[string]interface{}{"value": "v2"})
+	assert.NoError(t, err)
+	assert.False(t, put)
+	result, err := ds.Get("key", []string{"value"})
+	assert.NoError(t, err)
+	assert.Equal(t, "v1", result["value"])
+}
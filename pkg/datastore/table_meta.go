@@ -9,6 +9,7 @@ const (
 	KModelServiceEndPoint       = "END_POINT"
 	KModelServerImage           = "IMAGE"
 	KModelServiceMessage        = "MESSAGE"
+	KModelServiceInstanceType   = "INSTANCE_TYPE"
 	KModelServiceCreateTime     = "FUNC_CREATE_TIME"
 	KModelServiceLastModifyTime = "FUNC_LAST_MODIFY_TIME"
 )
@@ -40,6 +41,14 @@ const (
 	KTaskStatus             = "TASK_STATUS"
 	KTaskCreateTime         = "TASK_CREATE_TIME"
 	KTaskModifyTime         = "TASK_MODIFY_TIME"
+	KTaskGpuSeconds         = "TASK_GPU_SECONDS"
+	KTaskEffectiveSettings  = "TASK_EFFECTIVE_SETTINGS"
+	KTaskWebuiJobId         = "TASK_WEBUI_JOB_ID"
+	KTaskModel              = "TASK_MODEL"
+	KTaskLabels             = "TASK_LABELS"
+	KTaskImagesExpired      = "TASK_IMAGES_EXPIRED"
+	KTaskRequest            = "TASK_REQUEST"
+	KTaskFailedImages       = "TASK_FAILED_IMAGES"
 )
 
 // user table
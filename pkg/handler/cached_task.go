@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/datastore"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/models"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"strconv"
+)
+
+const (
+	cachedTaskPrefix = "cachedTask"
+	// txt2img submit in get or create mode
+	cachedSubmitKey = "cachedSubmit"
+)
+
+// cachedTaskItem user request hash -> task of /txt2img/cached
+type cachedTaskItem struct {
+	TaskId string `json:"taskId"`
+	// sd model and vae, item invalid once updated
+	Models     []string `json:"models"`
+	CreateTime int64    `json:"createTime"`
+}
+
+func cachedTaskStoreKey(username, hash string) string {
+	return fmt.Sprintf("%s_%s_%s", cachedTaskPrefix, username, hash)
+}
+
+// Txt2ImgCached get or create, return finished task of identical params or submit as txt2img
+// (POST /txt2img/cached)
+func (p *ProxyHandler) Txt2ImgCached(c *gin.Context) {
+	c.Set(cachedSubmitKey, true)
+	p.Txt2Img(c)
+}
+
+// cachedRequestHash hash of get or create request, "" when not cached submit,
+// error when seed random since result not reproducible
+func cachedRequestHash(c *gin.Context, path string, request interface{}) (string, error) {
+	if !c.GetBool(cachedSubmitKey) {
+		return "", nil
+	}
+	hash := requestHash(path, request)
+	if hash == "" {
+		return "", fmt.Errorf("seed need fixed, subseed too when subseed_strength > 0, -1 not allowed")
+	}
+	return hash, nil
+}
+
+// getCachedTask finished task of user request hash in ttl and models not updated since, "" if not found
+func (p *ProxyHandler) getCachedTask(username, hash string) string {
+	key := cachedTaskStoreKey(username, hash)
+	data, err := p.configStore.Get(key, []string{datastore.KConfigVal})
+	if err != nil || len(data) == 0 {
+		return ""
+	}
+	val, _ := data[datastore.KConfigVal].(string)
+	item := new(cachedTaskItem)
+	if err := json.Unmarshal([]byte(val), item); err != nil || item.TaskId == "" {
+		return ""
+	}
+	if utils.TimestampS()-item.CreateTime > int64(config.ConfigGlobal.RenderCacheTTL) ||
+		p.modelsUpdatedSince(item.Models, item.CreateTime) {
+		p.configStore.Delete(key)
+		return ""
+	}
+	task, err := p.taskStore.Get(item.TaskId, []string{datastore.KTaskStatus, datastore.KTaskCode,
+		datastore.KTaskImagesExpired})
+	if err != nil || task[datastore.KTaskStatus] != config.TASK_FINISH || imagesExpired(task) {
+		return ""
+	}
+	if code, ok := task[datastore.KTaskCode].(int64); !ok || code != requestOk {
+		return ""
+	}
+	return item.TaskId
+}
+
+// modelsUpdatedSince registered model updated or deleted at or after timestamp,
+// same second counted since order unknown
+func (p *ProxyHandler) modelsUpdatedSince(modelNames []string, timestamp int64) bool {
+	if p.modelStore == nil {
+		return false
+	}
+	for _, name := range modelNames {
+		data, err := p.modelStore.Get(name, []string{datastore.KModelStatus, datastore.KModelModifyTime})
+		if err != nil || len(data) == 0 {
+			continue
+		}
+		if data[datastore.KModelStatus] == config.MODEL_DELETE {
+			return true
+		}
+		modifyTime, _ := data[datastore.KModelModifyTime].(string)
+		if ts, err := strconv.ParseInt(modifyTime, 10, 64); err == nil && ts >= timestamp {
+			return true
+		}
+	}
+	return false
+}
+
+// putCachedTask record user request hash -> taskId, cover old item which already missed
+func (p *ProxyHandler) putCachedTask(username, hash, taskId string, modelNames []string) {
+	val, _ := json.Marshal(cachedTaskItem{TaskId: taskId, Models: modelNames, CreateTime: utils.TimestampS()})
+	if err := p.configStore.Put(cachedTaskStoreKey(username, hash), map[string]interface{}{
+		datastore.KConfigVal:        string(val),
+		datastore.KConfigModifyTime: fmt.Sprintf("%d", utils.TimestampS()),
+	}); err != nil {
+		logrus.WithFields(logrus.Fields{"taskId": taskId}).Warnf("put cached task err=%s", err.Error())
+	}
+}
+
+// replyCachedTask reply existed finished task of user request hash
+// return false when not found, caller submit as usual
+func (p *ProxyHandler) replyCachedTask(c *gin.Context, username, hash string) bool {
+	taskId := p.getCachedTask(username, hash)
+	if taskId == "" {
+		return false
+	}
+	result, err := p.getTaskResult(taskId)
+	if err != nil || result.Status != config.TASK_FINISH {
+		return false
+	}
+	logrus.WithFields(logrus.Fields{"taskId": taskId}).Info("get or create hit finished task")
+	c.Writer.Header().Set("taskId", taskId)
+	c.JSON(http.StatusOK, models.SubmitTaskResponse{
+		TaskId:     taskId,
+		Status:     config.TASK_FINISH,
+		OssUrl:     result.OssUrl,
+		InvokeMode: utils.String(invokeModeSync),
+		Cached:     utils.Bool(true),
+	})
+	return true
+}
+
+// cachedTaskModels sd model and vae of request, item invalid once any updated
+func cachedTaskModels(sdModel string, sdVae *string) []string {
+	modelNames := []string{sdModel}
+	if sdVae != nil && *sdVae != "" && *sdVae != "None" && *sdVae != "Automatic" {
+		modelNames = append(modelNames, *sdVae)
+	}
+	return modelNames
+}
@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/datastore"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/models"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTxt2ImgCached(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	initTestConfig(t)
+	config.ConfigGlobal.ServerName = config.PROXY
+	config.ConfigGlobal.ImageNameTemplate = config.DefaultImageNameTemplate
+	config.ConfigGlobal.RenderCacheTTL = config.DefaultRenderCacheTTL
+	mockOss(t, 0)
+	var renders int32
+	sd := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&renders, 1)
+		w.Write([]byte(`{"images":["aW1hZ2U="],"info":"{}"}`))
+	}))
+	defer sd.Close()
+	config.ConfigGlobal.SdUrlPrefix = sd.URL
+	taskStore := datastore.NewSQLiteDatastore(datastore.NewSQLiteConfig(datastore.KTaskTableName))
+	defer taskStore.Close()
+	configStore := datastore.NewSQLiteDatastore(datastore.NewSQLiteConfig(datastore.KConfigTableName))
+	defer configStore.Close()
+	modelStore := datastore.NewSQLiteDatastore(datastore.NewSQLiteConfig(datastore.KModelTableName))
+	defer modelStore.Close()
+	p := &ProxyHandler{taskStore: taskStore, configStore: configStore, modelStore: modelStore,
+		httpClient: &http.Client{}}
+	submit := func(handle gin.HandlerFunc, user, body string) (int, *models.SubmitTaskResponse) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/txt2img/cached", strings.NewReader(body))
+		c.Request.Header.Set(userKey, user)
+		handle(c)
+		resp := new(models.SubmitTaskResponse)
+		json.Unmarshal(w.Body.Bytes(), resp)
+		return w.Code, resp
+	}
+	request := func(seed int) string {
+		return fmt.Sprintf(`{"stable_diffusion_model":"sd.safetensors","prompt":"cat","seed":%d}`, seed)
+	}
+
+	// random seed not reproducible
+	code, _ := submit(p.Txt2ImgCached, "user", request(-1))
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _ = submit(p.Txt2ImgCached, "user", `{"stable_diffusion_model":"sd.safetensors","prompt":"cat"}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, int32(0), atomic.LoadInt32(&renders))
+
+	code, first := submit(p.Txt2ImgCached, "user", request(1))
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, config.TASK_FINISH, first.Status)
+	assert.Nil(t, first.Cached)
+	code, hit := submit(p.Txt2ImgCached, "user", request(1))
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, first.TaskId, hit.TaskId)
+	assert.True(t, *hit.Cached)
+	assert.Equal(t, []string{"http://oss/images/user/" + first.TaskId + "_1.png"}, *hit.OssUrl)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&renders))
+
+	// other params, other user and normal submit render again
+	_, other := submit(p.Txt2ImgCached, "user", request(2))
+	assert.Nil(t, other.Cached)
+	_, other = submit(p.Txt2ImgCached, "other", request(1))
+	assert.Nil(t, other.Cached)
+	_, other = submit(p.Txt2Img, "user", request(1))
+	assert.Nil(t, other.Cached)
+	assert.NotEqual(t, first.TaskId, other.TaskId)
+	assert.Equal(t, int32(4), atomic.LoadInt32(&renders))
+
+	// model updated, cached task invalid
+	assert.Nil(t, modelStore.Put("sd.safetensors", map[string]interface{}{
+		datastore.KModelName:       "sd.safetensors",
+		datastore.KModelStatus:     config.MODEL_LOADED,
+		datastore.KModelModifyTime: fmt.Sprintf("%d", utils.TimestampS()),
+	}))
+	_, rerender := submit(p.Txt2ImgCached, "user", request(1))
+	assert.Nil(t, rerender.Cached)
+	assert.NotEqual(t, first.TaskId, rerender.TaskId)
+	assert.Equal(t, int32(5), atomic.LoadInt32(&renders))
+}
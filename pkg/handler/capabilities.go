@@ -0,0 +1,192 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/models"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	capabilitiesTTL = 5 * time.Minute
+	// sd unreachable, requests in ttl not query it again
+	capabilitiesFailTTL = 30 * time.Second
+	// capabilities query of cold or wedged sd not block request long
+	capabilitiesTimeout = 5 * time.Second
+)
+
+// feature -> keyword of script or extension name
+var capabilityFeatures = map[string]string{
+	"controlnet": "controlnet",
+	"refiner":    "refiner",
+	"adetailer":  "adetailer",
+}
+
+type capabilitiesItem struct {
+	capabilities *models.SdCapabilities
+	// sampler names and aliases webui accept
+	samplers map[string]bool
+	// query fail, cached capabilitiesFailTTL
+	err     error
+	expired time.Time
+}
+
+// capabilitiesCache sd capabilities cache, key=endpoint
+type capabilitiesCache struct {
+	lock  sync.Mutex
+	items map[string]*capabilitiesItem
+}
+
+var capabilitiesCacheGlobal = &capabilitiesCache{
+	items: make(map[string]*capabilitiesItem),
+}
+
+func (cc *capabilitiesCache) get(endpoint string) *capabilitiesItem {
+	cc.lock.Lock()
+	defer cc.lock.Unlock()
+	if item, ok := cc.items[endpoint]; ok && time.Now().Before(item.expired) {
+		return item
+	}
+	delete(cc.items, endpoint)
+	return nil
+}
+
+func (cc *capabilitiesCache) put(endpoint string, item *capabilitiesItem, ttl time.Duration) {
+	cc.lock.Lock()
+	defer cc.lock.Unlock()
+	item.expired = time.Now().Add(ttl)
+	cc.items[endpoint] = item
+}
+
+// get capabilities item of endpoint, read cache first, failure cached briefly
+func getCapabilitiesItem(httpClient *http.Client, endpoint string) (*capabilitiesItem, error) {
+	if item := capabilitiesCacheGlobal.get(endpoint); item != nil {
+		return item, item.err
+	}
+	item, err := fetchCapabilities(&http.Client{Transport: httpClient.Transport, Timeout: capabilitiesTimeout},
+		endpoint)
+	if err != nil {
+		capabilitiesCacheGlobal.put(endpoint, &capabilitiesItem{err: err}, capabilitiesFailTTL)
+		return nil, err
+	}
+	capabilitiesCacheGlobal.put(endpoint, item, capabilitiesTTL)
+	return item, nil
+}
+
+// get capabilities of endpoint, read cache first
+func getCapabilities(httpClient *http.Client, endpoint string) (*models.SdCapabilities, error) {
+	item, err := getCapabilitiesItem(httpClient, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	return item.capabilities, nil
+}
+
+// validateSamplers check request samplers supported by sd of endpoint request routed to
+// capabilities unavailable skip check, predict report error as before
+func validateSamplers(httpClient *http.Client, endpoint string, samplers ...*string) error {
+	requested := make([]string, 0, len(samplers))
+	for _, sampler := range samplers {
+		if sampler != nil && *sampler != "" {
+			requested = append(requested, *sampler)
+		}
+	}
+	if len(requested) == 0 || endpoint == "" {
+		return nil
+	}
+	item, err := getCapabilitiesItem(httpClient, endpoint)
+	if err != nil {
+		logrus.Warnf("get sd capabilities err=%s, skip sampler check", err.Error())
+		return nil
+	}
+	for _, sampler := range requested {
+		if !item.samplers[sampler] {
+			return fmt.Errorf("sampler %s not supported, valid samplers: %s", sampler,
+				strings.Join(item.capabilities.Samplers, ", "))
+		}
+	}
+	return nil
+}
+
+// query sd webui version/samplers/scripts/extensions and normalize
+func fetchCapabilities(httpClient *http.Client, endpoint string) (*capabilitiesItem, error) {
+	capabilities := &models.SdCapabilities{
+		Extensions:     make([]string, 0),
+		Features:       make(map[string]bool),
+		Img2imgScripts: make([]string, 0),
+		Samplers:       make([]string, 0),
+		Txt2imgScripts: make([]string, 0),
+	}
+	// samplers
+	var samplers []struct {
+		Name    string   `json:"name"`
+		Aliases []string `json:"aliases"`
+	}
+	if err := getSdJson(httpClient, endpoint, config.GET_SAMPLERS, &samplers); err != nil {
+		return nil, err
+	}
+	validSamplers := make(map[string]bool)
+	for _, sampler := range samplers {
+		capabilities.Samplers = append(capabilities.Samplers, sampler.Name)
+		validSamplers[sampler.Name] = true
+		for _, alias := range sampler.Aliases {
+			validSamplers[alias] = true
+		}
+	}
+	// scripts
+	var scripts struct {
+		Txt2img []string `json:"txt2img"`
+		Img2img []string `json:"img2img"`
+	}
+	if err := getSdJson(httpClient, endpoint, config.GET_SCRIPTS, &scripts); err != nil {
+		return nil, err
+	}
+	if scripts.Txt2img != nil {
+		capabilities.Txt2imgScripts = scripts.Txt2img
+	}
+	if scripts.Img2img != nil {
+		capabilities.Img2imgScripts = scripts.Img2img
+	}
+	// extensions and version, old webui not support, ignore error
+	var extensions []struct {
+		Name    string `json:"name"`
+		Enabled bool   `json:"enabled"`
+	}
+	if err := getSdJson(httpClient, endpoint, config.GET_EXTENSIONS, &extensions); err == nil {
+		for _, extension := range extensions {
+			if extension.Enabled {
+				capabilities.Extensions = append(capabilities.Extensions, extension.Name)
+			}
+		}
+	}
+	var sysInfo struct {
+		Version string `json:"Version"`
+	}
+	if err := getSdJson(httpClient, endpoint, config.SYSINFO, &sysInfo); err == nil {
+		capabilities.Version = sysInfo.Version
+	}
+	// features
+	names := strings.ToLower(strings.Join(append(append(append([]string{}, capabilities.Txt2imgScripts...),
+		capabilities.Img2imgScripts...), capabilities.Extensions...), ","))
+	for feature, keyword := range capabilityFeatures {
+		capabilities.Features[feature] = strings.Contains(names, keyword)
+	}
+	return &capabilitiesItem{capabilities: capabilities, samplers: validSamplers}, nil
+}
+
+func getSdJson(httpClient *http.Client, endpoint, path string, out interface{}) error {
+	resp, err := httpClient.Get(fmt.Sprintf("%s%s", endpoint, path))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("get %s fail, status code=%d", path, resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/datastore"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateSamplers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	initTestConfig(t)
+	var fetches int32
+	sd := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case config.GET_SAMPLERS:
+			atomic.AddInt32(&fetches, 1)
+			json.NewEncoder(w).Encode([]map[string]interface{}{
+				{"name": "Euler a", "aliases": []string{"k_euler_a"}},
+				{"name": "DPM++ 2M Karras", "aliases": []string{"k_dpmpp_2m_ka"}},
+			})
+		case config.GET_SCRIPTS:
+			json.NewEncoder(w).Encode(map[string]interface{}{"txt2img": []string{}, "img2img": []string{}})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer sd.Close()
+	client := &http.Client{}
+
+	name, alias, unknown := "Euler a", "k_dpmpp_2m_ka", "Unknown"
+	assert.Nil(t, validateSamplers(client, sd.URL, nil, &name, &alias))
+	err := validateSamplers(client, sd.URL, &name, &unknown)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "Euler a, DPM++ 2M Karras")
+	// sampler list cached per endpoint
+	assert.Equal(t, int32(1), atomic.LoadInt32(&fetches))
+
+	// capabilities unavailable skip check, failure cached
+	var fails int32
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&fails, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer broken.Close()
+	assert.Nil(t, validateSamplers(client, broken.URL, &unknown))
+	assert.Nil(t, validateSamplers(client, broken.URL, &unknown))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&fails))
+
+	// txt2img reject unknown sampler before task created
+	config.ConfigGlobal.SdUrlPrefix = sd.URL
+	configStore := datastore.NewSQLiteDatastore(datastore.NewSQLiteConfig(datastore.KConfigTableName))
+	defer configStore.Close()
+	p := &ProxyHandler{configStore: configStore, httpClient: client}
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/txt2img",
+		bytes.NewBufferString(`{"prompt":"cat","stable_diffusion_model":"sd.safetensors","sampler_name":"Unknown"}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	p.Txt2Img(c)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "valid samplers")
+
+	// control img2img check sd function request routed to
+	config.ConfigGlobal.SdUrlPrefix = "http://127.0.0.1:1"
+	mockEndpointManager(t, &fakeEndpointManager{endpoints: map[string]string{"sd.safetensors": sd.URL}})
+	w = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/img2img",
+		bytes.NewBufferString(`{"prompt":"cat","stable_diffusion_model":"sd.safetensors","sampler_name":"Unknown"}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	p.Img2Img(c)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "valid samplers")
+}
@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+)
+
+var errPresetNotFound = errors.New("preset not found")
+
+// applyDimensionPreset expand request preset into width, height and batch_size which request not set,
+// preset removed from request, return whether request changed
+func applyDimensionPreset(request map[string]interface{}) (bool, error) {
+	val, existed := request["preset"]
+	if !existed {
+		return false, nil
+	}
+	delete(request, "preset")
+	name, _ := val.(string)
+	if name == "" {
+		return true, nil
+	}
+	preset, ok := config.ConfigGlobal.DimensionPresets[name]
+	if !ok || preset == nil {
+		return false, fmt.Errorf("%w: %s", errPresetNotFound, name)
+	}
+	for key, size := range map[string]int64{
+		"width":      preset.Width,
+		"height":     preset.Height,
+		"batch_size": preset.BatchSize,
+	} {
+		if _, existed := request[key]; !existed && size > 0 {
+			request[key] = size
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/datastore"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnmarshalWithDimensionPreset(t *testing.T) {
+	initTestConfig(t)
+	config.ConfigGlobal.DimensionPresets = map[string]*config.DimensionPreset{
+		"portrait":     {Width: 512, Height: 768},
+		"landscape-xl": {Width: 1344, Height: 768, BatchSize: 2},
+	}
+	configStore := datastore.NewSQLiteDatastore(datastore.NewSQLiteConfig(datastore.KConfigTableName))
+	defer configStore.Close()
+	p := &ProxyHandler{configStore: configStore}
+
+	request := new(models.Txt2ImgRequest)
+	assert.Nil(t, p.unmarshalWithModelDefaults([]byte(`{"preset":"landscape-xl","prompt":"cat"}`), request))
+	assert.Equal(t, int64(1344), *request.Width)
+	assert.Equal(t, int64(768), *request.Height)
+	assert.Equal(t, int64(2), *request.BatchSize)
+	assert.Nil(t, request.Preset)
+
+	// request dimensions win, preset without batch_size not fill it
+	request = new(models.Txt2ImgRequest)
+	assert.Nil(t, p.unmarshalWithModelDefaults([]byte(`{"preset":"portrait","height":1024}`), request))
+	assert.Equal(t, int64(512), *request.Width)
+	assert.Equal(t, int64(1024), *request.Height)
+	assert.Nil(t, request.BatchSize)
+
+	err := p.unmarshalWithModelDefaults([]byte(`{"preset":"missing"}`), new(models.Img2ImgRequest))
+	assert.ErrorIs(t, err, errPresetNotFound)
+}
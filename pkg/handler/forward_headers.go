@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"net/http"
+)
+
+const taskFlagKey = "Task-Flag"
+
+// proxy internal headers, kept by allow list since downstream and webui need them
+var internalHeaders = []string{"Content-Type", userKey, taskKey, versionKey, requestType, taskFlagKey,
+	sdModelKey, stickyRouteKey}
+
+// forwardHeaders headers of passthrough request by forwardHeaders policy, deny cover allow,
+// new header returned and request header not modified
+func forwardHeaders(header http.Header) http.Header {
+	policy := config.ConfigGlobal.ForwardHeaders
+	ret := make(http.Header, len(header))
+	for name, values := range header {
+		name = http.CanonicalHeaderKey(name)
+		if policy != nil && (containsHeader(policy.Deny, name) ||
+			(len(policy.Allow) > 0 && !containsHeader(policy.Allow, name) && !containsHeader(internalHeaders, name))) {
+			continue
+		}
+		ret[name] = append([]string(nil), values...)
+	}
+	return ret
+}
+
+func containsHeader(names []string, name string) bool {
+	for _, one := range names {
+		if http.CanonicalHeaderKey(one) == name {
+			return true
+		}
+	}
+	return false
+}
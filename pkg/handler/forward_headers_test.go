@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForwardHeaders(t *testing.T) {
+	initTestConfig(t)
+	header := http.Header{}
+	header.Set("Token", "secret")
+	header.Set("Cookie", "session=1")
+	header.Set("Content-Type", "application/json")
+	header.Set("X-Request-Id", "req")
+	header.Set("X-Other", "other")
+	header.Set(userKey, "user")
+	header.Set(sdModelKey, "model")
+	header.Set(stickyRouteKey, "session")
+
+	// not set forward all
+	assert.Equal(t, header, forwardHeaders(header))
+
+	config.ConfigGlobal.ForwardHeaders = &config.ForwardHeaders{Deny: config.DefaultDenyHeaders}
+	forwarded := forwardHeaders(header)
+	assert.Empty(t, forwarded.Get("Token"))
+	assert.Empty(t, forwarded.Get("Cookie"))
+	assert.Equal(t, "other", forwarded.Get("X-Other"))
+	// request header not modified
+	assert.Equal(t, "secret", header.Get("Token"))
+
+	// allow list keep internal headers, deny cover allow
+	config.ConfigGlobal.ForwardHeaders.Allow = []string{"x-request-id", "Token"}
+	forwarded = forwardHeaders(header)
+	assert.Equal(t, http.Header{
+		"Content-Type": {"application/json"},
+		"X-Request-Id": {"req"},
+		"Username":     {"user"},
+		// downstream route by model and sticky session
+		"X-Sd-Model":     {"model"},
+		"X-Sticky-Route": {"session"},
+	}, forwarded)
+}
+
+func TestNoRouterForwardHeaders(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	initTestConfig(t)
+	config.ConfigGlobal.ServerName = config.PROXY
+	config.ConfigGlobal.ForwardHeaders = &config.ForwardHeaders{Deny: config.DefaultDenyHeaders}
+	var received http.Header
+	sd := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+		w.Write([]byte("{}"))
+	}))
+	defer sd.Close()
+	config.ConfigGlobal.Downstream = sd.URL
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/sdapi/v1/options", nil)
+	c.Request.Header.Set("Token", "secret")
+	c.Request.Header.Set("Authorization", "Bearer secret")
+	c.Request.Header.Set("X-Other", "other")
+	(&ProxyHandler{}).NoRouterHandler(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, received.Get("Token"))
+	assert.Empty(t, received.Get("Authorization"))
+	assert.Equal(t, "other", received.Get("X-Other"))
+	assert.Equal(t, DEFAULT_USER, received.Get(userKey))
+}
@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"fmt"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/models"
+)
+
+const (
+	// webui default of params not set
+	defaultImageSize = 512
+	defaultHrScale   = 2
+)
+
+// checkGpuMemory reject request whose vram estimate exceed gpu memory of instance serve sdModel,
+// pixels of one output image, no guard when gpuMemoryGuard of model not set
+func checkGpuMemory(sdModel string, pixels int64, batchSize *int64) error {
+	coefficient := config.ConfigGlobal.GetGpuMemoryCoefficient(sdModel)
+	if coefficient == nil {
+		return nil
+	}
+	batch := int64Or(batchSize, 1)
+	estimate := coefficient.BaseMB + coefficient.PerMegapixelMB*float64(pixels*batch)/1e6
+	gpuMemory := instanceGpuMemory(sdModel)
+	if gpuMemory > 0 && estimate > float64(gpuMemory) {
+		return fmt.Errorf("estimated vram %.0fMB exceed gpu memory %dMB of instance, "+
+			"please reduce width/height/batch_size", estimate, gpuMemory)
+	}
+	return nil
+}
+
+// instanceGpuMemory gpu memory(MB) of function serve sdModel on multiFunc control, config gpuMemorySize of
+// current instance otherwise
+func instanceGpuMemory(sdModel string) int32 {
+	if config.ConfigGlobal.GetFlexMode() == config.MultiFunc && config.ConfigGlobal.IsServerTypeMatch(config.CONTROL) {
+		return getEndpointManager().GetGpuMemorySize(withDefaultModel(sdModel))
+	}
+	return config.ConfigGlobal.GpuMemorySize
+}
+
+// txt2ImgPixels pixels of one txt2img output, hires upscaled size when enable_hr
+func txt2ImgPixels(request *models.Txt2ImgRequest) int64 {
+	width, height := int64Or(request.Width, defaultImageSize), int64Or(request.Height, defaultImageSize)
+	if request.EnableHr == nil || !*request.EnableHr {
+		return width * height
+	}
+	hrWidth, hrHeight := int64Or(request.HrResizeX, 0), int64Or(request.HrResizeY, 0)
+	switch {
+	case hrWidth > 0 && hrHeight > 0:
+		return hrWidth * hrHeight
+	case hrWidth > 0:
+		// keep aspect ratio
+		return hrWidth * hrWidth * height / width
+	case hrHeight > 0:
+		return hrHeight * hrHeight * width / height
+	}
+	scale := int64Or(request.HrScale, defaultHrScale)
+	return width * height * scale * scale
+}
+
+// img2ImgPixels pixels of one img2img output
+func img2ImgPixels(request *models.Img2ImgRequest) int64 {
+	return int64Or(request.Width, defaultImageSize) * int64Or(request.Height, defaultImageSize)
+}
+
+func int64Or(val *int64, defaultVal int64) int64 {
+	if val == nil || *val <= 0 {
+		return defaultVal
+	}
+	return *val
+}
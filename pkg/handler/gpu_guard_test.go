@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/models"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTxt2ImgPixels(t *testing.T) {
+	assert.Equal(t, int64(512*512), txt2ImgPixels(&models.Txt2ImgRequest{}))
+	assert.Equal(t, int64(1024*768), txt2ImgPixels(&models.Txt2ImgRequest{Width: utils.Int64(1024),
+		Height: utils.Int64(768), HrScale: utils.Int64(2)}))
+	// hires
+	request := &models.Txt2ImgRequest{Width: utils.Int64(512), Height: utils.Int64(768), EnableHr: utils.Bool(true)}
+	assert.Equal(t, int64(1024*1536), txt2ImgPixels(request))
+	request.HrScale = utils.Int64(3)
+	assert.Equal(t, int64(1536*2304), txt2ImgPixels(request))
+	request.HrResizeX = utils.Int64(1024)
+	assert.Equal(t, int64(1024*1536), txt2ImgPixels(request))
+	request.HrResizeY = utils.Int64(1000)
+	assert.Equal(t, int64(1024*1000), txt2ImgPixels(request))
+	assert.Equal(t, int64(640*512), img2ImgPixels(&models.Img2ImgRequest{Width: utils.Int64(640)}))
+}
+
+func TestCheckGpuMemory(t *testing.T) {
+	initTestConfig(t)
+	config.ConfigGlobal.ServerName = config.PROXY
+	config.ConfigGlobal.GpuMemorySize = 16384
+	// no guard
+	assert.Nil(t, checkGpuMemory("sd_xl", 4096*4096, utils.Int64(8)))
+
+	config.ConfigGlobal.GpuMemoryGuard = map[string]*config.GpuMemoryCoefficient{
+		config.AllModels: {BaseMB: 4000, PerMegapixelMB: 2000},
+		"XL":             {BaseMB: 9000, PerMegapixelMB: 3000},
+	}
+	// 4000 + 2000 * 4.19
+	assert.Nil(t, checkGpuMemory("sd15", 1024*1024*4, nil))
+	assert.NotNil(t, checkGpuMemory("sd15", 1024*1024, utils.Int64(8)))
+	// 9000 + 3000 * 2.1
+	assert.Nil(t, checkGpuMemory("sd_xl_base", 1024*1024*2, nil))
+	err := checkGpuMemory("sd_xl_base", 1024*1024*3, nil)
+	assert.EqualError(t, err, "estimated vram 18437MB exceed gpu memory 16384MB of instance, "+
+		"please reduce width/height/batch_size")
+
+	// control use gpu memory of model function
+	config.ConfigGlobal.ServerName = config.CONTROL
+	config.ConfigGlobal.DefaultModel = "sd15"
+	mockEndpointManager(t, &fakeEndpointManager{gpuMemory: map[string]int32{"sd_xl_base": 24576, "sd15": 8192}})
+	assert.Nil(t, checkGpuMemory("sd_xl_base", 1024*1024*3, nil))
+	assert.NotNil(t, checkGpuMemory("", 1024*1024*3, nil))
+	// singleFunc no function per model, config gpuMemorySize
+	config.ConfigGlobal.FlexMode = "singleFunc"
+	assert.NotNil(t, checkGpuMemory("sd_xl_base", 1024*1024*3, nil))
+}
@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/binary"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/models"
+	"github.com/sirupsen/logrus"
+	"image/png"
+	"sync"
+	"time"
+)
+
+// png file signature
+const pngSignature = "\x89PNG\r\n\x1a\n"
+
+var pngCompressionLevels = map[string]png.CompressionLevel{
+	config.ImageCompressionFast:    png.BestSpeed,
+	config.ImageCompressionDefault: png.DefaultCompression,
+	config.ImageCompressionBest:    png.BestCompression,
+}
+
+// png text chunks keep after re-encode, webui write infotext into them
+var pngTextChunks = map[string]bool{"tEXt": true, "zTXt": true, "iTXt": true}
+
+// imageCompressionStats cumulative cost and saving of png compression
+var imageCompressionStats = struct {
+	lock sync.Mutex
+	models.ImageCompression
+}{}
+
+// compressImage lossless re-encode png with imageCompressionLevel, keep text chunks,
+// original returned when compression disabled, not png or result not smaller
+func compressImage(body []byte) []byte {
+	if !config.ConfigGlobal.IsImageCompression() || !bytes.HasPrefix(body, []byte(pngSignature)) {
+		return body
+	}
+	start := time.Now()
+	compressed, err := recompressPng(body, pngCompressionLevels[config.ConfigGlobal.ImageCompressionLevel])
+	cost := time.Since(start)
+	if err != nil {
+		logrus.Warnf("compress png err=%s", err.Error())
+		return body
+	}
+	if len(compressed) >= len(body) {
+		compressed = body
+	}
+	imageCompressionStats.lock.Lock()
+	imageCompressionStats.Images++
+	imageCompressionStats.OriginalBytes += int64(len(body))
+	imageCompressionStats.CompressedBytes += int64(len(compressed))
+	imageCompressionStats.CpuMillis += cost.Milliseconds()
+	imageCompressionStats.lock.Unlock()
+	logrus.Debugf("compress png %d -> %d bytes, cost %s", len(body), len(compressed), cost)
+	return compressed
+}
+
+// recompressPng decode and encode png, text chunks of original inserted after IHDR
+func recompressPng(body []byte, level png.CompressionLevel) ([]byte, error) {
+	img, err := png.Decode(bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	encoder := &png.Encoder{CompressionLevel: level}
+	if err := encoder.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	encoded := buf.Bytes()
+	var texts []byte
+	for _, chunk := range pngChunks(body) {
+		if pngTextChunks[string(chunk[4:8])] {
+			texts = append(texts, chunk...)
+		}
+	}
+	if len(texts) == 0 {
+		return encoded, nil
+	}
+	// signature + IHDR chunk (4 length + 4 type + 13 data + 4 crc)
+	ihdrEnd := len(pngSignature) + 25
+	ret := make([]byte, 0, len(encoded)+len(texts))
+	ret = append(ret, encoded[:ihdrEnd]...)
+	ret = append(ret, texts...)
+	return append(ret, encoded[ihdrEnd:]...), nil
+}
+
+// pngChunks raw chunks (length, type, data, crc) of png, stop at broken chunk
+func pngChunks(body []byte) [][]byte {
+	chunks := make([][]byte, 0)
+	for pos := len(pngSignature); pos+12 <= len(body); {
+		end := pos + 12 + int(binary.BigEndian.Uint32(body[pos:pos+4]))
+		if end > len(body) || end < pos {
+			break
+		}
+		chunks = append(chunks, body[pos:end])
+		pos = end
+	}
+	return chunks
+}
+
+// imageCompressionStatus png compression stats since start
+func imageCompressionStatus() models.ImageCompression {
+	imageCompressionStats.lock.Lock()
+	defer imageCompressionStats.lock.Unlock()
+	return imageCompressionStats.ImageCompression
+}
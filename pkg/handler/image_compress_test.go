@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+// testPng uncompressed png with infotext chunk like webui output
+func testPng(t *testing.T) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, 64, 64))
+	for x := 0; x < 64; x++ {
+		for y := 0; y < 64; y++ {
+			img.Set(x, y, color.RGBA{R: uint8(x * 4), G: uint8(y * 4), B: 128, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	encoder := &png.Encoder{CompressionLevel: png.NoCompression}
+	assert.Nil(t, encoder.Encode(&buf, img))
+	body := buf.Bytes()
+
+	data := []byte("parameters\x00a cat, Steps: 20")
+	chunk := make([]byte, 8, 12+len(data))
+	binary.BigEndian.PutUint32(chunk, uint32(len(data)))
+	copy(chunk[4:], "tEXt")
+	chunk = append(chunk, data...)
+	chunk = binary.BigEndian.AppendUint32(chunk, crc32.ChecksumIEEE(chunk[4:]))
+	ihdrEnd := len(pngSignature) + 25
+	return append(append(append([]byte{}, body[:ihdrEnd]...), chunk...), body[ihdrEnd:]...)
+}
+
+func TestCompressImage(t *testing.T) {
+	initTestConfig(t)
+	body := testPng(t)
+
+	// disabled by default
+	assert.Equal(t, body, compressImage(body))
+
+	config.ConfigGlobal.ImageCompression = config.ImageCompressionPng
+	config.ConfigGlobal.ImageCompressionLevel = config.ImageCompressionBest
+	before := imageCompressionStatus()
+	compressed := compressImage(body)
+	assert.Less(t, len(compressed), len(body))
+	// lossless and infotext kept
+	origin, err := png.Decode(bytes.NewReader(body))
+	assert.Nil(t, err)
+	img, err := png.Decode(bytes.NewReader(compressed))
+	assert.Nil(t, err)
+	assert.Equal(t, origin.At(10, 20), img.At(10, 20))
+	assert.Contains(t, string(compressed), "parameters\x00a cat, Steps: 20")
+	stats := imageCompressionStatus()
+	assert.Equal(t, before.Images+1, stats.Images)
+	assert.Equal(t, before.OriginalBytes+int64(len(body)), stats.OriginalBytes)
+	assert.Equal(t, before.CompressedBytes+int64(len(compressed)), stats.CompressedBytes)
+
+	// not png or broken png keep original
+	jpeg := []byte("\xff\xd8\xff\xe0 jpeg")
+	assert.Equal(t, jpeg, compressImage(jpeg))
+	broken := body[:len(body)/2]
+	assert.Equal(t, broken, compressImage(broken))
+}
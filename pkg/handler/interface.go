@@ -13,6 +13,7 @@ import (
 	"path"
 	"strings"
 
+	. "github.com/devsapp/serverless-stable-diffusion-api/pkg/models"
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/gin-gonic/gin"
 	"github.com/oapi-codegen/runtime"
@@ -20,18 +21,69 @@ import (
 
 // ServerInterface represents all server handlers.
 type ServerInterface interface {
+	// effective sd env of instance serving the request, credentials never returned, admin only
+	// (GET /admin/env)
+	GetEnv(c *gin.Context)
+	// tail recent sd webui logs, admin only
+	// (GET /admin/logs/sd)
+	TailSdLogs(c *gin.Context, params TailSdLogsParams)
+	// get maintenance mode status
+	// (GET /admin/maintenance)
+	GetMaintenance(c *gin.Context)
+	// enable or disable maintenance mode, new task submissions return 503 when enabled
+	// (POST /admin/maintenance)
+	SetMaintenance(c *gin.Context)
+	// reconcile model store with model files of sdPath model dirs, dry run only report, admin only
+	// (POST /admin/models/reconcile)
+	ReconcileModels(c *gin.Context, params ReconcileModelsParams)
+	// get model default params
+	// (GET /admin/models/{model_name}/defaults)
+	GetModelDefaults(c *gin.Context, modelName string)
+	// update model default params, inject into txt2img/img2img request when not set
+	// (PUT /admin/models/{model_name}/defaults)
+	UpdateModelDefaults(c *gin.Context, modelName string)
+	// list model profiles, admin only
+	// (GET /admin/profiles)
+	ListProfiles(c *gin.Context)
+	// delete model profile
+	// (DELETE /admin/profiles/{profile_name})
+	DeleteProfile(c *gin.Context, profileName string)
+	// get model profile
+	// (GET /admin/profiles/{profile_name})
+	GetProfile(c *gin.Context, profileName string)
+	// create or update model profile, txt2img/img2img reference it by profile
+	// (PUT /admin/profiles/{profile_name})
+	UpdateProfile(c *gin.Context, profileName string)
+	// render canned prompt with fixed seed, compare image hash with stored reference
+	// (POST /admin/selftest)
+	SelfTest(c *gin.Context)
+	// get server stats, include cold start budget
+	// (GET /admin/stats)
+	GetStats(c *gin.Context)
+	// disk usage of sdPath and size of model dirs, helps decide what to delete before nas full, admin only
+	// (GET /admin/storage)
+	GetStorage(c *gin.Context)
+	// gpu seconds usage per user, admin only
+	// (GET /admin/usage)
+	GetUsage(c *gin.Context, params GetUsageParams)
 	// update sd function resource by batch, Supports a specified list of functions, or all
 	// (POST /batch_update_sd_resource)
 	BatchUpdateResource(c *gin.Context)
 	// delete sd function
 	// (POST /del/sd/functions)
 	DelSDFunc(c *gin.Context)
+	// batch image upcaling
+	// (POST /extra_batch_images)
+	ExtraBatchImages(c *gin.Context)
 	// image upcaling
 	// (POST /extra_images)
 	ExtraImages(c *gin.Context)
 	// img to img predict
 	// (POST /img2img)
 	Img2Img(c *gin.Context)
+	// image to prompt (interrogate)
+	// (POST /interrogate)
+	Interrogate(c *gin.Context)
 	// get sdapi function
 	// (GET /list/sdapi/functions)
 	ListSdFunc(c *gin.Context)
@@ -44,6 +96,9 @@ type ServerInterface interface {
 	// register model
 	// (POST /models)
 	RegisterModel(c *gin.Context)
+	// delete models by names or type, models used by unfinished tasks skipped
+	// (POST /models/batch-delete)
+	BatchDeleteModels(c *gin.Context)
 	// delete model
 	// (DELETE /models/{model_name})
 	DeleteModel(c *gin.Context, modelName string)
@@ -53,24 +108,75 @@ type ServerInterface interface {
 	// update model
 	// (PUT /models/{model_name})
 	UpdateModel(c *gin.Context, modelName string)
+	// refresh functions env so model loaded on all instances, multiFunc control only
+	// (POST /models/{model_name}/distribute)
+	DistributeModel(c *gin.Context, modelName string)
 	// update config options
 	// (POST /options)
 	UpdateOptions(c *gin.Context)
+	// list user prompt templates
+	// (GET /prompt_templates)
+	ListPromptTemplates(c *gin.Context)
+	// delete user prompt template
+	// (DELETE /prompt_templates/{template_name})
+	DeletePromptTemplate(c *gin.Context, templateName string)
+	// get user prompt template
+	// (GET /prompt_templates/{template_name})
+	GetPromptTemplate(c *gin.Context, templateName string)
+	// create or update user prompt template, use {{template_name}} in prompt
+	// (PUT /prompt_templates/{template_name})
+	UpdatePromptTemplate(c *gin.Context, templateName string)
 	// restart webui api server
 	// (POST /restart)
 	Restart(c *gin.Context)
+	// get sd webui version and capabilities
+	// (GET /sdapi/capabilities)
+	GetCapabilities(c *gin.Context)
+	// get task result by share link token, no login required
+	// (GET /shared/{token})
+	GetSharedTaskResult(c *gin.Context, token string)
+	// query tasks by user, status, create time range and model, paginated by cursor
+	// (GET /tasks)
+	ListTasks(c *gin.Context, params ListTasksParams)
+	// get predict results of a batch of tasks in one request
+	// (POST /tasks/results)
+	GetTaskResults(c *gin.Context)
 	// cancel predict task
 	// (POST /tasks/{taskId}/cancellation)
 	CancelTask(c *gin.Context, taskId string)
 	// get predict progress
 	// (GET /tasks/{taskId}/progress)
 	GetTaskProgress(c *gin.Context, taskId string)
+	// get request body of task ready to resubmit, random seed replaced by seed used, base64 images redacted, task owner only
+	// (GET /tasks/{taskId}/reproduce)
+	GetTaskReproduce(c *gin.Context, taskId string)
 	// get predict result
 	// (GET /tasks/{taskId}/result)
 	GetTaskResult(c *gin.Context, taskId string)
+	// create signed, expiring public link of task result, task owner only
+	// (POST /tasks/{taskId}/share)
+	ShareTask(c *gin.Context, taskId string, params ShareTaskParams)
 	// txt to img predict
 	// (POST /txt2img)
 	Txt2Img(c *gin.Context)
+	// get or create, explicit fixed seed required, return finished task with identical params if exists, else submit as txt2img
+	// (POST /txt2img/cached)
+	Txt2ImgCached(c *gin.Context)
+	// txt to img predict of multi prompts with shared params, one task per prompt rendered before response
+	// (POST /txt2img/multi)
+	Txt2ImgMulti(c *gin.Context)
+	// delete images of user and clear them from owning task result
+	// (DELETE /users/{user}/images)
+	DeleteUserImages(c *gin.Context, user string)
+	// list generated images of user under image oss prefix, paginated by cursor
+	// (GET /users/{user}/images)
+	ListUserImages(c *gin.Context, user string, params ListUserImagesParams)
+	// task and resource usage of user in recent days, non admin user only access own stats when login on
+	// (GET /users/{user}/stats)
+	GetUserStats(c *gin.Context, user string, params GetUserStatsParams)
+	// get build version and server mode, no login required
+	// (GET /version)
+	GetVersion(c *gin.Context)
 }
 
 // ServerInterfaceWrapper converts contexts to parameters.
@@ -82,6 +188,295 @@ type ServerInterfaceWrapper struct {
 
 type MiddlewareFunc func(c *gin.Context)
 
+// GetEnv operation middleware
+func (siw *ServerInterfaceWrapper) GetEnv(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetEnv(c)
+}
+
+// TailSdLogs operation middleware
+func (siw *ServerInterfaceWrapper) TailSdLogs(c *gin.Context) {
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params TailSdLogsParams
+
+	// ------------- Optional query parameter "tail" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "tail", c.Request.URL.Query(), &params.Tail)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter tail: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.TailSdLogs(c, params)
+}
+
+// GetMaintenance operation middleware
+func (siw *ServerInterfaceWrapper) GetMaintenance(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetMaintenance(c)
+}
+
+// SetMaintenance operation middleware
+func (siw *ServerInterfaceWrapper) SetMaintenance(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.SetMaintenance(c)
+}
+
+// ReconcileModels operation middleware
+func (siw *ServerInterfaceWrapper) ReconcileModels(c *gin.Context) {
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ReconcileModelsParams
+
+	// ------------- Optional query parameter "dryRun" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "dryRun", c.Request.URL.Query(), &params.DryRun)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter dryRun: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.ReconcileModels(c, params)
+}
+
+// GetModelDefaults operation middleware
+func (siw *ServerInterfaceWrapper) GetModelDefaults(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "model_name" -------------
+	var modelName string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "model_name", c.Param("model_name"), &modelName, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter model_name: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetModelDefaults(c, modelName)
+}
+
+// UpdateModelDefaults operation middleware
+func (siw *ServerInterfaceWrapper) UpdateModelDefaults(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "model_name" -------------
+	var modelName string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "model_name", c.Param("model_name"), &modelName, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter model_name: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.UpdateModelDefaults(c, modelName)
+}
+
+// ListProfiles operation middleware
+func (siw *ServerInterfaceWrapper) ListProfiles(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.ListProfiles(c)
+}
+
+// DeleteProfile operation middleware
+func (siw *ServerInterfaceWrapper) DeleteProfile(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "profile_name" -------------
+	var profileName string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "profile_name", c.Param("profile_name"), &profileName, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter profile_name: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.DeleteProfile(c, profileName)
+}
+
+// GetProfile operation middleware
+func (siw *ServerInterfaceWrapper) GetProfile(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "profile_name" -------------
+	var profileName string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "profile_name", c.Param("profile_name"), &profileName, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter profile_name: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetProfile(c, profileName)
+}
+
+// UpdateProfile operation middleware
+func (siw *ServerInterfaceWrapper) UpdateProfile(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "profile_name" -------------
+	var profileName string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "profile_name", c.Param("profile_name"), &profileName, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter profile_name: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.UpdateProfile(c, profileName)
+}
+
+// SelfTest operation middleware
+func (siw *ServerInterfaceWrapper) SelfTest(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.SelfTest(c)
+}
+
+// GetStats operation middleware
+func (siw *ServerInterfaceWrapper) GetStats(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetStats(c)
+}
+
+// GetStorage operation middleware
+func (siw *ServerInterfaceWrapper) GetStorage(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetStorage(c)
+}
+
+// GetUsage operation middleware
+func (siw *ServerInterfaceWrapper) GetUsage(c *gin.Context) {
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetUsageParams
+
+	// ------------- Optional query parameter "user" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "user", c.Request.URL.Query(), &params.User)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter user: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetUsage(c, params)
+}
+
 // BatchUpdateResource operation middleware
 func (siw *ServerInterfaceWrapper) BatchUpdateResource(c *gin.Context) {
 
@@ -95,8 +490,234 @@ func (siw *ServerInterfaceWrapper) BatchUpdateResource(c *gin.Context) {
 	siw.Handler.BatchUpdateResource(c)
 }
 
-// DelSDFunc operation middleware
-func (siw *ServerInterfaceWrapper) DelSDFunc(c *gin.Context) {
+// DelSDFunc operation middleware
+func (siw *ServerInterfaceWrapper) DelSDFunc(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.DelSDFunc(c)
+}
+
+// ExtraBatchImages operation middleware
+func (siw *ServerInterfaceWrapper) ExtraBatchImages(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.ExtraBatchImages(c)
+}
+
+// ExtraImages operation middleware
+func (siw *ServerInterfaceWrapper) ExtraImages(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.ExtraImages(c)
+}
+
+// Img2Img operation middleware
+func (siw *ServerInterfaceWrapper) Img2Img(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.Img2Img(c)
+}
+
+// Interrogate operation middleware
+func (siw *ServerInterfaceWrapper) Interrogate(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.Interrogate(c)
+}
+
+// ListSdFunc operation middleware
+func (siw *ServerInterfaceWrapper) ListSdFunc(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.ListSdFunc(c)
+}
+
+// Login operation middleware
+func (siw *ServerInterfaceWrapper) Login(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.Login(c)
+}
+
+// ListModels operation middleware
+func (siw *ServerInterfaceWrapper) ListModels(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.ListModels(c)
+}
+
+// RegisterModel operation middleware
+func (siw *ServerInterfaceWrapper) RegisterModel(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.RegisterModel(c)
+}
+
+// BatchDeleteModels operation middleware
+func (siw *ServerInterfaceWrapper) BatchDeleteModels(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.BatchDeleteModels(c)
+}
+
+// DeleteModel operation middleware
+func (siw *ServerInterfaceWrapper) DeleteModel(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "model_name" -------------
+	var modelName string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "model_name", c.Param("model_name"), &modelName, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter model_name: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.DeleteModel(c, modelName)
+}
+
+// GetModel operation middleware
+func (siw *ServerInterfaceWrapper) GetModel(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "model_name" -------------
+	var modelName string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "model_name", c.Param("model_name"), &modelName, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter model_name: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetModel(c, modelName)
+}
+
+// UpdateModel operation middleware
+func (siw *ServerInterfaceWrapper) UpdateModel(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "model_name" -------------
+	var modelName string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "model_name", c.Param("model_name"), &modelName, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter model_name: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.UpdateModel(c, modelName)
+}
+
+// DistributeModel operation middleware
+func (siw *ServerInterfaceWrapper) DistributeModel(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "model_name" -------------
+	var modelName string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "model_name", c.Param("model_name"), &modelName, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter model_name: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.DistributeModel(c, modelName)
+}
+
+// UpdateOptions operation middleware
+func (siw *ServerInterfaceWrapper) UpdateOptions(c *gin.Context) {
 
 	for _, middleware := range siw.HandlerMiddlewares {
 		middleware(c)
@@ -105,11 +726,11 @@ func (siw *ServerInterfaceWrapper) DelSDFunc(c *gin.Context) {
 		}
 	}
 
-	siw.Handler.DelSDFunc(c)
+	siw.Handler.UpdateOptions(c)
 }
 
-// ExtraImages operation middleware
-func (siw *ServerInterfaceWrapper) ExtraImages(c *gin.Context) {
+// ListPromptTemplates operation middleware
+func (siw *ServerInterfaceWrapper) ListPromptTemplates(c *gin.Context) {
 
 	for _, middleware := range siw.HandlerMiddlewares {
 		middleware(c)
@@ -118,11 +739,22 @@ func (siw *ServerInterfaceWrapper) ExtraImages(c *gin.Context) {
 		}
 	}
 
-	siw.Handler.ExtraImages(c)
+	siw.Handler.ListPromptTemplates(c)
 }
 
-// Img2Img operation middleware
-func (siw *ServerInterfaceWrapper) Img2Img(c *gin.Context) {
+// DeletePromptTemplate operation middleware
+func (siw *ServerInterfaceWrapper) DeletePromptTemplate(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "template_name" -------------
+	var templateName string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "template_name", c.Param("template_name"), &templateName, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter template_name: %w", err), http.StatusBadRequest)
+		return
+	}
 
 	for _, middleware := range siw.HandlerMiddlewares {
 		middleware(c)
@@ -131,11 +763,22 @@ func (siw *ServerInterfaceWrapper) Img2Img(c *gin.Context) {
 		}
 	}
 
-	siw.Handler.Img2Img(c)
+	siw.Handler.DeletePromptTemplate(c, templateName)
 }
 
-// ListSdFunc operation middleware
-func (siw *ServerInterfaceWrapper) ListSdFunc(c *gin.Context) {
+// GetPromptTemplate operation middleware
+func (siw *ServerInterfaceWrapper) GetPromptTemplate(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "template_name" -------------
+	var templateName string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "template_name", c.Param("template_name"), &templateName, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter template_name: %w", err), http.StatusBadRequest)
+		return
+	}
 
 	for _, middleware := range siw.HandlerMiddlewares {
 		middleware(c)
@@ -144,11 +787,22 @@ func (siw *ServerInterfaceWrapper) ListSdFunc(c *gin.Context) {
 		}
 	}
 
-	siw.Handler.ListSdFunc(c)
+	siw.Handler.GetPromptTemplate(c, templateName)
 }
 
-// Login operation middleware
-func (siw *ServerInterfaceWrapper) Login(c *gin.Context) {
+// UpdatePromptTemplate operation middleware
+func (siw *ServerInterfaceWrapper) UpdatePromptTemplate(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "template_name" -------------
+	var templateName string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "template_name", c.Param("template_name"), &templateName, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter template_name: %w", err), http.StatusBadRequest)
+		return
+	}
 
 	for _, middleware := range siw.HandlerMiddlewares {
 		middleware(c)
@@ -157,11 +811,11 @@ func (siw *ServerInterfaceWrapper) Login(c *gin.Context) {
 		}
 	}
 
-	siw.Handler.Login(c)
+	siw.Handler.UpdatePromptTemplate(c, templateName)
 }
 
-// ListModels operation middleware
-func (siw *ServerInterfaceWrapper) ListModels(c *gin.Context) {
+// Restart operation middleware
+func (siw *ServerInterfaceWrapper) Restart(c *gin.Context) {
 
 	for _, middleware := range siw.HandlerMiddlewares {
 		middleware(c)
@@ -170,11 +824,11 @@ func (siw *ServerInterfaceWrapper) ListModels(c *gin.Context) {
 		}
 	}
 
-	siw.Handler.ListModels(c)
+	siw.Handler.Restart(c)
 }
 
-// RegisterModel operation middleware
-func (siw *ServerInterfaceWrapper) RegisterModel(c *gin.Context) {
+// GetCapabilities operation middleware
+func (siw *ServerInterfaceWrapper) GetCapabilities(c *gin.Context) {
 
 	for _, middleware := range siw.HandlerMiddlewares {
 		middleware(c)
@@ -183,20 +837,20 @@ func (siw *ServerInterfaceWrapper) RegisterModel(c *gin.Context) {
 		}
 	}
 
-	siw.Handler.RegisterModel(c)
+	siw.Handler.GetCapabilities(c)
 }
 
-// DeleteModel operation middleware
-func (siw *ServerInterfaceWrapper) DeleteModel(c *gin.Context) {
+// GetSharedTaskResult operation middleware
+func (siw *ServerInterfaceWrapper) GetSharedTaskResult(c *gin.Context) {
 
 	var err error
 
-	// ------------- Path parameter "model_name" -------------
-	var modelName string
+	// ------------- Path parameter "token" -------------
+	var token string
 
-	err = runtime.BindStyledParameterWithOptions("simple", "model_name", c.Param("model_name"), &modelName, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	err = runtime.BindStyledParameterWithOptions("simple", "token", c.Param("token"), &token, runtime.BindStyledParameterOptions{Explode: false, Required: true})
 	if err != nil {
-		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter model_name: %w", err), http.StatusBadRequest)
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter token: %w", err), http.StatusBadRequest)
 		return
 	}
 
@@ -207,59 +861,72 @@ func (siw *ServerInterfaceWrapper) DeleteModel(c *gin.Context) {
 		}
 	}
 
-	siw.Handler.DeleteModel(c, modelName)
+	siw.Handler.GetSharedTaskResult(c, token)
 }
 
-// GetModel operation middleware
-func (siw *ServerInterfaceWrapper) GetModel(c *gin.Context) {
+// ListTasks operation middleware
+func (siw *ServerInterfaceWrapper) ListTasks(c *gin.Context) {
 
 	var err error
 
-	// ------------- Path parameter "model_name" -------------
-	var modelName string
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListTasksParams
 
-	err = runtime.BindStyledParameterWithOptions("simple", "model_name", c.Param("model_name"), &modelName, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	// ------------- Optional query parameter "user" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "user", c.Request.URL.Query(), &params.User)
 	if err != nil {
-		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter model_name: %w", err), http.StatusBadRequest)
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter user: %w", err), http.StatusBadRequest)
 		return
 	}
 
-	for _, middleware := range siw.HandlerMiddlewares {
-		middleware(c)
-		if c.IsAborted() {
-			return
-		}
+	// ------------- Optional query parameter "status" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "status", c.Request.URL.Query(), &params.Status)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter status: %w", err), http.StatusBadRequest)
+		return
 	}
 
-	siw.Handler.GetModel(c, modelName)
-}
+	// ------------- Optional query parameter "from" -------------
 
-// UpdateModel operation middleware
-func (siw *ServerInterfaceWrapper) UpdateModel(c *gin.Context) {
+	err = runtime.BindQueryParameter("form", true, false, "from", c.Request.URL.Query(), &params.From)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter from: %w", err), http.StatusBadRequest)
+		return
+	}
 
-	var err error
+	// ------------- Optional query parameter "to" -------------
 
-	// ------------- Path parameter "model_name" -------------
-	var modelName string
+	err = runtime.BindQueryParameter("form", true, false, "to", c.Request.URL.Query(), &params.To)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter to: %w", err), http.StatusBadRequest)
+		return
+	}
 
-	err = runtime.BindStyledParameterWithOptions("simple", "model_name", c.Param("model_name"), &modelName, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	// ------------- Optional query parameter "model" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "model", c.Request.URL.Query(), &params.Model)
 	if err != nil {
-		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter model_name: %w", err), http.StatusBadRequest)
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter model: %w", err), http.StatusBadRequest)
 		return
 	}
 
-	for _, middleware := range siw.HandlerMiddlewares {
-		middleware(c)
-		if c.IsAborted() {
-			return
-		}
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", c.Request.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter limit: %w", err), http.StatusBadRequest)
+		return
 	}
 
-	siw.Handler.UpdateModel(c, modelName)
-}
+	// ------------- Optional query parameter "cursor" -------------
 
-// UpdateOptions operation middleware
-func (siw *ServerInterfaceWrapper) UpdateOptions(c *gin.Context) {
+	err = runtime.BindQueryParameter("form", true, false, "cursor", c.Request.URL.Query(), &params.Cursor)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter cursor: %w", err), http.StatusBadRequest)
+		return
+	}
 
 	for _, middleware := range siw.HandlerMiddlewares {
 		middleware(c)
@@ -268,11 +935,11 @@ func (siw *ServerInterfaceWrapper) UpdateOptions(c *gin.Context) {
 		}
 	}
 
-	siw.Handler.UpdateOptions(c)
+	siw.Handler.ListTasks(c, params)
 }
 
-// Restart operation middleware
-func (siw *ServerInterfaceWrapper) Restart(c *gin.Context) {
+// GetTaskResults operation middleware
+func (siw *ServerInterfaceWrapper) GetTaskResults(c *gin.Context) {
 
 	for _, middleware := range siw.HandlerMiddlewares {
 		middleware(c)
@@ -281,7 +948,7 @@ func (siw *ServerInterfaceWrapper) Restart(c *gin.Context) {
 		}
 	}
 
-	siw.Handler.Restart(c)
+	siw.Handler.GetTaskResults(c)
 }
 
 // CancelTask operation middleware
@@ -332,6 +999,30 @@ func (siw *ServerInterfaceWrapper) GetTaskProgress(c *gin.Context) {
 	siw.Handler.GetTaskProgress(c, taskId)
 }
 
+// GetTaskReproduce operation middleware
+func (siw *ServerInterfaceWrapper) GetTaskReproduce(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "taskId" -------------
+	var taskId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "taskId", c.Param("taskId"), &taskId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter taskId: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetTaskReproduce(c, taskId)
+}
+
 // GetTaskResult operation middleware
 func (siw *ServerInterfaceWrapper) GetTaskResult(c *gin.Context) {
 
@@ -356,6 +1047,41 @@ func (siw *ServerInterfaceWrapper) GetTaskResult(c *gin.Context) {
 	siw.Handler.GetTaskResult(c, taskId)
 }
 
+// ShareTask operation middleware
+func (siw *ServerInterfaceWrapper) ShareTask(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "taskId" -------------
+	var taskId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "taskId", c.Param("taskId"), &taskId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter taskId: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ShareTaskParams
+
+	// ------------- Optional query parameter "expiresIn" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "expiresIn", c.Request.URL.Query(), &params.ExpiresIn)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter expiresIn: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.ShareTask(c, taskId, params)
+}
+
 // Txt2Img operation middleware
 func (siw *ServerInterfaceWrapper) Txt2Img(c *gin.Context) {
 
@@ -369,6 +1095,155 @@ func (siw *ServerInterfaceWrapper) Txt2Img(c *gin.Context) {
 	siw.Handler.Txt2Img(c)
 }
 
+// Txt2ImgCached operation middleware
+func (siw *ServerInterfaceWrapper) Txt2ImgCached(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.Txt2ImgCached(c)
+}
+
+// Txt2ImgMulti operation middleware
+func (siw *ServerInterfaceWrapper) Txt2ImgMulti(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.Txt2ImgMulti(c)
+}
+
+// DeleteUserImages operation middleware
+func (siw *ServerInterfaceWrapper) DeleteUserImages(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "user" -------------
+	var user string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "user", c.Param("user"), &user, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter user: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.DeleteUserImages(c, user)
+}
+
+// ListUserImages operation middleware
+func (siw *ServerInterfaceWrapper) ListUserImages(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "user" -------------
+	var user string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "user", c.Param("user"), &user, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter user: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListUserImagesParams
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", c.Request.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter limit: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "cursor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "cursor", c.Request.URL.Query(), &params.Cursor)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter cursor: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "output_prefix" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "output_prefix", c.Request.URL.Query(), &params.OutputPrefix)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter output_prefix: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.ListUserImages(c, user, params)
+}
+
+// GetUserStats operation middleware
+func (siw *ServerInterfaceWrapper) GetUserStats(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "user" -------------
+	var user string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "user", c.Param("user"), &user, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter user: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetUserStatsParams
+
+	// ------------- Optional query parameter "days" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "days", c.Request.URL.Query(), &params.Days)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter days: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetUserStats(c, user, params)
+}
+
+// GetVersion operation middleware
+func (siw *ServerInterfaceWrapper) GetVersion(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetVersion(c)
+}
+
 // GinServerOptions provides options for the Gin server.
 type GinServerOptions struct {
 	BaseURL      string
@@ -396,87 +1271,250 @@ func RegisterHandlersWithOptions(router gin.IRouter, si ServerInterface, options
 		ErrorHandler:       errorHandler,
 	}
 
+	router.GET(options.BaseURL+"/admin/env", wrapper.GetEnv)
+	router.GET(options.BaseURL+"/admin/logs/sd", wrapper.TailSdLogs)
+	router.GET(options.BaseURL+"/admin/maintenance", wrapper.GetMaintenance)
+	router.POST(options.BaseURL+"/admin/maintenance", wrapper.SetMaintenance)
+	router.POST(options.BaseURL+"/admin/models/reconcile", wrapper.ReconcileModels)
+	router.GET(options.BaseURL+"/admin/models/:model_name/defaults", wrapper.GetModelDefaults)
+	router.PUT(options.BaseURL+"/admin/models/:model_name/defaults", wrapper.UpdateModelDefaults)
+	router.GET(options.BaseURL+"/admin/profiles", wrapper.ListProfiles)
+	router.DELETE(options.BaseURL+"/admin/profiles/:profile_name", wrapper.DeleteProfile)
+	router.GET(options.BaseURL+"/admin/profiles/:profile_name", wrapper.GetProfile)
+	router.PUT(options.BaseURL+"/admin/profiles/:profile_name", wrapper.UpdateProfile)
+	router.POST(options.BaseURL+"/admin/selftest", wrapper.SelfTest)
+	router.GET(options.BaseURL+"/admin/stats", wrapper.GetStats)
+	router.GET(options.BaseURL+"/admin/storage", wrapper.GetStorage)
+	router.GET(options.BaseURL+"/admin/usage", wrapper.GetUsage)
 	router.POST(options.BaseURL+"/batch_update_sd_resource", wrapper.BatchUpdateResource)
 	router.POST(options.BaseURL+"/del/sd/functions", wrapper.DelSDFunc)
+	router.POST(options.BaseURL+"/extra_batch_images", wrapper.ExtraBatchImages)
 	router.POST(options.BaseURL+"/extra_images", wrapper.ExtraImages)
 	router.POST(options.BaseURL+"/img2img", wrapper.Img2Img)
+	router.POST(options.BaseURL+"/interrogate", wrapper.Interrogate)
 	router.GET(options.BaseURL+"/list/sdapi/functions", wrapper.ListSdFunc)
 	router.POST(options.BaseURL+"/login", wrapper.Login)
 	router.GET(options.BaseURL+"/models", wrapper.ListModels)
 	router.POST(options.BaseURL+"/models", wrapper.RegisterModel)
+	router.POST(options.BaseURL+"/models/batch-delete", wrapper.BatchDeleteModels)
 	router.DELETE(options.BaseURL+"/models/:model_name", wrapper.DeleteModel)
 	router.GET(options.BaseURL+"/models/:model_name", wrapper.GetModel)
 	router.PUT(options.BaseURL+"/models/:model_name", wrapper.UpdateModel)
+	router.POST(options.BaseURL+"/models/:model_name/distribute", wrapper.DistributeModel)
 	router.POST(options.BaseURL+"/options", wrapper.UpdateOptions)
+	router.GET(options.BaseURL+"/prompt_templates", wrapper.ListPromptTemplates)
+	router.DELETE(options.BaseURL+"/prompt_templates/:template_name", wrapper.DeletePromptTemplate)
+	router.GET(options.BaseURL+"/prompt_templates/:template_name", wrapper.GetPromptTemplate)
+	router.PUT(options.BaseURL+"/prompt_templates/:template_name", wrapper.UpdatePromptTemplate)
 	router.POST(options.BaseURL+"/restart", wrapper.Restart)
+	router.GET(options.BaseURL+"/sdapi/capabilities", wrapper.GetCapabilities)
+	router.GET(options.BaseURL+"/shared/:token", wrapper.GetSharedTaskResult)
+	router.GET(options.BaseURL+"/tasks", wrapper.ListTasks)
+	router.POST(options.BaseURL+"/tasks/results", wrapper.GetTaskResults)
 	router.POST(options.BaseURL+"/tasks/:taskId/cancellation", wrapper.CancelTask)
 	router.GET(options.BaseURL+"/tasks/:taskId/progress", wrapper.GetTaskProgress)
+	router.GET(options.BaseURL+"/tasks/:taskId/reproduce", wrapper.GetTaskReproduce)
 	router.GET(options.BaseURL+"/tasks/:taskId/result", wrapper.GetTaskResult)
+	router.POST(options.BaseURL+"/tasks/:taskId/share", wrapper.ShareTask)
 	router.POST(options.BaseURL+"/txt2img", wrapper.Txt2Img)
+	router.POST(options.BaseURL+"/txt2img/cached", wrapper.Txt2ImgCached)
+	router.POST(options.BaseURL+"/txt2img/multi", wrapper.Txt2ImgMulti)
+	router.DELETE(options.BaseURL+"/users/:user/images", wrapper.DeleteUserImages)
+	router.GET(options.BaseURL+"/users/:user/images", wrapper.ListUserImages)
+	router.GET(options.BaseURL+"/users/:user/stats", wrapper.GetUserStats)
+	router.GET(options.BaseURL+"/version", wrapper.GetVersion)
 }
 
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/+xcW2/buPL/KoT+/4ddwI0l59Js3tq9nWCbbpGkfTjdQqDFkcxGIrUk5cSb5rsfkJRk",
-	"XShHcS7rPThogdgihzOc+XE4HI5860U8yzkDpqR3cuvJaAEZNh/fYhUtPuYEK7gg5yB5ISI4hz8LkEq3",
-	"54LnIBQF0zvKC/2HgIwEzRXlzDvxJEFxwSL9DekOEy/mIsPKO/HilGPlTTy1ysE78ViRzUF4dxMP2NI5",
-	"kH5ed+fzrxAp0/1GCfxGJNJJJBUWCmHdrLviLE81+atXOKfr0aQSlCV6tCQvziDjYnVB/4L+iL9++Ig+",
-	"UQIcnb85a86GMnV0sB6QMgWJnQ7NcAJO2WyLQwjKpMIsgkvT0KWMo70kL/YUyBTvBSeXBxNUPsJZDgL2",
-	"gpM3ge8aN9sws4onyiBDkv4F6Luzt9+Pm2LGCaRu/dsmlFKpJohxhSQoRCDGRaoQTlNv4lEFmSHuyVs+",
-	"wELglf7OsPyRs5gmfVYMSxTZNgdGuJRnvGBqiJrLTdSKZsAL5bBEETED7arHKG0t82hIjmUeDcpxdzcZ",
-	"WpEy50xCf0mCEGfSwSbGNEUZSDmAP93+S8Gid1SqAep6VWvLPsiIUmFVOMBSmGkh24yWOP1OFlEEUv7x",
-	"h+b4fWv9lk194bWWfoL04qdfSgEH/VU1A4coBNYTlA+YnIP5kGn0lOSAbgmkoGCjBA1wjlRvqbFvmsN4",
-	"Vf4sBBcOX8+Jw4WYzsi0NRgc+P44J1LicWDYNVzXor/FBFX27Ys/8QT8WVABxDv57JViVcN80ZPTO8ep",
-	"dsJyeFfjBLTwIMIllXROU6pWZm3VUvh7fjBqY2uMdQ00WagtxzE7ngyLXEY4BRHONok2GzVkEucJZo+f",
-	"Yr3ZrWnnWMLRwTeaJTlWC5e7EaD3mzArQdVg+y0Y51Llgl+HpVoEyCK1scx6pBinEr4pUTT83ZzzFDAr",
-	"F808hZDQOC4k5czIkraHkARFC4iuck6Zck2jtEcYUyE7ptWMvxkZnOxrSwZtsouoeP/zJfpw8f58A0MR",
-	"zrYgoywJI8HzLQTVpNZmbeLZnj8KJN1RwkV7nMCfHYyze2+k6+1G6viKJiArTGuHcZols9MsGXQWOL3G",
-	"K8lZaN1XG4M6tNZPf4PVp5k2iPn2CacFfJo1PPnatc/1bh/29Hx0MEo3UZyEBh8t4tkYAxFgnEqtVqkE",
-	"sES1DeTvHY8ahYeMq1DiJYSJoKQ1hgaaC2FNImn6trVosOkiBIXbWhqlpEXfD/9w5I+P68NHaJmyKC0I",
-	"hJRRFZrRRk51iOCzlSkIS0drvs3sty8PCdE0A4rTUKMAwqxIFc1TCqLF7XCclliOKVNhXKSpXqSjQNAl",
-	"CnNMiJZ1SMf38tdYjmnadukHDx0hw/IqpGwJog2ZkQEOlldt92TGG9oVTeM8Ldpa3x/NytCGN1vobE29",
-	"2oKahVR1oTIyBGSQYEWXEOaCZ3lnD32z5JSgmAuQSroUxpcgBCUQSlDaXD33ax/X/td+3eSAeyNqMCou",
-	"IMSxAnGNBRm5ZF0T+sVMBaWYERnhHB4SGgWj9FlJG+NorG+RYbQoBNtincgwoywsWMQZ2QI20nqbLcAu",
-	"Q5XhNs6DYDQlZdsIa3qLkDICbc6eeRQuZy5rVmQMZ53QqWpZ7rvpljo4bS8qb6o9x1TxadU8yHUJru1i",
-	"yPvawCTEIuluL1gkOiDHIpnpDaV39LSEjtnZhgHxSLjEHYIlhqHeAG10HR0e7M9GmhuAVIFiLHjWiTsP",
-	"jv3thrnuhGdjh2HkQdv+mEPKutGoL6PsXRm/BS5lKshlx1OPk12t0l7wYR6+8crWtw8LOWQx75n2h+PX",
-	"46SxtO5g9WhMKKZo2g0vhlbHNSUdDsFsFHA6Z4wBa+pjxjsq1Zgc0tbpvQ2ZrxXDGY1wmq5QJADrXW8n",
-	"ElFnFdbbKqh8TZuFUSUybU0G5nG4DJzBg5QfsDVseyy1AMSlRNrZIh4j/d2aauLyxVzK6SY+ypnStwKb",
-	"tolrNd+b3SpJyylXk/lSKe6NUoLOC1UdU9PfY+/k8633/wJi78T7v+n6DmhaXgBNrcbvJj3UKZwMq0m3",
-	"DqtpP359fHR86MP+8evDQz8meH68fwTkNRyR6Pg4IDDb9/1g7tJciqU644TGNMKa6SV1mV7z1T0187qr",
-	"yc8PSzXzZ/uv/OBV4F8GsxPfP/H9f7uDsYRKBQLIMO91n5FM/WAz06FlVI9aZswnNWvKkglKOSb1ByCI",
-	"C1Qw+7klRv1oM76M0WthvtxpZP2ed/LrbQHtPQbKscCZXtRtFBHcOa97t3eOO4+2FIZIs6584tlQzliU",
-	"HRpusM2/kW0e44HacjSTyBfFPKPqEsurYUftZKZJ0AJLNAdgqGStT7wrJM2YCsjegKv6KFL3JVYh0i0v",
-	"Y5paKLk7/ReWV6ekkzTE8iqY7R8cHt3vpyx5A0kTo4gPgicCpBzWYVQIAUyd9hPMte8tu0xNULX3NU9c",
-	"EwCFzyE1x8xOUmt2OCZUcNryg+DaenqztMz3nJbLy1l2GL8exVhrDDpH2nyBta70wCV/5zn2qYxWy99W",
-	"46RtnMqm5yYR37RoB68MkB4a2Yz9BJUJMWT5WTPKqXEgoEDIKWUx763kdQg9MLzpUDNJgX1nSb7/o/D9",
-	"fQjQ9QIYMslWFPGCKe0s7VdzD267oaDpNz+vUWdzbiXc2k9n5ukDU28xdzh7PY9cAKGRQqUWGjDQT36D",
-	"lYk2Y24yGk4cDPshvf+noIC0HNGzu5+1be+Zc72LNMGvn9lpm4/D83Z5uWtMdXD7TRSM6b9m3kCAmMD0",
-	"5ZzfjXqSK4X2hcJDrhP2Z4+4Tgie5Drh8NHXCYOH5u3vE5g5ny3EqFNh9/ZhXHLc3BQaHx46LiLG5iMa",
-	"o/QPp2OzEY/gvxDhxsTt+7IRLWiy0H6Yp4WJysvOjoW2EM6R/vWQAcoMzc02Z/XmAKutbocWIhyR7QsG",
-	"ZN98o7SZK0SckTDHUob9/E4wWvrqcrkteXXHnYFacDIwAccNQOA/3RVApvdoTNkjLwE6VwBPcwEw5B9c",
-	"szkr57G+AUCk0PNAsmAS1MB9wEBGfzCb60jo7z8uoR9sndCfbZ3Q97dN6AdPlNAPtkzozx6R0H/WbP6t",
-	"h0W5DrCo1sA2Wf3gQVn9YFRW30ZU/0VZ/UHzPCypH2yT1A/8x2b1gyqrP3t8Vv/18Q+Pz+ofbpnVHwz3",
-	"to2cxmf1P0oQ73hChzNmhQSBUt0FCdtnfSbWbXoJIswI0pv7NRekdxauG9pVUGYxSRIni6/OgjAJ4n1v",
-	"eWOiXel9Z5qadrJm3pnt0Pm/Nd2y0+PSdBNP8Sto+3/vz2sQakGu4jQx/xZfif5PnloTlnVjjC/m4sJ9",
-	"oL9cUImoNLlhCWIJIgUpkYUPquGjz/oggEWA3nw4NcdxqmyN35rowhL9VBOdVkTexFuCkJZlsOfv+Sao",
-	"yYHhnHon3r55pC2nFkbdU3tGtKXYoTQO0dSYG3BxC1ptH5NW10fhZkl6VZDuWcWAVG85WdkqWqaAGWqc",
-	"52mZlp9+lZyt3znRnzZdSGx8HcXo2llQXleqi7Z41m7aL9u4ygDQaGHm+88tdAn3YakrjN9NvMMdEKdW",
-	"Yo6FojhFMaZpIcAe783bHE8mo605d8nC4CaHSAFBUPbR20SWYbFqaI70TY7mK5vRm6CLIs+5UBJhJHOI",
-	"aEyBmJcYEI/XN50TxIV5PUWzmBJIp5JMW/em7tVQV/8/0xpwvtrgUFUtavV+xssh3v0ChENGc3f1PDAf",
-	"LcM/CN7lWyENeFtwmrL3RsjpBmbjXYdngqbjbQrHJG0yvsht2faLAtNxU3evgGVkslNA6KjQgIBmyYxm",
-	"ybD9y9L1Z7J9pzDeMadeIn/X7G5vPesLh4Zj2h27J0hxpP+UUlrbaw8/lQTntL1DJeDAgaktIvUO9Uwq",
-	"H6hgcqndbr5PvQFsJcCuGDoBhYw9O57enJWGl7g5bj3TAu8dXh2zske5OSer/rF10jyzvtzS759CB+Wu",
-	"j6C7FNHWJ2QLgPV71oNr+8x2eaRO6wzPvQVijcoy5+uwHVVTW5kFqQ2ud0fVa8lMut65wM7LAquzspjr",
-	"ORZaT6n9mVj9EVDmFeKRy6lbHmVnUtpiF7HfFrGJ/+mtLbDUfuXOzk1Hxs7DGCiorNWsNfjc//kAW6tX",
-	"1elRE7PYN3Ns5ttbM+3pfNLQxTptVH4K14Sh4mEpbD+b9GWM4cpDQFn4t3sxSlM+816xy0/9CmqXjBJz",
-	"EZY1NSNt8lIrXQcCO2vqtXBGedprun4jozxhVzZsI8FmonYIDEMY+Nsd/XZ+vqn8XcRQCxzGxfP8ngyb",
-	"hczvefVCwnPYpl3Z7JhWWdtshX3RiLZb+Tyc02rJKHfY+m1BLQwEmB9uGobBedlhXLRjfwVqB1VQiXYN",
-	"84IifeSzt0FWCwrLKzm9tXWDd9MIswjSFNuhhzTzo+l1ieXVfS7VVscStzOtixUf4kiVeUGa6DjHCrtt",
-	"nGOp1+WfZeHxLpqwI6pWgdN6zeLvobCoWQr/91lPB0SNUu+XDIqc7wIMREb/BHC45HSiw5an34cNW1L/",
-	"9yJDVDK8NC467xPcgwor5q5jolSmRcSN2pzDL2vFnyno6VSi/y+H/xy2Vzeqn8O/u/tPAAAA///94pR2",
-	"hVMAAA==",
+	"H4sIAAAAAAACA+19a3PbOLLoX0H53A9JLW09/IiTrfMhr92Ts/FMbpzMPXV2p1S0CEmcSCSHD9vaOP/9",
+	"9gMgQRKQKNlylKnZR8UiQaDRaPQL3Y2vB+N4kcSRjPLs4MXXg2w8kwuf/nzl5+PZGzmXubyIAznPPsrf",
+	"C5nl+C5J40SmeSipZZAuPxYR/SWzcRomeRjDz4M4mi9FKpM4zUVc5DCS9EQU57MwmoqAeg4OvAN56y+S",
+	"uTx4kaeF9A7yZQJ/H1zF8Vz60cE37yDyF2qgWvcLhErQS08s/Fsx6Pc94ecCvstgxEiKeMLvhR8FAjsG",
+	"cH4vwrQ+7j8P4nkwou5G14OjzJ8AZFEWp9nBr95BmMsFja4Ay/IU4Ee41AM/Tf1l9duKBZ6toDEyBAuQ",
+	"kBFEAPF8DmBNwyyXAJjZBgG+mclITQJQJzKZm6AfzOPUP/CasH0DaCzrlxXzlcvXRn1K31CzEg//J5UT",
+	"aPUfvYp0eopuejQSD6qGa+GJelWLAKhXo1dD/aph/5wEfi4vA+goLtKxdNLfOCnaaM8CMSmiMf4S2MA7",
+	"mMTpwofPDybz2M8rpEXF4kqmCKiMrq0d4fOyeXz1mxzTvORtnvov02lm/SjLfaB7H1+bC3Z46Cdhe8W8",
+	"g2lSXMhFnC4vw39byOjvHz6LX8JAxuLjywtzNmGUn51UHcJPOeXphAt/Kq2w8RsLEGEEYEdj+clKypPx",
+	"EUB5lMts7h8NXnw68YR6BLMD4oVnLwd9W7+LFTPTYwpoJDJoIp5cvHrabYq8WaxzVPtoDvvK01sH9uHE",
+	"ByrDPXewydaO/Ox1HE3CaXsoeCXG/M5CI3GWXcRFlLu+hvcrvs7DhQTOaVmJYhwRaesWnbB1nYxdcMAr",
+	"Jxzf3DsyAwaQyfaWlGl6kVmGmfjhHNY5yxz0h+//Btv2fZjljq/LXY0ru9EiApnlhYVYCpqW4Nfi2p8/",
+	"yYrxGID8179wxKe1/ate2Xnu6zAdF2H+KpX+F0B5a6QxvxdX3ACZfBDfAP3Db6B95DRBEsOKQfcNhOoX",
+	"+HcJzCzPkxe9XhYcIlaO1IsjYMwu5BapTZSOcRXHRR5eS1G2MmZ9aqMmAC/6DFQ4t2A0Cm+JNJ9kT6FD",
+	"kMa0dAW29gRJRJJr2IU5zuBZX/2nEz3jilkYyngeZzK4w87vZv588nNjlAM1bHMB64LJWAoex0AgyqjX",
+	"oDRcIo9/VQRTad2jWvzA6uIfmbiipp4Y+4k/DvOl6MNm8CMU7UDOi7C97v41jOlfzWVt4c9t2NCd1loO",
+	"+ramN2EEdHcpYd2DrNb+zNK+gZhyHM+ArtknYgj0gMs3f1NYcEpvjSYLWaJ+V73uvtW/tQd3MSpc0szB",
+	"aZTStgoCg1V3ZDaKf9zhCN0ZC6tUnzOZvkPR7dbFSbJndjnzRS5Jr+Q2oDMXsDGLKABGVEDP/FwkoN6F",
+	"t3X9mL/oYatBTz0f5X72ZRQGo8FRAmBuoCk36EmB/Kt1mg6VVRkP7mlW5sWWUOkOCKwQP7oqlCZdQVUf",
+	"HACspBPojKDVT4BbzASrtq29TRpKnaFnweh2PrryMwlo7ddMEQtD31Q7N7ZDF9Wc4atr5m+j63fRJG5P",
+	"Xk4msBNQgLDCTISmNDvF8uFFKufASgMQsmmIfAOo0C/yGQpdoGf4QJBSTWoz2HTZF1rCpigkLd0PghDH",
+	"9ucfaq9bWGpphhoI6BZ3HEGLyiGsmobYJP+vB2//59PHl6OXH/9+qRV4cXgYxTfyqkBV/vLN6OLnN2/f",
+	"r16/bxb9bjKXt0hSFjYBwM8lLtjdApAf4l81dmE+bU05Cz74+axOWr1FlPcA2TGoC45vwFCvf/Ps/Myq",
+	"zsMGvZbpT2CVWnZBGt8u70AK5Gk8v4NdHNUtVv1knfRFk0vNowSuNrKBPqLMNI1Ti3FoRS81FvTOgO2k",
+	"o96hFVhHt5V+W836lR8IzbTXzV2BpbuhyeGuIB38nTbqGhzRz/362iERnp3chYtpwjhsrWKk1q/6hlgx",
+	"8/N1QNKAFtDcogmnhciV6eg6zMKrcN5UVg76R/1BJ0vd6OtGhtNZvmU/xG2yUZFkY38OnQ1XgTbs1CW0",
+	"GJfCsd4HPnxn3XzTSTL1o/vjhRZwNFfWUyeh0CQtiy4DKp6ysrdkuuN5CGPCxsh9pBshx7MYmT0iRElH",
+	"ctEBwUzhp/bnnQkemd6d/ONVnSv/Fl8BMl/gv4eIHdROPtI8C/htY7dgKCdFPlIajkt5UBoQCjD+oFSY",
+	"IglSw5/PgfMH4mqpDGbV6gN9VbebcAfg4FkvkIvYIcLDf0tyPjaW/G7QzajPZvHNSNGxoRBUPU38eSbv",
+	"0Ll6YPOugsADQTwKwsmkyAARI6taIoBaxl+0QdSahtpAo0mYZo29iAPfEQzW4cutN6h/djkufnr7SXy4",
+	"/OnjigFhx27xGfwYjYF+twAUP+U1q388POp32qDNXkYNKT3oD0+6rXurp5vtemrwdZMga/yk5PV/svkH",
+	"59ibSu4/CkP+k/v9yf32nfsR42tYzk4n1k8tlRoswsHw+OT07Nn5c8fRiMOYkKYxwf5S5TSy2G4Xmmzt",
+	"5yCC7CZSWjSodefTRn4H7aoyJ2o/vG2gt4amCuyqR8Q1yZfXoKgi46FptEzMaCrGVQNxhUJCiiIBugsE",
+	"mM5jycdvpq85bHQLZj9u/bZ/Qfcsg1fLXNZnOTh9Njw/O+lmJo6T4iKcg/BszyCPc39OHnKRJciJ0U1s",
+	"TNn0vXe1SivXXwXu0Oq4T8NpCBLDMr3z82cnx2fnm28cNXizc6+FTRMtvNrTIfzfqU748xt/mQFjZvTV",
+	"4cWABXz6D7n8ZYgkSr9+QWcS/LZJnCs0dEYtDnZ20m1FJ9MRcd7ax8MurC+QURyiV2eEpz3RtOGe6R+d",
+	"d+olzFhe8TnmKJJTH51ulmPJGCR4ArQVaDNFffOT+uQt9AnKQzTNRB4L3REYR7BgNY8NCQWbTAjiEYwy",
+	"ynz4bJo2jF1XMIf5UUZt60vqHE02HBxnnVZs1lYbn59tsJ9G91hy4EPzIpCjMArzkWV3Oqfq+kBts8FI",
+	"6YX0a8i/NopUwQFCfz5CkgRph67EBDTCtDbaaTcsRYkPP0eTYj4fWQ8XVQtmxezTFX4qfQzVwa9Q34zn",
+	"Bbb2ygN6pbCtJafm8IAMImpHHI4aXjUSCRjsoM32D4enZ9XYx0OWGNiYHMOo7TYH8gBs4G0L3GDHw0PC",
+	"Tgnt8XAT3CFTmABHtIQ4MbjoTEUh0X8hsJ0nBi+E5rOeGL4Q6M+G97Scnjg2HlCwlQnroHbeuimYC3Jr",
+	"RdcytZx/AHh6rRlwAlQ/QoZUevQrvtcJgj+KvYPzd24QJEhsAEpLlgnc1LDIgo1BDygStjfvm1RS+zoi",
+	"qe+Ry2Ckl1fzIrXTmJABqJj4vtoSOKjeESf1DWHS08nhec2F3s2BrsEZWdxwMyDtfwPFg4JEAxJYDM84",
+	"BsoT1WTuMfDSEgOD5DTexbDRCBavwV07anVNwVwzLl5ex2EgUPnNcqumjoCDZAZRK3MksJb6xI9L/Yl/",
+	"rlKgWj0iM8wBgpE/gTne+GnQUcqhWmiLm8BjCYqMAe04Ik2dW+IzVmU8YoPiJgzymSdYxNMWrbQ73C/j",
+	"GYV7AnjW0EmMS0390GrRA7JhCOkGjq0q1UzBQ888ce1LAkZvnsRP/UXWAaAsABtsDVQtEvgbLT7wqSgA",
+	"NSWRmziZu7F/vb4Tf9xVgclG41mRRrXG3XZKNlqE0QjMxDgKnBrXqs9JBta+PO74ZQ4sv46eQecvw2gb",
+	"YKl1CvI0kLeNgzh8NLoeWq1v9Vn7+E6/uT62f3eNjq60cSyMIqOHp8LqtXNUeG3RSV2KGW+akZ9Omzos",
+	"PEJpCf8MUWttBc7wh5bZ8QsHeMEI9l39A3jgai1lnbrOTk+Ohx2XG77VTqcJbMiGD+vkvL9dNzcNg7Rr",
+	"N1GwkW3RxeFZvST0AXW/VxbrwIbMXCZZQ7Z1DN9bzlsWDj18eaDevtrMrsmKq9bSPj9/1g0a/tZunp91",
+	"sffycK4sj7W7g0RXw1XTiXAabhfHapJjBT5JQZ31c7k6WmzTw4eF3dUYVuNpSaglIGjfSU3S4YO7QMok",
+	"8CPASlqsDTeoXLG1edm9sSAHc+U4NOJORDKL8xiFty/GfocwDNULDorxyF3CCbeOe14RBLkEVohqKZiv",
+	"HEkKdsI+xCRekBESYbiUOzWoSCn01Qg1rQ+NAUlCebUEaZCgFlFbg3zYf7Woxrvwb9+onr0qhlaCalqz",
+	"bs/71uhX7fjd2H2tP/y1PvvLEq3NxBpoY4tSjND2nMxJbQW2swjZIY1KI7xCnx2usZ8tozFZqJ5Ahzy6",
+	"6UATSzr55rrPEXtLYIYv8zWrg95qoKBF8iR7WmVUuHBPkdy8Ap1cDIwOi6ecbM4SSRmgAHXttIgiRBKm",
+	"QGAqFceFmRBYXd8Kt5+gUxsxlhjPBBB0IQO0wEEcBDJVg2GyFo9VC/Q4tkoUPIiwnOvw0tTwuXUQvINC",
+	"DYw2Ju2VZElUrHl5nXIja2RflXBXdzyoxDmr/ZllOhKxsawzabg5MB9OSY1a16VyCk17q8axp94xwPTO",
+	"s6k3a0WA+lRNWU+mRNzLXIUFq5OK+c8T+KpDhhyA3JIcuT91ownfutF0PHl2fnZ+2pfH589OT/uTwL86",
+	"Pz6TwTN5FozPzweBHB7DZryyhxJkOcAUTkDE4KCfQtvS47jYEgcvm/L5lROqYX94fNgfHA76nwbDF/0+",
+	"/O9/7dapzn10j23kR3YbtD9YPWhmTUWLo0Pgdl84C6208tnE5xBm4HjsCivfIFeYx+g2wk9rHGhwPDwb",
+	"np4/P+mczWKTzuVEVYaSV2KDXLt45ln+wRyriPjvRqaoerQm8BbpsATm128lsb9haWyTc8abZgIHS3Dt",
+	"9WBXiFf+prBvoV1KIszrDlbj7OVZ0+49ePPh4i9/EcML8Q/Ub7KD0hA57rf9Vq3gfgWxMTsjZ7U1Q+cZ",
+	"/c0MaOJLmCSMeFSgGmgfwmpMwijMZki7JFqKrKLcDkG5zuRkqy2machgd5zJcHcTF9AT/7pTQN/hWdwk",
+	"LqLgroR9NX0oXljSh8ZfmH7OrJHJV/qU2XYCrjcabqRM5aIEYeoJdXaGkh8f1ITtoP/s+NnJ4HzYbV9R",
+	"3x2OxZOVIfMsfrLee2vC9YYSyJG1bRU+ygy7UqfnPJsS7x8qR+UPtyk9h56hvKqkaXi0W0hLIDiZ+3LG",
+	"5mbOU5uHiD6Cpx22VYcosRUhNCtdKN1telry/4uaaXvB522vxdB+BHL73tK070yv3j5DyZFPpCA1QSkn",
+	"9xGNvzGs/las2MXDbGx1w5AnC2OtlBLFW/WDOy2A1XP+eeeGTjMPAzoigjedNVXFlhXPMLjzz0kj+7KV",
+	"+Yu2NfOAVihUO6vk67eDtcJVZ4b8nGVv4huauz3neFykKQU/gYbLyYeBbi/8cYqPFVPKalnDVb+vdSfj",
+	"pTpOaXmCLDmxZ3YT9HP74GBd1BN9Y6Te4rQ/xFn+gVPLbLnmxDvZrqRzFUHnKigE2dCUqjxDhsF6HNla",
+	"D3DzaqfWWEYEFMQJxgKUdR2cEdlVFHVzMXQTwU3IreLfCk7r88RALDA5T/3qH9bCCPpHp138o62DpKaP",
+	"bCwVUti8UF5EjrS+q0CsuxLNx7aTr2rIRrD2mtGrxjTfyh1Vj6A46ncPfLWVBNBvlLTTg7z3o/G/43oy",
+	"ycfDt5cf//7yJ3Fy+5fV4b1VjK6d+mCyME9Y1cNzXFr05ahXNfuly9xwS3ygc8lPEj5TCflNAqT4FYsS",
+	"qD7RES6YGR9h2qzELQ27BEg7FbpVM9wBOG0SSkwvvUK95ffC59X6+pU8H4CIb99WyQIHLBupHW6No5XU",
+	"xzhADlEKuUcuzNMUrlvV5tHO7wuXIE5VA8Pf7RThXVzNDSXCyIq8DF4D46VNGkp7FRjK0aV6C2WzVj7x",
+	"bc5xBpnbV2e0qSXGZ8EhjXCo8l0jkEEbnVhNJEhqFTa3Jrap5rGt1PFqYOXgxTy0CFkN/rRp3eFiOoT/",
+	"X1oCav9p9ld1tdkhHJsEzY7fFsjl0OxpGgmbFdm6zXcJPDp2W0dHYHc/O+qvJU39rYGCFrwt7HsHNdoq",
+	"6YHp+308tThcgMfbyD0FXgjaFNWeCuIiF9QONKd5gPyRs1Nq5EtcQTuOQL6fHg2ze1RyYLgIcjmffIJB",
+	"nQdDbovKkcCQxyAHsvweWQvMvUeldLEM5l/LhhYmZn42Ez6ecNxUgqnDEUx3o67ClV0MIAQWWGf+8PSs",
+	"rTauPLHdFnWJjwH8LkmkkDJyAIoKWGAIdWq20sRquANocGU93VXD0WFow9umAO1qiSpQyq8Mu+ly5qfy",
+	"fRh9sRzy0nlZZjswKwsf6YMd3INfBH/RPuQ5Put4PpbHX2RkqxIxjdCtiG+RFHAwK+mnlq2VFFfzcCzg",
+	"HbnTK5uihlEsMZW96PWM2lK9DHET9PCTwVE1k6MsXF83AEHR8/EMVBLOAf0WdjeuVdXK1pbVytx1tbQd",
+	"Wf7WBma9VMwqTapR48siQ8btylArO2w016kHjRSoVV20Uqb46K1mfa/6vmapw7e/axfTqo/wPJF9URjQ",
+	"4qeLD8AULH4ZeFPWVaGl0W1Vib6uiP9/8Jk+PbMUfLpEy20q7dVp6FSnoEQ67W1ewqIvxAyQj0e7ZaWR",
+	"Rl5fKuUruwObHLKirIBFp/NB/fjn5NnJ+fFZ14iwhfKjb1ptU3veLZToKo9YnV5l4ibEE1jj4KvrejSP",
+	"QC3jJxtXoaFjAUtiWuny78gwC2v63vnp+fPnxyenz4dbnK3reKgKQnMYz6AVcy3LRSAGh3EMOW4cdwjR",
+	"2Af8Bo78lZ5SJ3vcyqM8GTZOubgI+oPkLXohA1E7duJ1DgNYQopxLz1+a8NDxnOs7hnYBDMFNqv3uAH0",
+	"EQLVssOdhikRDYsJzwPEeV8c/qsAsSHFaX/D3CVHhTMOXBOU3lGkoVnorHIdagBn0sejpv85fBeh0nrI",
+	"NQ3ImYYIpXLB1QFVtvAx6wY46mcUljC3zUqLoXPxGiSeo9TTMhq/KN15CCOThqcrUpJpLEFF/yvHBL3g",
+	"BfXhaYLrjNByuIonkng+d8py/HhNCnLVGKkUdTVxJQFxyjzH7Kclh+MgDo4cMR+fbfoGunNB+nvKjVn5",
+	"OavlURMGwqL0NG64ZY1P07VwVS5ZuwOuydOq08NJ2+sP6XRJH0N1RMy9A4gte5tUVnuYQ111REJQgXD3",
+	"KJA5TQpbkcfB8MgMigdbkUs4ttyW9zYcKFxk+QATPutvGEPR8CzJoPvyNwocWhXqrJHOQ08G3clFMcYG",
+	"1djL3iZKbaHoAeAKWE/9asm7PGznr0fyNn9dpFlsK0BLz6kuOrQS2LMn5CLJ1QaMYlDKUslD/ZXei4W/",
+	"BD4AlvEci+XlMz9S/J2jGdTZBGjULvx2V2jKnbPO08DdarR9UFl7K4QqnzS9a8c7lyFmqkmPmNLRb8nU",
+	"Nh2gVXgPrIQU9hV0rUhZhxHSWoGxI8c5S0pVngAj/7kNcO4s5IBW5cehbyiRc7wkHl8FItbUaXVMogMy",
+	"1TrmRgQjrOosRDt8eQ9eInP/IxZOVNnmxuHPsNPpD3zvjAIu0VriLZUY7otz5QsEuHYF+VrxaKrUeEQu",
+	"0wXnwdYQUERfIrBoavMddjumuneyqcoZLWGpQljzhr+imUNqzRm1CWd1vki0QPR6dORIGKON0ViwZ53w",
+	"QPTzQVGljcwbYU1Aihlmx7Fx4aOKhc+MHYArR3wHVcUictLs+sha+bs1bIQmK25STJXNUNWj4qNhhHI3",
+	"o0g9jFNecguvpB1eIbwwgpkfsTiMdqR2fxUqezgAIii1M9anYRCCe0qCPTU+zWSjtPVws5LWBo0koIWp",
+	"YnJq0a2Egpq1lePTGTu5pMnShWbkNlQR1EB5NThP7WCpHB9rEJfZdbai72NrkMlDaWAlsdd5lVdn/lpm",
+	"lFE0zRq+HHzPJP3EEgX+FCdIRU4pSZwDWNYX8raE5xx3D88Z9PubXH+g7j4gEq3PCGbC4kZD2d3UL309",
+	"q88A2sE9NROYjV/ATVCsukAgsUaNY+VdOpQlW5NZqrbp6oHjyk62xwSUZxJ2Bs+GcPNshQ3HqzhYal3M",
+	"0/m/PU5Q7umYO5T0ILDRHlNWqbJ0AEc+agBGiv8XmeRrQ2qU60FDXuERTTwTiU2XgTRtwSqqkvdMT5X1",
+	"JjsetPg064XoPWsdkOrSzpdGEnljpGZWOFineUE5S+giUZk9C5lOpV4vj4uOqwAkI2EaVVwJHDvHXI+U",
+	"wlTrMjPT8bBGVbY1hokO/jv4CXBiL8VMxwvvnM6FfDwD9AUhcOAn/UMcJnha+Rh07Snuxetg0lYukYF3",
+	"bHGAmAyyYePWrbrWeRkQI4CZV4HyRKoO86quE7ktQWddeU1ifFqmCQ24wBP+5Cm7eQaMDcbjGG9E4eIW",
+	"+JN8LMobNKh7i0rtXFUmZrW8/nRIT7fwIb2lwweLY0utaVIAuZLeSQ8+Ij1hizf+MlNBWGVBEdzGniYG",
+	"JGblLCLb6sBeucbmqKZe9Qqq3WiQPj75h1wSfUxiqtNgpeYfQYclBxMKGwpPr3mYtvErbeQmqljemiVo",
+	"u0m/8se8CvSnexnUrndltZmZbF7NG4Yf8tmucuJZE7mtx6VRPOKCxC9U8Evp/yB9IC7rGYsnUTa5wVMA",
+	"wAPwXOpDUNXBp8iCJdEfEbNav7822NgLkcXAY+wsUO+FMm4R9SiKR8Cua+zWlNw3fogC5E6h5a6EXh8A",
+	"j9Hwnc8pd0HN9K7FXe/t5vMOCHn/zaTtDCwCkhdhUJ6fPiGMwcNR6dd6iuVjUr5ygBUy/nIeNy4GG/aH",
+	"J/1BfzAYokm+teORdQJ3eV7+zkX2YWDepRcAPjHXC121ZJOjRdcIf2BIyPMF/w7vET2iIWvNxB4WAWbX",
+	"3zBxxT0TsswouaUN8PFmAsMIt2sFu5HGh6eKOJJSs8PqqCFOA3L1dXaANbS6dWjToHkVRgiDoAC/W0wv",
+	"sLabkxgUd1sHFHeleykL0axSB/AuFG6lS2l5ipce8WMRTqO4dRGjzsz3RRDf504ZDaCnp9hGiaN0gOuw",
+	"i2MexPc581q3aXFY6YMqpXEblWS3641ap42ty3fWi3duUrqzY609ex3HwYOU7jz9Y5Xu7PTVZrU7KYx2",
+	"NEu71SNoJJ2cdkzzS7OcXGUjS9HPrkEYRi/tGi1di/LcY/xZOlpZ8U2TgJjBEEblTFEufmsvQ5e2nv5r",
+	"kw5UoaLbbUrWmB0st6rECt93KHo1cMC+unrr6lHJYh5hfOCoXeZo0Bl6M/3DODJUZePBEJnFgWMCf5TS",
+	"l5YSiIP+w9VAXKBLwQ8jexXE/bv3pXthxkZZxocpyuhivUmcIZ7K5LVVaqGZ5/ZnOccHKud4oei4KugI",
+	"Vhid+WZFxCOsTnLrVpzPUp/x+H71GQdb12ccbl2fsb9tfcbBA9VnHGxZn3F4j/qMOy3O+BXLMjLTgT8U",
+	"w9mmSONgoyKNg05FGtlX8wcq0uhcns1qNA62qdE46N+3SONAF2kc3r9I47Pz5/cv0ni6ZZFGp9myrQXQ",
+	"PfeHIrffb3JdH16O64z3Lm/ObRviX+TSqfnUxFaHu35XlpGyOU92EupnzXDWZVIMOj85P33WjWtY02RU",
+	"jo1Kk3HkWDVWHHH6q7keqwP7qtuXjfC+am1cNzx3phfnxY4PESpY3vzSoYBnVmLlfTwN3VUpCCFzbFKd",
+	"Fuuza3xHahsZLWCZ3cRpOwiyfFG/FYokSBZMprPfXGGd7Suc/AD1h7WJTVl5L205eGO2rnP62nRVo/sl",
+	"TRsZY1Xr32+gu1nwZTKf0n9nvwX4v+ChMaGTu8o+NBrK/C7L9MvkHHagq+Q+dZkT3y2vgyYzzvQhpZvf",
+	"WG5xCvxlXWrZg4+2OEtvHKAfH53c6wRdnZNNZSRTX8U5toY56W9QYLKOrV3ElasVeNWltBdWzDQYHPmm",
+	"0ctQi/2jd0grn8oqDBRVFxcJ4uMrdvCtdivW8GR43rW6aBmEvMaFYnxicbNz1AJAo07cag4RVdsHla8q",
+	"3hyEj81aJ/yUJUhr5eatiUxbRZqrUwjaBppSNCZqIHjV3V3GZtBb9rO9xgMtKDQv43Vp+1b3vJv7prU1",
+	"nVkJXbdSbi/fqsJKNAuhw1YTRry0JA3qysEOUK5x3MDmL1wdwJ6feFWE80CoAgIk2ZSPKSsWCz9d4nbR",
+	"UVEtfNLHOha9fqCsSmFSHUxXKUy8ySVsuCGC52dXk3OrZNjVnfUk/95ayhY71XNgI3ZAqBznXSoXcaNa",
+	"S/noQW+0p/e2To1iEo3lDiNYVL3eqNv8xpkBwF3mwWTuN2IDrjGibZMCFGpNPYM2anM0lrHCY2MNkGir",
+	"nNsOeb3KYVZuwy9SJsD+8bQCUHQljUw2Eaow6VQdzzeUnTDCkTsUs7t3ZQMEzJ7HQfDRe4ZStJL77yFB",
+	"bzpMz1G0QCNHdfIrZT/bo8k+YWlsVSGMV59yeNggFaVBioE+qjjDyw/vKPgqzPlC2eqjS/6oLEkn3kVV",
+	"NYyS0g+YUnF3JjLykxDLEyviRU8WLW+P9MiejK5JHLDXGNefKm/RwcLfZf4WXntlNSH6cNjvN+pJ+QmH",
+	"qMB3vd8y3mts+qy99z66Jl5M6GteZcBULRBCekuu34cbGmOsbAMXUZkjJFUb1CdIBGCajA7E5ZIG12SQ",
+	"alhxfSnOe2aE2ILAoIxfYKFcox1e5UUaYYAWLQJJbBpGLQrGJWFutmthPoGio+rS1MPo/ukUxyqfCXrW",
+	"FWm0L32IUUYq3KhPRcvRTCkkJSqpkqI5X0pQ7QQlDypUG0UsLfIc3Zc7IyKFCctSVsV4KNJrj6gIESoq",
+	"8KpwNBdJGBcvrNqvxo0Iu9y37YsXLCgwQNbq+h6twBTvTTAgRM5ugImHcG0MX7YxTJv8VRwsd4HcMtxr",
+	"NXbLANlqg6q6Y39SwApOTjoWhsDr2zWa9OBRxanW7RfMvsVp/5gNZ33hg7lduVB1qov9kTsstrnZqOQH",
+	"34vKCUNVRVtPP7qZxZnkQvcEBF+DQV/CXPB2UqPafJ1iG2UO18kLAoSzPbH/MV5zCZ+jvNDl79HfiI6B",
+	"8cyPpuqai7bEMMoWrpcZjotfdik07OUfLUSEQZQ89QofnAocFzl0LPeJpEt6q60WGeFmuRnMEqVaO+XM",
+	"UlQIwBpKCxY9igac0ojJ+yunAOGSf+uZxc6d4ql2icEaWtRxAbr8nKY0lYilCK0CocX/rLRWVhaofdi0",
+	"6XZJenUk2NgmL0ot1mHvRKcDxqSwrPxnqvr3Yy3+DuT6VuveUag3awwH5RVwzaAZfV6xRwS1Alztl4F/",
+	"sPqkqryk6neWoT9l7jpG5xh8SgUTuXkSngl+0I3uuee7Z/HqCyOsNdRsNFHOY48WbR5meT1my2m86Pe9",
+	"r+ovFhlMt5h51l4YvgtGI6ojs6hBY+cYJgAdecaaGDKXrGhe+IHzqUO4j1vRBied0Tkk+o+8Qg/H1csN",
+	"vWYD76cUN4BbIb5/nJXekejuvMj3F9l7zCA4KIBuOLNA7FlEtHZrh3RwWxJbJR4yOZ/kOnPJ4Xfh8sw7",
+	"8rg0K2VbsKJh/B6+lkZx6lXQpeWlAvtjktKFYmM/wggulRtHFilG7GOxKXR04IC+DmTiet9c26dRuLpG",
+	"NjqSxSWbONRllwtDA1jXA49sBEO4ZzzfhK2K6MJ6yRytIq5UCWQT0xRqshrX3GSn2K6KC9twriKSCh2Y",
+	"uTcqVa3usXK8oAOpfsMlO2Fmcp5kYAON8dq3m5lPdbqUUqaq/EV+JrBCg0vbLrI1a/VZ3wjSwRNIns4q",
+	"LgzPMlWIhc3rp1518fk54zl2qaxVob62ZaRZJhQHpwOQ9mTbNoN8SijbNMD5OuqehYwi5+MiHUu3cH2F",
+	"X7CO91E33o2cNUa6DPRYK4Su0jD0ResirYP3ONLXAbSuDOCE2tDdTvcAnBKJqk4HVf8oUrmHfqAsaC85",
+	"qo5E2p64LBK0ADLhiww6o3B3Qd4ILAChPsSK0ynGPfKuAA6K94eWb9274Y2cX75R8VG72ANl/+aVgBZU",
+	"laDS1B6V4hsgukmLjhh2Q+adYfiByFuJcYO8mTjpgsERM+4qQtlOnm+xLfGAsm7PLqi0OcwKQlVl2Uhx",
+	"LxK+/E5VHhrRrqSMZ67BdwcWoVFH75HMp3bB/47TUJkAe0VENjhNMupEQLunnbVk05jB/hHE/pOCjQiU",
+	"28W9/u+gwbvFdEdrr3pfse6tom77tu6qoqmGcw9dcOhWA6sQ/1FQqrUHmKD5VN//aV9/o9GOaKAaYdX+",
+	"r1qVCX2PRwo1GF1eNRPE1CgFtl/7HyhBedWeGAA/ZZJAAQyKr5+Edd3XeRh6GZS6745QT6N0Uu0yVusf",
+	"WrXcCoC98uDhejZ0SMoacO96Sn7c0X5vpZJaZsWJlVi4uZ1E6pkZpI/HAto5oU6493HzV/mqTABVGXLn",
+	"3i5DAB8pzGHVvWQWVFfxBGS272Okgzsy+KOK2rxQwUo7O4U0keoOIcL48i1jh3T8qVoL4yasoHxE8aGz",
+	"OP8oJ/Aer5+4mUm6J1zfOqAi2hFzoExx6fGUW4uqZHi2XydV5sTNXcVO1cMqVmWFI5UDV4yttiM3qjnO",
+	"qjBxjiAuDxEe33VaB3R9mO0ehtWaQTEZeiJRbNE9DsjUyjBtqrIPb1u3smRfuIK+SVJm7Oz6QCjNVrqH",
+	"XuwsYHKUx6OSlu4fC7XnMVCrYp/2aVEmMdbImsTfI/pptUiq4oz2cKkr4Ah5VQSUOzinlWJhRDfvBzE8",
+	"clxUd43kASKj9jx62cnie0GYKTStOHsq23w3atokc3unnKWBC7fmgLVNqsOyVGukD34g1RUeuo6hBRCd",
+	"R+2XtqvgKiHFZOYsVvusvCyCaseUJQZA19H1I4QqxmDEHsTJmsNVZpU/q2a74Unc+wqVWFeO5d+PqQ9r",
+	"T8OFqiPlPs6swbjPORt1QJkM2B05ylUZobXZF9D4U9n2MbwT9TG7OCeUh7Wa0r65J8gd1IbSthy9r/rP",
+	"DdIwTHx1FEsNaOwCqgZKRxn1QLkYDfj22BKxLe6apIwfe70eBOnNXb52V++bYeJa9tVJGj/Syj+89N98",
+	"0e9jk/wALKSVr2GjKroHUnxtCIZvWCFKFUgnSYJFzv10RZ7GR9Wgm5+Zw833EGcaNHZi42EbB80zFvg4",
+	"dewn/lU4D8uCeg5e/Npst9MqOLWR7KeZPKEa6Ht3tqmArNXhq88M14BuggJNBsucfluZmEAtqzu91vFE",
+	"6pEC9fFDLJb0xcEPVYVVNx98TGFnu7TM7ovTgS3q2pF92H6eONG1VBj9YXTtz0O681mqC0LbpGJenoIV",
+	"Qcv14k6oXGNVv7jspFdWrnTaJLooZ+cEifLy9TJBAkePVHA+cVyuyimBE9/o9jRhhpAM0V1lVHjdQS/L",
+	"qtpgKV92gqYqwdoBIiWlqMQv38/2nwKL/a8unWsDEr/qCOJG5QI7wDz+T6C8zSHO403gPT872RLeFave",
+	"KLLRAHBhnGmvX/ZNHIgtGLEOHcNGlxdSPduqUF1Zp84BqL5PvVudui44q4qzs54sr8OYbpKeSgcMXLJ9",
+	"NRCPLRhcGU8lFSR7ljJHOFWEcLVUDJXZjyfMfZdiDSq+HpcLn8JMwkiX8FZrUXH9nnGHqF2DBY3BuPd0",
+	"Ry5Kyx2xjtXBK1Qf1UXZvvS1kxqR7evhXhNMLIaurjwvi1ST/C0Ldprk8pWv2fzWUxce+7kqJWwnntfU",
+	"ClG4VsvktXVolvpa4U2O//TtIHk8YmC39Y7x19Xl2+pKur20beugIgqsq6fnsMpOwFX7oNt9t9XDY/yk",
+	"guKxBYVGQFcbYp+JwwanlTpSCa+DYnVhVeaLuuF3pY/UAOPxTUw19joKUcYZl+faFxvzRNmYJmxYNYwv",
+	"JbAamboZhi6XV8zjJexLvneas0U8VESCeEHVLDA+cu6PWQWhBxgY5qkUOH0FByy8j9B53CVYhcpIdNCo",
+	"vip7NYF28nDsmDoVDD+S98MTbz/5UzGDdcXSGJJC/o/7J47Lvo3yp+pynHeTw58ApsML0ixk7k/3WxGy",
+	"Ehl5UFYU4cHX31e5QQJjKDvYkuQKYj+SMsArW5I6eQ8NPn16z1blWf/k3GlYshMqe9e1oO3x2eNXQdcz",
+	"srp+K9+YutRpD48o+JI3jz1+GEOeFFcACYNdsV4kXxfT5NpTbhL+BA12l/yoev8z+XGnlfNvc2vyo647",
+	"NvbHM768ZiUJvOZm+0EIHqsJVBOIL9d8QopK86ZN5RoV/ackgujW732jIHmLSRSBqIXAczEvXhpB7B5d",
+	"+/Xi6vmecSUUmgAkMyfiSgAA7I2qbFl5ruBpt6pl0iHd/TH257r+bjgRhCNYdQnyQ01e+JkunVenZ4q3",
+	"W0vOF9Rqp9RMQ6wkaTwo5rvq+XhOfEd2Vwfa5chi/w+XqMNltBwrKX1lrzkgBQHjTIVeBC6ep1ZB8Rj0",
+	"cdHkkioAQE9d1xarMh2RBtHvCvohXfzXM+9OXBUsVl52utaNQudinAFqOyzzSfbQeZmymawHZnX1snVe",
+	"5lYuVxYg20nhnxp21tXG+F5JW204XbtHRacpaPc3fE4BqC/5pYiCufRTvKZpQceMSGR0b1O1953xdXiY",
+	"8sPTuPXgTUHQPnkbdLgiapOzt0F/jw7fLHYjJq2q1UhRL4mYn9JZaVzkSZGPABpQBDAcHp1DEqPk8Qpc",
+	"MYnTVQUSa193PVIFXo0l8LNeIBfxo1dMNG/PdvOqPTxEpHWsrvRtcIGCytJyCQ285lsvqPMAsSYM19ae",
+	"ra5a7sogHm+vq7uJq1vh8IJareuYx6sVByj0dN5A0yfH/afMD47Pzlz3//Cdt52cJY/tKqnWxloFdB8L",
+	"99LKoOAq6yGWNVn5XufIXMu1sobm1xA1ROTGbakuyv6lvOB0ZwtkXtNrwZUODdQpm/tkMbYvEVb1ltV9",
+	"YpaQtG/f/j8UhyvLAgYBAA==",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
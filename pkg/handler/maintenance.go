@@ -0,0 +1,186 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/datastore"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/models"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	maintenanceKey = "maintenance"
+	// state shared by db, reload interval
+	maintenanceRefreshInterval = 5 * time.Second
+	sdApiPathPrefix            = "/sdapi/v1/"
+	// drain poll back off from min to max
+	drainMinInterval = 200 * time.Millisecond
+	drainMaxInterval = 2 * time.Second
+)
+
+// task submission paths reject in maintenance, sd api pass through by NoRouterHandler too
+var submitPaths = map[string]bool{
+	"/txt2img":            true,
+	"/txt2img/cached":     true,
+	"/txt2img/multi":      true,
+	"/img2img":            true,
+	"/extra_images":       true,
+	"/extra_batch_images": true,
+	"/interrogate":        true,
+}
+
+type maintenanceState struct {
+	Enabled  bool  `json:"enabled"`
+	Since    int64 `json:"since,omitempty"`
+	ExpireAt int64 `json:"expireAt,omitempty"`
+}
+
+// expired auto disable
+func (s maintenanceState) active(now int64) bool {
+	return s.Enabled && (s.ExpireAt <= 0 || now < s.ExpireAt)
+}
+
+// maintenance state store in configStore, all proxy share it
+type maintenance struct {
+	store    datastore.Datastore
+	lock     sync.Mutex
+	state    maintenanceState
+	loadTime time.Time
+	inflight int64
+	// async tasks pending in task table, nil not count
+	taskStore datastore.Datastore
+}
+
+func newMaintenance(store, taskStore datastore.Datastore) *maintenance {
+	return &maintenance{store: store, taskStore: taskStore}
+}
+
+// get current state, reload from db after maintenanceRefreshInterval
+func (m *maintenance) get() maintenanceState {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if time.Since(m.loadTime) < maintenanceRefreshInterval {
+		return m.state
+	}
+	m.loadTime = time.Now()
+	data, err := m.store.Get(maintenanceKey, []string{datastore.KConfigVal})
+	if err != nil {
+		// keep last state
+		logrus.Warnf("read maintenance state err=%s", err.Error())
+		return m.state
+	}
+	state := maintenanceState{}
+	if val, ok := data[datastore.KConfigVal].(string); ok && val != "" {
+		if err := json.Unmarshal([]byte(val), &state); err != nil {
+			logrus.Warnf("maintenance state invalid, val=%s", val)
+		}
+	}
+	m.state = state
+	return m.state
+}
+
+// set enable or disable, duration > 0 auto disable after duration(s)
+func (m *maintenance) set(enabled bool, duration int) (maintenanceState, error) {
+	state := maintenanceState{Enabled: enabled}
+	if enabled {
+		state.Since = utils.TimestampS()
+		if duration > 0 {
+			state.ExpireAt = state.Since + int64(duration)
+		}
+	}
+	val, err := json.Marshal(state)
+	if err != nil {
+		return state, err
+	}
+	if err := m.store.Put(maintenanceKey, map[string]interface{}{
+		datastore.KConfigVal:        string(val),
+		datastore.KConfigModifyTime: fmt.Sprintf("%d", utils.TimestampS()),
+	}); err != nil {
+		return state, err
+	}
+	m.lock.Lock()
+	m.state = state
+	m.loadTime = time.Now()
+	m.lock.Unlock()
+	return state, nil
+}
+
+func (m *maintenance) status() models.MaintenanceStatus {
+	state := m.get()
+	status := models.MaintenanceStatus{
+		Enabled:  state.active(utils.TimestampS()),
+		Inflight: int(atomic.LoadInt64(&m.inflight)),
+	}
+	// async tasks queued or rendering return before done, still need drain
+	pendingRead := true
+	if m.taskStore != nil {
+		if tasks, err := listActiveTasks(m.taskStore); err != nil {
+			logrus.Warnf("list active tasks err=%s", err.Error())
+			pendingRead = false
+		} else {
+			status.PendingTasks = len(tasks)
+		}
+	}
+	status.Drained = pendingRead && status.Inflight == 0 && status.PendingTasks == 0
+	if status.Enabled {
+		status.Since = &state.Since
+		if state.ExpireAt > 0 {
+			status.ExpireAt = &state.ExpireAt
+		}
+	}
+	return status
+}
+
+func isTaskSubmission(r *http.Request) bool {
+	return r.Method == http.MethodPost &&
+		(submitPaths[r.URL.Path] || strings.HasPrefix(r.URL.Path, sdApiPathPrefix))
+}
+
+// MaintenanceMode reject new task submissions with 503 in maintenance, count inflight submissions
+// other api like task result still work
+func (p *ProxyHandler) MaintenanceMode() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !isTaskSubmission(c.Request) {
+			return
+		}
+		if state := p.maintenance.get(); state.active(utils.TimestampS()) {
+			if state.ExpireAt > 0 {
+				c.Header("Retry-After", fmt.Sprintf("%d", state.ExpireAt-utils.TimestampS()))
+			}
+			handleError(c, http.StatusServiceUnavailable, "server in maintenance, please retry later")
+			c.Abort()
+			return
+		}
+		atomic.AddInt64(&p.maintenance.inflight, 1)
+		defer atomic.AddInt64(&p.maintenance.inflight, -1)
+		c.Next()
+	}
+}
+
+// Drain wait inflight submissions and pending async tasks done before shutdown, false when ctx done first
+func (p *ProxyHandler) Drain(ctx context.Context) bool {
+	interval := drainMinInterval
+	for {
+		status := p.maintenance.status()
+		if status.Drained {
+			return true
+		}
+		logrus.Infof("draining, inflight=%d pendingTasks=%d", status.Inflight, status.PendingTasks)
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(interval):
+		}
+		if interval *= 2; interval > drainMaxInterval {
+			interval = drainMaxInterval
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/datastore"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/models"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaintenanceMode(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	initTestConfig(t)
+	configStore := datastore.NewSQLiteDatastore(datastore.NewSQLiteConfig(datastore.KConfigTableName))
+	defer configStore.Close()
+	p := &ProxyHandler{configStore: configStore, maintenance: newMaintenance(configStore, nil)}
+
+	router := gin.New()
+	router.Use(p.MaintenanceMode())
+	router.GET("/admin/maintenance", p.GetMaintenance)
+	router.POST("/admin/maintenance", p.SetMaintenance)
+	router.POST("/txt2img", func(c *gin.Context) {
+		c.JSON(http.StatusOK, p.maintenance.status())
+	})
+	router.GET("/tasks/:taskId/result", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	router.NoRoute(func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w
+	}
+	status := func(w *httptest.ResponseRecorder) models.MaintenanceStatus {
+		var ret models.MaintenanceStatus
+		assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &ret))
+		return ret
+	}
+
+	// inflight submission counted
+	w := do(http.MethodPost, "/txt2img", "{}")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 1, status(w).Inflight)
+
+	w = do(http.MethodPost, "/admin/maintenance", `{"enabled":true}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, status(w).Enabled)
+	assert.Nil(t, status(w).ExpireAt)
+
+	assert.Equal(t, http.StatusServiceUnavailable, do(http.MethodPost, "/txt2img", "{}").Code)
+	assert.Equal(t, http.StatusServiceUnavailable, do(http.MethodPost, "/sdapi/v1/txt2img", "{}").Code)
+	assert.Equal(t, http.StatusOK, do(http.MethodGet, "/tasks/t1/result", "").Code)
+
+	// state shared by db
+	other := newMaintenance(configStore, nil)
+	assert.True(t, other.status().Enabled)
+
+	// auto expire
+	config.ConfigGlobal.MaintenanceMaxDuration = 60
+	w = do(http.MethodPost, "/admin/maintenance", `{"enabled":true}`)
+	assert.InDelta(t, utils.TimestampS()+60, *status(w).ExpireAt, 1)
+	_, err := p.maintenance.set(true, 1)
+	assert.Nil(t, err)
+	p.maintenance.state.ExpireAt = utils.TimestampS() - 1
+	assert.False(t, p.maintenance.status().Enabled)
+	assert.Equal(t, http.StatusOK, do(http.MethodPost, "/txt2img", "{}").Code)
+
+	w = do(http.MethodPost, "/admin/maintenance", `{"enabled":false}`)
+	assert.False(t, status(w).Enabled)
+	assert.Equal(t, http.StatusBadRequest, do(http.MethodPost, "/admin/maintenance",
+		`{"enabled":true,"durationSeconds":-1}`).Code)
+}
+
+func TestMaintenanceDrain(t *testing.T) {
+	initTestConfig(t)
+	configStore := datastore.NewSQLiteDatastore(datastore.NewSQLiteConfig(datastore.KConfigTableName))
+	defer configStore.Close()
+	taskStore := datastore.NewSQLiteDatastore(datastore.NewSQLiteConfig(datastore.KTaskTableName))
+	defer taskStore.Close()
+	p := &ProxyHandler{maintenance: newMaintenance(configStore, taskStore)}
+	now := fmt.Sprintf("%d", utils.TimestampS())
+	for taskId, status := range map[string]string{
+		"queued":   config.TASK_QUEUE,
+		"running":  config.TASK_INPROGRESS,
+		"finished": config.TASK_FINISH,
+	} {
+		assert.Nil(t, taskStore.Put(taskId, map[string]interface{}{
+			datastore.KTaskIdColumnName: taskId,
+			datastore.KTaskStatus:       status,
+			datastore.KTaskCreateTime:   now,
+		}))
+	}
+
+	// async tasks pending though no inflight submission
+	status := p.maintenance.status()
+	assert.Equal(t, 0, status.Inflight)
+	assert.Equal(t, 2, status.PendingTasks)
+	assert.False(t, status.Drained)
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	assert.False(t, p.Drain(ctx))
+
+	// drained after tasks done
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		for _, taskId := range []string{"queued", "running"} {
+			taskStore.Update(taskId, map[string]interface{}{datastore.KTaskStatus: config.TASK_FINISH})
+		}
+	}()
+	ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	assert.True(t, p.Drain(ctx))
+	assert.Equal(t, 0, p.maintenance.status().PendingTasks)
+}
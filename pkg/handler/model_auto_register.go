@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/datastore"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/module"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	errModelNotFound = errors.New("model not found, please check request")
+	// errModelRegistering auto register not finished in modelAutoRegisterTimeout, download keep running
+	errModelRegistering = errors.New("model registering from oss, please retry later")
+)
+
+// autoRegisterTmpSuffix model file downloading, checkModelExist not see half downloaded model
+const autoRegisterTmpSuffix = ".downloading"
+
+// modelRegister sd model auto register in progress, requests of same model wait the same download
+type modelRegister struct {
+	done chan struct{}
+	err  error
+}
+
+var (
+	modelRegistersLock sync.Mutex
+	modelRegisters     = make(map[string]*modelRegister)
+)
+
+// autoRegisterEnabled only multiFunc, model function created on demand so model file on nas is enough
+func autoRegisterEnabled() bool {
+	return config.ConfigGlobal.ModelAutoRegister && config.ConfigGlobal.GetFlexMode() == config.MultiFunc
+}
+
+// autoRegisterOssPath oss key of sd model by naming convention {modelAutoRegisterOssDir}/{model name}
+func autoRegisterOssPath(sdModel string) string {
+	return fmt.Sprintf("%s/%s", strings.Trim(config.ConfigGlobal.ModelAutoRegisterOssDir, "/"), sdModel)
+}
+
+// ossModelExist model key exist in model bucket which model downloaded from, error treated as not exist
+func ossModelExist(ossPath string) bool {
+	exist, err := module.OssGlobal.ModelExist(ossPath)
+	if err != nil {
+		logrus.Warnf("stat oss model %s err=%s", ossPath, err.Error())
+		return false
+	}
+	return exist
+}
+
+// ensureModelExist checkModelExist, sd model missing on nas but in oss auto registered when modelAutoRegister
+// enabled, wait at most modelAutoRegisterTimeout
+func (p *ProxyHandler) ensureModelExist(sdModel string) (int, error) {
+	if p.checkModelExist(sdModel) {
+		return http.StatusOK, nil
+	}
+	if !autoRegisterEnabled() || sdModel == "" || strings.Contains(sdModel, "..") {
+		return http.StatusNotFound, errModelNotFound
+	}
+	ossPath := autoRegisterOssPath(sdModel)
+	if !ossModelExist(ossPath) {
+		return http.StatusNotFound, errModelNotFound
+	}
+	reg := p.startModelRegister(sdModel, ossPath)
+	timer := time.NewTimer(config.ConfigGlobal.GetModelAutoRegisterTimeout())
+	defer timer.Stop()
+	select {
+	case <-reg.done:
+		if reg.err != nil {
+			return http.StatusInternalServerError, fmt.Errorf("model auto register fail, err=%s", reg.err.Error())
+		}
+		return http.StatusOK, nil
+	case <-timer.C:
+		return http.StatusServiceUnavailable, errModelRegistering
+	}
+}
+
+// startModelRegister register sd model in background, join running register of same model
+func (p *ProxyHandler) startModelRegister(sdModel, ossPath string) *modelRegister {
+	modelRegistersLock.Lock()
+	defer modelRegistersLock.Unlock()
+	if reg, ok := modelRegisters[sdModel]; ok {
+		return reg
+	}
+	reg := &modelRegister{done: make(chan struct{})}
+	modelRegisters[sdModel] = reg
+	go func() {
+		reg.err = p.registerModelFromOss(sdModel, ossPath)
+		modelRegistersLock.Lock()
+		delete(modelRegisters, sdModel)
+		modelRegistersLock.Unlock()
+		close(reg.done)
+	}()
+	return reg
+}
+
+// registerModelFromOss download sd model to nas then register it loaded
+func (p *ProxyHandler) registerModelFromOss(sdModel, ossPath string) error {
+	start := time.Now()
+	localFile := fmt.Sprintf("%s/%s", config.ConfigGlobal.GetModelDir(config.SD_MODEL), sdModel)
+	tmpFile := localFile + autoRegisterTmpSuffix
+	if _, err := downloadModelsFromOss(config.SD_MODEL, ossPath, sdModel+autoRegisterTmpSuffix); err != nil {
+		os.Remove(tmpFile)
+		logrus.Errorf("model %s auto register from oss %s fail, err=%s", sdModel, ossPath, err.Error())
+		return err
+	}
+	if err := os.Rename(tmpFile, localFile); err != nil {
+		os.Remove(tmpFile)
+		return err
+	}
+	logrus.Infof("model %s auto registered from oss %s, cost %s", sdModel, ossPath, time.Since(start))
+	if p.modelStore == nil || config.ConfigGlobal.UseLocalModel() {
+		return nil
+	}
+	now := fmt.Sprintf("%d", utils.TimestampS())
+	return p.modelStore.Put(sdModel, map[string]interface{}{
+		datastore.KModelType:       config.SD_MODEL,
+		datastore.KModelName:       sdModel,
+		datastore.KModelOssPath:    ossPath,
+		datastore.KModelEtag:       "",
+		datastore.KModelLocalPath:  localFile,
+		datastore.KModelStatus:     config.MODEL_LOADED,
+		datastore.KModelCreateTime: now,
+		datastore.KModelModifyTime: now,
+	})
+}
@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/datastore"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnsureModelExist(t *testing.T) {
+	initTestConfig(t)
+	config.ConfigGlobal.SdPath = t.TempDir()
+	config.ConfigGlobal.ModelDirs = map[string]string{config.SD_MODEL: "models"}
+	modelDir := config.ConfigGlobal.GetModelDir(config.SD_MODEL)
+	assert.Nil(t, os.MkdirAll(modelDir, 0755))
+	assert.Nil(t, os.WriteFile(filepath.Join(modelDir, "local.safetensors"), []byte("model"), 0644))
+	oss := mockOss(t, 0)
+	oss.uploaded["sd/models/remote.safetensors"] = []byte("remote")
+	modelStore := datastore.NewSQLiteDatastore(datastore.NewSQLiteConfig(datastore.KModelTableName))
+	defer modelStore.Close()
+	p := &ProxyHandler{modelStore: modelStore}
+
+	// exist on nas
+	code, err := p.ensureModelExist("local.safetensors")
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, code)
+	// auto register disabled
+	code, err = p.ensureModelExist("remote.safetensors")
+	assert.Equal(t, errModelNotFound, err)
+	assert.Equal(t, http.StatusNotFound, code)
+
+	config.ConfigGlobal.ModelAutoRegister = true
+	config.ConfigGlobal.ModelAutoRegisterOssDir = "/sd/models/"
+	config.ConfigGlobal.ModelAutoRegisterTimeout = 5
+	// not in oss
+	code, err = p.ensureModelExist("missing.safetensors")
+	assert.Equal(t, errModelNotFound, err)
+	assert.Equal(t, http.StatusNotFound, code)
+	// registered from oss then proceed
+	code, err = p.ensureModelExist("remote.safetensors")
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, code)
+	body, err := os.ReadFile(filepath.Join(modelDir, "remote.safetensors"))
+	assert.Nil(t, err)
+	assert.Equal(t, "remote", string(body))
+	assert.NoFileExists(t, filepath.Join(modelDir, "remote.safetensors"+autoRegisterTmpSuffix))
+	data, err := modelStore.Get("remote.safetensors", []string{datastore.KModelOssPath, datastore.KModelStatus})
+	assert.Nil(t, err)
+	assert.Equal(t, "sd/models/remote.safetensors", data[datastore.KModelOssPath])
+	assert.Equal(t, config.MODEL_LOADED, data[datastore.KModelStatus])
+}
+
+func TestEnsureModelExistTimeout(t *testing.T) {
+	initTestConfig(t)
+	config.ConfigGlobal.SdPath = t.TempDir()
+	config.ConfigGlobal.ModelDirs = map[string]string{config.SD_MODEL: "models"}
+	config.ConfigGlobal.ModelAutoRegister = true
+	config.ConfigGlobal.ModelAutoRegisterOssDir = "sd"
+	config.ConfigGlobal.ModelAutoRegisterTimeout = 1
+	assert.Nil(t, os.MkdirAll(config.ConfigGlobal.GetModelDir(config.SD_MODEL), 0755))
+	oss := mockOss(t, 1500*time.Millisecond)
+	oss.uploaded["sd/big.safetensors"] = []byte("big")
+	p := &ProxyHandler{}
+
+	// huge download not block request, download keep running
+	code, err := p.ensureModelExist("big.safetensors")
+	assert.Equal(t, errModelRegistering, err)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.False(t, p.checkModelExist("big.safetensors"))
+	assert.Eventually(t, func() bool {
+		return p.checkModelExist("big.safetensors")
+	}, 3*time.Second, 50*time.Millisecond)
+	// background register done before test config and oss restored
+	assert.Eventually(t, func() bool {
+		modelRegistersLock.Lock()
+		defer modelRegistersLock.Unlock()
+		return len(modelRegisters) == 0
+	}, 3*time.Second, 10*time.Millisecond)
+}
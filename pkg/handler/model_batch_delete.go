@@ -0,0 +1,228 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/datastore"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/models"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/module"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	maxBatchDeleteModels        = 100
+	batchModelDeleteConcurrency = 4
+)
+
+// outcome of model in batch delete
+const (
+	modelDeleted      = "deleted"
+	modelWouldDelete  = "would_delete"
+	modelSkipped      = "skipped"
+	modelNotFound     = "not_found"
+	modelDeleteFailed = "failed"
+)
+
+var batchDeleteModelColumns = []string{datastore.KModelName, datastore.KModelType, datastore.KModelLocalPath,
+	datastore.KModelStatus}
+
+// BatchDeleteModels delete models by names or type, skip models used by unfinished tasks
+// (POST /models/batch-delete)
+func (p *ProxyHandler) BatchDeleteModels(c *gin.Context) {
+	if config.ConfigGlobal.UseLocalModel() {
+		c.String(http.StatusNotFound, "useLocalModel=yes not support")
+		return
+	}
+	request := new(models.BatchDeleteModelsJSONRequestBody)
+	if err := getBindResult(c, request); err != nil {
+		handleError(c, http.StatusBadRequest, config.BADREQUEST)
+		return
+	}
+	names := make([]string, 0)
+	if request.Names != nil {
+		names = *request.Names
+	}
+	modelType := ""
+	if request.Type != nil {
+		modelType = *request.Type
+	}
+	if len(names) == 0 && modelType == "" {
+		handleError(c, http.StatusBadRequest, "names or type required")
+		return
+	}
+	if len(names) > maxBatchDeleteModels {
+		handleError(c, http.StatusBadRequest, fmt.Sprintf("names count should not exceed %d", maxBatchDeleteModels))
+		return
+	}
+	rows, err := p.modelStore.ListAll(batchDeleteModelColumns)
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, "read model from db error")
+		return
+	}
+	activeTasks, err := p.activeModelTasks()
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, "read task from db error")
+		return
+	}
+	results := planModelDeletes(rows, names, modelType, activeTasks)
+	dryRun := request.DryRun != nil && *request.DryRun
+	if !dryRun {
+		p.deleteModels(results, rows)
+	}
+	c.JSON(http.StatusOK, models.BatchDeleteModelsResult{DryRun: dryRun, Results: results})
+}
+
+// planModelDeletes outcome of each model before delete, names in request order or models of type sorted by name
+func planModelDeletes(rows map[string]map[string]interface{}, names []string, modelType string,
+	activeTasks map[string]int) []models.ModelDeleteResult {
+	if len(names) == 0 {
+		for name, row := range rows {
+			if row[datastore.KModelType] == modelType && row[datastore.KModelStatus] != config.MODEL_DELETE {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+	}
+	results := make([]models.ModelDeleteResult, 0, len(names))
+	seen := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		seen[name] = struct{}{}
+		result := models.ModelDeleteResult{Name: name, Status: modelWouldDelete}
+		row, ok := rows[name]
+		switch {
+		case !ok || row[datastore.KModelStatus] == config.MODEL_DELETE:
+			result.Status = modelNotFound
+		case modelType != "" && row[datastore.KModelType] != modelType:
+			result.Status = modelSkipped
+			result.Message = utils.String(fmt.Sprintf("model type %v not %s", row[datastore.KModelType], modelType))
+		case activeTasks[modelRefName(name)] > 0:
+			result.Status = modelSkipped
+			result.Message = utils.String(fmt.Sprintf("%d unfinished tasks use model",
+				activeTasks[modelRefName(name)]))
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// deleteModels delete planned models concurrently, update outcome in place
+func (p *ProxyHandler) deleteModels(results []models.ModelDeleteResult, rows map[string]map[string]interface{}) {
+	sem := make(chan struct{}, batchModelDeleteConcurrency)
+	var wg sync.WaitGroup
+	for i := range results {
+		if results[i].Status != modelWouldDelete {
+			continue
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(result *models.ModelDeleteResult) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			localFile, _ := rows[result.Name][datastore.KModelLocalPath].(string)
+			if err := p.deleteModel(result.Name, localFile); err != nil {
+				result.Status = modelDeleteFailed
+				result.Message = utils.String(err.Error())
+				return
+			}
+			result.Status = modelDeleted
+		}(&results[i])
+	}
+	wg.Wait()
+}
+
+// loraPattern <lora:name:weight> or <lyco:name:weight> of prompt
+var loraPattern = regexp.MustCompile(`<(?:lora|lyco):([^:>]+)`)
+
+// modelRefName model name comparable with request reference: controlnet " [hash]" and model file ext trimmed,
+// lora referenced without ext
+func modelRefName(name string) string {
+	name = strings.TrimSpace(name)
+	if idx := strings.LastIndex(name, " ["); idx > 0 {
+		name = name[:idx]
+	}
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".safetensors", ".ckpt", ".pt", ".pth", ".bin":
+		name = strings.TrimSuffix(name, filepath.Ext(name))
+	}
+	return name
+}
+
+// taskModelRefs models referenced by task request: sd model, lora of prompts, sd_vae and controlnet unit models
+func taskModelRefs(row map[string]interface{}) map[string]struct{} {
+	refs := make(map[string]struct{})
+	add := func(val interface{}) {
+		if name, ok := val.(string); ok && name != "" && name != "None" {
+			refs[modelRefName(name)] = struct{}{}
+		}
+	}
+	add(row[datastore.KTaskModel])
+	requestVal, _ := row[datastore.KTaskRequest].(string)
+	stored := new(models.TaskReproduceResponse)
+	if requestVal == "" || json.Unmarshal([]byte(requestVal), stored) != nil {
+		return refs
+	}
+	request := stored.Request
+	for _, key := range []string{"prompt", "negative_prompt"} {
+		prompt, _ := request[key].(string)
+		for _, match := range loraPattern.FindAllStringSubmatch(prompt, -1) {
+			add(match[1])
+		}
+	}
+	add(request["sd_vae"])
+	if settings, ok := request["override_settings"].(map[string]interface{}); ok {
+		add(settings["sd_vae"])
+	}
+	scripts, _ := request["alwayson_scripts"].(map[string]interface{})
+	for name, script := range scripts {
+		scriptMap, ok := script.(map[string]interface{})
+		if strings.ToLower(name) != controlNetScript || !ok {
+			continue
+		}
+		units, _ := scriptMap["args"].([]interface{})
+		for _, unit := range units {
+			if unitMap, ok := unit.(map[string]interface{}); ok {
+				add(unitMap["model"])
+			}
+		}
+	}
+	return refs
+}
+
+// activeModelTasks count of unfinished tasks by referenced model, keyed by modelRefName
+func (p *ProxyHandler) activeModelTasks() (map[string]int, error) {
+	active := make(map[string]int)
+	columns := []string{datastore.KTaskIdColumnName, datastore.KTaskModel, datastore.KTaskStatus,
+		datastore.KTaskRequest}
+	cursor := ""
+	for {
+		rows, nextKey, err := p.taskStore.ListRange(cursor, taskScanBatch, columns)
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			if status, _ := row[datastore.KTaskStatus].(string); module.IsTaskTerminal(status) {
+				continue
+			}
+			for name := range taskModelRefs(row) {
+				active[name]++
+			}
+		}
+		if nextKey == "" {
+			return active, nil
+		}
+		cursor = nextKey
+	}
+}
@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/datastore"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/models"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatchDeleteModels(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	initTestConfig(t)
+	dir := t.TempDir()
+	modelStore := datastore.NewSQLiteDatastore(datastore.NewSQLiteConfig(datastore.KModelTableName))
+	defer modelStore.Close()
+	taskStore := datastore.NewSQLiteDatastore(datastore.NewSQLiteConfig(datastore.KTaskTableName))
+	defer taskStore.Close()
+	putModel := func(name, modelType, status string) string {
+		localFile := filepath.Join(dir, name)
+		assert.Nil(t, os.WriteFile(localFile, []byte("model"), 0644))
+		assert.Nil(t, modelStore.Put(name, map[string]interface{}{
+			datastore.KModelName:      name,
+			datastore.KModelType:      modelType,
+			datastore.KModelLocalPath: localFile,
+			datastore.KModelStatus:    status,
+		}))
+		return localFile
+	}
+	sdFile := putModel("sd.safetensors", config.SD_MODEL, config.MODEL_LOADED)
+	busyFile := putModel("busy.safetensors", config.SD_MODEL, config.MODEL_LOADED)
+	loraA := putModel("a.safetensors", config.LORA_MODEL, config.MODEL_LOADED)
+	loraB := putModel("b.safetensors", config.LORA_MODEL, config.MODEL_LOADED)
+	putModel("gone.safetensors", config.LORA_MODEL, config.MODEL_DELETE)
+	assert.Nil(t, taskStore.Put("task", map[string]interface{}{
+		datastore.KTaskIdColumnName: "task",
+		datastore.KTaskModel:        "busy.safetensors",
+		datastore.KTaskStatus:       config.TASK_QUEUE,
+	}))
+	assert.Nil(t, taskStore.Put("done", map[string]interface{}{
+		datastore.KTaskIdColumnName: "done",
+		datastore.KTaskModel:        "sd.safetensors",
+		datastore.KTaskStatus:       config.TASK_FINISH,
+	}))
+	p := &ProxyHandler{modelStore: modelStore, taskStore: taskStore}
+	submit := func(request models.BatchDeleteModelsRequest) (*httptest.ResponseRecorder, models.BatchDeleteModelsResult) {
+		data, _ := json.Marshal(request)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/models/batch-delete", bytes.NewReader(data))
+		p.BatchDeleteModels(c)
+		var result models.BatchDeleteModelsResult
+		json.Unmarshal(w.Body.Bytes(), &result)
+		return w, result
+	}
+	status := func(result models.BatchDeleteModelsResult) map[string]string {
+		ret := make(map[string]string)
+		for _, one := range result.Results {
+			ret[one.Name] = one.Status
+		}
+		return ret
+	}
+
+	w, _ := submit(models.BatchDeleteModelsRequest{})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// dry run delete nothing
+	names := []string{"sd.safetensors", "busy.safetensors", "a.safetensors", "gone.safetensors", "sd.safetensors"}
+	w, result := submit(models.BatchDeleteModelsRequest{Names: &names, DryRun: utils.Bool(true)})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, result.DryRun)
+	assert.Len(t, result.Results, 4)
+	assert.Equal(t, map[string]string{"sd.safetensors": modelWouldDelete, "busy.safetensors": modelSkipped,
+		"a.safetensors": modelWouldDelete, "gone.safetensors": modelNotFound}, status(result))
+	assert.Equal(t, "1 unfinished tasks use model", *result.Results[1].Message)
+	assert.True(t, utils.FileExists(sdFile))
+
+	// type filter skip models of other type
+	w, result = submit(models.BatchDeleteModelsRequest{Names: &names, Type: utils.String(config.LORA_MODEL)})
+	assert.Equal(t, map[string]string{"sd.safetensors": modelSkipped, "busy.safetensors": modelSkipped,
+		"a.safetensors": modelDeleted, "gone.safetensors": modelNotFound}, status(result))
+	assert.False(t, utils.FileExists(loraA))
+	assert.True(t, utils.FileExists(sdFile))
+	data, err := modelStore.Get("a.safetensors", []string{datastore.KModelStatus})
+	assert.Nil(t, err)
+	assert.Equal(t, config.MODEL_DELETE, data[datastore.KModelStatus])
+
+	// all models of type, lora used by prompt of unfinished task
+	assert.Nil(t, taskStore.Put("lora", map[string]interface{}{
+		datastore.KTaskIdColumnName: "lora",
+		datastore.KTaskModel:        "sd.safetensors",
+		datastore.KTaskStatus:       config.TASK_INPROGRESS,
+		datastore.KTaskRequest: taskRequestVal(txt2ImgPath, map[string]interface{}{
+			"prompt": "cat <lora:b:0.6>"}),
+	}))
+	_, result = submit(models.BatchDeleteModelsRequest{Type: utils.String(config.LORA_MODEL)})
+	assert.Equal(t, map[string]string{"b.safetensors": modelSkipped}, status(result))
+	assert.Nil(t, taskStore.Update("lora", map[string]interface{}{datastore.KTaskStatus: config.TASK_FINISH}))
+	_, result = submit(models.BatchDeleteModelsRequest{Type: utils.String(config.LORA_MODEL)})
+	assert.Equal(t, map[string]string{"b.safetensors": modelDeleted}, status(result))
+	assert.False(t, utils.FileExists(loraB))
+
+	// file missing
+	assert.Nil(t, os.Remove(sdFile))
+	names = []string{"sd.safetensors"}
+	_, result = submit(models.BatchDeleteModelsRequest{Names: &names})
+	assert.Equal(t, modelDeleteFailed, result.Results[0].Status)
+	assert.True(t, utils.FileExists(busyFile))
+}
+
+func TestTaskModelRefs(t *testing.T) {
+	row := map[string]interface{}{
+		datastore.KTaskModel: "sd.safetensors",
+		datastore.KTaskRequest: taskRequestVal(img2ImgPath, map[string]interface{}{
+			"prompt":            "cat <lora:style:0.8>, <lyco:detail.v2:1>",
+			"negative_prompt":   "<lora:bad:1>",
+			"sd_vae":            "vae.safetensors",
+			"override_settings": map[string]interface{}{"sd_vae": "None"},
+			"alwayson_scripts": map[string]interface{}{
+				"ControlNet": map[string]interface{}{"args": []interface{}{
+					map[string]interface{}{"model": "control_canny [d14c016b]"},
+				}},
+			},
+		}),
+	}
+	refs := taskModelRefs(row)
+	assert.Equal(t, map[string]struct{}{"sd": {}, "style": {}, "detail.v2": {}, "bad": {}, "vae": {},
+		"control_canny": {}}, refs)
+	// request not recorded, sd model only
+	assert.Equal(t, map[string]struct{}{"sd": {}}, taskModelRefs(map[string]interface{}{
+		datastore.KTaskModel: "sd.safetensors"}))
+	assert.Equal(t, "control_canny", modelRefName("control_canny.pth"))
+}
@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"fmt"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/datastore"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/models"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"path/filepath"
+	"sort"
+)
+
+// outcome of model in reconcile
+const (
+	modelRegistered    = "registered"
+	modelWouldRegister = "would_register"
+	modelUnloaded      = "unloaded"
+	modelWouldUnload   = "would_unload"
+	modelReconcileFail = "failed"
+)
+
+var reconcileModelColumns = []string{datastore.KModelName, datastore.KModelType, datastore.KModelLocalPath,
+	datastore.KModelStatus}
+
+// ReconcileModels fix drift between model store and model files of sdPath model dirs,
+// disk only models registered, store only models whose file missing marked unloaded
+// (POST /admin/models/reconcile)
+func (p *ProxyHandler) ReconcileModels(c *gin.Context, params models.ReconcileModelsParams) {
+	if config.ConfigGlobal.UseLocalModel() {
+		c.String(http.StatusNotFound, "useLocalModel=yes not support")
+		return
+	}
+	// nas not mounted, every model looks missing
+	if !utils.FileExists(config.ConfigGlobal.SdPath) {
+		handleError(c, http.StatusInternalServerError, "sd path not exist, please check nas mounted")
+		return
+	}
+	rows, err := p.modelStore.ListAll(reconcileModelColumns)
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, "read model from db error")
+		return
+	}
+	results := planModelReconcile(rows, scanModelFiles())
+	dryRun := params.DryRun != nil && *params.DryRun
+	if !dryRun {
+		p.reconcileModels(results)
+	}
+	c.JSON(http.StatusOK, models.ReconcileModelsResult{DryRun: dryRun, Results: results})
+}
+
+// scanModelFiles model type -> model files in its dir, types whose dir not exist not scanned
+func scanModelFiles() map[string]map[string]*models.ModelAttributes {
+	scanned := make(map[string]map[string]*models.ModelAttributes)
+	for modelType := range config.ConfigGlobal.ModelDirs {
+		dir := config.ConfigGlobal.GetModelDir(modelType)
+		if !utils.FileExists(dir) {
+			continue
+		}
+		files := make(map[string]*models.ModelAttributes)
+		for _, attr := range listModelFile(dir, modelType) {
+			files[attr.Name] = attr
+		}
+		scanned[modelType] = files
+	}
+	return scanned
+}
+
+// planModelReconcile discrepancies between store rows and scanned files sorted by type and name,
+// deleted or unloaded rows whose file exist registered again
+func planModelReconcile(rows map[string]map[string]interface{},
+	scanned map[string]map[string]*models.ModelAttributes) []models.ModelReconcileResult {
+	results := make([]models.ModelReconcileResult, 0)
+	// model store keyed by name, same name in dirs of several types register once
+	planned := make(map[string]struct{})
+	modelTypes := make([]string, 0, len(scanned))
+	for modelType := range scanned {
+		modelTypes = append(modelTypes, modelType)
+	}
+	sort.Strings(modelTypes)
+	for _, modelType := range modelTypes {
+		for name := range scanned[modelType] {
+			row, ok := rows[name]
+			if ok && row[datastore.KModelStatus] != config.MODEL_DELETE &&
+				row[datastore.KModelStatus] != config.MODEL_UNLOADED {
+				continue
+			}
+			if _, ok := planned[name]; ok {
+				continue
+			}
+			planned[name] = struct{}{}
+			results = append(results, models.ModelReconcileResult{Name: name, Type: modelType,
+				Status: modelWouldRegister})
+		}
+	}
+	for name, row := range rows {
+		status, _ := row[datastore.KModelStatus].(string)
+		modelType, _ := row[datastore.KModelType].(string)
+		files, ok := scanned[modelType]
+		if !ok || status == config.MODEL_DELETE || status == config.MODEL_UNLOADED {
+			continue
+		}
+		if _, ok := files[name]; ok {
+			continue
+		}
+		if localPath, _ := row[datastore.KModelLocalPath].(string); localPath != "" && utils.FileExists(localPath) {
+			continue
+		}
+		results = append(results, models.ModelReconcileResult{Name: name, Type: modelType,
+			Status: modelWouldUnload})
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Type != results[j].Type {
+			return results[i].Type < results[j].Type
+		}
+		return results[i].Name < results[j].Name
+	})
+	return results
+}
+
+// reconcileModels apply planned outcome to model store, update outcome in place
+func (p *ProxyHandler) reconcileModels(results []models.ModelReconcileResult) {
+	for i := range results {
+		result := &results[i]
+		now := fmt.Sprintf("%d", utils.TimestampS())
+		var err error
+		switch result.Status {
+		case modelWouldRegister:
+			err = p.modelStore.Put(result.Name, map[string]interface{}{
+				datastore.KModelType:       result.Type,
+				datastore.KModelName:       result.Name,
+				datastore.KModelOssPath:    "",
+				datastore.KModelEtag:       "",
+				datastore.KModelLocalPath:  filepath.Join(config.ConfigGlobal.GetModelDir(result.Type), result.Name),
+				datastore.KModelStatus:     config.MODEL_LOADED,
+				datastore.KModelCreateTime: now,
+				datastore.KModelModifyTime: now,
+			})
+			result.Status = modelRegistered
+		case modelWouldUnload:
+			err = p.modelStore.Update(result.Name, map[string]interface{}{
+				datastore.KModelStatus:     config.MODEL_UNLOADED,
+				datastore.KModelModifyTime: now,
+			})
+			result.Status = modelUnloaded
+		}
+		if err != nil {
+			result.Status = modelReconcileFail
+			result.Message = utils.String(err.Error())
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/datastore"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/models"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReconcileModels(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	initTestConfig(t)
+	config.ConfigGlobal.SdPath = t.TempDir()
+	config.ConfigGlobal.ModelDirs = map[string]string{
+		config.SD_MODEL:   "models/Stable-diffusion",
+		config.LORA_MODEL: "models/Lora",
+		config.SD_VAE:     "models/VAE",
+	}
+	writeModel := func(modelType, name string) {
+		dir := config.ConfigGlobal.GetModelDir(modelType)
+		assert.Nil(t, os.MkdirAll(dir, 0755))
+		assert.Nil(t, os.WriteFile(filepath.Join(dir, name), []byte("model"), 0644))
+	}
+	writeModel(config.SD_MODEL, "registered.safetensors")
+	writeModel(config.SD_MODEL, "manual.safetensors")
+	writeModel(config.LORA_MODEL, "back.safetensors")
+	modelStore := datastore.NewSQLiteDatastore(datastore.NewSQLiteConfig(datastore.KModelTableName))
+	defer modelStore.Close()
+	for name, row := range map[string][]string{
+		"registered.safetensors": {config.SD_MODEL, config.MODEL_LOADED},
+		"removed.safetensors":    {config.SD_MODEL, config.MODEL_LOADED},
+		"back.safetensors":       {config.LORA_MODEL, config.MODEL_DELETE},
+		"deleted.safetensors":    {config.LORA_MODEL, config.MODEL_DELETE},
+		// vae dir not exist, not touched
+		"vae.safetensors": {config.SD_VAE, config.MODEL_LOADED},
+	} {
+		assert.Nil(t, modelStore.Put(name, map[string]interface{}{
+			datastore.KModelName:   name,
+			datastore.KModelType:   row[0],
+			datastore.KModelStatus: row[1],
+		}))
+	}
+	p := &ProxyHandler{modelStore: modelStore}
+	reconcile := func(dryRun bool) models.ReconcileModelsResult {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/admin/models/reconcile", nil)
+		p.ReconcileModels(c, models.ReconcileModelsParams{DryRun: utils.Bool(dryRun)})
+		assert.Equal(t, http.StatusOK, w.Code)
+		var result models.ReconcileModelsResult
+		assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &result))
+		return result
+	}
+	status := func(name string) interface{} {
+		data, err := modelStore.Get(name, []string{datastore.KModelStatus})
+		assert.Nil(t, err)
+		return data[datastore.KModelStatus]
+	}
+
+	// dry run only report
+	result := reconcile(true)
+	assert.True(t, result.DryRun)
+	assert.Equal(t, []models.ModelReconcileResult{
+		{Name: "back.safetensors", Type: config.LORA_MODEL, Status: modelWouldRegister},
+		{Name: "manual.safetensors", Type: config.SD_MODEL, Status: modelWouldRegister},
+		{Name: "removed.safetensors", Type: config.SD_MODEL, Status: modelWouldUnload},
+	}, result.Results)
+	assert.Nil(t, status("manual.safetensors"))
+	assert.Equal(t, config.MODEL_LOADED, status("removed.safetensors"))
+
+	result = reconcile(false)
+	assert.False(t, result.DryRun)
+	assert.Equal(t, []models.ModelReconcileResult{
+		{Name: "back.safetensors", Type: config.LORA_MODEL, Status: modelRegistered},
+		{Name: "manual.safetensors", Type: config.SD_MODEL, Status: modelRegistered},
+		{Name: "removed.safetensors", Type: config.SD_MODEL, Status: modelUnloaded},
+	}, result.Results)
+	assert.Equal(t, config.MODEL_LOADED, status("manual.safetensors"))
+	assert.Equal(t, config.MODEL_LOADED, status("back.safetensors"))
+	assert.Equal(t, config.MODEL_UNLOADED, status("removed.safetensors"))
+	assert.Equal(t, config.MODEL_DELETE, status("deleted.safetensors"))
+	assert.Equal(t, config.MODEL_LOADED, status("vae.safetensors"))
+	// nothing left to fix
+	assert.Empty(t, reconcile(false).Results)
+
+	// nas not mounted
+	config.ConfigGlobal.SdPath = filepath.Join(config.ConfigGlobal.SdPath, "missing")
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/admin/models/reconcile", nil)
+	p.ReconcileModels(c, models.ReconcileModelsParams{})
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/datastore"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// injectNegativeEmbeddings append config default negative embeddings of user to negative prompt,
+// embedding already in prompt or not registered skipped, disable opt out
+func (p *ProxyHandler) injectNegativeEmbeddings(username string, negativePrompt **string, disable *bool) {
+	if disable != nil && *disable {
+		return
+	}
+	embeddings := config.ConfigGlobal.GetNegativeEmbeddings(username)
+	if len(embeddings) == 0 {
+		return
+	}
+	prompt := ""
+	if *negativePrompt != nil {
+		prompt = **negativePrompt
+	}
+	added := false
+	for _, embedding := range embeddings {
+		// webui trigger embedding by file name without ext
+		token := strings.TrimSuffix(embedding, filepath.Ext(embedding))
+		if token == "" || containsPromptToken(prompt, token) {
+			continue
+		}
+		if !p.embeddingRegistered(embedding) {
+			logrus.Warnf("default negative embedding %s not registered, skip", embedding)
+			continue
+		}
+		if strings.TrimSpace(prompt) == "" {
+			prompt = token
+		} else {
+			prompt = strings.TrimRight(prompt, ", ") + ", " + token
+		}
+		added = true
+	}
+	if added {
+		*negativePrompt = utils.String(prompt)
+	}
+}
+
+// containsPromptToken token as whole word in prompt, "(EasyNegative:1.2)" contain EasyNegative
+func containsPromptToken(prompt, token string) bool {
+	return regexp.MustCompile(`(^|[^\w])` + regexp.QuoteMeta(token) + `([^\w]|$)`).MatchString(prompt)
+}
+
+// embeddingRegistered embedding model registered and not deleted, local model check embeddings dir
+func (p *ProxyHandler) embeddingRegistered(name string) bool {
+	if config.ConfigGlobal.UseLocalModel() {
+		return utils.FileExists(filepath.Join(config.ConfigGlobal.GetModelDir(config.EMBEDDING_MODEL), name))
+	}
+	data, err := p.modelStore.Get(name, []string{datastore.KModelType, datastore.KModelStatus})
+	if err != nil || len(data) == 0 {
+		return false
+	}
+	return data[datastore.KModelType] == config.EMBEDDING_MODEL && data[datastore.KModelStatus] != config.MODEL_DELETE
+}
@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/datastore"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInjectNegativeEmbeddings(t *testing.T) {
+	initTestConfig(t)
+	modelStore := datastore.NewSQLiteDatastore(datastore.NewSQLiteConfig(datastore.KModelTableName))
+	defer modelStore.Close()
+	p := &ProxyHandler{modelStore: modelStore}
+	for name, status := range map[string]string{
+		"EasyNegative.safetensors": config.MODEL_LOADED,
+		"bad-hands-5.pt":           config.MODEL_LOADED,
+		"deleted.pt":               config.MODEL_DELETE,
+	} {
+		assert.Nil(t, modelStore.Put(name, map[string]interface{}{
+			datastore.KModelName:   name,
+			datastore.KModelType:   config.EMBEDDING_MODEL,
+			datastore.KModelStatus: status,
+		}))
+	}
+	assert.Nil(t, modelStore.Put("lora.safetensors", map[string]interface{}{
+		datastore.KModelName:   "lora.safetensors",
+		datastore.KModelType:   config.LORA_MODEL,
+		datastore.KModelStatus: config.MODEL_LOADED,
+	}))
+	config.ConfigGlobal.DefaultNegativeEmbeddings = map[string][]string{
+		config.AllUsers: {"EasyNegative.safetensors", "bad-hands-5.pt", "deleted.pt", "lora.safetensors",
+			"missing.pt"},
+		"admin": {},
+	}
+	inject := func(user string, prompt *string, disable *bool) *string {
+		p.injectNegativeEmbeddings(user, &prompt, disable)
+		return prompt
+	}
+
+	// only registered embeddings appended
+	assert.Equal(t, "EasyNegative, bad-hands-5", *inject("user", nil, nil))
+	assert.Equal(t, "blurry, EasyNegative, bad-hands-5", *inject("user", utils.String("blurry, "), nil))
+	// dedupe with user included
+	assert.Equal(t, "(EasyNegative:1.2), lowres, bad-hands-5",
+		*inject("user", utils.String("(EasyNegative:1.2), lowres"), nil))
+	assert.Equal(t, "bad-hands-5, EasyNegative", *inject("user", utils.String("bad-hands-5, EasyNegative"), nil))
+	// EasyNegativeV2 not EasyNegative
+	assert.Equal(t, "EasyNegativeV2, EasyNegative, bad-hands-5",
+		*inject("user", utils.String("EasyNegativeV2"), nil))
+
+	// opt out by request or user setting
+	assert.Equal(t, "blurry", *inject("user", utils.String("blurry"), utils.Bool(true)))
+	assert.Nil(t, inject("user", nil, utils.Bool(true)))
+	assert.Nil(t, inject("admin", nil, nil))
+	assert.Equal(t, "EasyNegative, bad-hands-5", *inject("user", nil, utils.Bool(false)))
+}
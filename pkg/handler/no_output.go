@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+)
+
+// webui info longer than it cut in no output message, full info saved in task
+const maxNoOutputInfoLen = 256
+
+var errNoOutput = errors.New("webui returned no images")
+
+// noOutputError webui predict succeeded without images (nsfw filter, script error), info tell why
+func noOutputError(info string) error {
+	if info == "" {
+		return errNoOutput
+	}
+	if len(info) > maxNoOutputInfoLen {
+		info = info[:maxNoOutputInfoLen] + "..."
+	}
+	return fmt.Errorf("%w, info=%s", errNoOutput, info)
+}
+
+// predictErrorStatus task status of predict error response
+func predictErrorStatus(err error) string {
+	if errors.Is(err, errNoOutput) {
+		return config.TASK_NO_OUTPUT
+	}
+	if errors.Is(err, errPartialUpload) {
+		return config.TASK_PARTIAL_UPLOAD
+	}
+	return config.TASK_FAILED
+}
@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/datastore"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/models"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNoOutputError(t *testing.T) {
+	assert.Equal(t, errNoOutput, noOutputError(""))
+	err := noOutputError(`{"nsfw":true}`)
+	assert.ErrorIs(t, err, errNoOutput)
+	assert.Equal(t, `webui returned no images, info={"nsfw":true}`, err.Error())
+	err = noOutputError(strings.Repeat("a", maxNoOutputInfoLen+1))
+	assert.True(t, strings.HasSuffix(err.Error(), strings.Repeat("a", maxNoOutputInfoLen)+"..."))
+
+	assert.Equal(t, config.TASK_NO_OUTPUT, predictErrorStatus(err))
+	assert.Equal(t, http.StatusOK, predictErrorCode(err))
+	assert.Equal(t, config.TASK_FAILED, predictErrorStatus(errModelRemoved))
+}
+
+func TestTxt2ImgNoOutput(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	initTestConfig(t)
+	config.ConfigGlobal.ServerName = config.PROXY
+	config.ConfigGlobal.ImageNameTemplate = config.DefaultImageNameTemplate
+	mockOss(t, 0)
+	sd := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"images":[],"info":"{\"nsfw\":true}"}`))
+	}))
+	defer sd.Close()
+	config.ConfigGlobal.SdUrlPrefix = sd.URL
+	taskStore := datastore.NewSQLiteDatastore(datastore.NewSQLiteConfig(datastore.KTaskTableName))
+	defer taskStore.Close()
+	configStore := datastore.NewSQLiteDatastore(datastore.NewSQLiteConfig(datastore.KConfigTableName))
+	defer configStore.Close()
+	p := &ProxyHandler{taskStore: taskStore, configStore: configStore, httpClient: &http.Client{}}
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/txt2img",
+		strings.NewReader(`{"stable_diffusion_model":"sd.safetensors","prompt":"cat"}`))
+	c.Request.Header.Set(userKey, "user")
+	p.Txt2Img(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	resp := new(models.SubmitTaskResponse)
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), resp))
+	assert.Equal(t, config.TASK_NO_OUTPUT, resp.Status)
+	assert.Contains(t, *resp.Message, `info={"nsfw":true}`)
+	assert.Nil(t, resp.OssUrl)
+
+	// result carry webui info
+	result, err := p.getTaskResult(resp.TaskId)
+	assert.Nil(t, err)
+	assert.Equal(t, config.TASK_NO_OUTPUT, result.Status)
+	assert.Equal(t, errNoOutput.Error(), *result.Message)
+	assert.Equal(t, true, (*result.Info)["nsfw"])
+	assert.Empty(t, *result.Images)
+}
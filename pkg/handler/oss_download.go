@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"errors"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/models"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/module"
+	"net/http"
+	"time"
+)
+
+// errOssDownloadBusy no oss download slot released in timeout
+var errOssDownloadBusy = errors.New("too many oss image downloads, wait download slot timeout")
+
+// ossDownloadLimit shared by all requests, burst of image heavy requests not saturate oss bandwidth
+// and memory, set by NewProxyHandler, nil means no limit
+var ossDownloadLimit *downloadLimiter
+
+// downloadLimiter bound concurrent oss downloads, nil means no limit
+type downloadLimiter struct {
+	slots   chan struct{}
+	timeout time.Duration
+}
+
+func newDownloadLimiter(concurrency int, timeout time.Duration) *downloadLimiter {
+	if concurrency <= 0 {
+		return nil
+	}
+	return &downloadLimiter{slots: make(chan struct{}, concurrency), timeout: timeout}
+}
+
+// acquire wait slot at most timeout, call release after download done
+func (l *downloadLimiter) acquire() (release func(), err error) {
+	if l == nil {
+		return func() {}, nil
+	}
+	timer := time.NewTimer(l.timeout)
+	defer timer.Stop()
+	select {
+	case l.slots <- struct{}{}:
+		return func() { <-l.slots }, nil
+	case <-timer.C:
+		return nil, errOssDownloadBusy
+	}
+}
+
+// status slots in use and capacity
+func (l *downloadLimiter) status() models.OssDownloads {
+	return models.OssDownloads{InUse: len(l.slots), Capacity: cap(l.slots)}
+}
+
+// downloadFileToBase64 oss image to base64 within ossDownloadLimit
+func downloadFileToBase64(ossPath string) (*string, error) {
+	release, err := ossDownloadLimit.acquire()
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return module.OssGlobal.DownloadFileToBase64(ossPath)
+}
+
+// preprocessErrorCode 503 when oss download busy, client can retry later, otherwise bad request
+func preprocessErrorCode(err error) int {
+	if errors.Is(err, errOssDownloadBusy) {
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusBadRequest
+}
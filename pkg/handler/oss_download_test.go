@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDownloadLimiter(t *testing.T) {
+	// nil no limit
+	var limiter *downloadLimiter
+	release, err := limiter.acquire()
+	assert.Nil(t, err)
+	release()
+	assert.Nil(t, newDownloadLimiter(0, time.Second))
+
+	limiter = newDownloadLimiter(2, 10*time.Millisecond)
+	release1, err := limiter.acquire()
+	assert.Nil(t, err)
+	release2, err := limiter.acquire()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, limiter.status().InUse)
+	assert.Equal(t, 2, limiter.status().Capacity)
+	// full, wait timeout
+	_, err = limiter.acquire()
+	assert.ErrorIs(t, err, errOssDownloadBusy)
+	release1()
+	release2()
+	assert.Equal(t, 0, limiter.status().InUse)
+}
+
+func TestDownloadFileToBase64(t *testing.T) {
+	initTestConfig(t)
+	oss := mockOss(t, 0)
+	oss.uploaded["images/a.png"] = []byte("image")
+	old := ossDownloadLimit
+	defer func() {
+		ossDownloadLimit = old
+	}()
+	ossDownloadLimit = newDownloadLimiter(1, 10*time.Millisecond)
+
+	ret, err := downloadFileToBase64("images/a.png")
+	assert.Nil(t, err)
+	assert.Equal(t, "aW1hZ2U=", *ret)
+	assert.Equal(t, 0, ossDownloadLimit.status().InUse)
+
+	// slot held by other request
+	release, _ := ossDownloadLimit.acquire()
+	_, err = downloadFileToBase64("images/a.png")
+	assert.ErrorIs(t, err, errOssDownloadBusy)
+	release()
+	assert.Equal(t, http.StatusServiceUnavailable, preprocessErrorCode(err))
+	assert.Equal(t, http.StatusBadRequest, preprocessErrorCode(errors.New("object not exist")))
+}
@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"github.com/sirupsen/logrus"
+	"sort"
+	"strings"
+)
+
+var errUnknownOverrideSetting = errors.New("unknown override_settings key")
+
+// filterUserSettings check user config keys against overrideSettingsKeys before merged to override_settings,
+// drop mode remove unknown keys with warn log, reject mode return error
+func filterUserSettings(username string, settings map[string]interface{}) error {
+	keys := config.ConfigGlobal.OverrideSettingsKeys
+	if keys == nil {
+		return nil
+	}
+	unknown := make([]string, 0)
+	for key := range settings {
+		if !keys.IsKnown(key) {
+			unknown = append(unknown, key)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+	sort.Strings(unknown)
+	if keys.IsReject() {
+		return fmt.Errorf("%w: %s, please update config", errUnknownOverrideSetting, strings.Join(unknown, ","))
+	}
+	for _, key := range unknown {
+		delete(settings, key)
+	}
+	logrus.Warnf("user %s config unknown override_settings keys %s dropped", username, strings.Join(unknown, ","))
+	return nil
+}
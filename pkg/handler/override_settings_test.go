@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/datastore"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterUserSettings(t *testing.T) {
+	initTestConfig(t)
+	settings := map[string]interface{}{"CLIP_stop_at_last_layers": 2, "old_option": true}
+	// not set, keep all
+	assert.Nil(t, filterUserSettings("user", settings))
+	assert.Len(t, settings, 2)
+
+	config.ConfigGlobal.OverrideSettingsKeys = &config.OverrideSettingsKeys{
+		Known: []string{"CLIP_stop_at_last_layers"},
+		Mode:  config.UnknownSettingReject,
+	}
+	err := filterUserSettings("user", settings)
+	assert.ErrorIs(t, err, errUnknownOverrideSetting)
+	assert.Contains(t, err.Error(), "old_option")
+	assert.Len(t, settings, 2)
+
+	config.ConfigGlobal.OverrideSettingsKeys.Mode = config.UnknownSettingDrop
+	assert.Nil(t, filterUserSettings("user", settings))
+	assert.Equal(t, map[string]interface{}{"CLIP_stop_at_last_layers": 2}, settings)
+}
+
+func TestUpdateOverrideSettingsUnknownKeys(t *testing.T) {
+	initTestConfig(t)
+	configStore := datastore.NewSQLiteDatastore(datastore.NewSQLiteConfig(datastore.KConfigTableName))
+	defer configStore.Close()
+	assert.Nil(t, configStore.Put("user_1", map[string]interface{}{
+		datastore.KConfigVal: `{"CLIP_stop_at_last_layers":2,"old_option":true,"eta_noise_seed_delta":31337}`,
+		datastore.KConfigVer: "1",
+	}))
+	p := &ProxyHandler{configStore: configStore}
+	config.ConfigGlobal.OverrideSettingsKeys = &config.OverrideSettingsKeys{
+		Known: []string{"CLIP_stop_at_last_layers", "eta_noise_seed_delta"},
+	}
+
+	// request value keep priority, unknown key dropped
+	settings := map[string]interface{}{"CLIP_stop_at_last_layers": float64(1)}
+	assert.Nil(t, p.updateOverrideSettingsRequest(&settings, "user", "1", "sd.safetensors", nil))
+	assert.Equal(t, map[string]interface{}{
+		"CLIP_stop_at_last_layers": float64(1),
+		"eta_noise_seed_delta":     float64(31337),
+		"sd_model_checkpoint":      "sd.safetensors",
+		"sd_vae":                   "None",
+	}, settings)
+
+	config.ConfigGlobal.OverrideSettingsKeys.Mode = config.UnknownSettingReject
+	settings = map[string]interface{}{}
+	assert.ErrorIs(t, p.updateOverrideSettingsRequest(&settings, "user", "1", "sd.safetensors", nil),
+		errUnknownOverrideSetting)
+	// default config not checked
+	assert.Nil(t, p.updateOverrideSettingsRequest(&settings, "user", "-1", "sd.safetensors", nil))
+}
@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/datastore"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/models"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/module"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"strconv"
+	"strings"
+)
+
+var errPartialUpload = errors.New("some images upload failed, uploaded images kept")
+
+// partialUploadError failed image indices of batch and first upload error
+func partialUploadError(failed []int, err error) error {
+	return fmt.Errorf("%w, failed images=%v, err=%v", errPartialUpload, failed, err)
+}
+
+// partialUploadResponse sync response of partial upload task, oss url of uploaded images,
+// raw oss path when get url fail
+func partialUploadResponse(taskId string, images []string, err error) models.SubmitTaskResponse {
+	ossUrl := images
+	if urls, urlErr := module.OssGlobal.GetUrl(images); urlErr != nil {
+		logrus.WithFields(logrus.Fields{"taskId": taskId}).Warnf("get oss url err=%s", urlErr.Error())
+	} else {
+		ossUrl = urls
+	}
+	return models.SubmitTaskResponse{
+		TaskId:     taskId,
+		Status:     config.TASK_PARTIAL_UPLOAD,
+		OssUrl:     &ossUrl,
+		InvokeMode: utils.String(invokeModeSync),
+		Message:    utils.String(err.Error()),
+	}
+}
+
+// uploadedImages oss paths of batch without failed indices, keep order
+func uploadedImages(ossPaths []string, failed []int) []string {
+	ret := make([]string, 0, len(ossPaths))
+	for i, j := 0, 0; i < len(ossPaths); i++ {
+		if j < len(failed) && failed[j] == i {
+			j++
+			continue
+		}
+		ret = append(ret, ossPaths[i])
+	}
+	return ret
+}
+
+// failedImagesVal task column of failed image indices, like 1,3
+func failedImagesVal(failed []int) string {
+	vals := make([]string, 0, len(failed))
+	for _, idx := range failed {
+		vals = append(vals, strconv.Itoa(idx))
+	}
+	return strings.Join(vals, ",")
+}
+
+// taskFailedImages failed image indices of partial upload task, nil when not recorded
+func taskFailedImages(data map[string]interface{}) *[]int {
+	val, _ := data[datastore.KTaskFailedImages].(string)
+	if val == "" {
+		return nil
+	}
+	failed := make([]int, 0)
+	for _, one := range strings.Split(val, ",") {
+		if idx, err := strconv.Atoi(one); err == nil {
+			failed = append(failed, idx)
+		}
+	}
+	return &failed
+}
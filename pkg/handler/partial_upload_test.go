@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/datastore"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUploadedImages(t *testing.T) {
+	images := []string{"a.png", "b.png", "c.png", "d.png"}
+	assert.Equal(t, images, uploadedImages(images, nil))
+	assert.Equal(t, []string{"a.png", "c.png"}, uploadedImages(images, []int{1, 3}))
+	assert.Empty(t, uploadedImages(images, []int{0, 1, 2, 3}))
+}
+
+func TestFailedImagesVal(t *testing.T) {
+	assert.Equal(t, "1,3", failedImagesVal([]int{1, 3}))
+	data := map[string]interface{}{datastore.KTaskFailedImages: "1,3"}
+	assert.Equal(t, []int{1, 3}, *taskFailedImages(data))
+	assert.Nil(t, taskFailedImages(map[string]interface{}{}))
+
+	err := partialUploadError([]int{2}, errors.New("upload fail"))
+	assert.True(t, errors.Is(err, errPartialUpload))
+}
+
+func TestTaskResultPartialUpload(t *testing.T) {
+	data := map[string]interface{}{
+		datastore.KTaskCode:         int64(requestOk),
+		datastore.KTaskStatus:       config.TASK_PARTIAL_UPLOAD,
+		datastore.KTaskImage:        "images/a.png,images/c.png",
+		datastore.KTaskFailedImages: "1",
+		datastore.KTaskParams:       "{}",
+		datastore.KTaskInfo:         "{}",
+	}
+	result, err := taskResultFromData("task1", data)
+	assert.Nil(t, err)
+	assert.Equal(t, config.TASK_PARTIAL_UPLOAD, result.Status)
+	assert.Equal(t, []string{"images/a.png", "images/c.png"}, *result.Images)
+	assert.Equal(t, []int{1}, *result.FailedImages)
+	assert.Equal(t, errPartialUpload.Error(), *result.Message)
+}
+
+func TestPartialUploadResponse(t *testing.T) {
+	mockOss(t, 0)
+	err := partialUploadError([]int{1}, errors.New("upload fail"))
+	response := partialUploadResponse("task1", []string{"images/a.png", "images/c.png"}, err)
+	assert.Equal(t, config.TASK_PARTIAL_UPLOAD, response.Status)
+	assert.Equal(t, []string{"http://oss/images/a.png", "http://oss/images/c.png"}, *response.OssUrl)
+	assert.Equal(t, err.Error(), *response.Message)
+}
@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/models"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/utils"
+	"io"
+	"net/http"
+)
+
+const (
+	defaultPostUpscaler = "Lanczos"
+	maxPostUpscale      = 8
+	// error body of extras logged at most
+	postProcessErrorBodyMaxSize = 512
+)
+
+// face restoration models of extras
+const (
+	restoreFacesGfpgan     = "gfpgan"
+	restoreFacesCodeformer = "codeformer"
+)
+
+// normalizePostProcess check post process spec and set defaults, nil when nothing to do
+func normalizePostProcess(pp *models.PostProcess) (*models.PostProcess, error) {
+	if pp == nil {
+		return nil, nil
+	}
+	if pp.UpscalingResize != nil && (*pp.UpscalingResize < 1 || *pp.UpscalingResize > maxPostUpscale) {
+		return nil, fmt.Errorf("post_process upscaling_resize %v invalid, need 1-%d", *pp.UpscalingResize,
+			maxPostUpscale)
+	}
+	for name, value := range map[string]*float32{
+		"restore_faces_visibility": pp.RestoreFacesVisibility,
+		"codeformer_weight":        pp.CodeformerWeight,
+	} {
+		if value != nil && (*value < 0 || *value > 1) {
+			return nil, fmt.Errorf("post_process %s %v invalid, need 0-1", name, *value)
+		}
+	}
+	restoreFaces := ""
+	if pp.RestoreFaces != nil {
+		restoreFaces = *pp.RestoreFaces
+	}
+	if restoreFaces != "" && restoreFaces != restoreFacesGfpgan && restoreFaces != restoreFacesCodeformer {
+		return nil, fmt.Errorf("post_process restore_faces %s invalid, need %s|%s", restoreFaces,
+			restoreFacesGfpgan, restoreFacesCodeformer)
+	}
+	upscale := pp.UpscalingResize != nil && *pp.UpscalingResize > 1
+	if !upscale && restoreFaces == "" {
+		return nil, nil
+	}
+	if upscale && (pp.Upscaler == nil || *pp.Upscaler == "") {
+		pp.Upscaler = utils.String(defaultPostUpscaler)
+	}
+	if restoreFaces != "" && pp.RestoreFacesVisibility == nil {
+		pp.RestoreFacesVisibility = utils.Float32(1)
+	}
+	return pp, nil
+}
+
+// postProcessImages run extras on rendered base64 images, return processed images in same order
+func (p *ProxyHandler) postProcessImages(ctx context.Context, images []string,
+	pp *models.PostProcess) ([]string, error) {
+	request := models.ExtraBatchImagesRequest{
+		ResizeMode: 0,
+		ImageList:  make([]models.ExtraBatchImage, 0, len(images)),
+	}
+	for i, image := range images {
+		request.ImageList = append(request.ImageList, models.ExtraBatchImage{
+			Data: image,
+			Name: utils.String(fmt.Sprintf("%d.png", i+1)),
+		})
+	}
+	if pp.UpscalingResize != nil && *pp.UpscalingResize > 1 {
+		request.UpscalingResize = pp.UpscalingResize
+		request.Upscaler1 = pp.Upscaler
+	}
+	if pp.RestoreFaces != nil {
+		switch *pp.RestoreFaces {
+		case restoreFacesGfpgan:
+			request.GfpganVisibility = pp.RestoreFacesVisibility
+		case restoreFacesCodeformer:
+			request.CodeformerVisibility = pp.RestoreFacesVisibility
+			request.CodeformerWeight = pp.CodeformerWeight
+		}
+	}
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	// render slot released, wait again like a new predict
+	release, err := p.predictLimit.acquire()
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		fmt.Sprintf("%s%s", config.ConfigGlobal.SdUrlPrefix, config.EXTRABATCHIMAGES), bytes.NewBuffer(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != requestOk {
+		errBody, _ := io.ReadAll(io.LimitReader(resp.Body, postProcessErrorBodyMaxSize))
+		return nil, fmt.Errorf("extras status code=%d, body=%s", resp.StatusCode, string(errBody))
+	}
+	var result models.Txt2ImgResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	if len(result.Images) != len(images) {
+		return nil, errors.New("extras images count not match")
+	}
+	return result.Images, nil
+}
@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/datastore"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/models"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizePostProcess(t *testing.T) {
+	pp, err := normalizePostProcess(nil)
+	assert.Nil(t, err)
+	assert.Nil(t, pp)
+	// nothing to do
+	pp, err = normalizePostProcess(&models.PostProcess{UpscalingResize: utils.Float32(1)})
+	assert.Nil(t, err)
+	assert.Nil(t, pp)
+
+	pp, err = normalizePostProcess(&models.PostProcess{UpscalingResize: utils.Float32(2),
+		RestoreFaces: utils.String(restoreFacesCodeformer)})
+	assert.Nil(t, err)
+	assert.Equal(t, defaultPostUpscaler, *pp.Upscaler)
+	assert.Equal(t, float32(1), *pp.RestoreFacesVisibility)
+
+	for _, invalid := range []*models.PostProcess{
+		{UpscalingResize: utils.Float32(0.5)},
+		{UpscalingResize: utils.Float32(maxPostUpscale + 1)},
+		{RestoreFaces: utils.String("unknown")},
+		{RestoreFaces: utils.String(restoreFacesGfpgan), RestoreFacesVisibility: utils.Float32(1.5)},
+		{RestoreFaces: utils.String(restoreFacesCodeformer), CodeformerWeight: utils.Float32(-1)},
+	} {
+		_, err = normalizePostProcess(invalid)
+		assert.NotNil(t, err)
+	}
+}
+
+func TestPredictPostProcess(t *testing.T) {
+	initTestConfig(t)
+	config.ConfigGlobal.ImageNameTemplate = config.DefaultImageNameTemplate
+	oss := mockOss(t, 0)
+	raw := base64.StdEncoding.EncodeToString([]byte("raw"))
+	var extras models.ExtraBatchImagesRequest
+	extrasStatus := http.StatusOK
+	sd := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case config.TXT2IMG:
+			json.NewEncoder(w).Encode(map[string]interface{}{"images": []string{raw, raw}, "info": ""})
+		case config.EXTRABATCHIMAGES:
+			assert.Nil(t, json.NewDecoder(r.Body).Decode(&extras))
+			w.WriteHeader(extrasStatus)
+			images := make([]string, 0, len(extras.ImageList))
+			for range extras.ImageList {
+				images = append(images, base64.StdEncoding.EncodeToString([]byte("upscaled")))
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"images": images, "html_info": ""})
+		}
+	}))
+	defer sd.Close()
+	config.ConfigGlobal.SdUrlPrefix = sd.URL
+	taskStore := datastore.NewSQLiteDatastore(datastore.NewSQLiteConfig(datastore.KTaskTableName))
+	defer taskStore.Close()
+	p := &ProxyHandler{taskStore: taskStore, httpClient: &http.Client{}}
+	pp, err := normalizePostProcess(&models.PostProcess{UpscalingResize: utils.Float32(2),
+		RestoreFaces: utils.String(restoreFacesGfpgan)})
+	assert.Nil(t, err)
+
+	for _, taskId := range []string{"task", "fail"} {
+		assert.Nil(t, taskStore.Put(taskId, map[string]interface{}{
+			datastore.KTaskIdColumnName: taskId,
+			datastore.KTaskStatus:       config.TASK_QUEUE,
+		}))
+	}
+	images, _, err := p.predictTask("user", "task", config.TXT2IMG, []byte("{}"), predictOptions{postProcess: pp})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"images/user/task_1.png", "images/user/task_2.png"}, images)
+	// uploaded post processed images
+	assert.Equal(t, []byte("upscaled"), oss.uploaded["images/user/task_1.png"])
+	assert.Len(t, extras.ImageList, 2)
+	assert.Equal(t, raw, extras.ImageList[0].Data)
+	assert.Equal(t, float32(2), *extras.UpscalingResize)
+	assert.Equal(t, defaultPostUpscaler, *extras.Upscaler1)
+	assert.Equal(t, float32(1), *extras.GfpganVisibility)
+	assert.Nil(t, extras.CodeformerVisibility)
+
+	// extras fail, task fail
+	extrasStatus = http.StatusInternalServerError
+	_, _, err = p.predictTask("user", "fail", config.TXT2IMG, []byte("{}"), predictOptions{postProcess: pp})
+	assert.NotNil(t, err)
+	task, err := taskStore.Get("fail", []string{datastore.KTaskStatus})
+	assert.Nil(t, err)
+	assert.Equal(t, config.TASK_FAILED, task[datastore.KTaskStatus])
+	assert.NotContains(t, oss.uploaded, "images/user/fail_1.png")
+}
@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+)
+
+// errPredictQueueTimeout no predict slot released in queue timeout
+var errPredictQueueTimeout = errors.New("too many predict requests, wait predict slot timeout")
+
+// predictLimiter bound concurrent direct predict to single sd instance, nil means no limit
+type predictLimiter struct {
+	slots   chan struct{}
+	timeout time.Duration
+}
+
+func newPredictLimiter(concurrency int, timeout time.Duration) *predictLimiter {
+	if concurrency <= 0 {
+		return nil
+	}
+	return &predictLimiter{slots: make(chan struct{}, concurrency), timeout: timeout}
+}
+
+// acquire wait slot at most timeout, call release after predict done
+func (l *predictLimiter) acquire() (release func(), err error) {
+	if l == nil {
+		return func() {}, nil
+	}
+	timer := time.NewTimer(l.timeout)
+	defer timer.Stop()
+	select {
+	case l.slots <- struct{}{}:
+		return func() { <-l.slots }, nil
+	case <-timer.C:
+		return nil, errPredictQueueTimeout
+	}
+}
+
+// predictErrorCode 503 when sd busy, client can retry later, 410 when model removed,
+// 200 when webui return no images or some images upload fail since predict itself succeeded
+func predictErrorCode(err error) int {
+	if errors.Is(err, errNoOutput) || errors.Is(err, errPartialUpload) {
+		return http.StatusOK
+	}
+	if errors.Is(err, errPredictQueueTimeout) {
+		return http.StatusServiceUnavailable
+	}
+	if errors.Is(err, errModelRemoved) {
+		return http.StatusGone
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,179 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/datastore"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/models"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"sort"
+)
+
+var (
+	errProfileNotFound = errors.New("profile not found")
+	errReadProfiles    = errors.New("read model profiles from db error")
+)
+
+// ListProfiles list model profiles of deployment
+// (GET /admin/profiles)
+func (p *ProxyHandler) ListProfiles(c *gin.Context) {
+	profiles, err := p.getProfiles()
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, errReadProfiles.Error())
+		return
+	}
+	ret := make([]models.ModelProfile, 0, len(profiles))
+	for name, profile := range profiles {
+		profile.Name = utils.String(name)
+		ret = append(ret, profile)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return *ret[i].Name < *ret[j].Name
+	})
+	c.JSON(http.StatusOK, ret)
+}
+
+// GetProfile get model profile
+// (GET /admin/profiles/{profile_name})
+func (p *ProxyHandler) GetProfile(c *gin.Context, profileName string) {
+	profiles, err := p.getProfiles()
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, errReadProfiles.Error())
+		return
+	}
+	profile, ok := profiles[profileName]
+	if !ok {
+		handleError(c, http.StatusNotFound, config.NOTFOUND)
+		return
+	}
+	profile.Name = utils.String(profileName)
+	c.JSON(http.StatusOK, profile)
+}
+
+// UpdateProfile create or update model profile, effective without redeploy
+// (PUT /admin/profiles/{profile_name})
+func (p *ProxyHandler) UpdateProfile(c *gin.Context, profileName string) {
+	request := new(models.UpdateProfileJSONRequestBody)
+	if err := getBindResult(c, request); err != nil || request.StableDiffusionModel == "" {
+		handleError(c, http.StatusBadRequest, config.BADREQUEST)
+		return
+	}
+	if !promptTemplateNameRegex.MatchString(profileName) {
+		handleError(c, http.StatusBadRequest, "profile name only support letters, digits, _ - .")
+		return
+	}
+	// name from path param
+	request.Name = nil
+	if err := p.updateProfiles(func(profiles map[string]models.ModelProfile) error {
+		profiles[profileName] = *request
+		return nil
+	}); err != nil {
+		handleError(c, http.StatusInternalServerError, "update db error")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "success"})
+}
+
+// DeleteProfile delete model profile
+// (DELETE /admin/profiles/{profile_name})
+func (p *ProxyHandler) DeleteProfile(c *gin.Context, profileName string) {
+	if err := p.updateProfiles(func(profiles map[string]models.ModelProfile) error {
+		if _, ok := profiles[profileName]; !ok {
+			return errProfileNotFound
+		}
+		delete(profiles, profileName)
+		return nil
+	}); err != nil {
+		if errors.Is(err, errProfileNotFound) {
+			handleError(c, http.StatusNotFound, config.NOTFOUND)
+		} else {
+			handleError(c, http.StatusInternalServerError, "update db error")
+		}
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "delete success"})
+}
+
+// applyProfile expand request profile into stable_diffusion_model, sd_vae and default params which request not set,
+// profile removed from request, return whether request changed
+func (p *ProxyHandler) applyProfile(request map[string]interface{}) (bool, error) {
+	val, existed := request["profile"]
+	if !existed {
+		return false, nil
+	}
+	delete(request, "profile")
+	name, _ := val.(string)
+	if name == "" {
+		return true, nil
+	}
+	profiles, err := p.getProfiles()
+	if err != nil {
+		return false, errReadProfiles
+	}
+	profile, ok := profiles[name]
+	if !ok {
+		return false, fmt.Errorf("%w: %s", errProfileNotFound, name)
+	}
+	if sdModel, _ := request["stable_diffusion_model"].(string); sdModel == "" {
+		request["stable_diffusion_model"] = profile.StableDiffusionModel
+	}
+	if _, existed := request["sd_vae"]; !existed && profile.SdVae != nil {
+		request["sd_vae"] = *profile.SdVae
+	}
+	if profile.Defaults != nil {
+		for key, val := range *profile.Defaults {
+			if _, existed := request[key]; !existed {
+				request[key] = val
+			}
+		}
+	}
+	return true, nil
+}
+
+// handleBindError response bind error of predict request, profile and preset error keep its message
+func handleBindError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, errProfileNotFound), errors.Is(err, errPresetNotFound):
+		handleError(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, errReadProfiles):
+		handleError(c, http.StatusInternalServerError, err.Error())
+	default:
+		handleError(c, http.StatusBadRequest, config.BADREQUEST)
+	}
+}
+
+// get model profiles, name -> profile
+func (p *ProxyHandler) getProfiles() (map[string]models.ModelProfile, error) {
+	profiles := make(map[string]models.ModelProfile)
+	data, err := p.configStore.Get(modelProfilesKey, []string{datastore.KConfigVal})
+	if err != nil {
+		return nil, err
+	}
+	if val, ok := data[datastore.KConfigVal].(string); ok && val != "" {
+		if err := json.Unmarshal([]byte(val), &profiles); err != nil {
+			return nil, err
+		}
+	}
+	return profiles, nil
+}
+
+// updateProfiles read-modify-write model profiles, concurrent update not lost
+func (p *ProxyHandler) updateProfiles(update func(profiles map[string]models.ModelProfile) error) error {
+	return p.updateConfigVal(modelProfilesKey, func(val string) (string, error) {
+		profiles := make(map[string]models.ModelProfile)
+		if val != "" {
+			if err := json.Unmarshal([]byte(val), &profiles); err != nil {
+				return "", err
+			}
+		}
+		if err := update(profiles); err != nil {
+			return "", err
+		}
+		ret, err := json.Marshal(profiles)
+		return string(ret), err
+	})
+}
@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/datastore"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/models"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProfileCRUD(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	initTestConfig(t)
+	configStore := datastore.NewSQLiteDatastore(datastore.NewSQLiteConfig(datastore.KConfigTableName))
+	defer configStore.Close()
+	p := &ProxyHandler{configStore: configStore}
+	call := func(method, name, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(method, "/admin/profiles/"+name, strings.NewReader(body))
+		switch {
+		case name == "":
+			p.ListProfiles(c)
+		case method == http.MethodPut:
+			p.UpdateProfile(c, name)
+		case method == http.MethodDelete:
+			p.DeleteProfile(c, name)
+		default:
+			p.GetProfile(c, name)
+		}
+		return w
+	}
+
+	assert.Equal(t, http.StatusNotFound, call(http.MethodGet, "portrait", "").Code)
+	assert.Equal(t, http.StatusBadRequest, call(http.MethodPut, "portrait", `{"sd_vae":"vae"}`).Code)
+	assert.Equal(t, http.StatusBadRequest, call(http.MethodPut, "a*b", `{"stable_diffusion_model":"sd"}`).Code)
+	assert.Equal(t, http.StatusOK, call(http.MethodPut, "portrait",
+		`{"stable_diffusion_model":"sdxl.safetensors","sd_vae":"vae.safetensors","defaults":{"steps":30}}`).Code)
+	assert.Equal(t, http.StatusOK, call(http.MethodPut, "anime", `{"stable_diffusion_model":"anime.safetensors"}`).Code)
+
+	var profile models.ModelProfile
+	assert.Nil(t, json.Unmarshal(call(http.MethodGet, "portrait", "").Body.Bytes(), &profile))
+	assert.Equal(t, "portrait", *profile.Name)
+	assert.Equal(t, "sdxl.safetensors", profile.StableDiffusionModel)
+	assert.Equal(t, "vae.safetensors", *profile.SdVae)
+	var profiles []models.ModelProfile
+	assert.Nil(t, json.Unmarshal(call(http.MethodGet, "", "").Body.Bytes(), &profiles))
+	assert.Equal(t, 2, len(profiles))
+	assert.Equal(t, "anime", *profiles[0].Name)
+
+	assert.Equal(t, http.StatusOK, call(http.MethodDelete, "anime", "").Code)
+	assert.Equal(t, http.StatusNotFound, call(http.MethodDelete, "anime", "").Code)
+}
+
+func TestUnmarshalWithProfile(t *testing.T) {
+	initTestConfig(t)
+	configStore := datastore.NewSQLiteDatastore(datastore.NewSQLiteConfig(datastore.KConfigTableName))
+	defer configStore.Close()
+	p := &ProxyHandler{configStore: configStore}
+	assert.Nil(t, p.updateProfiles(func(profiles map[string]models.ModelProfile) error {
+		profiles["portrait"] = models.ModelProfile{StableDiffusionModel: "sdxl.safetensors",
+			SdVae:    utils.String("vae.safetensors"),
+			Defaults: &map[string]interface{}{"steps": 30, "cfg_scale": 7, "sampler_name": "Euler a"}}
+		return nil
+	}))
+	// model defaults lower than profile defaults
+	assert.Nil(t, configStore.Put(modelDefaultsKey("sdxl.safetensors"), map[string]interface{}{
+		datastore.KConfigVal: `{"steps":20,"width":1024}`,
+	}))
+
+	request := new(models.Txt2ImgRequest)
+	assert.Nil(t, p.unmarshalWithModelDefaults([]byte(`{"profile":"portrait","prompt":"cat","cfg_scale":5}`),
+		request))
+	assert.Equal(t, "sdxl.safetensors", request.StableDiffusionModel)
+	assert.Equal(t, "vae.safetensors", *request.SdVae)
+	assert.Equal(t, int64(30), *request.Steps)
+	assert.Equal(t, float32(5), *request.CfgScale)
+	assert.Equal(t, "Euler a", *request.SamplerName)
+	assert.Equal(t, int64(1024), *request.Width)
+	assert.Nil(t, request.Profile)
+
+	// request model and vae win
+	request = new(models.Txt2ImgRequest)
+	assert.Nil(t, p.unmarshalWithModelDefaults(
+		[]byte(`{"profile":"portrait","stable_diffusion_model":"other.safetensors","sd_vae":"None"}`), request))
+	assert.Equal(t, "other.safetensors", request.StableDiffusionModel)
+	assert.Equal(t, "None", *request.SdVae)
+	assert.Equal(t, int64(30), *request.Steps)
+
+	err := p.unmarshalWithModelDefaults([]byte(`{"profile":"missing"}`), new(models.Img2ImgRequest))
+	assert.ErrorIs(t, err, errProfileNotFound)
+}
@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"fmt"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// chars before unbalanced bracket shown in error
+const promptErrorContext = 20
+
+var promptBrackets = map[byte]byte{')': '(', ']': '[', '>': '<'}
+
+// promptGroup open bracket and top level ':' '|' in it
+type promptGroup struct {
+	open   byte
+	start  int
+	colons []int
+	pipe   bool
+}
+
+// validateRequestPrompts check prompt syntax when validatePrompt on
+func validateRequestPrompts(prompts ...*string) error {
+	if !config.ConfigGlobal.ValidatePrompt {
+		return nil
+	}
+	for _, prompt := range prompts {
+		if prompt == nil {
+			continue
+		}
+		if err := validatePromptSyntax(*prompt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validatePromptSyntax check webui prompt syntax: balanced ()/[]/<>, numeric weight of (text:weight)
+// and step of [from:to:when], escaped bracket and content of <lora:name:weight> ignored
+func validatePromptSyntax(prompt string) error {
+	var stack []*promptGroup
+	for i := 0; i < len(prompt); i++ {
+		ch := prompt[i]
+		if ch == '\\' {
+			i++
+			continue
+		}
+		if len(stack) > 0 && stack[len(stack)-1].open == '<' && ch != '>' {
+			continue
+		}
+		switch ch {
+		case '(', '[', '<':
+			stack = append(stack, &promptGroup{open: ch, start: i})
+		case ')', ']', '>':
+			if len(stack) == 0 || stack[len(stack)-1].open != promptBrackets[ch] {
+				return promptSyntaxError(prompt, i-promptErrorContext, i+1, fmt.Sprintf("unbalanced '%c'", ch))
+			}
+			group := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if err := group.check(prompt, i); err != nil {
+				return err
+			}
+		case ':':
+			if len(stack) > 0 {
+				stack[len(stack)-1].colons = append(stack[len(stack)-1].colons, i)
+			}
+		case '|':
+			if len(stack) > 0 {
+				stack[len(stack)-1].pipe = true
+			}
+		}
+	}
+	if len(stack) > 0 {
+		group := stack[len(stack)-1]
+		return promptSyntaxError(prompt, group.start, len(prompt), fmt.Sprintf("unclosed '%c'", group.open))
+	}
+	return nil
+}
+
+// check weight or step after last ':', end is index of close bracket
+func (g *promptGroup) check(prompt string, end int) error {
+	// [a|b] alternate words
+	if len(g.colons) == 0 || g.pipe {
+		return nil
+	}
+	value := strings.TrimSpace(prompt[g.colons[len(g.colons)-1]+1 : end])
+	switch g.open {
+	case '(':
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return promptSyntaxError(prompt, g.start, end+1, "weight should be number")
+		}
+	case '[':
+		if len(g.colons) > 2 {
+			return promptSyntaxError(prompt, g.start, end+1, "prompt editing should be [from:to:when]")
+		}
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return promptSyntaxError(prompt, g.start, end+1, "prompt editing step should be number")
+		}
+	}
+	return nil
+}
+
+// promptSyntaxError error with prompt[start:end], start moved to rune start
+func promptSyntaxError(prompt string, start, end int, reason string) error {
+	if start < 0 {
+		start = 0
+	}
+	for start > 0 && !utf8.RuneStart(prompt[start]) {
+		start--
+	}
+	return fmt.Errorf("prompt syntax error, %s: %q", reason, prompt[start:end])
+}
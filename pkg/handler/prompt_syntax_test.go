@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidatePromptSyntax(t *testing.T) {
+	valid := []string{
+		"a cat, (masterpiece:1.2), ((best quality)), [blurry]",
+		"(cat: -0.5 ), [dog:cat:0.4], [dog:10], [cat|dog], [cat::0.3]",
+		"<lora:add_detail:0.8>, <hypernet:x(1)>, \\(escaped\\), 一只猫(可爱:1.1)",
+		"",
+	}
+	for _, prompt := range valid {
+		assert.Nil(t, validatePromptSyntax(prompt), prompt)
+	}
+
+	invalid := map[string]string{
+		"a (cat:abc) dog":   `weight should be number: "(cat:abc)"`,
+		"masterpiece, cat)": `unbalanced ')': "masterpiece, cat)"`,
+		"a ((cat:1.2)":      `unclosed '(': "((cat:1.2)"`,
+		"[cat:dog:soon]":    `prompt editing step should be number: "[cat:dog:soon]"`,
+		"[a:b:c:0.5]":       `prompt editing should be [from:to:when]: "[a:b:c:0.5]"`,
+		"(cat]":             `unbalanced ']': "(cat]"`,
+		"<lora:x:0.8":       `unclosed '<': "<lora:x:0.8"`,
+		"一只很可爱很可爱很可爱很可爱的猫)": `unbalanced ')'`,
+	}
+	for prompt, msg := range invalid {
+		err := validatePromptSyntax(prompt)
+		if assert.NotNil(t, err, prompt) {
+			assert.Contains(t, err.Error(), msg)
+		}
+	}
+
+	// toggle
+	initTestConfig(t)
+	prompt := "(cat:abc)"
+	assert.Nil(t, validateRequestPrompts(&prompt, nil))
+	config.ConfigGlobal.ValidatePrompt = true
+	assert.NotNil(t, validateRequestPrompts(nil, &prompt))
+}
@@ -20,7 +20,12 @@ import (
 	"net/http"
 	"net/http/httputil"
 	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
 	"strings"
+	"time"
 )
 
 const DEFAULT_USER = "default"
@@ -32,18 +37,36 @@ type ProxyHandler struct {
 	httpClient    *http.Client // the http client
 	configStore   datastore.Datastore
 	functionStore datastore.Datastore
+	maintenance   *maintenance
+	warmPool      *warmPool
+	predictLimit  *predictLimiter
+	taskQueue     *taskQueue
+	userTasks     *userTaskCounter
+	janitor       *janitor
+	// queue snapshot shared by progress of queued tasks
+	queueSnapshots *queueSnapshotCache
 }
 
 func NewProxyHandler(taskStore datastore.Datastore,
 	modelStore datastore.Datastore, userStore datastore.Datastore,
 	configStore datastore.Datastore, functionStore datastore.Datastore) *ProxyHandler {
+	ossDownloadLimit = newDownloadLimiter(config.ConfigGlobal.OssDownloadConcurrency,
+		config.ConfigGlobal.GetOssDownloadTimeout())
 	return &ProxyHandler{
 		taskStore:     taskStore,
 		modelStore:    modelStore,
-		httpClient:    &http.Client{},
+		httpClient:    &http.Client{Transport: module.SdTransport(nil)},
 		userStore:     userStore,
 		configStore:   configStore,
 		functionStore: functionStore,
+		maintenance:   newMaintenance(configStore, taskStore),
+		warmPool:      newWarmPool(&http.Client{}),
+		predictLimit: newPredictLimiter(config.ConfigGlobal.PredictConcurrency,
+			config.ConfigGlobal.GetPredictQueueTimeout()),
+		taskQueue:      newTaskQueue(taskStore),
+		userTasks:      newUserTaskCounter(taskStore),
+		janitor:        newJanitor(),
+		queueSnapshots: new(queueSnapshotCache),
 	}
 }
 
@@ -101,7 +124,7 @@ func (p *ProxyHandler) Restart(c *gin.Context) {
 		}
 		c.JSON(http.StatusOK, gin.H{"message": "success"})
 	} else {
-		c.JSON(http.StatusNotFound, gin.H{"message": "not support"})
+		handleError(c, http.StatusNotFound, "not support")
 	}
 }
 
@@ -138,6 +161,11 @@ func (p *ProxyHandler) BatchUpdateResource(c *gin.Context) {
 		handleError(c, http.StatusBadRequest, err.Error())
 		return
 	}
+	if request.Timeout != nil && *request.Timeout > config.MaxFuncTimeout {
+		handleError(c, http.StatusBadRequest, fmt.Sprintf("timeout %d invalid, need <= %d", *request.Timeout,
+			config.MaxFuncTimeout))
+		return
+	}
 	// get request relevant function
 	funcDatas, err := getFunctionDatas(p.functionStore, request)
 	if err != nil {
@@ -174,7 +202,19 @@ func (p *ProxyHandler) CancelTask(c *gin.Context, taskId string) {
 // GetTaskResult  get predict progress
 // (GET /tasks/{taskId}/result)
 func (p *ProxyHandler) GetTaskResult(c *gin.Context, taskId string) {
-	result, err := p.getTaskResult(taskId)
+	data, err := p.taskStore.Get(taskId, taskETagColumns)
+	if err != nil || len(data) == 0 {
+		handleError(c, http.StatusNotFound, "not found")
+		return
+	}
+	// polling client not changed, skip result build and oss url sign
+	etag := taskResultETag(data)
+	c.Header("ETag", etag)
+	if etagMatch(c.GetHeader("If-None-Match"), etag) {
+		c.AbortWithStatus(http.StatusNotModified)
+		return
+	}
+	result, err := signedTaskResult(taskId, data)
 	if err != nil {
 		handleError(c, http.StatusNotFound, err.Error())
 		return
@@ -189,16 +229,16 @@ func (p *ProxyHandler) ListModels(c *gin.Context) {
 		// get from local disk
 		ret := make([]*models.ModelAttributes, 0)
 		// sdModel
-		path := fmt.Sprintf("%s/models/%s", config.ConfigGlobal.SdPath, "Stable-diffusion")
+		path := config.ConfigGlobal.GetModelDir(config.SD_MODEL)
 		ret = append(ret, listModelFile(path, config.SD_MODEL)...)
 		// sdVae
-		path = fmt.Sprintf("%s/models/%s", config.ConfigGlobal.SdPath, "VAE")
+		path = config.ConfigGlobal.GetModelDir(config.SD_VAE)
 		ret = append(ret, listModelFile(path, config.SD_VAE)...)
 		// lora
-		path = fmt.Sprintf("%s/models/%s", config.ConfigGlobal.SdPath, "Lora")
+		path = config.ConfigGlobal.GetModelDir(config.LORA_MODEL)
 		ret = append(ret, listModelFile(path, config.LORA_MODEL)...)
 		// controlNet
-		path = fmt.Sprintf("%s/models/%s", config.ConfigGlobal.SdPath, "ControlNet")
+		path = config.ConfigGlobal.GetModelDir(config.CONTORLNET_MODEL)
 		ret = append(ret, listModelFile(path, config.CONTORLNET_MODEL)...)
 		c.JSON(http.StatusOK, ret)
 	} else {
@@ -249,6 +289,11 @@ func (p *ProxyHandler) RegisterModel(c *gin.Context) {
 		return
 	}
 
+	// model file on nas but not loaded by sd if load fail
+	rollbackStatus := config.MODEL_UNLOADED
+	if status, _ := data[datastore.KModelStatus].(string); status == config.MODEL_LOADED {
+		rollbackStatus = status
+	}
 	// update db
 	data = map[string]interface{}{
 		datastore.KModelType:       request.Type,
@@ -256,12 +301,38 @@ func (p *ProxyHandler) RegisterModel(c *gin.Context) {
 		datastore.KModelOssPath:    request.OssPath,
 		datastore.KModelEtag:       request.Etag,
 		datastore.KModelLocalPath:  localFile,
-		datastore.KModelStatus:     getModelsStatus(request.Type),
+		datastore.KModelStatus:     config.MODEL_LOADING,
 		datastore.KModelCreateTime: fmt.Sprintf("%d", utils.TimestampS()),
 		datastore.KModelModifyTime: fmt.Sprintf("%d", utils.TimestampS()),
 	}
-	p.modelStore.Put(request.Name, data)
-	c.JSON(http.StatusOK, gin.H{"message": "register success"})
+	if err := p.modelStore.Put(request.Name, data); err != nil {
+		handleError(c, http.StatusInternalServerError, config.OTSPUTERROR)
+		return
+	}
+	if err := p.loadModel(request.Name, request.Type, rollbackStatus); err != nil {
+		if errors.Is(err, module.ErrFunctionNotExist) {
+			// model loaded by function created later
+			c.JSON(http.StatusOK, gin.H{"message": "register success, function not created"})
+			return
+		}
+		logrus.Warnf("model %s load fail, err=%s", request.Name, err.Error())
+		handleError(c, http.StatusInternalServerError, config.MODELUPDATEFCERROR)
+		return
+	}
+	ret := gin.H{"message": "register success"}
+	// running webui list new checkpoint without instance recycled
+	if request.Type == config.SD_MODEL {
+		if endpoint := hotModelEndpoint(request.Name); endpoint != "" {
+			if err := module.RefreshSdModel(endpoint, request.Name); err != nil {
+				logrus.Warnf("model %s hot refresh fail, err=%s", request.Name, err.Error())
+				ret["hotRefreshed"] = false
+				ret["hotRefreshMessage"] = err.Error()
+			} else {
+				ret["hotRefreshed"] = true
+			}
+		}
+	}
+	c.JSON(http.StatusOK, ret)
 }
 
 // DeleteModel delete model
@@ -281,21 +352,27 @@ func (p *ProxyHandler) DeleteModel(c *gin.Context, modelName string) {
 		handleError(c, http.StatusInternalServerError, "model not exist")
 		return
 	}
-	localFile := data[datastore.KModelLocalPath].(string)
+	if err := p.deleteModel(modelName, data[datastore.KModelLocalPath].(string)); err != nil {
+		handleError(c, http.StatusInternalServerError, err.Error())
+	} else {
+		c.JSON(http.StatusOK, gin.H{"message": "delete success"})
+	}
+}
+
+// deleteModel delete nas model file and set model status deleted
+func (p *ProxyHandler) deleteModel(modelName, localFile string) error {
 	// delete nas models
 	if ok, err := utils.DeleteLocalFile(localFile); !ok {
-		handleError(c, http.StatusInternalServerError, err.Error())
-		return
+		return err
 	}
 	// model status set deleted
 	if err := p.modelStore.Update(modelName, map[string]interface{}{
 		datastore.KModelStatus:     config.MODEL_DELETE,
 		datastore.KModelModifyTime: fmt.Sprintf("%d", utils.TimestampS()),
 	}); err != nil {
-		handleError(c, http.StatusInternalServerError, "update model status error")
-	} else {
-		c.JSON(http.StatusOK, gin.H{"message": "delete success"})
+		return errors.New("update model status error")
 	}
+	return nil
 }
 
 // GetModel get model info
@@ -322,6 +399,59 @@ func (p *ProxyHandler) GetModel(c *gin.Context, modelName string) {
 
 }
 
+// DistributeModel refresh functions env, instances load model before first request
+// checkpoint only refresh function serve it, function created when none, other models refresh all functions
+// (POST /models/{model_name}/distribute)
+func (p *ProxyHandler) DistributeModel(c *gin.Context, modelName string) {
+	if config.ConfigGlobal.GetFlexMode() != config.MultiFunc || !config.ConfigGlobal.IsServerTypeMatch(config.CONTROL) {
+		handleError(c, http.StatusBadRequest, "only support multiFunc control")
+		return
+	}
+	// local model type unknown, only function serve it refreshed
+	modelType := ""
+	if !config.ConfigGlobal.UseLocalModel() {
+		data, err := p.modelStore.Get(modelName, []string{datastore.KModelName, datastore.KModelType})
+		if err != nil {
+			handleError(c, http.StatusInternalServerError, "get model info from db error")
+			return
+		}
+		if len(data) == 0 {
+			handleError(c, http.StatusNotFound, config.NOTFOUND)
+			return
+		}
+		modelType, _ = data[datastore.KModelType].(string)
+	}
+	var results []module.FuncEnvResult
+	if modelType == "" || modelType == config.SD_MODEL {
+		results = getEndpointManager().RefreshFunctionsEnv(modelName)
+		if len(results) == 0 {
+			// new function env already has latest models, created function warm the model
+			_, err := getEndpointManager().GetEndpoint(modelName)
+			if errors.Is(err, module.ErrColdStartBudget) {
+				handleError(c, http.StatusTooManyRequests, err.Error())
+				return
+			}
+			results = []module.FuncEnvResult{{FunctionName: functionName(modelName), SdModel: modelName, Err: err}}
+		}
+	} else {
+		results = getEndpointManager().RefreshFunctionsEnv("")
+	}
+	resp := models.DistributeModelResult{Model: modelName, Results: make([]models.FunctionResult, 0, len(results))}
+	status := http.StatusOK
+	for _, result := range results {
+		item := models.FunctionResult{FunctionName: result.FunctionName, SdModel: result.SdModel, Success: true}
+		if result.Err != nil {
+			logrus.Warnf("distribute model %s to function %s err=%s", modelName, result.FunctionName,
+				result.Err.Error())
+			item.Success = false
+			item.Message = utils.String(result.Err.Error())
+			status = http.StatusInternalServerError
+		}
+		resp.Results = append(resp.Results, item)
+	}
+	c.JSON(status, resp)
+}
+
 // UpdateModel update model
 // (PUT /models/{model_name})
 func (p *ProxyHandler) UpdateModel(c *gin.Context, modelName string) {
@@ -361,26 +491,24 @@ func (p *ProxyHandler) UpdateModel(c *gin.Context, modelName string) {
 			"err=%s", err.Error()))
 		return
 	}
-	// sdModel and sdVae enable env update
-	if request.Type == config.SD_MODEL || request.Type == config.SD_VAE {
-		if err := module.FuncManagerGlobal.UpdateFunctionEnv(request.Name); err != nil {
-			handleError(c, http.StatusInternalServerError, config.MODELUPDATEFCERROR)
-			return
-		}
-	}
-
+	rollbackStatus := data[datastore.KModelStatus].(string)
 	// update db
 	data = map[string]interface{}{
 		datastore.KModelType:       request.Type,
 		datastore.KModelOssPath:    request.OssPath,
 		datastore.KModelEtag:       request.Etag,
-		datastore.KModelStatus:     getModelsStatus(request.Type),
+		datastore.KModelStatus:     config.MODEL_LOADING,
 		datastore.KModelModifyTime: fmt.Sprintf("%d", utils.TimestampS()),
 	}
 	if err := p.modelStore.Update(modelName, data); err != nil {
 		handleError(c, http.StatusInternalServerError, config.NOTFOUND)
 		return
 	}
+	if err := p.loadModel(modelName, request.Type, rollbackStatus); err != nil && !errors.Is(err, module.ErrFunctionNotExist) {
+		logrus.Warnf("model %s load fail, err=%s", modelName, err.Error())
+		handleError(c, http.StatusInternalServerError, config.MODELUPDATEFCERROR)
+		return
+	}
 	c.JSON(http.StatusOK, gin.H{"message": "success"})
 
 }
@@ -389,7 +517,7 @@ func (p *ProxyHandler) UpdateModel(c *gin.Context, modelName string) {
 // (GET /tasks/{taskId}/progress)
 func (p *ProxyHandler) GetTaskProgress(c *gin.Context, taskId string) {
 	data, err := p.taskStore.Get(taskId, []string{datastore.KTaskIdColumnName, datastore.KTaskStatus,
-		datastore.KTaskProgressColumnName})
+		datastore.KTaskProgressColumnName, datastore.KTaskLabels, datastore.KTaskModel})
 	if err != nil || data == nil || len(data) == 0 {
 		handleError(c, http.StatusNotFound, config.NOTFOUND)
 		return
@@ -400,14 +528,31 @@ func (p *ProxyHandler) GetTaskProgress(c *gin.Context, taskId string) {
 			handleError(c, http.StatusInternalServerError, config.NOTFOUND)
 			return
 		}
+		fillProgressEta([]byte(progress.(string)), resp)
 	}
-	if status, ok := data[datastore.KTaskStatus]; ok && (status == config.TASK_FINISH || status == config.TASK_FAILED) {
+	status, _ := data[datastore.KTaskStatus].(string)
+	if module.IsTaskTerminal(status) {
 		resp.Progress = 1
+		resp.EtaRelative = 0
+		resp.EtaSeconds = utils.Float32(0)
+		// no write after terminal, final seq newer than any written
+		seq := int64(1)
+		if resp.Seq != nil {
+			seq = *resp.Seq + 1
+		}
+		resp.Seq = &seq
 	} else if resp.Progress == 1 {
-		// task finish need status == config.TASK_FINISH|config.TASK_FAILED
+		// task finish need terminal status, see module.IsTaskTerminal
 		resp.Progress = 0.99
+	} else if status == config.TASK_QUEUE && resp.Progress <= 0 {
+		// not rendering yet, webui progress take over once positive
+		sdModel, _ := data[datastore.KTaskModel].(string)
+		if err := p.fillQueueEstimate(sdModel, taskId, resp); err != nil {
+			logrus.WithFields(logrus.Fields{"taskId": taskId}).Warnf("queue estimate err=%s", err.Error())
+		}
 	}
 	resp.TaskId = taskId
+	resp.Labels = taskLabels(data)
 	c.JSON(http.StatusOK, resp)
 }
 
@@ -424,11 +569,22 @@ func (p *ProxyHandler) ExtraImages(c *gin.Context) {
 			username = DEFAULT_USER
 		}
 	}
+	// result images returned by downstream
+	if rejectInlineImages(c) {
+		return
+	}
 	request := new(models.ExtraImagesJSONRequestBody)
 	if err := getBindResult(c, request); err != nil {
 		handleError(c, http.StatusBadRequest, config.BADREQUEST)
 		return
 	}
+	// labels stored in task, not forward
+	labels, err := taskLabelsVal(request.Labels)
+	if err != nil {
+		handleError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	request.Labels = nil
 	// taskId
 	taskId := c.GetHeader(taskKey)
 	if taskId == "" {
@@ -436,12 +592,30 @@ func (p *ProxyHandler) ExtraImages(c *gin.Context) {
 		taskId = utils.RandStr(taskIdLength)
 	}
 	c.Writer.Header().Set("taskId", taskId)
+	leaveUser := func() {}
+	if config.ConfigGlobal.IsServerTypeMatch(config.PROXY) {
+		var rejected bool
+		if leaveUser, rejected = p.enterUserTasks(c, username, taskId, 1); rejected {
+			return
+		}
+		defer leaveUser()
+	}
 
 	endPoint := config.ConfigGlobal.Downstream
-	var err error
 	if config.ConfigGlobal.IsServerTypeMatch(config.CONTROL) {
-		if endPoint = module.FuncManagerGlobal.GetLastInvokeEndpoint(request.StableDiffusionModel); endPoint == "" {
-			handleError(c, http.StatusInternalServerError, "not found valid endpoint")
+		sdModel := ""
+		if request.StableDiffusionModel != nil {
+			sdModel = *request.StableDiffusionModel
+		}
+		// model may deleted while async request queued
+		if sdModel != "" {
+			var ok bool
+			if sdModel, ok = p.resolveForwardRequest(c, taskId, sdModel, request); !ok {
+				return
+			}
+		}
+		if endPoint, err = getStickyEndpoint(c, sdModel, true); err != nil {
+			handleEndpointError(c, taskId, err)
 			return
 		}
 	}
@@ -451,7 +625,9 @@ func (p *ProxyHandler) ExtraImages(c *gin.Context) {
 		datastore.KTaskIdColumnName: taskId,
 		datastore.KTaskUser:         username,
 		datastore.KTaskStatus:       config.TASK_QUEUE,
+		datastore.KTaskCancel:       int64(config.CANCEL_INIT),
 		datastore.KTaskCreateTime:   fmt.Sprintf("%d", utils.TimestampS()),
+		datastore.KTaskLabels:       labels,
 	}); err != nil {
 		logrus.WithFields(logrus.Fields{"taskId": taskId}).Errorf("put db err=%s", err.Error())
 		c.JSON(http.StatusInternalServerError, models.SubmitTaskResponse{
@@ -461,44 +637,108 @@ func (p *ProxyHandler) ExtraImages(c *gin.Context) {
 		})
 		return
 	}
+	// task counted by row until terminal
+	leaveUser()
 
-	ctx, cancel := context.WithTimeout(context.Background(), config.HTTPTIMEOUT)
+	timeoutCtx, cancel := context.WithTimeout(context.Background(), config.HTTPTIMEOUT)
 	defer cancel()
+	// big upscale run long, stop waiting when task cancelled
+	ctx, stopWatch := p.watchTaskCancel(timeoutCtx, taskId)
 	// get client by endPoint
 	client := client.ManagerClientGlobal.GetClient(endPoint)
 	// async request
-	resp, err := client.ExtraImages(ctx, *request, func(ctx context.Context, req *http.Request) error {
+	editor := func(ctx context.Context, req *http.Request) error {
 		req.Header.Add(userKey, username)
 		req.Header.Add(taskKey, taskId)
 		if isAsync(invokeType) {
 			req.Header.Add(FcAsyncKey, "Async")
 		}
 		return nil
-	})
+	}
+	// record webui job of task, cancel only interrupt own job
+	stopProgress := p.startTaskProgress(ctx, endPoint, taskId)
+	resp, err := client.ExtraImages(ctx, *request, editor)
+	stopProgress()
+	if fallback, fallbackClient := controlFallback(c, endPoint, err); fallbackClient != nil {
+		endPoint = fallback
+		stopProgress = p.startTaskProgress(ctx, endPoint, taskId)
+		resp, err = fallbackClient.ExtraImages(ctx, *request, editor)
+		stopProgress()
+	}
+	if stopWatch() {
+		if resp != nil {
+			resp.Body.Close()
+		}
+		p.cancelTask(endPoint, taskId)
+		c.JSON(http.StatusOK, models.SubmitTaskResponse{
+			TaskId:  taskId,
+			Status:  config.TASK_CANCELLED,
+			Message: utils.String("task cancelled"),
+		})
+		return
+	}
 	if err != nil || (resp.StatusCode != syncSuccessCode && resp.StatusCode != asyncSuccessCode) {
 		handleRespError(c, err, resp, taskId)
 	} else {
+		status, invokeMode := submitResult(resp.StatusCode)
 		c.JSON(http.StatusOK, models.SubmitTaskResponse{
-			TaskId: taskId,
-			Status: func() string {
-				if resp.StatusCode == syncSuccessCode {
-					return config.TASK_FINISH
-				}
-				if resp.StatusCode == asyncSuccessCode {
-					return config.TASK_QUEUE
-				}
-				return config.TASK_FAILED
-			}(),
-			OssUrl: extraOssUrl(resp),
+			TaskId:     taskId,
+			Status:     status,
+			InvokeMode: invokeMode,
+			OssUrl:     extraOssUrl(resp),
 		})
 	}
 }
 
-// Txt2Img txt to img predict
-// (POST /txt2img)
-func (p *ProxyHandler) Txt2Img(c *gin.Context) {
+// forwardExtraBatchImages control route batch upscale to sd function, relay its response
+func (p *ProxyHandler) forwardExtraBatchImages(c *gin.Context, username string,
+	request *models.ExtraBatchImagesRequest) {
+	if request.ForceTaskId == nil || *request.ForceTaskId == "" {
+		// init taskId
+		request.ForceTaskId = utils.String(utils.RandStr(taskIdLength))
+	}
+	taskId := *request.ForceTaskId
+	c.Writer.Header().Set("taskId", taskId)
+	sdModel := ""
+	if request.StableDiffusionModel != nil {
+		sdModel = *request.StableDiffusionModel
+	}
+	endPoint, err := getStickyEndpoint(c, sdModel, true)
+	if err != nil {
+		handleEndpointError(c, taskId, err)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), config.HTTPTIMEOUT)
+	defer cancel()
+	editor := func(ctx context.Context, req *http.Request) error {
+		req.Header.Add(userKey, username)
+		req.Header.Add(taskKey, taskId)
+		if inline := c.GetHeader(inlineImagesKey); inline != "" {
+			req.Header.Add(inlineImagesKey, inline)
+		}
+		return nil
+	}
+	resp, err := client.ManagerClientGlobal.GetClient(endPoint).ExtraBatchImages(ctx, *request, editor)
+	if _, fallbackClient := controlFallback(c, endPoint, err); fallbackClient != nil {
+		resp, err = fallbackClient.ExtraBatchImages(ctx, *request, editor)
+	}
+	if err != nil {
+		handleRespError(c, err, resp, taskId)
+		return
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		handleRespError(c, err, resp, taskId)
+		return
+	}
+	c.Data(resp.StatusCode, resp.Header.Get("Content-Type"), body)
+}
+
+// ExtraBatchImages batch image upcaling, upload results to oss and record task
+// (POST /extra_batch_images)
+func (p *ProxyHandler) ExtraBatchImages(c *gin.Context) {
 	username := c.GetHeader(userKey)
-	//invokeType := c.GetHeader(requestType)
 	if username == "" {
 		if config.ConfigGlobal.EnableLogin() {
 			handleError(c, http.StatusBadRequest, config.BADREQUEST)
@@ -507,38 +747,71 @@ func (p *ProxyHandler) Txt2Img(c *gin.Context) {
 			username = DEFAULT_USER
 		}
 	}
-	request := new(models.Txt2ImgJSONRequestBody)
+	request := new(models.ExtraBatchImagesJSONRequestBody)
 	if err := getBindResult(c, request); err != nil {
 		handleError(c, http.StatusBadRequest, config.BADREQUEST)
 		return
 	}
-	if !checkSdModelValid(request.StableDiffusionModel) {
-		handleError(c, http.StatusBadRequest, "stable_diffusion_model val not valid, please set valid val")
+	if len(request.ImageList) == 0 {
+		handleError(c, http.StatusBadRequest, "image_list not set, please check request")
+		return
+	}
+	if config.ConfigGlobal.GetFlexMode() == config.MultiFunc && config.ConfigGlobal.IsServerTypeMatch(config.CONTROL) {
+		// upscale on sd function instance, its proxy record task and upload results
+		p.forwardExtraBatchImages(c, username, request)
+		return
+	}
+	// labels stored in task, not forward
+	labels, err := taskLabelsVal(request.Labels)
+	if err != nil {
+		handleError(c, http.StatusBadRequest, err.Error())
 		return
 	}
+	request.Labels = nil
+	outputPrefix, code, err := resolveOutputPrefix(username, request.OutputPrefix)
+	if err != nil {
+		handleError(c, code, err.Error())
+		return
+	}
+	// proxy only, not forward to webui
+	request.OutputPrefix = nil
 
 	// taskId
-	taskId := request.ForceTaskId
-	if taskId == "" {
+	taskId := ""
+	if request.ForceTaskId != nil {
+		taskId = *request.ForceTaskId
+	}
+	forced := taskId != ""
+	if !forced {
 		// init taskId
 		taskId = utils.RandStr(taskIdLength)
-		request.ForceTaskId = taskId
+		request.ForceTaskId = utils.String(taskId)
 	}
 	c.Writer.Header().Set("taskId", taskId)
 	if config.ConfigGlobal.IsServerTypeMatch(config.PROXY) {
-		// check request valid: sdModel and sdVae exist
-		if existed := p.checkModelExist(request.StableDiffusionModel); !existed {
-			handleError(c, http.StatusNotFound, "model not found, please check request")
+		if forced {
+			if code, err := p.checkForceTaskId(username, taskId); err != nil {
+				handleError(c, code, err.Error())
+				return
+			}
+		}
+		leaveUser, rejected := p.enterUserTasks(c, username, taskId, 1)
+		if rejected {
 			return
 		}
+		defer leaveUser()
 		// write db
-		if err := p.taskStore.Put(taskId, map[string]interface{}{
+		if code, err := p.putTask(username, taskId, forced, map[string]interface{}{
 			datastore.KTaskIdColumnName: taskId,
 			datastore.KTaskUser:         username,
 			datastore.KTaskStatus:       config.TASK_QUEUE,
 			datastore.KTaskCancel:       int64(config.CANCEL_INIT),
 			datastore.KTaskCreateTime:   fmt.Sprintf("%d", utils.TimestampS()),
-		}); err != nil {
+			datastore.KTaskLabels:       labels,
+		}); code == http.StatusConflict {
+			handleError(c, code, err.Error())
+			return
+		} else if err != nil {
 			logrus.WithFields(logrus.Fields{"taskId": taskId}).Errorf("put db err=%s", err.Error())
 			c.JSON(http.StatusInternalServerError, models.SubmitTaskResponse{
 				TaskId:  taskId,
@@ -547,6 +820,8 @@ func (p *ProxyHandler) Txt2Img(c *gin.Context) {
 			})
 			return
 		}
+		// task counted by row until terminal
+		leaveUser()
 	}
 
 	// preprocess request ossPath image to base64
@@ -557,26 +832,10 @@ func (p *ProxyHandler) Txt2Img(c *gin.Context) {
 			datastore.KTaskCode:       int64(requestFail),
 			datastore.KTaskModifyTime: fmt.Sprintf("%d", utils.TimestampS()),
 		})
-		handleError(c, http.StatusBadRequest, err.Error())
-		return
-	}
-
-	// update request OverrideSettings
-	if request.OverrideSettings == nil {
-		overrideSettings := make(map[string]interface{})
-		request.OverrideSettings = &overrideSettings
-	}
-	configVer := c.GetHeader(versionKey)
-	if err := p.updateOverrideSettingsRequest(request.OverrideSettings, username, configVer,
-		request.StableDiffusionModel, request.SdVae); err != nil {
-		logrus.WithFields(logrus.Fields{"taskId": taskId}).Errorf("update OverrideSettings err=%s", err.Error())
-		handleError(c, http.StatusInternalServerError, "please check config")
+		handleError(c, preprocessErrorCode(err), err.Error())
 		return
 	}
 
-	// default OverrideSettingsRestoreAfterwards = true
-	request.OverrideSettingsRestoreAfterwards = utils.Bool(false)
-
 	body, err := json.Marshal(request)
 	if err != nil {
 		logrus.WithFields(logrus.Fields{"taskId": taskId}).Errorln("request to json err=", err.Error())
@@ -584,104 +843,1073 @@ func (p *ProxyHandler) Txt2Img(c *gin.Context) {
 		return
 	}
 
-	// predict task
-	images, err := p.predictTask(username, taskId, config.TXT2IMG, body)
+	// batch extras result images like txt2img, one output per input image
+	images, dataUris, err := p.predictTask(username, taskId, config.EXTRABATCHIMAGES, body, predictOptions{
+		outputPrefix:   outputPrefix,
+		inlineMaxBytes: inlineImageMaxBytes(c),
+	})
+	if errors.Is(err, errPartialUpload) {
+		c.JSON(http.StatusOK, partialUploadResponse(taskId, images, err))
+		return
+	}
 	if err != nil {
-		//logrus.WithFields(logrus.Fields{"taskId": taskId}).Errorln(err.Error())
-		c.JSON(http.StatusInternalServerError, models.SubmitTaskResponse{
+		c.JSON(predictErrorCode(err), models.SubmitTaskResponse{
 			TaskId:  taskId,
-			Status:  config.TASK_FAILED,
-			Message: utils.String(""),
+			Status:  predictErrorStatus(err),
+			Message: utils.String(err.Error()),
+		})
+		return
+	}
+	// small images inline, client not need oss access
+	if dataUris != nil {
+		c.JSON(http.StatusOK, models.SubmitTaskResponse{
+			TaskId:     taskId,
+			Status:     config.TASK_FINISH,
+			Images:     &dataUris,
+			InvokeMode: utils.String(invokeModeSync),
 		})
 		return
 	}
 	if ossUrl, err := module.OssGlobal.GetUrl(images); err != nil {
-		logrus.Error("get oss url error")
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"message": "get oss url error",
+		logrus.WithFields(logrus.Fields{"taskId": taskId}).Warnf("get oss url err=%s", err.Error())
+		c.JSON(http.StatusOK, models.SubmitTaskResponse{
+			TaskId:     taskId,
+			Status:     config.TASK_FINISH,
+			OssUrl:     &images,
+			InvokeMode: utils.String(invokeModeSync),
+			Message:    utils.String("get oss url fail, return oss path, please get url by task result later"),
 		})
 	} else {
 		c.JSON(http.StatusOK, models.SubmitTaskResponse{
-			TaskId: taskId,
-			Status: config.TASK_FINISH,
-			OssUrl: &ossUrl,
+			TaskId:     taskId,
+			Status:     config.TASK_FINISH,
+			OssUrl:     &ossUrl,
+			InvokeMode: utils.String(invokeModeSync),
 		})
 	}
 }
 
-func (p *ProxyHandler) predictTask(user, taskId, path string, body []byte) ([]string, error) {
-	url := fmt.Sprintf("%s%s", config.ConfigGlobal.SdUrlPrefix, path)
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(body))
-	if err != nil {
-		return nil, err
+// Interrogate image to prompt, sync and not record task
+// (POST /interrogate)
+func (p *ProxyHandler) Interrogate(c *gin.Context) {
+	request := new(models.InterrogateJSONRequestBody)
+	if err := getBindResult(c, request); err != nil {
+		handleError(c, http.StatusBadRequest, config.BADREQUEST)
+		return
+	}
+	if request.Image == "" {
+		handleError(c, http.StatusBadRequest, "image not set, please check request")
+		return
+	}
+	var resp *http.Response
+	if config.ConfigGlobal.GetFlexMode() == config.MultiFunc && config.ConfigGlobal.IsServerTypeMatch(config.CONTROL) {
+		// interrogate on sd function instance, agent preprocess image itself
+		endPoint, err := getSdEndpoint("", true)
+		if err != nil {
+			code := http.StatusInternalServerError
+			if errors.Is(err, module.ErrColdStartBudget) {
+				code = http.StatusTooManyRequests
+			}
+			handleError(c, code, err.Error())
+			return
+		}
+		resp, err = client.ManagerClientGlobal.GetClient(endPoint).Interrogate(c.Request.Context(), *request,
+			func(ctx context.Context, req *http.Request) error {
+				req.Header.Add(userKey, c.GetHeader(userKey))
+				return nil
+			})
+		if err != nil {
+			logrus.Errorf("interrogate endpoint %s err=%s", endPoint, err.Error())
+			handleError(c, http.StatusInternalServerError, config.INTERNALERROR)
+			return
+		}
+	} else {
+		// preprocess request ossPath image to base64
+		if err := preprocessRequest(request); err != nil {
+			handleError(c, preprocessErrorCode(err), err.Error())
+			return
+		}
+		if request.Model == nil || *request.Model == "" {
+			request.Model = utils.String("clip")
+		}
+		body, err := json.Marshal(request)
+		if err != nil {
+			handleError(c, http.StatusBadRequest, config.BADREQUEST)
+			return
+		}
+		url := fmt.Sprintf("%s%s", config.ConfigGlobal.SdUrlPrefix, config.INTERROGATE)
+		if resp, err = p.httpClient.Post(url, "application/json", bytes.NewBuffer(body)); err != nil {
+			logrus.Errorf("interrogate err=%s", err.Error())
+			handleError(c, http.StatusInternalServerError, config.INTERNALERROR)
+			return
+		}
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil || resp.StatusCode != requestOk {
+		handleError(c, http.StatusInternalServerError, fmt.Sprintf("interrogate fail: %s", string(body)))
+		return
 	}
+	result := new(models.InterrogateResult)
+	if err := json.Unmarshal(body, result); err != nil {
+		handleError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
 
-	resp, err := p.httpClient.Do(req)
+// GetStats get server stats
+// (GET /admin/stats)
+func (p *ProxyHandler) GetStats(c *gin.Context) {
+	stats := models.Stats{}
+	if module.FuncManagerGlobal != nil {
+		budget := module.FuncManagerGlobal.ColdStartBudget()
+		stats.ColdStartBudget = &models.ColdStartBudget{
+			Capacity:      budget.Capacity,
+			Available:     budget.Available,
+			WindowSeconds: int(budget.Window.Seconds()),
+		}
+	}
+	if config.ConfigGlobal.IsImageCompression() {
+		compression := imageCompressionStatus()
+		stats.ImageCompression = &compression
+	}
+	if ossDownloadLimit != nil {
+		downloads := ossDownloadLimit.status()
+		stats.OssDownloads = &downloads
+	}
+	if p.taskQueue != nil {
+		queue := p.taskQueue.status()
+		stats.Queue = &queue
+	}
+	if p.warmPool != nil && len(config.ConfigGlobal.WarmPool) > 0 {
+		warmPool := p.warmPool.status()
+		stats.WarmPool = &warmPool
+	}
+	if config.ConfigGlobal.BreakerThreshold > 0 {
+		breakers := make([]models.CircuitBreaker, 0)
+		for _, status := range client.ManagerClientGlobal.BreakerStatus() {
+			breaker := models.CircuitBreaker{Endpoint: status.Endpoint, State: status.State,
+				Failures: status.Failures}
+			if !status.OpenUntil.IsZero() {
+				breaker.OpenUntil = utils.Int64(status.OpenUntil.Unix())
+			}
+			breakers = append(breakers, breaker)
+		}
+		stats.CircuitBreakers = &breakers
+	}
+	c.JSON(http.StatusOK, stats)
+}
+
+// GetMaintenance get maintenance mode status
+// (GET /admin/maintenance)
+func (p *ProxyHandler) GetMaintenance(c *gin.Context) {
+	c.JSON(http.StatusOK, p.maintenance.status())
+}
+
+// SetMaintenance enable or disable maintenance mode
+// (POST /admin/maintenance)
+func (p *ProxyHandler) SetMaintenance(c *gin.Context) {
+	request := new(models.SetMaintenanceJSONRequestBody)
+	if err := getBindResult(c, request); err != nil {
+		handleError(c, http.StatusBadRequest, config.BADREQUEST)
+		return
+	}
+	duration := config.ConfigGlobal.MaintenanceMaxDuration
+	if request.DurationSeconds != nil {
+		duration = *request.DurationSeconds
+	}
+	if duration < 0 {
+		handleError(c, http.StatusBadRequest, "durationSeconds should >= 0")
+		return
+	}
+	if _, err := p.maintenance.set(request.Enabled, duration); err != nil {
+		logrus.Errorf("set maintenance err=%s", err.Error())
+		handleError(c, http.StatusInternalServerError, "update db error")
+		return
+	}
+	logrus.Infof("maintenance mode enabled=%v duration=%d", request.Enabled, duration)
+	c.JSON(http.StatusOK, p.maintenance.status())
+}
+
+// GetUsage gpu seconds usage per user
+// (GET /admin/usage)
+func (p *ProxyHandler) GetUsage(c *gin.Context, params models.GetUsageParams) {
+	datas, err := p.taskStore.ListAll([]string{datastore.KTaskIdColumnName, datastore.KTaskUser,
+		datastore.KTaskGpuSeconds})
 	if err != nil {
-		return nil, err
+		handleError(c, http.StatusInternalServerError, "read task from db error")
+		return
+	}
+	user := ""
+	if params.User != nil {
+		user = *params.User
 	}
+	c.JSON(http.StatusOK, aggregateUsage(datas, user))
+}
 
-	body, err = io.ReadAll(resp.Body)
-	defer resp.Body.Close()
+// TailSdLogs tail recent sd webui logs
+// (GET /admin/logs/sd)
+func (p *ProxyHandler) TailSdLogs(c *gin.Context, params models.TailSdLogsParams) {
+	if module.SDManageObj == nil {
+		handleError(c, http.StatusNotFound, "sd not running on this server")
+		return
+	}
+	tail := defaultLogTail
+	if params.Tail != nil {
+		tail = *params.Tail
+	}
+	if tail <= 0 || tail > maxLogTail {
+		handleError(c, http.StatusBadRequest, fmt.Sprintf("tail should between 1 and %d", maxLogTail))
+		return
+	}
+	c.JSON(http.StatusOK, models.SdLogs{Lines: module.SDManageObj.TailLogs(tail)})
+}
+
+// GetModelDefaults get model default params
+// (GET /admin/models/{model_name}/defaults)
+func (p *ProxyHandler) GetModelDefaults(c *gin.Context, modelName string) {
+	defaults := p.getModelDefaults(modelName)
+	if defaults == nil {
+		defaults = make(map[string]interface{})
+	}
+	c.JSON(http.StatusOK, models.ModelDefaults{Defaults: defaults})
+}
+
+// UpdateModelDefaults update model default params, effective without redeploy
+// (PUT /admin/models/{model_name}/defaults)
+func (p *ProxyHandler) UpdateModelDefaults(c *gin.Context, modelName string) {
+	request := new(models.UpdateModelDefaultsJSONRequestBody)
+	if err := getBindResult(c, request); err != nil {
+		handleError(c, http.StatusBadRequest, config.BADREQUEST)
+		return
+	}
+	val, err := json.Marshal(request.Defaults)
 	if err != nil {
-		return nil, err
+		handleError(c, http.StatusBadRequest, config.BADREQUEST)
+		return
 	}
-	var result *models.Txt2ImgResult
+	if err := p.configStore.Put(modelDefaultsKey(modelName), map[string]interface{}{
+		datastore.KConfigVal:        string(val),
+		datastore.KConfigModifyTime: fmt.Sprintf("%d", utils.TimestampS()),
+	}); err != nil {
+		handleError(c, http.StatusInternalServerError, "update db error")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "success"})
+}
 
-	if err := json.Unmarshal(body, &result); err != nil {
-		//logrus.WithFields(logrus.Fields{"taskId": taskId}).Errorln(err.Error())
-		return nil, err
+// GetVersion get build version and server mode
+// (GET /version)
+func (p *ProxyHandler) GetVersion(c *gin.Context) {
+	c.JSON(http.StatusOK, models.VersionInfo{
+		Version:      config.Version,
+		Commit:       config.Commit,
+		BuildTime:    config.BuildTime,
+		ServerName:   config.ConfigGlobal.ServerName,
+		FlexMode:     config.ConfigGlobal.FlexMode,
+		OssMode:      config.ConfigGlobal.OssMode,
+		LoginEnabled: config.ConfigGlobal.EnableLogin(),
+	})
+}
+
+// sdEnvKeys process env shown by GetEnv, credentials never listed
+var sdEnvKeys = []string{config.MODEL_SD, config.SD_START_PARAMS, config.MODEL_REFRESH_SIGNAL, config.SERVER_NAME,
+	config.FLEX_MODE, config.DOWNSTREAM, config.USER_LOCAL_MODEL, config.SD_IMAGE, config.GPU_MEMORY_SIZE,
+	config.OSS_MODE, config.OSS_PATH, config.OSS_ENDPOINT, config.OSS_BUCKET, config.OSS_MODEL_BUCKET,
+	config.OSS_OUTPUT_BUCKET, config.OTS_ENDPOINT, config.OTS_INSTANCE, config.FC_FUNCTION_NAME,
+	config.DEFAULT_MODEL, config.CHECK_MODEL_LOAD, config.DISABLE_PROGRESS}
+
+// GetEnv get effective sd env of current instance
+// (GET /admin/env)
+func (p *ProxyHandler) GetEnv(c *gin.Context) {
+	env := make(map[string]string)
+	for _, key := range sdEnvKeys {
+		if val, ok := os.LookupEnv(key); ok {
+			env[key] = val
+		}
 	}
-	if result == nil {
+	if extraArgs, ok := env[config.SD_START_PARAMS]; ok {
+		env[config.SD_START_PARAMS] = maskAuthArgs(extraArgs)
+	}
+	c.JSON(http.StatusOK, models.EnvInfo{
+		Env:        env,
+		SdPath:     config.ConfigGlobal.SdPath,
+		SdPort:     config.ConfigGlobal.GetSDPort(),
+		ServerName: config.ConfigGlobal.ServerName,
+		FlexMode:   config.ConfigGlobal.FlexMode,
+	})
+}
+
+// maskAuthArgs mask value of auth flags like --api-auth user:pass in sd args
+func maskAuthArgs(args string) string {
+	fields := strings.Fields(args)
+	for i := 0; i < len(fields); i++ {
+		flag, _, hasValue := strings.Cut(fields[i], "=")
+		if !strings.HasPrefix(flag, "--") || !strings.Contains(flag, "auth") {
+			continue
+		}
+		if hasValue {
+			fields[i] = flag + "=***"
+		} else if i+1 < len(fields) && !strings.HasPrefix(fields[i+1], "--") {
+			i++
+			fields[i] = "***"
+		}
+	}
+	return strings.Join(fields, " ")
+}
+
+// GetCapabilities get sd webui version and capabilities
+// (GET /sdapi/capabilities)
+func (p *ProxyHandler) GetCapabilities(c *gin.Context) {
+	endPoint := config.ConfigGlobal.SdUrlPrefix
+	if config.ConfigGlobal.IsServerTypeMatch(config.CONTROL) {
+		var err error
+		if endPoint, err = getSdEndpoint(c.GetHeader(sdModelKey), true); err != nil {
+			handleError(c, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+	capabilities, err := getCapabilities(p.httpClient, endPoint)
+	if err != nil {
+		logrus.Errorf("get sd capabilities err=%s", err.Error())
+		handleError(c, http.StatusInternalServerError, "get sd capabilities fail")
+		return
+	}
+	c.JSON(http.StatusOK, capabilities)
+}
+
+// SelfTest render canned prompt with fixed seed, compare with stored reference hash
+// (POST /admin/selftest)
+func (p *ProxyHandler) SelfTest(c *gin.Context) {
+	request := new(models.SelfTestJSONRequestBody)
+	if err := getBindResult(c, request); err != nil || request.StableDiffusionModel == "" {
+		handleError(c, http.StatusBadRequest, config.BADREQUEST)
+		return
+	}
+	endPoint := config.ConfigGlobal.SdUrlPrefix
+	if config.ConfigGlobal.IsServerTypeMatch(config.CONTROL) {
+		var err error
+		if endPoint, err = getSdEndpoint(request.StableDiffusionModel, false); err != nil {
+			handleError(c, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+	hash, err := renderSelfTest(p.httpClient, endPoint, request.StableDiffusionModel)
+	if err != nil {
+		logrus.Errorf("selftest model=%s err=%s", request.StableDiffusionModel, err.Error())
+		handleError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	result := models.SelfTestResult{
+		Model: request.StableDiffusionModel,
+		Hash:  hash,
+	}
+	key := selfTestReferenceKey(request.StableDiffusionModel)
+	data, err := p.configStore.Get(key, []string{datastore.KConfigVal})
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, "read selftest reference from db error")
+		return
+	}
+	if data == nil || data[datastore.KConfigVal] == nil || (request.UpdateReference != nil && *request.UpdateReference) {
+		// first run or update, save as reference
+		if err := p.configStore.Put(key, map[string]interface{}{
+			datastore.KConfigVal:        hash,
+			datastore.KConfigModifyTime: fmt.Sprintf("%d", utils.TimestampS()),
+		}); err != nil {
+			handleError(c, http.StatusInternalServerError, "update db error")
+			return
+		}
+		result.Passed = true
+		result.Status = config.SELFTEST_REFERENCE
+		result.ReferenceHash = utils.String(hash)
+		c.JSON(http.StatusOK, result)
+		return
+	}
+	reference := data[datastore.KConfigVal].(string)
+	result.ReferenceHash = utils.String(reference)
+	result.Passed = reference == hash
+	if result.Passed {
+		result.Status = config.SELFTEST_PASSED
+	} else {
+		result.Status = config.SELFTEST_FAILED
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// promptTemplateUser owner of prompt templates, default user only when login disabled
+func promptTemplateUser(c *gin.Context) (string, bool) {
+	username := c.GetHeader(userKey)
+	if username != "" {
+		return username, true
+	}
+	if config.ConfigGlobal.EnableLogin() {
+		handleError(c, http.StatusUnauthorized, "login required")
+		return "", false
+	}
+	return DEFAULT_USER, true
+}
+
+// ListPromptTemplates list user prompt templates
+// (GET /prompt_templates)
+func (p *ProxyHandler) ListPromptTemplates(c *gin.Context) {
+	username, ok := promptTemplateUser(c)
+	if !ok {
+		return
+	}
+	templates, err := p.getPromptTemplates(username)
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, "read prompt templates from db error")
+		return
+	}
+	ret := make([]models.PromptTemplate, 0, len(templates))
+	for name, content := range templates {
+		ret = append(ret, models.PromptTemplate{Name: utils.String(name), Content: content})
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return *ret[i].Name < *ret[j].Name
+	})
+	c.JSON(http.StatusOK, ret)
+}
+
+// GetPromptTemplate get user prompt template
+// (GET /prompt_templates/{template_name})
+func (p *ProxyHandler) GetPromptTemplate(c *gin.Context, templateName string) {
+	username, ok := promptTemplateUser(c)
+	if !ok {
+		return
+	}
+	templates, err := p.getPromptTemplates(username)
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, "read prompt templates from db error")
+		return
+	}
+	content, ok := templates[templateName]
+	if !ok {
+		handleError(c, http.StatusNotFound, config.NOTFOUND)
+		return
+	}
+	c.JSON(http.StatusOK, models.PromptTemplate{Name: utils.String(templateName), Content: content})
+}
+
+// UpdatePromptTemplate create or update user prompt template
+// (PUT /prompt_templates/{template_name})
+func (p *ProxyHandler) UpdatePromptTemplate(c *gin.Context, templateName string) {
+	username, ok := promptTemplateUser(c)
+	if !ok {
+		return
+	}
+	request := new(models.UpdatePromptTemplateJSONRequestBody)
+	if err := getBindResult(c, request); err != nil {
+		handleError(c, http.StatusBadRequest, config.BADREQUEST)
+		return
+	}
+	if !promptTemplateNameRegex.MatchString(templateName) {
+		handleError(c, http.StatusBadRequest, "template name only support letters, digits, _ - .")
+		return
+	}
+	var invalid error
+	if err := p.updatePromptTemplates(username, func(templates map[string]string) error {
+		templates[templateName] = request.Content
+		// check template valid: no cycle and length limit
+		if _, invalid = expandPrompt(request.Content, templates); invalid != nil {
+			return invalid
+		}
+		return nil
+	}); err != nil {
+		if invalid != nil {
+			handleError(c, http.StatusBadRequest, invalid.Error())
+		} else {
+			handleError(c, http.StatusInternalServerError, "update db error")
+		}
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "success"})
+}
+
+// DeletePromptTemplate delete user prompt template
+// (DELETE /prompt_templates/{template_name})
+func (p *ProxyHandler) DeletePromptTemplate(c *gin.Context, templateName string) {
+	username, ok := promptTemplateUser(c)
+	if !ok {
+		return
+	}
+	if err := p.updatePromptTemplates(username, func(templates map[string]string) error {
+		if _, ok := templates[templateName]; !ok {
+			return errPromptTemplateNotFound
+		}
+		delete(templates, templateName)
+		return nil
+	}); err != nil {
+		if errors.Is(err, errPromptTemplateNotFound) {
+			handleError(c, http.StatusNotFound, config.NOTFOUND)
+		} else {
+			handleError(c, http.StatusInternalServerError, "update db error")
+		}
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "delete success"})
+}
+
+// Txt2Img txt to img predict
+// (POST /txt2img)
+func (p *ProxyHandler) Txt2Img(c *gin.Context) {
+	username := c.GetHeader(userKey)
+	//invokeType := c.GetHeader(requestType)
+	if username == "" {
+		if config.ConfigGlobal.EnableLogin() {
+			handleError(c, http.StatusBadRequest, config.BADREQUEST)
+			return
+		} else {
+			username = DEFAULT_USER
+		}
+	}
+	request := new(models.Txt2ImgJSONRequestBody)
+	if err := p.bindWithModelDefaults(c, request); err != nil {
+		handleBindError(c, err)
+		return
+	}
+	// labels stored in task, not forward
+	labels, err := taskLabelsVal(request.Labels)
+	if err != nil {
+		handleError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	request.Labels = nil
+	if err := p.prepareTxt2ImgPrompts(username, request); err != nil {
+		handleError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	// protect shared gpu, before render cache hash
+	clamped, err := applyRequestLimit(username, request.CfgScale, request.Steps, request.HrSecondPassSteps)
+	if err != nil {
+		handleError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	// likely oom fail early instead of crash webui
+	if err := checkGpuMemory(request.StableDiffusionModel, txt2ImgPixels(request), request.BatchSize); err != nil {
+		handleError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if !checkSdModelValid(request.StableDiffusionModel) {
+		handleError(c, http.StatusBadRequest, "stable_diffusion_model val not valid, please set valid val")
+		return
+	}
+	// unknown sampler fail early instead of mid-render
+	if err := validateSamplers(p.httpClient, config.ConfigGlobal.SdUrlPrefix, request.SamplerName,
+		request.SamplerIndex, request.HrSamplerName); err != nil {
+		handleError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	outputPrefix, code, err := resolveOutputPrefix(username, request.OutputPrefix)
+	if err != nil {
+		handleError(c, code, err.Error())
+		return
+	}
+	postProcess, err := normalizePostProcess(request.PostProcess)
+	if err != nil {
+		handleError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// get or create of /txt2img/cached, explicit fixed seed required
+	cachedHash, err := cachedRequestHash(c, config.TXT2IMG, request)
+	if err != nil {
+		handleError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	// taskId
+	taskId := request.ForceTaskId
+	forced := taskId != ""
+	if !forced {
+		// init taskId
+		taskId = utils.RandStr(taskIdLength)
+		request.ForceTaskId = taskId
+	}
+	c.Writer.Header().Set("taskId", taskId)
+	if config.ConfigGlobal.IsServerTypeMatch(config.PROXY) {
+		if forced {
+			if code, err := p.checkForceTaskId(username, taskId); err != nil {
+				handleError(c, code, err.Error())
+				return
+			}
+		}
+		// check request valid: sdModel and sdVae exist
+		if code, err := p.ensureModelExist(request.StableDiffusionModel); err != nil {
+			handleError(c, code, err.Error())
+			return
+		}
+		// identical render finished recently, reuse its images
+		cacheHash := renderCacheHash(c, config.TXT2IMG, username, c.GetHeader(versionKey), request)
+		if cacheHash != "" && p.replyRenderCache(c, username, taskId, cacheHash, labels) {
+			return
+		}
+		if cachedHash != "" && p.replyCachedTask(c, username, cachedHash) {
+			return
+		}
+		leaveUser, rejected := p.enterUserTasks(c, username, taskId, 1)
+		if rejected {
+			return
+		}
+		defer leaveUser()
+		// doomed work rejected instead of queued
+		leave, err := p.taskQueue.enter(request.StableDiffusionModel, 1)
+		if err != nil {
+			handleQueueFull(c, taskId)
+			return
+		}
+		defer leave()
+		// write db
+		if code, err := p.putTask(username, taskId, forced, map[string]interface{}{
+			datastore.KTaskIdColumnName: taskId,
+			datastore.KTaskUser:         username,
+			datastore.KTaskStatus:       config.TASK_QUEUE,
+			datastore.KTaskCancel:       int64(config.CANCEL_INIT),
+			datastore.KTaskCreateTime:   fmt.Sprintf("%d", utils.TimestampS()),
+			datastore.KTaskModel:        request.StableDiffusionModel,
+			datastore.KTaskLabels:       labels,
+			datastore.KTaskRequest:      taskRequestVal(txt2ImgPath, request),
+		}); code == http.StatusConflict {
+			handleError(c, code, err.Error())
+			return
+		} else if err != nil {
+			logrus.WithFields(logrus.Fields{"taskId": taskId}).Errorf("put db err=%s", err.Error())
+			c.JSON(http.StatusInternalServerError, models.SubmitTaskResponse{
+				TaskId:  taskId,
+				Status:  config.TASK_FAILED,
+				Message: utils.String(config.OTSPUTERROR),
+			})
+			return
+		}
+		// task counted by row until terminal
+		leave()
+		leaveUser()
+		if cacheHash != "" {
+			p.putRenderCache(cacheHash, taskId)
+		}
+		if cachedHash != "" {
+			p.putCachedTask(username, cachedHash, taskId,
+				cachedTaskModels(request.StableDiffusionModel, request.SdVae))
+		}
+	}
+
+	body, code, err := p.txt2ImgBody(username, taskId, c.GetHeader(versionKey), request)
+	if err != nil {
+		handleError(c, code, err.Error())
+		return
+	}
+
+	// predict task
+	images, dataUris, err := p.predictTask(username, taskId, config.TXT2IMG, body, predictOptions{
+		outputPrefix:   outputPrefix,
+		inlineMaxBytes: inlineImageMaxBytes(c),
+		postProcess:    postProcess,
+		sdModel:        request.StableDiffusionModel,
+	})
+	if errors.Is(err, errPartialUpload) {
+		// uploaded images returned, failed ones not
+		response := partialUploadResponse(taskId, images, err)
+		response.Clamped = clampedNotes(clamped)
+		c.JSON(http.StatusOK, response)
+		return
+	}
+	if err != nil {
+		//logrus.WithFields(logrus.Fields{"taskId": taskId}).Errorln(err.Error())
+		message := ""
+		if errors.Is(err, errPredictQueueTimeout) || errors.Is(err, errModelRemoved) || errors.Is(err, errNoOutput) {
+			message = err.Error()
+		}
+		c.JSON(predictErrorCode(err), models.SubmitTaskResponse{
+			TaskId:  taskId,
+			Status:  predictErrorStatus(err),
+			Message: utils.String(message),
+		})
+		return
+	}
+	// small images inline, client not need oss access
+	if dataUris != nil {
+		c.JSON(http.StatusOK, models.SubmitTaskResponse{
+			TaskId:     taskId,
+			Status:     config.TASK_FINISH,
+			Images:     &dataUris,
+			InvokeMode: utils.String(invokeModeSync),
+			Clamped:    clampedNotes(clamped),
+		})
+		return
+	}
+	if ossUrl, err := module.OssGlobal.GetUrl(images); err != nil {
+		// images already in oss, not discard success task, return raw oss path
+		// client can get url later by GetTaskResult
+		logrus.WithFields(logrus.Fields{"taskId": taskId}).Warnf("get oss url err=%s", err.Error())
+		c.JSON(http.StatusOK, models.SubmitTaskResponse{
+			TaskId:     taskId,
+			Status:     config.TASK_FINISH,
+			OssUrl:     &images,
+			InvokeMode: utils.String(invokeModeSync),
+			Message:    utils.String("get oss url fail, return oss path, please get url by task result later"),
+			Clamped:    clampedNotes(clamped),
+		})
+	} else {
+		c.JSON(http.StatusOK, models.SubmitTaskResponse{
+			TaskId:     taskId,
+			Status:     config.TASK_FINISH,
+			OssUrl:     &ossUrl,
+			InvokeMode: utils.String(invokeModeSync),
+			Clamped:    clampedNotes(clamped),
+		})
+	}
+}
+
+// prepareTxt2ImgPrompts expand prompt templates, add default negative embeddings and validate prompts
+func (p *ProxyHandler) prepareTxt2ImgPrompts(username string, request *models.Txt2ImgRequest) error {
+	// expand prompt templates
+	if err := p.expandRequestPrompt(username, request.Prompt, request.NegativePrompt); err != nil {
+		return err
+	}
+	p.injectNegativeEmbeddings(username, &request.NegativePrompt, request.DisableDefaultNegative)
+	// proxy only, not forward
+	request.DisableDefaultNegative = nil
+	return validateRequestPrompts(request.Prompt, request.NegativePrompt)
+}
+
+// txt2ImgBody webui request body of created task, merge user config of configVer to override settings,
+// return error code and message on failure
+func (p *ProxyHandler) txt2ImgBody(username, taskId, configVer string, request *models.Txt2ImgRequest) ([]byte,
+	int, error) {
+	// preprocess request ossPath image to base64
+	if err := preprocessRequest(request); err != nil {
+		// update task status
+		p.taskStore.Update(taskId, map[string]interface{}{
+			datastore.KTaskStatus:     config.TASK_FAILED,
+			datastore.KTaskCode:       int64(requestFail),
+			datastore.KTaskModifyTime: fmt.Sprintf("%d", utils.TimestampS()),
+		})
+		return nil, preprocessErrorCode(err), err
+	}
+
+	// update request OverrideSettings
+	if request.OverrideSettings == nil {
+		overrideSettings := make(map[string]interface{})
+		request.OverrideSettings = &overrideSettings
+	}
+	if err := p.updateOverrideSettingsRequest(request.OverrideSettings, username, configVer,
+		request.StableDiffusionModel, request.SdVae); err != nil {
+		logrus.WithFields(logrus.Fields{"taskId": taskId}).Errorf("update OverrideSettings err=%s", err.Error())
+		if errors.Is(err, errUnknownOverrideSetting) {
+			return nil, http.StatusBadRequest, p.predictFail(taskId, err, 0)
+		}
+		return nil, http.StatusInternalServerError, p.predictFail(taskId, errors.New("please check config"), 0)
+	}
+
+	// record effective settings, task result show it
+	if settings, err := json.Marshal(stripSecretSettings(*request.OverrideSettings)); err == nil {
 		if err := p.taskStore.Update(taskId, map[string]interface{}{
+			datastore.KTaskEffectiveSettings: string(settings),
+		}); err != nil {
+			logrus.WithFields(logrus.Fields{"taskId": taskId}).Warnf("update effective settings err=%s", err.Error())
+		}
+	}
+
+	// default OverrideSettingsRestoreAfterwards = true
+	request.OverrideSettingsRestoreAfterwards = utils.Bool(false)
+	// proxy only, not forward to webui, render cache hash already include it
+	request.OutputPrefix = nil
+	request.PostProcess = nil
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"taskId": taskId}).Errorln("request to json err=", err.Error())
+		return nil, http.StatusBadRequest, errors.New(config.BADREQUEST)
+	}
+	return body, http.StatusOK, nil
+}
+
+// predictOptions output options of predictTask
+type predictOptions struct {
+	// oss key prefix before imageNameTemplate, empty keep default layout
+	outputPrefix string
+	// return data uris when > 0 and images small enough
+	inlineMaxBytes int64
+	// extras pass on rendered images before upload, nil skip
+	postProcess *models.PostProcess
+	// sd model of task, checked again after waiting predict slot, empty skip
+	sdModel string
+}
+
+// predictTask return oss keys of images, and data uris when inline requested
+func (p *ProxyHandler) predictTask(user, taskId, path string, body []byte, opts predictOptions) ([]string,
+	[]string, error) {
+	url := fmt.Sprintf("%s%s", config.ConfigGlobal.SdUrlPrefix, path)
+	// single sd instance not overload, slot hold until response read
+	release, err := p.predictLimit.acquire()
+	if err != nil {
+		return nil, nil, p.predictFail(taskId, err, 0)
+	}
+	// model may deleted while task queued
+	if opts.sdModel != "" {
+		if body, _, err = p.resolveRemovedModel(taskId, opts.sdModel, body); err != nil {
+			release()
+			if !errors.Is(err, errModelRemoved) {
+				err = p.predictFail(taskId, err, 0)
+			}
+			return nil, nil, err
+		}
+	}
+	// wedged webui not hang task forever
+	ctx, cancel := context.WithTimeout(context.Background(), config.ConfigGlobal.GetPredictTimeout())
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(body))
+	if err != nil {
+		release()
+		return nil, nil, p.predictFail(taskId, err, 0)
+	}
+
+	// gpu time only cover sd predict call
+	predictStart := time.Now()
+	// stopped before task status written, deferred stop only guard unexpected return
+	stopProgress := p.startTaskProgress(ctx, config.ConfigGlobal.SdUrlPrefix, taskId)
+	defer stopProgress()
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		stopProgress()
+		release()
+		return nil, nil, p.predictFail(taskId, err, time.Since(predictStart).Seconds())
+	}
+
+	body, err = io.ReadAll(resp.Body)
+	resp.Body.Close()
+	stopProgress()
+	release()
+	gpuSeconds := time.Since(predictStart).Seconds()
+	if err != nil {
+		return nil, nil, p.predictFail(taskId, err, gpuSeconds)
+	}
+	var result *models.Txt2ImgResult
+
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, nil, p.predictFail(taskId, fmt.Errorf("predict response status=%d invalid, err=%w",
+			resp.StatusCode, err), gpuSeconds)
+	}
+	if result == nil {
+		if _, err := p.updateTaskStatus(taskId, map[string]interface{}{
 			datastore.KTaskCode:       int64(resp.StatusCode),
 			datastore.KTaskStatus:     config.TASK_FAILED,
 			datastore.KTaskInfo:       string(body),
+			datastore.KTaskGpuSeconds: gpuSeconds,
 			datastore.KTaskModifyTime: fmt.Sprintf("%d", utils.TimestampS()),
 		}); err != nil {
 			logrus.WithFields(logrus.Fields{"taskId": taskId}).Println(err.Error())
-			return nil, err
+			return nil, nil, err
+		}
+		return nil, nil, errors.New("predict fail")
+	}
+	if result.Parameters != nil {
+		result.Parameters["alwayson_scripts"] = ""
+	}
+	params, err := json.Marshal(result.Parameters)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"taskId": taskId}).Println("json:", err.Error())
+	}
+	var images, dataUris []string
+	var status, failedImages string
+	var errMeg error
+	if resp.StatusCode == requestOk {
+		if opts.postProcess != nil {
+			postStart := time.Now()
+			processed, err := p.postProcessImages(ctx, result.Images, opts.postProcess)
+			gpuSeconds += time.Since(postStart).Seconds()
+			if err != nil {
+				return nil, nil, p.predictFail(taskId, fmt.Errorf("post process err=%w", err), gpuSeconds)
+			}
+			result.Images = processed
 		}
-		return nil, errors.New("predict fail")
+		count := len(result.Images)
+		images = make([]string, 0, count)
+		seeds := infoSeeds(result.Info)
+		now := time.Now()
+		for i := 1; i <= count; i++ {
+			ossKey := imageOssKey(user, taskId, i, seeds, now)
+			if opts.outputPrefix != "" {
+				ossKey = opts.outputPrefix + "/" + ossKey
+			}
+			images = append(images, ossKey)
+		}
+		// before upload release images
+		dataUris = imageDataUris(result.Images, opts.inlineMaxBytes)
+		// upload image to oss
+		failed, err := uploadImagesConcurrently(images, result.Images, func(uploaded int) {
+			if uploaded >= count {
+				return
+			}
+			// update uploaded images progressively, task result return partial images
+			if _, err := p.updateTaskStatus(taskId, map[string]interface{}{
+				datastore.KTaskStatus:     config.TASK_INPROGRESS,
+				datastore.KTaskImage:      strings.Join(images[:uploaded], ","),
+				datastore.KTaskModifyTime: fmt.Sprintf("%d", utils.TimestampS()),
+			}); err != nil {
+				logrus.WithFields(logrus.Fields{"taskId": taskId}).Warnf("update partial images err=%s",
+					err.Error())
+			}
+		})
+		if err != nil && len(failed) == count {
+			return nil, nil, p.predictFail(taskId, fmt.Errorf("output image err=%s", err.Error()), gpuSeconds)
+		}
+		status = config.TASK_FINISH
+		if len(failed) > 0 {
+			// salvage uploaded images instead of losing whole batch
+			logrus.WithFields(logrus.Fields{"taskId": taskId}).Warnf("images %v upload fail, err=%s", failed,
+				err.Error())
+			images = uploadedImages(images, failed)
+			status = config.TASK_PARTIAL_UPLOAD
+			errMeg = partialUploadError(failed, err)
+			failedImages = failedImagesVal(failed)
+		}
+		if count == 0 {
+			// nsfw filter or script error, distinct status tell client why result empty
+			status = config.TASK_NO_OUTPUT
+			errMeg = noOutputError(result.Info)
+		}
+	} else {
+		status = config.TASK_FAILED
+		errMeg = fmt.Errorf("predict error, status code=%d", resp.StatusCode)
+		// webui error detail instead of empty info
+		result.Info = string(body)
+	}
+	data := map[string]interface{}{
+		datastore.KTaskCode:       int64(resp.StatusCode),
+		datastore.KTaskStatus:     status,
+		datastore.KTaskImage:      strings.Join(images, ","),
+		datastore.KTaskParams:     string(params),
+		datastore.KTaskInfo:       result.Info,
+		datastore.KTaskGpuSeconds: gpuSeconds,
+		datastore.KTaskModifyTime: fmt.Sprintf("%d", utils.TimestampS()),
+	}
+	if failedImages != "" {
+		data[datastore.KTaskFailedImages] = failedImages
+	}
+	// correlate task with webui logs
+	if jobId := webuiJobId(result.Info, resp.Header); jobId != "" {
+		data[datastore.KTaskWebuiJobId] = jobId
+	}
+	if updated, err := p.updateTaskStatus(taskId, data); err != nil {
+		logrus.WithFields(logrus.Fields{"taskId": taskId}).Errorln(err.Error())
+		return nil, nil, err
+	} else if !updated {
+		logrus.WithFields(logrus.Fields{"taskId": taskId}).Info("task already terminal, result not saved")
+	}
+	return images, dataUris, errMeg
+}
+
+// updateTaskStatus update task only if status not changed since read,
+// task already terminal (e.g. cancelled) not overwritten and return false
+func (p *ProxyHandler) updateTaskStatus(taskId string, values map[string]interface{}) (bool, error) {
+	for i := 0; i < maxCasRetries; i++ {
+		data, err := p.taskStore.Get(taskId, []string{datastore.KTaskStatus})
+		if err != nil {
+			return false, err
+		}
+		status, ok := data[datastore.KTaskStatus].(string)
+		if !ok || module.IsTaskTerminal(status) {
+			return false, nil
+		}
+		swapped, err := p.taskStore.CompareAndSwap(taskId, datastore.KTaskStatus, status, values)
+		if err != nil || swapped {
+			return swapped, err
+		}
+	}
+	return false, errCasConflict
+}
+
+// checkForceTaskId forced task id may reuse only terminal task of same user, otherwise conflict
+func (p *ProxyHandler) checkForceTaskId(username, taskId string) (int, error) {
+	data, err := p.taskStore.Get(taskId, []string{datastore.KTaskUser, datastore.KTaskStatus})
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"taskId": taskId}).Errorf("get task err=%s", err.Error())
+		return http.StatusInternalServerError, errors.New("read task from db error")
+	}
+	return taskReuseConflict(username, taskId, data)
+}
+
+// taskReuseConflict existing task of id reusable only when terminal and of same user
+func taskReuseConflict(username, taskId string, data map[string]interface{}) (int, error) {
+	if len(data) == 0 {
+		return http.StatusOK, nil
+	}
+	if data[datastore.KTaskUser] != username {
+		return http.StatusConflict, fmt.Errorf("taskId %s already used", taskId)
+	}
+	if status, _ := data[datastore.KTaskStatus].(string); !module.IsTaskTerminal(status) {
+		return http.StatusConflict, fmt.Errorf("taskId %s still %s", taskId, status)
+	}
+	return http.StatusOK, nil
+}
+
+// putTask write new task, forced task id claimed atomically: absent id put if absent, terminal task of same user
+// swapped back to queue before overwritten, so only one of concurrent reuses of same id succeed.
+// return http status code with error, 409 for conflict and 500 for db error
+func (p *ProxyHandler) putTask(username, taskId string, forced bool, values map[string]interface{}) (int, error) {
+	if !forced {
+		if err := p.taskStore.Put(taskId, values); err != nil {
+			return http.StatusInternalServerError, err
+		}
+		return http.StatusOK, nil
+	}
+	ok, err := p.taskStore.PutIfAbsent(taskId, values)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	if ok {
+		return http.StatusOK, nil
+	}
+	data, err := p.taskStore.Get(taskId, []string{datastore.KTaskUser, datastore.KTaskStatus})
+	if err != nil {
+		return http.StatusInternalServerError, err
 	}
-	if result.Parameters != nil {
-		result.Parameters["alwayson_scripts"] = ""
+	if len(data) == 0 {
+		return http.StatusConflict, fmt.Errorf("taskId %s reused concurrently", taskId)
 	}
-	params, err := json.Marshal(result.Parameters)
+	if code, err := taskReuseConflict(username, taskId, data); err != nil {
+		return code, err
+	}
+	swapped, err := p.taskStore.CompareAndSwap(taskId, datastore.KTaskStatus, data[datastore.KTaskStatus],
+		map[string]interface{}{datastore.KTaskStatus: config.TASK_QUEUE})
 	if err != nil {
-		logrus.WithFields(logrus.Fields{"taskId": taskId}).Println("json:", err.Error())
+		return http.StatusInternalServerError, err
 	}
-	var images []string
-	var status string
-	var errMeg error
-	if resp.StatusCode == requestOk {
-		count := len(result.Images)
-		for i := 1; i <= count; i++ {
-			// upload image to oss
-			ossPath := fmt.Sprintf("images/%s/%s_%d.png", user, taskId, i)
-			if err := uploadImages(&ossPath, &result.Images[i-1]); err != nil {
-				return nil, fmt.Errorf("output image err=%s", err.Error())
-			}
+	if !swapped {
+		return http.StatusConflict, fmt.Errorf("taskId %s reused concurrently", taskId)
+	}
+	// claimed, columns of previous task cleared
+	if err := p.taskStore.Put(taskId, values); err != nil {
+		return http.StatusInternalServerError, err
+	}
+	return http.StatusOK, nil
+}
 
-			images = append(images, ossPath)
+// predictFail mark task failed when sd request fail, restart sd if process gone after timeout
+func (p *ProxyHandler) predictFail(taskId string, err error, gpuSeconds float64) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		err = fmt.Errorf("predict timeout after %s", config.ConfigGlobal.GetPredictTimeout())
+		// wedged webui keep port open, detect loop never restart it
+		if module.SDManageObj != nil {
+			go func() {
+				if err := module.SDManageObj.Restart(); err != nil {
+					logrus.Errorf("restart sd after predict timeout err=%s", err.Error())
+				}
+			}()
 		}
-		status = config.TASK_FINISH
-	} else {
-		status = config.TASK_FAILED
-		errMeg = errors.New("predict error")
 	}
-	if err := p.taskStore.Update(taskId, map[string]interface{}{
-		datastore.KTaskCode:       int64(resp.StatusCode),
-		datastore.KTaskStatus:     status,
-		datastore.KTaskImage:      strings.Join(images, ","),
-		datastore.KTaskParams:     string(params),
-		datastore.KTaskInfo:       result.Info,
+	logrus.WithFields(logrus.Fields{"taskId": taskId}).Errorf("predict err=%s", err.Error())
+	if _, updateErr := p.updateTaskStatus(taskId, map[string]interface{}{
+		datastore.KTaskCode:       int64(requestFail),
+		datastore.KTaskStatus:     config.TASK_FAILED,
+		datastore.KTaskInfo:       err.Error(),
+		datastore.KTaskGpuSeconds: gpuSeconds,
 		datastore.KTaskModifyTime: fmt.Sprintf("%d", utils.TimestampS()),
-	}); err != nil {
-		logrus.WithFields(logrus.Fields{"taskId": taskId}).Errorln(err.Error())
-		return nil, err
+	}); updateErr != nil {
+		logrus.WithFields(logrus.Fields{"taskId": taskId}).Errorln(updateErr.Error())
 	}
-	return images, errMeg
+	return err
 }
 
 // deal ossImg to base64
@@ -692,13 +1920,38 @@ func preprocessRequest(req any) error {
 		if request.Image != "" {
 			if isImgPath(request.Image) {
 
-				base64, err := module.OssGlobal.DownloadFileToBase64(request.Image)
+				base64, err := downloadFileToBase64(request.Image)
 				if err != nil {
 					return err
 				}
 				request.Image = *base64
 			}
 		}
+	case *models.ExtraBatchImagesJSONRequestBody:
+		request := req.(*models.ExtraBatchImagesJSONRequestBody)
+		for i := range request.ImageList {
+			image := &request.ImageList[i]
+			if !isImgPath(image.Data) {
+				continue
+			}
+			if image.Name == nil || *image.Name == "" {
+				image.Name = utils.String(path.Base(image.Data))
+			}
+			base64, err := downloadFileToBase64(image.Data)
+			if err != nil {
+				return err
+			}
+			image.Data = *base64
+		}
+	case *models.InterrogateJSONRequestBody:
+		request := req.(*models.InterrogateJSONRequestBody)
+		if isImgPath(request.Image) {
+			base64, err := downloadFileToBase64(request.Image)
+			if err != nil {
+				return err
+			}
+			request.Image = *base64
+		}
 	case *models.Txt2ImgJSONRequestBody:
 		request := req.(*models.Txt2ImgJSONRequestBody)
 		if request.AlwaysonScripts != nil {
@@ -706,25 +1959,27 @@ func preprocessRequest(req any) error {
 		}
 	case *models.Img2ImgJSONRequestBody:
 		request := req.(*models.Img2ImgJSONRequestBody)
-		// init images: ossPath to base64Str
-		for i, str := range *request.InitImages {
-			if !isImgPath(str) {
-				continue
-			}
-			base64, err := module.OssGlobal.DownloadFileToBase64(str)
-			if err != nil {
-				return err
+		// init images: ossPath to base64Str or url
+		if request.InitImages != nil {
+			for i, str := range *request.InitImages {
+				if !isImgPath(str) {
+					continue
+				}
+				image, err := initImageInput(str)
+				if err != nil {
+					return err
+				}
+				(*request.InitImages)[i] = image
 			}
-			(*request.InitImages)[i] = *base64
 		}
 
-		// mask images: ossPath to base64St
+		// mask images: ossPath to base64St or url
 		if request.Mask != nil && isImgPath(*request.Mask) {
-			base64, err := module.OssGlobal.DownloadFileToBase64(*request.Mask)
+			image, err := initImageInput(*request.Mask)
 			if err != nil {
 				return err
 			}
-			*request.Mask = *base64
+			*request.Mask = image
 		}
 
 		// controlNet images: ossPath to base64Str
@@ -735,11 +1990,96 @@ func preprocessRequest(req any) error {
 	return nil
 }
 
+// initImageInput img2img oss image as webui input, signed url loaded by webui when initImageSource url,
+// image never read by proxy, fall back to base64 when oss not support url
+func initImageInput(ossPath string) (string, error) {
+	if config.ConfigGlobal.IsInitImageUrl() {
+		urls, err := module.OssGlobal.GetUrl([]string{ossPath})
+		if err == nil && len(urls) == 1 {
+			return urls[0], nil
+		}
+		logrus.Warnf("init image %s url fail, fall back to base64, err=%v", ossPath, err)
+	}
+	base64, err := downloadFileToBase64(ossPath)
+	if err != nil {
+		return "", err
+	}
+	return *base64, nil
+}
+
+// controlNet units: each unit image ossPath to base64Str and check unit model exist
+// other alwayson scripts ossPath to base64Str
 func updateControlNet(alwaysonScripts *map[string]interface{}) error {
+	for name, script := range *alwaysonScripts {
+		if strings.ToLower(name) != controlNetScript {
+			continue
+		}
+		scriptMap, ok := script.(map[string]interface{})
+		if !ok {
+			return errors.New("controlnet args invalid")
+		}
+		units, _ := scriptMap["args"].([]interface{})
+		for i, unit := range units {
+			unitMap, ok := unit.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if err := updateControlNetUnit(unitMap); err != nil {
+				return fmt.Errorf("controlnet unit %d: %s", i, err.Error())
+			}
+		}
+	}
 	*alwaysonScripts = parseMap(*alwaysonScripts, "", "", nil)
 	return nil
 }
 
+func updateControlNetUnit(unit map[string]interface{}) error {
+	if enabled, ok := unit["enabled"].(bool); ok && !enabled {
+		return nil
+	}
+	images := []map[string]interface{}{unit}
+	// image may be {"image": xx, "mask": xx}
+	if imageMap, ok := unit["image"].(map[string]interface{}); ok {
+		images = append(images, imageMap)
+	}
+	for _, imageMap := range images {
+		for _, key := range controlNetImageKeys {
+			path, ok := imageMap[key].(string)
+			if !ok || !isImgPath(path) {
+				continue
+			}
+			base64, err := downloadFileToBase64(path)
+			if err != nil {
+				return fmt.Errorf("download %s %s err=%s", key, path, err.Error())
+			}
+			imageMap[key] = *base64
+		}
+	}
+	if model, ok := unit["model"].(string); ok && !checkControlNetModelExist(model) {
+		return fmt.Errorf("model %s not found", model)
+	}
+	return nil
+}
+
+// controlNet model name format: name [hash]
+func checkControlNetModelExist(model string) bool {
+	model = strings.TrimSpace(model)
+	if idx := strings.LastIndex(model, " ["); idx > 0 {
+		model = model[:idx]
+	}
+	path := config.ConfigGlobal.GetModelDir(config.CONTORLNET_MODEL)
+	// mount nas && check
+	if model == "" || model == "None" || !utils.FileExists(path) {
+		return true
+	}
+	for _, one := range utils.ListFile(path) {
+		if strings.TrimSuffix(one, filepath.Ext(one)) == model || one == model {
+			return true
+		}
+	}
+	return false
+}
+
 func (p *ProxyHandler) updateOverrideSettingsRequest(overrideSettings *map[string]interface{},
 	username, configVersion, sdModel string, sdVae *string) error {
 	//if config.ConfigGlobal.GetFlexMode() == config.MultiFunc {
@@ -773,6 +2113,9 @@ func (p *ProxyHandler) updateOverrideSettingsRequest(overrideSettings *map[strin
 	if err := json.Unmarshal([]byte(val), &m); err != nil {
 		return nil
 	}
+	if err := filterUserSettings(username, m); err != nil {
+		return err
+	}
 	// priority request > db
 	for k, v := range m {
 		if _, ok := (*overrideSettings)[k]; !ok {
@@ -795,29 +2138,71 @@ func (p *ProxyHandler) Img2Img(c *gin.Context) {
 			username = DEFAULT_USER
 		}
 	}
+	// result images returned by downstream
+	if rejectInlineImages(c) {
+		return
+	}
 	request := new(models.Img2ImgJSONRequestBody)
-	if err := getBindResult(c, request); err != nil {
-		handleError(c, http.StatusBadRequest, config.BADREQUEST)
+	if err := p.bindWithModelDefaults(c, request); err != nil {
+		handleBindError(c, err)
+		return
+	}
+	// labels stored in task, not forward
+	labels, err := taskLabelsVal(request.Labels)
+	if err != nil {
+		handleError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	request.Labels = nil
+	// expand prompt templates
+	if err := p.expandRequestPrompt(username, request.Prompt, request.NegativePrompt); err != nil {
+		handleError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	p.injectNegativeEmbeddings(username, &request.NegativePrompt, request.DisableDefaultNegative)
+	// proxy only, not forward
+	request.DisableDefaultNegative = nil
+	if err := validateRequestPrompts(request.Prompt, request.NegativePrompt); err != nil {
+		handleError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	// protect shared gpu, before render cache hash
+	clamped, err := applyRequestLimit(username, request.CfgScale, request.Steps)
+	if err != nil {
+		handleError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	// likely oom fail early instead of crash webui
+	if err := checkGpuMemory(request.StableDiffusionModel, img2ImgPixels(request), request.BatchSize); err != nil {
+		handleError(c, http.StatusBadRequest, err.Error())
 		return
 	}
 	if !checkSdModelValid(request.StableDiffusionModel) {
 		handleError(c, http.StatusBadRequest, "stable_diffusion_model val not valid, please set valid val")
 		return
 	}
+	if err := normalizeInpaint(request); err != nil {
+		handleError(c, http.StatusBadRequest, err.Error())
+		return
+	}
 	// taskId
 	taskId := c.GetHeader(taskKey)
-	if taskId == "" {
+	forced := taskId != ""
+	if !forced {
 		// init taskId
 		taskId = utils.RandStr(taskIdLength)
 	}
 	c.Writer.Header().Set("taskId", taskId)
 
 	endPoint := config.ConfigGlobal.Downstream
-	var err error
 	version := c.GetHeader(versionKey)
 	if config.ConfigGlobal.IsServerTypeMatch(config.CONTROL) {
+		// model may deleted while async request queued
+		sdModel, ok := p.resolveForwardRequest(c, taskId, request.StableDiffusionModel, request)
+		if !ok {
+			return
+		}
 		// get endPoint
-		sdModel := request.StableDiffusionModel
 		c.Writer.Header().Set("model", sdModel)
 		// wait to valid
 		if concurrency.ConCurrencyGlobal.WaitToValid(sdModel) {
@@ -826,39 +2211,29 @@ func (p *ProxyHandler) Img2Img(c *gin.Context) {
 			defer concurrency.ConCurrencyGlobal.DecColdNum(sdModel, taskId)
 		}
 		defer concurrency.ConCurrencyGlobal.DoneTask(sdModel, taskId)
-		endPoint, err = module.FuncManagerGlobal.GetEndpoint(sdModel)
+		endPoint, err = getStickyEndpoint(c, sdModel, false)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, models.SubmitTaskResponse{
-				TaskId:  taskId,
-				Status:  config.TASK_FAILED,
-				Message: utils.String(err.Error()),
-			})
+			handleEndpointError(c, taskId, err)
 			return
 		}
 	}
+	// unknown sampler fail early instead of mid-render, check sd request routed to
+	if err := validateSamplers(p.httpClient, endPoint, request.SamplerName, request.SamplerIndex); err != nil {
+		handleError(c, http.StatusBadRequest, err.Error())
+		return
+	}
 	if config.ConfigGlobal.IsServerTypeMatch(config.PROXY) {
-		// check request valid: sdModel and sdVae exist
-		if existed := p.checkModelExist(request.StableDiffusionModel); !existed {
-			handleError(c, http.StatusNotFound, "model not found, please check request")
-			return
+		if forced {
+			if code, err := p.checkForceTaskId(username, taskId); err != nil {
+				handleError(c, code, err.Error())
+				return
+			}
 		}
-		// write db
-		if err := p.taskStore.Put(taskId, map[string]interface{}{
-			datastore.KTaskIdColumnName: taskId,
-			datastore.KTaskUser:         username,
-			datastore.KTaskStatus:       config.TASK_QUEUE,
-			datastore.KTaskCancel:       int64(config.CANCEL_INIT),
-			datastore.KTaskCreateTime:   fmt.Sprintf("%d", utils.TimestampS()),
-		}); err != nil {
-			logrus.WithFields(logrus.Fields{"taskId": taskId}).Error("[Error] put db err=", err.Error())
-			c.JSON(http.StatusInternalServerError, models.SubmitTaskResponse{
-				TaskId:  taskId,
-				Status:  config.TASK_FAILED,
-				Message: utils.String(config.OTSPUTERROR),
-			})
+		// check request valid: sdModel and sdVae exist
+		if code, err := p.ensureModelExist(request.StableDiffusionModel); err != nil {
+			handleError(c, code, err.Error())
 			return
 		}
-
 		// get user current config version
 		userItem, err := p.userStore.Get(username, []string{datastore.KUserConfigVer})
 		if err != nil {
@@ -877,13 +2252,58 @@ func (p *ProxyHandler) Img2Img(c *gin.Context) {
 				return version.(string)
 			}
 		}()
+		// identical render finished recently, reuse its images
+		cacheHash := renderCacheHash(c, config.IMG2IMG, username, version, request)
+		if cacheHash != "" && p.replyRenderCache(c, username, taskId, cacheHash, labels) {
+			return
+		}
+		leaveUser, rejected := p.enterUserTasks(c, username, taskId, 1)
+		if rejected {
+			return
+		}
+		defer leaveUser()
+		// doomed work rejected instead of queued
+		leave, err := p.taskQueue.enter(request.StableDiffusionModel, 1)
+		if err != nil {
+			handleQueueFull(c, taskId)
+			return
+		}
+		defer leave()
+		// write db
+		if code, err := p.putTask(username, taskId, forced, map[string]interface{}{
+			datastore.KTaskIdColumnName: taskId,
+			datastore.KTaskUser:         username,
+			datastore.KTaskStatus:       config.TASK_QUEUE,
+			datastore.KTaskCancel:       int64(config.CANCEL_INIT),
+			datastore.KTaskCreateTime:   fmt.Sprintf("%d", utils.TimestampS()),
+			datastore.KTaskModel:        request.StableDiffusionModel,
+			datastore.KTaskLabels:       labels,
+			datastore.KTaskRequest:      taskRequestVal(img2ImgPath, request),
+		}); code == http.StatusConflict {
+			handleError(c, code, err.Error())
+			return
+		} else if err != nil {
+			logrus.WithFields(logrus.Fields{"taskId": taskId}).Error("[Error] put db err=", err.Error())
+			c.JSON(http.StatusInternalServerError, models.SubmitTaskResponse{
+				TaskId:  taskId,
+				Status:  config.TASK_FAILED,
+				Message: utils.String(config.OTSPUTERROR),
+			})
+			return
+		}
+		// task counted by row until terminal
+		leave()
+		leaveUser()
+		if cacheHash != "" {
+			p.putRenderCache(cacheHash, taskId)
+		}
 	}
 	ctx, cancel := context.WithTimeout(context.Background(), config.HTTPTIMEOUT)
 	defer cancel()
 	// get client by endPoint
 	client := client.ManagerClientGlobal.GetClient(endPoint)
 	// async request
-	resp, err := client.Img2Img(ctx, *request, func(ctx context.Context, req *http.Request) error {
+	editor := func(ctx context.Context, req *http.Request) error {
 		req.Header.Add(userKey, username)
 		req.Header.Add(taskKey, taskId)
 		req.Header.Add(versionKey, version)
@@ -891,22 +2311,21 @@ func (p *ProxyHandler) Img2Img(c *gin.Context) {
 			req.Header.Add(FcAsyncKey, "Async")
 		}
 		return nil
-	})
+	}
+	resp, err := client.Img2Img(ctx, *request, editor)
+	if _, fallbackClient := controlFallback(c, endPoint, err); fallbackClient != nil {
+		resp, err = fallbackClient.Img2Img(ctx, *request, editor)
+	}
 	if err != nil || (resp.StatusCode != syncSuccessCode && resp.StatusCode != asyncSuccessCode) {
 		handleRespError(c, err, resp, taskId)
 	} else {
+		status, invokeMode := submitResult(resp.StatusCode)
 		c.JSON(http.StatusOK, models.SubmitTaskResponse{
-			TaskId: taskId,
-			Status: func() string {
-				if resp.StatusCode == syncSuccessCode {
-					return config.TASK_FINISH
-				}
-				if resp.StatusCode == asyncSuccessCode {
-					return config.TASK_QUEUE
-				}
-				return config.TASK_FAILED
-			}(),
-			OssUrl: extraOssUrl(resp),
+			TaskId:     taskId,
+			Status:     status,
+			InvokeMode: invokeMode,
+			OssUrl:     extraOssUrl(resp),
+			Clamped:    clampedNotes(clamped),
 		})
 	}
 }
@@ -971,39 +2390,113 @@ func (p *ProxyHandler) UpdateOptions(c *gin.Context) {
 		handleError(c, http.StatusBadRequest, config.BADREQUEST)
 		return
 	}
-	version := fmt.Sprintf("%d", utils.TimestampS())
-	key := fmt.Sprintf("%s_%s", username, version)
-	if err := p.configStore.Put(key, map[string]interface{}{
-		datastore.KConfigVal:      string(configStr),
-		datastore.KConfigVer:      version,
-		datastore.KUserModifyTime: fmt.Sprintf("%d", utils.TimestampS()),
-	}); err != nil {
+	if _, err := p.bumpConfigVersion(username, string(configStr)); err != nil {
+		logrus.Errorf("user %s bump config version err=%s", username, err.Error())
 		handleError(c, http.StatusInternalServerError, "update db error")
 		return
 	}
-	if err := p.userStore.Update(username, map[string]interface{}{
-		datastore.KUserConfigVer:  version,
-		datastore.KUserModifyTime: fmt.Sprintf("%d", utils.TimestampS()),
-	}); err != nil {
-		if !config.ConfigGlobal.EnableLogin() {
-			// if username not existed add user
-			if err = p.userStore.Put(username, map[string]interface{}{
+	c.JSON(http.StatusOK, gin.H{"message": "success"})
+}
+
+// bumpConfigVersion write config of new version then set user config version to it only if not changed
+// since read, so published version always has its config, concurrent update never claim same version
+func (p *ProxyHandler) bumpConfigVersion(username, configVal string) (string, error) {
+	for i := 0; i < maxCasRetries; i++ {
+		// name and modify time read to tell user exist when version not set
+		data, err := p.userStore.Get(username, []string{datastore.KUserConfigVer, datastore.KUserName,
+			datastore.KUserModifyTime})
+		if err != nil {
+			return "", err
+		}
+		if len(data) == 0 && config.ConfigGlobal.EnableLogin() {
+			return "", fmt.Errorf("user %s not exist", username)
+		}
+		// nil expected when version not set
+		current := data[datastore.KUserConfigVer]
+		cur, _ := current.(string)
+		version, key, err := p.putConfigVersion(username, cur, configVal)
+		if err != nil {
+			return "", err
+		}
+		var swapped bool
+		if len(data) == 0 {
+			// if username not existed add user, concurrent first update lose insert and retry by cas
+			swapped, err = p.userStore.PutIfAbsent(username, map[string]interface{}{
 				datastore.KUserConfigVer:  version,
 				datastore.KUserCreateTime: fmt.Sprintf("%d", utils.TimestampS()),
 				datastore.KUserModifyTime: fmt.Sprintf("%d", utils.TimestampS()),
-			}); err == nil {
-				c.JSON(http.StatusOK, gin.H{"message": "success"})
-				return
-			}
+			})
+		} else {
+			swapped, err = p.userStore.CompareAndSwap(username, datastore.KUserConfigVer, current,
+				map[string]interface{}{
+					datastore.KUserConfigVer:  version,
+					datastore.KUserModifyTime: fmt.Sprintf("%d", utils.TimestampS()),
+				})
+		}
+		if swapped {
+			return version, nil
+		}
+		// version not published, drop its config
+		if delErr := p.configStore.Delete(key); delErr != nil {
+			logrus.Warnf("delete unpublished config %s err=%s", key, delErr.Error())
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	return "", errCasConflict
+}
 
+// putConfigVersion put config of version greater than current, version taken by concurrent update skipped
+func (p *ProxyHandler) putConfigVersion(username, current, configVal string) (string, string, error) {
+	version := current
+	for i := 0; i < maxCasRetries; i++ {
+		version = nextConfigVersion(version)
+		key := fmt.Sprintf("%s_%s", username, version)
+		ok, err := p.configStore.PutIfAbsent(key, map[string]interface{}{
+			datastore.KConfigVal:        configVal,
+			datastore.KConfigVer:        version,
+			datastore.KConfigModifyTime: fmt.Sprintf("%d", utils.TimestampS()),
+		})
+		if err != nil || ok {
+			return version, key, err
 		}
-		handleError(c, http.StatusInternalServerError, "update db error")
-		return
 	}
-	c.JSON(http.StatusOK, gin.H{"message": "success"})
+	return "", "", errCasConflict
 }
 
+// task columns read for task result
+var taskResultColumns = []string{datastore.KTaskStatus, datastore.KTaskImage, datastore.KTaskInfo,
+	datastore.KTaskParams, datastore.KTaskCode, datastore.KTaskGpuSeconds, datastore.KTaskEffectiveSettings,
+	datastore.KTaskWebuiJobId, datastore.KTaskLabels, datastore.KTaskImagesExpired,
+	datastore.KTaskFailedImages}
+
 func (p *ProxyHandler) getTaskResult(taskId string) (*models.TaskResultResponse, error) {
+	data, err := p.taskStore.Get(taskId, taskResultColumns)
+	if err != nil || data == nil || len(data) == 0 {
+		return nil, errors.New("not found")
+	}
+	return signedTaskResult(taskId, data)
+}
+
+// signedTaskResult task result of task columns with ossUrl of images
+func signedTaskResult(taskId string, data map[string]interface{}) (*models.TaskResultResponse, error) {
+	result, err := taskResultFromData(taskId, data)
+	if err != nil {
+		return nil, err
+	}
+	if len(*result.Images) > 0 {
+		if ossUrl, err := module.OssGlobal.GetUrl(*result.Images); err == nil {
+			*result.OssUrl = ossUrl
+		} else {
+			logrus.Warn("get oss url error")
+		}
+	}
+	return result, nil
+}
+
+// taskResultFromData task result of task columns, ossUrl of images not filled
+func taskResultFromData(taskId string, data map[string]interface{}) (*models.TaskResultResponse, error) {
 	result := &models.TaskResultResponse{
 		TaskId:     taskId,
 		Status:     config.TASK_QUEUE,
@@ -1012,18 +2505,41 @@ func (p *ProxyHandler) getTaskResult(taskId string) (*models.TaskResultResponse,
 		Images:     new([]string),
 		OssUrl:     new([]string),
 	}
-	data, err := p.taskStore.Get(taskId, []string{datastore.KTaskStatus, datastore.KTaskImage, datastore.KTaskInfo,
-		datastore.KTaskParams, datastore.KTaskCode})
-	if err != nil || data == nil || len(data) == 0 {
-		return nil, errors.New("not found")
+	result.Labels = taskLabels(data)
+	if jobId, ok := data[datastore.KTaskWebuiJobId].(string); ok && jobId != "" {
+		result.WebuiJobId = utils.String(jobId)
+	}
+	if gpuSeconds, ok := data[datastore.KTaskGpuSeconds].(float64); ok {
+		result.GpuSeconds = utils.Float64(gpuSeconds)
+	}
+	if settings, ok := data[datastore.KTaskEffectiveSettings].(string); ok && settings != "" {
+		effectiveSettings := make(map[string]interface{})
+		if err := json.Unmarshal([]byte(settings), &effectiveSettings); err == nil {
+			result.EffectiveSettings = &effectiveSettings
+		}
 	}
 
-	// not success
-	if status, ok := data[datastore.KTaskStatus]; ok && (status != config.TASK_FINISH) {
+	// not success, partial upload return uploaded images like success
+	if status, ok := data[datastore.KTaskStatus]; ok && status != config.TASK_FINISH &&
+		status != config.TASK_PARTIAL_UPLOAD {
 		result.Status = status.(string)
+		// running task return uploaded images
+		if image, ok := data[datastore.KTaskImage].(string); ok && image != "" && status == config.TASK_INPROGRESS {
+			*result.Images = strings.Split(image, ",")
+			result.Partial = utils.Bool(true)
+		}
+		// webui info tell why no images
+		if status == config.TASK_NO_OUTPUT {
+			result.Message = utils.String(errNoOutput.Error())
+			if info, ok := data[datastore.KTaskInfo].(string); ok && info != "" {
+				if err := json.Unmarshal([]byte(info), result.Info); err != nil {
+					result.Message = utils.String(noOutputError(info).Error())
+				}
+			}
+		}
 		return result, nil
 	} else if ok {
-		result.Status = config.TASK_FINISH
+		result.Status = status.(string)
 	}
 
 	if code, ok := data[datastore.KTaskCode]; ok && code.(int64) != requestOk {
@@ -1034,7 +2550,16 @@ func (p *ProxyHandler) getTaskResult(taskId string) (*models.TaskResultResponse,
 	}
 
 	// images
-	*result.Images = strings.Split(data[datastore.KTaskImage].(string), ",")
+	if imagesExpired(data) {
+		// purged by retention, not return broken urls
+		result.ImagesExpired = utils.Bool(true)
+	} else {
+		*result.Images = strings.Split(data[datastore.KTaskImage].(string), ",")
+	}
+	if result.Status == config.TASK_PARTIAL_UPLOAD {
+		result.FailedImages = taskFailedImages(data)
+		result.Message = utils.String(errPartialUpload.Error())
+	}
 	// params
 	paramsStr := data[datastore.KTaskParams].(string)
 	var m map[string]interface{}
@@ -1049,11 +2574,6 @@ func (p *ProxyHandler) getTaskResult(taskId string) (*models.TaskResultResponse,
 		logrus.WithFields(logrus.Fields{"taskId": taskId}).Println("Unmarshal Info error=", err.Error())
 	}
 	*result.Info = mm
-	if ossUrl, err := module.OssGlobal.GetUrl(*result.Images); err == nil {
-		*result.OssUrl = ossUrl
-	} else {
-		logrus.Warn("get oss url error")
-	}
 	return result, nil
 }
 
@@ -1071,10 +2591,10 @@ func (p *ProxyHandler) checkModelExist(sdModel string) bool {
 		// check local existed
 		switch model[0] {
 		case config.SD_MODEL:
-			sdModelPath := fmt.Sprintf("%s/models/%s/%s", config.ConfigGlobal.SdPath, "Stable-diffusion", sdModel)
+			path := config.ConfigGlobal.GetModelDir(config.SD_MODEL)
+			sdModelPath := fmt.Sprintf("%s/%s", path, sdModel)
 			if !utils.FileExists(sdModelPath) {
 				// list check image models
-				path := fmt.Sprintf("%s/models/%s", config.ConfigGlobal.SdPath, "Stable-diffusion")
 				tmp := utils.ListFile(path)
 				for _, one := range tmp {
 					if one == sdModel {
@@ -1084,10 +2604,10 @@ func (p *ProxyHandler) checkModelExist(sdModel string) bool {
 				return false
 			}
 			//case config.MODEL_SD_VAE:
-			//	sdVaePath := fmt.Sprintf("%s/models/%s/%s", config.ConfigGlobal.SdPath, "VAE", sdVae)
+			//	path := config.ConfigGlobal.GetModelDir(config.SD_VAE)
+			//	sdVaePath := fmt.Sprintf("%s/%s", path, sdVae)
 			//	if !utils.FileExists(sdVaePath) {
 			//		// list check image models
-			//		path := fmt.Sprintf("%s/models/%s", config.ConfigGlobal.SdPath, "VAE")
 			//		tmp := utils.ListFile(path)
 			//		for _, one := range tmp {
 			//			if one == sdVae {
@@ -1119,23 +2639,43 @@ func convertToModelResponse(datas map[string]map[string]interface{}) []*models.M
 	return ret
 }
 
-func getModelsStatus(modelType string) string {
-	switch modelType {
-	case config.SD_MODEL, config.SD_VAE:
-		return config.MODEL_LOADED
-	default:
-		return config.MODEL_LOADED
+// loadModel let sd load model, model status loading -> loaded after refresh success,
+// rolled back to rollbackStatus when refresh fail or no function reloaded model (module.ErrFunctionNotExist)
+// multiFunc control: update function env, otherwise: call webui refresh api
+func (p *ProxyHandler) loadModel(modelName, modelType, rollbackStatus string) error {
+	var err error
+	if config.ConfigGlobal.GetFlexMode() == config.MultiFunc &&
+		config.ConfigGlobal.IsServerTypeMatch(config.CONTROL) {
+		// sdModel and sdVae enable env update
+		if modelType == config.SD_MODEL || modelType == config.SD_VAE {
+			err = updateFunctionEnv(modelName)
+		}
+	} else {
+		err = refreshModel(modelType)
 	}
+	if err != nil {
+		if rollbackErr := p.modelStore.Update(modelName, map[string]interface{}{
+			datastore.KModelStatus:     rollbackStatus,
+			datastore.KModelModifyTime: fmt.Sprintf("%d", utils.TimestampS()),
+		}); rollbackErr != nil {
+			logrus.Errorf("model %s rollback status %s err=%s", modelName, rollbackStatus, rollbackErr.Error())
+		}
+		return err
+	}
+	return p.modelStore.Update(modelName, map[string]interface{}{
+		datastore.KModelStatus:     config.MODEL_LOADED,
+		datastore.KModelModifyTime: fmt.Sprintf("%d", utils.TimestampS()),
+	})
 }
 
 func ApiAuth() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		path := c.Request.URL.Path
-		if path != "/login" {
+		if path != "/login" && path != "/version" && !strings.HasPrefix(path, sharedPathPrefix) {
 			tokenString := c.Request.Header.Get("Token")
 			userName, ok := module.UserManagerGlobal.VerifySessionValid(tokenString)
 			if !ok {
-				c.JSON(http.StatusGone, gin.H{"message": "please login first or login expired"})
+				handleError(c, http.StatusGone, "please login first or login expired")
 				c.Abort()
 			}
 			c.Request.Header.Set("userName", userName)
@@ -1143,6 +2683,28 @@ func ApiAuth() gin.HandlerFunc {
 	}
 }
 
+// AdminAuth only admin user can access /admin api
+func AdminAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if strings.HasPrefix(c.Request.URL.Path, adminPathPrefix) &&
+			c.Request.Header.Get(userKey) != module.DefaultUser {
+			handleError(c, http.StatusForbidden, "admin permission required")
+			c.Abort()
+		}
+	}
+}
+
+// submitResult task status and invoke mode by downstream status code, 202 means fc accepted async invoke
+func submitResult(statusCode int) (string, *string) {
+	switch statusCode {
+	case syncSuccessCode:
+		return config.TASK_FINISH, utils.String(invokeModeSync)
+	case asyncSuccessCode:
+		return config.TASK_QUEUE, utils.String(invokeModeAsync)
+	}
+	return config.TASK_FAILED, nil
+}
+
 func isAsync(invokeType string) bool {
 	// control server default sync
 	if config.ConfigGlobal.GetFlexMode() == config.MultiFunc && config.ConfigGlobal.IsServerTypeMatch(config.CONTROL) {
@@ -1165,7 +2727,7 @@ func (p *ProxyHandler) NoRouterHandler(c *gin.Context) {
 		}
 	}
 	taskId := ""
-	if isTask := c.GetHeader("Task-Flag"); isTask == "true" || isAsync(c.GetHeader(requestType)) {
+	if isTask := c.GetHeader(taskFlagKey); isTask == "true" || isAsync(c.GetHeader(requestType)) {
 		// taskId
 		taskId = c.GetHeader(taskKey)
 		if taskId == "" {
@@ -1181,18 +2743,32 @@ func (p *ProxyHandler) NoRouterHandler(c *gin.Context) {
 	body, _ := io.ReadAll(c.Request.Body)
 	defer c.Request.Body.Close()
 	if config.ConfigGlobal.IsServerTypeMatch(config.CONTROL) {
+		headerModel := c.GetHeader(sdModelKey)
 		if c.Request.Method != http.MethodGet && c.Request.Method != http.MethodDelete {
 			// extra body
 			request := make(map[string]interface{})
 			err := json.Unmarshal(body, &request)
-			if err != nil {
+			if err != nil && headerModel == "" {
 				c.String(http.StatusBadRequest, err.Error())
 				return
 			}
-			if sd, ok := request["StableDiffusionModel"]; ok {
-				sdModel = sd.(string)
+			// extension route carry model in its own field
+			if field := config.ConfigGlobal.GetRouteModelField(c.Request.URL.Path); field != "" {
+				sdModel = bodyModelField(request, field)
+			}
+			if sd, ok := request["StableDiffusionModel"].(string); ok && sdModel == "" {
+				sdModel = sd
 			}
 		}
+		// body not carry model, use X-SD-Model header pin instance, then defaultModel
+		if sdModel == "" {
+			sdModel = withDefaultModel(headerModel)
+		}
+		// model may deleted while async request queued, passthrough body model field unknown so no fallback
+		if sdModel != "" && p.modelRemoved(sdModel) {
+			replyRemovedModel(c, taskId, p.failRemovedModel(taskId, sdModel))
+			return
+		}
 		c.Writer.Header().Set("model", sdModel)
 		// wait to valid
 		if concurrency.ConCurrencyGlobal.WaitToValid(sdModel) {
@@ -1202,18 +2778,8 @@ func (p *ProxyHandler) NoRouterHandler(c *gin.Context) {
 		}
 		defer concurrency.ConCurrencyGlobal.DoneTask(sdModel, taskId)
 		var err error
-		if sdModel == "" {
-			endPoint = module.FuncManagerGlobal.GetLastInvokeEndpoint(&sdModel)
-		} else {
-			endPoint, err = module.FuncManagerGlobal.GetEndpoint(sdModel)
-		}
-
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, models.SubmitTaskResponse{
-				TaskId:  taskId,
-				Status:  config.TASK_FAILED,
-				Message: utils.String(err.Error()),
-			})
+		if endPoint, err = getStickyEndpoint(c, sdModel, false); err != nil {
+			handleEndpointError(c, taskId, err)
 			return
 		}
 	}
@@ -1221,8 +2787,8 @@ func (p *ProxyHandler) NoRouterHandler(c *gin.Context) {
 	if config.ConfigGlobal.IsServerTypeMatch(config.PROXY) {
 		// check request valid: sdModel and sdVae exist
 		if sdModel != "" {
-			if existed := p.checkModelExist(sdModel); !existed {
-				handleError(c, http.StatusNotFound, "model not found, please check request")
+			if code, err := p.ensureModelExist(sdModel); err != nil {
+				handleError(c, code, err.Error())
 				return
 			}
 		}
@@ -1253,7 +2819,8 @@ func (p *ProxyHandler) NoRouterHandler(c *gin.Context) {
 		return
 	}
 
-	req.Header = c.Request.Header
+	// client credentials not reach webui
+	req.Header = forwardHeaders(c.Request.Header)
 	if taskId != "" {
 		req.Header.Set("taskId", taskId)
 	}
@@ -1262,7 +2829,7 @@ func (p *ProxyHandler) NoRouterHandler(c *gin.Context) {
 	}
 	req.Header.Set(userKey, username)
 
-	client := &http.Client{}
+	client := &http.Client{Transport: module.SdTransport(nil)}
 	resp, err := client.Do(req)
 	if err != nil {
 		c.String(http.StatusInternalServerError, err.Error())
@@ -1276,17 +2843,11 @@ func (p *ProxyHandler) NoRouterHandler(c *gin.Context) {
 				Message: utils.String(config.INTERNALERROR),
 			})
 		} else {
+			status, invokeMode := submitResult(resp.StatusCode)
 			c.JSON(http.StatusOK, models.SubmitTaskResponse{
-				TaskId: taskId,
-				Status: func() string {
-					if resp.StatusCode == syncSuccessCode {
-						return config.TASK_FINISH
-					}
-					if resp.StatusCode == asyncSuccessCode {
-						return config.TASK_QUEUE
-					}
-					return config.TASK_FAILED
-				}(),
+				TaskId:     taskId,
+				Status:     status,
+				InvokeMode: invokeMode,
 			})
 		}
 	} else {
@@ -1296,6 +2857,10 @@ func (p *ProxyHandler) NoRouterHandler(c *gin.Context) {
 			c.String(http.StatusInternalServerError, err.Error())
 			return
 		}
+		// image or archive download, give browser a safe filename
+		if filename := passthroughFilename(taskId, c.Request.URL.Path, resp.Header); filename != "" {
+			setContentDisposition(c, filename)
+		}
 		c.Data(http.StatusOK, resp.Header.Get("Content-Type"), body)
 	}
 }
@@ -0,0 +1,751 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/datastore"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/models"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/module"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtraBatchImages(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	initTestConfig(t)
+	config.ConfigGlobal.ServerName = config.PROXY
+	config.ConfigGlobal.ImageNameTemplate = config.DefaultImageNameTemplate
+	config.ConfigGlobal.OssUploadConcurrency = 1
+	oss := mockOss(t, 0)
+	oss.uploaded["inputs/a.png"] = []byte("a")
+
+	sd := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, config.EXTRABATCHIMAGES, r.URL.Path)
+		var request models.ExtraBatchImagesRequest
+		assert.Nil(t, json.NewDecoder(r.Body).Decode(&request))
+		// oss path convert to base64, upscale output each input
+		images := make([]string, 0, len(request.ImageList))
+		for _, image := range request.ImageList {
+			data, err := base64.StdEncoding.DecodeString(image.Data)
+			assert.Nil(t, err)
+			images = append(images, base64.StdEncoding.EncodeToString(append(data, []byte("_up")...)))
+		}
+		assert.Equal(t, "a.png", *request.ImageList[0].Name)
+		assert.Nil(t, request.OutputPrefix)
+		assert.Nil(t, request.Labels)
+		json.NewEncoder(w).Encode(map[string]interface{}{"images": images, "html_info": "",
+			"info": `{"job_timestamp": "20240101120000"}`})
+	}))
+	defer sd.Close()
+	config.ConfigGlobal.SdUrlPrefix = sd.URL
+	taskStore := datastore.NewSQLiteDatastore(datastore.NewSQLiteConfig(datastore.KTaskTableName))
+	defer taskStore.Close()
+	p := &ProxyHandler{taskStore: taskStore, httpClient: &http.Client{}}
+
+	inline := "false"
+	extra := func(body string) (*httptest.ResponseRecorder, models.SubmitTaskResponse) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/extra_batch_images", bytes.NewBufferString(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Request.Header.Set(inlineImagesKey, inline)
+		p.ExtraBatchImages(c)
+		var resp models.SubmitTaskResponse
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		return w, resp
+	}
+
+	b := base64.StdEncoding.EncodeToString([]byte("b"))
+	w, resp := extra(`{"force_task_id":"task","labels":{"jobId":"j1"},"resize_mode":0,` +
+		`"image_list":[{"data":"inputs/a.png"},{"data":"` + b + `","name":"b.png"}]}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, config.TASK_FINISH, resp.Status)
+	assert.Equal(t, invokeModeSync, *resp.InvokeMode)
+	assert.Equal(t, []string{"http://oss/images/default/task_1.png", "http://oss/images/default/task_2.png"},
+		*resp.OssUrl)
+	assert.Equal(t, []byte("a_up"), oss.uploaded["images/default/task_1.png"])
+	assert.Equal(t, []byte("b_up"), oss.uploaded["images/default/task_2.png"])
+
+	// result retrievable by task
+	task, err := taskStore.Get("task", []string{datastore.KTaskStatus, datastore.KTaskImage})
+	assert.Nil(t, err)
+	assert.Equal(t, config.TASK_FINISH, task[datastore.KTaskStatus])
+	assert.Equal(t, "images/default/task_1.png,images/default/task_2.png", task[datastore.KTaskImage])
+	result, err := p.getTaskResult("task")
+	assert.Nil(t, err)
+	assert.Equal(t, "20240101120000", *result.WebuiJobId)
+	assert.Equal(t, map[string]string{"jobId": "j1"}, *result.Labels)
+
+	// small result inline as data uri, still recorded in oss
+	inline = "true"
+	config.ConfigGlobal.InlineImageMaxSize = 1
+	w, resp = extra(`{"force_task_id":"inline","resize_mode":0,"image_list":[{"data":"inputs/a.png"}]}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Nil(t, resp.OssUrl)
+	assert.Equal(t, []string{"data:image/png;base64," + base64.StdEncoding.EncodeToString([]byte("a_up"))},
+		*resp.Images)
+	assert.Equal(t, []byte("a_up"), oss.uploaded["images/default/inline_1.png"])
+	// inline disabled fall back to oss url
+	config.ConfigGlobal.InlineImageMaxSize = -1
+	_, resp = extra(`{"force_task_id":"inline","resize_mode":0,"image_list":[{"data":"inputs/a.png"}]}`)
+	assert.Nil(t, resp.Images)
+	assert.Equal(t, []string{"http://oss/images/default/inline_1.png"}, *resp.OssUrl)
+	inline = "false"
+
+	// output prefix allowed by policy
+	config.ConfigGlobal.OutputPrefixes = map[string][]string{config.AllUsers: {"users/{user}"}}
+	w, resp = extra(`{"force_task_id":"prefix","output_prefix":"users/default/p1/","resize_mode":0,` +
+		`"image_list":[{"data":"inputs/a.png"}]}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []string{"http://oss/users/default/p1/images/default/prefix_1.png"}, *resp.OssUrl)
+	assert.Equal(t, []byte("a_up"), oss.uploaded["users/default/p1/images/default/prefix_1.png"])
+	w, _ = extra(`{"output_prefix":"users/other","resize_mode":0,"image_list":[{"data":"inputs/a.png"}]}`)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w, _ = extra(`{"output_prefix":"users/default/../other","resize_mode":0,"image_list":[{"data":"inputs/a.png"}]}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// labels exceed limit
+	w, _ = extra(`{"labels":{"k":"` + strings.Repeat("v", maxTaskLabelsSize) + `"},"resize_mode":0,` +
+		`"image_list":[{"data":"inputs/a.png"}]}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// empty image list
+	w, _ = extra(`{"resize_mode":0,"image_list":[]}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// oss image not exist
+	w, _ = extra(`{"force_task_id":"missing","resize_mode":0,"image_list":[{"data":"inputs/none.png"}]}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	task, err = taskStore.Get("missing", []string{datastore.KTaskStatus})
+	assert.Nil(t, err)
+	assert.Equal(t, config.TASK_FAILED, task[datastore.KTaskStatus])
+}
+
+func TestGetVersion(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	initTestConfig(t)
+	config.ConfigGlobal.ServerName = config.CONTROL
+	config.ConfigGlobal.FlexMode = "multiFunc"
+	config.ConfigGlobal.OssMode = config.REMOTE
+	config.ConfigGlobal.LoginSwitch = "on"
+	config.ConfigGlobal.AccessKeySecret = "secret"
+
+	router := gin.New()
+	router.Use(ApiAuth())
+	RegisterHandlers(router, &ProxyHandler{})
+	// no login required
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/version", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "secret")
+	var version models.VersionInfo
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &version))
+	assert.Equal(t, models.VersionInfo{
+		Version:      config.Version,
+		Commit:       config.Commit,
+		BuildTime:    config.BuildTime,
+		ServerName:   config.CONTROL,
+		FlexMode:     "multiFunc",
+		OssMode:      config.REMOTE,
+		LoginEnabled: true,
+	}, version)
+}
+
+func TestGetEnv(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	initTestConfig(t)
+	config.ConfigGlobal.ServerName = config.AGENT
+	config.ConfigGlobal.SdPath = "/mnt/auto/sd"
+	t.Setenv(config.MODEL_SD, "sd_xl.safetensors")
+	t.Setenv(config.SD_START_PARAMS, "--api --api-auth user:pass --gradio-auth=admin:pw --medvram")
+	t.Setenv(config.ACCESS_KEY_SECRET, "secret")
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	(&ProxyHandler{}).GetEnv(c)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "secret")
+	assert.NotContains(t, w.Body.String(), "pass")
+	var env models.EnvInfo
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &env))
+	assert.Equal(t, "sd_xl.safetensors", env.Env[config.MODEL_SD])
+	assert.Equal(t, "--api --api-auth *** --gradio-auth=*** --medvram", env.Env[config.SD_START_PARAMS])
+	assert.Equal(t, "/mnt/auto/sd", env.SdPath)
+	assert.Equal(t, config.DefaultSdPort, env.SdPort)
+	assert.Equal(t, config.AGENT, env.ServerName)
+}
+
+func TestCancelExtraImages(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	initTestConfig(t)
+	config.ConfigGlobal.ServerName = config.PROXY
+	config.ConfigGlobal.ListenMinInterval = 10
+
+	interrupted := make(chan struct{}, 1)
+	sd := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case config.CANCEL:
+			interrupted <- struct{}{}
+		case config.PROGRESS:
+			json.NewEncoder(w).Encode(models.ProgressResult{State: models.State{JobTimestamp: "job"}})
+		default:
+			// long upscale, hold until client gone
+			io.Copy(io.Discard, r.Body)
+			<-r.Context().Done()
+		}
+	}))
+	defer sd.Close()
+	config.ConfigGlobal.Downstream = sd.URL
+	taskStore := datastore.NewSQLiteDatastore(datastore.NewSQLiteConfig(datastore.KTaskTableName))
+	defer taskStore.Close()
+	p := &ProxyHandler{taskStore: taskStore, httpClient: &http.Client{}}
+	router := gin.New()
+	RegisterHandlers(router, p)
+
+	go func() {
+		// cancel after webui job of task recorded
+		for {
+			if task, err := taskStore.Get("task", []string{datastore.KTaskWebuiJobId}); err == nil && task[datastore.KTaskWebuiJobId] == "job" {
+				break
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/tasks/task/cancellation", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+	}()
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/extra_images", bytes.NewBufferString(`{"image":"aaa"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(taskKey, "task")
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp models.SubmitTaskResponse
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, config.TASK_CANCELLED, resp.Status)
+	select {
+	case <-interrupted:
+	case <-time.After(time.Second):
+		t.Fatal("sd not interrupted")
+	}
+	task, err := taskStore.Get("task", []string{datastore.KTaskStatus})
+	assert.Nil(t, err)
+	assert.Equal(t, config.TASK_CANCELLED, task[datastore.KTaskStatus])
+
+	// progress finish
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/tasks/task/progress", nil))
+	var progress models.TaskProgressResponse
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &progress))
+	assert.Equal(t, float32(1), progress.Progress)
+}
+
+func TestCancelTaskInterruptOwnJob(t *testing.T) {
+	initTestConfig(t)
+	interrupted := make(chan struct{}, 1)
+	sd := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case config.CANCEL:
+			interrupted <- struct{}{}
+		case config.PROGRESS:
+			json.NewEncoder(w).Encode(models.ProgressResult{State: models.State{JobTimestamp: "job2"}})
+		}
+	}))
+	defer sd.Close()
+	taskStore := datastore.NewSQLiteDatastore(datastore.NewSQLiteConfig(datastore.KTaskTableName))
+	defer taskStore.Close()
+	p := &ProxyHandler{taskStore: taskStore, httpClient: &http.Client{}}
+	for taskId, jobId := range map[string]string{"task1": "job1", "task2": "job2", "task3": ""} {
+		assert.Nil(t, taskStore.Put(taskId, map[string]interface{}{
+			datastore.KTaskIdColumnName: taskId,
+			datastore.KTaskStatus:       config.TASK_INPROGRESS,
+			datastore.KTaskWebuiJobId:   jobId,
+		}))
+	}
+
+	// webui run other task's job, not interrupted
+	p.cancelTask(sd.URL, "task1")
+	// job unknown
+	p.cancelTask(sd.URL, "task3")
+	select {
+	case <-interrupted:
+		t.Fatal("other job interrupted")
+	default:
+	}
+	task, err := taskStore.Get("task1", []string{datastore.KTaskStatus})
+	assert.Nil(t, err)
+	assert.Equal(t, config.TASK_CANCELLED, task[datastore.KTaskStatus])
+	// own job
+	p.cancelTask(sd.URL, "task2")
+	select {
+	case <-interrupted:
+	default:
+		t.Fatal("sd not interrupted")
+	}
+}
+
+func TestSubmitResult(t *testing.T) {
+	status, mode := submitResult(http.StatusOK)
+	assert.Equal(t, config.TASK_FINISH, status)
+	assert.Equal(t, invokeModeSync, *mode)
+	status, mode = submitResult(http.StatusAccepted)
+	assert.Equal(t, config.TASK_QUEUE, status)
+	assert.Equal(t, invokeModeAsync, *mode)
+	status, mode = submitResult(http.StatusInternalServerError)
+	assert.Equal(t, config.TASK_FAILED, status)
+	assert.Nil(t, mode)
+}
+
+func TestUpdateOptions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	initTestConfig(t)
+	userStore := datastore.NewSQLiteDatastore(datastore.NewSQLiteConfig(datastore.KUserTableName))
+	defer userStore.Close()
+	configStore := datastore.NewSQLiteDatastore(datastore.NewSQLiteConfig(datastore.KConfigTableName))
+	defer configStore.Close()
+	p := &ProxyHandler{userStore: userStore, configStore: configStore}
+	router := gin.New()
+	RegisterHandlers(router, p)
+	update := func(data string) int {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/options", bytes.NewBufferString(`{"data":`+data+`}`))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// user added by first update
+	assert.Equal(t, http.StatusOK, update(`{"index":0}`))
+	// concurrent update in same second, each claim own version
+	const count = 8
+	var wg sync.WaitGroup
+	for i := 1; i < count; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			assert.Equal(t, http.StatusOK, update(fmt.Sprintf(`{"index":%d}`, i)))
+		}(i)
+	}
+	wg.Wait()
+	configs, err := configStore.ListAll([]string{datastore.KConfigKey, datastore.KConfigVer})
+	assert.Nil(t, err)
+	assert.Len(t, configs, count)
+	user, err := userStore.Get(DEFAULT_USER, []string{datastore.KUserConfigVer})
+	assert.Nil(t, err)
+	latest := user[datastore.KUserConfigVer].(string)
+	for _, item := range configs {
+		assert.LessOrEqual(t, item[datastore.KConfigVer].(string), latest)
+	}
+	assert.Contains(t, configs, DEFAULT_USER+"_"+latest)
+
+	// concurrent first update of new user, insert loser retry by cas
+	for i := 0; i < count; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			req := httptest.NewRequest(http.MethodPost, "/options",
+				bytes.NewBufferString(fmt.Sprintf(`{"data":{"index":%d}}`, i)))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set(userKey, "u2")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, http.StatusOK, w.Code)
+		}(i)
+	}
+	wg.Wait()
+	configs, err = configStore.ListAll([]string{datastore.KConfigKey, datastore.KConfigVer})
+	assert.Nil(t, err)
+	assert.Len(t, configs, 2*count)
+	user, err = userStore.Get("u2", []string{datastore.KUserConfigVer})
+	assert.Nil(t, err)
+	// published version is the latest, not overwritten by a stale insert
+	latest = user[datastore.KUserConfigVer].(string)
+	for key, item := range configs {
+		if strings.HasPrefix(key, "u2_") {
+			assert.LessOrEqual(t, item[datastore.KConfigVer].(string), latest)
+		}
+	}
+	assert.Contains(t, configs, "u2_"+latest)
+
+	// login user not exist
+	config.ConfigGlobal.LoginSwitch = "on"
+	req := httptest.NewRequest(http.MethodPost, "/options", bytes.NewBufferString(`{"data":{}}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(userKey, "nobody")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestUpdateTaskStatus(t *testing.T) {
+	initTestConfig(t)
+	taskStore := datastore.NewSQLiteDatastore(datastore.NewSQLiteConfig(datastore.KTaskTableName))
+	defer taskStore.Close()
+	p := &ProxyHandler{taskStore: taskStore}
+	assert.Nil(t, taskStore.Put("task", map[string]interface{}{
+		datastore.KTaskIdColumnName: "task",
+		datastore.KTaskStatus:       config.TASK_QUEUE,
+	}))
+
+	updated, err := p.updateTaskStatus("task", map[string]interface{}{datastore.KTaskStatus: config.TASK_INPROGRESS})
+	assert.Nil(t, err)
+	assert.True(t, updated)
+	updated, err = p.updateTaskStatus("task", map[string]interface{}{datastore.KTaskStatus: config.TASK_CANCELLED})
+	assert.Nil(t, err)
+	assert.True(t, updated)
+	// cancelled not overwritten by late result
+	updated, err = p.updateTaskStatus("task", map[string]interface{}{datastore.KTaskStatus: config.TASK_FINISH})
+	assert.Nil(t, err)
+	assert.False(t, updated)
+	task, err := taskStore.Get("task", []string{datastore.KTaskStatus})
+	assert.Nil(t, err)
+	assert.Equal(t, config.TASK_CANCELLED, task[datastore.KTaskStatus])
+
+	// task not exist
+	updated, err = p.updateTaskStatus("none", map[string]interface{}{datastore.KTaskStatus: config.TASK_FINISH})
+	assert.Nil(t, err)
+	assert.False(t, updated)
+}
+
+func TestCheckForceTaskId(t *testing.T) {
+	initTestConfig(t)
+	taskStore := datastore.NewSQLiteDatastore(datastore.NewSQLiteConfig(datastore.KTaskTableName))
+	defer taskStore.Close()
+	p := &ProxyHandler{taskStore: taskStore}
+	for taskId, status := range map[string]string{
+		"running": config.TASK_INPROGRESS,
+		"done":    config.TASK_FINISH,
+	} {
+		assert.Nil(t, taskStore.Put(taskId, map[string]interface{}{
+			datastore.KTaskIdColumnName: taskId,
+			datastore.KTaskUser:         "user",
+			datastore.KTaskStatus:       status,
+		}))
+	}
+
+	code, err := p.checkForceTaskId("user", "new")
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, code)
+	// terminal task of same user reusable
+	code, err = p.checkForceTaskId("user", "done")
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, code)
+	// active or other user task conflict
+	code, err = p.checkForceTaskId("user", "running")
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusConflict, code)
+	code, err = p.checkForceTaskId("other", "done")
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusConflict, code)
+}
+
+func TestPutTask(t *testing.T) {
+	initTestConfig(t)
+	taskStore := datastore.NewSQLiteDatastore(datastore.NewSQLiteConfig(datastore.KTaskTableName))
+	defer taskStore.Close()
+	p := &ProxyHandler{taskStore: taskStore}
+	task := func(user string) map[string]interface{} {
+		return map[string]interface{}{
+			datastore.KTaskIdColumnName: "task",
+			datastore.KTaskUser:         user,
+			datastore.KTaskStatus:       config.TASK_QUEUE,
+		}
+	}
+
+	code, err := p.putTask("user", "task", true, task("user"))
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, code)
+	// still queued, reuse rejected
+	code, err = p.putTask("user", "task", true, task("user"))
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusConflict, code)
+
+	// concurrent reuse of finished task, only one claim it, old columns cleared
+	assert.Nil(t, taskStore.Update("task", map[string]interface{}{
+		datastore.KTaskStatus: config.TASK_FINISH,
+		datastore.KTaskImage:  "old.png",
+	}))
+	const count = 8
+	var claimed int32
+	var wg sync.WaitGroup
+	for i := 0; i < count; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			code, err := p.putTask("user", "task", true, task("user"))
+			if err == nil {
+				atomic.AddInt32(&claimed, 1)
+			} else {
+				assert.Equal(t, http.StatusConflict, code)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), claimed)
+	data, err := taskStore.Get("task", []string{datastore.KTaskStatus, datastore.KTaskImage})
+	assert.Nil(t, err)
+	assert.Equal(t, config.TASK_QUEUE, data[datastore.KTaskStatus])
+	assert.Nil(t, data[datastore.KTaskImage])
+
+	// other user
+	assert.Nil(t, taskStore.Update("task", map[string]interface{}{datastore.KTaskStatus: config.TASK_FINISH}))
+	code, _ = p.putTask("other", "task", true, task("other"))
+	assert.Equal(t, http.StatusConflict, code)
+}
+
+func TestPredictTimeout(t *testing.T) {
+	initTestConfig(t)
+	config.ConfigGlobal.PredictTimeout = 1
+	release := make(chan struct{})
+	sd := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// wedged webui
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer sd.Close()
+	defer close(release)
+	config.ConfigGlobal.SdUrlPrefix = sd.URL
+	taskStore := datastore.NewSQLiteDatastore(datastore.NewSQLiteConfig(datastore.KTaskTableName))
+	defer taskStore.Close()
+	assert.Nil(t, taskStore.Put("task", map[string]interface{}{
+		datastore.KTaskIdColumnName: "task",
+		datastore.KTaskStatus:       config.TASK_QUEUE,
+	}))
+	p := &ProxyHandler{taskStore: taskStore, httpClient: &http.Client{}}
+
+	start := time.Now()
+	_, _, err := p.predictTask("user", "task", config.TXT2IMG, []byte("{}"), predictOptions{})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "timeout")
+	assert.Less(t, time.Since(start), 5*time.Second)
+	// task not hang
+	task, err := taskStore.Get("task", []string{datastore.KTaskStatus, datastore.KTaskCode})
+	assert.Nil(t, err)
+	assert.Equal(t, config.TASK_FAILED, task[datastore.KTaskStatus])
+	assert.Equal(t, int64(requestFail), task[datastore.KTaskCode])
+}
+
+func TestTxt2ImgPredictError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	initTestConfig(t)
+	config.ConfigGlobal.ServerName = config.PROXY
+	mockOss(t, 0)
+	sd := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`{"error":"OutOfMemoryError","detail":"CUDA out of memory"}`))
+	}))
+	defer sd.Close()
+	config.ConfigGlobal.SdUrlPrefix = sd.URL
+	taskStore := datastore.NewSQLiteDatastore(datastore.NewSQLiteConfig(datastore.KTaskTableName))
+	defer taskStore.Close()
+	configStore := datastore.NewSQLiteDatastore(datastore.NewSQLiteConfig(datastore.KConfigTableName))
+	defer configStore.Close()
+	p := &ProxyHandler{taskStore: taskStore, configStore: configStore, httpClient: &http.Client{}}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/txt2img",
+		strings.NewReader(`{"stable_diffusion_model":"sd.safetensors","prompt":"cat"}`))
+	p.Txt2Img(c)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	resp := new(models.SubmitTaskResponse)
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), resp))
+	assert.Equal(t, config.TASK_FAILED, resp.Status)
+	task, err := taskStore.Get(resp.TaskId, []string{datastore.KTaskStatus, datastore.KTaskCode,
+		datastore.KTaskInfo})
+	assert.Nil(t, err)
+	assert.Equal(t, config.TASK_FAILED, task[datastore.KTaskStatus])
+	assert.Equal(t, int64(http.StatusInternalServerError), task[datastore.KTaskCode])
+	assert.Contains(t, task[datastore.KTaskInfo], "CUDA out of memory")
+	result, err := p.getTaskResult(resp.TaskId)
+	assert.Nil(t, err)
+	assert.Equal(t, config.TASK_FAILED, result.Status)
+	assert.Empty(t, *result.Images)
+}
+
+func TestPredictConcurrency(t *testing.T) {
+	initTestConfig(t)
+	config.ConfigGlobal.ImageNameTemplate = config.DefaultImageNameTemplate
+	mockOss(t, 0)
+	started := make(chan struct{}, 2)
+	release := make(chan struct{})
+	sd := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		<-release
+		json.NewEncoder(w).Encode(map[string]interface{}{"images": []string{"aW1hZ2U="}, "info": ""})
+	}))
+	defer sd.Close()
+	config.ConfigGlobal.SdUrlPrefix = sd.URL
+	taskStore := datastore.NewSQLiteDatastore(datastore.NewSQLiteConfig(datastore.KTaskTableName))
+	defer taskStore.Close()
+	p := &ProxyHandler{taskStore: taskStore, httpClient: &http.Client{},
+		predictLimit: newPredictLimiter(1, 100*time.Millisecond)}
+
+	done := make(chan error)
+	go func() {
+		_, _, err := p.predictTask("user", "task1", config.TXT2IMG, []byte("{}"), predictOptions{})
+		done <- err
+	}()
+	<-started
+	// sd busy, second wait slot timeout and not reach sd
+	_, _, err := p.predictTask("user", "task2", config.TXT2IMG, []byte("{}"), predictOptions{})
+	assert.ErrorIs(t, err, errPredictQueueTimeout)
+	assert.Equal(t, http.StatusServiceUnavailable, predictErrorCode(err))
+	assert.Len(t, started, 0)
+	close(release)
+	assert.Nil(t, <-done)
+	// slot released
+	_, _, err = p.predictTask("user", "task3", config.TXT2IMG, []byte("{}"), predictOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusInternalServerError, predictErrorCode(errors.New("predict error")))
+}
+
+func TestDistributeModel(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	initTestConfig(t)
+	config.ConfigGlobal.UseLocalModels = "no"
+	modelStore := datastore.NewSQLiteDatastore(datastore.NewSQLiteConfig(datastore.KModelTableName))
+	defer modelStore.Close()
+	for name, modelType := range map[string]string{"sd.safetensors": config.SD_MODEL,
+		"cold.safetensors": config.SD_MODEL, "lora.safetensors": config.LORA_MODEL} {
+		assert.Nil(t, modelStore.Put(name, map[string]interface{}{
+			datastore.KModelName: name,
+			datastore.KModelType: modelType,
+		}))
+	}
+	manager := &fakeEndpointManager{envResults: []module.FuncEnvResult{
+		{FunctionName: "sd-a", SdModel: "sd.safetensors"},
+		{FunctionName: "sd-b", SdModel: "other.safetensors", Err: errors.New("update fail")},
+	}}
+	mockEndpointManager(t, manager)
+	oldFunctionName := functionName
+	functionName = func(key string) string {
+		return "sd-" + strings.TrimSuffix(key, ".safetensors")
+	}
+	defer func() {
+		functionName = oldFunctionName
+	}()
+	p := &ProxyHandler{modelStore: modelStore}
+	distribute := func(modelName string) (int, models.DistributeModelResult) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/models/"+modelName+"/distribute", nil)
+		p.DistributeModel(c, modelName)
+		var resp models.DistributeModelResult
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		return w.Code, resp
+	}
+
+	// checkpoint only refresh function serve it
+	code, resp := distribute("sd.safetensors")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []models.FunctionResult{{FunctionName: "sd-a", SdModel: "sd.safetensors", Success: true}},
+		resp.Results)
+
+	// other model refresh all functions, report failed function
+	code, resp = distribute("lora.safetensors")
+	assert.Equal(t, http.StatusInternalServerError, code)
+	assert.Equal(t, 2, len(resp.Results))
+	assert.True(t, resp.Results[0].Success)
+	assert.False(t, resp.Results[1].Success)
+	assert.Equal(t, "update fail", *resp.Results[1].Message)
+	assert.Equal(t, []string{"sd.safetensors", ""}, manager.refreshes)
+
+	// checkpoint no function serve, function created instead of refresh all
+	code, resp = distribute("cold.safetensors")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []models.FunctionResult{{FunctionName: "sd-cold", SdModel: "cold.safetensors", Success: true}},
+		resp.Results)
+	assert.Equal(t, []string{"sd.safetensors", "", "cold.safetensors"}, manager.refreshes)
+	assert.Equal(t, []string{"cold.safetensors"}, manager.coldStarts)
+	// create fail
+	manager.err = errors.New("create fail")
+	code, resp = distribute("cold.safetensors")
+	assert.Equal(t, http.StatusInternalServerError, code)
+	assert.Equal(t, "create fail", *resp.Results[0].Message)
+	// cold start budget used up
+	manager.err = module.ErrColdStartBudget
+	code, _ = distribute("cold.safetensors")
+	assert.Equal(t, http.StatusTooManyRequests, code)
+	manager.err = nil
+
+	// model not registered
+	code, _ = distribute("unknown.safetensors")
+	assert.Equal(t, http.StatusNotFound, code)
+
+	// not multiFunc control
+	config.ConfigGlobal.ServerName = config.PROXY
+	code, _ = distribute("sd.safetensors")
+	assert.Equal(t, http.StatusBadRequest, code)
+}
+
+func TestExtraBatchImagesControl(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	initTestConfig(t)
+	agent := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/extra_batch_images", r.URL.Path)
+		assert.Equal(t, "u1", r.Header.Get(userKey))
+		var request models.ExtraBatchImagesRequest
+		assert.Nil(t, json.NewDecoder(r.Body).Decode(&request))
+		// oss path and output prefix left to sd function proxy
+		assert.Equal(t, "inputs/a.png", request.ImageList[0].Data)
+		assert.Equal(t, "out", *request.OutputPrefix)
+		assert.Equal(t, r.Header.Get(taskKey), *request.ForceTaskId)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"taskId":"` + *request.ForceTaskId + `","status":"succeeded"}`))
+	}))
+	defer agent.Close()
+	manager := &fakeEndpointManager{endpoints: map[string]string{"sd": agent.URL}}
+	mockEndpointManager(t, manager)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/extra_batch_images", strings.NewReader(
+		`{"image_list":[{"data":"inputs/a.png","name":"a"}],"output_prefix":"out","stable_diffusion_model":"sd"}`))
+	c.Request.Header.Set(userKey, "u1")
+	(&ProxyHandler{}).ExtraBatchImages(c)
+	assert.Equal(t, http.StatusOK, w.Code)
+	taskId := w.Header().Get("taskId")
+	assert.NotEmpty(t, taskId)
+	assert.JSONEq(t, `{"taskId":"`+taskId+`","status":"succeeded"}`, w.Body.String())
+	assert.Equal(t, []string{"sd"}, manager.coldStarts)
+}
+
+func TestInterrogateControl(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	initTestConfig(t)
+	config.ConfigGlobal.ServerName = config.CONTROL
+	agent := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/interrogate", r.URL.Path)
+		assert.Equal(t, "u1", r.Header.Get(userKey))
+		w.Write([]byte(`{"caption":"a cat"}`))
+	}))
+	defer agent.Close()
+	mockEndpointManager(t, &fakeEndpointManager{lastEndpoint: agent.URL})
+	// local webui not used
+	config.ConfigGlobal.SdUrlPrefix = "http://127.0.0.1:1"
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/interrogate", strings.NewReader(`{"image":"aaa"}`))
+	c.Request.Header.Set(userKey, "u1")
+	(&ProxyHandler{httpClient: &http.Client{}}).Interrogate(c)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"caption":"a cat"}`, w.Body.String())
+}
@@ -0,0 +1,166 @@
+package handler
+
+import (
+	"fmt"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/datastore"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/models"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/module"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/utils"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// queueSnapshotTTL progress polls of queued tasks share one task table read in ttl
+	queueSnapshotTTL = 5 * time.Second
+	// latencySamples recent finished tasks of model averaged as per task latency
+	latencySamples = 20
+	// latencyWindow finished tasks created within window read as latency samples
+	latencyWindow = time.Hour
+)
+
+var queueSnapshotColumns = []string{datastore.KTaskIdColumnName, datastore.KTaskModel, datastore.KTaskStatus,
+	datastore.KTaskCreateTime, datastore.KTaskGpuSeconds}
+
+// queuedTask unfinished task in queue order
+type queuedTask struct {
+	taskId     string
+	createTime int64
+}
+
+// queueSnapshot unfinished tasks and recent latency of each sd model
+type queueSnapshot struct {
+	at time.Time
+	// sd model -> unfinished tasks ordered by create time
+	pending map[string][]queuedTask
+	// sd model -> rendering tasks, each on one instance
+	instances map[string]int
+	// sd model -> average gpu seconds of recent finished tasks
+	latency map[string]float64
+}
+
+// queueSnapshotCache snapshot rebuilt when older than queueSnapshotTTL, nil cache read every time
+type queueSnapshotCache struct {
+	lock     sync.Mutex
+	snapshot *queueSnapshot
+}
+
+// get snapshot within ttl, read task table when expired
+func (q *queueSnapshotCache) get(taskStore datastore.Datastore) (*queueSnapshot, error) {
+	if q == nil {
+		return scanQueueSnapshot(taskStore)
+	}
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	if q.snapshot != nil && time.Since(q.snapshot.at) < queueSnapshotTTL {
+		return q.snapshot, nil
+	}
+	snapshot, err := scanQueueSnapshot(taskStore)
+	if err != nil {
+		return nil, err
+	}
+	q.snapshot = snapshot
+	return snapshot, nil
+}
+
+// scanQueueSnapshot group unfinished tasks(not stale) and latency of tasks finished within latencyWindow
+// by sd model, read by status and create time index instead of whole table
+func scanQueueSnapshot(taskStore datastore.Datastore) (*queueSnapshot, error) {
+	type sample struct {
+		createTime int64
+		seconds    float64
+	}
+	active, err := listActiveTasks(taskStore)
+	if err != nil {
+		return nil, err
+	}
+	pending := make(map[string][]queuedTask)
+	instances := make(map[string]int)
+	for taskId, row := range active {
+		sdModel, _ := row[datastore.KTaskModel].(string)
+		createTime := parseTaskTime(row[datastore.KTaskCreateTime])
+		pending[sdModel] = append(pending[sdModel], queuedTask{taskId: taskId, createTime: *createTime})
+		if row[datastore.KTaskStatus] == config.TASK_INPROGRESS {
+			instances[sdModel]++
+		}
+	}
+	since := fmt.Sprintf("%d", utils.TimestampS()-int64(latencyWindow/time.Second))
+	recentRows, err := taskStore.ListIndexRange(datastore.KTaskCreateTime, since, "", queueSnapshotColumns)
+	if err != nil {
+		return nil, err
+	}
+	samples := make(map[string][]sample)
+	for _, row := range recentRows {
+		status, _ := row[datastore.KTaskStatus].(string)
+		seconds, ok := row[datastore.KTaskGpuSeconds].(float64)
+		if !module.IsTaskTerminal(status) || !ok || seconds <= 0 {
+			continue
+		}
+		sdModel, _ := row[datastore.KTaskModel].(string)
+		createStr, _ := row[datastore.KTaskCreateTime].(string)
+		createTime, _ := strconv.ParseInt(createStr, 10, 64)
+		samples[sdModel] = append(samples[sdModel], sample{createTime: createTime, seconds: seconds})
+	}
+	for _, tasks := range pending {
+		sort.Slice(tasks, func(i, j int) bool {
+			if tasks[i].createTime != tasks[j].createTime {
+				return tasks[i].createTime < tasks[j].createTime
+			}
+			return tasks[i].taskId < tasks[j].taskId
+		})
+	}
+	latency := make(map[string]float64, len(samples))
+	for sdModel, recent := range samples {
+		sort.Slice(recent, func(i, j int) bool {
+			return recent[i].createTime > recent[j].createTime
+		})
+		if len(recent) > latencySamples {
+			recent = recent[:latencySamples]
+		}
+		total := 0.0
+		for _, one := range recent {
+			total += one.seconds
+		}
+		latency[sdModel] = total / float64(len(recent))
+	}
+	return &queueSnapshot{at: time.Now(), pending: pending, instances: instances, latency: latency}, nil
+}
+
+// position tasks of sd model ahead of task, task not in snapshot(submitted after scan) behind all
+func (s *queueSnapshot) position(sdModel, taskId string) int {
+	tasks := s.pending[sdModel]
+	for i, task := range tasks {
+		if task.taskId == taskId {
+			return i
+		}
+	}
+	return len(tasks)
+}
+
+// fillQueueEstimate queue position and estimated start time of queued task not started rendering,
+// start time unknown when sd model has no finished task
+func (p *ProxyHandler) fillQueueEstimate(sdModel, taskId string, resp *models.TaskProgressResponse) error {
+	snapshot, err := p.queueSnapshots.get(p.taskStore)
+	if err != nil {
+		return err
+	}
+	position := snapshot.position(sdModel, taskId)
+	resp.QueuePosition = utils.Int(position)
+	if latency, ok := snapshot.latency[sdModel]; ok {
+		// tasks ahead rendered by serving instances in parallel
+		instances := snapshot.instances[sdModel]
+		if instances < 1 {
+			instances = 1
+		}
+		wait := float64(position) * latency / float64(instances)
+		start := snapshot.at.Add(time.Duration(wait * float64(time.Second)))
+		if now := time.Now(); start.Before(now) {
+			start = now
+		}
+		resp.EstimatedStartTime = utils.Int64(start.Unix())
+	}
+	return nil
+}
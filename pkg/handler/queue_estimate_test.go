@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/datastore"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/models"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetTaskProgressQueued(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	initTestConfig(t)
+	taskStore := datastore.NewSQLiteDatastore(datastore.NewSQLiteConfig(datastore.KTaskTableName))
+	defer taskStore.Close()
+	p := &ProxyHandler{taskStore: taskStore, queueSnapshots: new(queueSnapshotCache)}
+	putTask := func(taskId, sdModel, status string, createTime int64, gpuSeconds float64) {
+		data := map[string]interface{}{
+			datastore.KTaskIdColumnName: taskId,
+			datastore.KTaskModel:        sdModel,
+			datastore.KTaskStatus:       status,
+			datastore.KTaskCreateTime:   fmt.Sprintf("%d", createTime),
+		}
+		if gpuSeconds > 0 {
+			data[datastore.KTaskGpuSeconds] = gpuSeconds
+		}
+		assert.Nil(t, taskStore.Put(taskId, data))
+	}
+	base := time.Now().Unix() - 600
+	// history of model a: 10s per task, newer samples first, tasks out of latency window not read
+	putTask("done1", "a", config.TASK_FINISH, base+1, 100)
+	putTask("old", "a", config.TASK_FINISH, base-int64(latencyWindow.Seconds()), 1000)
+	for i := 0; i < latencySamples; i++ {
+		putTask(fmt.Sprintf("done%d", i+2), "a", config.TASK_FINISH, base+int64(10+i), 10)
+	}
+	// running task and queued tasks in create order, other model and stale task not counted
+	putTask("running", "a", config.TASK_INPROGRESS, base+100, 0)
+	putTask("queued1", "a", config.TASK_QUEUE, base+101, 0)
+	putTask("queued2", "a", config.TASK_QUEUE, base+102, 0)
+	putTask("other", "b", config.TASK_QUEUE, base+50, 0)
+	putTask("lost", "a", config.TASK_QUEUE, base-int64(staleTaskAge.Seconds()), 0)
+	getProgress := func(taskId string) *models.TaskProgressResponse {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		p.GetTaskProgress(c, taskId)
+		assert.Equal(t, http.StatusOK, w.Code)
+		resp := new(models.TaskProgressResponse)
+		assert.Nil(t, json.Unmarshal(w.Body.Bytes(), resp))
+		return resp
+	}
+
+	now := time.Now().Unix()
+	resp := getProgress("queued2")
+	assert.Equal(t, 2, *resp.QueuePosition)
+	assert.InDelta(t, now+20, *resp.EstimatedStartTime, 2)
+	resp = getProgress("queued1")
+	assert.Equal(t, 1, *resp.QueuePosition)
+	assert.InDelta(t, now+10, *resp.EstimatedStartTime, 2)
+	// no history, position only
+	resp = getProgress("other")
+	assert.Equal(t, 0, *resp.QueuePosition)
+	assert.Nil(t, resp.EstimatedStartTime)
+
+	// tasks ahead shared by serving instances
+	putTask("running2", "a", config.TASK_INPROGRESS, base+100, 0)
+	p.queueSnapshots = new(queueSnapshotCache)
+	resp = getProgress("queued2")
+	assert.Equal(t, 3, *resp.QueuePosition)
+	assert.InDelta(t, now+15, *resp.EstimatedStartTime, 2)
+
+	// rendering, webui progress take over
+	assert.Nil(t, p.writeTaskProgress("queued1", &models.TaskProgressResponse{Progress: 0.3}))
+	resp = getProgress("queued1")
+	assert.Equal(t, float32(0.3), resp.Progress)
+	assert.Nil(t, resp.QueuePosition)
+	assert.Nil(t, resp.EstimatedStartTime)
+	resp = getProgress("running")
+	assert.Nil(t, resp.QueuePosition)
+}
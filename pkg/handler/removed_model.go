@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/datastore"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/models"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"net/http"
+)
+
+// errModelRemoved task model deleted while task queued and no fallback
+var errModelRemoved = errors.New("model removed")
+
+// modelRemoved sd model file gone or model deleted by DeleteModel
+func (p *ProxyHandler) modelRemoved(sdModel string) bool {
+	if !p.checkModelExist(sdModel) {
+		return true
+	}
+	if p.modelStore == nil || config.ConfigGlobal.UseLocalModel() {
+		return false
+	}
+	data, err := p.modelStore.Get(sdModel, []string{datastore.KModelStatus})
+	return err == nil && data[datastore.KModelStatus] == config.MODEL_DELETE
+}
+
+// resolveRemovedModel check task model again before predict, model deleted while queued:
+// render with deletedModelFallback if set, otherwise mark task failed and return errModelRemoved.
+// model rendered returned with body
+func (p *ProxyHandler) resolveRemovedModel(taskId, sdModel string, body []byte) ([]byte, string, error) {
+	if !p.modelRemoved(sdModel) {
+		return body, sdModel, nil
+	}
+	fallback := config.ConfigGlobal.DeletedModelFallback
+	if fallback == "" || fallback == sdModel || p.modelRemoved(fallback) {
+		return nil, "", p.failRemovedModel(taskId, sdModel)
+	}
+	request := make(map[string]interface{})
+	if err := json.Unmarshal(body, &request); err != nil {
+		return nil, "", err
+	}
+	request["stable_diffusion_model"] = fallback
+	if settings, ok := request["override_settings"].(map[string]interface{}); ok {
+		settings["sd_model_checkpoint"] = fallback
+	}
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, "", err
+	}
+	logrus.WithFields(logrus.Fields{"taskId": taskId}).Warnf("model %s removed, fallback to %s", sdModel, fallback)
+	if taskId != "" {
+		if err := p.taskStore.Update(taskId, map[string]interface{}{datastore.KTaskModel: fallback}); err != nil {
+			logrus.WithFields(logrus.Fields{"taskId": taskId}).Warnf("update task model err=%s", err.Error())
+		}
+	}
+	return body, fallback, nil
+}
+
+// failRemovedModel mark task failed by removed model, return errModelRemoved
+func (p *ProxyHandler) failRemovedModel(taskId, sdModel string) error {
+	err := fmt.Errorf("%w: %s", errModelRemoved, sdModel)
+	if taskId == "" {
+		return err
+	}
+	if _, updateErr := p.updateTaskStatus(taskId, map[string]interface{}{
+		datastore.KTaskCode:       int64(http.StatusGone),
+		datastore.KTaskStatus:     config.TASK_FAILED,
+		datastore.KTaskInfo:       err.Error(),
+		datastore.KTaskModifyTime: fmt.Sprintf("%d", utils.TimestampS()),
+	}); updateErr != nil {
+		logrus.WithFields(logrus.Fields{"taskId": taskId}).Errorf("update task err=%s", updateErr.Error())
+	}
+	return err
+}
+
+// replyRemovedModel reply task failed by removed model or resolve error
+func replyRemovedModel(c *gin.Context, taskId string, err error) {
+	code := http.StatusInternalServerError
+	if errors.Is(err, errModelRemoved) {
+		code = http.StatusGone
+	}
+	c.JSON(code, models.SubmitTaskResponse{
+		TaskId:  taskId,
+		Status:  config.TASK_FAILED,
+		Message: utils.String(err.Error()),
+	})
+}
+
+// resolveForwardRequest resolveRemovedModel before request forwarded downstream, async request may wait long
+// in fc queue. request model replaced by fallback, reply and return false when task failed
+func (p *ProxyHandler) resolveForwardRequest(c *gin.Context, taskId, sdModel string, request interface{}) (string,
+	bool) {
+	body, err := json.Marshal(request)
+	if err != nil {
+		replyRemovedModel(c, taskId, err)
+		return "", false
+	}
+	body, model, err := p.resolveRemovedModel(taskId, sdModel, body)
+	if err == nil && model != sdModel {
+		err = json.Unmarshal(body, request)
+	}
+	if err != nil {
+		replyRemovedModel(c, taskId, err)
+		return "", false
+	}
+	return model, true
+}
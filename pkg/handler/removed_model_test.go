@@ -0,0 +1,174 @@
+package handler
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/datastore"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveRemovedModel(t *testing.T) {
+	initTestConfig(t)
+	config.ConfigGlobal.ImageNameTemplate = config.DefaultImageNameTemplate
+	mockOss(t, 0)
+	rendered := ""
+	sd := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request := make(map[string]interface{})
+		assert.Nil(t, json.NewDecoder(r.Body).Decode(&request))
+		rendered = request["override_settings"].(map[string]interface{})["sd_model_checkpoint"].(string)
+		assert.Equal(t, rendered, request["stable_diffusion_model"])
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"images": []string{base64.StdEncoding.EncodeToString([]byte("image"))}, "info": "{}"})
+	}))
+	defer sd.Close()
+	config.ConfigGlobal.SdUrlPrefix = sd.URL
+	taskStore := datastore.NewSQLiteDatastore(datastore.NewSQLiteConfig(datastore.KTaskTableName))
+	defer taskStore.Close()
+	modelStore := datastore.NewSQLiteDatastore(datastore.NewSQLiteConfig(datastore.KModelTableName))
+	defer modelStore.Close()
+	p := &ProxyHandler{taskStore: taskStore, modelStore: modelStore, httpClient: &http.Client{}}
+	for name, status := range map[string]string{
+		"live.safetensors":    config.MODEL_LOADED,
+		"deleted.safetensors": config.MODEL_DELETE,
+	} {
+		assert.Nil(t, modelStore.Put(name, map[string]interface{}{
+			datastore.KModelName:   name,
+			datastore.KModelStatus: status,
+		}))
+	}
+	predict := func(taskId, sdModel string) error {
+		assert.Nil(t, taskStore.Put(taskId, map[string]interface{}{
+			datastore.KTaskIdColumnName: taskId,
+			datastore.KTaskStatus:       config.TASK_QUEUE,
+			datastore.KTaskModel:        sdModel,
+		}))
+		body, _ := json.Marshal(map[string]interface{}{
+			"stable_diffusion_model": sdModel,
+			"override_settings":      map[string]interface{}{"sd_model_checkpoint": sdModel},
+		})
+		_, _, err := p.predictTask("user", taskId, config.TXT2IMG, body, predictOptions{sdModel: sdModel})
+		return err
+	}
+
+	assert.Nil(t, predict("live", "live.safetensors"))
+	assert.Equal(t, "live.safetensors", rendered)
+
+	// fail fast
+	rendered = ""
+	err := predict("fail", "deleted.safetensors")
+	assert.ErrorIs(t, err, errModelRemoved)
+	assert.Equal(t, http.StatusGone, predictErrorCode(err))
+	assert.Empty(t, rendered)
+	task, err := taskStore.Get("fail", []string{datastore.KTaskStatus, datastore.KTaskCode})
+	assert.Nil(t, err)
+	assert.Equal(t, config.TASK_FAILED, task[datastore.KTaskStatus])
+	assert.Equal(t, int64(http.StatusGone), task[datastore.KTaskCode])
+
+	// fallback
+	config.ConfigGlobal.DeletedModelFallback = "live.safetensors"
+	assert.Nil(t, predict("fallback", "deleted.safetensors"))
+	assert.Equal(t, "live.safetensors", rendered)
+	task, err = taskStore.Get("fallback", []string{datastore.KTaskStatus, datastore.KTaskModel})
+	assert.Nil(t, err)
+	assert.Equal(t, config.TASK_FINISH, task[datastore.KTaskStatus])
+	assert.Equal(t, "live.safetensors", task[datastore.KTaskModel])
+
+	// fallback removed too
+	config.ConfigGlobal.DeletedModelFallback = "deleted.safetensors"
+	assert.ErrorIs(t, predict("both", "deleted.safetensors"), errModelRemoved)
+}
+
+func TestForwardRemovedModel(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	initTestConfig(t)
+	config.ConfigGlobal.UseLocalModels = "no"
+	var lock sync.Mutex
+	forwarded := make(map[string]interface{})
+	agent := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == config.PROGRESS {
+			return
+		}
+		lock.Lock()
+		defer lock.Unlock()
+		forwarded = make(map[string]interface{})
+		json.NewDecoder(r.Body).Decode(&forwarded)
+		w.Write([]byte(`{}`))
+	}))
+	defer agent.Close()
+	mockEndpointManager(t, &fakeEndpointManager{endpoints: map[string]string{"live.safetensors": agent.URL}})
+	taskStore := datastore.NewSQLiteDatastore(datastore.NewSQLiteConfig(datastore.KTaskTableName))
+	defer taskStore.Close()
+	modelStore := datastore.NewSQLiteDatastore(datastore.NewSQLiteConfig(datastore.KModelTableName))
+	defer modelStore.Close()
+	for name, status := range map[string]string{
+		"live.safetensors":    config.MODEL_LOADED,
+		"deleted.safetensors": config.MODEL_DELETE,
+	} {
+		assert.Nil(t, modelStore.Put(name, map[string]interface{}{
+			datastore.KModelName:   name,
+			datastore.KModelStatus: status,
+		}))
+	}
+	configStore := datastore.NewSQLiteDatastore(datastore.NewSQLiteConfig(datastore.KConfigTableName))
+	defer configStore.Close()
+	p := &ProxyHandler{taskStore: taskStore, modelStore: modelStore, configStore: configStore,
+		httpClient: &http.Client{}}
+	router := gin.New()
+	RegisterHandlers(router, p)
+	router.NoRoute(p.NoRouterHandler)
+	forward := func(path, taskId, body string) int {
+		// task queued by proxy before forwarded
+		assert.Nil(t, taskStore.Put(taskId, map[string]interface{}{
+			datastore.KTaskIdColumnName: taskId,
+			datastore.KTaskStatus:       config.TASK_QUEUE,
+		}))
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(taskKey, taskId)
+		req.Header.Set(taskFlagKey, "true")
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+	forwardedModel := func() interface{} {
+		lock.Lock()
+		defer lock.Unlock()
+		return forwarded["stable_diffusion_model"]
+	}
+
+	// no fallback, task failed before forward
+	for path, body := range map[string]string{
+		"/img2img":             `{"stable_diffusion_model":"deleted.safetensors","init_images":["aaa"]}`,
+		"/extra_images":        `{"stable_diffusion_model":"deleted.safetensors","image":"aaa"}`,
+		"/sdapi/v1/extensions": `{"StableDiffusionModel":"deleted.safetensors"}`,
+	} {
+		assert.Equal(t, http.StatusGone, forward(path, "fail", body), path)
+		task, err := taskStore.Get("fail", []string{datastore.KTaskStatus})
+		assert.Nil(t, err)
+		assert.Equal(t, config.TASK_FAILED, task[datastore.KTaskStatus], path)
+	}
+	assert.Nil(t, forwardedModel())
+
+	// fallback forwarded to fallback model function
+	config.ConfigGlobal.DeletedModelFallback = "live.safetensors"
+	assert.Equal(t, http.StatusOK, forward("/extra_images", "extra",
+		`{"stable_diffusion_model":"deleted.safetensors","image":"aaa"}`))
+	assert.Equal(t, "live.safetensors", forwardedModel())
+	assert.Equal(t, http.StatusOK, forward("/img2img", "img2img",
+		`{"stable_diffusion_model":"deleted.safetensors","init_images":["aaa"]}`))
+	assert.Equal(t, "live.safetensors", forwardedModel())
+	task, err := taskStore.Get("img2img", []string{datastore.KTaskModel})
+	assert.Nil(t, err)
+	assert.Equal(t, "live.safetensors", task[datastore.KTaskModel])
+	// passthrough body model field unknown, not fall back
+	assert.Equal(t, http.StatusGone, forward("/sdapi/v1/extensions", "passthrough",
+		`{"StableDiffusionModel":"deleted.safetensors"}`))
+}
@@ -0,0 +1,173 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/datastore"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/models"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/module"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"strings"
+)
+
+const (
+	renderCachePrefix = "renderCache"
+	// request opt in reuse identical render result
+	renderCacheKey = "X-Render-Cache"
+	randomSeed     = -1
+)
+
+// renderCacheItem hash -> task which render it
+type renderCacheItem struct {
+	TaskId     string `json:"taskId"`
+	CreateTime int64  `json:"createTime"`
+}
+
+func renderCacheStoreKey(hash string) string {
+	return fmt.Sprintf("%s_%s", renderCachePrefix, hash)
+}
+
+// renderCacheHash hash normalized predict request, return "" when not cacheable:
+// not opt in, or seed/subseed random. user config of configVer merged into override settings later,
+// so user and configVer part of hash
+func renderCacheHash(c *gin.Context, path, username, configVer string, request interface{}) string {
+	if c.GetHeader(renderCacheKey) != "true" {
+		return ""
+	}
+	return requestHash(fmt.Sprintf("%s\n%s\n%s", path, username, configVer), request)
+}
+
+// requestHash hash normalized predict request, return "" when seed/subseed random
+func requestHash(path string, request interface{}) string {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return ""
+	}
+	normalized := make(map[string]interface{})
+	if err := json.Unmarshal(body, &normalized); err != nil {
+		return ""
+	}
+	if !fixedSeed(normalized["seed"]) {
+		return ""
+	}
+	if strength, ok := normalized["subseed_strength"].(float64); ok && strength > 0 &&
+		!fixedSeed(normalized["subseed"]) {
+		return ""
+	}
+	// task id differ every request
+	delete(normalized, "force_task_id")
+	// map keys marshal in order
+	if body, err = json.Marshal(normalized); err != nil {
+		return ""
+	}
+	hash := sha256.Sum256(append([]byte(path), body...))
+	return hex.EncodeToString(hash[:])
+}
+
+func fixedSeed(seed interface{}) bool {
+	val, ok := seed.(float64)
+	return ok && val != randomSeed
+}
+
+// getRenderCache return task which finished identical render in ttl, "" if not found
+func (p *ProxyHandler) getRenderCache(hash string) string {
+	key := renderCacheStoreKey(hash)
+	data, err := p.configStore.Get(key, []string{datastore.KConfigVal})
+	if err != nil || len(data) == 0 {
+		return ""
+	}
+	val, _ := data[datastore.KConfigVal].(string)
+	item := new(renderCacheItem)
+	if err := json.Unmarshal([]byte(val), item); err != nil || item.TaskId == "" {
+		return ""
+	}
+	if utils.TimestampS()-item.CreateTime > int64(config.ConfigGlobal.RenderCacheTTL) {
+		p.configStore.Delete(key)
+		return ""
+	}
+	task, err := p.taskStore.Get(item.TaskId, []string{datastore.KTaskStatus, datastore.KTaskCode,
+		datastore.KTaskImagesExpired})
+	if err != nil || task[datastore.KTaskStatus] != config.TASK_FINISH || imagesExpired(task) {
+		return ""
+	}
+	if code, ok := task[datastore.KTaskCode].(int64); !ok || code != requestOk {
+		return ""
+	}
+	return item.TaskId
+}
+
+// putRenderCache record hash -> taskId, keep existed item still valid
+func (p *ProxyHandler) putRenderCache(hash, taskId string) {
+	if p.getRenderCache(hash) != "" {
+		return
+	}
+	val, _ := json.Marshal(renderCacheItem{TaskId: taskId, CreateTime: utils.TimestampS()})
+	if err := p.configStore.Put(renderCacheStoreKey(hash), map[string]interface{}{
+		datastore.KConfigVal:        string(val),
+		datastore.KConfigModifyTime: fmt.Sprintf("%d", utils.TimestampS()),
+	}); err != nil {
+		logrus.WithFields(logrus.Fields{"taskId": taskId}).Warnf("put render cache err=%s", err.Error())
+	}
+}
+
+// replyRenderCache copy identical finished task result to new task with its own labels and reply
+// return false when cache miss, caller render as usual
+func (p *ProxyHandler) replyRenderCache(c *gin.Context, username, taskId, hash, labels string) bool {
+	srcTaskId := p.getRenderCache(hash)
+	if srcTaskId == "" {
+		return false
+	}
+	src, err := p.taskStore.Get(srcTaskId, []string{datastore.KTaskImage, datastore.KTaskParams,
+		datastore.KTaskInfo, datastore.KTaskEffectiveSettings, datastore.KTaskModel, datastore.KTaskRequest,
+		datastore.KTaskStatus})
+	if err != nil || len(src) == 0 {
+		return false
+	}
+	// partial upload miss images of batch, render again
+	image, _ := src[datastore.KTaskImage].(string)
+	if image == "" || src[datastore.KTaskStatus] == config.TASK_PARTIAL_UPLOAD {
+		return false
+	}
+	now := fmt.Sprintf("%d", utils.TimestampS())
+	// no gpu used, gpu seconds 0
+	task := map[string]interface{}{
+		datastore.KTaskIdColumnName: taskId,
+		datastore.KTaskUser:         username,
+		datastore.KTaskStatus:       config.TASK_FINISH,
+		datastore.KTaskCode:         int64(requestOk),
+		datastore.KTaskCancel:       int64(config.CANCEL_INIT),
+		datastore.KTaskImage:        image,
+		datastore.KTaskGpuSeconds:   float64(0),
+		datastore.KTaskCreateTime:   now,
+		datastore.KTaskModifyTime:   now,
+		datastore.KTaskLabels:       labels,
+	}
+	for _, key := range []string{datastore.KTaskParams, datastore.KTaskInfo, datastore.KTaskEffectiveSettings,
+		datastore.KTaskModel, datastore.KTaskRequest} {
+		if val, ok := src[key].(string); ok {
+			task[key] = val
+		}
+	}
+	if err := p.taskStore.Put(taskId, task); err != nil {
+		logrus.WithFields(logrus.Fields{"taskId": taskId}).Warnf("put render cache task err=%s", err.Error())
+		return false
+	}
+	logrus.WithFields(logrus.Fields{"taskId": taskId}).Infof("reuse identical render of task %s", srcTaskId)
+	images := strings.Split(image, ",")
+	if ossUrl, err := module.OssGlobal.GetUrl(images); err == nil {
+		images = ossUrl
+	}
+	c.JSON(http.StatusOK, models.SubmitTaskResponse{
+		TaskId:     taskId,
+		Status:     config.TASK_FINISH,
+		OssUrl:     &images,
+		InvokeMode: utils.String(invokeModeSync),
+	})
+	return true
+}
@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/datastore"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/models"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func renderCacheContext(optIn bool) (*gin.Context, *httptest.ResponseRecorder) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/txt2img", nil)
+	if optIn {
+		c.Request.Header.Set(renderCacheKey, "true")
+	}
+	return c, w
+}
+
+func TestRenderCacheHash(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	seed := int64(42)
+	request := &models.Txt2ImgRequest{StableDiffusionModel: "sd", Prompt: utils.String("cat"), Seed: &seed,
+		ForceTaskId: "task1"}
+
+	c, _ := renderCacheContext(false)
+	assert.Empty(t, renderCacheHash(c, config.TXT2IMG, "user", "1", request))
+
+	c, _ = renderCacheContext(true)
+	hash := renderCacheHash(c, config.TXT2IMG, "user", "1", request)
+	assert.NotEmpty(t, hash)
+
+	// task id ignored
+	request.ForceTaskId = "task2"
+	assert.Equal(t, hash, renderCacheHash(c, config.TXT2IMG, "user", "1", request))
+	// path, user config and params matter
+	assert.NotEqual(t, hash, renderCacheHash(c, config.IMG2IMG, "user", "1", request))
+	assert.NotEqual(t, hash, renderCacheHash(c, config.TXT2IMG, "other", "1", request))
+	assert.NotEqual(t, hash, renderCacheHash(c, config.TXT2IMG, "user", "2", request))
+	request.Prompt = utils.String("dog")
+	assert.NotEqual(t, hash, renderCacheHash(c, config.TXT2IMG, "user", "1", request))
+
+	// random seed bypass
+	seed = randomSeed
+	assert.Empty(t, renderCacheHash(c, config.TXT2IMG, "user", "1", request))
+	request.Seed = nil
+	assert.Empty(t, renderCacheHash(c, config.TXT2IMG, "user", "1", request))
+
+	// random subseed bypass
+	seed = 42
+	request.Seed = &seed
+	strength := float32(0.5)
+	request.SubseedStrength = &strength
+	assert.Empty(t, renderCacheHash(c, config.TXT2IMG, "user", "1", request))
+}
+
+func TestReplyRenderCache(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	initTestConfig(t)
+	config.ConfigGlobal.RenderCacheTTL = config.DefaultRenderCacheTTL
+	mockOss(t, 0)
+	configStore := datastore.NewSQLiteDatastore(datastore.NewSQLiteConfig(datastore.KConfigTableName))
+	defer configStore.Close()
+	taskStore := datastore.NewSQLiteDatastore(datastore.NewSQLiteConfig(datastore.KTaskTableName))
+	defer taskStore.Close()
+	p := &ProxyHandler{configStore: configStore, taskStore: taskStore}
+
+	assert.Nil(t, taskStore.Put("src", map[string]interface{}{
+		datastore.KTaskIdColumnName: "src",
+		datastore.KTaskUser:         "alice",
+		datastore.KTaskStatus:       config.TASK_INPROGRESS,
+	}))
+	p.putRenderCache("hash", "src")
+
+	// identical task still running, render as usual
+	c, _ := renderCacheContext(true)
+	assert.False(t, p.replyRenderCache(c, "bob", "dst", "hash", ""))
+
+	assert.Nil(t, taskStore.Update("src", map[string]interface{}{
+		datastore.KTaskStatus: config.TASK_FINISH,
+		datastore.KTaskCode:   int64(requestOk),
+		datastore.KTaskImage:  "images/a.png,images/b.png",
+		datastore.KTaskParams: "{}",
+		datastore.KTaskInfo:   "{}",
+	}))
+	c, w := renderCacheContext(true)
+	assert.True(t, p.replyRenderCache(c, "bob", "dst", "hash", `{"jobId":"j1"}`))
+	var resp models.SubmitTaskResponse
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "dst", resp.TaskId)
+	assert.Equal(t, config.TASK_FINISH, resp.Status)
+	assert.Equal(t, []string{"http://oss/images/a.png", "http://oss/images/b.png"}, *resp.OssUrl)
+
+	// new task belong to bob, no gpu seconds
+	task, err := taskStore.Get("dst", []string{datastore.KTaskUser, datastore.KTaskImage,
+		datastore.KTaskGpuSeconds, datastore.KTaskLabels})
+	assert.Nil(t, err)
+	assert.Equal(t, "bob", task[datastore.KTaskUser])
+	assert.Equal(t, map[string]string{"jobId": "j1"}, *taskLabels(task))
+	assert.Equal(t, "images/a.png,images/b.png", task[datastore.KTaskImage])
+	assert.Equal(t, float64(0), task[datastore.KTaskGpuSeconds])
+
+	// expired
+	config.ConfigGlobal.RenderCacheTTL = -1
+	c, _ = renderCacheContext(true)
+	assert.False(t, p.replyRenderCache(c, "bob", "dst2", "hash", ""))
+}
@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"fmt"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+)
+
+// applyRequestLimit check steps(hr steps)/cfg_scale against request limit of user before marshal,
+// clamp mode clamp params over limit and return notes, reject mode return error, nil param skipped
+func applyRequestLimit(user string, cfgScale *float32, steps ...*int64) ([]string, error) {
+	limit := config.ConfigGlobal.GetRequestLimit(user)
+	if limit == nil {
+		return nil, nil
+	}
+	notes := make([]string, 0)
+	if limit.MaxSteps > 0 {
+		for i, val := range steps {
+			if val == nil || *val <= limit.MaxSteps {
+				continue
+			}
+			name := "steps"
+			if i > 0 {
+				name = "hr_second_pass_steps"
+			}
+			if limit.IsReject() {
+				return nil, fmt.Errorf("%s %d exceed limit %d", name, *val, limit.MaxSteps)
+			}
+			notes = append(notes, fmt.Sprintf("%s %d -> %d", name, *val, limit.MaxSteps))
+			*val = limit.MaxSteps
+		}
+	}
+	if limit.MaxCfgScale > 0 && cfgScale != nil && *cfgScale > limit.MaxCfgScale {
+		if limit.IsReject() {
+			return nil, fmt.Errorf("cfg_scale %g exceed limit %g", *cfgScale, limit.MaxCfgScale)
+		}
+		notes = append(notes, fmt.Sprintf("cfg_scale %g -> %g", *cfgScale, limit.MaxCfgScale))
+		*cfgScale = limit.MaxCfgScale
+	}
+	if len(notes) == 0 {
+		return nil, nil
+	}
+	return notes, nil
+}
+
+// clampedNotes response field of clamp notes, nil when nothing clamped
+func clampedNotes(notes []string) *[]string {
+	if len(notes) == 0 {
+		return nil
+	}
+	return &notes
+}
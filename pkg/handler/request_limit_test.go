@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/datastore"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/models"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyRequestLimit(t *testing.T) {
+	initTestConfig(t)
+	steps, hrSteps, cfgScale := utils.Int64(80), utils.Int64(30), utils.Float32(20)
+	// no limit
+	notes, err := applyRequestLimit("user", cfgScale, steps, hrSteps)
+	assert.Nil(t, err)
+	assert.Nil(t, notes)
+	assert.Equal(t, int64(80), *steps)
+
+	config.ConfigGlobal.RequestLimits = map[string]*config.RequestLimit{
+		config.AllUsers: {MaxSteps: 50, MaxCfgScale: 15},
+		"strict":        {MaxSteps: 50, Mode: config.RequestLimitReject},
+		"admin":         nil,
+	}
+	notes, err = applyRequestLimit("user", cfgScale, steps, hrSteps, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"steps 80 -> 50", "cfg_scale 20 -> 15"}, notes)
+	assert.Equal(t, int64(50), *steps)
+	assert.Equal(t, int64(30), *hrSteps)
+	assert.Equal(t, float32(15), *cfgScale)
+	// within limit or not set
+	notes, err = applyRequestLimit("user", nil, steps, nil)
+	assert.Nil(t, err)
+	assert.Nil(t, notes)
+
+	// user setting cover "*"
+	_, err = applyRequestLimit("strict", utils.Float32(20), utils.Int64(20), utils.Int64(60))
+	assert.EqualError(t, err, "hr_second_pass_steps 60 exceed limit 50")
+	notes, err = applyRequestLimit("admin", utils.Float32(20), utils.Int64(80))
+	assert.Nil(t, err)
+	assert.Nil(t, notes)
+}
+
+func TestTxt2ImgRequestLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	initTestConfig(t)
+	config.ConfigGlobal.ServerName = config.PROXY
+	config.ConfigGlobal.ImageNameTemplate = config.DefaultImageNameTemplate
+	config.ConfigGlobal.RequestLimits = map[string]*config.RequestLimit{
+		config.AllUsers: {MaxSteps: 50},
+		"strict":        {MaxSteps: 50, Mode: config.RequestLimitReject},
+	}
+	mockOss(t, 0)
+	var rendered models.Txt2ImgRequest
+	sd := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Nil(t, json.NewDecoder(r.Body).Decode(&rendered))
+		w.Write([]byte(`{"images":["aW1hZ2U="],"info":"{}"}`))
+	}))
+	defer sd.Close()
+	config.ConfigGlobal.SdUrlPrefix = sd.URL
+	taskStore := datastore.NewSQLiteDatastore(datastore.NewSQLiteConfig(datastore.KTaskTableName))
+	defer taskStore.Close()
+	configStore := datastore.NewSQLiteDatastore(datastore.NewSQLiteConfig(datastore.KConfigTableName))
+	defer configStore.Close()
+	p := &ProxyHandler{taskStore: taskStore, configStore: configStore, httpClient: &http.Client{}}
+	submit := func(user string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/txt2img",
+			strings.NewReader(`{"stable_diffusion_model":"sd.safetensors","prompt":"cat","steps":80}`))
+		c.Request.Header.Set(userKey, user)
+		p.Txt2Img(c)
+		return w
+	}
+
+	w := submit("user")
+	assert.Equal(t, http.StatusOK, w.Code)
+	resp := new(models.SubmitTaskResponse)
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), resp))
+	assert.Equal(t, []string{"steps 80 -> 50"}, *resp.Clamped)
+	assert.Equal(t, int64(50), *rendered.Steps)
+
+	w = submit("strict")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "steps 80 exceed limit 50")
+}
@@ -0,0 +1,199 @@
+package handler
+
+import (
+	"fmt"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/datastore"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/module"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"strings"
+	"sync"
+	"time"
+)
+
+var retentionColumns = []string{datastore.KTaskIdColumnName, datastore.KTaskStatus, datastore.KTaskCreateTime,
+	datastore.KTaskModifyTime, datastore.KTaskImage, datastore.KTaskImagesExpired}
+
+// janitorLeaseKey config store row of instance running janitor, val holder id, modify time renew time
+const janitorLeaseKey = "janitorLease"
+
+// janitor purge images and rows of terminal tasks older than imageRetentionDays/taskRetentionDays
+type janitor struct {
+	// lease holder id of this instance
+	id   string
+	stop chan struct{}
+	once sync.Once
+}
+
+func newJanitor() *janitor {
+	return &janitor{id: utils.RandStr(16), stop: make(chan struct{})}
+}
+
+func (j *janitor) close() {
+	j.once.Do(func() {
+		close(j.stop)
+	})
+}
+
+// imagesExpired images of task row purged by retention
+func imagesExpired(row map[string]interface{}) bool {
+	expired, ok := row[datastore.KTaskImagesExpired].(int64)
+	return ok && expired == 1
+}
+
+// StartJanitor purge expired task images and rows every retentionInterval, do nothing when retention not config
+func (p *ProxyHandler) StartJanitor() {
+	if !config.ConfigGlobal.EnableRetention() {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(time.Duration(config.ConfigGlobal.RetentionInterval) * time.Second)
+		defer ticker.Stop()
+		for {
+			now := utils.TimestampS()
+			if ok, err := p.acquireJanitorLease(now); err != nil {
+				logrus.Warnf("janitor lease err=%s", err.Error())
+			} else if ok {
+				images, rows, err := p.purgeExpiredTasks(now)
+				if err != nil {
+					logrus.Warnf("janitor purge err=%s", err.Error())
+				} else if images > 0 || rows > 0 {
+					logrus.Infof("janitor purged images of %d tasks, deleted %d task rows", images, rows)
+				}
+			}
+			select {
+			case <-p.janitor.stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// acquireJanitorLease only one instance purge, lease renewed every round by holder, taken over by
+// other instance when not renewed in two retentionInterval
+func (p *ProxyHandler) acquireJanitorLease(now int64) (bool, error) {
+	lease := map[string]interface{}{
+		datastore.KConfigVal:        p.janitor.id,
+		datastore.KConfigModifyTime: fmt.Sprintf("%d", now),
+	}
+	if ok, err := p.configStore.PutIfAbsent(janitorLeaseKey, lease); err != nil || ok {
+		return ok, err
+	}
+	data, err := p.configStore.Get(janitorLeaseKey, []string{datastore.KConfigVal, datastore.KConfigModifyTime})
+	if err != nil {
+		return false, err
+	}
+	renewTime := parseTaskTime(data[datastore.KConfigModifyTime])
+	ttl := 2 * int64(config.ConfigGlobal.RetentionInterval)
+	if data[datastore.KConfigVal] != p.janitor.id && renewTime != nil && now-*renewTime <= ttl {
+		return false, nil
+	}
+	return p.configStore.CompareAndSwap(janitorLeaseKey, datastore.KConfigModifyTime,
+		data[datastore.KConfigModifyTime], lease)
+}
+
+// StopJanitor stop background janitor
+func (p *ProxyHandler) StopJanitor() {
+	p.janitor.close()
+}
+
+// expiredTask terminal task whose images or row expired
+type expiredTask struct {
+	taskId     string
+	row        map[string]interface{}
+	rowExpired bool
+}
+
+// purgeExpiredTasks one round of retention, return count of tasks whose images purged and rows deleted,
+// oss keys still referenced by task whose images not expired (render cache copy) kept
+func (p *ProxyHandler) purgeExpiredTasks(now int64) (int, int, error) {
+	imageTTL := int64(config.ConfigGlobal.ImageRetentionDays) * secondsPerDay
+	taskTTL := int64(config.ConfigGlobal.TaskRetentionDays) * secondsPerDay
+	expired := make([]expiredTask, 0)
+	// oss key -> referenced by task kept this round
+	liveKeys := make(map[string]struct{})
+	cursor := ""
+	for {
+		rows, nextKey, err := p.taskStore.ListRange(cursor, taskScanBatch, retentionColumns)
+		if err != nil {
+			return 0, 0, err
+		}
+		for taskId, row := range rows {
+			status, _ := row[datastore.KTaskStatus].(string)
+			finishTime := parseTaskTime(row[datastore.KTaskModifyTime])
+			if finishTime == nil {
+				finishTime = parseTaskTime(row[datastore.KTaskCreateTime])
+			}
+			if module.IsTaskTerminal(status) && finishTime != nil {
+				age := now - *finishTime
+				rowExpired := taskTTL > 0 && age > taskTTL
+				if rowExpired || (imageTTL > 0 && age > imageTTL && !imagesExpired(row)) {
+					expired = append(expired, expiredTask{taskId: taskId, row: row, rowExpired: rowExpired})
+					continue
+				}
+			}
+			if !imagesExpired(row) {
+				for _, key := range taskImageKeys(row) {
+					liveKeys[key] = struct{}{}
+				}
+			}
+		}
+		if nextKey == "" {
+			break
+		}
+		cursor = nextKey
+	}
+	purged, deleted := 0, 0
+	for _, task := range expired {
+		// row deleted with its images, otherwise images left without reference
+		if !imagesExpired(task.row) {
+			if err := purgeTaskImages(task.row, liveKeys); err != nil {
+				logrus.WithFields(logrus.Fields{"taskId": task.taskId}).Warnf("purge images err=%s", err.Error())
+				continue
+			}
+		}
+		if task.rowExpired {
+			if err := p.taskStore.Delete(task.taskId); err != nil {
+				logrus.WithFields(logrus.Fields{"taskId": task.taskId}).Warnf("delete task err=%s", err.Error())
+				continue
+			}
+			deleted++
+			continue
+		}
+		if err := p.taskStore.Update(task.taskId, map[string]interface{}{
+			datastore.KTaskImagesExpired: int64(1),
+		}); err != nil {
+			logrus.WithFields(logrus.Fields{"taskId": task.taskId}).Warnf("mark images expired err=%s",
+				err.Error())
+			continue
+		}
+		purged++
+	}
+	return purged, deleted, nil
+}
+
+// taskImageKeys oss keys of task row images
+func taskImageKeys(row map[string]interface{}) []string {
+	image, _ := row[datastore.KTaskImage].(string)
+	if image == "" {
+		return nil
+	}
+	return strings.Split(image, ",")
+}
+
+// purgeTaskImages delete oss images of task row, skip keys in keep and add deleted keys to keep,
+// tasks sharing keys delete them once
+func purgeTaskImages(row map[string]interface{}, keep map[string]struct{}) error {
+	for _, key := range taskImageKeys(row) {
+		if _, ok := keep[key]; ok {
+			continue
+		}
+		if err := module.OssGlobal.DeleteFile(key); err != nil {
+			return fmt.Errorf("delete %s err=%s", key, err.Error())
+		}
+		keep[key] = struct{}{}
+	}
+	return nil
+}
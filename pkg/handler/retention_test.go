@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/datastore"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPurgeExpiredTasks(t *testing.T) {
+	initTestConfig(t)
+	config.ConfigGlobal.ImageRetentionDays = 7
+	config.ConfigGlobal.TaskRetentionDays = 30
+	oss := mockOss(t, 0)
+	taskStore := datastore.NewSQLiteDatastore(datastore.NewSQLiteConfig(datastore.KTaskTableName))
+	defer taskStore.Close()
+	now := int64(100 * secondsPerDay)
+	put := func(taskId, status string, days int64) {
+		image := fmt.Sprintf("images/user/%s_1.png", taskId)
+		assert.Nil(t, oss.UploadFileByByte(image, []byte("image")))
+		assert.Nil(t, taskStore.Put(taskId, map[string]interface{}{
+			datastore.KTaskIdColumnName: taskId,
+			datastore.KTaskStatus:       status,
+			datastore.KTaskCode:         int64(requestOk),
+			datastore.KTaskParams:       "{}",
+			datastore.KTaskInfo:         "{}",
+			datastore.KTaskCreateTime:   fmt.Sprintf("%d", now-days*secondsPerDay-10),
+			datastore.KTaskModifyTime:   fmt.Sprintf("%d", now-days*secondsPerDay),
+			datastore.KTaskImage:        image,
+		}))
+	}
+	put("fresh", config.TASK_FINISH, 1)
+	put("old", config.TASK_FINISH, 10)
+	put("expired", config.TASK_FAILED, 40)
+	// running task never purged
+	put("running", config.TASK_INPROGRESS, 40)
+	p := &ProxyHandler{taskStore: taskStore}
+
+	purged, deleted, err := p.purgeExpiredTasks(now)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, purged)
+	assert.Equal(t, 1, deleted)
+	assert.Contains(t, oss.uploaded, "images/user/fresh_1.png")
+	assert.NotContains(t, oss.uploaded, "images/user/old_1.png")
+	assert.NotContains(t, oss.uploaded, "images/user/expired_1.png")
+	assert.Contains(t, oss.uploaded, "images/user/running_1.png")
+	row, err := taskStore.Get("expired", []string{datastore.KTaskStatus})
+	assert.Nil(t, err)
+	assert.Empty(t, row)
+
+	// metadata kept, images flagged instead of broken urls
+	result, err := p.getTaskResult("old")
+	assert.Nil(t, err)
+	assert.Equal(t, config.TASK_FINISH, result.Status)
+	assert.True(t, *result.ImagesExpired)
+	assert.Empty(t, *result.Images)
+	assert.Empty(t, *result.OssUrl)
+	result, err = p.getTaskResult("fresh")
+	assert.Nil(t, err)
+	assert.Nil(t, result.ImagesExpired)
+	assert.Equal(t, []string{"http://oss/images/user/fresh_1.png"}, *result.OssUrl)
+
+	// purged once
+	purged, deleted, err = p.purgeExpiredTasks(now)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, purged+deleted)
+	// row expired later, images already purged
+	purged, deleted, err = p.purgeExpiredTasks(now + 25*secondsPerDay)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, purged)
+	assert.Equal(t, 1, deleted)
+}
+
+func TestPurgeSharedImages(t *testing.T) {
+	initTestConfig(t)
+	config.ConfigGlobal.ImageRetentionDays = 7
+	oss := mockOss(t, 0)
+	taskStore := datastore.NewSQLiteDatastore(datastore.NewSQLiteConfig(datastore.KTaskTableName))
+	defer taskStore.Close()
+	now := int64(100 * secondsPerDay)
+	image := "images/user/src_1.png"
+	assert.Nil(t, oss.UploadFileByByte(image, []byte("image")))
+	// render cache copy share images of source task
+	for taskId, days := range map[string]int64{"src": 10, "copy": 1} {
+		assert.Nil(t, taskStore.Put(taskId, map[string]interface{}{
+			datastore.KTaskIdColumnName: taskId,
+			datastore.KTaskStatus:       config.TASK_FINISH,
+			datastore.KTaskModifyTime:   fmt.Sprintf("%d", now-days*secondsPerDay),
+			datastore.KTaskImage:        image,
+		}))
+	}
+	p := &ProxyHandler{taskStore: taskStore}
+
+	purged, _, err := p.purgeExpiredTasks(now)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, purged)
+	assert.Contains(t, oss.uploaded, image)
+	// last reference expired
+	purged, _, err = p.purgeExpiredTasks(now + 7*secondsPerDay)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, purged)
+	assert.NotContains(t, oss.uploaded, image)
+}
+
+func TestJanitorLease(t *testing.T) {
+	initTestConfig(t)
+	config.ConfigGlobal.RetentionInterval = 60
+	configStore := datastore.NewSQLiteDatastore(datastore.NewSQLiteConfig(datastore.KConfigTableName))
+	defer configStore.Close()
+	p1 := &ProxyHandler{configStore: configStore, janitor: newJanitor()}
+	p2 := &ProxyHandler{configStore: configStore, janitor: newJanitor()}
+	acquire := func(p *ProxyHandler, now int64) bool {
+		ok, err := p.acquireJanitorLease(now)
+		assert.Nil(t, err)
+		return ok
+	}
+
+	assert.True(t, acquire(p1, 1000))
+	assert.False(t, acquire(p2, 1000))
+	// holder renew
+	assert.True(t, acquire(p1, 1060))
+	assert.False(t, acquire(p2, 1180))
+	// holder gone, taken over
+	assert.True(t, acquire(p2, 1181))
+	assert.False(t, acquire(p1, 1200))
+}
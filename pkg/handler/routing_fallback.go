@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/client"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/module"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// response header mark request served by routingFallbackEndpoint, value is the reason
+const routingFallbackKey = "X-Routing-Fallback"
+
+// reason of routing fallback
+const (
+	fallbackRoutingFailed      = "routing-failed"
+	fallbackControlUnreachable = "control-unreachable"
+)
+
+// routingFallback fallback endpoint when control can not route sd model,
+// cold start budget exhausted is throttling not failure, not fallback
+func routingFallback(c *gin.Context, err error) (string, bool) {
+	fallback := config.ConfigGlobal.RoutingFallbackEndpoint
+	if fallback == "" || errors.Is(err, module.ErrColdStartBudget) {
+		return "", false
+	}
+	markRoutingFallback(c, fallbackRoutingFailed, err)
+	return fallback, true
+}
+
+// controlFallback fallback endpoint and its client when proxy can not reach control downstream,
+// timeout not fallback since control may already run the task, nil client not fallback
+func controlFallback(c *gin.Context, endPoint string, err error) (string, *client.Client) {
+	fallback := config.ConfigGlobal.RoutingFallbackEndpoint
+	if err == nil || fallback == "" || fallback == endPoint || endPoint != config.ConfigGlobal.Downstream ||
+		errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return "", nil
+	}
+	markRoutingFallback(c, fallbackControlUnreachable, err)
+	return fallback, client.ManagerClientGlobal.GetClient(fallback)
+}
+
+func markRoutingFallback(c *gin.Context, reason string, err error) {
+	c.Header(routingFallbackKey, reason)
+	logrus.WithFields(logrus.Fields{"taskId": c.Writer.Header().Get(taskKey)}).Warnf(
+		"degraded routing, %s, use fallback endpoint, err=%s", reason, err.Error())
+}
@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/client"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/models"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/module"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoutingFallback(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	initTestConfig(t)
+	route := func() (string, http.Header, error) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/img2img", nil)
+		endpoint, err := getStickyEndpoint(c, "sd", false)
+		return endpoint, w.Header(), err
+	}
+
+	// disabled
+	mockEndpointManager(t, &fakeEndpointManager{err: errors.New("fc api unavailable")})
+	_, header, err := route()
+	assert.EqualError(t, err, config.NOFOUNDENDPOINT)
+	assert.Empty(t, header.Get(routingFallbackKey))
+
+	config.ConfigGlobal.RoutingFallbackEndpoint = "http://fallback"
+	endpoint, header, err := route()
+	assert.Nil(t, err)
+	assert.Equal(t, "http://fallback", endpoint)
+	assert.Equal(t, fallbackRoutingFailed, header.Get(routingFallbackKey))
+	assert.Empty(t, header.Get(stickyRouteKey))
+
+	// throttled, not fallback
+	mockEndpointManager(t, &fakeEndpointManager{err: module.ErrColdStartBudget})
+	_, header, err = route()
+	assert.ErrorIs(t, err, module.ErrColdStartBudget)
+	assert.Empty(t, header.Get(routingFallbackKey))
+
+	// routed normally
+	mockEndpointManager(t, &fakeEndpointManager{endpoints: map[string]string{"sd": "http://sd"}})
+	endpoint, header, err = route()
+	assert.Nil(t, err)
+	assert.Equal(t, "http://sd", endpoint)
+	assert.Empty(t, header.Get(routingFallbackKey))
+}
+
+func TestControlFallback(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	initTestConfig(t)
+	config.ConfigGlobal.ServerName = config.PROXY
+	fallback := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/extra_images", r.URL.Path)
+		w.Write([]byte(`{}`))
+	}))
+	defer fallback.Close()
+	control := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	control.Close()
+	config.ConfigGlobal.Downstream = control.URL
+	fallbackOf := func(endPoint string, err error) (string, *client.Client, http.Header) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		endpoint, fallbackClient := controlFallback(c, endPoint, err)
+		return endpoint, fallbackClient, w.Header()
+	}
+
+	_, err := client.ManagerClientGlobal.GetClient(control.URL).ExtraImages(context.Background(),
+		models.ExtraImagesRequest{})
+	assert.NotNil(t, err)
+	// disabled
+	_, fallbackClient, _ := fallbackOf(control.URL, err)
+	assert.Nil(t, fallbackClient)
+
+	config.ConfigGlobal.RoutingFallbackEndpoint = fallback.URL
+	endpoint, fallbackClient, header := fallbackOf(control.URL, err)
+	assert.Equal(t, fallback.URL, endpoint)
+	assert.Equal(t, fallbackControlUnreachable, header.Get(routingFallbackKey))
+	resp, err := fallbackClient.ExtraImages(context.Background(), models.ExtraImagesRequest{})
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	resp.Body.Close()
+
+	// success, timeout, or not control downstream
+	_, fallbackClient, _ = fallbackOf(control.URL, nil)
+	assert.Nil(t, fallbackClient)
+	_, fallbackClient, _ = fallbackOf(control.URL, context.DeadlineExceeded)
+	assert.Nil(t, fallbackClient)
+	_, fallbackClient, _ = fallbackOf("http://sd", errors.New("connection refused"))
+	assert.Nil(t, fallbackClient)
+}
@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	schemaVersionKey = "X-API-Schema-Version"
+	schemaAcceptKey  = "X-API-Schema-Accept"
+	// gin context key of negotiated schema version
+	schemaVersionCtxKey = "schemaVersion"
+)
+
+// response schema versions, bump ApiSchemaVersion when response shape changes
+const (
+	// error response {"message": "..."}
+	schemaVersionLegacy = 1
+	// error response {"error": {"code": 400, "message": "..."}}
+	schemaVersionErrorEnvelope = 2
+	// ApiSchemaVersion latest response schema
+	ApiSchemaVersion = schemaVersionErrorEnvelope
+)
+
+// SchemaVersion negotiate response schema by client X-API-Schema-Accept, default legacy keep old client work
+// X-API-Schema-Version of every response tell client which schema served
+func SchemaVersion() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		version := schemaVersionLegacy
+		if accept := strings.TrimSpace(c.GetHeader(schemaAcceptKey)); accept != "" {
+			if accept == "latest" {
+				version = ApiSchemaVersion
+			} else if v, err := strconv.Atoi(accept); err == nil && v >= schemaVersionLegacy &&
+				v <= ApiSchemaVersion {
+				version = v
+			} else {
+				c.Header(schemaVersionKey, strconv.Itoa(version))
+				c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"message": fmt.Sprintf(
+					"%s %s not support, value: %d-%d|latest", schemaAcceptKey, accept, schemaVersionLegacy,
+					ApiSchemaVersion)})
+				return
+			}
+		}
+		c.Set(schemaVersionCtxKey, version)
+		c.Header(schemaVersionKey, strconv.Itoa(version))
+		c.Next()
+	}
+}
+
+// schemaVersion negotiated schema of request, legacy when middleware not used
+func schemaVersion(c *gin.Context) int {
+	if version, ok := c.Get(schemaVersionCtxKey); ok {
+		return version.(int)
+	}
+	return schemaVersionLegacy
+}
+
+// errorBody error response of negotiated schema
+func errorBody(c *gin.Context, code int, message string) gin.H {
+	if schemaVersion(c) >= schemaVersionErrorEnvelope {
+		return gin.H{"error": gin.H{"code": code, "message": message}}
+	}
+	return gin.H{"message": message}
+}
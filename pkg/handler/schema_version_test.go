@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchemaVersion(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(SchemaVersion())
+	router.Use(AdminAuth())
+	router.GET("/ok", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "success"})
+	})
+	router.GET("/admin/x", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	router.NoRoute(func(c *gin.Context) {
+		handleError(c, http.StatusNotFound, "not support")
+	})
+	do := func(path, accept string) (*httptest.ResponseRecorder, map[string]interface{}) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if accept != "" {
+			req.Header.Set(schemaAcceptKey, accept)
+		}
+		router.ServeHTTP(w, req)
+		var body map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &body)
+		return w, body
+	}
+
+	// default legacy, header on success and error response
+	w, body := do("/ok", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "1", w.Header().Get(schemaVersionKey))
+	w, body = do("/none", "")
+	assert.Equal(t, "1", w.Header().Get(schemaVersionKey))
+	assert.Equal(t, "not support", body["message"])
+	w, body = do("/admin/x", "1")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Equal(t, "admin permission required", body["message"])
+
+	// opt in error envelope
+	for _, accept := range []string{"2", "latest"} {
+		w, body = do("/none", accept)
+		assert.Equal(t, strconv.Itoa(ApiSchemaVersion), w.Header().Get(schemaVersionKey))
+		assert.Nil(t, body["message"])
+		assert.Equal(t, map[string]interface{}{"code": float64(http.StatusNotFound), "message": "not support"},
+			body["error"])
+	}
+	w, body = do("/admin/x", "2")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.NotNil(t, body["error"])
+
+	// unknown version
+	for _, accept := range []string{"0", "99", "v2"} {
+		w, body = do("/ok", accept)
+		assert.Equal(t, http.StatusNotAcceptable, w.Code, accept)
+		assert.Equal(t, "1", w.Header().Get(schemaVersionKey))
+		assert.Contains(t, body["message"], schemaAcceptKey)
+	}
+}
@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"io"
+	"net/http"
+)
+
+const (
+	selfTestPrefix = "selftestReference"
+	// canned request, fixed seed and deterministic sampler
+	selfTestPrompt  = "a red apple on a wooden table, studio lighting"
+	selfTestSeed    = 20231001
+	selfTestSteps   = 20
+	selfTestSize    = 512
+	selfTestSampler = "Euler"
+)
+
+func selfTestReferenceKey(sdModel string) string {
+	return fmt.Sprintf("%s_%s", selfTestPrefix, sdModel)
+}
+
+// render canned prompt with sdModel, return sha256 of the first image
+func renderSelfTest(httpClient *http.Client, endpoint, sdModel string) (string, error) {
+	body, err := json.Marshal(map[string]interface{}{
+		"prompt":       selfTestPrompt,
+		"seed":         selfTestSeed,
+		"subseed":      selfTestSeed,
+		"steps":        selfTestSteps,
+		"width":        selfTestSize,
+		"height":       selfTestSize,
+		"sampler_name": selfTestSampler,
+		"batch_size":   1,
+		"n_iter":       1,
+		"override_settings": map[string]interface{}{
+			"sd_model_checkpoint": sdModel,
+		},
+		"override_settings_restore_afterwards": false,
+	})
+	if err != nil {
+		return "", err
+	}
+	resp, err := httpClient.Post(fmt.Sprintf("%s%s", endpoint, config.TXT2IMG), "application/json",
+		bytes.NewBuffer(body))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("selftest render fail, status code=%d, body=%s", resp.StatusCode, string(respBody))
+	}
+	var result struct {
+		Images []string `json:"images"`
+	}
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return "", err
+	}
+	if len(result.Images) == 0 {
+		return "", errors.New("selftest render return no image")
+	}
+	image, err := base64.StdEncoding.DecodeString(result.Images[0])
+	if err != nil {
+		return "", fmt.Errorf("base64 decode err=%s", err.Error())
+	}
+	hash := sha256.Sum256(image)
+	return hex.EncodeToString(hash[:]), nil
+}
@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/datastore"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/models"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelfTest(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	initTestConfig(t)
+	config.ConfigGlobal.ServerName = config.PROXY
+	image := "image"
+	sd := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		assert.Equal(t, float64(selfTestSeed), body["seed"])
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"images": []string{base64.StdEncoding.EncodeToString([]byte(image))},
+		})
+	}))
+	defer sd.Close()
+	config.ConfigGlobal.SdUrlPrefix = sd.URL
+	configStore := datastore.NewSQLiteDatastore(datastore.NewSQLiteConfig(datastore.KConfigTableName))
+	defer configStore.Close()
+	p := &ProxyHandler{configStore: configStore, httpClient: &http.Client{}}
+
+	selfTest := func(body string) models.SelfTestResult {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/admin/selftest", bytes.NewBufferString(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+		p.SelfTest(c)
+		assert.Equal(t, http.StatusOK, w.Code)
+		var result models.SelfTestResult
+		assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &result))
+		return result
+	}
+
+	// first run save reference
+	result := selfTest(`{"stable_diffusion_model":"sd"}`)
+	assert.Equal(t, config.SELFTEST_REFERENCE, result.Status)
+	reference := result.Hash
+
+	result = selfTest(`{"stable_diffusion_model":"sd"}`)
+	assert.Equal(t, config.SELFTEST_PASSED, result.Status)
+	assert.True(t, result.Passed)
+
+	// image drift
+	image = "drift"
+	result = selfTest(`{"stable_diffusion_model":"sd"}`)
+	assert.Equal(t, config.SELFTEST_FAILED, result.Status)
+	assert.False(t, result.Passed)
+	assert.Equal(t, reference, *result.ReferenceHash)
+
+	// update reference
+	result = selfTest(`{"stable_diffusion_model":"sd","update_reference":true}`)
+	assert.Equal(t, config.SELFTEST_REFERENCE, result.Status)
+	assert.NotEqual(t, reference, result.Hash)
+}
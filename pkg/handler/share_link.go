@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/datastore"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/models"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// sharedPathPrefix share link route, no login required
+const sharedPathPrefix = "/shared/"
+
+var errShareToken = errors.New("share link invalid or expired")
+
+// ShareTask create signed, expiring public link of task result, task owner only
+// (POST /tasks/{taskId}/share)
+func (p *ProxyHandler) ShareTask(c *gin.Context, taskId string, params models.ShareTaskParams) {
+	secret := config.ConfigGlobal.ShareLinkSecret
+	if secret == "" {
+		handleError(c, http.StatusNotFound, "share link disabled, shareLinkSecret not set")
+		return
+	}
+	ttl := config.ConfigGlobal.ShareLinkTTL
+	if params.ExpiresIn != nil {
+		ttl = *params.ExpiresIn
+	}
+	if ttl <= 0 || ttl > config.MaxShareLinkTTL {
+		handleError(c, http.StatusBadRequest, fmt.Sprintf("expiresIn should between 1 and %d", config.MaxShareLinkTTL))
+		return
+	}
+	data, err := p.taskStore.Get(taskId, []string{datastore.KTaskUser})
+	if err != nil || len(data) == 0 {
+		handleError(c, http.StatusNotFound, "not found")
+		return
+	}
+	if config.ConfigGlobal.EnableLogin() && data[datastore.KTaskUser] != c.GetHeader(userKey) {
+		handleError(c, http.StatusForbidden, "only task owner can share")
+		return
+	}
+	expiresAt := utils.TimestampS() + int64(ttl)
+	token := shareToken(secret, taskId, expiresAt)
+	c.JSON(http.StatusOK, models.ShareLink{
+		Url:       requestBaseUrl(c) + sharedPathPrefix + token,
+		Token:     token,
+		ExpiresAt: expiresAt,
+	})
+}
+
+// GetSharedTaskResult task result of share link, labels not shared
+// (GET /shared/{token})
+func (p *ProxyHandler) GetSharedTaskResult(c *gin.Context, token string) {
+	secret := config.ConfigGlobal.ShareLinkSecret
+	if secret == "" {
+		handleError(c, http.StatusNotFound, "share link disabled")
+		return
+	}
+	taskId, err := verifyShareToken(secret, token, utils.TimestampS())
+	if err != nil {
+		handleError(c, http.StatusForbidden, err.Error())
+		return
+	}
+	data, err := p.taskStore.Get(taskId, taskResultColumns)
+	if err != nil || len(data) == 0 {
+		handleError(c, http.StatusNotFound, "not found")
+		return
+	}
+	result, err := signedTaskResult(taskId, data)
+	if err != nil {
+		handleError(c, http.StatusNotFound, err.Error())
+		return
+	}
+	result.Labels = nil
+	c.JSON(http.StatusOK, result)
+}
+
+// shareToken taskId.expiresAt.signature, signature is base64url hmac-sha256 of taskId.expiresAt
+func shareToken(secret, taskId string, expiresAt int64) string {
+	payload := fmt.Sprintf("%s.%d", taskId, expiresAt)
+	return payload + "." + shareSignature(secret, payload)
+}
+
+// verifyShareToken taskId of token signed by secret and not expired at now
+func verifyShareToken(secret, token string, now int64) (string, error) {
+	idx := strings.LastIndex(token, ".")
+	if idx < 0 {
+		return "", errShareToken
+	}
+	payload, signature := token[:idx], token[idx+1:]
+	if !hmac.Equal([]byte(signature), []byte(shareSignature(secret, payload))) {
+		return "", errShareToken
+	}
+	idx = strings.LastIndex(payload, ".")
+	if idx <= 0 {
+		return "", errShareToken
+	}
+	expiresAt, err := strconv.ParseInt(payload[idx+1:], 10, 64)
+	if err != nil || now > expiresAt {
+		return "", errShareToken
+	}
+	return payload[:idx], nil
+}
+
+func shareSignature(secret, payload string) string {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(h.Sum(nil))
+}
+
+// requestBaseUrl scheme://host of request, X-Forwarded-Proto cover scheme behind gateway
+func requestBaseUrl(c *gin.Context) string {
+	scheme := c.GetHeader("X-Forwarded-Proto")
+	if scheme == "" {
+		scheme = "http"
+		if c.Request.TLS != nil {
+			scheme = "https"
+		}
+	}
+	return scheme + "://" + c.Request.Host
+}
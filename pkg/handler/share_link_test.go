@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/datastore"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/models"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShareToken(t *testing.T) {
+	token := shareToken("secret", "task.1", 1000)
+	taskId, err := verifyShareToken("secret", token, 1000)
+	assert.Nil(t, err)
+	assert.Equal(t, "task.1", taskId)
+
+	// expired, wrong secret, tampered
+	_, err = verifyShareToken("secret", token, 1001)
+	assert.ErrorIs(t, err, errShareToken)
+	_, err = verifyShareToken("other", token, 1000)
+	assert.ErrorIs(t, err, errShareToken)
+	_, err = verifyShareToken("secret", "task.2"+token[len("task.1"):], 1000)
+	assert.ErrorIs(t, err, errShareToken)
+	_, err = verifyShareToken("secret", "garbage", 1000)
+	assert.ErrorIs(t, err, errShareToken)
+}
+
+func TestShareTask(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	initTestConfig(t)
+	mockOss(t, 0)
+	config.ConfigGlobal.LoginSwitch = "on"
+	taskStore := datastore.NewSQLiteDatastore(datastore.NewSQLiteConfig(datastore.KTaskTableName))
+	defer taskStore.Close()
+	assert.Nil(t, taskStore.Put("task", map[string]interface{}{
+		datastore.KTaskIdColumnName: "task",
+		datastore.KTaskUser:         "alice",
+		datastore.KTaskStatus:       config.TASK_INPROGRESS,
+		datastore.KTaskLabels:       `{"project":"secret"}`,
+	}))
+	p := &ProxyHandler{taskStore: taskStore}
+	share := func(user, taskId string, expiresIn *int) (*httptest.ResponseRecorder, models.ShareLink) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/tasks/"+taskId+"/share", nil)
+		c.Request.Header.Set(userKey, user)
+		p.ShareTask(c, taskId, models.ShareTaskParams{ExpiresIn: expiresIn})
+		var link models.ShareLink
+		json.Unmarshal(w.Body.Bytes(), &link)
+		return w, link
+	}
+	shared := func(token string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, sharedPathPrefix+token, nil)
+		p.GetSharedTaskResult(c, token)
+		return w
+	}
+
+	// disabled
+	w, _ := share("alice", "task", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	config.ConfigGlobal.ShareLinkSecret = "secret"
+	config.ConfigGlobal.ShareLinkTTL = 60
+	w, link := share("alice", "task", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "http://example.com/shared/"+link.Token, link.Url)
+	assert.InDelta(t, utils.TimestampS()+60, link.ExpiresAt, 1)
+	w = shared(link.Token)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), config.TASK_INPROGRESS)
+	assert.NotContains(t, w.Body.String(), "project")
+
+	// owner only, ttl bounded, task exist
+	w, _ = share("bob", "task", nil)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w, _ = share("alice", "task", utils.Int(config.MaxShareLinkTTL+1))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w, _ = share("alice", "unknown", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// expired or forged
+	assert.Equal(t, http.StatusForbidden, shared(shareToken("secret", "task", utils.TimestampS()-1)).Code)
+	assert.Equal(t, http.StatusForbidden, shared(shareToken("other", "task", utils.TimestampS()+60)).Code)
+
+	// no login required
+	router := gin.New()
+	router.Use(ApiAuth())
+	RegisterHandlers(router, p)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, link.Url, nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
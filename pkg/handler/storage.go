@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/models"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"syscall"
+)
+
+// GetStorage disk usage of sdPath and size of each model dir
+// (GET /admin/storage)
+func (p *ProxyHandler) GetStorage(c *gin.Context) {
+	sdPath := config.ConfigGlobal.SdPath
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(sdPath, &stat); err != nil {
+		logrus.Errorf("statfs %s err=%s", sdPath, err.Error())
+		handleError(c, http.StatusInternalServerError, "stat sd path fail")
+		return
+	}
+	bsize := uint64(stat.Bsize)
+	info := models.StorageInfo{
+		Path:       sdPath,
+		TotalBytes: int64(stat.Blocks * bsize),
+		UsedBytes:  int64((stat.Blocks - stat.Bfree) * bsize),
+		FreeBytes:  int64(stat.Bavail * bsize),
+		ModelDirs:  make([]models.ModelDirUsage, 0, len(config.ConfigGlobal.ModelDirs)),
+		Models:     make([]models.ModelAttributes, 0),
+	}
+	modelTypes := make([]string, 0, len(config.ConfigGlobal.ModelDirs))
+	for modelType := range config.ConfigGlobal.ModelDirs {
+		modelTypes = append(modelTypes, modelType)
+	}
+	sort.Strings(modelTypes)
+	for _, modelType := range modelTypes {
+		dir := config.ConfigGlobal.GetModelDir(modelType)
+		bytes, files := dirUsage(dir)
+		info.ModelDirs = append(info.ModelDirs, models.ModelDirUsage{
+			Type:  modelType,
+			Path:  dir,
+			Bytes: bytes,
+			Files: files,
+		})
+		for _, attr := range listModelFile(dir, modelType) {
+			info.Models = append(info.Models, *attr)
+		}
+	}
+	c.JSON(http.StatusOK, info)
+}
+
+// dirUsage total size and count of files under dir, symlink to file counted with target size,
+// unreadable entries skipped
+func dirUsage(dir string) (bytes int64, files int) {
+	filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		if info, err := os.Stat(path); err == nil && info.Mode().IsRegular() {
+			bytes += info.Size()
+			files++
+		}
+		return nil
+	})
+	return
+}
@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/models"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetStorage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	initTestConfig(t)
+	sdPath := t.TempDir()
+	config.ConfigGlobal.SdPath = sdPath
+	config.ConfigGlobal.ModelDirs = map[string]string{
+		config.SD_MODEL:   "models/Stable-diffusion",
+		config.LORA_MODEL: "models/Lora",
+		config.SD_VAE:     "models/VAE",
+	}
+	write := func(name string, size int) {
+		path := filepath.Join(sdPath, name)
+		assert.Nil(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.Nil(t, os.WriteFile(path, make([]byte, size), 0644))
+	}
+	write("models/Stable-diffusion/sd.safetensors", 1000)
+	write("models/Stable-diffusion/sd.yaml", 10)
+	write("models/Lora/style/cat.safetensors", 200)
+	write("models/Lora/dog.safetensors", 300)
+	assert.Nil(t, os.Symlink(filepath.Join(sdPath, "models/Lora/dog.safetensors"),
+		filepath.Join(sdPath, "models/Lora/dog_v2.safetensors")))
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	(&ProxyHandler{}).GetStorage(c)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var info models.StorageInfo
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &info))
+	assert.Equal(t, sdPath, info.Path)
+	assert.True(t, info.TotalBytes > 0)
+	assert.True(t, info.FreeBytes > 0 && info.FreeBytes <= info.TotalBytes)
+	assert.True(t, info.UsedBytes <= info.TotalBytes)
+	// sorted by type, missing dir empty
+	assert.Equal(t, []models.ModelDirUsage{
+		{Type: config.LORA_MODEL, Path: filepath.Join(sdPath, "models/Lora"), Bytes: 800, Files: 3},
+		{Type: config.SD_VAE, Path: filepath.Join(sdPath, "models/VAE")},
+		{Type: config.SD_MODEL, Path: filepath.Join(sdPath, "models/Stable-diffusion"), Bytes: 1010, Files: 2},
+	}, info.ModelDirs)
+	sizes := make(map[string]int64)
+	for _, model := range info.Models {
+		sizes[model.Name] = *model.Size
+	}
+	assert.Equal(t, map[string]int64{"dog.safetensors": 300, "dog_v2.safetensors": 300, "sd.safetensors": 1000},
+		sizes)
+
+	config.ConfigGlobal.SdPath = filepath.Join(sdPath, "missing")
+	w = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(w)
+	(&ProxyHandler{}).GetStorage(c)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
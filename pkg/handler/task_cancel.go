@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/datastore"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"time"
+)
+
+// sd interrupt only set stop flag, return fast
+const interruptTimeout = 10 * time.Second
+
+// cancelPollMaxInterval cancel flag poll back off up to, long task not hammer db
+const cancelPollMaxInterval = 2 * time.Second
+
+// watchTaskCancel poll task cancel flag with back off until stop, cancel returned ctx when user cancel task
+// stop return true if task cancelled
+func (p *ProxyHandler) watchTaskCancel(parent context.Context, taskId string) (context.Context, func() bool) {
+	ctx, cancel := context.WithCancel(parent)
+	interval := time.Duration(config.ConfigGlobal.ListenMinInterval) * time.Millisecond
+	if interval <= 0 {
+		interval = config.DefaultListenMinInterval * time.Millisecond
+	}
+	// write before done closed
+	cancelled := false
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		timer := time.NewTimer(interval)
+		defer timer.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-timer.C:
+			}
+			// cancel mostly come early, later poll back off
+			if interval *= 2; interval > cancelPollMaxInterval {
+				interval = cancelPollMaxInterval
+			}
+			timer.Reset(interval)
+			ret, err := p.taskStore.Get(taskId, []string{datastore.KTaskCancel})
+			if err != nil {
+				continue
+			}
+			if val, ok := ret[datastore.KTaskCancel].(int64); ok && val == int64(config.CANCEL_VALID) {
+				logrus.WithFields(logrus.Fields{"taskId": taskId}).Info("task cancelled, stop waiting")
+				cancelled = true
+				cancel()
+				return
+			}
+		}
+	}()
+	return ctx, func() bool {
+		cancel()
+		<-done
+		return cancelled
+	}
+}
+
+// cancelTask mark task cancelled and interrupt sd of endpoint, gpu not held by abandoned request.
+// webui shared by tasks, only interrupt when its current job is the task's
+func (p *ProxyHandler) cancelTask(endPoint, taskId string) {
+	// task finished meanwhile keep result
+	if _, err := p.updateTaskStatus(taskId, map[string]interface{}{
+		datastore.KTaskStatus:     config.TASK_CANCELLED,
+		datastore.KTaskModifyTime: fmt.Sprintf("%d", utils.TimestampS()),
+	}); err != nil {
+		logrus.WithFields(logrus.Fields{"taskId": taskId}).Errorf("update task cancelled err=%s", err.Error())
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), interruptTimeout)
+	defer cancel()
+	if !p.isTaskRunning(ctx, endPoint, taskId) {
+		logrus.WithFields(logrus.Fields{"taskId": taskId}).Info("webui not running task job, skip interrupt")
+		return
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s%s", endPoint, config.CANCEL), nil)
+	if err != nil {
+		return
+	}
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"taskId": taskId}).Warnf("interrupt sd err=%s", err.Error())
+		return
+	}
+	resp.Body.Close()
+}
+
+// isTaskRunning webui of endPoint current job is the job recorded for task
+func (p *ProxyHandler) isTaskRunning(ctx context.Context, endPoint, taskId string) bool {
+	data, err := p.taskStore.Get(taskId, []string{datastore.KTaskWebuiJobId})
+	if err != nil {
+		return false
+	}
+	jobId, _ := data[datastore.KTaskWebuiJobId].(string)
+	if jobId == "" {
+		return false
+	}
+	progress, err := p.sdProgress(ctx, endPoint)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"taskId": taskId}).Warnf("get sd progress err=%s", err.Error())
+		return false
+	}
+	return progress.State.JobTimestamp == jobId
+}
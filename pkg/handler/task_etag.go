@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/datastore"
+	"strings"
+)
+
+// columns of task result and modifyTime, any update of them change etag
+var taskETagColumns = append([]string{datastore.KTaskModifyTime}, taskResultColumns...)
+
+// taskResultETag strong etag of task row, modifyTime only second precision and not updated by every write,
+// so status, images and result columns hashed together
+func taskResultETag(data map[string]interface{}) string {
+	h := sha1.New()
+	for _, column := range taskETagColumns {
+		if val, ok := data[column]; ok {
+			fmt.Fprintf(h, "%s=%v\n", column, val)
+		}
+	}
+	return fmt.Sprintf(`"%x"`, h.Sum(nil))
+}
+
+// etagMatch If-None-Match contain etag, support list, weak etag and *
+func etagMatch(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	for _, one := range strings.Split(ifNoneMatch, ",") {
+		one = strings.TrimPrefix(strings.TrimSpace(one), "W/")
+		if one == "*" || one == etag {
+			return true
+		}
+	}
+	return false
+}
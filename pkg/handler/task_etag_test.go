@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/datastore"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEtagMatch(t *testing.T) {
+	assert.False(t, etagMatch("", `"a"`))
+	assert.True(t, etagMatch(`"a"`, `"a"`))
+	assert.True(t, etagMatch(`"b", W/"a"`, `"a"`))
+	assert.True(t, etagMatch("*", `"a"`))
+	assert.False(t, etagMatch(`"b"`, `"a"`))
+}
+
+func TestGetTaskResultETag(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	initTestConfig(t)
+	mockOss(t, 0)
+	taskStore := datastore.NewSQLiteDatastore(datastore.NewSQLiteConfig(datastore.KTaskTableName))
+	defer taskStore.Close()
+	p := &ProxyHandler{taskStore: taskStore}
+	assert.Nil(t, taskStore.Put("task", map[string]interface{}{
+		datastore.KTaskIdColumnName: "task",
+		datastore.KTaskStatus:       config.TASK_INPROGRESS,
+		datastore.KTaskModifyTime:   "1000",
+	}))
+	getTask := func(taskId, ifNoneMatch string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/tasks/"+taskId+"/result", nil)
+		if ifNoneMatch != "" {
+			c.Request.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		p.GetTaskResult(c, taskId)
+		return w
+	}
+	get := func(ifNoneMatch string) *httptest.ResponseRecorder {
+		return getTask("task", ifNoneMatch)
+	}
+
+	w := get("")
+	assert.Equal(t, http.StatusOK, w.Code)
+	etag := w.Header().Get("ETag")
+	assert.NotEmpty(t, etag)
+	w = get(etag)
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Empty(t, w.Body.String())
+
+	// partial image uploaded in same second
+	assert.Nil(t, taskStore.Update("task", map[string]interface{}{
+		datastore.KTaskImage: "images/task_1.png",
+	}))
+	w = get(etag)
+	assert.Equal(t, http.StatusOK, w.Code)
+	partialEtag := w.Header().Get("ETag")
+	assert.NotEqual(t, etag, partialEtag)
+
+	assert.Nil(t, taskStore.Update("task", map[string]interface{}{
+		datastore.KTaskStatus:     config.TASK_FINISH,
+		datastore.KTaskCode:       int64(requestOk),
+		datastore.KTaskParams:     `{}`,
+		datastore.KTaskInfo:       `{}`,
+		datastore.KTaskModifyTime: "1001",
+	}))
+	w = get(partialEtag)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "http://oss/images/task_1.png")
+
+	assert.Equal(t, http.StatusNotFound, getTask("unknown", "").Code)
+}
@@ -0,0 +1,195 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/datastore"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/models"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/module"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// progressTasks tasks polling webui progress, webui progress is global so attributed to task by current_task,
+// or to the only polling task when webui not report current_task
+var progressTasks int32
+
+// startTaskProgress poll webui progress of endPoint for task in background until stop called,
+// stop return after poller exit so no progress written after it, safe to call more than once
+func (p *ProxyHandler) startTaskProgress(ctx context.Context, endPoint, taskId string) (stop func()) {
+	if config.ConfigGlobal.DisableProgress() {
+		return func() {}
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	atomic.AddInt32(&progressTasks, 1)
+	go func() {
+		defer close(done)
+		defer atomic.AddInt32(&progressTasks, -1)
+		p.taskProgress(ctx, endPoint, taskId)
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			cancel()
+			<-done
+		})
+	}
+}
+
+// taskProgress write webui progress to task every PROGRESS_INTERVAL ms, only when progress changed
+func (p *ProxyHandler) taskProgress(ctx context.Context, endPoint, taskId string) {
+	ticker := time.NewTicker(config.PROGRESS_INTERVAL * time.Millisecond)
+	defer ticker.Stop()
+	var last float64
+	var lastJob string
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		progress, err := p.sdProgress(ctx, endPoint)
+		if err != nil || !progressOfTask(progress, taskId) {
+			continue
+		}
+		// cancel interrupt webui only when it still run this job
+		if job := progress.State.JobTimestamp; job != "" && job != lastJob {
+			if err := p.taskStore.Update(taskId, map[string]interface{}{datastore.KTaskWebuiJobId: job}); err != nil {
+				logrus.WithFields(logrus.Fields{"taskId": taskId}).Warnf("write webui job err=%s", err.Error())
+			} else {
+				lastJob = job
+			}
+		}
+		if progress.Progress <= 0 || progress.Progress == last {
+			continue
+		}
+		last = progress.Progress
+		resp := &models.TaskProgressResponse{
+			TaskId:      taskId,
+			Progress:    float32(progress.Progress),
+			EtaRelative: float32(progress.EtaRelative),
+			EtaSeconds:  utils.Float32(float32(progress.EtaRelative)),
+		}
+		if progress.State.SamplingSteps > 0 {
+			resp.Step = utils.Int(progress.State.SamplingStep)
+			resp.Steps = utils.Int(progress.State.SamplingSteps)
+		}
+		if config.ConfigGlobal.EnableProgressImg() {
+			resp.CurrentImage = progress.CurrentImage
+		}
+		if state, err := json.Marshal(progress.State); err == nil {
+			stateMap := make(map[string]interface{})
+			if err := json.Unmarshal(state, &stateMap); err == nil {
+				resp.State = &stateMap
+			}
+		}
+		if err := p.writeTaskProgress(taskId, resp); err != nil {
+			logrus.WithFields(logrus.Fields{"taskId": taskId}).Warnf("write progress err=%s", err.Error())
+		}
+	}
+}
+
+// progressOfTask webui progress belong to task, unknown owner only attributed when no other task in flight
+func progressOfTask(progress *models.ProgressResult, taskId string) bool {
+	if progress.CurrentTask != "" {
+		return progress.CurrentTask == taskId
+	}
+	return atomic.LoadInt32(&progressTasks) == 1
+}
+
+// sdProgress current progress of webui at endPoint
+func (p *ProxyHandler) sdProgress(ctx context.Context, endPoint string) (*models.ProgressResult, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		fmt.Sprintf("%s%s", endPoint, config.PROGRESS), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != requestOk {
+		return nil, fmt.Errorf("progress status code=%d", resp.StatusCode)
+	}
+	result := new(models.ProgressResult)
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// writeTaskProgress write progress with seq one greater than stored, concurrent writers never regress seq,
+// terminal task not written
+func (p *ProxyHandler) writeTaskProgress(taskId string, progress *models.TaskProgressResponse) error {
+	for i := 0; i < maxCasRetries; i++ {
+		data, err := p.taskStore.Get(taskId, []string{datastore.KTaskStatus, datastore.KTaskProgressColumnName})
+		if err != nil {
+			return err
+		}
+		if status, _ := data[datastore.KTaskStatus].(string); len(data) == 0 || module.IsTaskTerminal(status) {
+			return nil
+		}
+		var expected interface{}
+		var seq int64
+		if stored, ok := data[datastore.KTaskProgressColumnName].(string); ok {
+			expected = stored
+			prev := new(models.TaskProgressResponse)
+			if err := json.Unmarshal([]byte(stored), prev); err == nil && prev.Seq != nil {
+				seq = *prev.Seq
+			}
+		}
+		seq++
+		progress.Seq = &seq
+		value, err := json.Marshal(progress)
+		if err != nil {
+			return err
+		}
+		swapped, err := p.taskStore.CompareAndSwap(taskId, datastore.KTaskProgressColumnName, expected,
+			map[string]interface{}{datastore.KTaskProgressColumnName: string(value)})
+		if err != nil || swapped {
+			return err
+		}
+	}
+	return errCasConflict
+}
+
+// fillProgressEta fill eta seconds and sampling step not set in stored progress, progress written before these
+// fields or by webui directly keep snake_case eta_relative/sampling_step, renamed ones use camelCase
+func fillProgressEta(stored []byte, resp *models.TaskProgressResponse) {
+	raw := make(map[string]interface{})
+	if err := json.Unmarshal(stored, &raw); err != nil {
+		return
+	}
+	if resp.EtaSeconds == nil {
+		if eta, ok := progressNumber(raw, "etaRelative", "eta_relative"); ok {
+			resp.EtaRelative = float32(eta)
+			resp.EtaSeconds = utils.Float32(float32(eta))
+		}
+	}
+	state, _ := raw["state"].(map[string]interface{})
+	if resp.Steps == nil {
+		steps, ok := progressNumber(state, "samplingSteps", "sampling_steps")
+		if step, stepOk := progressNumber(state, "samplingStep", "sampling_step"); ok && stepOk && steps > 0 {
+			resp.Step = utils.Int(int(step))
+			resp.Steps = utils.Int(int(steps))
+		}
+	}
+}
+
+// progressNumber first number value of keys
+func progressNumber(m map[string]interface{}, keys ...string) (float64, bool) {
+	for _, key := range keys {
+		if val, ok := m[key].(float64); ok {
+			return val, true
+		}
+	}
+	return 0, false
+}
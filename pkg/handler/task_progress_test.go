@@ -0,0 +1,276 @@
+package handler
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/datastore"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/models"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteTaskProgress(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	initTestConfig(t)
+	taskStore := datastore.NewSQLiteDatastore(datastore.NewSQLiteConfig(datastore.KTaskTableName))
+	defer taskStore.Close()
+	p := &ProxyHandler{taskStore: taskStore}
+	assert.Nil(t, taskStore.Put("task", map[string]interface{}{
+		datastore.KTaskIdColumnName: "task",
+		datastore.KTaskStatus:       config.TASK_INPROGRESS,
+	}))
+	getProgress := func() *models.TaskProgressResponse {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		p.GetTaskProgress(c, "task")
+		assert.Equal(t, http.StatusOK, w.Code)
+		resp := new(models.TaskProgressResponse)
+		assert.Nil(t, json.Unmarshal(w.Body.Bytes(), resp))
+		return resp
+	}
+
+	// concurrent writers each get own seq
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.Nil(t, p.writeTaskProgress("task", &models.TaskProgressResponse{Progress: 0.5}))
+		}()
+	}
+	wg.Wait()
+	resp := getProgress()
+	assert.Equal(t, int64(5), *resp.Seq)
+	assert.Equal(t, float32(0.5), resp.Progress)
+
+	// terminal task not written, final seq newer than last write
+	assert.Nil(t, taskStore.Update("task", map[string]interface{}{datastore.KTaskStatus: config.TASK_FINISH}))
+	assert.Nil(t, p.writeTaskProgress("task", &models.TaskProgressResponse{Progress: 0.9}))
+	resp = getProgress()
+	assert.Equal(t, int64(6), *resp.Seq)
+	assert.Equal(t, float32(1), resp.Progress)
+}
+
+func TestTaskProgress(t *testing.T) {
+	initTestConfig(t)
+	var lock sync.Mutex
+	progress := 0.0
+	sd := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, config.PROGRESS, r.URL.Path)
+		lock.Lock()
+		defer lock.Unlock()
+		json.NewEncoder(w).Encode(models.ProgressResult{Progress: progress, EtaRelative: 1,
+			State: models.State{SamplingStep: 5, SamplingSteps: 20, JobTimestamp: "20261017000000"}})
+	}))
+	defer sd.Close()
+	config.ConfigGlobal.SdUrlPrefix = sd.URL
+	taskStore := datastore.NewSQLiteDatastore(datastore.NewSQLiteConfig(datastore.KTaskTableName))
+	defer taskStore.Close()
+	p := &ProxyHandler{taskStore: taskStore, httpClient: &http.Client{}}
+	assert.Nil(t, taskStore.Put("task", map[string]interface{}{
+		datastore.KTaskIdColumnName: "task",
+		datastore.KTaskStatus:       config.TASK_INPROGRESS,
+	}))
+	stored := func() *models.TaskProgressResponse {
+		data, err := taskStore.Get("task", []string{datastore.KTaskProgressColumnName})
+		assert.Nil(t, err)
+		value, ok := data[datastore.KTaskProgressColumnName].(string)
+		if !ok {
+			return nil
+		}
+		resp := new(models.TaskProgressResponse)
+		assert.Nil(t, json.Unmarshal([]byte(value), resp))
+		return resp
+	}
+
+	stop := p.startTaskProgress(context.Background(), config.ConfigGlobal.SdUrlPrefix, "task")
+	// webui not started yet, nothing written
+	time.Sleep(2 * config.PROGRESS_INTERVAL * time.Millisecond)
+	assert.Nil(t, stored())
+	lock.Lock()
+	progress = 0.25
+	lock.Unlock()
+	time.Sleep(2 * config.PROGRESS_INTERVAL * time.Millisecond)
+	stop()
+	stop()
+	resp := stored()
+	// unchanged progress written once
+	assert.Equal(t, int64(1), *resp.Seq)
+	assert.Equal(t, float32(0.25), resp.Progress)
+	assert.Equal(t, float64(5), (*resp.State)["sampling_step"])
+	assert.Equal(t, float32(1), *resp.EtaSeconds)
+	assert.Equal(t, 5, *resp.Step)
+	assert.Equal(t, 20, *resp.Steps)
+	// webui job recorded for cancel
+	data, err := taskStore.Get("task", []string{datastore.KTaskWebuiJobId})
+	assert.Nil(t, err)
+	assert.Equal(t, "20261017000000", data[datastore.KTaskWebuiJobId])
+	// stopped poller not write
+	lock.Lock()
+	progress = 0.5
+	lock.Unlock()
+	time.Sleep(2 * config.PROGRESS_INTERVAL * time.Millisecond)
+	assert.Equal(t, float32(0.25), stored().Progress)
+}
+
+func TestTaskProgressAttribution(t *testing.T) {
+	initTestConfig(t)
+	var lock sync.Mutex
+	currentTask := ""
+	sd := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+		json.NewEncoder(w).Encode(models.ProgressResult{Progress: 0.5, CurrentTask: currentTask})
+	}))
+	defer sd.Close()
+	config.ConfigGlobal.SdUrlPrefix = sd.URL
+	taskStore := datastore.NewSQLiteDatastore(datastore.NewSQLiteConfig(datastore.KTaskTableName))
+	defer taskStore.Close()
+	p := &ProxyHandler{taskStore: taskStore, httpClient: &http.Client{}}
+	written := func(taskId string) bool {
+		data, err := taskStore.Get(taskId, []string{datastore.KTaskProgressColumnName})
+		assert.Nil(t, err)
+		_, ok := data[datastore.KTaskProgressColumnName].(string)
+		return ok
+	}
+	for _, taskId := range []string{"task1", "task2"} {
+		assert.Nil(t, taskStore.Put(taskId, map[string]interface{}{
+			datastore.KTaskIdColumnName: taskId,
+			datastore.KTaskStatus:       config.TASK_INPROGRESS,
+		}))
+	}
+
+	// owner unknown with two tasks in flight, nobody written
+	stop1 := p.startTaskProgress(context.Background(), config.ConfigGlobal.SdUrlPrefix, "task1")
+	stop2 := p.startTaskProgress(context.Background(), config.ConfigGlobal.SdUrlPrefix, "task2")
+	time.Sleep(2 * config.PROGRESS_INTERVAL * time.Millisecond)
+	assert.False(t, written("task1"))
+	assert.False(t, written("task2"))
+	// webui report current task
+	lock.Lock()
+	currentTask = "task2"
+	lock.Unlock()
+	time.Sleep(2 * config.PROGRESS_INTERVAL * time.Millisecond)
+	assert.False(t, written("task1"))
+	assert.True(t, written("task2"))
+	stop2()
+	// only task in flight
+	lock.Lock()
+	currentTask = ""
+	lock.Unlock()
+	time.Sleep(2 * config.PROGRESS_INTERVAL * time.Millisecond)
+	stop1()
+	assert.True(t, written("task1"))
+}
+
+func TestGetTaskProgressEta(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	initTestConfig(t)
+	taskStore := datastore.NewSQLiteDatastore(datastore.NewSQLiteConfig(datastore.KTaskTableName))
+	defer taskStore.Close()
+	p := &ProxyHandler{taskStore: taskStore}
+	getProgress := func(stored string) *models.TaskProgressResponse {
+		assert.Nil(t, taskStore.Put("task", map[string]interface{}{
+			datastore.KTaskIdColumnName:       "task",
+			datastore.KTaskStatus:             config.TASK_INPROGRESS,
+			datastore.KTaskProgressColumnName: stored,
+		}))
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		p.GetTaskProgress(c, "task")
+		assert.Equal(t, http.StatusOK, w.Code)
+		resp := new(models.TaskProgressResponse)
+		assert.Nil(t, json.Unmarshal(w.Body.Bytes(), resp))
+		return resp
+	}
+
+	// written before eta fields
+	resp := getProgress(`{"progress":0.5,"etaRelative":12,"state":{"sampling_step":10,"sampling_steps":20}}`)
+	assert.Equal(t, float32(12), *resp.EtaSeconds)
+	assert.Equal(t, 10, *resp.Step)
+	assert.Equal(t, 20, *resp.Steps)
+	// webui snake_case
+	resp = getProgress(`{"progress":0.5,"eta_relative":8.5,"state":{"sampling_step":3,"sampling_steps":30}}`)
+	assert.Equal(t, float32(8.5), *resp.EtaSeconds)
+	assert.Equal(t, float32(8.5), resp.EtaRelative)
+	assert.Equal(t, 3, *resp.Step)
+	// unknown
+	resp = getProgress(`{"progress":0.5}`)
+	assert.Nil(t, resp.EtaSeconds)
+	assert.Nil(t, resp.Step)
+
+	assert.Nil(t, taskStore.Update("task", map[string]interface{}{datastore.KTaskStatus: config.TASK_FINISH}))
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	p.GetTaskProgress(c, "task")
+	resp = new(models.TaskProgressResponse)
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), resp))
+	assert.Equal(t, float32(0), *resp.EtaSeconds)
+}
+
+func TestPredictFailStopProgress(t *testing.T) {
+	initTestConfig(t)
+	config.ConfigGlobal.ImageNameTemplate = config.DefaultImageNameTemplate
+	oss := mockOss(t, 0)
+	var lock sync.Mutex
+	predictBody := ""
+	sd := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == config.PROGRESS {
+			json.NewEncoder(w).Encode(models.ProgressResult{Progress: 0.3})
+			return
+		}
+		// render long enough for progress written
+		time.Sleep(3 * config.PROGRESS_INTERVAL * time.Millisecond)
+		lock.Lock()
+		defer lock.Unlock()
+		if predictBody == "" {
+			w.WriteHeader(http.StatusBadGateway)
+			w.Write([]byte("<html>bad gateway</html>"))
+			return
+		}
+		w.Write([]byte(predictBody))
+	}))
+	defer sd.Close()
+	config.ConfigGlobal.SdUrlPrefix = sd.URL
+	taskStore := datastore.NewSQLiteDatastore(datastore.NewSQLiteConfig(datastore.KTaskTableName))
+	defer taskStore.Close()
+	p := &ProxyHandler{taskStore: taskStore, httpClient: &http.Client{}}
+	predict := func(taskId string) {
+		assert.Nil(t, taskStore.Put(taskId, map[string]interface{}{
+			datastore.KTaskIdColumnName: taskId,
+			datastore.KTaskStatus:       config.TASK_QUEUE,
+		}))
+		_, _, err := p.predictTask("user", taskId, config.TXT2IMG, []byte("{}"), predictOptions{})
+		assert.NotNil(t, err)
+		task, err := taskStore.Get(taskId, []string{datastore.KTaskStatus, datastore.KTaskCode,
+			datastore.KTaskProgressColumnName})
+		assert.Nil(t, err)
+		assert.Equal(t, config.TASK_FAILED, task[datastore.KTaskStatus], taskId)
+		assert.Equal(t, int64(requestFail), task[datastore.KTaskCode], taskId)
+		// poller stopped before return
+		progress := task[datastore.KTaskProgressColumnName]
+		assert.NotNil(t, progress, taskId)
+		time.Sleep(2 * config.PROGRESS_INTERVAL * time.Millisecond)
+		task, err = taskStore.Get(taskId, []string{datastore.KTaskProgressColumnName})
+		assert.Nil(t, err)
+		assert.Equal(t, progress, task[datastore.KTaskProgressColumnName], taskId)
+	}
+
+	// webui response not json
+	predict("invalid")
+	// upload fail
+	lock.Lock()
+	predictBody = fmt.Sprintf(`{"images":["%s"],"info":"{}"}`, base64.StdEncoding.EncodeToString([]byte("image")))
+	lock.Unlock()
+	oss.failKey = "images/user/upload_1.png"
+	predict("upload")
+}
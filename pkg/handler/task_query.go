@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"fmt"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/datastore"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/models"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/module"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"sort"
+	"strconv"
+)
+
+const (
+	defaultTaskLimit = 20
+	maxTaskLimit     = 100
+	// rows read from db per request at most, page may have less than limit tasks before end
+	maxTaskScan   = 2000
+	taskScanBatch = 200
+)
+
+var taskListColumns = []string{datastore.KTaskIdColumnName, datastore.KTaskUser, datastore.KTaskStatus,
+	datastore.KTaskModel, datastore.KTaskCreateTime, datastore.KTaskModifyTime, datastore.KTaskGpuSeconds}
+
+// taskFilter empty field match all
+type taskFilter struct {
+	user   string
+	status string
+	model  string
+	from   *int64
+	to     *int64
+}
+
+// match convert matched row to task item
+func (f *taskFilter) match(taskId string, row map[string]interface{}) (models.TaskItem, bool) {
+	item := models.TaskItem{TaskId: taskId}
+	item.User, _ = row[datastore.KTaskUser].(string)
+	item.Status, _ = row[datastore.KTaskStatus].(string)
+	if (f.user != "" && item.User != f.user) || (f.status != "" && item.Status != f.status) {
+		return item, false
+	}
+	if model, ok := row[datastore.KTaskModel].(string); ok && model != "" {
+		item.Model = &model
+	}
+	if f.model != "" && (item.Model == nil || *item.Model != f.model) {
+		return item, false
+	}
+	item.CreateTime = parseTaskTime(row[datastore.KTaskCreateTime])
+	if (f.from != nil || f.to != nil) && item.CreateTime == nil {
+		return item, false
+	}
+	if (f.from != nil && *item.CreateTime < *f.from) || (f.to != nil && *item.CreateTime > *f.to) {
+		return item, false
+	}
+	item.ModifyTime = parseTaskTime(row[datastore.KTaskModifyTime])
+	if gpuSeconds, ok := row[datastore.KTaskGpuSeconds].(float64); ok {
+		item.GpuSeconds = &gpuSeconds
+	}
+	return item, true
+}
+
+// task time saved as timestamp string
+func parseTaskTime(val interface{}) *int64 {
+	str, ok := val.(string)
+	if !ok {
+		return nil
+	}
+	timestamp, err := strconv.ParseInt(str, 10, 64)
+	if err != nil {
+		return nil
+	}
+	return &timestamp
+}
+
+// scanTasks read tasks from cursor in task id order until limit matched or maxTaskScan rows read
+// return matched tasks and cursor of next page, empty cursor means no more tasks
+func (p *ProxyHandler) scanTasks(cursor string, limit int, filter *taskFilter) ([]models.TaskItem, string, error) {
+	tasks := make([]models.TaskItem, 0, limit)
+	for scanned := 0; scanned < maxTaskScan; {
+		rows, nextKey, err := p.taskStore.ListRange(cursor, taskScanBatch, taskListColumns)
+		if err != nil {
+			return nil, "", err
+		}
+		scanned += len(rows)
+		keys := make([]string, 0, len(rows))
+		for key := range rows {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for i, key := range keys {
+			if item, ok := filter.match(key, rows[key]); ok {
+				tasks = append(tasks, item)
+			}
+			if len(tasks) == limit {
+				if i+1 < len(keys) {
+					return tasks, keys[i+1], nil
+				}
+				return tasks, nextKey, nil
+			}
+		}
+		cursor = nextKey
+		if cursor == "" {
+			break
+		}
+	}
+	return tasks, cursor, nil
+}
+
+// ListTasks query tasks by user/status/create time/model, paginated by cursor
+// (GET /tasks)
+func (p *ProxyHandler) ListTasks(c *gin.Context, params models.ListTasksParams) {
+	limit := defaultTaskLimit
+	if params.Limit != nil {
+		limit = *params.Limit
+	}
+	if limit <= 0 || limit > maxTaskLimit {
+		handleError(c, http.StatusBadRequest, fmt.Sprintf("limit should between 1 and %d", maxTaskLimit))
+		return
+	}
+	if params.From != nil && params.To != nil && *params.From > *params.To {
+		handleError(c, http.StatusBadRequest, "from should <= to")
+		return
+	}
+	filter := &taskFilter{from: params.From, to: params.To}
+	if params.User != nil {
+		filter.user = *params.User
+	}
+	if params.Status != nil {
+		filter.status = *params.Status
+	}
+	if params.Model != nil {
+		filter.model = *params.Model
+	}
+	// non admin user only see own tasks
+	if username := c.GetHeader(userKey); config.ConfigGlobal.EnableLogin() && username != module.DefaultUser {
+		filter.user = username
+	}
+	cursor := ""
+	if params.Cursor != nil {
+		cursor = *params.Cursor
+	}
+	tasks, nextCursor, err := p.scanTasks(cursor, limit, filter)
+	if err != nil {
+		logrus.Errorf("list tasks err=%s", err.Error())
+		handleError(c, http.StatusInternalServerError, "read task from db error")
+		return
+	}
+	ret := models.TaskList{Tasks: tasks}
+	if nextCursor != "" {
+		ret.NextCursor = &nextCursor
+	}
+	c.JSON(http.StatusOK, ret)
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/datastore"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/models"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListTasks(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	initTestConfig(t)
+	taskStore := datastore.NewSQLiteDatastore(datastore.NewSQLiteConfig(datastore.KTaskTableName))
+	defer taskStore.Close()
+	for i := 0; i < 10; i++ {
+		user, status, model := "user1", config.TASK_FINISH, "sd.safetensors"
+		if i%2 == 1 {
+			user, status, model = "user2", config.TASK_FAILED, "xl.safetensors"
+		}
+		taskId := fmt.Sprintf("task%02d", i)
+		assert.Nil(t, taskStore.Put(taskId, map[string]interface{}{
+			datastore.KTaskIdColumnName: taskId,
+			datastore.KTaskUser:         user,
+			datastore.KTaskStatus:       status,
+			datastore.KTaskModel:        model,
+			datastore.KTaskCreateTime:   fmt.Sprintf("%d", 1000+i),
+			datastore.KTaskGpuSeconds:   float64(i),
+		}))
+	}
+	p := &ProxyHandler{taskStore: taskStore}
+	list := func(params models.ListTasksParams, user string) (int, models.TaskList) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/tasks", nil)
+		c.Request.Header.Set(userKey, user)
+		p.ListTasks(c, params)
+		var ret models.TaskList
+		json.Unmarshal(w.Body.Bytes(), &ret)
+		return w.Code, ret
+	}
+	taskIds := func(tasks []models.TaskItem) []string {
+		ret := make([]string, 0, len(tasks))
+		for _, task := range tasks {
+			ret = append(ret, task.TaskId)
+		}
+		return ret
+	}
+
+	// filter by status and create time, paginated
+	limit, overLimit := 2, maxTaskLimit+1
+	params := models.ListTasksParams{Status: utils.String(config.TASK_FINISH), From: utils.Int64(1002),
+		To: utils.Int64(1008), Limit: &limit}
+	code, ret := list(params, "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []string{"task02", "task04"}, taskIds(ret.Tasks))
+	assert.Equal(t, "sd.safetensors", *ret.Tasks[0].Model)
+	assert.Equal(t, int64(1002), *ret.Tasks[0].CreateTime)
+	assert.NotNil(t, ret.NextCursor)
+	params.Cursor = ret.NextCursor
+	_, ret = list(params, "")
+	assert.Equal(t, []string{"task06", "task08"}, taskIds(ret.Tasks))
+	params.Cursor = ret.NextCursor
+	_, ret = list(params, "")
+	assert.Empty(t, ret.Tasks)
+	assert.Nil(t, ret.NextCursor)
+
+	// filter by model
+	_, ret = list(models.ListTasksParams{Model: utils.String("xl.safetensors")}, "")
+	assert.Equal(t, []string{"task01", "task03", "task05", "task07", "task09"}, taskIds(ret.Tasks))
+	assert.Nil(t, ret.NextCursor)
+
+	// login on, user only see own tasks
+	config.ConfigGlobal.LoginSwitch = "on"
+	_, ret = list(models.ListTasksParams{User: utils.String("user1")}, "user2")
+	assert.Equal(t, 5, len(ret.Tasks))
+	assert.Equal(t, "user2", ret.Tasks[0].User)
+
+	// invalid params
+	code, _ = list(models.ListTasksParams{Limit: &overLimit}, "")
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _ = list(models.ListTasksParams{From: utils.Int64(2), To: utils.Int64(1)}, "")
+	assert.Equal(t, http.StatusBadRequest, code)
+}
@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/datastore"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/models"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// queueFullRetryAfter Retry-After(s) hint when queue full
+const queueFullRetryAfter = 10
+
+// errQueueFull pending tasks reach maxQueueLength
+var errQueueFull = errors.New("too many pending tasks, please retry later")
+
+// staleTaskAge unfinished task created earlier treated as lost, fc async invocation expire in 24h
+const staleTaskAge = 24 * time.Hour
+
+var activeTaskColumns = []string{datastore.KTaskIdColumnName, datastore.KTaskUser, datastore.KTaskModel,
+	datastore.KTaskStatus, datastore.KTaskCreateTime}
+
+// listActiveTasks queued and rendering task rows of all instances read by status index, stale rows dropped
+func listActiveTasks(taskStore datastore.Datastore) (map[string]map[string]interface{}, error) {
+	since := utils.TimestampS() - int64(staleTaskAge/time.Second)
+	active := make(map[string]map[string]interface{})
+	for _, status := range []string{config.TASK_QUEUE, config.TASK_INPROGRESS} {
+		rows, err := taskStore.ListIndexRange(datastore.KTaskStatus, status, status, activeTaskColumns)
+		if err != nil {
+			return nil, err
+		}
+		for taskId, row := range rows {
+			if createTime := parseTaskTime(row[datastore.KTaskCreateTime]); createTime != nil && *createTime >= since {
+				active[taskId] = row
+			}
+		}
+	}
+	return active, nil
+}
+
+// taskQueue count pending tasks (queued or rendering) per model from task rows not terminal,
+// plus tasks entered and not written yet, so async tasks counted until terminal on all instances
+type taskQueue struct {
+	lock      sync.Mutex
+	taskStore datastore.Datastore
+	// tasks entered, not written to task table yet
+	total   int
+	lengths map[string]int
+}
+
+func newTaskQueue(taskStore datastore.Datastore) *taskQueue {
+	return &taskQueue{taskStore: taskStore, lengths: make(map[string]int)}
+}
+
+// counts pending tasks in total and per model, only entered tasks counted when read task table fail
+func (q *taskQueue) counts() (int, map[string]int) {
+	total := q.total
+	lengths := make(map[string]int, len(q.lengths))
+	for sdModel, length := range q.lengths {
+		lengths[sdModel] = length
+	}
+	if q.taskStore == nil {
+		return total, lengths
+	}
+	rows, err := listActiveTasks(q.taskStore)
+	if err != nil {
+		logrus.Warnf("list active tasks err=%s", err.Error())
+		return total, lengths
+	}
+	for _, row := range rows {
+		sdModel, _ := row[datastore.KTaskModel].(string)
+		total++
+		lengths[sdModel]++
+	}
+	return total, lengths
+}
+
+// enter count n tasks of sdModel, errQueueFull when total or model limit reached,
+// call leave once task rows written(counted by row) or not submitted, nil queue no count
+func (q *taskQueue) enter(sdModel string, n int) (leave func(), err error) {
+	if q == nil {
+		return func() {}, nil
+	}
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	total, lengths := q.counts()
+	if max := config.ConfigGlobal.MaxQueueLength; max > 0 && total+n > max {
+		return nil, errQueueFull
+	}
+	if max := config.ConfigGlobal.GetMaxQueueLength(sdModel); max > 0 && lengths[sdModel]+n > max {
+		return nil, errQueueFull
+	}
+	q.total += n
+	q.lengths[sdModel] += n
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			q.lock.Lock()
+			defer q.lock.Unlock()
+			q.total -= n
+			if q.lengths[sdModel] -= n; q.lengths[sdModel] <= 0 {
+				delete(q.lengths, sdModel)
+			}
+		})
+	}, nil
+}
+
+// status queue length of models pending or limited, sorted by model
+func (q *taskQueue) status() models.TaskQueue {
+	q.lock.Lock()
+	total, lengths := q.counts()
+	q.lock.Unlock()
+	ret := models.TaskQueue{
+		Length:    total,
+		MaxLength: config.ConfigGlobal.MaxQueueLength,
+		Models:    make([]models.ModelQueue, 0, len(lengths)),
+	}
+	for sdModel, length := range lengths {
+		ret.Models = append(ret.Models, models.ModelQueue{Model: sdModel, Length: length,
+			MaxLength: config.ConfigGlobal.GetMaxQueueLength(sdModel)})
+	}
+	for sdModel, max := range config.ConfigGlobal.ModelMaxQueueLength {
+		if _, ok := lengths[sdModel]; !ok {
+			ret.Models = append(ret.Models, models.ModelQueue{Model: sdModel, MaxLength: max})
+		}
+	}
+	sort.Slice(ret.Models, func(i, j int) bool {
+		return ret.Models[i].Model < ret.Models[j].Model
+	})
+	return ret
+}
+
+// handleQueueFull reject task with 429 and Retry-After hint
+func handleQueueFull(c *gin.Context, taskId string) {
+	c.Header("Retry-After", fmt.Sprintf("%d", queueFullRetryAfter))
+	c.JSON(http.StatusTooManyRequests, models.SubmitTaskResponse{
+		TaskId:  taskId,
+		Status:  config.TASK_FAILED,
+		Message: utils.String(errQueueFull.Error()),
+	})
+}
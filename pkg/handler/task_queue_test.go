@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/datastore"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/models"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTaskQueue(t *testing.T) {
+	initTestConfig(t)
+	config.ConfigGlobal.MaxQueueLength = 3
+	config.ConfigGlobal.ModelMaxQueueLength = map[string]int{"sd_xl": 1, "idle": 2}
+	taskStore := datastore.NewSQLiteDatastore(datastore.NewSQLiteConfig(datastore.KTaskTableName))
+	defer taskStore.Close()
+	q := newTaskQueue(taskStore)
+	putTask := func(taskId, sdModel, status string, createTime int64) {
+		assert.Nil(t, taskStore.Put(taskId, map[string]interface{}{
+			datastore.KTaskIdColumnName: taskId,
+			datastore.KTaskModel:        sdModel,
+			datastore.KTaskStatus:       status,
+			datastore.KTaskCreateTime:   fmt.Sprintf("%d", createTime),
+		}))
+	}
+
+	leaveXl, err := q.enter("sd_xl", 1)
+	assert.Nil(t, err)
+	// model limit
+	_, err = q.enter("sd_xl", 1)
+	assert.ErrorIs(t, err, errQueueFull)
+	leave1, err := q.enter("v1-5", 1)
+	assert.Nil(t, err)
+	// written row counted after leave, async task held until terminal
+	putTask("task1", "v1-5", config.TASK_QUEUE, utils.TimestampS())
+	leave1()
+	_, err = q.enter("v1-5", 1)
+	assert.Nil(t, err)
+	// global limit
+	_, err = q.enter("v1-5", 1)
+	assert.ErrorIs(t, err, errQueueFull)
+	assert.Equal(t, models.TaskQueue{Length: 3, MaxLength: 3, Models: []models.ModelQueue{
+		{Model: "idle", MaxLength: 2},
+		{Model: "sd_xl", Length: 1, MaxLength: 1},
+		{Model: "v1-5", Length: 2},
+	}}, q.status())
+
+	// leave idempotent, terminal and stale rows not counted
+	leaveXl()
+	leaveXl()
+	putTask("task1", "v1-5", config.TASK_FINISH, utils.TimestampS())
+	putTask("lost", "sd_xl", config.TASK_INPROGRESS, utils.TimestampS()-int64(staleTaskAge.Seconds())-1)
+	status := q.status()
+	assert.Equal(t, 1, status.Length)
+	assert.Equal(t, models.ModelQueue{Model: "sd_xl", MaxLength: 1}, status.Models[1])
+	_, err = q.enter("sd_xl", 1)
+	assert.Nil(t, err)
+	// batch entered as a whole
+	_, err = q.enter("v1-5", 2)
+	assert.ErrorIs(t, err, errQueueFull)
+
+	// no limit
+	config.ConfigGlobal.MaxQueueLength = 0
+	config.ConfigGlobal.ModelMaxQueueLength = nil
+	for i := 0; i < 10; i++ {
+		_, err = q.enter("v1-5", 1)
+		assert.Nil(t, err)
+	}
+}
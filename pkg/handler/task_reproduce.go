@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/datastore"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/models"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strings"
+)
+
+// api path of recorded request, resubmit to it
+const (
+	txt2ImgPath = "/txt2img"
+	img2ImgPath = "/img2img"
+)
+
+// redactedImage placeholder of base64 image in recorded request, replace it with oss path before resubmit
+const redactedImage = "<redacted base64 image>"
+
+// string shorter than it never treated as base64 image
+const minBase64ImageLen = 256
+
+// GetTaskReproduce request body of task ready to resubmit, task owner only
+// (GET /tasks/{taskId}/reproduce)
+func (p *ProxyHandler) GetTaskReproduce(c *gin.Context, taskId string) {
+	data, err := p.taskStore.Get(taskId, []string{datastore.KTaskUser, datastore.KTaskRequest, datastore.KTaskInfo})
+	if err != nil || len(data) == 0 {
+		handleError(c, http.StatusNotFound, "not found")
+		return
+	}
+	if config.ConfigGlobal.EnableLogin() && data[datastore.KTaskUser] != c.GetHeader(userKey) {
+		handleError(c, http.StatusForbidden, "only task owner can reproduce")
+		return
+	}
+	ret := new(models.TaskReproduceResponse)
+	val, _ := data[datastore.KTaskRequest].(string)
+	if val == "" || json.Unmarshal([]byte(val), ret) != nil {
+		handleError(c, http.StatusNotFound, "task request not recorded")
+		return
+	}
+	info, _ := data[datastore.KTaskInfo].(string)
+	resolveSeeds(ret.Request, info)
+	c.JSON(http.StatusOK, ret)
+}
+
+// resolveSeeds random seed(-1 or not set) of request replaced by seed webui used from task info,
+// so resubmit render the same images, subseed replaced only when subseed_strength used
+func resolveSeeds(request map[string]interface{}, info string) {
+	var seeds struct {
+		Seed    *int64 `json:"seed"`
+		Subseed *int64 `json:"subseed"`
+	}
+	if info == "" || json.Unmarshal([]byte(info), &seeds) != nil {
+		return
+	}
+	if seeds.Seed != nil && !fixedSeed(request["seed"]) {
+		request["seed"] = *seeds.Seed
+	}
+	if strength, ok := request["subseed_strength"].(float64); ok && strength > 0 &&
+		seeds.Subseed != nil && !fixedSeed(request["subseed"]) {
+		request["subseed"] = *seeds.Subseed
+	}
+}
+
+// taskRequestVal recorded request of task, force_task_id dropped so resubmit create new task,
+// base64 images redacted to keep row small, empty when request not marshal
+func taskRequestVal(path string, request interface{}) string {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return ""
+	}
+	fields := make(map[string]interface{})
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return ""
+	}
+	delete(fields, "force_task_id")
+	redactImages(fields)
+	val, err := json.Marshal(models.TaskReproduceResponse{Path: path, Request: fields})
+	if err != nil {
+		return ""
+	}
+	return string(val)
+}
+
+// redactImages replace base64 images of nested request in place, oss path and other strings kept
+func redactImages(val interface{}) interface{} {
+	switch v := val.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = redactImages(item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactImages(item)
+		}
+	case string:
+		if isBase64Image(v) {
+			return redactedImage
+		}
+	}
+	return val
+}
+
+// data uri or long string of base64 alphabet only, prompt has spaces and punctuation
+func isBase64Image(str string) bool {
+	if strings.HasPrefix(str, "data:image/") {
+		return true
+	}
+	if len(str) < minBase64ImageLen || isImgPath(str) {
+		return false
+	}
+	if _, err := base64.StdEncoding.DecodeString(str); err == nil {
+		return true
+	}
+	_, err := base64.RawStdEncoding.DecodeString(str)
+	return err == nil
+}
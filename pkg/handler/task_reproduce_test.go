@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/datastore"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/models"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTaskRequestVal(t *testing.T) {
+	blob := base64.StdEncoding.EncodeToString(make([]byte, 300))
+	request := &models.Img2ImgRequest{
+		ForceTaskId:          utils.String("task"),
+		Prompt:               utils.String(strings.Repeat("a cat, ", 64)),
+		StableDiffusionModel: "sd.safetensors",
+		InitImages:           &[]string{"images/init.png", blob},
+		Mask:                 utils.String("data:image/png;base64,xxx"),
+		AlwaysonScripts: &map[string]interface{}{"controlnet": map[string]interface{}{
+			"args": []interface{}{map[string]interface{}{"input_image": blob, "model": "canny"}},
+		}},
+	}
+	var ret models.TaskReproduceResponse
+	assert.Nil(t, json.Unmarshal([]byte(taskRequestVal(img2ImgPath, request)), &ret))
+	assert.Equal(t, img2ImgPath, ret.Path)
+	assert.NotContains(t, ret.Request, "force_task_id")
+	assert.Equal(t, *request.Prompt, ret.Request["prompt"])
+	assert.Equal(t, []interface{}{"images/init.png", redactedImage}, ret.Request["init_images"])
+	assert.Equal(t, redactedImage, ret.Request["mask"])
+	controlNet := ret.Request["alwayson_scripts"].(map[string]interface{})["controlnet"].(map[string]interface{})
+	unit := controlNet["args"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, redactedImage, unit["input_image"])
+	assert.Equal(t, "canny", unit["model"])
+}
+
+func TestGetTaskReproduce(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	initTestConfig(t)
+	config.ConfigGlobal.LoginSwitch = "on"
+	taskStore := datastore.NewSQLiteDatastore(datastore.NewSQLiteConfig(datastore.KTaskTableName))
+	defer taskStore.Close()
+	assert.Nil(t, taskStore.Put("task", map[string]interface{}{
+		datastore.KTaskIdColumnName: "task",
+		datastore.KTaskUser:         "alice",
+		datastore.KTaskRequest: taskRequestVal(txt2ImgPath, &models.Txt2ImgRequest{
+			ForceTaskId: "task", Prompt: utils.String("cat"), StableDiffusionModel: "sd.safetensors"}),
+	}))
+	assert.Nil(t, taskStore.Put("old", map[string]interface{}{
+		datastore.KTaskIdColumnName: "old",
+		datastore.KTaskUser:         "alice",
+	}))
+	p := &ProxyHandler{taskStore: taskStore}
+	reproduce := func(user, taskId string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/tasks/"+taskId+"/reproduce", nil)
+		c.Request.Header.Set(userKey, user)
+		p.GetTaskReproduce(c, taskId)
+		return w
+	}
+
+	w := reproduce("alice", "task")
+	assert.Equal(t, http.StatusOK, w.Code)
+	var ret models.TaskReproduceResponse
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &ret))
+	assert.Equal(t, txt2ImgPath, ret.Path)
+	assert.Equal(t, "cat", ret.Request["prompt"])
+	assert.Equal(t, "sd.safetensors", ret.Request["stable_diffusion_model"])
+
+	// random seed not recorded yet
+	assert.Nil(t, ret.Request["seed"])
+
+	// seed webui used replace random seed
+	assert.Nil(t, taskStore.Update("task", map[string]interface{}{
+		datastore.KTaskRequest: taskRequestVal(txt2ImgPath, map[string]interface{}{"prompt": "cat", "seed": -1,
+			"subseed": -1, "subseed_strength": 0.5}),
+		datastore.KTaskInfo: `{"seed": 1234, "subseed": 5678, "all_seeds": [1234, 1235]}`,
+	}))
+	ret = models.TaskReproduceResponse{}
+	assert.Nil(t, json.Unmarshal(reproduce("alice", "task").Body.Bytes(), &ret))
+	assert.Equal(t, float64(1234), ret.Request["seed"])
+	assert.Equal(t, float64(5678), ret.Request["subseed"])
+	// fixed seed kept
+	request := map[string]interface{}{"seed": float64(7), "subseed": float64(-1)}
+	resolveSeeds(request, `{"seed": 1234, "subseed": 5678}`)
+	assert.Equal(t, map[string]interface{}{"seed": float64(7), "subseed": float64(-1)}, request)
+
+	assert.Equal(t, http.StatusForbidden, reproduce("bob", "task").Code)
+	assert.Equal(t, http.StatusNotFound, reproduce("alice", "old").Code)
+	assert.Equal(t, http.StatusNotFound, reproduce("alice", "unknown").Code)
+}
@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"fmt"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/models"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/module"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"sync"
+)
+
+const (
+	// max task ids of one batch result request
+	maxBatchTaskResults = 100
+	// concurrent task store reads of one batch
+	batchTaskReadConcurrency = 8
+)
+
+// GetTaskResults get results of a batch of tasks, task store read with bounded concurrency
+// and oss urls of all images signed at once
+// (POST /tasks/results)
+func (p *ProxyHandler) GetTaskResults(c *gin.Context) {
+	request := new(models.GetTaskResultsJSONRequestBody)
+	if err := getBindResult(c, request); err != nil {
+		handleError(c, http.StatusBadRequest, config.BADREQUEST)
+		return
+	}
+	taskIds := make([]string, 0, len(request.TaskIds))
+	seen := make(map[string]bool)
+	for _, taskId := range request.TaskIds {
+		if taskId != "" && !seen[taskId] {
+			seen[taskId] = true
+			taskIds = append(taskIds, taskId)
+		}
+	}
+	if len(taskIds) == 0 || len(taskIds) > maxBatchTaskResults {
+		handleError(c, http.StatusBadRequest, fmt.Sprintf("taskIds count should between 1 and %d",
+			maxBatchTaskResults))
+		return
+	}
+	c.JSON(http.StatusOK, p.getTaskResults(taskIds))
+}
+
+// getTaskResults results of tasks in order, tasks not found or failed to read in notFound
+func (p *ProxyHandler) getTaskResults(taskIds []string) models.TaskResultsResult {
+	results := make([]*models.TaskResultResponse, len(taskIds))
+	sem := make(chan struct{}, batchTaskReadConcurrency)
+	var wg sync.WaitGroup
+	for i := range taskIds {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			data, err := p.taskStore.Get(taskIds[i], taskResultColumns)
+			if err != nil || len(data) == 0 {
+				return
+			}
+			if result, err := taskResultFromData(taskIds[i], data); err == nil {
+				results[i] = result
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	ret := models.TaskResultsResult{
+		Results:  make([]models.TaskResultResponse, 0, len(taskIds)),
+		NotFound: make([]string, 0),
+	}
+	images := make([]string, 0)
+	for i, result := range results {
+		if result == nil {
+			ret.NotFound = append(ret.NotFound, taskIds[i])
+			continue
+		}
+		images = append(images, *result.Images...)
+		ret.Results = append(ret.Results, *result)
+	}
+	if len(images) == 0 {
+		return ret
+	}
+	ossUrl, err := module.OssGlobal.GetUrl(images)
+	if err != nil || len(ossUrl) != len(images) {
+		logrus.Warn("get oss url error")
+		return ret
+	}
+	for i := range ret.Results {
+		n := len(*ret.Results[i].Images)
+		*ret.Results[i].OssUrl, ossUrl = ossUrl[:n:n], ossUrl[n:]
+	}
+	return ret
+}
@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/datastore"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/models"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetTaskResults(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	initTestConfig(t)
+	mockOss(t, 0)
+	taskStore := datastore.NewSQLiteDatastore(datastore.NewSQLiteConfig(datastore.KTaskTableName))
+	defer taskStore.Close()
+	p := &ProxyHandler{taskStore: taskStore}
+	assert.Nil(t, taskStore.Put("finished", map[string]interface{}{
+		datastore.KTaskIdColumnName: "finished",
+		datastore.KTaskStatus:       config.TASK_FINISH,
+		datastore.KTaskCode:         int64(requestOk),
+		datastore.KTaskImage:        "images/finished_1.png,images/finished_2.png",
+		datastore.KTaskParams:       `{"prompt":"cat"}`,
+		datastore.KTaskInfo:         `{"seed":1}`,
+	}))
+	assert.Nil(t, taskStore.Put("running", map[string]interface{}{
+		datastore.KTaskIdColumnName: "running",
+		datastore.KTaskStatus:       config.TASK_INPROGRESS,
+		datastore.KTaskImage:        "images/running_1.png",
+	}))
+	assert.Nil(t, taskStore.Put("failed", map[string]interface{}{
+		datastore.KTaskIdColumnName: "failed",
+		datastore.KTaskStatus:       config.TASK_FAILED,
+		datastore.KTaskCode:         int64(requestFail),
+	}))
+	submit := func(taskIds []string) *httptest.ResponseRecorder {
+		data, _ := json.Marshal(models.TaskResultsRequest{TaskIds: taskIds})
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/tasks/results", bytes.NewReader(data))
+		p.GetTaskResults(c)
+		return w
+	}
+
+	w := submit([]string{"running", "unknown", "finished", "failed", "running"})
+	assert.Equal(t, http.StatusOK, w.Code)
+	var result models.TaskResultsResult
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, []string{"unknown"}, result.NotFound)
+	assert.Len(t, result.Results, 3)
+	running, finished, failed := result.Results[0], result.Results[1], result.Results[2]
+	assert.Equal(t, "running", running.TaskId)
+	assert.True(t, *running.Partial)
+	assert.Equal(t, []string{"http://oss/images/running_1.png"}, *running.OssUrl)
+	assert.Equal(t, config.TASK_FINISH, finished.Status)
+	assert.Equal(t, []string{"http://oss/images/finished_1.png", "http://oss/images/finished_2.png"},
+		*finished.OssUrl)
+	assert.Equal(t, "cat", (*finished.Parameters)["prompt"])
+	assert.Equal(t, config.TASK_FAILED, failed.Status)
+	assert.Empty(t, *failed.OssUrl)
+
+	// same as single task result
+	single, err := p.getTaskResult("finished")
+	assert.Nil(t, err)
+	assert.Equal(t, *single.OssUrl, *finished.OssUrl)
+
+	assert.Equal(t, http.StatusBadRequest, submit(nil).Code)
+	taskIds := make([]string, 0, maxBatchTaskResults+1)
+	for i := 0; i <= maxBatchTaskResults; i++ {
+		taskIds = append(taskIds, fmt.Sprintf("task%d", i))
+	}
+	assert.Equal(t, http.StatusBadRequest, submit(taskIds).Code)
+	assert.Equal(t, http.StatusOK, submit(taskIds[:maxBatchTaskResults]).Code)
+}
@@ -0,0 +1,219 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/datastore"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/models"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"sync"
+)
+
+// max prompts of one txt2img multi request
+const maxMultiPrompts = 16
+
+// multiTask one prompt task of txt2img multi
+type multiTask struct {
+	taskId  string
+	request *models.Txt2ImgRequest
+}
+
+// Txt2ImgMulti create one txt2img task per prompt with shared params, render all before reply so
+// rendering not detached from request lifetime(instance may be frozen or recycled after reply)
+// (POST /txt2img/multi)
+func (p *ProxyHandler) Txt2ImgMulti(c *gin.Context) {
+	username := c.GetHeader(userKey)
+	if username == "" {
+		if config.ConfigGlobal.EnableLogin() {
+			handleError(c, http.StatusBadRequest, config.BADREQUEST)
+			return
+		}
+		username = DEFAULT_USER
+	}
+	// result images got by task id
+	if rejectInlineImages(c) {
+		return
+	}
+	var multi struct {
+		Prompts []string        `json:"prompts"`
+		Params  json.RawMessage `json:"params"`
+	}
+	if err := getBindResult(c, &multi); err != nil || len(multi.Params) == 0 {
+		handleError(c, http.StatusBadRequest, config.BADREQUEST)
+		return
+	}
+	if len(multi.Prompts) == 0 || len(multi.Prompts) > maxMultiPrompts {
+		handleError(c, http.StatusBadRequest, fmt.Sprintf("prompts count should between 1 and %d",
+			maxMultiPrompts))
+		return
+	}
+	params := new(models.Txt2ImgRequest)
+	if err := p.unmarshalWithModelDefaults(multi.Params, params); err != nil {
+		handleBindError(c, err)
+		return
+	}
+	if params.ForceTaskId != "" {
+		handleError(c, http.StatusBadRequest, "force_task_id not support in multi, task id generated per prompt")
+		return
+	}
+	// shared by all tasks
+	labels, err := taskLabelsVal(params.Labels)
+	if err != nil {
+		handleError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	params.Labels = nil
+	clamped, err := applyRequestLimit(username, params.CfgScale, params.Steps, params.HrSecondPassSteps)
+	if err != nil {
+		handleError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	// likely oom fail early instead of crash webui
+	if err := checkGpuMemory(params.StableDiffusionModel, txt2ImgPixels(params), params.BatchSize); err != nil {
+		handleError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if !checkSdModelValid(params.StableDiffusionModel) {
+		handleError(c, http.StatusBadRequest, "stable_diffusion_model val not valid, please set valid val")
+		return
+	}
+	if err := validateSamplers(p.httpClient, config.ConfigGlobal.SdUrlPrefix, params.SamplerName,
+		params.SamplerIndex, params.HrSamplerName); err != nil {
+		handleError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	outputPrefix, code, err := resolveOutputPrefix(username, params.OutputPrefix)
+	if err != nil {
+		handleError(c, code, err.Error())
+		return
+	}
+	postProcess, err := normalizePostProcess(params.PostProcess)
+	if err != nil {
+		handleError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if config.ConfigGlobal.IsServerTypeMatch(config.PROXY) {
+		if code, err := p.ensureModelExist(params.StableDiffusionModel); err != nil {
+			handleError(c, code, err.Error())
+			return
+		}
+	}
+	shared, err := json.Marshal(params)
+	if err != nil {
+		handleError(c, http.StatusBadRequest, config.BADREQUEST)
+		return
+	}
+
+	// check all prompts before create any task
+	tasks := make([]*multiTask, 0, len(multi.Prompts))
+	for i := range multi.Prompts {
+		// each task own copy, request mutated when render
+		request := new(models.Txt2ImgRequest)
+		if err := json.Unmarshal(shared, request); err != nil {
+			handleError(c, http.StatusBadRequest, config.BADREQUEST)
+			return
+		}
+		request.Prompt = utils.String(multi.Prompts[i])
+		if err := p.prepareTxt2ImgPrompts(username, request); err != nil {
+			handleError(c, http.StatusBadRequest, fmt.Sprintf("prompts[%d]: %s", i, err.Error()))
+			return
+		}
+		request.ForceTaskId = utils.RandStr(taskIdLength)
+		tasks = append(tasks, &multiTask{taskId: request.ForceTaskId, request: request})
+	}
+	leaveUser, rejected := p.enterUserTasks(c, username, "", len(tasks))
+	if rejected {
+		return
+	}
+	// tasks counted by rows until terminal once written
+	defer leaveUser()
+	// whole batch queued or rejected
+	leaveQueue, err := p.taskQueue.enter(params.StableDiffusionModel, len(tasks))
+	if err != nil {
+		handleQueueFull(c, "")
+		return
+	}
+	defer leaveQueue()
+	taskIds := make([]string, 0, len(tasks))
+	for i, task := range tasks {
+		if err := p.taskStore.Put(task.taskId, map[string]interface{}{
+			datastore.KTaskIdColumnName: task.taskId,
+			datastore.KTaskUser:         username,
+			datastore.KTaskStatus:       config.TASK_QUEUE,
+			datastore.KTaskCancel:       int64(config.CANCEL_INIT),
+			datastore.KTaskCreateTime:   fmt.Sprintf("%d", utils.TimestampS()),
+			datastore.KTaskModel:        params.StableDiffusionModel,
+			datastore.KTaskLabels:       labels,
+			datastore.KTaskRequest:      taskRequestVal(txt2ImgPath, task.request),
+		}); err != nil {
+			logrus.WithFields(logrus.Fields{"taskId": task.taskId}).Errorf("put db err=%s", err.Error())
+			// batch not submitted, tasks created already failed
+			for _, task := range tasks[:i] {
+				p.multiTaskFail(task.taskId, err)
+			}
+			handleError(c, http.StatusInternalServerError, config.OTSPUTERROR)
+			return
+		}
+		taskIds = append(taskIds, task.taskId)
+	}
+	p.renderMulti(username, c.GetHeader(versionKey), tasks, predictOptions{
+		outputPrefix: outputPrefix,
+		postProcess:  postProcess,
+		sdModel:      params.StableDiffusionModel,
+	})
+	c.JSON(http.StatusOK, models.Txt2ImgMultiResult{TaskIds: taskIds, Clamped: clampedNotes(clamped)})
+}
+
+// renderMulti render tasks of txt2img multi, at most predictConcurrency at the same time
+// so queued tasks not fail by predict queue timeout
+func (p *ProxyHandler) renderMulti(username, configVer string, tasks []*multiTask, opts predictOptions) {
+	workers := len(tasks)
+	if concurrency := config.ConfigGlobal.PredictConcurrency; concurrency > 0 && concurrency < workers {
+		workers = concurrency
+	}
+	taskCh := make(chan *multiTask)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for task := range taskCh {
+				p.renderMultiTask(username, configVer, task, opts)
+			}
+		}()
+	}
+	for _, task := range tasks {
+		taskCh <- task
+	}
+	close(taskCh)
+	wg.Wait()
+}
+
+// renderMultiTask render one task of txt2img multi
+func (p *ProxyHandler) renderMultiTask(username, configVer string, task *multiTask, opts predictOptions) {
+	body, _, err := p.txt2ImgBody(username, task.taskId, configVer, task.request)
+	if err != nil {
+		p.multiTaskFail(task.taskId, err)
+		return
+	}
+	// task status updated by predictTask
+	if _, _, err := p.predictTask(username, task.taskId, config.TXT2IMG, body, opts); err != nil {
+		logrus.WithFields(logrus.Fields{"taskId": task.taskId}).Warnf("multi task predict err=%s", err.Error())
+	}
+}
+
+// multiTaskFail mark multi task failed before predict
+func (p *ProxyHandler) multiTaskFail(taskId string, err error) {
+	if _, updateErr := p.updateTaskStatus(taskId, map[string]interface{}{
+		datastore.KTaskCode:       int64(requestFail),
+		datastore.KTaskStatus:     config.TASK_FAILED,
+		datastore.KTaskInfo:       err.Error(),
+		datastore.KTaskModifyTime: fmt.Sprintf("%d", utils.TimestampS()),
+	}); updateErr != nil {
+		logrus.WithFields(logrus.Fields{"taskId": taskId}).Errorf("update task err=%s", updateErr.Error())
+	}
+}
@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/datastore"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/models"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTxt2ImgMulti(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	initTestConfig(t)
+	config.ConfigGlobal.ServerName = config.PROXY
+	config.ConfigGlobal.ImageNameTemplate = config.DefaultImageNameTemplate
+	config.ConfigGlobal.PredictConcurrency = 1
+	mockOss(t, 0)
+	var lock sync.Mutex
+	prompts := make(map[string]string)
+	var running, maxRunning int32
+	sd := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != config.TXT2IMG {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if n := atomic.AddInt32(&running, 1); n > atomic.LoadInt32(&maxRunning) {
+			atomic.StoreInt32(&maxRunning, n)
+		}
+		defer atomic.AddInt32(&running, -1)
+		var request models.Txt2ImgRequest
+		assert.Nil(t, json.NewDecoder(r.Body).Decode(&request))
+		assert.Equal(t, "blurry", *request.NegativePrompt)
+		lock.Lock()
+		prompts[request.ForceTaskId] = *request.Prompt
+		lock.Unlock()
+		time.Sleep(20 * time.Millisecond)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"images": []string{base64.StdEncoding.EncodeToString([]byte("image"))}, "info": "{}"})
+	}))
+	defer sd.Close()
+	config.ConfigGlobal.SdUrlPrefix = sd.URL
+	taskStore := datastore.NewSQLiteDatastore(datastore.NewSQLiteConfig(datastore.KTaskTableName))
+	defer taskStore.Close()
+	configStore := datastore.NewSQLiteDatastore(datastore.NewSQLiteConfig(datastore.KConfigTableName))
+	defer configStore.Close()
+	p := &ProxyHandler{taskStore: taskStore, configStore: configStore, httpClient: &http.Client{},
+		taskQueue: newTaskQueue(taskStore)}
+	submit := func(body map[string]interface{}) *httptest.ResponseRecorder {
+		data, _ := json.Marshal(body)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/txt2img/multi", bytes.NewReader(data))
+		c.Request.Header.Set(userKey, "user")
+		p.Txt2ImgMulti(c)
+		return w
+	}
+	params := map[string]interface{}{
+		"stable_diffusion_model": "sd.safetensors",
+		"negative_prompt":        "blurry",
+		"labels":                 map[string]string{"jobId": "job1"},
+	}
+
+	w := submit(map[string]interface{}{"prompts": []string{"cat", "dog", "fox"}, "params": params})
+	assert.Equal(t, http.StatusOK, w.Code)
+	var result models.Txt2ImgMultiResult
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Len(t, result.TaskIds, 3)
+	// rendered before reply
+	for _, taskId := range result.TaskIds {
+		task, err := taskStore.Get(taskId, []string{datastore.KTaskStatus})
+		assert.Nil(t, err)
+		assert.Equal(t, config.TASK_FINISH, task[datastore.KTaskStatus])
+	}
+	lock.Lock()
+	for i, prompt := range []string{"cat", "dog", "fox"} {
+		assert.Equal(t, prompt, prompts[result.TaskIds[i]])
+	}
+	lock.Unlock()
+	// fan out bound by predictConcurrency
+	assert.Equal(t, int32(1), atomic.LoadInt32(&maxRunning))
+	task, err := taskStore.Get(result.TaskIds[0], []string{datastore.KTaskUser, datastore.KTaskLabels})
+	assert.Nil(t, err)
+	assert.Equal(t, "user", task[datastore.KTaskUser])
+	assert.Equal(t, map[string]string{"jobId": "job1"}, *taskLabels(task))
+	assert.Equal(t, 0, p.taskQueue.status().Length)
+
+	// invalid batch create no task
+	assert.Equal(t, http.StatusBadRequest, submit(map[string]interface{}{"prompts": []string{}, "params": params}).Code)
+	params["force_task_id"] = "task"
+	assert.Equal(t, http.StatusBadRequest,
+		submit(map[string]interface{}{"prompts": []string{"cat"}, "params": params}).Code)
+	delete(params, "force_task_id")
+	// whole batch rejected when queue not enough
+	config.ConfigGlobal.MaxQueueLength = 2
+	w = submit(map[string]interface{}{"prompts": []string{"cat", "dog", "fox"}, "params": params})
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, 0, p.taskQueue.status().Length)
+}
@@ -0,0 +1,205 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/models"
+	"github.com/gin-gonic/gin"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+)
+
+var errUnsupportedMediaType = errors.New("unsupported media type")
+
+// uploadRequests request type of upload routes, key method and route path
+var uploadRequests = map[string]func() any{
+	"POST /txt2img":            func() any { return new(models.Txt2ImgJSONRequestBody) },
+	"POST /txt2img/cached":     func() any { return new(models.Txt2ImgJSONRequestBody) },
+	"POST /txt2img/multi":      func() any { return new(models.Txt2ImgMultiRequest) },
+	"POST /img2img":            func() any { return new(models.Img2ImgJSONRequestBody) },
+	"POST /extra_images":       func() any { return new(models.ExtraImagesJSONRequestBody) },
+	"POST /extra_batch_images": func() any { return new(models.ExtraBatchImagesJSONRequestBody) },
+	"POST /interrogate":        func() any { return new(models.InterrogateJSONRequestBody) },
+	"POST /models":             func() any { return new(models.RegisterModelJSONRequestBody) },
+	"PUT /models/:model_name":  func() any { return new(models.UpdateModelJSONRequestBody) },
+}
+
+// passthroughUploads image fields of webui api, requests not routed (NoRouterHandler) checked by them
+type passthroughUploads struct {
+	InitImages      []string                 `json:"init_images"`
+	Mask            string                   `json:"mask"`
+	Image           string                   `json:"image"`
+	ImageList       []models.ExtraBatchImage `json:"image_list"`
+	AlwaysonScripts *map[string]interface{}  `json:"alwayson_scripts"`
+}
+
+// UploadPolicy check images and model files of request against uploadAllowLists once for all routes,
+// passthrough requests included, response 415 when rejected
+func UploadPolicy() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if len(config.ConfigGlobal.UploadAllowLists) == 0 || c.Request.Body == nil ||
+			(c.Request.Method != http.MethodPost && c.Request.Method != http.MethodPut) ||
+			strings.HasPrefix(c.ContentType(), "multipart/") {
+			return
+		}
+		var req any
+		if c.FullPath() == "" {
+			req = new(passthroughUploads)
+		} else if newRequest, ok := uploadRequests[c.Request.Method+" "+c.FullPath()]; ok {
+			req = newRequest()
+		} else {
+			return
+		}
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				abortTooLarge(c, maxBytesErr.Limit)
+				return
+			}
+			handleError(c, http.StatusBadRequest, config.BADREQUEST)
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		// invalid body left to handler, fields of other type not checked
+		json.Unmarshal(body, req)
+		if err := checkRequestUploads(req); err != nil {
+			handleError(c, http.StatusUnsupportedMediaType, err.Error())
+			c.Abort()
+		}
+	}
+}
+
+// checkRequestUploads all upload routes check here, new route add its request type
+func checkRequestUploads(req any) error {
+	images := make([]string, 0)
+	switch request := req.(type) {
+	case *models.ExtraImagesJSONRequestBody:
+		images = append(images, request.Image)
+	case *models.ExtraBatchImagesJSONRequestBody:
+		for _, image := range request.ImageList {
+			images = append(images, image.Data)
+		}
+	case *models.InterrogateJSONRequestBody:
+		images = append(images, request.Image)
+	case *models.Txt2ImgJSONRequestBody:
+		images = append(images, controlNetImages(request.AlwaysonScripts)...)
+	case *models.Txt2ImgMultiRequest:
+		images = append(images, controlNetImages(request.Params.AlwaysonScripts)...)
+	case *models.Img2ImgJSONRequestBody:
+		if request.InitImages != nil {
+			images = append(images, *request.InitImages...)
+		}
+		if request.Mask != nil {
+			images = append(images, *request.Mask)
+		}
+		images = append(images, controlNetImages(request.AlwaysonScripts)...)
+	case *models.ModelAttributes:
+		return checkUploadExt(config.UploadModel, request.Name)
+	case *passthroughUploads:
+		images = append(images, request.InitImages...)
+		images = append(images, request.Mask, request.Image)
+		for _, image := range request.ImageList {
+			images = append(images, image.Data)
+		}
+		images = append(images, controlNetImages(request.AlwaysonScripts)...)
+	}
+	for _, image := range images {
+		if err := checkImageInput(image); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkImageInput image is oss path or base64(data uri), oss path check ext, base64 check sniffed type
+func checkImageInput(image string) error {
+	if image == "" {
+		return nil
+	}
+	if isImgPath(image) {
+		return checkUploadExt(config.UploadImage, image)
+	}
+	if _, data, ok := strings.Cut(image, ";base64,"); ok && strings.HasPrefix(image, "data:") {
+		image = data
+	}
+	if len(image) > sniffBase64Len {
+		image = image[:sniffBase64Len]
+	}
+	decode, _ := base64.StdEncoding.DecodeString(image)
+	return checkUploadMimeType(config.UploadImage, decode)
+}
+
+// checkUploadExt ext of name in allow list of kind
+func checkUploadExt(kind, name string) error {
+	allowList := config.ConfigGlobal.GetUploadAllowList(kind)
+	if allowList == nil || len(allowList.Extensions) == 0 {
+		return nil
+	}
+	ext := strings.ToLower(path.Ext(name))
+	for _, allowed := range allowList.Extensions {
+		if ext == allowed {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s %s ext %q not allowed", errUnsupportedMediaType, kind, name, ext)
+}
+
+// checkUploadMimeType sniffed mime type of content in allow list of kind
+func checkUploadMimeType(kind string, content []byte) error {
+	allowList := config.ConfigGlobal.GetUploadAllowList(kind)
+	if allowList == nil || len(allowList.MimeTypes) == 0 {
+		return nil
+	}
+	mimeType, _, _ := strings.Cut(http.DetectContentType(content), ";")
+	for _, allowed := range allowList.MimeTypes {
+		if mimeType == allowed {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s type %s not allowed", errUnsupportedMediaType, kind, mimeType)
+}
+
+// controlNetImages image inputs of enabled controlnet units
+func controlNetImages(alwaysonScripts *map[string]interface{}) []string {
+	images := make([]string, 0)
+	if alwaysonScripts == nil {
+		return images
+	}
+	for name, script := range *alwaysonScripts {
+		scriptMap, ok := script.(map[string]interface{})
+		if strings.ToLower(name) != controlNetScript || !ok {
+			continue
+		}
+		units, _ := scriptMap["args"].([]interface{})
+		for _, unit := range units {
+			unitMap, ok := unit.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if enabled, ok := unitMap["enabled"].(bool); ok && !enabled {
+				continue
+			}
+			imageMaps := []map[string]interface{}{unitMap}
+			// image may be {"image": xx, "mask": xx}
+			if imageMap, ok := unitMap["image"].(map[string]interface{}); ok {
+				imageMaps = append(imageMaps, imageMap)
+			}
+			for _, imageMap := range imageMaps {
+				for _, key := range controlNetImageKeys {
+					if image, ok := imageMap[key].(string); ok {
+						images = append(images, image)
+					}
+				}
+			}
+		}
+	}
+	return images
+}
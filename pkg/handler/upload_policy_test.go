@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/models"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckRequestUploads(t *testing.T) {
+	initTestConfig(t)
+	encode := func(data string) string {
+		return base64.StdEncoding.EncodeToString([]byte(data))
+	}
+	png := encode("\x89PNG\r\n\x1a\n0000000000000000")
+	jpeg := encode("\xff\xd8\xff\xe0000000000000000")
+	webp := encode("RIFF0000WEBPVP80000000000")
+	gif := encode("GIF89a0000000000000000")
+	text := encode("plain text, not image")
+	controlNet := func(image string) *map[string]interface{} {
+		return &map[string]interface{}{"ControlNet": map[string]interface{}{"args": []interface{}{
+			map[string]interface{}{"enabled": true, "image": map[string]interface{}{"image": image}},
+		}}}
+	}
+
+	// no allow list accept all
+	assert.Nil(t, checkRequestUploads(&models.InterrogateJSONRequestBody{Image: text}))
+	assert.Nil(t, checkRequestUploads(&models.ModelAttributes{Name: "model.bin"}))
+
+	config.ConfigGlobal.UploadAllowLists = map[string]*config.UploadAllowList{
+		config.UploadImage: {MimeTypes: []string{"image/png", "image/jpeg", "image/webp"},
+			Extensions: []string{".png", ".jpg"}},
+		config.UploadModel: {Extensions: []string{".safetensors", ".ckpt"}},
+	}
+	cases := []struct {
+		name    string
+		req     any
+		allowed bool
+	}{
+		{"png", &models.InterrogateJSONRequestBody{Image: png}, true},
+		{"jpeg", &models.ExtraImagesJSONRequestBody{Image: jpeg}, true},
+		{"webp", &models.ExtraBatchImagesJSONRequestBody{ImageList: []models.ExtraBatchImage{{Data: webp}}}, true},
+		{"data uri", &models.InterrogateJSONRequestBody{Image: "data:image/png;base64," + png}, true},
+		{"gif", &models.InterrogateJSONRequestBody{Image: gif}, false},
+		{"text", &models.ExtraImagesJSONRequestBody{Image: text}, false},
+		{"batch one bad", &models.ExtraBatchImagesJSONRequestBody{ImageList: []models.ExtraBatchImage{
+			{Data: png}, {Data: gif}}}, false},
+		{"oss path", &models.Img2ImgJSONRequestBody{InitImages: &[]string{"images/a.png"}}, true},
+		{"oss path ext", &models.Img2ImgJSONRequestBody{InitImages: &[]string{"images/a.jpeg"}}, false},
+		{"mask", &models.Img2ImgJSONRequestBody{InitImages: &[]string{png}, Mask: utils.String(text)}, false},
+		{"controlnet", &models.Txt2ImgJSONRequestBody{AlwaysonScripts: controlNet(png)}, true},
+		{"controlnet bad", &models.Txt2ImgJSONRequestBody{AlwaysonScripts: controlNet(gif)}, false},
+		{"controlnet disabled", &models.Txt2ImgJSONRequestBody{AlwaysonScripts: &map[string]interface{}{
+			"controlnet": map[string]interface{}{"args": []interface{}{
+				map[string]interface{}{"enabled": false, "input_image": gif}}}}}, true},
+		{"no image", &models.Txt2ImgJSONRequestBody{Prompt: utils.String("cat")}, true},
+		{"model", &models.ModelAttributes{Name: "sd_xl.safetensors"}, true},
+		{"model upper ext", &models.ModelAttributes{Name: "sd.CKPT"}, true},
+		{"model pickle", &models.ModelAttributes{Name: "sd.pt"}, false},
+		{"model no ext", &models.ModelAttributes{Name: "sd"}, false},
+	}
+	for _, tc := range cases {
+		err := checkRequestUploads(tc.req)
+		if tc.allowed {
+			assert.Nil(t, err, tc.name)
+		} else {
+			assert.ErrorIs(t, err, errUnsupportedMediaType, tc.name)
+		}
+	}
+
+	// middleware reject with 415 before task created, passthrough request included
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(UploadPolicy())
+	RegisterHandlers(router, &ProxyHandler{})
+	passthrough := ""
+	router.NoRoute(func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		passthrough = string(body)
+		c.Status(http.StatusOK)
+	})
+	post := func(method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w
+	}
+	w := post(http.MethodPost, "/interrogate", `{"image":"`+gif+`"}`)
+	assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+	assert.Contains(t, w.Body.String(), "image/gif")
+	assert.Equal(t, http.StatusUnsupportedMediaType,
+		post(http.MethodPut, "/models/sd.pt", `{"name":"sd.pt","type":"sd"}`).Code)
+	assert.Equal(t, http.StatusUnsupportedMediaType,
+		post(http.MethodPost, "/txt2img/multi", `{"params":{"alwayson_scripts":{"controlnet":{"args":[{"image":"`+
+			gif+`"}]}}},"prompts":["cat"]}`).Code)
+	assert.Equal(t, http.StatusUnsupportedMediaType,
+		post(http.MethodPost, "/sdapi/v1/img2img", `{"init_images":["`+png+`"],"mask":"`+text+`"}`).Code)
+	assert.Empty(t, passthrough)
+	// allowed passthrough body still readable by handler
+	body := `{"init_images":["` + png + `"]}`
+	assert.Equal(t, http.StatusOK, post(http.MethodPost, "/sdapi/v1/img2img", body).Code)
+	assert.Equal(t, body, passthrough)
+}
@@ -0,0 +1,257 @@
+package handler
+
+import (
+	"fmt"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/datastore"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/models"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/module"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"path"
+	"regexp"
+	"strings"
+)
+
+const (
+	defaultImageLimit = 100
+	maxImageLimit     = 1000
+)
+
+var imageNamePlaceholderRegex = regexp.MustCompile(`\{(user|taskId|index|seed|date|timestamp)\}`)
+
+// imageNameTemplate template as used by imageOssKey
+func imageNameTemplate() string {
+	return strings.TrimLeft(path.Clean(config.ConfigGlobal.ImageNameTemplate), "/")
+}
+
+// userImagePrefix oss prefix of user images, error when imageNameTemplate not put images in {user} dir
+func userImagePrefix(user string) (string, error) {
+	template := imageNameTemplate()
+	idx := strings.Index(template, "{user}/")
+	if idx < 0 || imageNamePlaceholderRegex.MatchString(template[:idx]) {
+		return "", fmt.Errorf("imageNameTemplate %s not group images by {user} dir, not support",
+			config.ConfigGlobal.ImageNameTemplate)
+	}
+	return template[:idx] + sanitizeNameField(user) + "/", nil
+}
+
+// userImageRoot oss prefix of user images written under output_prefix (empty for default), return http status
+// code with error, user name changed by sanitizing share dir with other user so rejected
+func userImageRoot(user string, outputPrefix *string) (string, int, error) {
+	if sanitizeNameField(user) != user {
+		return "", http.StatusBadRequest, fmt.Errorf("user %s not safe as image dir, images not accessible", user)
+	}
+	prefix, err := userImagePrefix(user)
+	if err != nil {
+		return "", http.StatusNotFound, err
+	}
+	output, code, err := resolveOutputPrefix(user, outputPrefix)
+	if err != nil {
+		return "", code, err
+	}
+	if output != "" {
+		prefix = output + "/" + prefix
+	}
+	return prefix, http.StatusOK, nil
+}
+
+// splitOutputPrefix output prefix of user image key and key as named by imageNameTemplate,
+// false when key not under user prefix or its output prefix not allowed
+func splitOutputPrefix(user, prefix, key string) (string, string, bool) {
+	if strings.HasPrefix(key, prefix) {
+		return "", key, true
+	}
+	idx := strings.Index(key, "/"+prefix)
+	if idx <= 0 {
+		return "", "", false
+	}
+	output := key[:idx]
+	if _, _, err := resolveOutputPrefix(user, &output); err != nil {
+		return "", "", false
+	}
+	return output, key[idx+1:], true
+}
+
+// imageTaskId task id of user image key by imageNameTemplate, empty if key not match
+func imageTaskId(user, key string) string {
+	template := imageNameTemplate()
+	// ext fixed by real image format
+	ext := path.Ext(template)
+	if strings.Contains(ext, "{") {
+		ext = ""
+	}
+	template = strings.TrimSuffix(template, ext)
+	pattern := "^"
+	last := 0
+	for _, loc := range imageNamePlaceholderRegex.FindAllStringSubmatchIndex(template, -1) {
+		pattern += regexp.QuoteMeta(template[last:loc[0]])
+		switch template[loc[2]:loc[3]] {
+		case "user":
+			pattern += regexp.QuoteMeta(sanitizeNameField(user))
+		case "taskId":
+			pattern += `([^/]+?)`
+		case "seed":
+			pattern += `(?:-?\d+|unknown)`
+		case "date":
+			pattern += `\d{8}`
+		default:
+			pattern += `\d+`
+		}
+		last = loc[1]
+	}
+	pattern += regexp.QuoteMeta(template[last:])
+	if ext != "" {
+		pattern += `\.\w+`
+	}
+	match := regexp.MustCompile(pattern + "$").FindStringSubmatch(key)
+	if len(match) < 2 {
+		return ""
+	}
+	return match[1]
+}
+
+// imageUserAllowed non admin user only access own images when login on
+func imageUserAllowed(c *gin.Context, user string) bool {
+	username := c.GetHeader(userKey)
+	return !config.ConfigGlobal.EnableLogin() || username == module.DefaultUser || username == user
+}
+
+// ListUserImages list user images in oss under user image prefix, or under it in output_prefix,
+// paginated by cursor (GET /users/{user}/images)
+func (p *ProxyHandler) ListUserImages(c *gin.Context, user string, params models.ListUserImagesParams) {
+	if !imageUserAllowed(c, user) {
+		handleError(c, http.StatusForbidden, "only own images accessible")
+		return
+	}
+	limit := defaultImageLimit
+	if params.Limit != nil {
+		limit = *params.Limit
+	}
+	if limit <= 0 || limit > maxImageLimit {
+		handleError(c, http.StatusBadRequest, fmt.Sprintf("limit should between 1 and %d", maxImageLimit))
+		return
+	}
+	prefix, code, err := userImageRoot(user, params.OutputPrefix)
+	if err != nil {
+		handleError(c, code, err.Error())
+		return
+	}
+	cursor := ""
+	if params.Cursor != nil {
+		cursor = *params.Cursor
+	}
+	// cursor of other user not leak images
+	if cursor != "" && !strings.HasPrefix(cursor, prefix) {
+		handleError(c, http.StatusBadRequest, "invalid cursor")
+		return
+	}
+	objects, nextCursor, err := module.OssGlobal.ListFiles(prefix, cursor, limit)
+	if err != nil {
+		logrus.Errorf("list user %s images err=%s", user, err.Error())
+		handleError(c, http.StatusInternalServerError, "list images from oss error")
+		return
+	}
+	keys := make([]string, 0, len(objects))
+	for _, object := range objects {
+		keys = append(keys, object.Key)
+	}
+	// local oss no url
+	urls, _ := module.OssGlobal.GetUrl(keys)
+	ret := models.UserImageList{Images: make([]models.UserImage, 0, len(objects))}
+	for i, object := range objects {
+		image := models.UserImage{
+			Key:          object.Key,
+			Size:         utils.Int64(object.Size),
+			LastModified: utils.Int64(object.LastModified.Unix()),
+		}
+		if i < len(urls) {
+			image.Url = utils.String(urls[i])
+		}
+		ret.Images = append(ret.Images, image)
+	}
+	if nextCursor != "" {
+		ret.NextCursor = &nextCursor
+	}
+	c.JSON(http.StatusOK, ret)
+}
+
+// DeleteUserImages delete user images in oss and remove them from owning task result
+// (DELETE /users/{user}/images)
+func (p *ProxyHandler) DeleteUserImages(c *gin.Context, user string) {
+	if !imageUserAllowed(c, user) {
+		handleError(c, http.StatusForbidden, "only own images accessible")
+		return
+	}
+	request := new(models.DeleteUserImagesRequest)
+	if err := getBindResult(c, request); err != nil || len(request.Images) == 0 {
+		handleError(c, http.StatusBadRequest, config.BADREQUEST)
+		return
+	}
+	prefix, code, err := userImageRoot(user, nil)
+	if err != nil {
+		handleError(c, code, err.Error())
+		return
+	}
+	// check all keys before delete any, images under allowed output prefix included
+	names := make([]string, 0, len(request.Images))
+	for _, key := range request.Images {
+		_, name, ok := splitOutputPrefix(user, prefix, key)
+		if !ok || path.Clean(key) != key || strings.Contains(key, "..") {
+			handleError(c, http.StatusForbidden, fmt.Sprintf("image %s not under %s", key, prefix))
+			return
+		}
+		names = append(names, name)
+	}
+	deleted := make([]string, 0, len(request.Images))
+	for i, key := range request.Images {
+		if err := module.OssGlobal.DeleteFile(key); err != nil {
+			logrus.Errorf("delete user %s image %s err=%s", user, key, err.Error())
+			handleError(c, http.StatusInternalServerError, fmt.Sprintf("delete image %s error", key))
+			return
+		}
+		deleted = append(deleted, key)
+		if err := p.removeTaskImage(user, names[i], key); err != nil {
+			logrus.Warnf("remove image %s from task err=%s", key, err.Error())
+		}
+	}
+	c.JSON(http.StatusOK, models.DeleteUserImagesResult{Deleted: deleted})
+}
+
+// removeTaskImage remove deleted image key from result of user task found by image name, task not found ignored
+func (p *ProxyHandler) removeTaskImage(user, name, key string) error {
+	taskId := imageTaskId(user, name)
+	if taskId == "" {
+		return nil
+	}
+	for i := 0; i < maxCasRetries; i++ {
+		data, err := p.taskStore.Get(taskId, []string{datastore.KTaskUser, datastore.KTaskImage})
+		if err != nil {
+			return err
+		}
+		image, ok := data[datastore.KTaskImage].(string)
+		if !ok || data[datastore.KTaskUser] != user {
+			return nil
+		}
+		images := strings.Split(image, ",")
+		kept := make([]string, 0, len(images))
+		for _, item := range images {
+			if item != key {
+				kept = append(kept, item)
+			}
+		}
+		if len(kept) == len(images) {
+			return nil
+		}
+		swapped, err := p.taskStore.CompareAndSwap(taskId, datastore.KTaskImage, image, map[string]interface{}{
+			datastore.KTaskImage:      strings.Join(kept, ","),
+			datastore.KTaskModifyTime: fmt.Sprintf("%d", utils.TimestampS()),
+		})
+		if err != nil || swapped {
+			return err
+		}
+	}
+	return errCasConflict
+}
@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/datastore"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/models"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/module"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserImagePrefix(t *testing.T) {
+	initTestConfig(t)
+	config.ConfigGlobal.ImageNameTemplate = config.DefaultImageNameTemplate
+	prefix, err := userImagePrefix("user1")
+	assert.Nil(t, err)
+	assert.Equal(t, "images/user1/", prefix)
+	prefix, err = userImagePrefix("a/b")
+	assert.Nil(t, err)
+	assert.Equal(t, "images/a_b/", prefix)
+	// sanitized name collide with user a_b
+	_, code, err := userImageRoot("a/b", nil)
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.NotNil(t, err)
+	config.ConfigGlobal.OutputPrefixes = map[string][]string{config.AllUsers: {"projects"}}
+	prefix, _, err = userImageRoot("user1", utils.String("projects/demo"))
+	assert.Nil(t, err)
+	assert.Equal(t, "projects/demo/images/user1/", prefix)
+	_, code, _ = userImageRoot("user1", utils.String("other"))
+	assert.Equal(t, http.StatusForbidden, code)
+	assert.Equal(t, "task_1", imageTaskId("user1", "images/user1/task_1_2.png"))
+	// ext fixed by format
+	assert.Equal(t, "task", imageTaskId("user1", "images/user1/task_1.jpg"))
+	assert.Empty(t, imageTaskId("user2", "images/user1/task_1.png"))
+
+	config.ConfigGlobal.ImageNameTemplate = "/out/{date}/{user}/{seed}-{taskId}-{index}.png"
+	_, err = userImagePrefix("user1")
+	assert.NotNil(t, err)
+	assert.Equal(t, "task", imageTaskId("user1", "out/20240101/user1/-1-task-1.png"))
+
+	config.ConfigGlobal.ImageNameTemplate = "images/{taskId}_{index}.png"
+	_, err = userImagePrefix("user1")
+	assert.NotNil(t, err)
+}
+
+func TestUserImages(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	initTestConfig(t)
+	config.ConfigGlobal.ImageNameTemplate = config.DefaultImageNameTemplate
+	config.ConfigGlobal.OutputPrefixes = map[string][]string{config.AllUsers: {"projects"}}
+	oss := mockOss(t, 0)
+	for _, key := range []string{"images/user1/task_1.png", "images/user1/task_2.png", "images/user1/other_1.png",
+		"images/user2/task2_1.png", "projects/demo/images/user1/out_1.png"} {
+		assert.Nil(t, oss.UploadFileByByte(key, []byte("image")))
+	}
+	taskStore := datastore.NewSQLiteDatastore(datastore.NewSQLiteConfig(datastore.KTaskTableName))
+	defer taskStore.Close()
+	assert.Nil(t, taskStore.Put("task", map[string]interface{}{
+		datastore.KTaskIdColumnName: "task",
+		datastore.KTaskUser:         "user1",
+		datastore.KTaskImage:        "images/user1/task_1.png,images/user1/task_2.png",
+	}))
+	assert.Nil(t, taskStore.Put("out", map[string]interface{}{
+		datastore.KTaskIdColumnName: "out",
+		datastore.KTaskUser:         "user1",
+		datastore.KTaskImage:        "projects/demo/images/user1/out_1.png",
+	}))
+	router := gin.New()
+	RegisterHandlers(router, &ProxyHandler{taskStore: taskStore})
+	do := func(method, url, user, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(method, url, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(userKey, user)
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// paginated list of own prefix
+	w := do(http.MethodGet, "/users/user1/images?limit=2", "user1", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	var list models.UserImageList
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &list))
+	assert.Len(t, list.Images, 2)
+	assert.Equal(t, "images/user1/other_1.png", list.Images[0].Key)
+	assert.Equal(t, "http://oss/images/user1/other_1.png", *list.Images[0].Url)
+	assert.Equal(t, int64(5), *list.Images[0].Size)
+	w = do(http.MethodGet, "/users/user1/images?limit=2&cursor="+*list.NextCursor, "user1", "")
+	list = models.UserImageList{}
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &list))
+	assert.Len(t, list.Images, 1)
+	assert.Equal(t, "images/user1/task_2.png", list.Images[0].Key)
+	assert.Nil(t, list.NextCursor)
+	assert.Equal(t, http.StatusBadRequest, do(http.MethodGet, "/users/user1/images?limit=0", "user1", "").Code)
+	assert.Equal(t, http.StatusBadRequest,
+		do(http.MethodGet, "/users/user1/images?cursor=images/user2/", "user1", "").Code)
+	// images written with output_prefix
+	w = do(http.MethodGet, "/users/user1/images?output_prefix=projects/demo", "user1", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	list = models.UserImageList{}
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &list))
+	assert.Len(t, list.Images, 1)
+	assert.Equal(t, "projects/demo/images/user1/out_1.png", list.Images[0].Key)
+	assert.Equal(t, http.StatusForbidden,
+		do(http.MethodGet, "/users/user1/images?output_prefix=private", "user1", "").Code)
+
+	// key outside own prefix, nothing deleted
+	w = do(http.MethodDelete, "/users/user1/images", "user1",
+		`{"images":["images/user1/task_1.png","images/user2/task2_1.png"]}`)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = do(http.MethodDelete, "/users/user1/images", "user1", `{"images":["images/user1/../user2/task2_1.png"]}`)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = do(http.MethodDelete, "/users/user1/images", "user1", `{"images":["private/images/user1/task_1.png"]}`)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Len(t, oss.uploaded, 5)
+
+	// delete and clear task reference
+	w = do(http.MethodDelete, "/users/user1/images", "user1", `{"images":["images/user1/task_1.png"]}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var result models.DeleteUserImagesResult
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, []string{"images/user1/task_1.png"}, result.Deleted)
+	assert.NotContains(t, oss.uploaded, "images/user1/task_1.png")
+	task, err := taskStore.Get("task", []string{datastore.KTaskImage})
+	assert.Nil(t, err)
+	assert.Equal(t, "images/user1/task_2.png", task[datastore.KTaskImage])
+	w = do(http.MethodDelete, "/users/user1/images", "user1", `{"images":["projects/demo/images/user1/out_1.png"]}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	task, err = taskStore.Get("out", []string{datastore.KTaskImage})
+	assert.Nil(t, err)
+	assert.Equal(t, "", task[datastore.KTaskImage])
+	assert.Equal(t, http.StatusBadRequest, do(http.MethodDelete, "/users/user1/images", "user1", `{"images":[]}`).Code)
+
+	// login on, only own images
+	config.ConfigGlobal.LoginSwitch = "on"
+	assert.Equal(t, http.StatusForbidden, do(http.MethodGet, "/users/user2/images", "user1", "").Code)
+	assert.Equal(t, http.StatusForbidden, do(http.MethodDelete, "/users/user2/images", "user1",
+		`{"images":["images/user2/task2_1.png"]}`).Code)
+	assert.Equal(t, http.StatusOK, do(http.MethodGet, "/users/user2/images", module.DefaultUser, "").Code)
+}
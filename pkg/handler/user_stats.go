@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"fmt"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/datastore"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/models"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/module"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"strings"
+)
+
+const (
+	maxUserStatsDays = 366
+	secondsPerDay    = 24 * 3600
+)
+
+var userStatsColumns = []string{datastore.KTaskIdColumnName, datastore.KTaskUser, datastore.KTaskStatus,
+	datastore.KTaskCreateTime, datastore.KTaskGpuSeconds, datastore.KTaskImage}
+
+// GetUserStats task count, images, gpu seconds of user tasks created in recent days and oss storage of user
+// (GET /users/{user}/stats)
+func (p *ProxyHandler) GetUserStats(c *gin.Context, user string, params models.GetUserStatsParams) {
+	if !imageUserAllowed(c, user) {
+		handleError(c, http.StatusForbidden, "only own stats accessible")
+		return
+	}
+	days := config.ConfigGlobal.UserStatsDays
+	if params.Days != nil {
+		days = *params.Days
+	}
+	if days <= 0 || days > maxUserStatsDays {
+		handleError(c, http.StatusBadRequest, fmt.Sprintf("days should between 1 and %d", maxUserStatsDays))
+		return
+	}
+	rows, err := p.taskStore.ListAll(userStatsColumns)
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, "read task from db error")
+		return
+	}
+	since := utils.TimestampS() - int64(days)*secondsPerDay
+	stats := aggregateUserStats(rows, user, since)
+	stats.Days = days
+	stats.StorageBytes = userStorageBytes(user)
+	c.JSON(http.StatusOK, stats)
+}
+
+// aggregateUserStats stats of user tasks created since timestamp
+func aggregateUserStats(rows map[string]map[string]interface{}, user string, since int64) models.UserStats {
+	stats := models.UserStats{User: user, Since: since, Tasks: make(map[string]int)}
+	filter := &taskFilter{user: user, from: &since}
+	for taskId, row := range rows {
+		item, ok := filter.match(taskId, row)
+		if !ok {
+			continue
+		}
+		stats.Tasks[item.Status]++
+		stats.TotalTasks++
+		if item.GpuSeconds != nil {
+			stats.GpuSeconds += *item.GpuSeconds
+		}
+		if image, ok := row[datastore.KTaskImage].(string); ok && image != "" {
+			stats.Images += len(strings.Split(image, ","))
+		}
+	}
+	return stats
+}
+
+// userStorageBytes total size of user images in oss, nil when images not group by user or list fail
+func userStorageBytes(user string) *int64 {
+	prefix, err := userImagePrefix(user)
+	if err != nil {
+		return nil
+	}
+	total, cursor := int64(0), ""
+	for {
+		objects, nextCursor, err := module.OssGlobal.ListFiles(prefix, cursor, maxImageLimit)
+		if err != nil {
+			logrus.Warnf("list user %s images err=%s", user, err.Error())
+			return nil
+		}
+		for _, object := range objects {
+			total += object.Size
+		}
+		if nextCursor == "" {
+			return &total
+		}
+		cursor = nextCursor
+	}
+}
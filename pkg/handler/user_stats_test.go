@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/datastore"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/models"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/module"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAggregateUserStats(t *testing.T) {
+	rows := map[string]map[string]interface{}{
+		"t1": {datastore.KTaskUser: "user1", datastore.KTaskStatus: config.TASK_FINISH,
+			datastore.KTaskCreateTime: "1000", datastore.KTaskGpuSeconds: 1.5,
+			datastore.KTaskImage: "images/user1/t1_1.png,images/user1/t1_2.png"},
+		"t2": {datastore.KTaskUser: "user1", datastore.KTaskStatus: config.TASK_FAILED,
+			datastore.KTaskCreateTime: "1001"},
+		// outside window
+		"t3": {datastore.KTaskUser: "user1", datastore.KTaskStatus: config.TASK_FINISH,
+			datastore.KTaskCreateTime: "10", datastore.KTaskGpuSeconds: 3.0, datastore.KTaskImage: "images/user1/t3_1.png"},
+		"t4": {datastore.KTaskUser: "user2", datastore.KTaskStatus: config.TASK_FINISH,
+			datastore.KTaskCreateTime: "1000", datastore.KTaskGpuSeconds: 2.0},
+	}
+	stats := aggregateUserStats(rows, "user1", 100)
+	assert.Equal(t, "user1", stats.User)
+	assert.Equal(t, int64(100), stats.Since)
+	assert.Equal(t, 2, stats.TotalTasks)
+	assert.Equal(t, map[string]int{config.TASK_FINISH: 1, config.TASK_FAILED: 1}, stats.Tasks)
+	assert.Equal(t, 1.5, stats.GpuSeconds)
+	assert.Equal(t, 2, stats.Images)
+	assert.Equal(t, 0, aggregateUserStats(rows, "user3", 0).TotalTasks)
+}
+
+func TestGetUserStats(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	initTestConfig(t)
+	config.ConfigGlobal.ImageNameTemplate = config.DefaultImageNameTemplate
+	config.ConfigGlobal.UserStatsDays = config.DefaultUserStatsDays
+	oss := mockOss(t, 0)
+	for _, key := range []string{"images/user1/task_1.png", "images/user1/task_2.png", "images/user2/task2_1.png"} {
+		assert.Nil(t, oss.UploadFileByByte(key, []byte("image")))
+	}
+	taskStore := datastore.NewSQLiteDatastore(datastore.NewSQLiteConfig(datastore.KTaskTableName))
+	defer taskStore.Close()
+	now := utils.TimestampS()
+	assert.Nil(t, taskStore.Put("task", map[string]interface{}{
+		datastore.KTaskIdColumnName: "task",
+		datastore.KTaskUser:         "user1",
+		datastore.KTaskStatus:       config.TASK_FINISH,
+		datastore.KTaskCreateTime:   fmt.Sprintf("%d", now-secondsPerDay),
+		datastore.KTaskGpuSeconds:   2.5,
+		datastore.KTaskImage:        "images/user1/task_1.png,images/user1/task_2.png",
+	}))
+	assert.Nil(t, taskStore.Put("old", map[string]interface{}{
+		datastore.KTaskIdColumnName: "old",
+		datastore.KTaskUser:         "user1",
+		datastore.KTaskStatus:       config.TASK_FINISH,
+		datastore.KTaskCreateTime:   fmt.Sprintf("%d", now-10*secondsPerDay),
+		datastore.KTaskGpuSeconds:   1.0,
+	}))
+	p := &ProxyHandler{taskStore: taskStore}
+	get := func(user, loginUser string, days *int) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/users/"+user+"/stats", nil)
+		c.Request.Header.Set(userKey, loginUser)
+		p.GetUserStats(c, user, models.GetUserStatsParams{Days: days})
+		return w
+	}
+
+	w := get("user1", "user1", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var stats models.UserStats
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &stats))
+	assert.Equal(t, config.DefaultUserStatsDays, stats.Days)
+	assert.Equal(t, 2, stats.TotalTasks)
+	assert.Equal(t, 3.5, stats.GpuSeconds)
+	assert.Equal(t, 2, stats.Images)
+	assert.Equal(t, int64(10), *stats.StorageBytes)
+
+	// narrow window
+	days := 7
+	w = get("user1", "user1", &days)
+	stats = models.UserStats{}
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &stats))
+	assert.Equal(t, 1, stats.TotalTasks)
+	assert.Equal(t, map[string]int{config.TASK_FINISH: 1}, stats.Tasks)
+	days = 0
+	assert.Equal(t, http.StatusBadRequest, get("user1", "user1", &days).Code)
+	days = maxUserStatsDays + 1
+	assert.Equal(t, http.StatusBadRequest, get("user1", "user1", &days).Code)
+
+	// images not group by user, storage not set
+	config.ConfigGlobal.ImageNameTemplate = "images/{taskId}_{index}.png"
+	stats = models.UserStats{}
+	assert.Nil(t, json.Unmarshal(get("user1", "user1", nil).Body.Bytes(), &stats))
+	assert.Nil(t, stats.StorageBytes)
+
+	// login on, only own stats
+	config.ConfigGlobal.LoginSwitch = "on"
+	assert.Equal(t, http.StatusForbidden, get("user2", "user1", nil).Code)
+	assert.Equal(t, http.StatusOK, get("user2", module.DefaultUser, nil).Code)
+}
@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"fmt"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/datastore"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/models"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"sync"
+)
+
+// userTaskCounter count unfinished tasks per user from task rows not terminal, plus tasks entered and
+// not written yet, like taskQueue
+type userTaskCounter struct {
+	lock      sync.Mutex
+	taskStore datastore.Datastore
+	// user -> tasks entered, not written to task table yet
+	counts map[string]int
+}
+
+func newUserTaskCounter(taskStore datastore.Datastore) *userTaskCounter {
+	return &userTaskCounter{taskStore: taskStore, counts: make(map[string]int)}
+}
+
+// count unfinished tasks of user, only entered tasks counted when read task table fail
+func (u *userTaskCounter) count(user string) int {
+	count := u.counts[user]
+	if u.taskStore == nil {
+		return count
+	}
+	rows, err := listActiveTasks(u.taskStore)
+	if err != nil {
+		logrus.Warnf("list active tasks err=%s", err.Error())
+		return count
+	}
+	for _, row := range rows {
+		if row[datastore.KTaskUser] == user {
+			count++
+		}
+	}
+	return count
+}
+
+// enter count n tasks of user, false with unfinished count when exceed max, call leave once task rows
+// written(counted by row) or not submitted, nil counter or max <= 0 no count
+func (u *userTaskCounter) enter(user string, n, max int) (func(), int, bool) {
+	if u == nil || max <= 0 {
+		return func() {}, 0, true
+	}
+	u.lock.Lock()
+	defer u.lock.Unlock()
+	count := u.count(user)
+	if count+n > max {
+		return nil, count, false
+	}
+	u.counts[user] += n
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			u.lock.Lock()
+			defer u.lock.Unlock()
+			if u.counts[user] -= n; u.counts[user] <= 0 {
+				delete(u.counts, user)
+			}
+		})
+	}, count, true
+}
+
+// enterUserTasks reject n new tasks with 429 when user unfinished tasks would exceed limit, true if rejected,
+// otherwise leave called once task rows written or not submitted
+func (p *ProxyHandler) enterUserTasks(c *gin.Context, user, taskId string, n int) (func(), bool) {
+	max := config.ConfigGlobal.GetMaxUserTasks(user)
+	leave, count, ok := p.userTasks.enter(user, n, max)
+	if ok {
+		return leave, false
+	}
+	c.Header("Retry-After", fmt.Sprintf("%d", queueFullRetryAfter))
+	c.JSON(http.StatusTooManyRequests, models.SubmitTaskResponse{
+		TaskId:  taskId,
+		Status:  config.TASK_FAILED,
+		Message: utils.String(fmt.Sprintf("user has %d unfinished tasks, limit %d, please retry later", count, max)),
+	})
+	return nil, true
+}
@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/datastore"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnterUserTasks(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	initTestConfig(t)
+	taskStore := datastore.NewSQLiteDatastore(datastore.NewSQLiteConfig(datastore.KTaskTableName))
+	defer taskStore.Close()
+	putTask := func(taskId, user, status string) {
+		assert.Nil(t, taskStore.Put(taskId, map[string]interface{}{
+			datastore.KTaskIdColumnName: taskId,
+			datastore.KTaskUser:         user,
+			datastore.KTaskStatus:       status,
+			datastore.KTaskCreateTime:   fmt.Sprintf("%d", utils.TimestampS()),
+		}))
+	}
+	p := &ProxyHandler{userTasks: newUserTaskCounter(taskStore)}
+	enter := func(user string, n int) (func(), *httptest.ResponseRecorder) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/txt2img", nil)
+		leave, rejected := p.enterUserTasks(c, user, "task", n)
+		assert.Equal(t, rejected, leave == nil)
+		return leave, w
+	}
+
+	// no limit, not counted
+	leave, _ := enter("alice", 5)
+	assert.NotNil(t, leave)
+	assert.Empty(t, p.userTasks.counts)
+
+	config.ConfigGlobal.MaxUserTasks = 2
+	putTask("queued", "alice", config.TASK_QUEUE)
+	putTask("done", "alice", config.TASK_FINISH)
+	alice, _ := enter("alice", 1)
+	assert.NotNil(t, alice)
+	leave, w := enter("alice", 1)
+	assert.Nil(t, leave)
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+	assert.Contains(t, w.Body.String(), "user has 2 unfinished tasks, limit 2")
+	// row written, async task counted by row after leave, leave twice release once
+	putTask("running", "alice", config.TASK_INPROGRESS)
+	alice()
+	alice()
+	assert.Empty(t, p.userTasks.counts)
+	leave, _ = enter("alice", 1)
+	assert.Nil(t, leave)
+	bob, _ := enter("bob", 1)
+	assert.NotNil(t, bob)
+	// whole batch counted
+	leave, _ = enter("bob", 2)
+	assert.Nil(t, leave)
+
+	// user setting cover global
+	config.ConfigGlobal.UserMaxTasks = map[string]int{"alice": 3, "bob": 0}
+	leave, _ = enter("alice", 1)
+	assert.NotNil(t, leave)
+	leave()
+	leave, _ = enter("bob", 10)
+	assert.NotNil(t, leave)
+
+	// slot released once row terminal
+	config.ConfigGlobal.UserMaxTasks = nil
+	putTask("running", "alice", config.TASK_CANCELLED)
+	leave, _ = enter("alice", 1)
+	assert.NotNil(t, leave)
+}
+
+func TestUserTaskCounterConcurrent(t *testing.T) {
+	counter := newUserTaskCounter(nil)
+	var wg sync.WaitGroup
+	var lock sync.Mutex
+	entered := 0
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, _, ok := counter.enter("alice", 1, 4); ok {
+				lock.Lock()
+				entered++
+				lock.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 4, entered)
+}
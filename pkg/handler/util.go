@@ -1,10 +1,12 @@
 package handler
 
 import (
+	"bytes"
 	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/client"
 	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
 	"github.com/devsapp/serverless-stable-diffusion-api/pkg/datastore"
 	"github.com/devsapp/serverless-stable-diffusion-api/pkg/models"
@@ -15,26 +17,180 @@ import (
 	"github.com/sirupsen/logrus"
 	"io"
 	"io/ioutil"
+	"mime"
 	"net/http"
 	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
 const (
-	taskIdLength     = 10
-	userKey          = "username"
-	requestType      = "Request-Type"
-	taskKey          = "taskId"
-	FcAsyncKey       = "X-Fc-Invocation-Type"
-	versionKey       = "version"
-	requestOk        = 200
-	requestFail      = 422
-	asyncSuccessCode = 202
-	syncSuccessCode  = 200
-	base64MinLen     = 2048
+	taskIdLength         = 10
+	userKey              = "username"
+	requestType          = "Request-Type"
+	taskKey              = "taskId"
+	FcAsyncKey           = "X-Fc-Invocation-Type"
+	versionKey           = "version"
+	sdModelKey           = "X-SD-Model"
+	modelDefaultsPrefix  = "modelDefaults"
+	promptTemplatePrefix = "promptTemplates"
+	modelProfilesKey     = "modelProfiles"
+	maxTemplateDepth     = 5
+	maxPromptLength      = 8192
+	adminPathPrefix      = "/admin/"
+	modelUploadPath      = "/models"
+	controlNetScript     = "controlnet"
+	defaultLogTail       = 200
+	maxLogTail           = 1000
+	requestOk            = 200
+	requestFail          = 422
+	asyncSuccessCode     = 202
+	syncSuccessCode      = 200
+	base64MinLen         = 2048
+	maxFilenameLength    = 128
+	defaultFilename      = "download"
+	downloadQueryKey     = "download"
+	webuiJobIdKey        = "X-Job-Id"
+	inlineImagesKey      = "X-Inline-Images"
+	stickyRouteKey       = "X-Sticky-Route"
+	maxTaskLabels        = 16
+	maxTaskLabelsSize    = 4096
+	// read then compare and swap retry under concurrent update
+	maxCasRetries = 10
+	// base64 chars decoded to sniff image type
+	sniffBase64Len = 24
 )
 
+// sdEndpointManager get sd function endpoint, default module.FuncManagerGlobal
+type sdEndpointManager interface {
+	GetEndpoint(sdModel string) (string, error)
+	GetLastInvokeEndpoint(sdModel *string) string
+	GetStickyEndpoint(token, sdModel string) string
+	RefreshFunctionsEnv(sdModel string) []module.FuncEnvResult
+	GetGpuMemorySize(sdModel string) int32
+}
+
+var getEndpointManager = func() sdEndpointManager {
+	return module.FuncManagerGlobal
+}
+
+// updateFunctionEnv restart function of key to load latest models, default module.FuncManagerGlobal
+var updateFunctionEnv = func(key string) error {
+	return module.FuncManagerGlobal.UpdateFunctionEnv(key)
+}
+
+// functionName sd function name of model key, default module.GetFunctionName
+var functionName = module.GetFunctionName
+
+// refreshModel let local webui refresh models of type
+var refreshModel = module.RefreshModel
+
+// withDefaultModel use config defaultModel when sdModel not set
+func withDefaultModel(sdModel string) string {
+	if sdModel == "" {
+		return config.ConfigGlobal.DefaultModel
+	}
+	return sdModel
+}
+
+// bodyModelField model name of dot path field in json body, array index as number like args.0.model,
+// checkpoint title "name [hash]" trim to name, empty if not found
+func bodyModelField(body map[string]interface{}, field string) string {
+	var val interface{} = body
+	for _, key := range strings.Split(field, ".") {
+		switch node := val.(type) {
+		case map[string]interface{}:
+			val = node[key]
+		case []interface{}:
+			idx, err := strconv.Atoi(key)
+			if err != nil || idx < 0 || idx >= len(node) {
+				return ""
+			}
+			val = node[idx]
+		default:
+			return ""
+		}
+	}
+	model, _ := val.(string)
+	model = strings.TrimSpace(model)
+	if idx := strings.LastIndex(model, " ["); idx > 0 && strings.HasSuffix(model, "]") {
+		model = model[:idx]
+	}
+	return model
+}
+
+// getSdEndpoint get sd endpoint
+// sdModel not set: use defaultModel, use last invoke endpoint first if defaultModel not set either
+// lastInvokeFirst: use last invoke endpoint first
+// endpoint empty: cold start by GetEndpoint, return NOFOUNDENDPOINT if still not found
+// cold start budget exhausted return ErrColdStartBudget
+func getSdEndpoint(sdModel string, lastInvokeFirst bool) (string, error) {
+	manager := getEndpointManager()
+	endpoint := ""
+	if sdModel == "" && config.ConfigGlobal.DefaultModel != "" {
+		// route default model deterministically
+		sdModel = config.ConfigGlobal.DefaultModel
+		lastInvokeFirst = false
+	}
+	if lastInvokeFirst || sdModel == "" {
+		endpoint = manager.GetLastInvokeEndpoint(&sdModel)
+	}
+	if endpoint == "" {
+		var err error
+		if endpoint, err = manager.GetEndpoint(sdModel); err != nil {
+			logrus.Errorf("sd %s get endpoint err=%s", sdModel, err.Error())
+			if errors.Is(err, module.ErrColdStartBudget) {
+				return "", err
+			}
+		}
+	}
+	if endpoint == "" {
+		return "", errors.New(config.NOFOUNDENDPOINT)
+	}
+	return endpoint, nil
+}
+
+// getStickyEndpoint sd endpoint pinned by X-Sticky-Route token of request, fallback to getSdEndpoint
+// when no token or pinned function gone, token of endpoint returned in X-Sticky-Route response header,
+// routingFallbackEndpoint returned when routing fail
+func getStickyEndpoint(c *gin.Context, sdModel string, lastInvokeFirst bool) (string, error) {
+	if token := c.GetHeader(stickyRouteKey); token != "" {
+		if endpoint := getEndpointManager().GetStickyEndpoint(token, sdModel); endpoint != "" {
+			c.Header(stickyRouteKey, token)
+			return endpoint, nil
+		}
+		logrus.Infof("sticky route %s endpoint gone or not serve sd %s, route by model", token, sdModel)
+	}
+	endpoint, err := getSdEndpoint(sdModel, lastInvokeFirst)
+	if err != nil {
+		if fallback, ok := routingFallback(c, err); ok {
+			return fallback, nil
+		}
+		return "", err
+	}
+	c.Header(stickyRouteKey, module.StickyToken(endpoint))
+	return endpoint, nil
+}
+
+// handle sd endpoint not found, cold start budget exhausted return 429
+func handleEndpointError(c *gin.Context, taskId string, err error) {
+	code := http.StatusInternalServerError
+	if errors.Is(err, module.ErrColdStartBudget) {
+		code = http.StatusTooManyRequests
+	}
+	c.JSON(code, models.SubmitTaskResponse{
+		TaskId:  taskId,
+		Status:  config.TASK_FAILED,
+		Message: utils.String(err.Error()),
+	})
+}
+
 func getBindResult(c *gin.Context, in interface{}) error {
 	if err := binding.JSON.Bind(c.Request, in); err != nil {
 		return err
@@ -42,6 +198,266 @@ func getBindResult(c *gin.Context, in interface{}) error {
 	return nil
 }
 
+// bind predict request, fill profile, defaultModel and model default params which request not set
+func (p *ProxyHandler) bindWithModelDefaults(c *gin.Context, in interface{}) error {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return err
+	}
+	return p.unmarshalWithModelDefaults(body, in)
+}
+
+// unmarshal predict request body, fill preset, profile, defaultModel and model default params which request not set
+func (p *ProxyHandler) unmarshalWithModelDefaults(body []byte, in interface{}) error {
+	request := make(map[string]interface{})
+	if err := json.Unmarshal(body, &request); err != nil {
+		return err
+	}
+	// request > preset > profile > model defaults
+	changed, err := applyDimensionPreset(request)
+	if err != nil {
+		return err
+	}
+	profileChanged, err := p.applyProfile(request)
+	if err != nil {
+		return err
+	}
+	changed = changed || profileChanged
+	sdModel, _ := request["stable_diffusion_model"].(string)
+	if sdModel == "" && config.ConfigGlobal.DefaultModel != "" {
+		sdModel = config.ConfigGlobal.DefaultModel
+		request["stable_diffusion_model"] = sdModel
+		changed = true
+	}
+	if sdModel != "" {
+		if defaults := p.getModelDefaults(sdModel); len(defaults) > 0 {
+			for key, val := range defaults {
+				if _, existed := request[key]; !existed {
+					request[key] = val
+				}
+			}
+			changed = true
+		}
+	}
+	if changed {
+		if body, err = json.Marshal(request); err != nil {
+			return err
+		}
+	}
+	return json.Unmarshal(body, in)
+}
+
+// get model default params, db first then config
+func (p *ProxyHandler) getModelDefaults(sdModel string) map[string]interface{} {
+	data, err := p.configStore.Get(modelDefaultsKey(sdModel), []string{datastore.KConfigVal})
+	if err == nil && len(data) > 0 {
+		if val, ok := data[datastore.KConfigVal].(string); ok && val != "" {
+			defaults := make(map[string]interface{})
+			if err := json.Unmarshal([]byte(val), &defaults); err == nil {
+				return defaults
+			}
+			logrus.Warnf("model %s defaults invalid, val=%s", sdModel, val)
+		}
+	}
+	return config.ConfigGlobal.ModelDefaults[sdModel]
+}
+
+func modelDefaultsKey(sdModel string) string {
+	return fmt.Sprintf("%s_%s", modelDefaultsPrefix, sdModel)
+}
+
+// setting key contain these words is secret, not return to client
+var secretSettingKeywords = []string{"api_key", "apikey", "access_key", "secret", "token", "password", "passwd",
+	"auth", "credential"}
+
+// stripSecretSettings copy settings without secret keys
+func stripSecretSettings(settings map[string]interface{}) map[string]interface{} {
+	ret := make(map[string]interface{}, len(settings))
+	for key, val := range settings {
+		secret := false
+		lowerKey := strings.ToLower(key)
+		for _, keyword := range secretSettingKeywords {
+			if strings.Contains(lowerKey, keyword) {
+				secret = true
+				break
+			}
+		}
+		if !secret {
+			ret[key] = val
+		}
+	}
+	return ret
+}
+
+// controlNet unit image keys, ossPath to base64Str
+var controlNetImageKeys = []string{"input_image", "image", "mask", "mask_image"}
+
+var (
+	promptTemplateRegex     = regexp.MustCompile(`\{\{\s*([\w\-.]+)\s*\}\}`)
+	promptTemplateNameRegex = regexp.MustCompile(`^[\w\-.]+$`)
+	// path separator and control char not allowed in image name field
+	imageNameUnsafeRegex = regexp.MustCompile(`[/\\\x00-\x1f]`)
+	// attachment filename keep letter, digit, dot, dash and underscore only
+	filenameUnsafeRegex = regexp.MustCompile(`[^A-Za-z0-9._\-]`)
+)
+
+// content type of browser download -> file ext
+var downloadContentTypes = map[string]string{
+	"image/png":                ".png",
+	"image/jpeg":               ".jpg",
+	"image/webp":               ".webp",
+	"image/gif":                ".gif",
+	"application/zip":          ".zip",
+	"application/octet-stream": "",
+}
+
+// sniffed content type of output image -> key ext
+var imageContentTypes = map[string]string{
+	"image/png":  ".png",
+	"image/jpeg": ".jpg",
+	"image/webp": ".webp",
+	"image/gif":  ".gif",
+}
+
+// expandPrompt replace {{template_name}} with template content
+// template can reference other template, max depth maxTemplateDepth and no cycle
+func expandPrompt(prompt string, templates map[string]string) (string, error) {
+	return expandPromptWithStack(prompt, templates, nil)
+}
+
+func expandPromptWithStack(prompt string, templates map[string]string, stack []string) (string, error) {
+	var expandErr error
+	ret := promptTemplateRegex.ReplaceAllStringFunc(prompt, func(match string) string {
+		if expandErr != nil {
+			return match
+		}
+		name := promptTemplateRegex.FindStringSubmatch(match)[1]
+		content, ok := templates[name]
+		if !ok {
+			expandErr = fmt.Errorf("prompt template %s not found", name)
+			return match
+		}
+		for _, one := range stack {
+			if one == name {
+				expandErr = fmt.Errorf("prompt template %s recursive reference", name)
+				return match
+			}
+		}
+		if len(stack) >= maxTemplateDepth {
+			expandErr = fmt.Errorf("prompt template %s exceed max depth %d", name, maxTemplateDepth)
+			return match
+		}
+		expanded, err := expandPromptWithStack(content, templates, append(stack, name))
+		if err != nil {
+			expandErr = err
+			return match
+		}
+		return expanded
+	})
+	if expandErr != nil {
+		return "", expandErr
+	}
+	if len(ret) > maxPromptLength {
+		return "", fmt.Errorf("expanded prompt length %d exceed %d", len(ret), maxPromptLength)
+	}
+	return ret, nil
+}
+
+// expand request prompt and negative prompt with user templates
+func (p *ProxyHandler) expandRequestPrompt(username string, prompts ...*string) error {
+	var templates map[string]string
+	for _, prompt := range prompts {
+		if prompt == nil || !promptTemplateRegex.MatchString(*prompt) {
+			continue
+		}
+		if templates == nil {
+			var err error
+			if templates, err = p.getPromptTemplates(username); err != nil {
+				return err
+			}
+		}
+		expanded, err := expandPrompt(*prompt, templates)
+		if err != nil {
+			return err
+		}
+		*prompt = expanded
+	}
+	return nil
+}
+
+// get user prompt templates, name -> content
+func (p *ProxyHandler) getPromptTemplates(username string) (map[string]string, error) {
+	templates := make(map[string]string)
+	data, err := p.configStore.Get(promptTemplateKey(username), []string{datastore.KConfigVal})
+	if err != nil {
+		return nil, err
+	}
+	if val, ok := data[datastore.KConfigVal].(string); ok && val != "" {
+		if err := json.Unmarshal([]byte(val), &templates); err != nil {
+			return nil, err
+		}
+	}
+	return templates, nil
+}
+
+var errPromptTemplateNotFound = errors.New("prompt template not found")
+
+// updatePromptTemplates read-modify-write prompt templates of user, concurrent update not lost
+func (p *ProxyHandler) updatePromptTemplates(username string, update func(templates map[string]string) error) error {
+	return p.updateConfigVal(promptTemplateKey(username), func(val string) (string, error) {
+		templates := make(map[string]string)
+		if val != "" {
+			if err := json.Unmarshal([]byte(val), &templates); err != nil {
+				return "", err
+			}
+		}
+		if err := update(templates); err != nil {
+			return "", err
+		}
+		ret, err := json.Marshal(templates)
+		return string(ret), err
+	})
+}
+
+// updateConfigVal read-modify-write config value of key, update get current value ("" if not set) and return new one,
+// retried with compare and swap when changed by others, error of update returned as is
+func (p *ProxyHandler) updateConfigVal(key string, update func(val string) (string, error)) error {
+	for i := 0; i < maxCasRetries; i++ {
+		data, err := p.configStore.Get(key, []string{datastore.KConfigVal})
+		if err != nil {
+			return err
+		}
+		// nil expected when value not set
+		current := data[datastore.KConfigVal]
+		cur, _ := current.(string)
+		val, err := update(cur)
+		if err != nil {
+			return err
+		}
+		values := map[string]interface{}{
+			datastore.KConfigVal:        val,
+			datastore.KConfigModifyTime: fmt.Sprintf("%d", utils.TimestampS()),
+		}
+		var written bool
+		if data == nil {
+			written, err = p.configStore.PutIfAbsent(key, values)
+		} else {
+			written, err = p.configStore.CompareAndSwap(key, datastore.KConfigVal, current, values)
+		}
+		if err != nil {
+			return err
+		}
+		if written {
+			return nil
+		}
+	}
+	return errCasConflict
+}
+
+func promptTemplateKey(username string) string {
+	return fmt.Sprintf("%s_%s", promptTemplatePrefix, username)
+}
+
 func outputImage(fileName, base64Str *string) error {
 	decode, err := base64.StdEncoding.DecodeString(*base64Str)
 	if err != nil {
@@ -54,31 +470,403 @@ func outputImage(fileName, base64Str *string) error {
 }
 
 func downloadModelsFromOss(modelsType, ossPath, modelName string) (string, error) {
-	path := ""
-	switch modelsType {
-	case config.SD_MODEL:
-		path = fmt.Sprintf("%s/models/%s/%s", config.ConfigGlobal.SdPath, "Stable-diffusion", modelName)
-	case config.SD_VAE:
-		path = fmt.Sprintf("%s/models/%s/%s", config.ConfigGlobal.SdPath, "VAE", modelName)
-	case config.LORA_MODEL:
-		path = fmt.Sprintf("%s/models/%s/%s", config.ConfigGlobal.SdPath, "Lora", modelName)
-	case config.CONTORLNET_MODEL:
-		path = fmt.Sprintf("%s/models/%s/%s", config.ConfigGlobal.SdPath, "ControlNet", modelName)
-	default:
+	dir := config.ConfigGlobal.GetModelDir(modelsType)
+	if dir == "" {
 		return "", fmt.Errorf("modeltype: %s not support", modelsType)
 	}
+	path := fmt.Sprintf("%s/%s", dir, modelName)
 	if err := module.OssGlobal.DownloadFile(ossPath, path); err != nil {
 		return "", err
 	}
 	return path, nil
 }
 
+// sanitizeNameField user influenced field can not change image oss key path
+func sanitizeNameField(val string) string {
+	val = imageNameUnsafeRegex.ReplaceAllString(val, "_")
+	val = strings.ReplaceAll(val, "..", "_")
+	if val == "" {
+		return "_"
+	}
+	return val
+}
+
+// safeFilename filename safe in Content-Disposition, no path or quote, keep ext when too long
+func safeFilename(name string) string {
+	name = filenameUnsafeRegex.ReplaceAllString(path.Base(strings.ReplaceAll(name, "\\", "/")), "_")
+	name = strings.TrimLeft(name, ".")
+	if len(name) > maxFilenameLength {
+		ext := path.Ext(name)
+		if len(ext) >= maxFilenameLength {
+			ext = ""
+		}
+		name = name[:maxFilenameLength-len(ext)] + ext
+	}
+	if name == "" || name == "_" {
+		return defaultFilename
+	}
+	return name
+}
+
+// downloadFilename {taskId}_{index}{ext}, index <= 0 means whole task like archive
+func downloadFilename(taskId string, index int, ext string) string {
+	name := taskId
+	if name == "" {
+		name = defaultFilename
+	}
+	if index > 0 {
+		name = fmt.Sprintf("%s_%d", name, index)
+	}
+	return safeFilename(name + ext)
+}
+
+// setContentDisposition name response filename, browser display it inline
+// unless request ask save as file by query download=true
+func setContentDisposition(c *gin.Context, filename string) {
+	disposition := "inline"
+	if download, err := strconv.ParseBool(c.Query(downloadQueryKey)); err == nil && download {
+		disposition = "attachment"
+	}
+	c.Header("Content-Disposition", mime.FormatMediaType(disposition,
+		map[string]string{"filename": safeFilename(filename)}))
+}
+
+// passthroughFilename download filename of sd response, "" means not download
+// upstream filename first, then taskId, then request path
+func passthroughFilename(taskId, reqPath string, header http.Header) string {
+	if _, params, err := mime.ParseMediaType(header.Get("Content-Disposition")); err == nil &&
+		params["filename"] != "" {
+		return safeFilename(params["filename"])
+	}
+	mediaType, _, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		return ""
+	}
+	ext, ok := downloadContentTypes[mediaType]
+	if !ok {
+		return ""
+	}
+	if taskId != "" {
+		return downloadFilename(taskId, 0, ext)
+	}
+	name := path.Base(reqPath)
+	if path.Ext(name) == "" {
+		name += ext
+	}
+	return safeFilename(name)
+}
+
+// infoSeeds resolved seed of each image from sd result info
+func infoSeeds(info string) []int64 {
+	var seeds struct {
+		Seed     *int64  `json:"seed"`
+		AllSeeds []int64 `json:"all_seeds"`
+	}
+	if err := json.Unmarshal([]byte(info), &seeds); err != nil {
+		return nil
+	}
+	if len(seeds.AllSeeds) > 0 {
+		return seeds.AllSeeds
+	}
+	if seeds.Seed != nil {
+		return []int64{*seeds.Seed}
+	}
+	return nil
+}
+
+// webuiJobId sd webui job id, info job_timestamp first, then response header set by webui behind proxy
+func webuiJobId(info string, header http.Header) string {
+	var job struct {
+		JobTimestamp string `json:"job_timestamp"`
+	}
+	if err := json.Unmarshal([]byte(info), &job); err == nil && job.JobTimestamp != "" {
+		return job.JobTimestamp
+	}
+	return header.Get(webuiJobIdKey)
+}
+
+// imageOssKey oss key of index(from 1) image by config imageNameTemplate
+func imageOssKey(user, taskId string, index int, seeds []int64, now time.Time) string {
+	seed := "unknown"
+	if index-1 < len(seeds) {
+		seed = strconv.FormatInt(seeds[index-1], 10)
+	} else if len(seeds) > 0 {
+		seed = strconv.FormatInt(seeds[0], 10)
+	}
+	replacer := strings.NewReplacer(
+		"{user}", sanitizeNameField(user),
+		"{taskId}", sanitizeNameField(taskId),
+		"{index}", strconv.Itoa(index),
+		"{seed}", seed,
+		"{date}", now.Format("20060102"),
+		"{timestamp}", strconv.FormatInt(now.Unix(), 10),
+	)
+	return strings.TrimLeft(path.Clean(replacer.Replace(config.ConfigGlobal.ImageNameTemplate)), "/")
+}
+
+// resolveOutputPrefix check request output_prefix allowed for user, empty when not set
+// return http status code with error, 400 for invalid path and 403 for not allowed
+func resolveOutputPrefix(user string, outputPrefix *string) (string, int, error) {
+	if outputPrefix == nil || *outputPrefix == "" {
+		return "", http.StatusOK, nil
+	}
+	prefix := strings.TrimSuffix(*outputPrefix, "/")
+	if strings.HasPrefix(prefix, "/") || path.Clean(prefix) != prefix || strings.Contains(prefix, "..") {
+		return "", http.StatusBadRequest, fmt.Errorf("output_prefix %s invalid, need clean relative path",
+			*outputPrefix)
+	}
+	for _, allowed := range config.ConfigGlobal.GetOutputPrefixes(user) {
+		allowed = path.Clean(allowed)
+		if prefix == allowed || strings.HasPrefix(prefix, allowed+"/") {
+			return prefix, http.StatusOK, nil
+		}
+	}
+	return "", http.StatusForbidden, fmt.Errorf("output_prefix %s not allowed", *outputPrefix)
+}
+
+// inpainting defaults and ranges follow webui ui
+const (
+	defaultMaskBlur         = 4
+	defaultInpaintPadding   = 32
+	maxMaskBlur             = 64
+	maxInpaintPadding       = 256
+	maxInpaintingFill       = 3
+	maxInpaintingMaskInvert = 1
+)
+
+// normalizeInpaint default and check inpainting fields when mask set, webui ignore them without mask
+// webui api padding default 0 crop only masked area too tight, default 32 like webui ui
+func normalizeInpaint(request *models.Img2ImgRequest) error {
+	if request.Mask == nil || *request.Mask == "" {
+		return nil
+	}
+	for _, field := range []struct {
+		name  string
+		value *int64
+		max   int64
+	}{
+		{"mask_blur", request.MaskBlur, maxMaskBlur},
+		{"mask_blur_x", request.MaskBlurX, maxMaskBlur},
+		{"mask_blur_y", request.MaskBlurY, maxMaskBlur},
+		{"inpaint_full_res_padding", request.InpaintFullResPadding, maxInpaintPadding},
+		{"inpainting_fill", request.InpaintingFill, maxInpaintingFill},
+		{"inpainting_mask_invert", request.InpaintingMaskInvert, maxInpaintingMaskInvert},
+	} {
+		if field.value != nil && (*field.value < 0 || *field.value > field.max) {
+			return fmt.Errorf("%s %d invalid, need 0-%d", field.name, *field.value, field.max)
+		}
+	}
+	if request.MaskBlur == nil {
+		request.MaskBlur = utils.Int64(defaultMaskBlur)
+	}
+	// only masked, webui default true
+	if (request.InpaintFullRes == nil || *request.InpaintFullRes) && request.InpaintFullResPadding == nil {
+		request.InpaintFullResPadding = utils.Int64(defaultInpaintPadding)
+	}
+	return nil
+}
+
+// taskLabelsVal check labels count and size, return json store in task, empty when no labels
+func taskLabelsVal(labels *map[string]string) (string, error) {
+	if labels == nil || len(*labels) == 0 {
+		return "", nil
+	}
+	if len(*labels) > maxTaskLabels {
+		return "", fmt.Errorf("labels count %d exceed limit %d", len(*labels), maxTaskLabels)
+	}
+	size := 0
+	for key, val := range *labels {
+		if key == "" {
+			return "", errors.New("labels key can not be empty")
+		}
+		size += len(key) + len(val)
+	}
+	if size > maxTaskLabelsSize {
+		return "", fmt.Errorf("labels size %d exceed limit %d bytes", size, maxTaskLabelsSize)
+	}
+	val, err := json.Marshal(*labels)
+	if err != nil {
+		return "", err
+	}
+	return string(val), nil
+}
+
+// invoke modes of SubmitTaskResponse
+const (
+	invokeModeSync  = "sync"
+	invokeModeAsync = "async"
+)
+
+// errCasConflict value kept changed by others, compare and swap retry exhausted
+var errCasConflict = errors.New("concurrent update conflict, please retry")
+
+// nextConfigVersion timestamp version, greater than current so concurrent bump in same second not collide
+func nextConfigVersion(current string) string {
+	version := utils.TimestampS()
+	if cur, err := strconv.ParseInt(current, 10, 64); err == nil && cur >= version {
+		version = cur + 1
+	}
+	return fmt.Sprintf("%d", version)
+}
+
+// taskLabels labels stored in task, nil when not set
+func taskLabels(data map[string]interface{}) *map[string]string {
+	val, ok := data[datastore.KTaskLabels].(string)
+	if !ok || val == "" {
+		return nil
+	}
+	labels := make(map[string]string)
+	if err := json.Unmarshal([]byte(val), &labels); err != nil {
+		return nil
+	}
+	return &labels
+}
+
+// imageKeyWithFormat fix oss key ext by real format of image bytes
+// unknown format or ext already match (.jpeg for jpeg) keep key
+func imageKeyWithFormat(ossKey string, body []byte) string {
+	contentType := http.DetectContentType(body)
+	ext, ok := imageContentTypes[contentType]
+	if !ok {
+		return ossKey
+	}
+	oldExt := path.Ext(ossKey)
+	if mime.TypeByExtension(oldExt) == contentType {
+		return ossKey
+	}
+	return strings.TrimSuffix(ossKey, oldExt) + ext
+}
+
+// hotModelEndpoint running webui should list new sd model, empty if no function invoked yet
+func hotModelEndpoint(sdModel string) string {
+	if config.ConfigGlobal.GetFlexMode() == config.MultiFunc && config.ConfigGlobal.IsServerTypeMatch(config.CONTROL) {
+		return getEndpointManager().GetLastInvokeEndpoint(&sdModel)
+	}
+	return config.ConfigGlobal.SdUrlPrefix
+}
+
+// rejectInlineImages response 400 and return true when client ask inline images of api whose result
+// not returned by this proxy (rendered downstream or in background)
+func rejectInlineImages(c *gin.Context) bool {
+	if c.GetHeader(inlineImagesKey) != "true" {
+		return false
+	}
+	handleError(c, http.StatusBadRequest, fmt.Sprintf("%s not supported by %s", inlineImagesKey,
+		c.Request.URL.Path))
+	return true
+}
+
+// inlineImageMaxBytes max total base64 size of inline images requested by client, 0 not inline
+func inlineImageMaxBytes(c *gin.Context) int64 {
+	if c.GetHeader(inlineImagesKey) != "true" {
+		return 0
+	}
+	return config.ConfigGlobal.GetInlineImageMaxBytes()
+}
+
+// imageDataUris base64 images to data uri, nil if total size exceed maxBytes
+func imageDataUris(images []string, maxBytes int64) []string {
+	total := int64(0)
+	for _, image := range images {
+		total += int64(len(image))
+	}
+	if maxBytes <= 0 || total > maxBytes {
+		return nil
+	}
+	dataUris := make([]string, 0, len(images))
+	for _, image := range images {
+		head := image
+		if len(head) > sniffBase64Len {
+			head = head[:sniffBase64Len]
+		}
+		decode, _ := base64.StdEncoding.DecodeString(head)
+		contentType := http.DetectContentType(decode)
+		if _, ok := imageContentTypes[contentType]; !ok {
+			// sd default output format
+			contentType = "image/png"
+		}
+		dataUris = append(dataUris, fmt.Sprintf("data:%s;base64,%s", contentType, image))
+	}
+	return dataUris
+}
+
+// uploadImages upload decoded image, ossPath ext fixed by image format when detectImageType on,
+// png compressed when imageCompression on
 func uploadImages(ossPath, imageBody *string) error {
 	decode, err := base64.StdEncoding.DecodeString(*imageBody)
 	if err != nil {
 		return fmt.Errorf("base64 decode err=%s", err.Error())
 	}
-	return module.OssGlobal.UploadFileByByte(*ossPath, decode)
+	if config.ConfigGlobal.IsDetectImageType() {
+		*ossPath = imageKeyWithFormat(*ossPath, decode)
+	}
+	return module.OssGlobal.UploadFileByByte(*ossPath, compressImage(decode))
+}
+
+// upload images to oss with bounded concurrency, ossPaths[i] for images[i], one fail not stop others
+// onProgress called with count of leading uploaded images, keep images order
+// return indices of failed images ascending and first error
+func uploadImagesConcurrently(ossPaths, images []string, onProgress func(uploaded int)) ([]int, error) {
+	concurrency := config.ConfigGlobal.OssUploadConcurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	var (
+		wg       sync.WaitGroup
+		lock     sync.Mutex
+		firstErr error
+		uploaded int
+		// onProgress serialized outside lock, uploads not wait db write
+		progressLock sync.Mutex
+		reported     int
+	)
+	done := make([]bool, len(images))
+	errs := make([]error, len(images))
+	sem := make(chan struct{}, concurrency)
+	for i := range images {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			err := uploadImages(&ossPaths[i], &images[i])
+			<-sem
+			// release decoded image early
+			images[i] = ""
+			lock.Lock()
+			if err != nil {
+				errs[i] = err
+				if firstErr == nil {
+					firstErr = err
+				}
+				lock.Unlock()
+				return
+			}
+			done[i] = true
+			for uploaded < len(done) && done[uploaded] {
+				uploaded++
+			}
+			current := uploaded
+			lock.Unlock()
+			if onProgress == nil {
+				return
+			}
+			progressLock.Lock()
+			defer progressLock.Unlock()
+			// newer count may be reported by other upload first
+			if current > reported {
+				reported = current
+				onProgress(current)
+			}
+		}(i)
+	}
+	wg.Wait()
+	var failed []int
+	for i, err := range errs {
+		if err != nil {
+			failed = append(failed, i)
+		}
+	}
+	return failed, firstErr
 }
 
 // delete local file
@@ -98,7 +886,7 @@ func deleteLocalModelFile(localFile string) (bool, error) {
 }
 
 func handleError(c *gin.Context, code int, err string) {
-	c.JSON(code, gin.H{"message": err})
+	c.JSON(code, errorBody(c, code, err))
 }
 
 func isImgPath(str string) bool {
@@ -107,20 +895,64 @@ func isImgPath(str string) bool {
 }
 
 func listModelFile(path, modelType string) (modelAttrs []*models.ModelAttributes) {
-	files := utils.ListFile(path)
-	for _, name := range files {
+	files, _ := os.ReadDir(path)
+	for _, file := range files {
+		name := file.Name()
 		if strings.HasSuffix(name, ".pt") || strings.HasSuffix(name, ".ckpt") ||
 			strings.HasSuffix(name, ".safetensors") || strings.HasSuffix(name, ".pth") {
-			modelAttrs = append(modelAttrs, &models.ModelAttributes{
+			attr := &models.ModelAttributes{
 				Type:   modelType,
 				Name:   name,
 				Status: config.MODEL_LOADED,
-			})
+			}
+			// stat follow symlink to model file
+			if info, err := os.Stat(filepath.Join(path, name)); err == nil {
+				attr.Size = utils.Int64(info.Size())
+			}
+			modelAttrs = append(modelAttrs, attr)
 		}
 	}
 	return
 }
 
+// BodyLimit limit request body size, return 413 when exceeded
+// model upload route (POST /models) not limit
+func BodyLimit(limit int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if limit <= 0 || c.Request.Body == nil ||
+			(c.Request.Method == http.MethodPost && c.Request.URL.Path == modelUploadPath) {
+			c.Next()
+			return
+		}
+		if c.Request.ContentLength > limit {
+			abortTooLarge(c, limit)
+			return
+		}
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit)
+		if c.Request.ContentLength < 0 {
+			// chunked body, read first to check size
+			body, err := io.ReadAll(c.Request.Body)
+			if err != nil {
+				var maxBytesErr *http.MaxBytesError
+				if errors.As(err, &maxBytesErr) {
+					abortTooLarge(c, limit)
+				} else {
+					handleError(c, http.StatusBadRequest, config.BADREQUEST)
+					c.Abort()
+				}
+				return
+			}
+			c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		}
+		c.Next()
+	}
+}
+
+func abortTooLarge(c *gin.Context, limit int64) {
+	c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, errorBody(c, http.StatusRequestEntityTooLarge,
+		fmt.Sprintf("request body too large, limit %dMB", limit>>20)))
+}
+
 // Stat cost code
 func Stat() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -203,7 +1035,7 @@ func parseMap(aMap map[string]interface{}, taskId, user string, idx *int) map[st
 			aMap[key] = parseArray(val.([]interface{}), taskId, user, idx)
 		case string:
 			if isImgPath(concreteVal) {
-				base64, err := module.OssGlobal.DownloadFileToBase64(concreteVal)
+				base64, err := downloadFileToBase64(concreteVal)
 				if err == nil {
 					aMap[key] = *base64
 				}
@@ -232,7 +1064,7 @@ func parseArray(anArray []interface{}, taskId, user string, idx *int) []interfac
 			anArray[i] = parseArray(val.([]interface{}), taskId, user, idx)
 		case string:
 			if isImgPath(concreteVal) {
-				base64, err := module.OssGlobal.DownloadFileToBase64(concreteVal)
+				base64, err := downloadFileToBase64(concreteVal)
 				if err == nil {
 					anArray[i] = *base64
 				}
@@ -265,12 +1097,13 @@ func getFunctionDatas(store datastore.Datastore,
 		for _, funcData := range funcDatas {
 			functionName := funcData[datastore.KModelServiceFunctionName].(string)
 			if resource := module.FuncManagerGlobal.GetFuncResource(functionName); resource != nil {
-				funcDataNew, err := updateFuncResource(request, resource)
+				key := funcData[datastore.KModelServiceKey].(string)
+				funcDataNew, err := updateFuncResource(request, key, resource)
 				if err != nil {
 					return nil, err
 				}
 				if funcDataNew != nil {
-					Datas[funcData[datastore.KModelServiceKey].(string)] = funcDataNew
+					Datas[key] = funcDataNew
 				}
 			}
 		}
@@ -278,7 +1111,7 @@ func getFunctionDatas(store datastore.Datastore,
 		for _, model := range *request.Models {
 			functionName := module.GetFunctionName(model)
 			if resource := module.FuncManagerGlobal.GetFuncResource(functionName); resource != nil {
-				funcDataNew, err := updateFuncResource(request, resource)
+				funcDataNew, err := updateFuncResource(request, model, resource)
 				if err != nil {
 					return nil, err
 				}
@@ -291,7 +1124,38 @@ func getFunctionDatas(store datastore.Datastore,
 	return Datas, nil
 }
 
-func updateFuncResource(request *models.BatchUpdateSdResourceRequest,
+// aggregate task gpu seconds by user, only user if not empty
+// task without gpu seconds record(old task or not predict yet) skip
+func aggregateUsage(tasks map[string]map[string]interface{}, user string) models.UsageList {
+	usages := make(map[string]*models.UserUsage)
+	for _, task := range tasks {
+		gpuSeconds, ok := task[datastore.KTaskGpuSeconds].(float64)
+		if !ok {
+			continue
+		}
+		taskUser, _ := task[datastore.KTaskUser].(string)
+		if user != "" && taskUser != user {
+			continue
+		}
+		usage, ok := usages[taskUser]
+		if !ok {
+			usage = &models.UserUsage{User: taskUser}
+			usages[taskUser] = usage
+		}
+		usage.Tasks++
+		usage.GpuSeconds += gpuSeconds
+	}
+	ret := make(models.UsageList, 0, len(usages))
+	for _, usage := range usages {
+		ret = append(ret, *usage)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].User < ret[j].User
+	})
+	return ret
+}
+
+func updateFuncResource(request *models.BatchUpdateSdResourceRequest, key string,
 	res *module.FuncResource) (*module.FuncResource, error) {
 	isDiff := false
 	// update resource
@@ -335,9 +1199,13 @@ func updateFuncResource(request *models.BatchUpdateSdResourceRequest,
 		res.InstanceType = *request.InstanceType
 		isDiff = true
 	}
-	// timeout
-	if request.Timeout != nil && *request.Timeout > 0 && *request.Timeout != int64(res.Timeout) {
-		res.Timeout = int32(*request.Timeout)
+	// timeout, model timeout not lost by batch update not set timeout
+	timeout := config.ConfigGlobal.ModelTimeouts[key]
+	if request.Timeout != nil && *request.Timeout > 0 {
+		timeout = int32(*request.Timeout)
+	}
+	if timeout > 0 && timeout != res.Timeout {
+		res.Timeout = timeout
 		isDiff = true
 	}
 	if request.VpcConfig != nil {
@@ -402,10 +1270,16 @@ func extraErrorMsg(resp *http.Response) *string {
 
 func handleRespError(c *gin.Context, err error, resp *http.Response, taskId string) {
 	msg := ""
+	code := http.StatusInternalServerError
 	if err != nil {
 		msg = err.Error()
+		// endpoint failing, client retry later
+		if errors.Is(err, client.ErrCircuitOpen) {
+			code = http.StatusServiceUnavailable
+		}
 		logrus.WithFields(logrus.Fields{"taskId": taskId}).Errorf("%v", err)
 	} else {
+		code = resp.StatusCode
 		if v := extraErrorMsg(resp); v != nil {
 			msg = *v
 		} else {
@@ -413,7 +1287,7 @@ func handleRespError(c *gin.Context, err error, resp *http.Response, taskId stri
 		}
 		logrus.WithFields(logrus.Fields{"taskId": taskId}).Errorf("%v", resp)
 	}
-	c.JSON(resp.StatusCode, models.SubmitTaskResponse{
+	c.JSON(code, models.SubmitTaskResponse{
 		TaskId:  taskId,
 		Status:  config.TASK_FAILED,
 		Message: utils.String(msg),
@@ -0,0 +1,1019 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/datastore"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/models"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/module"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeEndpointManager struct {
+	lock         sync.Mutex
+	lastEndpoint string
+	endpoints    map[string]string
+	err          error
+	coldStarts   []string
+	envResults   []module.FuncEnvResult
+	refreshes    []string
+	gpuMemory    map[string]int32
+}
+
+func (f *fakeEndpointManager) GetGpuMemorySize(sdModel string) int32 {
+	if size, ok := f.gpuMemory[sdModel]; ok {
+		return size
+	}
+	return config.ConfigGlobal.GpuMemorySize
+}
+
+func (f *fakeEndpointManager) GetEndpoint(sdModel string) (string, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.coldStarts = append(f.coldStarts, sdModel)
+	return f.endpoints[sdModel], f.err
+}
+
+func (f *fakeEndpointManager) GetLastInvokeEndpoint(sdModel *string) string {
+	return f.lastEndpoint
+}
+
+func (f *fakeEndpointManager) GetStickyEndpoint(token, sdModel string) string {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if endpoint, ok := f.endpoints[sdModel]; ok && module.StickyToken(endpoint) == token {
+		return endpoint
+	}
+	return ""
+}
+
+func (f *fakeEndpointManager) RefreshFunctionsEnv(sdModel string) []module.FuncEnvResult {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.refreshes = append(f.refreshes, sdModel)
+	ret := make([]module.FuncEnvResult, 0, len(f.envResults))
+	for _, result := range f.envResults {
+		if sdModel == "" || result.SdModel == sdModel {
+			ret = append(ret, result)
+		}
+	}
+	return ret
+}
+
+func initTestConfig(t *testing.T) {
+	old := config.ConfigGlobal
+	t.Cleanup(func() {
+		config.ConfigGlobal = old
+	})
+	config.ConfigGlobal = &config.Config{
+		ConfigYaml: config.ConfigYaml{
+			ServerName: config.CONTROL,
+			FlexMode:   "multiFunc",
+			DbSqlite:   filepath.Join(t.TempDir(), "sqlite3"),
+		},
+		ConfigEnv: config.ConfigEnv{
+			ColdStartConcurrency: config.ColdStartConcurrency,
+		},
+	}
+}
+
+func mockEndpointManager(t *testing.T, manager *fakeEndpointManager) {
+	old := getEndpointManager
+	getEndpointManager = func() sdEndpointManager {
+		return manager
+	}
+	t.Cleanup(func() {
+		getEndpointManager = old
+	})
+}
+
+func TestGetSdEndpoint(t *testing.T) {
+	initTestConfig(t)
+
+	// last invoke endpoint first
+	manager := &fakeEndpointManager{lastEndpoint: "http://last"}
+	mockEndpointManager(t, manager)
+	endpoint, err := getSdEndpoint("sd", true)
+	assert.Nil(t, err)
+	assert.Equal(t, "http://last", endpoint)
+	assert.Empty(t, manager.coldStarts)
+
+	// last invoke empty, cold start with model
+	manager = &fakeEndpointManager{endpoints: map[string]string{"sd": "http://sd"}}
+	mockEndpointManager(t, manager)
+	endpoint, err = getSdEndpoint("sd", true)
+	assert.Nil(t, err)
+	assert.Equal(t, "http://sd", endpoint)
+	assert.Equal(t, []string{"sd"}, manager.coldStarts)
+
+	// cold start fail
+	manager = &fakeEndpointManager{err: errors.New("create function fail")}
+	mockEndpointManager(t, manager)
+	_, err = getSdEndpoint("sd", false)
+	assert.EqualError(t, err, config.NOFOUNDENDPOINT)
+
+	// cold start budget exhausted
+	manager = &fakeEndpointManager{err: module.ErrColdStartBudget}
+	mockEndpointManager(t, manager)
+	_, err = getSdEndpoint("sd", false)
+	assert.ErrorIs(t, err, module.ErrColdStartBudget)
+
+	// not found
+	manager = &fakeEndpointManager{}
+	mockEndpointManager(t, manager)
+	_, err = getSdEndpoint("", false)
+	assert.EqualError(t, err, config.NOFOUNDENDPOINT)
+	assert.Equal(t, []string{""}, manager.coldStarts)
+
+	// model not set, route to default model not last invoke endpoint
+	oldDefault := config.ConfigGlobal.DefaultModel
+	config.ConfigGlobal.DefaultModel = "sd"
+	t.Cleanup(func() {
+		config.ConfigGlobal.DefaultModel = oldDefault
+	})
+	manager = &fakeEndpointManager{lastEndpoint: "http://last", endpoints: map[string]string{"sd": "http://sd"}}
+	mockEndpointManager(t, manager)
+	endpoint, err = getSdEndpoint("", true)
+	assert.Nil(t, err)
+	assert.Equal(t, "http://sd", endpoint)
+	assert.Equal(t, []string{"sd"}, manager.coldStarts)
+}
+
+func TestHotModelEndpoint(t *testing.T) {
+	initTestConfig(t)
+	config.ConfigGlobal.SdUrlPrefix = "http://local"
+	// control refresh webui of function last invoked
+	mockEndpointManager(t, &fakeEndpointManager{lastEndpoint: "http://last"})
+	assert.Equal(t, "http://last", hotModelEndpoint("new"))
+	// single function refresh local webui
+	config.ConfigGlobal.FlexMode = "singleFunc"
+	assert.Equal(t, "http://local", hotModelEndpoint("sd"))
+}
+
+func TestGetStickyEndpoint(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	initTestConfig(t)
+	manager := &fakeEndpointManager{endpoints: map[string]string{"sd": "http://sd", "sd_xl": "http://sd_xl"}}
+	mockEndpointManager(t, manager)
+	route := func(token, sdModel string) (string, string) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/img2img", nil)
+		if token != "" {
+			c.Request.Header.Set(stickyRouteKey, token)
+		}
+		endpoint, err := getStickyEndpoint(c, sdModel, false)
+		assert.Nil(t, err)
+		return endpoint, w.Header().Get(stickyRouteKey)
+	}
+
+	// first request route by model, get token
+	endpoint, token := route("", "sd_xl")
+	assert.Equal(t, "http://sd_xl", endpoint)
+	assert.Equal(t, module.StickyToken("http://sd_xl"), token)
+	// following request of same model pinned
+	endpoint, pinned := route(token, "sd_xl")
+	assert.Equal(t, "http://sd_xl", endpoint)
+	assert.Equal(t, token, pinned)
+	assert.Equal(t, []string{"sd_xl"}, manager.coldStarts)
+	// other model not served by pinned function, route by model and new token
+	endpoint, other := route(token, "sd")
+	assert.Equal(t, "http://sd", endpoint)
+	assert.Equal(t, module.StickyToken("http://sd"), other)
+
+	// pinned function gone, fallback and new token
+	delete(manager.endpoints, "sd_xl")
+	manager.endpoints["sd_xl"] = "http://sd_xl_new"
+	endpoint, token = route(token, "sd_xl")
+	assert.Equal(t, "http://sd_xl_new", endpoint)
+	assert.Equal(t, module.StickyToken("http://sd_xl_new"), token)
+}
+
+func TestEmptyEndpointHandlers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	initTestConfig(t)
+	configStore := datastore.NewSQLiteDatastore(datastore.NewSQLiteConfig(datastore.KConfigTableName))
+	defer configStore.Close()
+	p := &ProxyHandler{configStore: configStore}
+
+	cases := []struct {
+		name    string
+		path    string
+		body    string
+		handler gin.HandlerFunc
+	}{
+		{
+			name:    "extraImages",
+			path:    "/extra_images",
+			body:    `{"stable_diffusion_model":"sd","image":"img","resize_mode":0}`,
+			handler: p.ExtraImages,
+		},
+		{
+			name:    "img2img",
+			path:    "/img2img",
+			body:    `{"stable_diffusion_model":"sd","init_images":[]}`,
+			handler: p.Img2Img,
+		},
+		{
+			name:    "noRouter",
+			path:    "/sdapi/v1/png-info",
+			body:    `{"StableDiffusionModel":"sd"}`,
+			handler: p.NoRouterHandler,
+		},
+	}
+	for _, tc := range cases {
+		manager := &fakeEndpointManager{}
+		mockEndpointManager(t, manager)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, tc.path, bytes.NewBufferString(tc.body))
+		c.Request.Header.Set("Content-Type", "application/json")
+		tc.handler(c)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code, tc.name)
+		var resp models.SubmitTaskResponse
+		assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &resp), tc.name)
+		assert.Equal(t, config.TASK_FAILED, resp.Status, tc.name)
+		assert.Equal(t, config.NOFOUNDENDPOINT, *resp.Message, tc.name)
+		// cold start with model
+		assert.Equal(t, []string{"sd"}, manager.coldStarts, tc.name)
+	}
+}
+
+func TestExpandPrompt(t *testing.T) {
+	templates := map[string]string{
+		"style":   "masterpiece, {{ quality }}",
+		"quality": "best quality",
+		"loop-a":  "{{loop-b}}",
+		"loop-b":  "{{loop-a}}",
+	}
+	ret, err := expandPrompt("a cat, {{style}}", templates)
+	assert.Nil(t, err)
+	assert.Equal(t, "a cat, masterpiece, best quality", ret)
+
+	_, err = expandPrompt("{{loop-a}}", templates)
+	assert.NotNil(t, err)
+
+	_, err = expandPrompt("{{unknown}}", templates)
+	assert.NotNil(t, err)
+
+	templates["long"] = strings.Repeat("a", maxPromptLength+1)
+	_, err = expandPrompt("{{long}}", templates)
+	assert.NotNil(t, err)
+}
+
+func TestAggregateUsage(t *testing.T) {
+	tasks := map[string]map[string]interface{}{
+		"t1": {datastore.KTaskUser: "bob", datastore.KTaskGpuSeconds: 1.5},
+		"t2": {datastore.KTaskUser: "alice", datastore.KTaskGpuSeconds: 2.0},
+		"t3": {datastore.KTaskUser: "bob", datastore.KTaskGpuSeconds: 3.0},
+		// old task without gpu seconds
+		"t4": {datastore.KTaskUser: "bob"},
+	}
+	usages := aggregateUsage(tasks, "")
+	assert.Equal(t, models.UsageList{
+		{User: "alice", Tasks: 1, GpuSeconds: 2.0},
+		{User: "bob", Tasks: 2, GpuSeconds: 4.5},
+	}, usages)
+
+	usages = aggregateUsage(tasks, "bob")
+	assert.Equal(t, models.UsageList{{User: "bob", Tasks: 2, GpuSeconds: 4.5}}, usages)
+
+	assert.Empty(t, aggregateUsage(tasks, "nobody"))
+}
+
+func TestStripSecretSettings(t *testing.T) {
+	settings := map[string]interface{}{
+		"sd_model_checkpoint": "sd",
+		"sd_vae":              "None",
+		"CivitAI_API_Key":     "xxx",
+		"hf_token":            "xxx",
+	}
+	assert.Equal(t, map[string]interface{}{
+		"sd_model_checkpoint": "sd",
+		"sd_vae":              "None",
+	}, stripSecretSettings(settings))
+	// source not changed
+	assert.Len(t, settings, 4)
+}
+
+func TestImageOssKey(t *testing.T) {
+	initTestConfig(t)
+	config.ConfigGlobal.ImageNameTemplate = config.DefaultImageNameTemplate
+	now := time.Date(2023, 10, 1, 8, 0, 0, 0, time.UTC)
+	seeds := infoSeeds(`{"seed": 100, "all_seeds": [100, 101]}`)
+	assert.Equal(t, []int64{100, 101}, seeds)
+
+	// default naming not changed
+	assert.Equal(t, "images/user/task_1.png", imageOssKey("user", "task", 1, seeds, now))
+
+	config.ConfigGlobal.ImageNameTemplate = "images/{user}/{date}/{taskId}_{index}_{seed}.png"
+	assert.Equal(t, "images/user/20231001/task_2_101.png", imageOssKey("user", "task", 2, seeds, now))
+	assert.Equal(t, "images/user/20231001/task_3_100.png", imageOssKey("user", "task", 3, seeds, now))
+	assert.Equal(t, "images/user/20231001/task_1_unknown.png", imageOssKey("user", "task", 1,
+		infoSeeds("not json"), now))
+
+	// user influenced field can not traversal
+	assert.Equal(t, "images/____etc/20231001/task_1_100.png",
+		imageOssKey("../../etc", "task", 1, seeds, now))
+	assert.Equal(t, "images/_/20231001/a_b_1_100.png", imageOssKey("", "a/b", 1, seeds, now))
+}
+
+func TestResolveOutputPrefix(t *testing.T) {
+	initTestConfig(t)
+	// not set keep default layout
+	prefix, _, err := resolveOutputPrefix("user", nil)
+	assert.Nil(t, err)
+	assert.Empty(t, prefix)
+	// no policy reject any prefix
+	_, code, err := resolveOutputPrefix("user", utils.String("projects"))
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusForbidden, code)
+
+	config.ConfigGlobal.OutputPrefixes = map[string][]string{
+		config.AllUsers: {"users/{user}"},
+		"admin":         {"projects/"},
+	}
+	allowed := map[string]string{
+		"users/user":      "users/user",
+		"users/user/a/b/": "users/user/a/b",
+		"":                "",
+	}
+	for request, expect := range allowed {
+		prefix, _, err := resolveOutputPrefix("user", utils.String(request))
+		assert.Nil(t, err, request)
+		assert.Equal(t, expect, prefix)
+	}
+	prefix, _, err = resolveOutputPrefix("admin", utils.String("projects/demo"))
+	assert.Nil(t, err)
+	assert.Equal(t, "projects/demo", prefix)
+
+	denied := map[string]int{
+		"projects/demo":       http.StatusForbidden,
+		"users/user2":         http.StatusForbidden,
+		"users/username":      http.StatusForbidden,
+		"users/user/../admin": http.StatusBadRequest,
+		"/users/user":         http.StatusBadRequest,
+		"users//user":         http.StatusBadRequest,
+		"users/user/./a":      http.StatusBadRequest,
+	}
+	for request, expect := range denied {
+		_, code, err := resolveOutputPrefix("user", utils.String(request))
+		assert.NotNil(t, err, request)
+		assert.Equal(t, expect, code, request)
+	}
+}
+
+func TestNormalizeInpaint(t *testing.T) {
+	// no mask not touch
+	request := &models.Img2ImgRequest{MaskBlur: utils.Int64(100)}
+	assert.Nil(t, normalizeInpaint(request))
+	assert.Nil(t, request.InpaintFullResPadding)
+
+	// only masked default
+	request = &models.Img2ImgRequest{Mask: utils.String("mask")}
+	assert.Nil(t, normalizeInpaint(request))
+	assert.Equal(t, int64(defaultMaskBlur), *request.MaskBlur)
+	assert.Equal(t, int64(defaultInpaintPadding), *request.InpaintFullResPadding)
+
+	// user value kept, whole picture no padding
+	request = &models.Img2ImgRequest{Mask: utils.String("mask"), MaskBlur: utils.Int64(0),
+		InpaintFullRes: utils.Bool(false)}
+	assert.Nil(t, normalizeInpaint(request))
+	assert.Equal(t, int64(0), *request.MaskBlur)
+	assert.Nil(t, request.InpaintFullResPadding)
+	request = &models.Img2ImgRequest{Mask: utils.String("mask"), InpaintFullResPadding: utils.Int64(64)}
+	assert.Nil(t, normalizeInpaint(request))
+	assert.Equal(t, int64(64), *request.InpaintFullResPadding)
+
+	invalid := []*models.Img2ImgRequest{
+		{Mask: utils.String("mask"), MaskBlur: utils.Int64(-1)},
+		{Mask: utils.String("mask"), MaskBlurY: utils.Int64(65)},
+		{Mask: utils.String("mask"), InpaintFullResPadding: utils.Int64(257)},
+		{Mask: utils.String("mask"), InpaintingFill: utils.Int64(4)},
+		{Mask: utils.String("mask"), InpaintingMaskInvert: utils.Int64(2)},
+	}
+	for _, request := range invalid {
+		assert.NotNil(t, normalizeInpaint(request))
+	}
+	err := normalizeInpaint(&models.Img2ImgRequest{Mask: utils.String("mask"), InpaintingFill: utils.Int64(4)})
+	assert.Equal(t, "inpainting_fill 4 invalid, need 0-3", err.Error())
+
+	// mask oss path to base64, inpaint fields kept
+	initTestConfig(t)
+	oss := mockOss(t, 0)
+	oss.uploaded["inputs/init.png"] = []byte("init")
+	oss.uploaded["inputs/mask.png"] = []byte("mask")
+	request = &models.Img2ImgRequest{InitImages: &[]string{"inputs/init.png"}, Mask: utils.String("inputs/mask.png"),
+		InpaintFullRes: utils.Bool(true), InpaintingFill: utils.Int64(1)}
+	assert.Nil(t, normalizeInpaint(request))
+	assert.Nil(t, preprocessRequest(request))
+	assert.Equal(t, base64.StdEncoding.EncodeToString([]byte("mask")), *request.Mask)
+	assert.True(t, *request.InpaintFullRes)
+	assert.Equal(t, int64(defaultInpaintPadding), *request.InpaintFullResPadding)
+	assert.Equal(t, int64(defaultMaskBlur), *request.MaskBlur)
+	assert.Equal(t, int64(1), *request.InpaintingFill)
+}
+
+func TestInitImageSource(t *testing.T) {
+	initTestConfig(t)
+	oss := mockOss(t, 0)
+	oss.uploaded["inputs/init.png"] = []byte("init")
+	oss.uploaded["inputs/mask.png"] = []byte("mask")
+	newRequest := func() *models.Img2ImgRequest {
+		return &models.Img2ImgRequest{InitImages: &[]string{"inputs/init.png", "aW5pdA=="},
+			Mask: utils.String("inputs/mask.png")}
+	}
+
+	// default base64
+	request := newRequest()
+	assert.Nil(t, preprocessRequest(request))
+	assert.Equal(t, []string{"aW5pdA==", "aW5pdA=="}, *request.InitImages)
+
+	config.ConfigGlobal.InitImageSource = config.InitImageUrl
+	request = newRequest()
+	assert.Nil(t, preprocessRequest(request))
+	assert.Equal(t, []string{"http://oss/inputs/init.png", "aW5pdA=="}, *request.InitImages)
+	assert.Equal(t, "http://oss/inputs/mask.png", *request.Mask)
+
+	// url not supported, fall back to base64
+	oss.failKey = "inputs/mask.png"
+	request = newRequest()
+	assert.Nil(t, preprocessRequest(request))
+	assert.Equal(t, "http://oss/inputs/init.png", (*request.InitImages)[0])
+	assert.Equal(t, base64.StdEncoding.EncodeToString([]byte("mask")), *request.Mask)
+}
+
+func TestTaskLabels(t *testing.T) {
+	val, err := taskLabelsVal(nil)
+	assert.Nil(t, err)
+	assert.Empty(t, val)
+	val, err = taskLabelsVal(&map[string]string{"jobId": "j1", "userRef": "u1"})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"jobId": "j1", "userRef": "u1"},
+		*taskLabels(map[string]interface{}{datastore.KTaskLabels: val}))
+	assert.Nil(t, taskLabels(map[string]interface{}{}))
+
+	tooMany := make(map[string]string)
+	for i := 0; i <= maxTaskLabels; i++ {
+		tooMany[fmt.Sprintf("k%d", i)] = "v"
+	}
+	_, err = taskLabelsVal(&tooMany)
+	assert.NotNil(t, err)
+	_, err = taskLabelsVal(&map[string]string{"k": strings.Repeat("v", maxTaskLabelsSize)})
+	assert.NotNil(t, err)
+	_, err = taskLabelsVal(&map[string]string{"": "v"})
+	assert.NotNil(t, err)
+}
+
+func TestWebuiJobId(t *testing.T) {
+	header := http.Header{}
+	assert.Equal(t, "", webuiJobId("not json", header))
+	assert.Equal(t, "20231001080000", webuiJobId(`{"job_timestamp": "20231001080000"}`, header))
+	// fallback header
+	header.Set(webuiJobIdKey, "job1")
+	assert.Equal(t, "job1", webuiJobId(`{"seed": 100}`, header))
+	assert.Equal(t, "20231001080000", webuiJobId(`{"job_timestamp": "20231001080000"}`, header))
+}
+
+// fakeOss record uploaded keys, upload cost latency
+type fakeOss struct {
+	module.OssOp
+	lock     sync.Mutex
+	latency  time.Duration
+	uploaded map[string][]byte
+	failKey  string
+}
+
+func (f *fakeOss) UploadFileByByte(ossKey string, body []byte) error {
+	time.Sleep(f.latency)
+	if ossKey == f.failKey {
+		return errors.New("upload fail")
+	}
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.uploaded[ossKey] = body
+	return nil
+}
+
+func (f *fakeOss) DownloadFile(ossKey, localFile string) error {
+	time.Sleep(f.latency)
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	body, ok := f.uploaded[ossKey]
+	if !ok {
+		return errors.New("object not exist")
+	}
+	return os.WriteFile(localFile, body, 0644)
+}
+
+func (f *fakeOss) DownloadFileToBase64(ossPath string) (*string, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	body, ok := f.uploaded[ossPath]
+	if !ok {
+		return nil, errors.New("object not exist")
+	}
+	ret := base64.StdEncoding.EncodeToString(body)
+	return &ret, nil
+}
+
+func (f *fakeOss) GetUrl(ossPath []string) ([]string, error) {
+	urls := make([]string, 0, len(ossPath))
+	for _, path := range ossPath {
+		if path == f.failKey {
+			return nil, errors.New("sign url fail")
+		}
+		urls = append(urls, "http://oss/"+path)
+	}
+	return urls, nil
+}
+
+func (f *fakeOss) ModelExist(ossKey string) (bool, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	_, ok := f.uploaded[ossKey]
+	return ok, nil
+}
+
+func (f *fakeOss) DeleteFile(ossKey string) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	delete(f.uploaded, ossKey)
+	return nil
+}
+
+func (f *fakeOss) ListFiles(prefix, marker string, limit int) ([]module.OssObject, string, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	keys := make([]string, 0)
+	for key := range f.uploaded {
+		if strings.HasPrefix(key, prefix) && key > marker {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	next := ""
+	if len(keys) > limit {
+		keys = keys[:limit]
+		next = keys[limit-1]
+	}
+	objects := make([]module.OssObject, 0, len(keys))
+	for _, key := range keys {
+		objects = append(objects, module.OssObject{Key: key, Size: int64(len(f.uploaded[key]))})
+	}
+	return objects, next, nil
+}
+
+func mockOss(t testing.TB, latency time.Duration) *fakeOss {
+	old := module.OssGlobal
+	oss := &fakeOss{latency: latency, uploaded: make(map[string][]byte)}
+	module.OssGlobal = oss
+	t.Cleanup(func() {
+		module.OssGlobal = old
+	})
+	return oss
+}
+
+func testImages(count int) ([]string, []string) {
+	ossPaths := make([]string, 0, count)
+	images := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		ossPaths = append(ossPaths, fmt.Sprintf("images/default/task_%d.png", i+1))
+		images = append(images, base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("image%d", i+1))))
+	}
+	return ossPaths, images
+}
+
+func TestUploadImagesConcurrently(t *testing.T) {
+	initTestConfig(t)
+	config.ConfigGlobal.OssUploadConcurrency = 3
+	oss := mockOss(t, time.Millisecond)
+
+	ossPaths, images := testImages(8)
+	var progress []int
+	failed, err := uploadImagesConcurrently(ossPaths, images, func(uploaded int) {
+		progress = append(progress, uploaded)
+	})
+	assert.Nil(t, err)
+	assert.Empty(t, failed)
+	assert.Len(t, oss.uploaded, 8)
+	for i, ossPath := range ossPaths {
+		assert.Equal(t, fmt.Sprintf("image%d", i+1), string(oss.uploaded[ossPath]))
+		assert.Empty(t, images[i])
+	}
+	// leading uploaded count increase and end with all
+	for i := 1; i < len(progress); i++ {
+		assert.Greater(t, progress[i], progress[i-1])
+	}
+	assert.Equal(t, 8, progress[len(progress)-1])
+
+	// upload fail, other images still uploaded
+	oss.failKey = ossPaths[2]
+	oss.uploaded = map[string][]byte{}
+	ossPaths, images = testImages(8)
+	progress = nil
+	failed, err = uploadImagesConcurrently(ossPaths, images, func(uploaded int) {
+		progress = append(progress, uploaded)
+	})
+	assert.NotNil(t, err)
+	assert.Equal(t, []int{2}, failed)
+	assert.Len(t, oss.uploaded, 7)
+	assert.NotContains(t, oss.uploaded, ossPaths[2])
+	// leading uploaded count stop before failed image
+	assert.Equal(t, 2, progress[len(progress)-1])
+
+	// slow progress write not block other uploads
+	config.ConfigGlobal.OssUploadConcurrency = 1
+	oss.failKey = ""
+	oss.uploaded = map[string][]byte{}
+	ossPaths, images = testImages(3)
+	progress = nil
+	othersUploaded := false
+	failed, err = uploadImagesConcurrently(ossPaths, images, func(uploaded int) {
+		if uploaded == 1 {
+			deadline := time.Now().Add(time.Second)
+			for !othersUploaded && time.Now().Before(deadline) {
+				oss.lock.Lock()
+				othersUploaded = len(oss.uploaded) == 3
+				oss.lock.Unlock()
+				time.Sleep(time.Millisecond)
+			}
+		}
+		progress = append(progress, uploaded)
+	})
+	assert.Nil(t, err)
+	assert.Empty(t, failed)
+	// other uploads finished while first progress written
+	assert.True(t, othersUploaded)
+	assert.Equal(t, 3, progress[len(progress)-1])
+}
+
+func TestImageKeyWithFormat(t *testing.T) {
+	png := []byte("\x89PNG\r\n\x1a\n0000")
+	jpeg := []byte("\xff\xd8\xff\xe0000")
+	webp := []byte("RIFF0000WEBPVP8 ")
+	assert.Equal(t, "images/a/task_1.png", imageKeyWithFormat("images/a/task_1.png", png))
+	assert.Equal(t, "images/a/task_1.jpg", imageKeyWithFormat("images/a/task_1.png", jpeg))
+	assert.Equal(t, "images/a/task_1.jpeg", imageKeyWithFormat("images/a/task_1.jpeg", jpeg))
+	assert.Equal(t, "images/a/task_1.webp", imageKeyWithFormat("images/a/task_1.png", webp))
+	assert.Equal(t, "images/a.b/task_1.jpg", imageKeyWithFormat("images/a.b/task_1", jpeg))
+	// unknown format keep key
+	assert.Equal(t, "images/a/task_1.png", imageKeyWithFormat("images/a/task_1.png", []byte("image")))
+
+	initTestConfig(t)
+	oss := mockOss(t, 0)
+	ossPath, image := "images/a/task_1.png", base64.StdEncoding.EncodeToString(jpeg)
+	assert.Nil(t, uploadImages(&ossPath, &image))
+	assert.Equal(t, "images/a/task_1.jpg", ossPath)
+	assert.Equal(t, jpeg, oss.uploaded[ossPath])
+	// detect disabled
+	detect := false
+	config.ConfigGlobal.DetectImageType = &detect
+	ossPath = "images/a/task_2.png"
+	assert.Nil(t, uploadImages(&ossPath, &image))
+	assert.Equal(t, "images/a/task_2.png", ossPath)
+}
+
+func benchmarkUploadImages(b *testing.B, concurrency int) {
+	config.ConfigGlobal = &config.Config{
+		ConfigYaml: config.ConfigYaml{OssUploadConcurrency: concurrency},
+	}
+	mockOss(b, 5*time.Millisecond)
+	for i := 0; i < b.N; i++ {
+		ossPaths, images := testImages(8)
+		if _, err := uploadImagesConcurrently(ossPaths, images, nil); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// serial path, same as upload one by one
+func BenchmarkUploadImagesSerial(b *testing.B) {
+	benchmarkUploadImages(b, 1)
+}
+
+func BenchmarkUploadImagesConcurrent(b *testing.B) {
+	benchmarkUploadImages(b, config.DefaultOssUploadConcurrency)
+}
+
+func TestUpdateControlNet(t *testing.T) {
+	initTestConfig(t)
+	config.ConfigGlobal.SdPath = t.TempDir()
+	config.ConfigGlobal.ModelDirs = config.DefaultModelDirs
+	modelDir := config.ConfigGlobal.GetModelDir(config.CONTORLNET_MODEL)
+	assert.Nil(t, os.MkdirAll(modelDir, os.ModePerm))
+	assert.Nil(t, os.WriteFile(filepath.Join(modelDir, "control_canny.pth"), []byte("model"), 0644))
+	oss := mockOss(t, 0)
+	oss.uploaded["images/canny.png"] = []byte("canny")
+	oss.uploaded["images/depth.png"] = []byte("depth")
+
+	newScripts := func(secondImage, secondModel string) *map[string]interface{} {
+		return &map[string]interface{}{
+			"ControlNet": map[string]interface{}{
+				"args": []interface{}{
+					map[string]interface{}{"input_image": "images/canny.png", "model": "control_canny [d14c016b]"},
+					map[string]interface{}{"image": map[string]interface{}{"image": secondImage},
+						"model": secondModel},
+				},
+			},
+		}
+	}
+	scripts := newScripts("images/depth.png", "None")
+	assert.Nil(t, updateControlNet(scripts))
+	units := (*scripts)["ControlNet"].(map[string]interface{})["args"].([]interface{})
+	assert.Equal(t, base64.StdEncoding.EncodeToString([]byte("canny")),
+		units[0].(map[string]interface{})["input_image"])
+	assert.Equal(t, base64.StdEncoding.EncodeToString([]byte("depth")),
+		units[1].(map[string]interface{})["image"].(map[string]interface{})["image"])
+
+	// unit image not exist
+	err := updateControlNet(newScripts("images/missing.png", "None"))
+	assert.ErrorContains(t, err, "controlnet unit 1")
+
+	// unit model not exist
+	err = updateControlNet(newScripts("images/depth.png", "control_depth [abcd]"))
+	assert.ErrorContains(t, err, "controlnet unit 1: model control_depth [abcd] not found")
+}
+
+func TestDownloadFilename(t *testing.T) {
+	assert.Equal(t, "task_1.png", downloadFilename("task", 1, ".png"))
+	assert.Equal(t, "task.zip", downloadFilename("task", 0, ".zip"))
+	assert.Equal(t, "download_2.png", downloadFilename("", 2, ".png"))
+	// no path, quote or header injection
+	assert.Equal(t, "passwd", safeFilename("../../etc/passwd"))
+	assert.Equal(t, "evil.png", safeFilename(`C:\tmp\evil.png`))
+	assert.Equal(t, "a_b_c_.png", safeFilename("a\"b;c\r.png"))
+	assert.Equal(t, "download", safeFilename(".."))
+	long := safeFilename(strings.Repeat("a", 200) + ".png")
+	assert.Equal(t, maxFilenameLength, len(long))
+	assert.True(t, strings.HasSuffix(long, ".png"))
+
+	disposition := func(target string) string {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, target, nil)
+		setContentDisposition(c, "task_1.png")
+		return w.Header().Get("Content-Disposition")
+	}
+	// shown inline unless download requested
+	assert.Equal(t, `inline; filename=task_1.png`, disposition("/file=outputs/a.png"))
+	assert.Equal(t, `inline; filename=task_1.png`, disposition("/file=outputs/a.png?download=false"))
+	assert.Equal(t, `attachment; filename=task_1.png`, disposition("/file=outputs/a.png?download=true"))
+}
+
+func TestPassthroughFilename(t *testing.T) {
+	header := func(contentType, disposition string) http.Header {
+		h := http.Header{}
+		h.Set("Content-Type", contentType)
+		if disposition != "" {
+			h.Set("Content-Disposition", disposition)
+		}
+		return h
+	}
+	assert.Equal(t, "task.png", passthroughFilename("task", "/file=outputs/a.png", header("image/png", "")))
+	assert.Equal(t, "a.png", passthroughFilename("", "/file=outputs/a.png", header("image/png", "")))
+	assert.Equal(t, "grid.jpg", passthroughFilename("", "/file=outputs/grid", header("image/jpeg", "")))
+	assert.Equal(t, "out.zip", passthroughFilename("task", "/zip",
+		header("application/zip", `attachment; filename="../out.zip"`)))
+	// not download
+	assert.Equal(t, "", passthroughFilename("task", "/sdapi/v1/options", header("application/json", "")))
+	assert.Equal(t, "", passthroughFilename("task", "/sdapi/v1/options", header("", "")))
+}
+
+func TestImageDataUris(t *testing.T) {
+	png := base64.StdEncoding.EncodeToString([]byte("\x89PNG\r\n\x1a\n0000"))
+	jpg := base64.StdEncoding.EncodeToString([]byte("\xff\xd8\xff\xe0000"))
+	assert.Equal(t, []string{"data:image/png;base64," + png, "data:image/jpeg;base64," + jpg},
+		imageDataUris([]string{png, jpg}, int64(len(png)+len(jpg))))
+	// exceed max size or disabled
+	assert.Nil(t, imageDataUris([]string{png, jpg}, int64(len(png))))
+	assert.Nil(t, imageDataUris([]string{png}, 0))
+}
+
+func TestRejectInlineImages(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	initTestConfig(t)
+	p := &ProxyHandler{}
+	for path, handle := range map[string]gin.HandlerFunc{"/img2img": p.Img2Img, "/extra_images": p.ExtraImages,
+		"/txt2img/multi": p.Txt2ImgMulti} {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, path, strings.NewReader("{}"))
+		c.Request.Header.Set(inlineImagesKey, "true")
+		handle(c)
+		assert.Equal(t, http.StatusBadRequest, w.Code, path)
+		assert.Contains(t, w.Body.String(), inlineImagesKey, path)
+	}
+}
+
+func TestBodyModelField(t *testing.T) {
+	body := make(map[string]interface{})
+	assert.Nil(t, json.Unmarshal([]byte(`{"sd_model":"sd_xl","override_settings":{
+		"sd_model_checkpoint":"sd_xl.safetensors [31e35c80fc]"},"args":[{"model":"face"},"x"]}`), &body))
+	assert.Equal(t, "sd_xl", bodyModelField(body, "sd_model"))
+	assert.Equal(t, "sd_xl.safetensors", bodyModelField(body, "override_settings.sd_model_checkpoint"))
+	assert.Equal(t, "face", bodyModelField(body, "args.0.model"))
+	for _, field := range []string{"unknown", "args.2.model", "args.x", "args.1.model", "override_settings"} {
+		assert.Empty(t, bodyModelField(body, field), field)
+	}
+}
+
+func TestNoRouterModelField(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	initTestConfig(t)
+	config.ConfigGlobal.DefaultModel = "default"
+	config.ConfigGlobal.RouteModelFields = map[string]string{
+		"/reactor/image": "sd_model",
+		"/adetailer/*":   "override_settings.sd_model_checkpoint",
+	}
+	p := &ProxyHandler{}
+	cases := []struct {
+		path  string
+		body  string
+		model string
+	}{
+		{"/reactor/image", `{"sd_model":"sd_xl","source_image":"img"}`, "sd_xl"},
+		{"/adetailer/v1/detect", `{"override_settings":{"sd_model_checkpoint":"sd15 [abc]"}}`, "sd15"},
+		// mapped field missing, standard field still work
+		{"/reactor/image", `{"StableDiffusionModel":"sd"}`, "sd"},
+		// unmapped route
+		{"/other/api", `{"sd_model":"sd_xl"}`, "default"},
+	}
+	for _, tc := range cases {
+		manager := &fakeEndpointManager{}
+		mockEndpointManager(t, manager)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, tc.path, bytes.NewBufferString(tc.body))
+		p.NoRouterHandler(c)
+		assert.Equal(t, tc.model, w.Header().Get("model"), tc.path)
+		assert.Equal(t, []string{tc.model}, manager.coldStarts, tc.path)
+	}
+}
+
+func TestBodyLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(BodyLimit(16))
+	read := func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		c.String(http.StatusOK, "%d", len(body))
+	}
+	router.POST("/txt2img", read)
+	router.POST("/models", read)
+	post := func(path, contentType, body string) int {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", contentType)
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+	large := strings.Repeat("a", 32)
+	assert.Equal(t, http.StatusOK, post("/txt2img", "application/json", "{}"))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, post("/txt2img", "application/json", large))
+	// multipart of other route limited too
+	assert.Equal(t, http.StatusRequestEntityTooLarge, post("/txt2img", "multipart/form-data; boundary=x", large))
+	// model upload not limited
+	assert.Equal(t, http.StatusOK, post("/models", "multipart/form-data; boundary=x", large))
+}
+
+func TestLoadModel(t *testing.T) {
+	initTestConfig(t)
+	modelStore := datastore.NewSQLiteDatastore(datastore.NewSQLiteConfig(datastore.KModelTableName))
+	defer modelStore.Close()
+	p := &ProxyHandler{modelStore: modelStore}
+	var envErr error
+	oldUpdate := updateFunctionEnv
+	updateFunctionEnv = func(key string) error {
+		// env updated before row marked loaded
+		data, err := modelStore.Get(key, []string{datastore.KModelStatus})
+		assert.Nil(t, err)
+		assert.Equal(t, config.MODEL_LOADING, data[datastore.KModelStatus])
+		return envErr
+	}
+	t.Cleanup(func() { updateFunctionEnv = oldUpdate })
+	status := func() interface{} {
+		data, err := modelStore.Get("sd.safetensors", []string{datastore.KModelStatus})
+		assert.Nil(t, err)
+		return data[datastore.KModelStatus]
+	}
+	putLoading := func() {
+		assert.Nil(t, modelStore.Put("sd.safetensors", map[string]interface{}{
+			datastore.KModelName:   "sd.safetensors",
+			datastore.KModelStatus: config.MODEL_LOADING,
+		}))
+	}
+
+	putLoading()
+	assert.Nil(t, p.loadModel("sd.safetensors", config.SD_MODEL, config.MODEL_UNLOADED))
+	assert.Equal(t, config.MODEL_LOADED, status())
+
+	// env update fail, not stuck in loading
+	putLoading()
+	envErr = errors.New("fc unavailable")
+	assert.NotNil(t, p.loadModel("sd.safetensors", config.SD_MODEL, config.MODEL_UNLOADED))
+	assert.Equal(t, config.MODEL_UNLOADED, status())
+
+	// no function reloaded model, not marked loaded
+	putLoading()
+	envErr = module.ErrFunctionNotExist
+	assert.ErrorIs(t, p.loadModel("sd.safetensors", config.SD_MODEL, config.MODEL_UNLOADED), module.ErrFunctionNotExist)
+	assert.Equal(t, config.MODEL_UNLOADED, status())
+}
+
+func TestUpdatePromptTemplatesConcurrent(t *testing.T) {
+	initTestConfig(t)
+	configStore := datastore.NewSQLiteDatastore(datastore.NewSQLiteConfig(datastore.KConfigTableName))
+	defer configStore.Close()
+	p := &ProxyHandler{configStore: configStore}
+	const workers = 8
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			assert.Nil(t, p.updatePromptTemplates("u1", func(templates map[string]string) error {
+				templates[fmt.Sprintf("t%d", i)] = "cat"
+				return nil
+			}))
+		}(i)
+	}
+	wg.Wait()
+	templates, err := p.getPromptTemplates("u1")
+	assert.Nil(t, err)
+	// no update lost
+	assert.Len(t, templates, workers)
+
+	err = p.updatePromptTemplates("u1", func(templates map[string]string) error {
+		return errPromptTemplateNotFound
+	})
+	assert.ErrorIs(t, err, errPromptTemplateNotFound)
+}
+
+func TestPromptTemplatesLogin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	initTestConfig(t)
+	configStore := datastore.NewSQLiteDatastore(datastore.NewSQLiteConfig(datastore.KConfigTableName))
+	defer configStore.Close()
+	router := gin.New()
+	RegisterHandlers(router, &ProxyHandler{configStore: configStore})
+	request := func(method, path, user string) int {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, strings.NewReader(`{"content":"cat"}`))
+		req.Header.Set("Content-Type", "application/json")
+		if user != "" {
+			req.Header.Set(userKey, user)
+		}
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// login disabled, default user
+	assert.Equal(t, http.StatusOK, request(http.MethodPut, "/prompt_templates/t1", ""))
+	assert.Equal(t, http.StatusOK, request(http.MethodGet, "/prompt_templates/t1", ""))
+	// login enabled, user required and default user templates not shared
+	config.ConfigGlobal.LoginSwitch = "on"
+	for _, method := range []string{http.MethodGet, http.MethodPut, http.MethodDelete} {
+		assert.Equal(t, http.StatusUnauthorized, request(method, "/prompt_templates/t1", ""))
+	}
+	assert.Equal(t, http.StatusUnauthorized, request(http.MethodGet, "/prompt_templates", ""))
+	assert.Equal(t, http.StatusNotFound, request(http.MethodGet, "/prompt_templates/t1", "u1"))
+}
+
+func TestUpdateFuncResourceTimeout(t *testing.T) {
+	initTestConfig(t)
+	config.ConfigGlobal.ModelTimeouts = map[string]int32{"sdxl": 1200}
+	update := func(key string, timeout *int64) int32 {
+		res, err := updateFuncResource(&models.BatchUpdateSdResourceRequest{Timeout: timeout}, key,
+			&module.FuncResource{Timeout: 600})
+		assert.Nil(t, err)
+		if res == nil {
+			return 600
+		}
+		return res.Timeout
+	}
+	// model timeout kept when request not set timeout
+	assert.Equal(t, int32(1200), update("sdxl", nil))
+	// request timeout win
+	assert.Equal(t, int32(900), update("sdxl", utils.Int64(900)))
+	assert.Equal(t, int32(900), update("sd15", utils.Int64(900)))
+	assert.Equal(t, int32(600), update("sd15", nil))
+}
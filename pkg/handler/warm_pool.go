@@ -0,0 +1,175 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/models"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// tiny txt2img keep instance busy briefly, same as sd predictProbe
+var warmProbeBody, _ = json.Marshal(map[string]interface{}{
+	"prompt": "",
+	"steps":  1,
+	"height": 8,
+	"width":  8,
+})
+
+type warmItem struct {
+	warm      int
+	probeTime int64
+}
+
+// warmPool keep min warm instances of hot models alive
+// every round send minWarm concurrent probes to model endpoint, instanceConcurrency=1 make fc
+// scale out to minWarm instances, cold start function if not exist
+type warmPool struct {
+	httpClient *http.Client
+	lock       sync.RWMutex
+	items      map[string]*warmItem
+	stop       chan struct{}
+	once       sync.Once
+}
+
+func newWarmPool(httpClient *http.Client) *warmPool {
+	return &warmPool{
+		httpClient: httpClient,
+		items:      make(map[string]*warmItem),
+		stop:       make(chan struct{}),
+	}
+}
+
+// run reconcile every interval until close, do nothing when warmPool not config
+func (w *warmPool) run(interval time.Duration) {
+	if len(config.ConfigGlobal.WarmPool) == 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		w.reconcile()
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *warmPool) close() {
+	w.once.Do(func() {
+		close(w.stop)
+	})
+}
+
+// reconcile probe all models concurrently
+func (w *warmPool) reconcile() {
+	var wg sync.WaitGroup
+	for sdModel, minWarm := range config.ConfigGlobal.WarmPool {
+		if minWarm <= 0 {
+			continue
+		}
+		wg.Add(1)
+		go func(sdModel string, minWarm int) {
+			defer wg.Done()
+			w.warmModel(sdModel, minWarm)
+		}(sdModel, minWarm)
+	}
+	wg.Wait()
+}
+
+// warmModel return count of probes succeeded
+func (w *warmPool) warmModel(sdModel string, minWarm int) int {
+	warm := 0
+	defer func() {
+		w.lock.Lock()
+		w.items[sdModel] = &warmItem{warm: warm, probeTime: utils.TimestampS()}
+		w.lock.Unlock()
+	}()
+	endpoint, err := getSdEndpoint(sdModel, false)
+	if err != nil {
+		logrus.Warnf("warm pool sd %s get endpoint err=%s", sdModel, err.Error())
+		return warm
+	}
+	var (
+		lock sync.Mutex
+		wg   sync.WaitGroup
+	)
+	for i := 0; i < minWarm; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := w.probe(endpoint); err != nil {
+				logrus.Warnf("warm pool sd %s probe err=%s", sdModel, err.Error())
+				return
+			}
+			lock.Lock()
+			warm++
+			lock.Unlock()
+		}()
+	}
+	wg.Wait()
+	if warm < minWarm {
+		logrus.Warnf("warm pool sd %s warm %d below min %d", sdModel, warm, minWarm)
+	}
+	return warm
+}
+
+func (w *warmPool) probe(endpoint string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), config.HTTPTIMEOUT)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s%s", endpoint, config.TXT2IMG),
+		bytes.NewBuffer(warmProbeBody))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := w.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode != requestOk {
+		return fmt.Errorf("status code=%d", resp.StatusCode)
+	}
+	return nil
+}
+
+// status warm count of config models, sort by model
+func (w *warmPool) status() []models.WarmModel {
+	w.lock.RLock()
+	defer w.lock.RUnlock()
+	ret := make([]models.WarmModel, 0, len(config.ConfigGlobal.WarmPool))
+	for sdModel, minWarm := range config.ConfigGlobal.WarmPool {
+		item := models.WarmModel{Model: sdModel, MinWarm: minWarm}
+		if warm, ok := w.items[sdModel]; ok {
+			item.Warm = warm.warm
+			item.ProbeTime = utils.Int64(warm.probeTime)
+		}
+		ret = append(ret, item)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Model < ret[j].Model
+	})
+	return ret
+}
+
+// StartWarmPool keep warmPool models warm in background, control only
+func (p *ProxyHandler) StartWarmPool() {
+	go p.warmPool.run(time.Duration(config.ConfigGlobal.WarmPoolInterval) * time.Second)
+}
+
+// StopWarmPool stop background warm pool
+func (p *ProxyHandler) StopWarmPool() {
+	p.warmPool.close()
+}
@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWarmPool(t *testing.T) {
+	initTestConfig(t)
+	var probes int32
+	sd := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, config.TXT2IMG, r.URL.Path)
+		atomic.AddInt32(&probes, 1)
+		w.Write([]byte(`{"images":[]}`))
+	}))
+	defer sd.Close()
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer down.Close()
+	manager := &fakeEndpointManager{endpoints: map[string]string{"hot": sd.URL, "down": down.URL}}
+	mockEndpointManager(t, manager)
+	config.ConfigGlobal.WarmPool = map[string]int{"hot": 3, "down": 1, "off": 0}
+
+	w := newWarmPool(&http.Client{})
+	// not probed yet
+	status := w.status()
+	assert.Equal(t, []models.WarmModel{{Model: "down", MinWarm: 1}, {Model: "hot", MinWarm: 3},
+		{Model: "off", MinWarm: 0}}, status)
+
+	w.reconcile()
+	assert.Equal(t, int32(3), atomic.LoadInt32(&probes))
+	// min 0 not cold start
+	assert.ElementsMatch(t, []string{"hot", "down"}, manager.coldStarts)
+	status = w.status()
+	assert.Equal(t, "down", status[0].Model)
+	assert.Equal(t, 0, status[0].Warm)
+	assert.NotNil(t, status[0].ProbeTime)
+	assert.Equal(t, "hot", status[1].Model)
+	assert.Equal(t, 3, status[1].Warm)
+	assert.Nil(t, status[2].ProbeTime)
+
+	// run return at once without config
+	config.ConfigGlobal.WarmPool = nil
+	w.run(0)
+	w.close()
+	w.close()
+}
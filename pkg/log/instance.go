@@ -1,7 +1,6 @@
 package log
 
 import (
-	"encoding/json"
 	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
 	"github.com/sirupsen/logrus"
 	"os"
@@ -11,10 +10,8 @@ import (
 
 // send log && trace
 const (
-	defaultCacheCount = 64
-	defaultCacheSize  = 16 * 1024 // 16KB
-	logPath           = "collect/log"
-	tracePath         = "collect/tracker"
+	logPath   = "collect/log"
+	tracePath = "collect/tracker"
 )
 
 var SDLogInstance = NewSDLog()
@@ -46,8 +43,8 @@ type Tracker struct {
 type SDLog struct {
 	taskId       string
 	requestId    sync.Map
-	cacheLog     []*Log
-	cacheTrace   []*Tracker
+	logShipper   *shipper
+	traceShipper *shipper
 	LogFlow      chan string
 	TraceFlow    chan []string
 	closeLog     chan struct{}
@@ -60,8 +57,8 @@ func NewSDLog() *SDLog {
 	sdLogInstance := &SDLog{
 		LogFlow:      make(chan string, 8192),
 		TraceFlow:    make(chan []string, 8192),
-		cacheLog:     make([]*Log, 0, defaultCacheCount),
-		cacheTrace:   make([]*Tracker, 0, defaultCacheCount),
+		logShipper:   newShipper(logPath),
+		traceShipper: newShipper(tracePath),
 		closeLog:     make(chan struct{}),
 		closeTrace:   make(chan struct{}),
 		accountId:    os.Getenv(config.FC_ACCOUNT_ID),
@@ -87,7 +84,6 @@ func (s *SDLog) getRequestId() string {
 }
 
 func (s *SDLog) consumeLog() {
-	cacheSize := 0
 	for {
 		select {
 		case logStr := <-s.LogFlow:
@@ -107,15 +103,7 @@ func (s *SDLog) consumeLog() {
 						Source:    config.ConfigGlobal.ServerName,
 						Level:     "info",
 					}
-					if cacheSize >= defaultCacheSize || len(s.cacheLog) >= defaultCacheCount {
-						if body, err := json.Marshal(s.cacheLog); err == nil {
-							go monitor.Post(body, logPath)
-						}
-						s.cacheLog = s.cacheLog[:0]
-						cacheSize = 0
-					}
-					s.cacheLog = append(s.cacheLog, logObj)
-					cacheSize += logObj.Size()
+					s.logShipper.enqueue(logObj)
 				}
 			} else {
 				logrus.Info(logStr)
@@ -138,14 +126,7 @@ func (s *SDLog) consumeTrace() {
 					Payload:   traceSlice[1],
 					Source:    config.ConfigGlobal.ServerName,
 				}
-				if len(s.cacheTrace) >= defaultCacheCount {
-					if body, err := json.Marshal(s.cacheTrace); err == nil {
-						go monitor.Post(body, tracePath)
-					}
-					s.cacheTrace = s.cacheTrace[:0]
-				}
-				s.cacheTrace = append(s.cacheTrace, traceObj)
-
+				s.traceShipper.enqueue(traceObj)
 			}
 		case <-s.closeTrace:
 			return
@@ -168,16 +149,12 @@ func (s *SDLog) DelRequestId(requestId string) {
 func (s *SDLog) Close() {
 	s.closeLog <- struct{}{}
 	s.closeTrace <- struct{}{}
-	// send trace
-	if len(s.cacheTrace) > 0 {
-		if body, err := json.Marshal(s.cacheTrace); err == nil {
-			monitor.Post(body, tracePath)
-		}
-	}
-	// send log
-	if len(s.cacheLog) > 0 {
-		if body, err := json.Marshal(s.cacheLog); err == nil {
-			monitor.Post(body, logPath)
-		}
-	}
+	// flush remain trace and log
+	s.traceShipper.close()
+	s.logShipper.close()
+}
+
+// DroppedLogs count of logs dropped because remote service unavailable
+func (s *SDLog) DroppedLogs() uint64 {
+	return s.logShipper.droppedCount() + s.traceShipper.droppedCount()
 }
@@ -31,6 +31,13 @@ func (m *Monitor) Post(body []byte, path string) error {
 		return err
 	}
 
-	_, err = m.client.Do(req)
-	return err
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("post %s fail, status code=%d", path, resp.StatusCode)
+	}
+	return nil
 }
@@ -0,0 +1,174 @@
+package log
+
+import (
+	"encoding/json"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"github.com/sirupsen/logrus"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	shipMaxRetry     = 3
+	shipRetryBackoff = 200 * time.Millisecond
+)
+
+// shipper batch send items to remote service
+// queue is bounded, drop oldest when overflow
+type shipper struct {
+	path      string
+	batchSize int
+	queueSize int
+	interval  time.Duration
+	post      func(body []byte, path string) error
+
+	once    sync.Once
+	lock    sync.Mutex
+	queue   []any
+	dropped uint64
+	notify  chan struct{}
+	closed  chan struct{}
+	done    chan struct{}
+}
+
+func newShipper(path string) *shipper {
+	return &shipper{
+		path:   path,
+		post:   monitor.Post,
+		notify: make(chan struct{}, 1),
+		closed: make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+}
+
+// start lazily, config not init when SDLogInstance create
+func (s *shipper) start() {
+	if s.batchSize <= 0 {
+		s.batchSize = config.ConfigGlobal.LogBatchSize
+	}
+	if s.queueSize <= 0 {
+		s.queueSize = config.ConfigGlobal.LogQueueSize
+	}
+	if s.interval <= 0 {
+		s.interval = time.Duration(config.ConfigGlobal.LogFlushInterval) * time.Second
+	}
+	go s.run()
+}
+
+// enqueue not block caller
+func (s *shipper) enqueue(item any) {
+	s.once.Do(s.start)
+	s.lock.Lock()
+	if len(s.queue) >= s.queueSize {
+		s.queue = s.queue[1:]
+		atomic.AddUint64(&s.dropped, 1)
+	}
+	s.queue = append(s.queue, item)
+	full := len(s.queue) >= s.batchSize
+	s.lock.Unlock()
+	if full {
+		select {
+		case s.notify <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// droppedCount count of items dropped because of queue overflow
+func (s *shipper) droppedCount() uint64 {
+	return atomic.LoadUint64(&s.dropped)
+}
+
+func (s *shipper) run() {
+	defer close(s.done)
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	var lastDropped uint64
+	for {
+		select {
+		case <-s.notify:
+			s.flush(false)
+		case <-ticker.C:
+			s.flush(true)
+			if dropped := s.droppedCount(); dropped > lastDropped {
+				logrus.Warnf("[shipper] %s queue overflow, dropped %d in total", s.path, dropped)
+				lastDropped = dropped
+			}
+		case <-s.closed:
+			s.flush(true)
+			return
+		}
+	}
+}
+
+// flush send full batches, partial batch too when all is true
+// stop when send fail, remain items wait next flush
+func (s *shipper) flush(all bool) {
+	for {
+		batch := s.take(all)
+		if len(batch) == 0 {
+			return
+		}
+		if err := s.send(batch); err != nil {
+			logrus.Warnf("[shipper] send %d items to %s fail, err=%s", len(batch), s.path, err.Error())
+			s.requeue(batch)
+			return
+		}
+	}
+}
+
+func (s *shipper) take(all bool) []any {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if len(s.queue) == 0 || (!all && len(s.queue) < s.batchSize) {
+		return nil
+	}
+	size := len(s.queue)
+	if size > s.batchSize {
+		size = s.batchSize
+	}
+	batch := make([]any, size)
+	copy(batch, s.queue[:size])
+	s.queue = s.queue[size:]
+	return batch
+}
+
+// put failed batch back to queue head, drop oldest when overflow
+func (s *shipper) requeue(batch []any) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.queue = append(batch, s.queue...)
+	if over := len(s.queue) - s.queueSize; over > 0 {
+		s.queue = s.queue[over:]
+		atomic.AddUint64(&s.dropped, uint64(over))
+	}
+}
+
+func (s *shipper) send(batch []any) error {
+	body, err := json.Marshal(batch)
+	if err != nil {
+		return err
+	}
+	backoff := shipRetryBackoff
+	for retry := 0; ; retry++ {
+		if err = s.post(body, s.path); err == nil || retry >= shipMaxRetry {
+			return err
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// close flush remain items and stop, no-op if not started
+func (s *shipper) close() {
+	started := true
+	s.once.Do(func() {
+		started = false
+	})
+	if !started {
+		return
+	}
+	close(s.closed)
+	<-s.done
+}
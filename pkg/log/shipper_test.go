@@ -0,0 +1,77 @@
+package log
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeRemote struct {
+	lock    sync.Mutex
+	fail    bool
+	batches [][]int
+}
+
+func (f *fakeRemote) post(body []byte, path string) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if f.fail {
+		return errors.New("remote unavailable")
+	}
+	var batch []int
+	if err := json.Unmarshal(body, &batch); err != nil {
+		return err
+	}
+	f.batches = append(f.batches, batch)
+	return nil
+}
+
+func (f *fakeRemote) setFail(fail bool) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.fail = fail
+}
+
+func newTestShipper(remote *fakeRemote) *shipper {
+	s := newShipper("test")
+	s.batchSize = 2
+	s.queueSize = 4
+	s.interval = time.Hour
+	s.post = remote.post
+	return s
+}
+
+func TestShipperBatch(t *testing.T) {
+	remote := &fakeRemote{}
+	s := newTestShipper(remote)
+	for i := 1; i <= 3; i++ {
+		s.enqueue(i)
+	}
+	// close flush partial batch
+	s.close()
+	assert.Equal(t, [][]int{{1, 2}, {3}}, remote.batches)
+	assert.Equal(t, uint64(0), s.droppedCount())
+}
+
+func TestShipperDropOldest(t *testing.T) {
+	remote := &fakeRemote{fail: true}
+	s := newTestShipper(remote)
+	s.once.Do(func() {})
+	for i := 1; i <= 6; i++ {
+		s.enqueue(i)
+	}
+	assert.Equal(t, uint64(2), s.droppedCount())
+
+	// send fail, batch back to queue
+	s.flush(true)
+	assert.Equal(t, []any{3, 4, 5, 6}, s.queue)
+
+	remote.setFail(false)
+	s.flush(true)
+	assert.Equal(t, [][]int{{3, 4}, {5, 6}}, remote.batches)
+	assert.Empty(t, s.queue)
+}
@@ -3,6 +3,24 @@
 // Code generated by github.com/deepmap/oapi-codegen/v2 version v2.1.0 DO NOT EDIT.
 package models
 
+// BatchDeleteModelsRequest defines model for BatchDeleteModelsRequest.
+type BatchDeleteModelsRequest struct {
+	// DryRun only report outcome, nothing deleted
+	DryRun *bool `json:"dryRun,omitempty"`
+
+	// Names model names, max 100, at least one of names and type required
+	Names *[]string `json:"names,omitempty"`
+
+	// Type only delete models of this type, all registered models of type when names not set
+	Type *string `json:"type,omitempty"`
+}
+
+// BatchDeleteModelsResult defines model for BatchDeleteModelsResult.
+type BatchDeleteModelsResult struct {
+	DryRun  bool                `json:"dryRun"`
+	Results []ModelDeleteResult `json:"results"`
+}
+
 // BatchUpdateSdResourceRequest defines model for BatchUpdateSdResourceRequest.
 type BatchUpdateSdResourceRequest struct {
 	// Cpu sd function cpu
@@ -54,6 +72,27 @@ type BatchUpdateSdResourceResponse struct {
 	Status *string `json:"status,omitempty"`
 }
 
+// CircuitBreaker circuit breaker of downstream sd endpoint
+type CircuitBreaker struct {
+	Endpoint string `json:"endpoint"`
+
+	// Failures consecutive failures
+	Failures int `json:"failures"`
+
+	// OpenUntil unix time(s) fast fail until, only when open
+	OpenUntil *int64 `json:"openUntil,omitempty"`
+
+	// State closed|open|halfOpen
+	State string `json:"state"`
+}
+
+// ColdStartBudget function creations budget, capacity 0 means no limit
+type ColdStartBudget struct {
+	Available     int `json:"available"`
+	Capacity      int `json:"capacity"`
+	WindowSeconds int `json:"windowSeconds"`
+}
+
 // DelSDFunctionRequest defines model for DelSDFunctionRequest.
 type DelSDFunctionRequest struct {
 	// Functions del functions
@@ -69,6 +108,38 @@ type DelSDFunctionResponse struct {
 	Status *string `json:"status,omitempty"`
 }
 
+// DeleteUserImagesRequest defines model for DeleteUserImagesRequest.
+type DeleteUserImagesRequest struct {
+	// Images oss keys of images, must under user image prefix
+	Images []string `json:"images"`
+}
+
+// DeleteUserImagesResult defines model for DeleteUserImagesResult.
+type DeleteUserImagesResult struct {
+	// Deleted oss keys deleted
+	Deleted []string `json:"deleted"`
+}
+
+// DistributeModelResult per function env refresh result
+type DistributeModelResult struct {
+	Model   string           `json:"model"`
+	Results []FunctionResult `json:"results"`
+}
+
+// EnvInfo effective sd env of instance, only sd related variables, auth values in extra args masked
+type EnvInfo struct {
+	// Env sd related process env set on instance
+	Env map[string]string `json:"env"`
+
+	// FlexMode singleFunc|multiFunc
+	FlexMode string `json:"flexMode"`
+	SdPath   string `json:"sdPath"`
+	SdPort   string `json:"sdPort"`
+
+	// ServerName proxy|control|agent
+	ServerName string `json:"serverName"`
+}
+
 // Error defines model for Error.
 type Error struct {
 	// Code Error code
@@ -78,6 +149,36 @@ type Error struct {
 	Message string `json:"message"`
 }
 
+// ExtraBatchImage defines model for ExtraBatchImage.
+type ExtraBatchImage struct {
+	Data string  `json:"data"`
+	Name *string `json:"name,omitempty"`
+}
+
+// ExtraBatchImagesRequest defines model for ExtraBatchImagesRequest.
+type ExtraBatchImagesRequest struct {
+	CodeformerVisibility      *float32          `json:"codeformer_visibility,omitempty"`
+	CodeformerWeight          *float32          `json:"codeformer_weight,omitempty"`
+	ExtrasUpscaler2Visibility *float32          `json:"extras_upscaler_2_visibility,omitempty"`
+	ForceTaskId               *string           `json:"force_task_id,omitempty"`
+	GfpganVisibility          *float32          `json:"gfpgan_visibility,omitempty"`
+	ImageList                 []ExtraBatchImage `json:"image_list"`
+	// OutputPrefix oss key prefix of output images, need allowed by config outputPrefixes
+	// Labels client metadata echo in task result and progress, max 16 labels and 4KB
+	Labels               *map[string]string `json:"labels,omitempty"`
+	OutputPrefix         *string            `json:"output_prefix,omitempty"`
+	ResizeMode           int64              `json:"resize_mode"`
+	ShowExtrasResults    *bool              `json:"show_extras_results,omitempty"`
+	StableDiffusionModel *string            `json:"stable_diffusion_model,omitempty"`
+	UpscaleFirst         *bool              `json:"upscale_first,omitempty"`
+	Upscaler1            *string            `json:"upscaler_1,omitempty"`
+	Upscaler2            *string            `json:"upscaler_2,omitempty"`
+	UpscalingCrop        *bool              `json:"upscaling_crop,omitempty"`
+	UpscalingResize      *float32           `json:"upscaling_resize,omitempty"`
+	UpscalingResizeH     *int64             `json:"upscaling_resize_h,omitempty"`
+	UpscalingResizeW     *int64             `json:"upscaling_resize_w,omitempty"`
+}
+
 // ExtraImagesRequest defines model for ExtraImagesRequest.
 type ExtraImagesRequest struct {
 	ForceTaskId               *string  `json:"force_task_id,omitempty"`
@@ -86,37 +187,65 @@ type ExtraImagesRequest struct {
 	ExtrasUpscaler2Visibility *float32 `json:"extras_upscaler_2_visibility,omitempty"`
 	GfpganVisibility          *float32 `json:"gfpgan_visibility,omitempty"`
 	Image                     string   `json:"image"`
-	ResizeMode                int64    `json:"resize_mode"`
-	ShowExtrasResults         *bool    `json:"show_extras_results,omitempty"`
-	StableDiffusionModel      *string  `json:"stable_diffusion_model,omitempty"`
-	UpscaleFirst              *bool    `json:"upscale_first,omitempty"`
-	Upscaler1                 *string  `json:"upscaler_1,omitempty"`
-	Upscaler2                 *string  `json:"upscaler_2,omitempty"`
-	UpscalingCrop             *bool    `json:"upscaling_crop,omitempty"`
-	UpscalingResize           *float32 `json:"upscaling_resize,omitempty"`
-	UpscalingResizeH          *int64   `json:"upscaling_resize_h,omitempty"`
-	UpscalingResizeW          *int64   `json:"upscaling_resize_w,omitempty"`
+	// Labels client metadata echo in task result and progress, max 16 labels and 4KB
+	Labels               *map[string]string `json:"labels,omitempty"`
+	ResizeMode           int64              `json:"resize_mode"`
+	ShowExtrasResults    *bool              `json:"show_extras_results,omitempty"`
+	StableDiffusionModel *string            `json:"stable_diffusion_model,omitempty"`
+	UpscaleFirst         *bool              `json:"upscale_first,omitempty"`
+	Upscaler1            *string            `json:"upscaler_1,omitempty"`
+	Upscaler2            *string            `json:"upscaler_2,omitempty"`
+	UpscalingCrop        *bool              `json:"upscaling_crop,omitempty"`
+	UpscalingResize      *float32           `json:"upscaling_resize,omitempty"`
+	UpscalingResizeH     *int64             `json:"upscaling_resize_h,omitempty"`
+	UpscalingResizeW     *int64             `json:"upscaling_resize_w,omitempty"`
+}
+
+// FunctionResult defines model for FunctionResult.
+type FunctionResult struct {
+	FunctionName string `json:"functionName"`
+
+	// Message error message when fail
+	Message *string `json:"message,omitempty"`
+
+	// SdModel sd model served by function
+	SdModel string `json:"sdModel"`
+	Success bool   `json:"success"`
+}
+
+// ImageCompression png compression before upload since start, only when imageCompression enabled
+type ImageCompression struct {
+	CompressedBytes int64 `json:"compressedBytes"`
+
+	// CpuMillis total time spent on compression
+	CpuMillis     int64 `json:"cpuMillis"`
+	Images        int   `json:"images"`
+	OriginalBytes int64 `json:"originalBytes"`
 }
 
 // Img2ImgRequest defines model for Img2ImgRequest.
 type Img2ImgRequest struct {
-	ForceTaskId                       *string                 `json:"force_task_id,omitempty"`
-	AlwaysonScripts                   *map[string]interface{} `json:"alwayson_scripts,omitempty"`
-	BatchSize                         *int64                  `json:"batch_size,omitempty"`
-	CfgScale                          *float32                `json:"cfg_scale,omitempty"`
-	DenoisingStrength                 *float32                `json:"denoising_strength,omitempty"`
-	DoNotSaveGrid                     *bool                   `json:"do_not_save_grid,omitempty"`
-	DoNotSaveSamples                  *bool                   `json:"do_not_save_samples,omitempty"`
-	Eta                               *int64                  `json:"eta,omitempty"`
-	Height                            *int64                  `json:"height,omitempty"`
-	ImageCfgScale                     *float32                `json:"image_cfg_scale,omitempty"`
-	IncludeInitImages                 *bool                   `json:"include_init_images,omitempty"`
-	InitImages                        *[]string               `json:"init_images,omitempty"`
-	InitialNoiseMultiplier            *int64                  `json:"initial_noise_multiplier,omitempty"`
-	InpaintFullRes                    *bool                   `json:"inpaint_full_res,omitempty"`
-	InpaintFullResPadding             *int64                  `json:"inpaint_full_res_padding,omitempty"`
-	InpaintingFill                    *int64                  `json:"inpainting_fill,omitempty"`
-	InpaintingMaskInvert              *int64                  `json:"inpainting_mask_invert,omitempty"`
+	ForceTaskId       *string                 `json:"force_task_id,omitempty"`
+	AlwaysonScripts   *map[string]interface{} `json:"alwayson_scripts,omitempty"`
+	BatchSize         *int64                  `json:"batch_size,omitempty"`
+	CfgScale          *float32                `json:"cfg_scale,omitempty"`
+	DenoisingStrength *float32                `json:"denoising_strength,omitempty"`
+	// DisableDefaultNegative not append config defaultNegativeEmbeddings to negative_prompt
+	DisableDefaultNegative *bool     `json:"disable_default_negative,omitempty"`
+	DoNotSaveGrid          *bool     `json:"do_not_save_grid,omitempty"`
+	DoNotSaveSamples       *bool     `json:"do_not_save_samples,omitempty"`
+	Eta                    *int64    `json:"eta,omitempty"`
+	Height                 *int64    `json:"height,omitempty"`
+	ImageCfgScale          *float32  `json:"image_cfg_scale,omitempty"`
+	IncludeInitImages      *bool     `json:"include_init_images,omitempty"`
+	InitImages             *[]string `json:"init_images,omitempty"`
+	InitialNoiseMultiplier *int64    `json:"initial_noise_multiplier,omitempty"`
+	InpaintFullRes         *bool     `json:"inpaint_full_res,omitempty"`
+	InpaintFullResPadding  *int64    `json:"inpaint_full_res_padding,omitempty"`
+	InpaintingFill         *int64    `json:"inpainting_fill,omitempty"`
+	InpaintingMaskInvert   *int64    `json:"inpainting_mask_invert,omitempty"`
+	// Labels client metadata echo in task result and progress, max 16 labels and 4KB
+	Labels                            *map[string]string      `json:"labels,omitempty"`
 	Mask                              *string                 `json:"mask,omitempty"`
 	MaskBlur                          *int64                  `json:"mask_blur,omitempty"`
 	MaskBlurX                         *int64                  `json:"mask_blur_x,omitempty"`
@@ -125,32 +254,49 @@ type Img2ImgRequest struct {
 	NegativePrompt                    *string                 `json:"negative_prompt,omitempty"`
 	OverrideSettings                  *map[string]interface{} `json:"override_settings,omitempty"`
 	OverrideSettingsRestoreAfterwards *bool                   `json:"override_settings_restore_afterwards,omitempty"`
-	Prompt                            *string                 `json:"prompt,omitempty"`
-	ResizeMode                        *int64                  `json:"resize_mode,omitempty"`
-	RestoreFaces                      *bool                   `json:"restore_faces,omitempty"`
-	SChurn                            *int64                  `json:"s_churn,omitempty"`
-	SMinUncond                        *int64                  `json:"s_min_uncond,omitempty"`
-	SNoise                            *int64                  `json:"s_noise,omitempty"`
-	STmax                             *int64                  `json:"s_tmax,omitempty"`
-	STmin                             *int64                  `json:"s_tmin,omitempty"`
-	SamplerIndex                      *string                 `json:"sampler_index,omitempty"`
-	SamplerName                       *string                 `json:"sampler_name,omitempty"`
-	SaveDir                           *string                 `json:"save_dir,omitempty"`
-	SaveImages                        *bool                   `json:"save_images,omitempty"`
-	ScriptArgs                        *[]interface{}          `json:"script_args,omitempty"`
-	ScriptName                        *string                 `json:"script_name,omitempty"`
-	SdVae                             *string                 `json:"sd_vae,omitempty"`
-	Seed                              *int64                  `json:"seed,omitempty"`
-	SeedResizeFromH                   *int64                  `json:"seed_resize_from_h,omitempty"`
-	SeedResizeFromW                   *int64                  `json:"seed_resize_from_w,omitempty"`
-	SendImages                        *bool                   `json:"send_images,omitempty"`
-	StableDiffusionModel              string                  `json:"stable_diffusion_model"`
-	Steps                             *int64                  `json:"steps,omitempty"`
-	Styles                            *[]string               `json:"styles,omitempty"`
-	Subseed                           *int64                  `json:"subseed,omitempty"`
-	SubseedStrength                   *float32                `json:"subseed_strength,omitempty"`
-	Tiling                            *bool                   `json:"tiling,omitempty"`
-	Width                             *int64                  `json:"width,omitempty"`
+	// Preset name of dimension preset of config, fill width, height and batch_size which request not set
+	Preset *string `json:"preset,omitempty"`
+	// Profile name of model profile, fill model, vae and default params which request not set
+	Profile              *string        `json:"profile,omitempty"`
+	Prompt               *string        `json:"prompt,omitempty"`
+	ResizeMode           *int64         `json:"resize_mode,omitempty"`
+	RestoreFaces         *bool          `json:"restore_faces,omitempty"`
+	SChurn               *int64         `json:"s_churn,omitempty"`
+	SMinUncond           *int64         `json:"s_min_uncond,omitempty"`
+	SNoise               *int64         `json:"s_noise,omitempty"`
+	STmax                *int64         `json:"s_tmax,omitempty"`
+	STmin                *int64         `json:"s_tmin,omitempty"`
+	SamplerIndex         *string        `json:"sampler_index,omitempty"`
+	SamplerName          *string        `json:"sampler_name,omitempty"`
+	SaveDir              *string        `json:"save_dir,omitempty"`
+	SaveImages           *bool          `json:"save_images,omitempty"`
+	ScriptArgs           *[]interface{} `json:"script_args,omitempty"`
+	ScriptName           *string        `json:"script_name,omitempty"`
+	SdVae                *string        `json:"sd_vae,omitempty"`
+	Seed                 *int64         `json:"seed,omitempty"`
+	SeedResizeFromH      *int64         `json:"seed_resize_from_h,omitempty"`
+	SeedResizeFromW      *int64         `json:"seed_resize_from_w,omitempty"`
+	SendImages           *bool          `json:"send_images,omitempty"`
+	StableDiffusionModel string         `json:"stable_diffusion_model"`
+	Steps                *int64         `json:"steps,omitempty"`
+	Styles               *[]string      `json:"styles,omitempty"`
+	Subseed              *int64         `json:"subseed,omitempty"`
+	SubseedStrength      *float32       `json:"subseed_strength,omitempty"`
+	Tiling               *bool          `json:"tiling,omitempty"`
+	Width                *int64         `json:"width,omitempty"`
+}
+
+// InterrogateRequest defines model for InterrogateRequest.
+type InterrogateRequest struct {
+	Image string `json:"image"`
+
+	// Model interrogate model, default clip
+	Model *string `json:"model,omitempty"`
+}
+
+// InterrogateResult defines model for InterrogateResult.
+type InterrogateResult struct {
+	Caption string `json:"caption"`
 }
 
 // ListSDFunctionResponse defines model for ListSDFunctionResponse.
@@ -165,6 +311,32 @@ type ListSDFunctionResponse struct {
 	Status *string `json:"status,omitempty"`
 }
 
+// MaintenanceRequest defines model for MaintenanceRequest.
+type MaintenanceRequest struct {
+	// DurationSeconds auto disable after seconds, default config maintenanceMaxDuration, 0 means never
+	DurationSeconds *int `json:"durationSeconds,omitempty"`
+	Enabled         bool `json:"enabled"`
+}
+
+// MaintenanceStatus defines model for MaintenanceStatus.
+type MaintenanceStatus struct {
+	// Drained no inflight submission and no pending async task, safe to stop
+	Drained bool `json:"drained"`
+	Enabled bool `json:"enabled"`
+
+	// ExpireAt auto disable timestamp(s), not set means never
+	ExpireAt *int64 `json:"expireAt,omitempty"`
+
+	// Inflight task submissions still running on this server
+	Inflight int `json:"inflight"`
+
+	// PendingTasks async tasks queued or rendering on all servers
+	PendingTasks int `json:"pendingTasks"`
+
+	// Since enabled timestamp(s)
+	Since *int64 `json:"since,omitempty"`
+}
+
 // Model defines model for Model.
 type Model struct {
 	// Name model name
@@ -197,109 +369,450 @@ type ModelAttributes struct {
 	// Status the model status, registering, loading, loaded or unloaded
 	Status string `json:"status"`
 
+	// Size on-disk size of model file, only set when model file on local disk
+	Size *int64 `json:"size,omitempty"`
+
 	// Type model type
 	Type string `json:"type"`
 }
 
+// ModelDefaults defines model for ModelDefaults.
+type ModelDefaults struct {
+	// Defaults default request params, request value override it
+	Defaults map[string]interface{} `json:"defaults"`
+}
+
+// ModelDeleteResult defines model for ModelDeleteResult.
+type ModelDeleteResult struct {
+	// Message why skipped or failed
+	Message *string `json:"message,omitempty"`
+	Name    string  `json:"name"`
+	Status  string  `json:"status"`
+}
+
+// ModelDirUsage defines model for ModelDirUsage.
+type ModelDirUsage struct {
+	// Bytes total size of files under dir, include sub dirs
+	Bytes int64  `json:"bytes"`
+	Files int    `json:"files"`
+	Path  string `json:"path"`
+
+	// Type model type
+	Type string `json:"type"`
+}
+
+// ModelProfile defines model for ModelProfile.
+type ModelProfile struct {
+	// Defaults default request params, request value override it
+	Defaults *map[string]interface{} `json:"defaults,omitempty"`
+
+	// Name profile name, use path param when update
+	Name                 *string `json:"name,omitempty"`
+	SdVae                *string `json:"sd_vae,omitempty"`
+	StableDiffusionModel string  `json:"stable_diffusion_model"`
+}
+
+// ModelQueue defines model for ModelQueue.
+type ModelQueue struct {
+	Length    int    `json:"length"`
+	MaxLength int    `json:"maxLength"`
+	Model     string `json:"model"`
+}
+
+// ModelReconcileResult defines model for ModelReconcileResult.
+type ModelReconcileResult struct {
+	// Message why failed
+	Message *string `json:"message,omitempty"`
+	Name    string  `json:"name"`
+	Status  string  `json:"status"`
+	Type    string  `json:"type"`
+}
+
 // OptionRequest config params
 type OptionRequest struct {
 	Data map[string]interface{} `json:"data"`
 }
 
+// OssDownloads concurrent oss image downloads across requests, only when ossDownloadConcurrency set
+type OssDownloads struct {
+	Capacity int `json:"capacity"`
+	InUse    int `json:"inUse"`
+}
+
+// PostProcess upscale or restore faces of rendered images by extras before upload, task result is the final image
+type PostProcess struct {
+	// CodeformerWeight codeformer weight, 0 max effect, 1 min effect, 0-1
+	CodeformerWeight *float32 `json:"codeformer_weight,omitempty"`
+
+	// RestoreFaces face restoration model, gfpgan|codeformer
+	RestoreFaces *string `json:"restore_faces,omitempty"`
+
+	// RestoreFacesVisibility face restoration visibility, 0-1, default 1
+	RestoreFacesVisibility *float32 `json:"restore_faces_visibility,omitempty"`
+
+	// Upscaler upscaler name, default Lanczos
+	Upscaler *string `json:"upscaler,omitempty"`
+
+	// UpscalingResize upscale factor, 1-8, 1 not upscale
+	UpscalingResize *float32 `json:"upscaling_resize,omitempty"`
+}
+
+// PromptTemplate defines model for PromptTemplate.
+type PromptTemplate struct {
+	// Content template content, can reference other template
+	Content string `json:"content"`
+
+	// Name template name, use path param when update
+	Name *string `json:"name,omitempty"`
+}
+
+// ReconcileModelsResult defines model for ReconcileModelsResult.
+type ReconcileModelsResult struct {
+	DryRun  bool                   `json:"dryRun"`
+	Results []ModelReconcileResult `json:"results"`
+}
+
 // ResponseMessage response message
 type ResponseMessage struct {
 	Message string `json:"message"`
 }
 
+// SdCapabilities sd webui capabilities
+type SdCapabilities struct {
+	// Extensions enabled extensions
+	Extensions     []string        `json:"extensions"`
+	Features       map[string]bool `json:"features"`
+	Img2imgScripts []string        `json:"img2imgScripts"`
+	Samplers       []string        `json:"samplers"`
+	Txt2imgScripts []string        `json:"txt2imgScripts"`
+	Version        string          `json:"version"`
+}
+
+// SdLogs defines model for SdLogs.
+type SdLogs struct {
+	// Lines recent sd stdout lines, oldest first
+	Lines []string `json:"lines"`
+}
+
+// SelfTestRequest defines model for SelfTestRequest.
+type SelfTestRequest struct {
+	// StableDiffusionModel sd model to test
+	StableDiffusionModel string `json:"stable_diffusion_model"`
+
+	// UpdateReference save rendered image hash as new reference
+	UpdateReference *bool `json:"update_reference,omitempty"`
+}
+
+// SelfTestResult defines model for SelfTestResult.
+type SelfTestResult struct {
+	// Hash sha256 of rendered image
+	Hash   string `json:"hash"`
+	Model  string `json:"model"`
+	Passed bool   `json:"passed"`
+
+	// ReferenceHash stored reference hash
+	ReferenceHash *string `json:"reference_hash,omitempty"`
+
+	// Status passed|failed|reference_created
+	Status string `json:"status"`
+}
+
+// ShareLink defines model for ShareLink.
+type ShareLink struct {
+	// ExpiresAt unix timestamp(s) link expire
+	ExpiresAt int64 `json:"expiresAt"`
+
+	// Token signed token of link
+	Token string `json:"token"`
+
+	// Url public url of task result
+	Url string `json:"url"`
+}
+
+// Stats defines model for Stats.
+type Stats struct {
+	// CircuitBreakers circuit breakers of downstream sd endpoints, only endpoints requested
+	CircuitBreakers *[]CircuitBreaker `json:"circuitBreakers,omitempty"`
+
+	// ColdStartBudget function creations budget, capacity 0 means no limit
+	ColdStartBudget *ColdStartBudget `json:"coldStartBudget,omitempty"`
+
+	// ImageCompression png compression before upload since start, only when imageCompression enabled
+	ImageCompression *ImageCompression `json:"imageCompression,omitempty"`
+
+	// OssDownloads concurrent oss image downloads across requests, only when ossDownloadConcurrency set
+	OssDownloads *OssDownloads `json:"ossDownloads,omitempty"`
+
+	// Queue pending tasks (queued or rendering) of proxy, maxLength 0 means no limit
+	Queue *TaskQueue `json:"queue,omitempty"`
+
+	// WarmPool warm instances of warmPool models
+	WarmPool *[]WarmModel `json:"warmPool,omitempty"`
+}
+
+// StorageInfo disk usage of filesystem holding sdPath
+type StorageInfo struct {
+	// FreeBytes bytes available to sd
+	FreeBytes int64           `json:"freeBytes"`
+	ModelDirs []ModelDirUsage `json:"modelDirs"`
+
+	// Models model files with on-disk size
+	Models     []ModelAttributes `json:"models"`
+	Path       string            `json:"path"`
+	TotalBytes int64             `json:"totalBytes"`
+	UsedBytes  int64             `json:"usedBytes"`
+}
+
 // SubmitTaskResponse defines model for SubmitTaskResponse.
 type SubmitTaskResponse struct {
-	Message *string `json:"message,omitempty"`
+	// Cached only /txt2img/cached, true when taskId is existed finished task with identical params
+	Cached *bool `json:"cached,omitempty"`
 
-	// OssUrl oss url
+	// Clamped params clamped to request limit of user
+	Clamped *[]string `json:"clamped,omitempty"`
+
+	// Images base64 data uri of images, only when request header X-Inline-Images is true and total size small, ossUrl omitted
+	Images *[]string `json:"images,omitempty"`
+
+	// InvokeMode sync: result in response, status succeeded; async: task accepted and queued, poll task result
+	InvokeMode *string `json:"invokeMode,omitempty"`
+	Message    *string `json:"message,omitempty"`
+
+	// OssUrl oss url, uploaded images only when status partial_upload
 	OssUrl *[]string `json:"ossUrl,omitempty"`
 	Status string    `json:"status"`
 	TaskId string    `json:"taskId"`
 }
 
+// TaskItem defines model for TaskItem.
+type TaskItem struct {
+	// CreateTime unix timestamp in seconds
+	CreateTime *int64   `json:"createTime,omitempty"`
+	GpuSeconds *float64 `json:"gpuSeconds,omitempty"`
+	Model      *string  `json:"model,omitempty"`
+
+	// ModifyTime unix timestamp in seconds
+	ModifyTime *int64 `json:"modifyTime,omitempty"`
+	Status     string `json:"status"`
+	TaskId     string `json:"taskId"`
+	User       string `json:"user"`
+}
+
+// TaskQueue pending tasks (queued or rendering) of proxy, maxLength 0 means no limit
+type TaskQueue struct {
+	Length    int `json:"length"`
+	MaxLength int `json:"maxLength"`
+
+	// Models models with pending tasks or queue limit
+	Models []ModelQueue `json:"models"`
+}
+
+// TaskList page of tasks, sort by task id
+type TaskList struct {
+	// NextCursor cursor of next page, empty when no more tasks; page may have less than limit tasks before end
+	NextCursor *string    `json:"nextCursor,omitempty"`
+	Tasks      []TaskItem `json:"tasks"`
+}
+
 // TaskProgressResponse defines model for TaskProgressResponse.
 type TaskProgressResponse struct {
-	CurrentImage string                  `json:"currentImage"`
-	EtaRelative  float32                 `json:"etaRelative"`
-	Message      *string                 `json:"message,omitempty"`
-	Progress     float32                 `json:"progress"`
-	State        *map[string]interface{} `json:"state,omitempty"`
-	TaskId       string                  `json:"taskId"`
+	CurrentImage string `json:"currentImage"`
+
+	// EstimatedStartTime unix seconds queued task expected to start, from queue position, recent task latency and rendering instances of model, not set when not queued or no history
+	EstimatedStartTime *int64  `json:"estimatedStartTime,omitempty"`
+	EtaRelative        float32 `json:"etaRelative"`
+
+	// EtaSeconds estimated seconds remaining reported by webui, 0 when task terminal, not set when unknown
+	EtaSeconds *float32 `json:"etaSeconds,omitempty"`
+	// Labels labels set when task submitted
+	Labels   *map[string]string `json:"labels,omitempty"`
+	Message  *string            `json:"message,omitempty"`
+	Progress float32            `json:"progress"`
+
+	// QueuePosition unfinished tasks of same model ahead of queued task, 0 next to run, not set when not queued
+	QueuePosition *int `json:"queuePosition,omitempty"`
+
+	// Seq progress write sequence, increase on every write, terminal task one more than last write; client drop response with seq not greater than last seen
+	Seq   *int64                  `json:"seq,omitempty"`
+	State *map[string]interface{} `json:"state,omitempty"`
+
+	// Step current sampling step of running job
+	Step *int `json:"step,omitempty"`
+
+	// Steps total sampling steps of running job
+	Steps  *int   `json:"steps,omitempty"`
+	TaskId string `json:"taskId"`
+}
+
+// TaskReproduceResponse defines model for TaskReproduceResponse.
+type TaskReproduceResponse struct {
+	// Path api path to resubmit request
+	Path string `json:"path"`
+
+	// Request request body of task, profile/preset/defaults expanded, base64 images redacted, oss path kept
+	Request map[string]interface{} `json:"request"`
 }
 
 // TaskResultResponse one task result, include taskId/images/parameters/info
 type TaskResultResponse struct {
+	// EffectiveSettings override_settings actually used after merge request, user config and defaults, secrets stripped
+	EffectiveSettings *map[string]interface{} `json:"effectiveSettings,omitempty"`
+
+	// FailedImages batch indices(0-based) of images upload failed, only when status partial_upload
+	FailedImages *[]int `json:"failedImages,omitempty"`
+
+	// GpuSeconds sd predict time of task, in seconds
+	GpuSeconds *float64 `json:"gpuSeconds,omitempty"`
+
 	// Images one task image result, len(images)>1 when batch count or batch size > 1
 	Images *[]string `json:"images,omitempty"`
 
+	// ImagesExpired images purged by imageRetentionDays, task metadata kept, images and ossUrl empty
+	ImagesExpired *bool `json:"imagesExpired,omitempty"`
+
 	// Info task predict info
-	Info    *map[string]interface{} `json:"info,omitempty"`
-	Message *string                 `json:"message,omitempty"`
+	Info *map[string]interface{} `json:"info,omitempty"`
+	// Labels labels set when task submitted
+	Labels  *map[string]string `json:"labels,omitempty"`
+	Message *string            `json:"message,omitempty"`
 
 	// OssUrl oss url
 	OssUrl *[]string `json:"ossUrl,omitempty"`
 
 	// Parameters task predict params
 	Parameters *map[string]interface{} `json:"parameters,omitempty"`
-	Status     string                  `json:"status"`
-	TaskId     string                  `json:"taskId"`
+
+	// Partial task still running, images only part of result
+	Partial *bool `json:"partial,omitempty"`
+
+	// Status no_output: webui succeeded without images (nsfw filter, script error), see info and message; partial_upload: some images upload failed, images are uploaded ones, see failedImages
+	Status string `json:"status"`
+	TaskId string `json:"taskId"`
+
+	// WebuiJobId sd webui job id of task (info job_timestamp), correlate with webui logs
+	WebuiJobId *string `json:"webuiJobId,omitempty"`
+}
+
+// TaskResultsRequest defines model for TaskResultsRequest.
+type TaskResultsRequest struct {
+	// TaskIds task ids, max 100, duplicated ids read once
+	TaskIds []string `json:"taskIds"`
+}
+
+// TaskResultsResult defines model for TaskResultsResult.
+type TaskResultsResult struct {
+	// NotFound task ids not found
+	NotFound []string `json:"notFound"`
+
+	// Results results of found tasks in request order
+	Results []TaskResultResponse `json:"results"`
+}
+
+// Txt2ImgMultiRequest defines model for Txt2ImgMultiRequest.
+type Txt2ImgMultiRequest struct {
+	Params Txt2ImgRequest `json:"params"`
+
+	// Prompts one task per prompt, max 16, params.prompt ignored
+	Prompts []string `json:"prompts"`
+}
+
+// Txt2ImgMultiResult defines model for Txt2ImgMultiResult.
+type Txt2ImgMultiResult struct {
+	// Clamped shared params clamped to request limit of user
+	Clamped *[]string `json:"clamped,omitempty"`
+
+	// TaskIds task id of each prompt in order
+	TaskIds []string `json:"taskIds"`
 }
 
 // Txt2ImgRequest defines model for Txt2ImgRequest.
 type Txt2ImgRequest struct {
-	ForceTaskId                       string                  `json:"force_task_id,omitempty"`
-	AlwaysonScripts                   *map[string]interface{} `json:"alwayson_scripts,omitempty"`
-	BatchSize                         *int64                  `json:"batch_size,omitempty"`
-	CfgScale                          *float32                `json:"cfg_scale,omitempty"`
-	DenoisingStrength                 *float32                `json:"denoising_strength,omitempty"`
-	DoNotSaveGrid                     *bool                   `json:"do_not_save_grid,omitempty"`
-	DoNotSaveSamples                  *bool                   `json:"do_not_save_samples,omitempty"`
-	EnableHr                          *bool                   `json:"enable_hr,omitempty"`
-	Eta                               *int64                  `json:"eta,omitempty"`
-	FirstphaseHeight                  *int64                  `json:"firstphase_height,omitempty"`
-	FirstphaseWidth                   *int64                  `json:"firstphase_width,omitempty"`
-	Height                            *int64                  `json:"height,omitempty"`
-	HrNegativePrompt                  *string                 `json:"hr_negative_prompt,omitempty"`
-	HrPrompt                          *string                 `json:"hr_prompt,omitempty"`
-	HrResizeX                         *int64                  `json:"hr_resize_x,omitempty"`
-	HrResizeY                         *int64                  `json:"hr_resize_y,omitempty"`
-	HrSamplerName                     *string                 `json:"hr_sampler_name,omitempty"`
-	HrScale                           *int64                  `json:"hr_scale,omitempty"`
-	HrSecondPassSteps                 *int64                  `json:"hr_second_pass_steps,omitempty"`
-	HrUpscaler                        *string                 `json:"hr_upscaler,omitempty"`
-	NIter                             *int64                  `json:"n_iter,omitempty"`
-	NegativePrompt                    *string                 `json:"negative_prompt,omitempty"`
+	ForceTaskId       string                  `json:"force_task_id,omitempty"`
+	AlwaysonScripts   *map[string]interface{} `json:"alwayson_scripts,omitempty"`
+	BatchSize         *int64                  `json:"batch_size,omitempty"`
+	CfgScale          *float32                `json:"cfg_scale,omitempty"`
+	DenoisingStrength *float32                `json:"denoising_strength,omitempty"`
+	// DisableDefaultNegative not append config defaultNegativeEmbeddings to negative_prompt
+	DisableDefaultNegative *bool   `json:"disable_default_negative,omitempty"`
+	DoNotSaveGrid          *bool   `json:"do_not_save_grid,omitempty"`
+	DoNotSaveSamples       *bool   `json:"do_not_save_samples,omitempty"`
+	EnableHr               *bool   `json:"enable_hr,omitempty"`
+	Eta                    *int64  `json:"eta,omitempty"`
+	FirstphaseHeight       *int64  `json:"firstphase_height,omitempty"`
+	FirstphaseWidth        *int64  `json:"firstphase_width,omitempty"`
+	Height                 *int64  `json:"height,omitempty"`
+	HrNegativePrompt       *string `json:"hr_negative_prompt,omitempty"`
+	HrPrompt               *string `json:"hr_prompt,omitempty"`
+	HrResizeX              *int64  `json:"hr_resize_x,omitempty"`
+	HrResizeY              *int64  `json:"hr_resize_y,omitempty"`
+	HrSamplerName          *string `json:"hr_sampler_name,omitempty"`
+	HrScale                *int64  `json:"hr_scale,omitempty"`
+	HrSecondPassSteps      *int64  `json:"hr_second_pass_steps,omitempty"`
+	HrUpscaler             *string `json:"hr_upscaler,omitempty"`
+	// Labels client metadata echo in task result and progress, max 16 labels and 4KB
+	Labels         *map[string]string `json:"labels,omitempty"`
+	NIter          *int64             `json:"n_iter,omitempty"`
+	NegativePrompt *string            `json:"negative_prompt,omitempty"`
+	// OutputPrefix oss key prefix of output images, need allowed by config outputPrefixes
+	OutputPrefix                      *string                 `json:"output_prefix,omitempty"`
 	OverrideSettings                  *map[string]interface{} `json:"override_settings,omitempty"`
 	OverrideSettingsRestoreAfterwards *bool                   `json:"override_settings_restore_afterwards,omitempty"`
-	Prompt                            *string                 `json:"prompt,omitempty"`
-	RestoreFaces                      *bool                   `json:"restore_faces,omitempty"`
-	SChurn                            *int64                  `json:"s_churn,omitempty"`
-	SMinUncond                        *int64                  `json:"s_min_uncond,omitempty"`
-	SNoise                            *int64                  `json:"s_noise,omitempty"`
-	STmax                             *int64                  `json:"s_tmax,omitempty"`
-	STmin                             *int64                  `json:"s_tmin,omitempty"`
-	SamplerIndex                      *string                 `json:"sampler_index,omitempty"`
-	SamplerName                       *string                 `json:"sampler_name,omitempty"`
-	SaveDir                           *string                 `json:"save_dir,omitempty"`
-	SaveImages                        *bool                   `json:"save_images,omitempty"`
-	ScriptArgs                        *[]interface{}          `json:"script_args,omitempty"`
-	ScriptName                        *string                 `json:"script_name,omitempty"`
-	SdVae                             *string                 `json:"sd_vae,omitempty"`
-	Seed                              *int64                  `json:"seed,omitempty"`
-	SeedResizeFromH                   *int64                  `json:"seed_resize_from_h,omitempty"`
-	SeedResizeFromW                   *int64                  `json:"seed_resize_from_w,omitempty"`
-	SendImages                        *bool                   `json:"send_images,omitempty"`
-	StableDiffusionModel              string                  `json:"stable_diffusion_model"`
-	Steps                             *int64                  `json:"steps,omitempty"`
-	Styles                            *[]string               `json:"styles,omitempty"`
-	Subseed                           *int64                  `json:"subseed,omitempty"`
-	SubseedStrength                   *float32                `json:"subseed_strength,omitempty"`
-	Tiling                            *bool                   `json:"tiling,omitempty"`
-	Width                             *int64                  `json:"width,omitempty"`
+	PostProcess                       *PostProcess            `json:"post_process,omitempty"`
+	// Preset name of dimension preset of config, fill width, height and batch_size which request not set
+	Preset *string `json:"preset,omitempty"`
+	// Profile name of model profile, fill model, vae and default params which request not set
+	Profile              *string        `json:"profile,omitempty"`
+	Prompt               *string        `json:"prompt,omitempty"`
+	RestoreFaces         *bool          `json:"restore_faces,omitempty"`
+	SChurn               *int64         `json:"s_churn,omitempty"`
+	SMinUncond           *int64         `json:"s_min_uncond,omitempty"`
+	SNoise               *int64         `json:"s_noise,omitempty"`
+	STmax                *int64         `json:"s_tmax,omitempty"`
+	STmin                *int64         `json:"s_tmin,omitempty"`
+	SamplerIndex         *string        `json:"sampler_index,omitempty"`
+	SamplerName          *string        `json:"sampler_name,omitempty"`
+	SaveDir              *string        `json:"save_dir,omitempty"`
+	SaveImages           *bool          `json:"save_images,omitempty"`
+	ScriptArgs           *[]interface{} `json:"script_args,omitempty"`
+	ScriptName           *string        `json:"script_name,omitempty"`
+	SdVae                *string        `json:"sd_vae,omitempty"`
+	Seed                 *int64         `json:"seed,omitempty"`
+	SeedResizeFromH      *int64         `json:"seed_resize_from_h,omitempty"`
+	SeedResizeFromW      *int64         `json:"seed_resize_from_w,omitempty"`
+	SendImages           *bool          `json:"send_images,omitempty"`
+	StableDiffusionModel string         `json:"stable_diffusion_model"`
+	Steps                *int64         `json:"steps,omitempty"`
+	Styles               *[]string      `json:"styles,omitempty"`
+	Subseed              *int64         `json:"subseed,omitempty"`
+	SubseedStrength      *float32       `json:"subseed_strength,omitempty"`
+	Tiling               *bool          `json:"tiling,omitempty"`
+	Width                *int64         `json:"width,omitempty"`
+}
+
+// UsageList defines model for UsageList.
+type UsageList = []UserUsage
+
+// UserImage defines model for UserImage.
+type UserImage struct {
+	// Key oss key
+	Key string `json:"key"`
+
+	// LastModified unix timestamp in seconds
+	LastModified *int64 `json:"lastModified,omitempty"`
+
+	// Size bytes
+	Size *int64 `json:"size,omitempty"`
+
+	// Url signed url of image
+	Url *string `json:"url,omitempty"`
+}
+
+// UserImageList page of user images, sort by oss key
+type UserImageList struct {
+	Images []UserImage `json:"images"`
+
+	// NextCursor cursor of next page, empty when no more images
+	NextCursor *string `json:"nextCursor,omitempty"`
 }
 
 // UserLoginRequest user login request, include username and password
@@ -315,29 +828,191 @@ type UserLoginResponse struct {
 	UserName string  `json:"userName"`
 }
 
+// UserStats user usage of tasks created since window start, storage not windowed
+type UserStats struct {
+	Days int `json:"days"`
+
+	// GpuSeconds sd predict time of tasks
+	GpuSeconds float64 `json:"gpuSeconds"`
+
+	// Images images generated by tasks
+	Images int `json:"images"`
+
+	// Since window start, unix timestamp in seconds
+	Since int64 `json:"since"`
+
+	// StorageBytes total size of all user images in oss, not set when imageNameTemplate not group by {user}
+	StorageBytes *int64 `json:"storageBytes,omitempty"`
+
+	// Tasks task count by status
+	Tasks      map[string]int `json:"tasks"`
+	TotalTasks int            `json:"totalTasks"`
+	User       string         `json:"user"`
+}
+
+// UserUsage user gpu seconds usage, only sd predict time
+type UserUsage struct {
+	GpuSeconds float64 `json:"gpuSeconds"`
+
+	// Tasks count of tasks with gpu seconds record
+	Tasks int    `json:"tasks"`
+	User  string `json:"user"`
+}
+
+// VersionInfo build version and config summary, no secrets
+type VersionInfo struct {
+	BuildTime string `json:"buildTime"`
+	Commit    string `json:"commit"`
+
+	// FlexMode singleFunc|multiFunc
+	FlexMode     string `json:"flexMode"`
+	LoginEnabled bool   `json:"loginEnabled"`
+
+	// OssMode local|remote
+	OssMode string `json:"ossMode"`
+
+	// ServerName proxy|control|agent
+	ServerName string `json:"serverName"`
+
+	// Version binary version, injected by ldflags
+	Version string `json:"version"`
+}
+
+// WarmModel warm instances of model, count of keep alive probes succeeded in last round
+type WarmModel struct {
+	MinWarm int    `json:"minWarm"`
+	Model   string `json:"model"`
+
+	// ProbeTime last probe round unix timestamp(s)
+	ProbeTime *int64 `json:"probeTime,omitempty"`
+	Warm      int    `json:"warm"`
+}
+
+// ReconcileModelsParams defines parameters for ReconcileModels.
+type ReconcileModelsParams struct {
+	// DryRun only report discrepancies, model store not changed
+	DryRun *bool `form:"dryRun,omitempty" json:"dryRun,omitempty"`
+}
+
+// TailSdLogsParams defines parameters for TailSdLogs.
+type TailSdLogsParams struct {
+	// Tail count of recent log lines, default 200, max 1000
+	Tail *int `form:"tail,omitempty" json:"tail,omitempty"`
+}
+
+// GetUsageParams defines parameters for GetUsage.
+type GetUsageParams struct {
+	// User only return usage of this user
+	User *string `form:"user,omitempty" json:"user,omitempty"`
+}
+
+// ListTasksParams defines parameters for ListTasks.
+type ListTasksParams struct {
+	// User only return tasks of this user, non admin user only see own tasks when login on
+	User *string `form:"user,omitempty" json:"user,omitempty"`
+
+	// Status only return tasks of this status
+	Status *string `form:"status,omitempty" json:"status,omitempty"`
+
+	// From create time >= from, unix timestamp in seconds
+	From *int64 `form:"from,omitempty" json:"from,omitempty"`
+
+	// To create time <= to, unix timestamp in seconds
+	To *int64 `form:"to,omitempty" json:"to,omitempty"`
+
+	// Model only return tasks of this sd model
+	Model *string `form:"model,omitempty" json:"model,omitempty"`
+
+	// Limit max tasks per page, default 20, max 100
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Cursor nextCursor of previous page
+	Cursor *string `form:"cursor,omitempty" json:"cursor,omitempty"`
+}
+
+// ShareTaskParams defines parameters for ShareTask.
+type ShareTaskParams struct {
+	// ExpiresIn link valid seconds, default shareLinkTTL, max 604800
+	ExpiresIn *int `form:"expiresIn,omitempty" json:"expiresIn,omitempty"`
+}
+
+// ListUserImagesParams defines parameters for ListUserImages.
+type ListUserImagesParams struct {
+	// Limit max images per page, default 100, max 1000
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Cursor nextCursor of previous page
+	Cursor *string `form:"cursor,omitempty" json:"cursor,omitempty"`
+
+	// OutputPrefix list images written with this output_prefix, must be allowed for user
+	OutputPrefix *string `form:"output_prefix,omitempty" json:"output_prefix,omitempty"`
+}
+
+// GetUserStatsParams defines parameters for GetUserStats.
+type GetUserStatsParams struct {
+	// Days window of recent days by task create time, default userStatsDays(30), max 366
+	Days *int `form:"days,omitempty" json:"days,omitempty"`
+}
+
 // BatchUpdateResourceJSONRequestBody defines body for BatchUpdateResource for application/json ContentType.
 type BatchUpdateResourceJSONRequestBody = BatchUpdateSdResourceRequest
 
 // DelSDFuncJSONRequestBody defines body for DelSDFunc for application/json ContentType.
 type DelSDFuncJSONRequestBody = DelSDFunctionRequest
 
+// ExtraBatchImagesJSONRequestBody defines body for ExtraBatchImages for application/json ContentType.
+type ExtraBatchImagesJSONRequestBody = ExtraBatchImagesRequest
+
 // ExtraImagesJSONRequestBody defines body for ExtraImages for application/json ContentType.
 type ExtraImagesJSONRequestBody = ExtraImagesRequest
 
 // Img2ImgJSONRequestBody defines body for Img2Img for application/json ContentType.
 type Img2ImgJSONRequestBody = Img2ImgRequest
 
+// InterrogateJSONRequestBody defines body for Interrogate for application/json ContentType.
+type InterrogateJSONRequestBody = InterrogateRequest
+
 // LoginJSONRequestBody defines body for Login for application/json ContentType.
 type LoginJSONRequestBody = UserLoginRequest
 
+// SetMaintenanceJSONRequestBody defines body for SetMaintenance for application/json ContentType.
+type SetMaintenanceJSONRequestBody = MaintenanceRequest
+
+// UpdatePromptTemplateJSONRequestBody defines body for UpdatePromptTemplate for application/json ContentType.
+type UpdatePromptTemplateJSONRequestBody = PromptTemplate
+
 // RegisterModelJSONRequestBody defines body for RegisterModel for application/json ContentType.
 type RegisterModelJSONRequestBody = ModelAttributes
 
+// BatchDeleteModelsJSONRequestBody defines body for BatchDeleteModels for application/json ContentType.
+type BatchDeleteModelsJSONRequestBody = BatchDeleteModelsRequest
+
 // UpdateModelJSONRequestBody defines body for UpdateModel for application/json ContentType.
 type UpdateModelJSONRequestBody = ModelAttributes
 
+// UpdateModelDefaultsJSONRequestBody defines body for UpdateModelDefaults for application/json ContentType.
+type UpdateModelDefaultsJSONRequestBody = ModelDefaults
+
+// UpdateProfileJSONRequestBody defines body for UpdateProfile for application/json ContentType.
+type UpdateProfileJSONRequestBody = ModelProfile
+
+// SelfTestJSONRequestBody defines body for SelfTest for application/json ContentType.
+type SelfTestJSONRequestBody = SelfTestRequest
+
 // UpdateOptionsJSONRequestBody defines body for UpdateOptions for application/json ContentType.
 type UpdateOptionsJSONRequestBody = OptionRequest
 
 // Txt2ImgJSONRequestBody defines body for Txt2Img for application/json ContentType.
 type Txt2ImgJSONRequestBody = Txt2ImgRequest
+
+// Txt2ImgCachedJSONRequestBody defines body for Txt2ImgCached for application/json ContentType.
+type Txt2ImgCachedJSONRequestBody = Txt2ImgRequest
+
+// Txt2ImgMultiJSONRequestBody defines body for Txt2ImgMulti for application/json ContentType.
+type Txt2ImgMultiJSONRequestBody = Txt2ImgMultiRequest
+
+// GetTaskResultsJSONRequestBody defines body for GetTaskResults for application/json ContentType.
+type GetTaskResultsJSONRequestBody = TaskResultsRequest
+
+// DeleteUserImagesJSONRequestBody defines body for DeleteUserImages for application/json ContentType.
+type DeleteUserImagesJSONRequestBody = DeleteUserImagesRequest
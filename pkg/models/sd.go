@@ -16,6 +16,8 @@ type ProgressResult struct {
 	EtaRelative  float64 `json:"eta_relative"`
 	Progress     float64 `json:"progress"`
 	State        State   `json:"state"`
+	// force_task_id of request rendering, empty when webui not report it
+	CurrentTask string `json:"current_task"`
 }
 
 type State struct {
@@ -0,0 +1,154 @@
+package module
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/aliyun/aliyun-oss-go-sdk/oss"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const credentialsUriTimeout = 5 * time.Second
+
+// auth expiry error of fc/oss, refresh credential and retry
+var credentialExpiredKeywords = []string{"securitytokenexpired", "invalidsecuritytoken.expired",
+	"security token expired", "token has expired", "invalidaccesskeyid"}
+
+// Credential ak and sts token, implement oss.Credentials
+type Credential struct {
+	AccessKeyId     string
+	AccessKeySecret string
+	SecurityToken   string
+}
+
+func (c Credential) GetAccessKeyID() string {
+	return c.AccessKeyId
+}
+
+func (c Credential) GetAccessKeySecret() string {
+	return c.AccessKeySecret
+}
+
+func (c Credential) GetSecurityToken() string {
+	return c.SecurityToken
+}
+
+// credentialStore latest credential, version increase when changed
+// oss read it every request, fc client rebuild when version changed
+type credentialStore struct {
+	lock    sync.RWMutex
+	cred    Credential
+	version int64
+}
+
+// CredentialGlobal latest credential shared by fc and oss client
+var CredentialGlobal = new(credentialStore)
+
+func (s *credentialStore) get() (Credential, int64) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.cred, s.version
+}
+
+// Update set credential, return true if changed
+func (s *credentialStore) Update(cred Credential) bool {
+	if cred.AccessKeyId == "" || cred.AccessKeySecret == "" {
+		return false
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.cred == cred {
+		return false
+	}
+	s.cred = cred
+	s.version++
+	return true
+}
+
+// GetCredentials oss.CredentialsProvider
+func (s *credentialStore) GetCredentials() oss.Credentials {
+	cred, _ := s.get()
+	return cred
+}
+
+// refresh fetch latest credential from credentials uri, fc runtime env without uri,
+// credential never read from request headers which any client can set
+func (s *credentialStore) refresh() {
+	uri := os.Getenv(config.CREDENTIALS_URI)
+	if uri == "" {
+		if s.Update(Credential{
+			AccessKeyId:     os.Getenv(config.ACCESS_KEY_ID),
+			AccessKeySecret: os.Getenv(config.ACCESS_KEY_SECRET),
+			SecurityToken:   os.Getenv(config.ACCESS_KET_TOKEN),
+		}) {
+			logrus.Info("credential reload from fc runtime env")
+		}
+		return
+	}
+	cred, err := fetchCredential(uri)
+	if err != nil {
+		logrus.Warnf("reload credential from %s err=%s", uri, err.Error())
+		return
+	}
+	if s.Update(cred) {
+		logrus.Info("credential reload from credentials uri")
+	}
+}
+
+// fetchCredential get sts credential from credentials uri
+// response {"Code":"Success","AccessKeyId":"","AccessKeySecret":"","SecurityToken":""}
+func fetchCredential(uri string) (Credential, error) {
+	httpClient := &http.Client{Timeout: credentialsUriTimeout}
+	resp, err := httpClient.Get(uri)
+	if err != nil {
+		return Credential{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return Credential{}, fmt.Errorf("status code %d", resp.StatusCode)
+	}
+	var body struct {
+		Code            string
+		AccessKeyId     string
+		AccessKeySecret string
+		SecurityToken   string
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return Credential{}, err
+	}
+	if body.Code != "Success" {
+		return Credential{}, fmt.Errorf("code %s", body.Code)
+	}
+	return Credential{AccessKeyId: body.AccessKeyId, AccessKeySecret: body.AccessKeySecret,
+		SecurityToken: body.SecurityToken}, nil
+}
+
+// init store with config credential once
+func initCredential() {
+	if _, version := CredentialGlobal.get(); version > 0 {
+		return
+	}
+	CredentialGlobal.Update(Credential{
+		AccessKeyId:     config.ConfigGlobal.AccessKeyId,
+		AccessKeySecret: config.ConfigGlobal.AccessKeySecret,
+		SecurityToken:   config.ConfigGlobal.AccessKeyToken,
+	})
+}
+
+func isCredentialExpiredError(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	for _, keyword := range credentialExpiredKeywords {
+		if strings.Contains(msg, keyword) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,92 @@
+package module
+
+import (
+	"errors"
+	"fmt"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func mockCredential(t *testing.T) {
+	old := CredentialGlobal
+	CredentialGlobal = new(credentialStore)
+	t.Cleanup(func() {
+		CredentialGlobal = old
+	})
+}
+
+func TestCredentialStore(t *testing.T) {
+	mockCredential(t)
+	config.ConfigGlobal = &config.Config{ConfigEnv: config.ConfigEnv{AccessKeyId: "ak", AccessKeySecret: "sk",
+		AccessKeyToken: "token"}}
+	initCredential()
+	cred, version := CredentialGlobal.get()
+	assert.Equal(t, Credential{AccessKeyId: "ak", AccessKeySecret: "sk", SecurityToken: "token"}, cred)
+	assert.Equal(t, int64(1), version)
+	assert.Equal(t, "token", CredentialGlobal.GetCredentials().GetSecurityToken())
+
+	// same or empty credential not change version
+	assert.False(t, CredentialGlobal.Update(cred))
+	assert.False(t, CredentialGlobal.Update(Credential{}))
+
+	// without credentials uri reload from fc runtime env
+	t.Setenv(config.CREDENTIALS_URI, "")
+	t.Setenv(config.ACCESS_KEY_ID, "ak2")
+	t.Setenv(config.ACCESS_KEY_SECRET, "sk2")
+	t.Setenv(config.ACCESS_KET_TOKEN, "token2")
+	CredentialGlobal.refresh()
+	cred, version = CredentialGlobal.get()
+	assert.Equal(t, "token2", cred.SecurityToken)
+	assert.Equal(t, int64(2), version)
+}
+
+func TestWithCredentialRetry(t *testing.T) {
+	mockCredential(t)
+	config.ConfigGlobal = &config.Config{ConfigEnv: config.ConfigEnv{AccountId: "1", Region: "cn-hangzhou",
+		AccessKeyId: "ak", AccessKeySecret: "sk", AccessKeyToken: "token"}}
+	token := "token"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"Code":"Success","AccessKeyId":"ak","AccessKeySecret":"sk","SecurityToken":"%s"}`, token)
+	}))
+	defer server.Close()
+	t.Setenv(config.CREDENTIALS_URI, server.URL)
+	initCredential()
+	f := &FuncManager{}
+	assert.Nil(t, f.newClient())
+	oldClient := f.getFc3Client()
+	expired := errors.New("code: 401, SecurityTokenExpired: the security token you provided has expired")
+
+	// credential not rotated, no retry
+	calls := 0
+	err := f.withCredentialRetry(func() error {
+		calls++
+		return expired
+	})
+	assert.Equal(t, expired, err)
+	assert.Equal(t, 1, calls)
+
+	// rotated, rebuild client and retry once
+	token = "token2"
+	calls = 0
+	err = f.withCredentialRetry(func() error {
+		calls++
+		if calls == 1 {
+			return expired
+		}
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, calls)
+	assert.NotSame(t, oldClient, f.getFc3Client())
+
+	// other error not retry
+	calls = 0
+	f.withCredentialRetry(func() error {
+		calls++
+		return errors.New("code: 400, InvalidArgument")
+	})
+	assert.Equal(t, 1, calls)
+}
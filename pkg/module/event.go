@@ -1,17 +1,28 @@
 package module
 
 import (
+	"context"
 	"fmt"
 	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
 	"github.com/sirupsen/logrus"
 	"net/http"
+	"time"
 )
 
-var client = &http.Client{}
+var client = &http.Client{Transport: SdTransport(nil)}
+
+// sdRefreshTimeout hot refresh of remote webui not block register long
+var sdRefreshTimeout = 30 * time.Second
 
 // ModelChangeEvent  models change callback func
 func ModelChangeEvent(v any) {
-	modelType := v.(string)
+	if err := RefreshModel(v.(string)); err != nil {
+		logrus.Info("[ModelChangeEvent] listen model refresh do fail")
+	}
+}
+
+// RefreshModel call webui refresh api, let webui reload modelType models
+func RefreshModel(modelType string) error {
 	path := ""
 	method := "GET"
 	switch modelType {
@@ -26,15 +37,46 @@ func ModelChangeEvent(v any) {
 		method = "POST"
 	default:
 		logrus.Infof("[ModelChangeEvent] modelType=%s no need reload", modelType)
-		return
+		return nil
 	}
 	url := fmt.Sprintf("%s%s", config.ConfigGlobal.SdUrlPrefix, path)
 	req, _ := http.NewRequest(method, url, nil)
-	_, err := client.Do(req)
+	resp, err := client.Do(req)
 	if err != nil {
-		logrus.Info("[ModelChangeEvent] listen model refresh do fail")
-		return
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("refresh %s fail, status code=%d", modelType, resp.StatusCode)
+	}
+	return nil
+}
+
+// RefreshSdModel refresh checkpoints of running webui at endpoint, check sdModel selectable
+func RefreshSdModel(endpoint, sdModel string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), sdRefreshTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s%s", endpoint,
+		config.REFRESH_SD_MODEL), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("refresh checkpoints fail, status code=%d", resp.StatusCode)
+	}
+	checkPoints, err := listCheckPoints(ctx, endpoint)
+	if err != nil {
+		return fmt.Errorf("list checkpoints err=%s", err.Error())
+	}
+	if _, ok := checkPoints[sdModel]; !ok {
+		return fmt.Errorf("sd model %s not in checkpoints after refresh", sdModel)
 	}
+	return nil
 }
 
 // CancelEvent tasks cancel signal callback
@@ -0,0 +1,66 @@
+package module
+
+import (
+	"encoding/json"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRefreshSdModel(t *testing.T) {
+	old := config.ConfigGlobal
+	config.ConfigGlobal = &config.Config{}
+	defer func() {
+		config.ConfigGlobal = old
+	}()
+	var refreshed int32
+	sd := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case config.REFRESH_SD_MODEL:
+			assert.Equal(t, http.MethodPost, r.Method)
+			atomic.StoreInt32(&refreshed, 1)
+		case config.GET_SD_MODEL:
+			checkPoints := []map[string]string{{"title": "old.safetensors [abc]"}}
+			if atomic.LoadInt32(&refreshed) == 1 {
+				checkPoints = append(checkPoints, map[string]string{"title": "new.safetensors [def]"})
+			}
+			json.NewEncoder(w).Encode(checkPoints)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer sd.Close()
+
+	// listed after refresh
+	assert.Nil(t, RefreshSdModel(sd.URL, "new.safetensors"))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&refreshed))
+	// not listed
+	err := RefreshSdModel(sd.URL, "missing.safetensors")
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "not in checkpoints")
+	// webui unavailable
+	assert.NotNil(t, RefreshSdModel("http://127.0.0.1:1", "new.safetensors"))
+
+	// wedged webui not block
+	release := make(chan struct{})
+	wedged := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer wedged.Close()
+	defer close(release)
+	oldTimeout := sdRefreshTimeout
+	sdRefreshTimeout = 100 * time.Millisecond
+	defer func() {
+		sdRefreshTimeout = oldTimeout
+	}()
+	start := time.Now()
+	assert.NotNil(t, RefreshSdModel(wedged.URL, "new.safetensors"))
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
@@ -8,22 +8,41 @@ import (
 	fc3 "github.com/alibabacloud-go/fc-20230330/client"
 	fc "github.com/alibabacloud-go/fc-open-20210406/v2/client"
 	fcService "github.com/alibabacloud-go/tea-utils/v2/service"
+	"github.com/alibabacloud-go/tea/tea"
 	gr "github.com/awesome-fc/golang-runtime"
 	"github.com/devsapp/goutils/aigc/project"
 	fcUtils "github.com/devsapp/goutils/fc"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/concurrency"
 	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
 	"github.com/devsapp/serverless-stable-diffusion-api/pkg/datastore"
 	"github.com/devsapp/serverless-stable-diffusion-api/pkg/utils"
 	"github.com/sirupsen/logrus"
+	"net/url"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 )
 
 const (
-	RETRY_INTERVALMS = time.Duration(10) * time.Millisecond
+	RETRY_INTERVALMS       = time.Duration(10) * time.Millisecond
+	COLD_START_BUDGET_WAIT = 2 * time.Second
+	MANIFEST_CONCURRENCY   = 8
+	// hex chars of endpoint hash as sticky token
+	stickyTokenLength = 16
+	// function not created yet, gpu memory lookup not repeated within ttl
+	gpuMemoryMissTTL = time.Minute
 )
 
+// ErrColdStartBudget function creations exceed cold start budget
+var ErrColdStartBudget = errors.New("cold start budget exhausted, please retry later")
+
+// ErrFunctionNotExist function of key not created, no instance to reload models
+var ErrFunctionNotExist = errors.New("function not exist")
+
+// fc error codes of create function meaning gpu capacity not enough
+var capacityErrorCodes = map[string]struct{}{"ResourceExhausted": {}, "ResourceNotEnough": {}}
+
 type SdModels struct {
 	sdModel  string
 	sdVae    string
@@ -55,9 +74,19 @@ type FuncManager struct {
 	funcStore          datastore.Datastore
 	fcClient           *fc.Client
 	fc3Client          *fc3.Client
+	clientLock         sync.RWMutex
+	clientVersion      int64
 	lock               sync.RWMutex
 	lastInvokeEndpoint string
 	prefix             string
+	coldStartBudget    *concurrency.TokenBucket
+	stop               chan struct{}
+	stopOnce           sync.Once
+	// function key -> gpu memory(MB), fc api too slow for every request
+	gpuMemory map[string]int32
+	// function key -> expire time of lookup found no function
+	gpuMemoryMiss map[string]time.Time
+	gpuMemoryLock sync.Mutex
 }
 
 func isFc3() bool {
@@ -65,28 +94,20 @@ func isFc3() bool {
 }
 
 func InitFuncManager(funcStore datastore.Datastore) error {
-	// init fc client
-	fcEndpoint := fmt.Sprintf("%s.%s.fc.aliyuncs.com", config.ConfigGlobal.AccountId,
-		config.ConfigGlobal.Region)
 	FuncManagerGlobal = &FuncManager{
 		endpoints: make(map[string][]string),
 		funcStore: funcStore,
+		stop:      make(chan struct{}),
+		coldStartBudget: concurrency.NewTokenBucket(config.ConfigGlobal.ColdStartBudget,
+			time.Duration(config.ConfigGlobal.ColdStartBudgetWindow)*time.Second),
 	}
 	// extra prefix
 	if parts := strings.Split(config.ConfigGlobal.FunctionName, project.PrefixDelimiter); len(parts) >= 2 {
 		FuncManagerGlobal.prefix = fmt.Sprintf("%s%s", parts[0], project.PrefixDelimiter)
 	}
-	var err error
-	if isFc3() {
-		FuncManagerGlobal.fc3Client, err = fc3.NewClient(new(openapi.Config).SetAccessKeyId(config.ConfigGlobal.AccessKeyId).
-			SetAccessKeySecret(config.ConfigGlobal.AccessKeySecret).SetSecurityToken(config.ConfigGlobal.AccessKeyToken).
-			SetProtocol("HTTP").SetEndpoint(fcEndpoint))
-	} else {
-		FuncManagerGlobal.fcClient, err = fc.NewClient(new(openapi.Config).SetAccessKeyId(config.ConfigGlobal.AccessKeyId).
-			SetAccessKeySecret(config.ConfigGlobal.AccessKeySecret).SetSecurityToken(config.ConfigGlobal.AccessKeyToken).
-			SetProtocol("HTTP").SetEndpoint(fcEndpoint))
-	}
-
+	// init fc client
+	initCredential()
+	err := FuncManagerGlobal.newClient()
 	if err != nil {
 		return err
 	}
@@ -94,10 +115,81 @@ func InitFuncManager(funcStore datastore.Datastore) error {
 		// load func endpoint to cache
 		FuncManagerGlobal.loadFunc()
 		//FuncManagerGlobal.checkDbAndFcMatch()
+		// control keep cache fresh, function created or deleted by other control instance
+		if interval := config.ConfigGlobal.FuncRefreshInterval; interval > 0 &&
+			config.ConfigGlobal.IsServerTypeMatch(config.CONTROL) {
+			go FuncManagerGlobal.runRefresh(time.Duration(interval) * time.Second)
+		}
+	}
+	return nil
+}
+
+// newClient build fc client with latest credential
+func (f *FuncManager) newClient() error {
+	cred, version := CredentialGlobal.get()
+	fcEndpoint := fmt.Sprintf("%s.%s.fc.aliyuncs.com", config.ConfigGlobal.AccountId,
+		config.ConfigGlobal.Region)
+	clientConfig := new(openapi.Config).SetAccessKeyId(cred.AccessKeyId).
+		SetAccessKeySecret(cred.AccessKeySecret).SetSecurityToken(cred.SecurityToken).
+		SetProtocol("HTTP").SetEndpoint(fcEndpoint)
+	f.clientLock.Lock()
+	defer f.clientLock.Unlock()
+	if isFc3() {
+		client, err := fc3.NewClient(clientConfig)
+		if err != nil {
+			return err
+		}
+		f.fc3Client = client
+	} else {
+		client, err := fc.NewClient(clientConfig)
+		if err != nil {
+			return err
+		}
+		f.fcClient = client
 	}
+	f.clientVersion = version
 	return nil
 }
 
+func (f *FuncManager) getFcClient() *fc.Client {
+	f.clientLock.RLock()
+	defer f.clientLock.RUnlock()
+	return f.fcClient
+}
+
+func (f *FuncManager) getFc3Client() *fc3.Client {
+	f.clientLock.RLock()
+	defer f.clientLock.RUnlock()
+	return f.fc3Client
+}
+
+// refreshClient reload credential, rebuild client if credential changed
+func (f *FuncManager) refreshClient() bool {
+	CredentialGlobal.refresh()
+	_, version := CredentialGlobal.get()
+	f.clientLock.RLock()
+	changed := version != f.clientVersion
+	f.clientLock.RUnlock()
+	if !changed {
+		return false
+	}
+	if err := f.newClient(); err != nil {
+		logrus.Errorf("rebuild fc client err=%s", err.Error())
+		return false
+	}
+	return true
+}
+
+// withCredentialRetry retry op once after credential refreshed when sts token expired
+func (f *FuncManager) withCredentialRetry(op func() error) error {
+	err := op()
+	if isCredentialExpiredError(err) && f.refreshClient() {
+		logrus.Warnf("fc credential expired, retry with refreshed credential, err=%s", err.Error())
+		err = op()
+	}
+	return err
+}
+
 // check ots table function list match fc function or not
 func (f *FuncManager) checkDbAndFcMatch() {
 	for sdModel, _ := range f.endpoints {
@@ -124,6 +216,25 @@ func (f *FuncManager) GetLastInvokeEndpoint(sdModel *string) string {
 	return f.lastInvokeEndpoint
 }
 
+// StickyToken opaque routing token of endpoint, client send it back to pin following requests
+func StickyToken(endpoint string) string {
+	return utils.Hash(endpoint)[:stickyTokenLength]
+}
+
+// GetStickyEndpoint endpoint pinned by sticky token, empty if function of endpoint gone or not serve sdModel
+func (f *FuncManager) GetStickyEndpoint(token, sdModel string) string {
+	key := "default"
+	if config.ConfigGlobal.GetFlexMode() == config.MultiFunc && sdModel != "" {
+		key = sdModel
+	}
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+	if val, ok := f.endpoints[key]; ok && StickyToken(val[0]) == token {
+		return val[0]
+	}
+	return ""
+}
+
 // GetEndpoint get endpoint, key=sdModel
 // retry and read from db if create function fail
 // first get from cache
@@ -152,6 +263,19 @@ func (f *FuncManager) GetEndpoint(sdModel string) (string, error) {
 			f.lock.Unlock()
 			return endpoint, nil
 		}
+		f.lock.Unlock()
+		// limit function creations, control fc cost; wait without lock, lookups of other models not stall
+		if f.coldStartBudget != nil && !f.coldStartBudget.Wait(COLD_START_BUDGET_WAIT) {
+			logrus.Warnf("sdModel:%s create function fail, %s", sdModel, ErrColdStartBudget.Error())
+			return "", ErrColdStartBudget
+		}
+		f.lock.Lock()
+		// created by other request while waiting budget
+		if val, ok := f.endpoints[key]; ok {
+			f.lastInvokeEndpoint = val[0]
+			f.lock.Unlock()
+			return val[0], nil
+		}
 		// third create function
 		if endpoint, err = f.createFunc(key, sdModel, getEnv(sdModel)); endpoint != "" {
 			f.lastInvokeEndpoint = endpoint
@@ -180,12 +304,10 @@ func (f *FuncManager) GetEndpoint(sdModel string) (string, error) {
 // UpdateAllFunctionEnv update instance env, restart agent function
 func (f *FuncManager) UpdateAllFunctionEnv() error {
 	// reload from db
-	f.lock.Lock()
 	f.loadFunc()
-	f.lock.Unlock()
 	// update all function env
-	for key, _ := range f.endpoints {
-		if err := f.UpdateFunctionEnv(key); err != nil {
+	for _, key := range f.endpointKeys() {
+		if err := f.UpdateFunctionEnv(key); err != nil && !errors.Is(err, ErrFunctionNotExist) {
 			return err
 		}
 	}
@@ -198,29 +320,70 @@ func (f *FuncManager) UpdateFunctionEnv(key string) error {
 	functionName := GetFunctionName(key)
 	res := f.GetFuncResource(functionName)
 	if res == nil {
-		return nil
+		return ErrFunctionNotExist
 	}
 	res.Env[config.MODEL_REFRESH_SIGNAL] = utils.String(fmt.Sprintf("%d", utils.TimestampS())) // value = now timestamp
 	//compatible fc3.0
-	if isFc3() {
-		if _, err := f.fc3Client.UpdateFunction(&functionName,
-			new(fc3.UpdateFunctionRequest).SetRequest(new(fc3.UpdateFunctionInput).SetRuntime("custom-container").
-				SetEnvironmentVariables(res.Env).SetGpuConfig(new(fc3.GPUConfig).
-				SetGpuMemorySize(res.GpuMemorySize).SetGpuType(res.InstanceType)))); err != nil {
-			logrus.Info(err.Error())
+	if err := f.withCredentialRetry(func() error {
+		if isFc3() {
+			_, err := f.getFc3Client().UpdateFunction(&functionName,
+				new(fc3.UpdateFunctionRequest).SetRequest(new(fc3.UpdateFunctionInput).SetRuntime("custom-container").
+					SetEnvironmentVariables(res.Env).SetGpuConfig(new(fc3.GPUConfig).
+					SetGpuMemorySize(res.GpuMemorySize).SetGpuType(res.InstanceType))))
 			return err
 		}
-	} else {
-		if _, err := f.fcClient.UpdateFunction(&config.ConfigGlobal.ServiceName, &functionName,
+		_, err := f.getFcClient().UpdateFunction(&config.ConfigGlobal.ServiceName, &functionName,
 			new(fc.UpdateFunctionRequest).SetRuntime("custom-container").SetGpuMemorySize(res.GpuMemorySize).
-				SetEnvironmentVariables(res.Env)); err != nil {
-			logrus.Info(err.Error())
-			return err
-		}
+				SetEnvironmentVariables(res.Env))
+		return err
+	}); err != nil {
+		logrus.Info(err.Error())
+		return err
 	}
 	return nil
 }
 
+// FuncEnvResult env refresh result of function
+type FuncEnvResult struct {
+	FunctionName string
+	SdModel      string
+	Err          error
+}
+
+// RefreshFunctionsEnv update env of cached functions concurrently, instances restart and load latest models
+// sdModel not empty only refresh function serve it
+func (f *FuncManager) RefreshFunctionsEnv(sdModel string) []FuncEnvResult {
+	f.lock.RLock()
+	results := make([]FuncEnvResult, 0, len(f.endpoints))
+	keys := make([]string, 0, len(f.endpoints))
+	for key, val := range f.endpoints {
+		if sdModel != "" && val[1] != sdModel {
+			continue
+		}
+		keys = append(keys, key)
+		results = append(results, FuncEnvResult{FunctionName: GetFunctionName(key), SdModel: val[1]})
+	}
+	f.lock.RUnlock()
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, MANIFEST_CONCURRENCY)
+	for i := range keys {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			results[i].Err = f.UpdateFunctionEnv(keys[i])
+		}(i)
+	}
+	wg.Wait()
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].FunctionName < results[j].FunctionName
+	})
+	return results
+}
+
 // UpdateFunctionResource update function resource
 func (f *FuncManager) UpdateFunctionResource(resources map[string]*FuncResource) ([]string, []string, []string) {
 	success := make([]string, 0, len(resources))
@@ -228,26 +391,26 @@ func (f *FuncManager) UpdateFunctionResource(resources map[string]*FuncResource)
 	errs := make([]string, 0, len(resources))
 	for key, resource := range resources {
 		functionName := GetFunctionName(key)
-		if isFc3() {
-			if _, err := f.fc3Client.UpdateFunction(&functionName, getFC3UpdateFunctionRequest(resource)); err != nil {
-				fail = append(fail, functionName)
-				errs = append(errs, err.Error())
-
-			} else {
-				success = append(success, key)
+		if err := f.withCredentialRetry(func() error {
+			if isFc3() {
+				_, err := f.getFc3Client().UpdateFunction(&functionName, getFC3UpdateFunctionRequest(resource))
+				return err
 			}
-		} else {
-			if _, err := f.fcClient.UpdateFunction(&config.ConfigGlobal.ServiceName, &functionName,
+			_, err := f.getFcClient().UpdateFunction(&config.ConfigGlobal.ServiceName, &functionName,
 				new(fc.UpdateFunctionRequest).SetRuntime("custom-container").SetGpuMemorySize(resource.GpuMemorySize).
 					SetMemorySize(resource.MemorySize).SetCpu(resource.CPU).SetInstanceType(resource.InstanceType).
 					SetTimeout(resource.Timeout).SetCustomContainerConfig(new(fc.CustomContainerConfig).
-					SetImage(resource.Image)).SetEnvironmentVariables(resource.Env)); err != nil {
-				fail = append(fail, functionName)
-				errs = append(errs, err.Error())
-
-			} else {
-				success = append(success, key)
-			}
+					SetImage(resource.Image)).SetEnvironmentVariables(resource.Env))
+			return err
+		}); err != nil {
+			fail = append(fail, functionName)
+			errs = append(errs, err.Error())
+		} else {
+			success = append(success, key)
+			f.gpuMemoryLock.Lock()
+			delete(f.gpuMemory, key)
+			delete(f.gpuMemoryMiss, key)
+			f.gpuMemoryLock.Unlock()
 		}
 	}
 	return success, fail, errs
@@ -262,6 +425,25 @@ func (f *FuncManager) DeleteFunction(functions []string) ([]string, []string) {
 	}
 }
 
+// ColdStartBudget current cold start budget
+func (f *FuncManager) ColdStartBudget() concurrency.BudgetStatus {
+	if f.coldStartBudget == nil {
+		return concurrency.BudgetStatus{}
+	}
+	return f.coldStartBudget.Status()
+}
+
+// keys of cached endpoints
+func (f *FuncManager) endpointKeys() []string {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+	keys := make([]string, 0, len(f.endpoints))
+	for key := range f.endpoints {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
 // get endpoint from cache
 func (f *FuncManager) getEndpointFromCache(key string) string {
 	f.lock.RLock()
@@ -289,22 +471,47 @@ func (f *FuncManager) createFunc(key, sdModel string, env map[string]*string) (s
 	functionName := GetFunctionName(key)
 	var endpoint string
 	var err error
-	if isFc3() {
-		endpoint, err = f.createFc3Function(functionName, env)
-	} else {
-		serviceName := config.ConfigGlobal.ServiceName
-		endpoint, err = f.createFCFunction(serviceName, functionName, env)
+	// slow models need longer timeout, long render not killed
+	timeout := config.ConfigGlobal.GetFuncTimeout(sdModel)
+	instanceTypes := config.ConfigGlobal.GetInstanceTypes()
+	for i, instanceType := range instanceTypes {
+		err = f.withCredentialRetry(func() (err error) {
+			if isFc3() {
+				endpoint, err = f.createFc3Function(functionName, instanceType, timeout, env)
+			} else {
+				serviceName := config.ConfigGlobal.ServiceName
+				endpoint, err = f.createFCFunction(serviceName, functionName, instanceType, timeout, env)
+			}
+			return err
+		})
+		if err == nil && endpoint != "" {
+			// update cache
+			f.endpoints[key] = []string{endpoint, sdModel}
+			// put func to db
+			f.putFunc(key, functionName, sdModel, endpoint, instanceType)
+			return endpoint, nil
+		}
+		if err == nil || !isCapacityError(err) || i == len(instanceTypes)-1 {
+			break
+		}
+		logrus.Warnf("functionName:%s instanceType:%s capacity not enough, fallback to %s, err=%s",
+			functionName, instanceType, instanceTypes[i+1], err.Error())
 	}
-	if err == nil && endpoint != "" {
-		// update cache
-		f.endpoints[key] = []string{endpoint, sdModel}
-		// put func to db
-		f.putFunc(key, functionName, sdModel, endpoint)
-		return endpoint, nil
-	} else {
-		logrus.Info(err.Error())
-		return "", err
+	if err == nil {
+		err = fmt.Errorf("create function %s fail, endpoint empty", functionName)
+	}
+	logrus.Info(err.Error())
+	return "", err
+}
+
+// capacity error, can retry with other instance type
+func isCapacityError(err error) bool {
+	var sdkErr *tea.SDKError
+	if !errors.As(err, &sdkErr) || sdkErr.Code == nil {
+		return false
 	}
+	_, ok := capacityErrorCodes[*sdkErr.Code]
+	return ok
 }
 
 // GetFcFuncEnv get fc function env info
@@ -350,67 +557,198 @@ func (f *FuncManager) GetFuncResource(functionName string) *FuncResource {
 	return nil
 }
 
+// GetGpuMemorySize gpu memory(MB) of function serve sdModel, config gpuMemorySize when function not created,
+// lookup cached, miss cached for gpuMemoryMissTTL
+func (f *FuncManager) GetGpuMemorySize(sdModel string) int32 {
+	key := "default"
+	if config.ConfigGlobal.GetFlexMode() == config.MultiFunc && sdModel != "" {
+		key = sdModel
+	}
+	f.gpuMemoryLock.Lock()
+	size, ok := f.gpuMemory[key]
+	missExpire, miss := f.gpuMemoryMiss[key]
+	f.gpuMemoryLock.Unlock()
+	if ok {
+		return size
+	}
+	if miss && time.Now().Before(missExpire) {
+		return config.ConfigGlobal.GpuMemorySize
+	}
+	resource := funcResource(f, GetFunctionName(key))
+	f.gpuMemoryLock.Lock()
+	defer f.gpuMemoryLock.Unlock()
+	if resource == nil || resource.GpuMemorySize <= 0 {
+		// created later with config gpuMemorySize, look up again after ttl
+		if f.gpuMemoryMiss == nil {
+			f.gpuMemoryMiss = make(map[string]time.Time)
+		}
+		f.gpuMemoryMiss[key] = time.Now().Add(gpuMemoryMissTTL)
+		return config.ConfigGlobal.GpuMemorySize
+	}
+	if f.gpuMemory == nil {
+		f.gpuMemory = make(map[string]int32)
+	}
+	f.gpuMemory[key] = resource.GpuMemorySize
+	delete(f.gpuMemoryMiss, key)
+	return resource.GpuMemorySize
+}
+
 // GetFcFunc  get fc function info
 func (f *FuncManager) GetFcFunc(functionName string) interface{} {
-	if isFc3() {
-		if resp, err := f.fc3Client.GetFunction(&functionName, &fc3.GetFunctionRequest{}); err == nil {
-			return resp
+	var ret interface{}
+	f.withCredentialRetry(func() error {
+		if isFc3() {
+			resp, err := f.getFc3Client().GetFunction(&functionName, &fc3.GetFunctionRequest{})
+			if err == nil {
+				ret = resp
+			}
+			return err
 		}
-	} else {
 		serviceName := config.ConfigGlobal.ServiceName
-		if resp, err := f.fcClient.GetFunction(&serviceName, &functionName, &fc.GetFunctionRequest{}); err == nil {
-			return resp
+		resp, err := f.getFcClient().GetFunction(&serviceName, &functionName, &fc.GetFunctionRequest{})
+		if err == nil {
+			ret = resp
 		}
-	}
-	return nil
+		return err
+	})
+	return ret
 }
 
-// load endpoint from db
-func (f *FuncManager) loadFunc() {
-	// load func from db
-	funcAll, _ := f.funcStore.ListAll([]string{datastore.KModelServiceKey, datastore.KModelServiceEndPoint,
+// manifest function item of db
+type manifestItem struct {
+	key      string
+	sdModel  string
+	endpoint string
+	valid    bool
+}
+
+// check function exist in fc, mock in test
+var funcExist = func(f *FuncManager, functionName string) bool {
+	return f.GetFcFunc(functionName) != nil
+}
+
+// get function resource from fc, mock in test
+var funcResource = func(f *FuncManager, functionName string) *FuncResource {
+	return f.GetFuncResource(functionName)
+}
+
+// loadManifest list func from db, validate endpoint and fc function concurrently
+func (f *FuncManager) loadManifest() ([]*manifestItem, error) {
+	funcAll, err := f.funcStore.ListAll([]string{datastore.KModelServiceKey, datastore.KModelServiceEndPoint,
 		datastore.KModelServiceSdModel, datastore.KModelServerImage})
+	if err != nil {
+		return nil, err
+	}
+	items := make([]*manifestItem, 0, len(funcAll))
 	for _, data := range funcAll {
-		key := data[datastore.KModelServiceKey].(string)
-		sdModel := data[datastore.KModelServiceSdModel].(string)
-		// check fc && db match
-		functionName := GetFunctionName(sdModel)
-		if f.GetFcFunc(functionName) == nil {
-			logrus.Errorf("functionName:%s, sdModel:%s function in db, not in FC, please delete ots table fucntion "+
-				"key=%s", functionName, sdModel, sdModel)
-			// function in db not in FC， del ots data
-			//f.funcStore.Delete(sdModel)
+		key, _ := data[datastore.KModelServiceKey].(string)
+		sdModel, _ := data[datastore.KModelServiceSdModel].(string)
+		endpoint, _ := data[datastore.KModelServiceEndPoint].(string)
+		items = append(items, &manifestItem{key: key, sdModel: sdModel, endpoint: endpoint})
+	}
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, MANIFEST_CONCURRENCY)
+	for _, item := range items {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(item *manifestItem) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			item.valid = f.validFunc(item)
+		}(item)
+	}
+	wg.Wait()
+	return items, nil
+}
+
+// validFunc endpoint is http url and function exist in fc
+func (f *FuncManager) validFunc(item *manifestItem) bool {
+	if u, err := url.Parse(item.endpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") ||
+		u.Host == "" {
+		logrus.Errorf("key:%s, sdModel:%s endpoint %q in db invalid", item.key, item.sdModel, item.endpoint)
+		return false
+	}
+	functionName := GetFunctionName(item.sdModel)
+	if !funcExist(f, functionName) {
+		logrus.Errorf("functionName:%s, sdModel:%s function in db, not in FC, please delete ots table fucntion "+
+			"key=%s", functionName, item.sdModel, item.sdModel)
+		return false
+	}
+	return true
+}
+
+// load endpoint from db, preload all functions on start
+func (f *FuncManager) loadFunc() {
+	items, err := f.loadManifest()
+	if err != nil {
+		logrus.Errorf("load function from db err=%s", err.Error())
+		return
+	}
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	for _, item := range items {
+		if !item.valid {
 			continue
 		}
-		//image := data[datastore.KModelServerImage].(string)
-		//if image != "" && config.ConfigGlobal.Image != "" &&
-		//	image != config.ConfigGlobal.Image {
-		//	// update function image
-		//	if err := f.UpdateFunctionImage(key); err != nil {
-		//		logrus.Info("update function image err=", err.Error())
-		//	}
-		//	// update db
-		//	f.funcStore.Update(key, map[string]interface{}{
-		//		datastore.KModelServerImage: config.ConfigGlobal.Image,
-		//		datastore.KModelModifyTime:  fmt.Sprintf("%d", utils.TimestampS()),
-		//	})
-		//}
-		endpoint := data[datastore.KModelServiceEndPoint].(string)
 		// init lastInvokeEndpoint
 		if f.lastInvokeEndpoint == "" {
-			f.lastInvokeEndpoint = endpoint
+			f.lastInvokeEndpoint = item.endpoint
 		}
-		f.endpoints[key] = []string{endpoint, sdModel}
+		f.endpoints[item.key] = []string{item.endpoint, item.sdModel}
 	}
+	logrus.Infof("preload %d function endpoints", len(f.endpoints))
+}
+
+// refreshFunc sync cache with db, drop function removed from fc
+// key not in db keep, may created after list
+func (f *FuncManager) refreshFunc() {
+	items, err := f.loadManifest()
+	if err != nil {
+		logrus.Warnf("refresh function from db err=%s", err.Error())
+		return
+	}
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	for _, item := range items {
+		if item.valid {
+			f.endpoints[item.key] = []string{item.endpoint, item.sdModel}
+		} else if val, ok := f.endpoints[item.key]; ok && val[0] == item.endpoint {
+			delete(f.endpoints, item.key)
+		}
+	}
+}
+
+// run refresh every interval until Close
+func (f *FuncManager) runRefresh(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-f.stop:
+			return
+		case <-ticker.C:
+			f.refreshFunc()
+		}
+	}
+}
+
+// Close stop background refresh
+func (f *FuncManager) Close() {
+	f.stopOnce.Do(func() {
+		close(f.stop)
+	})
 }
 
 // write func into db
-func (f *FuncManager) putFunc(key, functionName, sdModel, endpoint string) {
+func (f *FuncManager) putFunc(key, functionName, sdModel, endpoint, instanceType string) {
 	f.funcStore.Put(key, map[string]interface{}{
 		datastore.KModelServiceKey:            key,
 		datastore.KModelServiceSdModel:        sdModel,
 		datastore.KModelServiceFunctionName:   functionName,
 		datastore.KModelServiceEndPoint:       endpoint,
+		datastore.KModelServiceInstanceType:   instanceType,
 		datastore.KModelServiceCreateTime:     fmt.Sprintf("%d", utils.TimestampS()),
 		datastore.KModelServiceLastModifyTime: fmt.Sprintf("%d", utils.TimestampS()),
 	})
@@ -446,7 +784,7 @@ func (f *FuncManager) ListFunction() *project.T {
 
 func GetHttpTrigger(functionName string) string {
 	if isFc3() {
-		if result, err := FuncManagerGlobal.fc3Client.ListTriggers(&functionName, new(fc3.ListTriggersRequest)); err == nil {
+		if result, err := FuncManagerGlobal.getFc3Client().ListTriggers(&functionName, new(fc3.ListTriggersRequest)); err == nil {
 			for _, trigger := range result.Body.Triggers {
 				if trigger.HttpTrigger != nil {
 					return *trigger.HttpTrigger.UrlInternet
@@ -454,7 +792,7 @@ func GetHttpTrigger(functionName string) string {
 			}
 		}
 	} else {
-		if result, err := FuncManagerGlobal.fcClient.ListTriggers(&config.ConfigGlobal.ServiceName,
+		if result, err := FuncManagerGlobal.getFcClient().ListTriggers(&config.ConfigGlobal.ServiceName,
 			&functionName, new(fc.ListTriggersRequest)); err == nil {
 			for _, trigger := range result.Body.Triggers {
 				if trigger.UrlInternet != nil {
@@ -468,20 +806,20 @@ func GetHttpTrigger(functionName string) string {
 
 // ---------fc2.0----------
 // create fc function
-func (f *FuncManager) createFCFunction(serviceName, functionName string,
+func (f *FuncManager) createFCFunction(serviceName, functionName, instanceType string, timeout int32,
 	env map[string]*string) (endpoint string, err error) {
-	createRequest := getCreateFuncRequest(functionName, env)
+	createRequest := getCreateFuncRequest(functionName, instanceType, timeout, env)
 	header := &fc.CreateFunctionHeaders{
 		XFcAccountId: utils.String(config.ConfigGlobal.AccountId),
 	}
 	// create function
-	if _, err := f.fcClient.CreateFunctionWithOptions(&serviceName, createRequest,
+	if _, err := f.getFcClient().CreateFunctionWithOptions(&serviceName, createRequest,
 		header, &fcService.RuntimeOptions{}); err != nil {
 		return "", err
 	}
 	// create http triggers
 	httpTriggerRequest := getHttpTrigger()
-	resp, err := f.fcClient.CreateTrigger(&serviceName, &functionName, httpTriggerRequest)
+	resp, err := f.getFcClient().CreateTrigger(&serviceName, &functionName, httpTriggerRequest)
 	if err != nil {
 		return "", err
 	}
@@ -490,13 +828,14 @@ func (f *FuncManager) createFCFunction(serviceName, functionName string,
 }
 
 // get create function request
-func getCreateFuncRequest(functionName string, env map[string]*string) *fc.CreateFunctionRequest {
+func getCreateFuncRequest(functionName, instanceType string, timeout int32,
+	env map[string]*string) *fc.CreateFunctionRequest {
 	defaultReq := &fc.CreateFunctionRequest{
 		FunctionName:         utils.String(functionName),
 		CaPort:               utils.Int32(config.ConfigGlobal.CAPort),
 		Cpu:                  utils.Float32(config.ConfigGlobal.CPU),
-		Timeout:              utils.Int32(config.ConfigGlobal.Timeout),
-		InstanceType:         utils.String(config.ConfigGlobal.InstanceType),
+		Timeout:              utils.Int32(timeout),
+		InstanceType:         utils.String(instanceType),
 		Runtime:              utils.String("custom-container"),
 		InstanceConcurrency:  utils.Int32(config.ConfigGlobal.InstanceConcurrency),
 		MemorySize:           utils.Int32(config.ConfigGlobal.MemorySize),
@@ -506,9 +845,9 @@ func getCreateFuncRequest(functionName string, env map[string]*string) *fc.Creat
 		EnvironmentVariables: env,
 		CustomContainerConfig: &fc.CustomContainerConfig{
 			Command:          utils.String("/docker/entrypoint.sh"),
-			AccelerationType: utils.String("Default"),
+			AccelerationType: utils.String(config.ConfigGlobal.AccelerationType),
 			Image:            utils.String(config.ConfigGlobal.Image),
-			WebServerMode:    utils.Bool(true),
+			WebServerMode:    utils.Bool(config.ConfigGlobal.IsWebServerMode()),
 		},
 	}
 	if sd := FuncManagerGlobal.GetSd(); sd != nil {
@@ -533,8 +872,8 @@ func getCreateFuncRequest(functionName string, env map[string]*string) *fc.Creat
 
 func (f *FuncManager) delFunction(functionNames []string) (fails []string, errs []string) {
 	for _, functionName := range functionNames {
-		f.fcClient.DeleteTrigger(&config.ConfigGlobal.ServiceName, &functionName, utils.String(config.TRIGGER_NAME))
-		if _, err := f.fcClient.DeleteFunction(&config.ConfigGlobal.ServiceName, &functionName); err != nil {
+		f.getFcClient().DeleteTrigger(&config.ConfigGlobal.ServiceName, &functionName, utils.String(config.TRIGGER_NAME))
+		if _, err := f.getFcClient().DeleteFunction(&config.ConfigGlobal.ServiceName, &functionName); err != nil {
 			logrus.Warnf("%s delete fail, err: %s", functionName, err.Error())
 			fails = append(fails, functionName)
 			errs = append(errs, err.Error())
@@ -559,19 +898,19 @@ func getHttpTrigger() *fc.CreateTriggerRequest {
 // ------------end fc2.0----------
 
 // --------------fc3.0--------------
-func (f *FuncManager) createFc3Function(functionName string,
+func (f *FuncManager) createFc3Function(functionName, instanceType string, timeout int32,
 	env map[string]*string) (endpoint string, err error) {
-	createRequest := f.getCreateFuncRequestFc3(functionName, env)
+	createRequest := f.getCreateFuncRequestFc3(functionName, instanceType, timeout, env)
 	if createRequest == nil {
 		return "", errors.New("get createFunctionRequest error")
 	}
 	// create function
-	if _, err := f.fc3Client.CreateFunction(createRequest); err != nil {
+	if _, err := f.getFc3Client().CreateFunction(createRequest); err != nil {
 		return "", err
 	}
 	// create http triggers
 	httpTriggerRequest := getHttpTriggerFc3()
-	resp, err := f.fc3Client.CreateTrigger(&functionName, httpTriggerRequest)
+	resp, err := f.getFc3Client().CreateTrigger(&functionName, httpTriggerRequest)
 	if err != nil {
 		return "", err
 	}
@@ -579,7 +918,8 @@ func (f *FuncManager) createFc3Function(functionName string,
 }
 
 // fc3.0 get create function request
-func (f *FuncManager) getCreateFuncRequestFc3(functionName string, env map[string]*string) *fc3.CreateFunctionRequest {
+func (f *FuncManager) getCreateFuncRequestFc3(functionName, instanceType string, timeout int32,
+	env map[string]*string) *fc3.CreateFunctionRequest {
 	// get current function
 	function := f.GetFcFunc(config.ConfigGlobal.FunctionName)
 	if function == nil {
@@ -589,7 +929,7 @@ func (f *FuncManager) getCreateFuncRequestFc3(functionName string, env map[strin
 	input := &fc3.CreateFunctionInput{
 		FunctionName:         utils.String(functionName),
 		Cpu:                  utils.Float32(config.ConfigGlobal.CPU),
-		Timeout:              utils.Int32(config.ConfigGlobal.Timeout),
+		Timeout:              utils.Int32(timeout),
 		Runtime:              utils.String("custom-container"),
 		InstanceConcurrency:  utils.Int32(config.ConfigGlobal.InstanceConcurrency),
 		MemorySize:           utils.Int32(config.ConfigGlobal.MemorySize),
@@ -598,19 +938,22 @@ func (f *FuncManager) getCreateFuncRequestFc3(functionName string, env map[strin
 		Handler:              utils.String("index.handler"),
 		CustomContainerConfig: &fc3.CustomContainerConfig{
 			Entrypoint:       []*string{utils.String("/docker/entrypoint.sh")},
-			AccelerationType: utils.String("Default"),
+			AccelerationType: utils.String(config.ConfigGlobal.AccelerationType),
 			Image:            utils.String(config.ConfigGlobal.Image),
-			Port:             utils.Int32(config.ConfigGlobal.CAPort),
 		},
 		GpuConfig: &fc3.GPUConfig{
 			GpuMemorySize: utils.Int32(config.ConfigGlobal.GpuMemorySize),
-			GpuType:       utils.String(config.ConfigGlobal.InstanceType),
+			GpuType:       utils.String(instanceType),
 		},
 		Role:           curFunction.Body.Role,
 		VpcConfig:      curFunction.Body.VpcConfig,
 		NasConfig:      curFunction.Body.NasConfig,
 		OssMountConfig: curFunction.Body.OssMountConfig,
 	}
+	// fc3.0 not support web server mode option, not web server no port
+	if config.ConfigGlobal.IsWebServerMode() {
+		input.CustomContainerConfig.Port = utils.Int32(config.ConfigGlobal.CAPort)
+	}
 	if sd := FuncManagerGlobal.GetSd(); sd != nil {
 		if config.ConfigGlobal.Image == "" {
 			input.CustomContainerConfig.Image = sd.CustomContainerConfig.Image
@@ -630,7 +973,7 @@ func (f *FuncManager) getCreateFuncRequestFc3(functionName string, env map[strin
 // delete function
 func (f *FuncManager) delFunctionFC3(functionNames []string) (fails []string, errs []string) {
 	for _, functionName := range functionNames {
-		if _, err := f.fc3Client.DeleteFunction(&functionName); err != nil {
+		if _, err := f.getFc3Client().DeleteFunction(&functionName); err != nil {
 			logrus.Warnf("%s delete fail, err: %s", functionName, err.Error())
 			fails = append(fails, functionName)
 			errs = append(errs, err.Error())
@@ -664,15 +1007,25 @@ func GetFunctionName(key string) string {
 
 func getEnv(sdModel string) map[string]*string {
 	env := map[string]*string{
-		config.SD_START_PARAMS:      utils.String(config.ConfigGlobal.ExtraArgs),
+		config.SD_START_PARAMS:      utils.String(config.ConfigGlobal.GetExtraArgs(sdModel)),
 		config.MODEL_SD:             utils.String(sdModel),
 		config.MODEL_REFRESH_SIGNAL: utils.String(fmt.Sprintf("%d", utils.TimestampS())), // value = now timestamp
 		config.OTS_INSTANCE:         utils.String(config.ConfigGlobal.OtsInstanceName),
 		config.OTS_ENDPOINT:         utils.String(config.ConfigGlobal.OtsEndpoint),
 	}
+	// function proxy request webui started with --api-auth
+	if config.ConfigGlobal.SdApiAuth != "" {
+		env[config.SD_API_AUTH] = utils.String(config.ConfigGlobal.SdApiAuth)
+	}
 	if config.ConfigGlobal.OssMode == config.REMOTE {
 		env[config.OSS_ENDPOINT] = utils.String(config.ConfigGlobal.OssEndpoint)
 		env[config.OSS_BUCKET] = utils.String(config.ConfigGlobal.Bucket)
+		if config.ConfigGlobal.ModelBucket != "" {
+			env[config.OSS_MODEL_BUCKET] = utils.String(config.ConfigGlobal.ModelBucket)
+		}
+		if config.ConfigGlobal.OutputBucket != "" {
+			env[config.OSS_OUTPUT_BUCKET] = utils.String(config.ConfigGlobal.OutputBucket)
+		}
 	}
 	return env
 }
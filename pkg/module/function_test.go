@@ -1,10 +1,17 @@
 package module
 
 import (
+	"errors"
+	"fmt"
+	"github.com/alibabacloud-go/tea/tea"
 	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/datastore"
 	"github.com/devsapp/serverless-stable-diffusion-api/pkg/utils"
 	"github.com/stretchr/testify/assert"
+	"path/filepath"
+	"sync"
 	"testing"
+	"time"
 )
 
 func TestFunction(t *testing.T) {
@@ -15,7 +22,109 @@ func TestFunction(t *testing.T) {
 	env := map[string]*string{
 		"EXTRA_ARGS": utils.String("--api"),
 	}
-	endpoint, err := FuncManagerGlobal.createFCFunction(config.ConfigGlobal.ServiceName, functionName, env)
+	endpoint, err := FuncManagerGlobal.createFCFunction(config.ConfigGlobal.ServiceName, functionName,
+		config.ConfigGlobal.InstanceType, config.ConfigGlobal.Timeout, env)
 	assert.Nil(t, err)
 	assert.NotEqual(t, endpoint, "")
 }
+
+func TestIsCapacityError(t *testing.T) {
+	sdkError := func(code, message string) error {
+		return tea.NewSDKError(map[string]interface{}{"code": code, "message": message})
+	}
+	assert.True(t, isCapacityError(sdkError("ResourceExhausted", "gpu resource exhausted")))
+	assert.True(t, isCapacityError(fmt.Errorf("create function: %w", sdkError("ResourceNotEnough", "no gpu"))))
+	assert.False(t, isCapacityError(sdkError("InvalidArgument", "image not found")))
+	// keyword in message of other error not capacity
+	assert.False(t, isCapacityError(sdkError("InvalidArgument", "memory exceeds capacity of instance type")))
+	assert.False(t, isCapacityError(errors.New("ResourceExhausted: insufficient capacity")))
+}
+
+func TestLoadFunc(t *testing.T) {
+	config.ConfigGlobal = &config.Config{ConfigYaml: config.ConfigYaml{
+		DbSqlite: filepath.Join(t.TempDir(), "sqlite3"),
+		FlexMode: "multiFunc",
+	}}
+	funcStore := datastore.NewSQLiteDatastore(datastore.NewSQLiteConfig(datastore.KModelServiceTableName))
+	defer funcStore.Close()
+	f := &FuncManager{endpoints: make(map[string][]string), funcStore: funcStore, stop: make(chan struct{})}
+	oldManager := FuncManagerGlobal
+	FuncManagerGlobal = f
+	defer func() {
+		FuncManagerGlobal = oldManager
+	}()
+	// functions exist in fc
+	var lock sync.Mutex
+	fcFuncs := map[string]bool{GetFunctionName("a"): true, GetFunctionName("b"): true, GetFunctionName("c"): true}
+	old := funcExist
+	funcExist = func(f *FuncManager, functionName string) bool {
+		lock.Lock()
+		defer lock.Unlock()
+		return fcFuncs[functionName]
+	}
+	defer func() {
+		funcExist = old
+	}()
+	f.putFunc("a", GetFunctionName("a"), "a", "http://a.fc.com", "fc.gpu.tesla.1")
+	f.putFunc("b", GetFunctionName("b"), "b", "not url", "fc.gpu.tesla.1")
+	f.putFunc("d", GetFunctionName("d"), "d", "http://d.fc.com", "fc.gpu.tesla.1")
+
+	// preload only valid function
+	f.loadFunc()
+	assert.Equal(t, map[string][]string{"a": {"http://a.fc.com", "a"}}, f.endpoints)
+	assert.Equal(t, "http://a.fc.com", f.getEndpointFromCache("a"))
+	token := StickyToken("http://a.fc.com")
+	assert.Equal(t, "http://a.fc.com", f.GetStickyEndpoint(token, "a"))
+	assert.Empty(t, f.GetStickyEndpoint(StickyToken("http://d.fc.com"), "d"))
+	// function not serve other model
+	assert.Empty(t, f.GetStickyEndpoint(token, "c"))
+
+	// function created by other control, function a deleted from fc
+	f.putFunc("c", GetFunctionName("c"), "c", "https://c.fc.com", "fc.gpu.tesla.1")
+	lock.Lock()
+	delete(fcFuncs, GetFunctionName("a"))
+	lock.Unlock()
+	f.refreshFunc()
+	assert.Equal(t, map[string][]string{"c": {"https://c.fc.com", "c"}}, f.endpoints)
+	// pinned function gone
+	assert.Empty(t, f.GetStickyEndpoint(token, "a"))
+
+	f.Close()
+	f.Close()
+}
+
+func TestGetGpuMemorySize(t *testing.T) {
+	config.ConfigGlobal = &config.Config{ConfigYaml: config.ConfigYaml{
+		FlexMode:      "multiFunc",
+		GpuMemorySize: 16384,
+	}}
+	f := &FuncManager{}
+	oldManager := FuncManagerGlobal
+	FuncManagerGlobal = f
+	defer func() {
+		FuncManagerGlobal = oldManager
+	}()
+	lookups := make(map[string]int)
+	resources := map[string]*FuncResource{GetFunctionName("sd_xl"): {GpuMemorySize: 24576}}
+	old := funcResource
+	funcResource = func(f *FuncManager, functionName string) *FuncResource {
+		lookups[functionName]++
+		return resources[functionName]
+	}
+	defer func() {
+		funcResource = old
+	}()
+
+	assert.Equal(t, int32(24576), f.GetGpuMemorySize("sd_xl"))
+	assert.Equal(t, int32(24576), f.GetGpuMemorySize("sd_xl"))
+	assert.Equal(t, 1, lookups[GetFunctionName("sd_xl")])
+	// function not created, miss cached
+	assert.Equal(t, int32(16384), f.GetGpuMemorySize("sd15"))
+	assert.Equal(t, int32(16384), f.GetGpuMemorySize("sd15"))
+	assert.Equal(t, 1, lookups[GetFunctionName("sd15")])
+	// miss expired, look up again
+	resources[GetFunctionName("sd15")] = &FuncResource{GpuMemorySize: 8192}
+	f.gpuMemoryMiss["sd15"] = time.Now()
+	assert.Equal(t, int32(8192), f.GetGpuMemorySize("sd15"))
+	assert.Equal(t, 2, lookups[GetFunctionName("sd15")])
+}
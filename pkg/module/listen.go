@@ -1,6 +1,7 @@
 package module
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -13,6 +14,7 @@ import (
 	"net/http"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -50,8 +52,14 @@ type ListenDbTask struct {
 	modelStore     datastore.Datastore
 	configStore    datastore.Datastore
 	intervalSecond int32
-	tasks          *sync.Map
-	stop           chan struct{}
+	// adaptive poll bounds
+	minInterval time.Duration
+	maxInterval time.Duration
+	// current poll interval of listen loop
+	interval atomic.Int64
+	tasks    *sync.Map
+	stop     chan struct{}
+	stopOnce sync.Once
 }
 
 func NewListenDbTask(intervalSecond int32, taskStore datastore.Datastore,
@@ -61,39 +69,76 @@ func NewListenDbTask(intervalSecond int32, taskStore datastore.Datastore,
 		modelStore:     modelStore,
 		configStore:    configStore,
 		intervalSecond: intervalSecond,
+		minInterval:    time.Duration(config.ConfigGlobal.ListenMinInterval) * time.Millisecond,
+		maxInterval:    time.Duration(config.ConfigGlobal.ListenMaxInterval) * time.Millisecond,
 		tasks:          new(sync.Map),
 		stop:           make(chan struct{}),
 	}
-	//go listenTask.init()
+	if listenTask.minInterval <= 0 {
+		listenTask.minInterval = config.DefaultListenMinInterval * time.Millisecond
+	}
+	if listenTask.maxInterval < listenTask.minInterval {
+		listenTask.maxInterval = listenTask.minInterval
+	}
+	go listenTask.init()
 	return listenTask
 }
 
-// init listen
+// init listen, poll at minInterval when tasks pending, back off exponentially to maxInterval when idle
 func (l *ListenDbTask) init() {
+	interval := time.Duration(l.intervalSecond) * time.Second
 	for {
+		busy := l.listenOnce()
+		interval = nextListenInterval(interval, busy, l.minInterval, l.maxInterval)
+		l.interval.Store(int64(interval))
 		select {
 		case <-l.stop:
-			break
-		default:
-			// go on next
+			return
+		case <-time.After(interval):
 		}
-		l.tasks.Range(func(key, value any) bool {
-			taskId := key.(string)
-			taskItem := value.(*TaskItem)
-			switch taskItem.listenType {
-			case CancelListen:
-				l.cancelTask(taskId, taskItem)
-			case ModelListen:
-				l.modelTask(taskItem)
-			case ConfigListen:
-				l.configTask(taskItem)
-			}
-			return true
-		})
-		time.Sleep(time.Duration(l.intervalSecond) * time.Second)
 	}
 }
 
+// currentInterval poll interval of listen loop, 0 before first poll
+func (l *ListenDbTask) currentInterval() time.Duration {
+	return time.Duration(l.interval.Load())
+}
+
+// listenOnce check all listen tasks, return true when task cancel listen pending
+func (l *ListenDbTask) listenOnce() bool {
+	busy := false
+	l.tasks.Range(func(key, value any) bool {
+		taskId := key.(string)
+		taskItem := value.(*TaskItem)
+		switch taskItem.listenType {
+		case CancelListen:
+			busy = true
+			l.cancelTask(taskId, taskItem)
+		case ModelListen:
+			l.modelTask(taskItem)
+		case ConfigListen:
+			l.configTask(taskItem)
+		}
+		return true
+	})
+	return busy
+}
+
+// nextListenInterval busy use min, idle double current interval, keep in [min, max]
+func nextListenInterval(cur time.Duration, busy bool, min, max time.Duration) time.Duration {
+	if busy {
+		return min
+	}
+	next := cur * 2
+	if next > max {
+		next = max
+	}
+	if next < min {
+		next = min
+	}
+	return next
+}
+
 // listen config
 func (l *ListenDbTask) configTask(item *TaskItem) {
 	confSignal := item.curVal.(*configSignal)
@@ -128,7 +173,7 @@ func (l *ListenDbTask) configTask(item *TaskItem) {
 func (l *ListenDbTask) modelTask(item *TaskItem) {
 	// controlNet
 	oldVal := *(item.curVal.(*map[string]struct{}))
-	path := fmt.Sprintf("%s/models/%s", config.ConfigGlobal.SdPath, "ControlNet")
+	path := config.ConfigGlobal.GetModelDir(config.CONTORLNET_MODEL)
 	curVal := listModelFile(path)
 	add, del := utils.DiffSet(oldVal, curVal)
 	if len(add) != 0 || len(del) != 0 {
@@ -139,7 +184,7 @@ func (l *ListenDbTask) modelTask(item *TaskItem) {
 	}
 	// vae
 	if oldVal, err := getVaeFromSD(); err == nil {
-		path := fmt.Sprintf("%s/models/%s", config.ConfigGlobal.SdPath, "VAE")
+		path := config.ConfigGlobal.GetModelDir(config.SD_VAE)
 		curVal := listModelFile(path)
 		add, del := utils.DiffSet(oldVal, curVal)
 		if len(add) != 0 || len(del) != 0 {
@@ -150,7 +195,7 @@ func (l *ListenDbTask) modelTask(item *TaskItem) {
 	}
 	// checkpoint
 	if oldVal, err := getCheckPointFromSD(); err == nil {
-		path := fmt.Sprintf("%s/models/%s", config.ConfigGlobal.SdPath, "Stable-diffusion")
+		path := config.ConfigGlobal.GetModelDir(config.SD_MODEL)
 		curVal := listModelFile(path)
 		add, del := utils.DiffSet(oldVal, curVal)
 		if len(add) != 0 || len(del) != 0 {
@@ -170,7 +215,7 @@ func (l *ListenDbTask) cancelTask(taskId string, item *TaskItem) {
 	}
 	// check task finish delete db listen task
 	status := ret[datastore.KTaskStatus].(string)
-	if status == config.TASK_FINISH || status == config.TASK_FAILED {
+	if IsTaskTerminal(status) {
 		l.tasks.Delete(taskId)
 		return
 	}
@@ -189,7 +234,7 @@ func (l *ListenDbTask) AddTask(key string, listenType ListenType, callBack CallB
 	var curVal interface{}
 	if listenType == ModelListen {
 		// controlNet load and other type model not need
-		path := fmt.Sprintf("%s/models/%s", config.ConfigGlobal.SdPath, "ControlNet")
+		path := config.ConfigGlobal.GetModelDir(config.CONTORLNET_MODEL)
 		datas := listModelFile(path)
 		val := make(map[string]struct{})
 		for data := range datas {
@@ -252,7 +297,9 @@ func (l *ListenDbTask) AddTask(key string, listenType ListenType, callBack CallB
 
 // Close listen
 func (l *ListenDbTask) Close() {
-	l.stop <- struct{}{}
+	l.stopOnce.Do(func() {
+		close(l.stop)
+	})
 }
 
 func listModelFile(path string) map[string]struct{} {
@@ -327,8 +374,16 @@ func getVaeFromSD() (map[string]struct{}, error) {
 }
 
 func getCheckPointFromSD() (map[string]struct{}, error) {
-	url := fmt.Sprintf("%s%s", config.ConfigGlobal.SdUrlPrefix, config.GET_SD_MODEL)
-	req, err := http.NewRequest("GET", url, nil)
+	return listCheckPoints(context.Background(), config.ConfigGlobal.SdUrlPrefix)
+}
+
+// listCheckPoints checkpoints of webui at endpoint, key is title without hash
+func listCheckPoints(ctx context.Context, endpoint string) (map[string]struct{}, error) {
+	url := fmt.Sprintf("%s%s", endpoint, config.GET_SD_MODEL)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,99 @@
+package module
+
+import (
+	"fmt"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/datastore"
+	"github.com/stretchr/testify/assert"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNextListenInterval(t *testing.T) {
+	min, max := 200*time.Millisecond, 10*time.Second
+	// pending tasks poll fast
+	assert.Equal(t, min, nextListenInterval(5*time.Second, true, min, max))
+	// idle back off exponentially
+	assert.Equal(t, 400*time.Millisecond, nextListenInterval(min, false, min, max))
+	assert.Equal(t, 2*time.Second, nextListenInterval(time.Second, false, min, max))
+	assert.Equal(t, max, nextListenInterval(8*time.Second, false, min, max))
+	assert.Equal(t, min, nextListenInterval(0, false, min, max))
+}
+
+func TestListenCancelTask(t *testing.T) {
+	config.ConfigGlobal = &config.Config{ConfigYaml: config.ConfigYaml{
+		DbSqlite:          filepath.Join(t.TempDir(), "sqlite3"),
+		ListenMinInterval: 10,
+		ListenMaxInterval: 1000,
+	}}
+	taskStore := datastore.NewSQLiteDatastore(datastore.NewSQLiteConfig(datastore.KTaskTableName))
+	defer taskStore.Close()
+	assert.Nil(t, taskStore.Put("task", map[string]interface{}{
+		datastore.KTaskIdColumnName: "task",
+		datastore.KTaskStatus:       config.TASK_INPROGRESS,
+		datastore.KTaskCancel:       int64(config.CANCEL_INIT),
+		datastore.KTaskCreateTime:   fmt.Sprintf("%d", time.Now().Unix()),
+	}))
+	l := NewListenDbTask(1, taskStore, nil, nil)
+	canceled := make(chan struct{})
+	var once sync.Once
+	l.AddTask("task", CancelListen, func(v any) {
+		once.Do(func() {
+			close(canceled)
+		})
+	})
+	defer l.Close()
+
+	// pending task poll at min interval, cancel found quickly
+	assert.True(t, l.listenOnce())
+	assert.Nil(t, taskStore.Update("task", map[string]interface{}{
+		datastore.KTaskCancel: int64(config.CANCEL_VALID),
+	}))
+	select {
+	case <-canceled:
+	case <-time.After(500 * time.Millisecond):
+		t.Fatal("cancel not detected")
+	}
+	// cancel listen removed, idle
+	assert.Eventually(t, func() bool {
+		return !l.listenOnce()
+	}, time.Second, 10*time.Millisecond)
+	l.Close()
+}
+
+func TestListenIntervalAdaptive(t *testing.T) {
+	config.ConfigGlobal = &config.Config{ConfigYaml: config.ConfigYaml{
+		DbSqlite:          filepath.Join(t.TempDir(), "sqlite3"),
+		ListenMinInterval: 10,
+		ListenMaxInterval: 80,
+	}}
+	taskStore := datastore.NewSQLiteDatastore(datastore.NewSQLiteConfig(datastore.KTaskTableName))
+	defer taskStore.Close()
+	assert.Nil(t, taskStore.Put("task", map[string]interface{}{
+		datastore.KTaskIdColumnName: "task",
+		datastore.KTaskStatus:       config.TASK_INPROGRESS,
+		datastore.KTaskCancel:       int64(config.CANCEL_INIT),
+	}))
+	// loop started by constructor
+	l := NewListenDbTask(1, taskStore, nil, nil)
+	defer l.Close()
+
+	// idle back off to max
+	assert.Eventually(t, func() bool {
+		return l.currentInterval() == 80*time.Millisecond
+	}, time.Second, 5*time.Millisecond)
+	// pending cancel listen poll at min
+	l.AddTask("task", CancelListen, func(v any) {})
+	assert.Eventually(t, func() bool {
+		return l.currentInterval() == 10*time.Millisecond
+	}, time.Second, 5*time.Millisecond)
+	// task terminal, listen removed, back off again
+	assert.Nil(t, taskStore.Update("task", map[string]interface{}{
+		datastore.KTaskStatus: config.TASK_FINISH,
+	}))
+	assert.Eventually(t, func() bool {
+		return l.currentInterval() == 80*time.Millisecond
+	}, time.Second, 5*time.Millisecond)
+}
@@ -0,0 +1,179 @@
+package module
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/datastore"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	mnsVersion          = "2015-06-06"
+	mnsContentType      = "text/xml;charset=utf-8"
+	mnsSecurityToken    = "security-token"
+	mnsPublishTimeout   = 10 * time.Second
+	mnsMessagePrefix    = `<?xml version="1.0" encoding="UTF-8"?><Message xmlns="http://mns.aliyuncs.com/doc/v1/"><MessageBody>`
+	mnsMessageSuffix    = `</MessageBody></Message>`
+	mnsPublishedCode    = http.StatusCreated
+	mnsErrorBodyMaxSize = 1024
+)
+
+// IsTaskTerminal task not change any more
+func IsTaskTerminal(status string) bool {
+	return status == config.TASK_FINISH || status == config.TASK_FAILED || status == config.TASK_CANCELLED ||
+		status == config.TASK_NO_OUTPUT || status == config.TASK_PARTIAL_UPLOAD
+}
+
+// TaskEvent message published when task terminal
+type TaskEvent struct {
+	TaskId    string   `json:"taskId"`
+	User      string   `json:"user"`
+	Status    string   `json:"status"`
+	OssPaths  []string `json:"ossPaths"`
+	Timestamp int64    `json:"timestamp"`
+}
+
+// MnsPublisher send message to mns topic or queue
+type MnsPublisher struct {
+	endpoint   string
+	resource   string
+	httpClient *http.Client
+}
+
+// MnsGlobal nil when mns not config
+var MnsGlobal *MnsPublisher
+
+// InitMnsPublisher init MnsGlobal when mnsTopic or mnsQueue set
+func InitMnsPublisher() {
+	resource := ""
+	if config.ConfigGlobal.MnsTopic != "" {
+		resource = fmt.Sprintf("/topics/%s/messages", config.ConfigGlobal.MnsTopic)
+	} else if config.ConfigGlobal.MnsQueue != "" {
+		resource = fmt.Sprintf("/queues/%s/messages", config.ConfigGlobal.MnsQueue)
+	} else {
+		return
+	}
+	initCredential()
+	MnsGlobal = &MnsPublisher{
+		endpoint:   config.ConfigGlobal.GetMnsEndpoint(),
+		resource:   resource,
+		httpClient: &http.Client{Timeout: mnsPublishTimeout},
+	}
+}
+
+// Publish send body as message, topic PublishMessage or queue SendMessage
+func (m *MnsPublisher) Publish(body []byte) error {
+	var msg bytes.Buffer
+	msg.WriteString(mnsMessagePrefix)
+	if err := xml.EscapeText(&msg, body); err != nil {
+		return err
+	}
+	msg.WriteString(mnsMessageSuffix)
+	req, err := http.NewRequest(http.MethodPost, m.endpoint+m.resource, &msg)
+	if err != nil {
+		return err
+	}
+	date := time.Now().UTC().Format(http.TimeFormat)
+	cred, _ := CredentialGlobal.get()
+	req.Header.Set("Content-Type", mnsContentType)
+	req.Header.Set("Date", date)
+	req.Header.Set("x-mns-version", mnsVersion)
+	if cred.SecurityToken != "" {
+		req.Header.Set(mnsSecurityToken, cred.SecurityToken)
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("MNS %s:%s", cred.AccessKeyId,
+		mnsSignature(cred.AccessKeySecret, http.MethodPost, date, m.resource)))
+	resp, err := m.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != mnsPublishedCode {
+		errBody, _ := io.ReadAll(io.LimitReader(resp.Body, mnsErrorBodyMaxSize))
+		return fmt.Errorf("mns publish status code=%d, body=%s", resp.StatusCode, string(errBody))
+	}
+	return nil
+}
+
+// mnsSignature VERB\nContent-MD5\nContent-Type\nDate\nCanonicalizedMNSHeaders\nCanonicalizedResource
+func mnsSignature(secret, method, date, resource string) string {
+	sign := strings.Join([]string{method, "", mnsContentType, date, "x-mns-version:" + mnsVersion, resource}, "\n")
+	h := hmac.New(sha1.New, []byte(secret))
+	h.Write([]byte(sign))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// taskNotifyStore publish task event after task status put or updated to terminal
+type taskNotifyStore struct {
+	datastore.Datastore
+	publisher *MnsPublisher
+}
+
+// WithTaskNotify wrap task store, publish task event to mns when MnsGlobal init
+func WithTaskNotify(store datastore.Datastore) datastore.Datastore {
+	if MnsGlobal == nil {
+		return store
+	}
+	return &taskNotifyStore{Datastore: store, publisher: MnsGlobal}
+}
+
+func (t *taskNotifyStore) Put(key string, values map[string]interface{}) error {
+	if err := t.Datastore.Put(key, values); err != nil {
+		return err
+	}
+	t.notify(key, values)
+	return nil
+}
+
+func (t *taskNotifyStore) Update(key string, values map[string]interface{}) error {
+	if err := t.Datastore.Update(key, values); err != nil {
+		return err
+	}
+	t.notify(key, values)
+	return nil
+}
+
+func (t *taskNotifyStore) CompareAndSwap(key string, column string, expected interface{},
+	values map[string]interface{}) (bool, error) {
+	swapped, err := t.Datastore.CompareAndSwap(key, column, expected, values)
+	if swapped {
+		t.notify(key, values)
+	}
+	return swapped, err
+}
+
+// notify publish in background, task not wait or fail by mns
+func (t *taskNotifyStore) notify(taskId string, values map[string]interface{}) {
+	status, _ := values[datastore.KTaskStatus].(string)
+	if !IsTaskTerminal(status) {
+		return
+	}
+	go func() {
+		event := TaskEvent{TaskId: taskId, Status: status, OssPaths: []string{}, Timestamp: utils.TimestampS()}
+		if data, err := t.Datastore.Get(taskId, []string{datastore.KTaskUser, datastore.KTaskImage}); err == nil {
+			event.User, _ = data[datastore.KTaskUser].(string)
+			if image, ok := data[datastore.KTaskImage].(string); ok && image != "" {
+				event.OssPaths = strings.Split(image, ",")
+			}
+		}
+		body, err := json.Marshal(event)
+		if err != nil {
+			return
+		}
+		if err := t.publisher.Publish(body); err != nil {
+			logrus.WithFields(logrus.Fields{"taskId": taskId}).Warnf("publish task event to mns err=%s",
+				err.Error())
+		}
+	}()
+}
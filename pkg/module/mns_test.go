@@ -0,0 +1,76 @@
+package module
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/datastore"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestMnsTaskNotify(t *testing.T) {
+	mockCredential(t)
+	events := make(chan TaskEvent, 4)
+	mns := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/topics/sd-events/messages", r.URL.Path)
+		assert.Equal(t, "token", r.Header.Get(mnsSecurityToken))
+		assert.Equal(t, "MNS ak:"+mnsSignature("sk", http.MethodPost, r.Header.Get("Date"), r.URL.Path),
+			r.Header.Get("Authorization"))
+		var msg struct {
+			MessageBody string `xml:"MessageBody"`
+		}
+		assert.Nil(t, xml.NewDecoder(r.Body).Decode(&msg))
+		var event TaskEvent
+		assert.Nil(t, json.Unmarshal([]byte(msg.MessageBody), &event))
+		events <- event
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer mns.Close()
+	config.ConfigGlobal = &config.Config{
+		ConfigYaml: config.ConfigYaml{MnsEndpoint: mns.URL, MnsTopic: "sd-events",
+			DbSqlite: filepath.Join(t.TempDir(), "sqlite3")},
+		ConfigEnv: config.ConfigEnv{AccessKeyId: "ak", AccessKeySecret: "sk", AccessKeyToken: "token"},
+	}
+	old := MnsGlobal
+	defer func() {
+		MnsGlobal = old
+	}()
+	InitMnsPublisher()
+	assert.NotNil(t, MnsGlobal)
+
+	store := WithTaskNotify(datastore.NewSQLiteDatastore(datastore.NewSQLiteConfig(datastore.KTaskTableName)))
+	defer store.Close()
+	assert.Nil(t, store.Put("task", map[string]interface{}{
+		datastore.KTaskIdColumnName: "task",
+		datastore.KTaskUser:         "user1",
+		datastore.KTaskStatus:       config.TASK_QUEUE,
+	}))
+	assert.Nil(t, store.Update("task", map[string]interface{}{
+		datastore.KTaskStatus: config.TASK_INPROGRESS,
+		datastore.KTaskImage:  "images/a.png",
+	}))
+	// only terminal status publish
+	assert.Nil(t, store.Update("task", map[string]interface{}{
+		datastore.KTaskStatus: config.TASK_FINISH,
+		datastore.KTaskImage:  "images/a.png,images/b.png",
+	}))
+	select {
+	case event := <-events:
+		assert.Equal(t, "task", event.TaskId)
+		assert.Equal(t, "user1", event.User)
+		assert.Equal(t, config.TASK_FINISH, event.Status)
+		assert.Equal(t, []string{"images/a.png", "images/b.png"}, event.OssPaths)
+	case <-time.After(5 * time.Second):
+		t.Fatal("task event not published")
+	}
+	select {
+	case event := <-events:
+		t.Fatalf("unexpected event %v", event)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
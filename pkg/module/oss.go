@@ -8,17 +8,67 @@ import (
 	"github.com/aliyun/aliyun-oss-go-sdk/oss"
 	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
 	"github.com/devsapp/serverless-stable-diffusion-api/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"io"
 	"io/ioutil"
 	"log"
+	"math/rand"
+	"mime"
+	"net"
+	"net/http"
 	"os"
 	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
 	"strings"
+	"time"
 )
 
 const (
 	expiredInSec = 3 * 60 * 60 * 1000
 )
 
+// first retry backoff, doubled every retry with jitter
+var ossRetryBackoff = 200 * time.Millisecond
+
+// withOssRetry retry op with backoff on transient error, op should be idempotent
+func withOssRetry(name string, op func() error) error {
+	attempts := config.ConfigGlobal.OssRetryAttempts
+	backoff := ossRetryBackoff
+	for attempt := 1; ; attempt++ {
+		err := op()
+		if err == nil || attempt >= attempts || !isOssRetryableError(err) {
+			return err
+		}
+		logrus.Warnf("oss %s attempt %d err=%s, retry", name, attempt, err.Error())
+		time.Sleep(backoff/2 + time.Duration(rand.Int63n(int64(backoff))))
+		backoff *= 2
+	}
+}
+
+// server error, throttling and network error are transient, client error like 403/404 not retry
+func isOssRetryableError(err error) bool {
+	var serviceErr oss.ServiceError
+	if errors.As(err, &serviceErr) {
+		return serviceErr.StatusCode >= http.StatusInternalServerError ||
+			serviceErr.StatusCode == http.StatusTooManyRequests
+	}
+	var statusErr oss.UnexpectedStatusCodeError
+	if errors.As(err, &statusErr) {
+		return statusErr.Got() >= http.StatusInternalServerError || statusErr.Got() == http.StatusTooManyRequests
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// OssObject object listed in output bucket
+type OssObject struct {
+	Key          string
+	Size         int64
+	LastModified time.Time
+}
+
 type OssOp interface {
 	UploadFile(ossKey, localFile string) error
 	UploadFileByByte(ossKey string, body []byte) error
@@ -26,6 +76,11 @@ type OssOp interface {
 	DeleteFile(ossKey string) error
 	DownloadFileToBase64(ossPath string) (*string, error)
 	GetUrl(ossPath []string) ([]string, error)
+	// ListFiles list at most limit objects with prefix after marker in key order,
+	// return marker of next page, empty when no more objects
+	ListFiles(prefix, marker string, limit int) ([]OssObject, string, error)
+	// ModelExist model object exist in model bucket
+	ModelExist(ossKey string) (bool, error)
 }
 
 // OssGlobal oss manager
@@ -37,17 +92,27 @@ func NewOssManager() error {
 		// read/write with disk
 		OssGlobal = new(OssManagerLocal)
 	case config.REMOTE:
+		// read latest credential every request, sts token refreshed from credentials uri
+		initCredential()
 		client, err := oss.New(config.ConfigGlobal.OssEndpoint, config.ConfigGlobal.AccessKeyId,
-			config.ConfigGlobal.AccessKeySecret, oss.SecurityToken(config.ConfigGlobal.AccessKeyToken))
+			config.ConfigGlobal.AccessKeySecret, oss.SecurityToken(config.ConfigGlobal.AccessKeyToken),
+			oss.SetCredentialsProvider(CredentialGlobal))
 		if err != nil {
 			return err
 		}
-		bucket, err := client.Bucket(config.ConfigGlobal.Bucket)
+		modelBucket, err := remoteBucket(client, config.ConfigGlobal.GetModelBucket())
 		if err != nil {
 			return err
 		}
+		outputBucket := modelBucket
+		if config.ConfigGlobal.GetOutputBucket() != config.ConfigGlobal.GetModelBucket() {
+			if outputBucket, err = remoteBucket(client, config.ConfigGlobal.GetOutputBucket()); err != nil {
+				return err
+			}
+		}
 		OssGlobal = &OssManagerRemote{
-			bucket: bucket,
+			modelBucket:  modelBucket,
+			outputBucket: outputBucket,
 		}
 	default:
 		log.Fatal("oss mode err")
@@ -55,16 +120,31 @@ func NewOssManager() error {
 	return nil
 }
 
+// remoteBucket get bucket and check it exist and accessible,
+// access denied tolerated since object only policy not allow read bucket info
+func remoteBucket(client *oss.Client, name string) (*oss.Bucket, error) {
+	if _, err := client.GetBucketInfo(name); err != nil {
+		var serviceErr oss.ServiceError
+		if !errors.As(err, &serviceErr) || serviceErr.Code != "AccessDenied" {
+			return nil, fmt.Errorf("oss bucket %s not accessible, err=%s", name, err.Error())
+		}
+		logrus.Warnf("oss bucket %s info access denied, skip check", name)
+	}
+	return client.Bucket(name)
+}
+
+// OssManagerRemote download models from modelBucket, images read/write in outputBucket
 type OssManagerRemote struct {
-	bucket *oss.Bucket
+	modelBucket  *oss.Bucket
+	outputBucket *oss.Bucket
 }
 
 func (o *OssManagerRemote) GetUrl(ossKeys []string) ([]string, error) {
 	ossUrl := make([]string, 0, len(ossKeys))
 	for _, key := range ossKeys {
-		url, err := o.bucket.SignURL(key, oss.HTTPGet, expiredInSec)
+		url, err := o.outputBucket.SignURL(key, oss.HTTPGet, expiredInSec)
 		if err != nil {
-			return nil, errors.New("get")
+			return nil, fmt.Errorf("sign url %s err=%s", key, err.Error())
 		}
 		ossUrl = append(ossUrl, url)
 	}
@@ -74,34 +154,127 @@ func (o *OssManagerRemote) GetUrl(ossKeys []string) ([]string, error) {
 // UploadFile upload file to oss
 func (o *OssManagerRemote) UploadFile(ossKey, localFile string) error {
 	// mode: remote
-	return o.bucket.PutObjectFromFile(ossKey, localFile)
+	return withOssRetry("upload "+ossKey, func() error {
+		return o.outputBucket.PutObjectFromFile(ossKey, localFile)
+	})
 }
 
 // UploadFileByByte UploadFile upload file to oss
+// body size >= multipart threshold use multipart upload
+// put retry overwrite the whole object, never leave partial object
 func (o *OssManagerRemote) UploadFileByByte(ossKey string, body []byte) error {
-	return o.bucket.PutObject(ossKey, bytes.NewReader(body))
+	threshold := config.ConfigGlobal.GetOssMultipartThresholdBytes()
+	if threshold > 0 && int64(len(body)) >= threshold {
+		return o.uploadMultipart(ossKey, body)
+	}
+	return withOssRetry("upload "+ossKey, func() error {
+		return o.outputBucket.PutObject(ossKey, bytes.NewReader(body),
+			oss.ContentType(objectContentType(ossKey, body)))
+	})
+}
+
+// uploadMultipart retry each part in same upload, part number overwrite, abort upload when fail
+func (o *OssManagerRemote) uploadMultipart(ossKey string, body []byte) error {
+	var imur oss.InitiateMultipartUploadResult
+	if err := withOssRetry("initiate multipart upload "+ossKey, func() (err error) {
+		imur, err = o.outputBucket.InitiateMultipartUpload(ossKey, oss.ContentType(objectContentType(ossKey, body)))
+		return err
+	}); err != nil {
+		return err
+	}
+	parts := make([]oss.UploadPart, 0, len(body)/config.DefaultOssMultipartPartSize+1)
+	for offset := 0; offset < len(body); offset += config.DefaultOssMultipartPartSize {
+		end := offset + config.DefaultOssMultipartPartSize
+		if end > len(body) {
+			end = len(body)
+		}
+		var part oss.UploadPart
+		err := withOssRetry("upload part "+ossKey, func() (err error) {
+			part, err = o.outputBucket.UploadPart(imur, bytes.NewReader(body[offset:end]), int64(end-offset),
+				len(parts)+1)
+			return err
+		})
+		if err != nil {
+			o.outputBucket.AbortMultipartUpload(imur)
+			return fmt.Errorf("multipart upload %s err=%s", ossKey, err.Error())
+		}
+		parts = append(parts, part)
+	}
+	if err := withOssRetry("complete multipart upload "+ossKey, func() error {
+		_, err := o.outputBucket.CompleteMultipartUpload(imur, parts)
+		return err
+	}); err != nil {
+		o.outputBucket.AbortMultipartUpload(imur)
+		return fmt.Errorf("complete multipart upload %s err=%s", ossKey, err.Error())
+	}
+	return nil
+}
+
+// objectContentType content type by key ext, sniff body when ext unknown
+func objectContentType(ossKey string, body []byte) string {
+	if contentType := mime.TypeByExtension(path.Ext(ossKey)); contentType != "" {
+		return contentType
+	}
+	return http.DetectContentType(body)
 }
 
-// DownloadFile download file from oss
+// DownloadFile download model file from oss
 func (o *OssManagerRemote) DownloadFile(ossKey, localFile string) error {
-	return o.bucket.GetObjectToFile(ossKey, localFile)
+	return withOssRetry("download "+ossKey, func() error {
+		return o.modelBucket.GetObjectToFile(ossKey, localFile)
+	})
+}
+
+// ModelExist model object exist in model bucket
+func (o *OssManagerRemote) ModelExist(ossKey string) (bool, error) {
+	var exist bool
+	err := withOssRetry("stat "+ossKey, func() error {
+		var err error
+		exist, err = o.modelBucket.IsObjectExist(ossKey)
+		return err
+	})
+	return exist, err
 }
 
 // DeleteFile delete file from oss
 func (o *OssManagerRemote) DeleteFile(ossKey string) error {
-	return o.bucket.DeleteObject(ossKey)
+	return withOssRetry("delete "+ossKey, func() error {
+		return o.outputBucket.DeleteObject(ossKey)
+	})
 }
 
-func (o *OssManagerRemote) DownloadFileToBase64(ossKey string) (*string, error) {
-	// get image from oss
-	body, err := o.bucket.GetObject(ossKey)
-	if err != nil {
-		return nil, err
+// ListFiles list images in output bucket
+func (o *OssManagerRemote) ListFiles(prefix, marker string, limit int) ([]OssObject, string, error) {
+	var result oss.ListObjectsResult
+	if err := withOssRetry("list "+prefix, func() error {
+		var err error
+		result, err = o.outputBucket.ListObjects(oss.Prefix(prefix), oss.Marker(marker), oss.MaxKeys(limit))
+		return err
+	}); err != nil {
+		return nil, "", err
+	}
+	objects := make([]OssObject, 0, len(result.Objects))
+	for _, object := range result.Objects {
+		objects = append(objects, OssObject{Key: object.Key, Size: object.Size, LastModified: object.LastModified})
 	}
+	if !result.IsTruncated {
+		return objects, "", nil
+	}
+	return objects, result.NextMarker, nil
+}
 
-	data, err := ioutil.ReadAll(body)
-	body.Close()
-	if err != nil {
+func (o *OssManagerRemote) DownloadFileToBase64(ossKey string) (*string, error) {
+	// get image from oss, read body in retry since stream may break
+	var data []byte
+	if err := withOssRetry("download "+ossKey, func() error {
+		body, err := o.outputBucket.GetObject(ossKey)
+		if err != nil {
+			return err
+		}
+		defer body.Close()
+		data, err = ioutil.ReadAll(body)
+		return err
+	}); err != nil {
 		return nil, err
 	}
 	// image to base64
@@ -146,6 +319,9 @@ func (o *OssManagerLocal) DownloadFile(ossKey, localFile string) error {
 	err := cmd.Run()
 	return err
 }
+func (o *OssManagerLocal) ModelExist(ossKey string) (bool, error) {
+	return utils.FileExists(fmt.Sprintf("%s/%s", config.ConfigGlobal.OssPath, ossKey)), nil
+}
 func (o *OssManagerLocal) DeleteFile(ossKey string) error {
 	destFile := fmt.Sprintf("%s/%s", config.ConfigGlobal.OssPath, ossKey)
 	_, err := utils.DeleteLocalFile(destFile)
@@ -165,3 +341,39 @@ func (o *OssManagerLocal) DownloadFileToBase64(ossKey string) (*string, error) {
 	imageBase64 := base64.StdEncoding.EncodeToString(data)
 	return &imageBase64, nil
 }
+
+func (o *OssManagerLocal) ListFiles(prefix, marker string, limit int) ([]OssObject, string, error) {
+	// walk from dir of prefix, key filtered by whole prefix
+	root := filepath.Join(config.ConfigGlobal.OssPath, path.Dir(prefix+"_"))
+	objects := make([]OssObject, 0)
+	err := filepath.Walk(root, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(config.ConfigGlobal.OssPath, file)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if strings.HasPrefix(key, prefix) && key > marker {
+			objects = append(objects, OssObject{Key: key, Size: info.Size(), LastModified: info.ModTime()})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	sort.Slice(objects, func(i, j int) bool {
+		return objects[i].Key < objects[j].Key
+	})
+	if len(objects) <= limit {
+		return objects, "", nil
+	}
+	return objects[:limit], objects[limit-1].Key, nil
+}
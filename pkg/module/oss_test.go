@@ -1,9 +1,18 @@
 package module
 
 import (
+	"encoding/base64"
+	"fmt"
+	"github.com/aliyun/aliyun-oss-go-sdk/oss"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
 	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 func TestOss(t *testing.T) {
@@ -24,3 +33,126 @@ func TestOss(t *testing.T) {
 	err = os.Remove(downloadFile)
 	assert.Nil(t, err)
 }
+
+func TestObjectContentType(t *testing.T) {
+	assert.Equal(t, "image/png", objectContentType("images/a.png", []byte("\xff\xd8\xff\xe0000")))
+	assert.Equal(t, "image/jpeg", objectContentType("images/a.jpg", nil))
+	// unknown ext sniff body
+	assert.Equal(t, "image/jpeg", objectContentType("images/a", []byte("\xff\xd8\xff\xe0000")))
+}
+
+// flakyOss fail first n requests with status, then serve from memory
+type flakyOss struct {
+	fails   int32
+	status  int
+	calls   int32
+	objects map[string][]byte
+}
+
+func (f *flakyOss) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	if atomic.AddInt32(&f.calls, 1) <= atomic.LoadInt32(&f.fails) {
+		w.Header().Set("Content-Type", "application/xml")
+		w.WriteHeader(f.status)
+		io.WriteString(w, "<Error><Code>ServiceUnavailable</Code><Message>flaky</Message></Error>")
+		return
+	}
+	switch r.Method {
+	case http.MethodPut:
+		f.objects[r.URL.Path] = body
+	case http.MethodGet:
+		w.Write(f.objects[r.URL.Path])
+	}
+}
+
+func newFlakyOss(t *testing.T, fails int32, status int) (*flakyOss, *OssManagerRemote) {
+	config.ConfigGlobal = &config.Config{ConfigYaml: config.ConfigYaml{OssRetryAttempts: 3}}
+	old := ossRetryBackoff
+	ossRetryBackoff = time.Millisecond
+	t.Cleanup(func() {
+		ossRetryBackoff = old
+	})
+	stub := &flakyOss{fails: fails, status: status, objects: make(map[string][]byte)}
+	server := httptest.NewServer(stub)
+	t.Cleanup(server.Close)
+	client, err := oss.New(server.URL, "ak", "sk")
+	assert.Nil(t, err)
+	bucket, err := client.Bucket("bucket")
+	assert.Nil(t, err)
+	return stub, &OssManagerRemote{modelBucket: bucket, outputBucket: bucket}
+}
+
+func TestOssRetry(t *testing.T) {
+	// transient error retry until success
+	stub, manager := newFlakyOss(t, 2, http.StatusServiceUnavailable)
+	assert.Nil(t, manager.UploadFileByByte("images/a.png", []byte("image")))
+	assert.Equal(t, int32(3), atomic.LoadInt32(&stub.calls))
+	atomic.StoreInt32(&stub.fails, 1)
+	atomic.StoreInt32(&stub.calls, 0)
+	data, err := manager.DownloadFileToBase64("images/a.png")
+	assert.Nil(t, err)
+	assert.Equal(t, base64.StdEncoding.EncodeToString([]byte("image")), *data)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&stub.calls))
+
+	// attempts exhausted
+	stub, manager = newFlakyOss(t, 5, http.StatusInternalServerError)
+	assert.NotNil(t, manager.UploadFileByByte("images/a.png", []byte("image")))
+	assert.Equal(t, int32(3), atomic.LoadInt32(&stub.calls))
+
+	// client error not retry
+	stub, manager = newFlakyOss(t, 5, http.StatusForbidden)
+	assert.NotNil(t, manager.UploadFileByByte("images/a.png", []byte("image")))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&stub.calls))
+}
+
+func TestRemoteBucket(t *testing.T) {
+	code := ""
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/xml")
+		status := http.StatusForbidden
+		if code == "NoSuchBucket" {
+			status = http.StatusNotFound
+		}
+		w.WriteHeader(status)
+		fmt.Fprintf(w, "<Error><Code>%s</Code><Message>%s</Message></Error>", code, code)
+	}))
+	defer server.Close()
+	client, err := oss.New(server.URL, "ak", "sk")
+	assert.Nil(t, err)
+
+	// object only policy can not read bucket info
+	code = "AccessDenied"
+	bucket, err := remoteBucket(client, "bucket")
+	assert.Nil(t, err)
+	assert.Equal(t, "bucket", bucket.BucketName)
+
+	code = "NoSuchBucket"
+	_, err = remoteBucket(client, "bucket")
+	assert.NotNil(t, err)
+}
+
+func TestOssLocalListFiles(t *testing.T) {
+	config.ConfigGlobal = &config.Config{ConfigYaml: config.ConfigYaml{OssPath: t.TempDir()}}
+	o := &OssManagerLocal{}
+	for _, key := range []string{"images/user1/a.png", "images/user1/b.png", "images/user1/sub/c.png",
+		"images/user10/d.png", "images/user2/e.png"} {
+		assert.Nil(t, o.UploadFileByByte(key, []byte("image")))
+	}
+	objects, next, err := o.ListFiles("images/user1/", "", 2)
+	assert.Nil(t, err)
+	assert.Equal(t, "images/user1/b.png", next)
+	assert.Len(t, objects, 2)
+	assert.Equal(t, "images/user1/a.png", objects[0].Key)
+	assert.Equal(t, int64(5), objects[0].Size)
+	objects, next, err = o.ListFiles("images/user1/", next, 2)
+	assert.Nil(t, err)
+	assert.Empty(t, next)
+	assert.Len(t, objects, 1)
+	assert.Equal(t, "images/user1/sub/c.png", objects[0].Key)
+
+	// prefix not exist
+	objects, next, err = o.ListFiles("images/user3/", "", 2)
+	assert.Nil(t, err)
+	assert.Empty(t, objects)
+	assert.Empty(t, next)
+}
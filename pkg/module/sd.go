@@ -15,9 +15,12 @@ import (
 	"io/ioutil"
 	"net/http"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"syscall"
 	"time"
 )
 
@@ -26,6 +29,7 @@ const (
 	SD_START_TIMEOUT  = 5 * 60 * 1000 // 5min
 	SD_DETECT_TIMEOUT = 500           // 500ms
 	SD_REQUEST_WAIT   = 30 * 1000     // 30s
+	SD_LOG_RING_SIZE  = 1000
 )
 
 var (
@@ -33,12 +37,35 @@ var (
 	once        sync.Once
 )
 
+// errSdModelMissing model of env SD_MODEL not exist on disk
+var errSdModelMissing = errors.New("sd model missing")
+
+// startSdProcess start sd webui, replaced in test
+var startSdProcess = func(s *SDManager) (*utils.ExecItem, error) {
+	return utils.DoExecAsync(config.ConfigGlobal.SdShell, config.ConfigGlobal.SdPath, s.getEnv())
+}
+
+// sdProcessExist sd webui process of pid alive, replaced in test
+var sdProcessExist = checkSdExist
+
+// killSdProcess kill process group of sd shell pid, webui started by shell in the group, replaced in test
+var killSdProcess = func(pid int) {
+	if err := syscall.Kill(-pid, syscall.SIGKILL); err != nil {
+		logrus.Warnf("kill sd process group %d err=%s", pid, err.Error())
+	}
+}
+
 type SDManager struct {
+	// serialize start and restart, detect loop and predict fail may restart concurrently
+	restartLock sync.Mutex
+	// guard pid and stdout, written by start and read by probe
+	lock            sync.RWMutex
 	pid             int
 	port            string
-	modelLoadedFlag bool
+	modelLoadedFlag atomic.Bool
 	restartFlag     bool
 	stdout          io.ReadCloser
+	recentLogs      *utils.LineRing
 	endChan         chan struct{}
 	signalIn        chan struct{}
 	signalOut       chan struct{}
@@ -50,7 +77,12 @@ func NewSDManager(port string) *SDManager {
 	SDManageObj.endChan = make(chan struct{}, 1)
 	SDManageObj.signalIn = make(chan struct{}, 1)
 	SDManageObj.signalOut = make(chan struct{})
+	SDManageObj.recentLogs = utils.NewLineRing(SD_LOG_RING_SIZE)
 	if err := SDManageObj.init(); err != nil {
+		// refuse to start instead of webui loading another model
+		if errors.Is(err, errSdModelMissing) {
+			logrus.Fatal(err.Error())
+		}
 		logrus.Error(err.Error())
 	}
 	return SDManageObj
@@ -83,7 +115,21 @@ func (s *SDManager) getEnv() []string {
 }
 
 func (s *SDManager) init() error {
-	s.modelLoadedFlag = false
+	s.restartLock.Lock()
+	defer s.restartLock.Unlock()
+	return s.start()
+}
+
+// start sd and read its log, caller hold restartLock
+func (s *SDManager) start() error {
+	// webui silently load another model when checkpoint missing, surface it before start and restart
+	if err := checkSdModelFile(os.Getenv(config.MODEL_SD)); err != nil {
+		logrus.Error(err.Error())
+		if config.ConfigGlobal.FailOnMissingSdModel {
+			return err
+		}
+	}
+	s.modelLoadedFlag.Store(false)
 	sdStartTs := utils.TimestampMS()
 	defer func() {
 		sdEndTs := utils.TimestampMS()
@@ -92,7 +138,7 @@ func (s *SDManager) init() error {
 	}()
 	// start sd
 	// todo: 修改成windows启动方式
-	execItem, err := utils.DoExecAsync(config.ConfigGlobal.SdShell, config.ConfigGlobal.SdPath, s.getEnv())
+	execItem, err := startSdProcess(s)
 	if err != nil {
 		return err
 	}
@@ -106,15 +152,18 @@ func (s *SDManager) init() error {
 				return
 			default:
 				logStr := stdout.Text()
-				if !s.modelLoadedFlag && strings.HasPrefix(logStr, "Model loaded in") {
-					s.modelLoadedFlag = true
+				if !s.modelLoadedFlag.Load() && strings.HasPrefix(logStr, "Model loaded in") {
+					s.modelLoadedFlag.Store(true)
 				}
+				s.recentLogs.Add(logStr)
 				log.SDLogInstance.LogFlow <- logStr
 			}
 		}
 	}()
+	s.lock.Lock()
 	s.pid = execItem.Pid
 	s.stdout = execItem.Stdout
+	s.lock.Unlock()
 	// make sure sd started(port exist)
 	if !utils.PortCheck(s.port, SD_START_TIMEOUT) {
 		return errors.New("sd not start after 5min")
@@ -129,6 +178,18 @@ func (s *SDManager) init() error {
 	return nil
 }
 
+// getPid pid of current sd process
+func (s *SDManager) getPid() int {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.pid
+}
+
+// TailLogs return recent n sd stdout lines, oldest first
+func (s *SDManager) TailLogs(n int) []string {
+	return s.recentLogs.Tail(n)
+}
+
 // idle charge mode need check model
 func (s *SDManager) waitModelLoaded(timeout int) {
 	timeoutChan := time.After(time.Duration(timeout) * time.Millisecond)
@@ -137,7 +198,7 @@ func (s *SDManager) waitModelLoaded(timeout int) {
 		case <-timeoutChan:
 			return
 		default:
-			if s.modelLoadedFlag && s.predictProbe() {
+			if s.modelLoadedFlag.Load() && s.predictProbe() {
 				return
 			}
 		}
@@ -156,7 +217,7 @@ func (s *SDManager) predictProbe() bool {
 	req, _ := http.NewRequest(config.HTTP_POST,
 		fmt.Sprintf("%s%s", config.ConfigGlobal.SdUrlPrefix,
 			config.TXT2IMG), bytes.NewBuffer(body))
-	client := &http.Client{}
+	client := &http.Client{Transport: SdTransport(nil)}
 	client.Do(req)
 	return true
 }
@@ -171,17 +232,39 @@ func (s *SDManager) detectSdAlive() {
 }
 
 func (s *SDManager) KillAgentWithoutSd() {
-	if !checkSdExist(strconv.Itoa(s.pid)) && !utils.PortCheck(s.port, SD_DETECT_TIMEOUT) {
+	if !sdProcessExist(strconv.Itoa(s.getPid())) && !utils.PortCheck(s.port, SD_DETECT_TIMEOUT) {
 		//syscall.Kill(syscall.Getpid(), syscall.SIGTERM)
 	}
 }
 
 func (s *SDManager) WaitPortWork() {
+	// check under restartLock, concurrent callers not restart twice
+	s.restartLock.Lock()
+	defer s.restartLock.Unlock()
 	// sd not exist, kill
-	if !checkSdExist(strconv.Itoa(s.pid)) && !utils.PortCheck(s.port, SD_DETECT_TIMEOUT) {
+	if !sdProcessExist(strconv.Itoa(s.getPid())) && !utils.PortCheck(s.port, SD_DETECT_TIMEOUT) {
 		logrus.Info("restart process....")
-		s.init()
+		s.start()
+	}
+}
+
+// Restart kill sd which port still open but not respond then start again,
+// callers waiting lock while other caller restart not restart twice
+func (s *SDManager) Restart() error {
+	pid := s.getPid()
+	s.restartLock.Lock()
+	defer s.restartLock.Unlock()
+	if s.getPid() != pid {
+		return nil
+	}
+	logrus.Infof("kill wedged sd %d, restart process....", pid)
+	killSdProcess(pid)
+	// old port released, start not mistake it for new sd
+	deadline := time.Now().Add(time.Duration(SD_REQUEST_WAIT) * time.Millisecond)
+	for time.Now().Before(deadline) && utils.PortCheck(s.port, SD_DETECT_TIMEOUT/10) {
+		time.Sleep(time.Duration(SD_DETECT_TIMEOUT/5) * time.Millisecond)
 	}
+	return s.start()
 }
 
 // WaitSDRestartFinish blocking until sd restart finish
@@ -244,6 +327,24 @@ func UpdateSdConfig(configStore datastore.Datastore) error {
 	return nil
 }
 
+// checkSdModelFile sd model exist under stable diffusion model dir, name may carry " [hash]",
+// empty model (model not bound to function) skipped
+func checkSdModelFile(sdModel string) error {
+	sdModel = strings.TrimSpace(sdModel)
+	if idx := strings.LastIndex(sdModel, " ["); idx > 0 {
+		sdModel = sdModel[:idx]
+	}
+	if sdModel == "" {
+		return nil
+	}
+	dir := config.ConfigGlobal.GetModelDir(config.SD_MODEL)
+	if dir == "" || !utils.FileExists(filepath.Join(dir, sdModel)) {
+		return fmt.Errorf("%w: %s not found under %s, webui would load another model", errSdModelMissing,
+			sdModel, dir)
+	}
+	return nil
+}
+
 func checkSdExist(pid string) bool {
 	execItem := utils.DoExec("ps -ef|grep webui|grep -v agent|grep -v grep|awk '{print $2}'", "", nil)
 	if strings.Trim(execItem.Output, "\n") == pid {
@@ -0,0 +1,36 @@
+package module
+
+import (
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"net/http"
+	"net/url"
+)
+
+// sdAuthTransport attach webui api basic auth to requests of sdUrlPrefix host
+type sdAuthTransport struct {
+	base http.RoundTripper
+}
+
+// SdTransport transport for requests may reach sd webui, basic auth attached when sdApiAuth set,
+// requests of other hosts unchanged, nil base use http.DefaultTransport
+func SdTransport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &sdAuthTransport{base: base}
+}
+
+func (t *sdAuthTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if user, passwd, ok := config.ConfigGlobal.GetSdApiAuth(); ok && isSdHost(req.URL) {
+		// RoundTripper must not modify request
+		req = req.Clone(req.Context())
+		req.SetBasicAuth(user, passwd)
+	}
+	return t.base.RoundTrip(req)
+}
+
+// isSdHost request target is sd webui of sdUrlPrefix
+func isSdHost(target *url.URL) bool {
+	sd, err := url.Parse(config.ConfigGlobal.SdUrlPrefix)
+	return err == nil && sd.Host != "" && sd.Host == target.Host
+}
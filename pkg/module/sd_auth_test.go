@@ -0,0 +1,46 @@
+package module
+
+import (
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSdTransport(t *testing.T) {
+	auths := make(chan string, 1)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, passwd, ok := r.BasicAuth()
+		if !ok {
+			auths <- ""
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		auths <- user + ":" + passwd
+	})
+	sd := httptest.NewServer(handler)
+	defer sd.Close()
+	other := httptest.NewServer(handler)
+	defer other.Close()
+	config.ConfigGlobal = &config.Config{ConfigYaml: config.ConfigYaml{SdUrlPrefix: sd.URL}}
+	c := &http.Client{Transport: SdTransport(nil)}
+	get := func(url string) int {
+		req, _ := http.NewRequest(http.MethodGet, url+config.PROGRESS, nil)
+		resp, err := c.Do(req)
+		assert.Nil(t, err)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	// auth off
+	assert.Equal(t, http.StatusUnauthorized, get(sd.URL))
+	assert.Empty(t, <-auths)
+
+	config.ConfigGlobal.SdApiAuth = "sdapi:secret"
+	assert.Equal(t, http.StatusOK, get(sd.URL))
+	assert.Equal(t, "sdapi:secret", <-auths)
+	// credentials only sent to webui
+	assert.Equal(t, http.StatusUnauthorized, get(other.URL))
+	assert.Empty(t, <-auths)
+}
@@ -0,0 +1,216 @@
+package module
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestSDManagerRestart(t *testing.T) {
+	// sd log consumer read config, not replace it
+	if config.ConfigGlobal == nil {
+		config.ConfigGlobal = &config.Config{}
+	}
+	// fake sd port
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer listener.Close()
+	_, port, _ := net.SplitHostPort(listener.Addr().String())
+	var starts, alive atomic.Int32
+	oldStart, oldExist := startSdProcess, sdProcessExist
+	startSdProcess = func(s *SDManager) (*utils.ExecItem, error) {
+		starts.Add(1)
+		return utils.DoExecAsync("echo 'Model loaded in 1.0s'", "", nil)
+	}
+	sdProcessExist = func(pid string) bool {
+		return alive.Load() == 1
+	}
+	defer func() {
+		startSdProcess, sdProcessExist = oldStart, oldExist
+	}()
+	// detect loop not started in test
+	once.Do(func() {})
+
+	s := &SDManager{port: port, endChan: make(chan struct{}, 1), recentLogs: utils.NewLineRing(SD_LOG_RING_SIZE)}
+	assert.Nil(t, s.init())
+	alive.Store(1)
+	// restart while probing
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			assert.Nil(t, s.init())
+		}()
+		go func() {
+			defer wg.Done()
+			s.WaitPortWork()
+			s.KillAgentWithoutSd()
+			s.modelLoadedFlag.Load()
+			s.TailLogs(10)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(5), starts.Load())
+	assert.NotZero(t, s.getPid())
+
+	// process gone but port alive, not restart
+	alive.Store(0)
+	s.WaitPortWork()
+	assert.Equal(t, int32(5), starts.Load())
+	// process and port gone, concurrent probes restart once
+	listener.Close()
+	startSdProcess = func(s *SDManager) (*utils.ExecItem, error) {
+		starts.Add(1)
+		// restarted sd listen again
+		if starts.Load() == 6 {
+			listener, err = net.Listen("tcp", "127.0.0.1:"+port)
+			assert.Nil(t, err)
+		}
+		return utils.DoExecAsync("true", "", nil)
+	}
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.WaitPortWork()
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(6), starts.Load())
+}
+
+func TestSDManagerRestartWedged(t *testing.T) {
+	if config.ConfigGlobal == nil {
+		config.ConfigGlobal = &config.Config{}
+	}
+	// wedged sd keep port open
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	_, port, _ := net.SplitHostPort(listener.Addr().String())
+	var starts, kills atomic.Int32
+	oldStart, oldKill := startSdProcess, killSdProcess
+	startSdProcess = func(s *SDManager) (*utils.ExecItem, error) {
+		if starts.Add(1) > 1 {
+			listener, err = net.Listen("tcp", "127.0.0.1:"+port)
+			assert.Nil(t, err)
+		}
+		return utils.DoExecAsync("true", "", nil)
+	}
+	killSdProcess = func(pid int) {
+		kills.Add(1)
+		listener.Close()
+	}
+	defer func() {
+		startSdProcess, killSdProcess = oldStart, oldKill
+		listener.Close()
+	}()
+	once.Do(func() {})
+
+	s := &SDManager{port: port, endChan: make(chan struct{}, 1), recentLogs: utils.NewLineRing(SD_LOG_RING_SIZE)}
+	s.restartLock.Lock()
+	assert.Nil(t, s.start())
+	s.restartLock.Unlock()
+	// timed out tasks restart once
+	var wg sync.WaitGroup
+	s.restartLock.Lock()
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.Nil(t, s.Restart())
+		}()
+	}
+	// callers read pid before lock
+	time.Sleep(100 * time.Millisecond)
+	s.restartLock.Unlock()
+	wg.Wait()
+	assert.Equal(t, int32(1), kills.Load())
+	assert.Equal(t, int32(2), starts.Load())
+}
+
+func TestCheckSdModelFile(t *testing.T) {
+	// log goroutines of other tests read config, fields changed instead of replacing it
+	if config.ConfigGlobal == nil {
+		config.ConfigGlobal = &config.Config{}
+	}
+	oldSdPath, oldModelDirs := config.ConfigGlobal.SdPath, config.ConfigGlobal.ModelDirs
+	oldFail := config.ConfigGlobal.FailOnMissingSdModel
+	defer func() {
+		config.ConfigGlobal.SdPath, config.ConfigGlobal.ModelDirs = oldSdPath, oldModelDirs
+		config.ConfigGlobal.FailOnMissingSdModel = oldFail
+	}()
+	sdPath := t.TempDir()
+	config.ConfigGlobal.SdPath = sdPath
+	config.ConfigGlobal.ModelDirs = map[string]string{config.SD_MODEL: "models/Stable-diffusion"}
+	dir := filepath.Join(sdPath, "models/Stable-diffusion")
+	assert.Nil(t, os.MkdirAll(filepath.Join(dir, "xl"), 0755))
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "xl", "sdxl.safetensors"), nil, 0644))
+
+	assert.Nil(t, checkSdModelFile(""))
+	assert.Nil(t, checkSdModelFile("xl/sdxl.safetensors"))
+	assert.Nil(t, checkSdModelFile("xl/sdxl.safetensors [31e35c80fc]"))
+	assert.ErrorIs(t, checkSdModelFile("missing.safetensors"), errSdModelMissing)
+
+	// fail fast not start webui
+	t.Setenv(config.MODEL_SD, "missing.safetensors")
+	config.ConfigGlobal.FailOnMissingSdModel = true
+	oldStart := startSdProcess
+	defer func() {
+		startSdProcess = oldStart
+	}()
+	started := false
+	startSdProcess = func(s *SDManager) (*utils.ExecItem, error) {
+		started = true
+		return nil, errors.New("not start")
+	}
+	s := &SDManager{endChan: make(chan struct{}, 1), recentLogs: utils.NewLineRing(SD_LOG_RING_SIZE)}
+	assert.ErrorIs(t, s.init(), errSdModelMissing)
+	assert.False(t, started)
+	// model removed before restart
+	s.restartLock.Lock()
+	assert.ErrorIs(t, s.start(), errSdModelMissing)
+	s.restartLock.Unlock()
+	assert.False(t, started)
+	// default only log
+	config.ConfigGlobal.FailOnMissingSdModel = false
+	assert.NotNil(t, s.init())
+	assert.True(t, started)
+}
+
+func TestKillSdProcess(t *testing.T) {
+	// shell and the process it started in one group, other processes not touched
+	execItem, err := utils.DoExecAsync("sleep 30 & echo $!; wait", "", nil)
+	assert.Nil(t, err)
+	line, err := bufio.NewReader(execItem.Stdout).ReadString('\n')
+	assert.Nil(t, err)
+	child, err := strconv.Atoi(strings.TrimSpace(line))
+	assert.Nil(t, err)
+	other, err := utils.DoExecAsync("sleep 30", "", nil)
+	assert.Nil(t, err)
+	defer syscall.Kill(-other.Pid, syscall.SIGKILL)
+
+	killSdProcess(execItem.Pid)
+	alive := func(pid int) bool {
+		stat, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+		// zombie not reaped counted as killed
+		return err == nil && !strings.Contains(string(stat), ") Z ")
+	}
+	assert.Eventually(t, func() bool {
+		return !alive(execItem.Pid) && !alive(child)
+	}, 3*time.Second, 20*time.Millisecond)
+	assert.True(t, alive(other.Pid))
+}
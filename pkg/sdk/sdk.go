@@ -0,0 +1,319 @@
+// Package sdk is a go client of serverless stable diffusion api,
+// wrap login, submit txt2img/img2img, poll task progress and fetch result.
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/client"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/models"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	tokenKey            = "Token"
+	userKey             = "username"
+	requestTypeKey      = "Request-Type"
+	asyncRequest        = "async"
+	taskFinish          = "succeeded"
+	taskFailed          = "failed"
+	taskCancelled       = "cancelled"
+	taskNoOutput        = "no_output"
+	taskPartialUpload   = "partial_upload"
+	defaultPollInterval = time.Second
+)
+
+// APIError api return not 200
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("status code=%d, message=%s", e.StatusCode, e.Message)
+}
+
+var (
+	// ErrTaskFailed task finish with failed status
+	ErrTaskFailed = errors.New("task failed")
+	// ErrTaskCancelled task cancelled before finish
+	ErrTaskCancelled = errors.New("task cancelled")
+	// ErrTaskNoOutput webui finished without images, result message and info tell why
+	ErrTaskNoOutput = errors.New("task no output")
+	// ErrTaskPartialUpload some images of batch not uploaded, errors.As PartialUploadError for failed indices
+	ErrTaskPartialUpload = errors.New("task partial upload")
+)
+
+// PartialUploadError task finished but images of FailedImages(0-based batch indices) upload failed,
+// result images are the uploaded ones
+type PartialUploadError struct {
+	FailedImages []int
+}
+
+func (e *PartialUploadError) Error() string {
+	return fmt.Sprintf("%s, failed images=%v", ErrTaskPartialUpload.Error(), e.FailedImages)
+}
+
+// Is errors.Is(err, ErrTaskPartialUpload)
+func (e *PartialUploadError) Is(target error) bool {
+	return target == ErrTaskPartialUpload
+}
+
+// Client stable diffusion api client, safe for concurrent use
+type Client struct {
+	api          *client.ClientWithResponses
+	httpClient   *http.Client
+	async        bool
+	pollInterval time.Duration
+
+	lock     sync.RWMutex
+	token    string
+	userName string
+}
+
+type Option func(*Client)
+
+// WithHTTPClient use custom http client, default http.DefaultClient
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithUser request as user when server login disabled
+func WithUser(userName string) Option {
+	return func(c *Client) {
+		c.userName = userName
+	}
+}
+
+// WithToken use session token from previous login
+func WithToken(token string) Option {
+	return func(c *Client) {
+		c.token = token
+	}
+}
+
+// WithAsync submit task async, server return before task finish, use Wait to get result
+func WithAsync() Option {
+	return func(c *Client) {
+		c.async = true
+	}
+}
+
+// WithPollInterval task progress/result poll interval of Wait, default 1s
+func WithPollInterval(interval time.Duration) Option {
+	return func(c *Client) {
+		c.pollInterval = interval
+	}
+}
+
+// NewClient server is api endpoint, like http://127.0.0.1:7860
+func NewClient(server string, opts ...Option) (*Client, error) {
+	c := &Client{
+		httpClient:   http.DefaultClient,
+		pollInterval: defaultPollInterval,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	api, err := client.NewClientWithResponses(server, client.WithHTTPClient(c.httpClient),
+		client.WithRequestEditorFn(c.editRequest))
+	if err != nil {
+		return nil, err
+	}
+	c.api = api
+	return c, nil
+}
+
+// add auth and invoke type header
+func (c *Client) editRequest(ctx context.Context, req *http.Request) error {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	if c.token != "" {
+		req.Header.Set(tokenKey, c.token)
+	}
+	if c.userName != "" {
+		req.Header.Set(userKey, c.userName)
+	}
+	if c.async {
+		req.Header.Set(requestTypeKey, asyncRequest)
+	}
+	return nil
+}
+
+// Token current session token
+func (c *Client) Token() string {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.token
+}
+
+// Login login and use session token in later request
+func (c *Client) Login(ctx context.Context, userName, password string) error {
+	resp, err := c.api.LoginWithResponse(ctx, models.UserLoginRequest{UserName: userName, Password: password})
+	if err != nil {
+		return err
+	}
+	if resp.JSON200 == nil {
+		return apiError(resp.HTTPResponse, resp.Body)
+	}
+	c.lock.Lock()
+	c.token = resp.JSON200.Token
+	c.userName = resp.JSON200.UserName
+	c.lock.Unlock()
+	return nil
+}
+
+// Txt2Img submit txt2img task
+func (c *Client) Txt2Img(ctx context.Context, request models.Txt2ImgRequest) (*models.SubmitTaskResponse, error) {
+	resp, err := c.api.Txt2ImgWithResponse(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	if resp.JSON200 == nil {
+		return nil, apiError(resp.HTTPResponse, resp.Body)
+	}
+	return resp.JSON200, nil
+}
+
+// Txt2ImgCached get or create, finished task of identical params returned with cached true,
+// seed of request need fixed
+func (c *Client) Txt2ImgCached(ctx context.Context, request models.Txt2ImgRequest) (*models.SubmitTaskResponse,
+	error) {
+	resp, err := c.api.Txt2ImgCachedWithResponse(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	if resp.JSON200 == nil {
+		return nil, apiError(resp.HTTPResponse, resp.Body)
+	}
+	return resp.JSON200, nil
+}
+
+// Img2Img submit img2img task
+func (c *Client) Img2Img(ctx context.Context, request models.Img2ImgRequest) (*models.SubmitTaskResponse, error) {
+	resp, err := c.api.Img2ImgWithResponse(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	if resp.JSON200 == nil {
+		return nil, apiError(resp.HTTPResponse, resp.Body)
+	}
+	return resp.JSON200, nil
+}
+
+// Progress task progress
+func (c *Client) Progress(ctx context.Context, taskId string) (*models.TaskProgressResponse, error) {
+	resp, err := c.api.GetTaskProgressWithResponse(ctx, taskId)
+	if err != nil {
+		return nil, err
+	}
+	if resp.JSON200 == nil {
+		return nil, apiError(resp.HTTPResponse, resp.Body)
+	}
+	return resp.JSON200, nil
+}
+
+// WatchProgress poll task progress until task terminal or ctx done, onProgress called only with newer seq,
+// stale or duplicate responses dropped, seq gap means intermediate updates overwritten
+func (c *Client) WatchProgress(ctx context.Context, taskId string,
+	onProgress func(progress *models.TaskProgressResponse)) error {
+	ticker := time.NewTicker(c.pollInterval)
+	defer ticker.Stop()
+	var last int64
+	for {
+		progress, err := c.Progress(ctx, taskId)
+		if err != nil {
+			return err
+		}
+		if progress.Seq != nil && *progress.Seq > last {
+			last = *progress.Seq
+			onProgress(progress)
+		}
+		// running task progress at most 0.99
+		if progress.Progress >= 1 {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Result task result, status not finish when task running
+func (c *Client) Result(ctx context.Context, taskId string) (*models.TaskResultResponse, error) {
+	resp, err := c.api.GetTaskResultWithResponse(ctx, taskId)
+	if err != nil {
+		return nil, err
+	}
+	if resp.JSON200 == nil {
+		return nil, apiError(resp.HTTPResponse, resp.Body)
+	}
+	return resp.JSON200, nil
+}
+
+// Results results of a batch of tasks in one request, unknown task ids in notFound
+func (c *Client) Results(ctx context.Context, taskIds []string) (*models.TaskResultsResult, error) {
+	resp, err := c.api.GetTaskResultsWithResponse(ctx, models.TaskResultsRequest{TaskIds: taskIds})
+	if err != nil {
+		return nil, err
+	}
+	if resp.JSON200 == nil {
+		return nil, apiError(resp.HTTPResponse, resp.Body)
+	}
+	return resp.JSON200, nil
+}
+
+// Wait poll task result until task finish or ctx done
+// task failed/cancelled/no output return result and ErrTaskFailed/ErrTaskCancelled/ErrTaskNoOutput,
+// partial upload return result of uploaded images and *PartialUploadError
+func (c *Client) Wait(ctx context.Context, taskId string) (*models.TaskResultResponse, error) {
+	ticker := time.NewTicker(c.pollInterval)
+	defer ticker.Stop()
+	for {
+		result, err := c.Result(ctx, taskId)
+		if err != nil {
+			return nil, err
+		}
+		switch result.Status {
+		case taskFinish:
+			return result, nil
+		case taskFailed:
+			return result, ErrTaskFailed
+		case taskCancelled:
+			return result, ErrTaskCancelled
+		case taskNoOutput:
+			return result, ErrTaskNoOutput
+		case taskPartialUpload:
+			partialErr := new(PartialUploadError)
+			if result.FailedImages != nil {
+				partialErr.FailedImages = *result.FailedImages
+			}
+			return result, partialErr
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// api error message in json body {"message": "..."}
+func apiError(resp *http.Response, body []byte) error {
+	ret := &APIError{StatusCode: resp.StatusCode, Message: string(body)}
+	var message struct {
+		Message *string `json:"message"`
+	}
+	if err := json.Unmarshal(body, &message); err == nil && message.Message != nil {
+		ret.Message = *message.Message
+	}
+	return ret
+}
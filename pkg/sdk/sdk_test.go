@@ -0,0 +1,184 @@
+package sdk
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/datastore"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/handler"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/models"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/module"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestServer mount real handlers in proxy mode, sd webui and downstream are fake
+func newTestServer(t *testing.T) *httptest.Server {
+	gin.SetMode(gin.TestMode)
+	dir := t.TempDir()
+	config.ConfigGlobal = &config.Config{
+		ConfigYaml: config.ConfigYaml{
+			ServerName:        config.PROXY,
+			FlexMode:          "multiFunc",
+			DbSqlite:          filepath.Join(dir, "sqlite3"),
+			OssMode:           config.LOCAL,
+			OssPath:           dir,
+			SdPath:            filepath.Join(dir, "not-exist"),
+			LoginSwitch:       "on",
+			SessionExpire:     config.DefaultSessionExpire,
+			ImageNameTemplate: config.DefaultImageNameTemplate,
+			RenderCacheTTL:    config.DefaultRenderCacheTTL,
+		},
+	}
+	sd := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request models.Txt2ImgRequest
+		json.NewDecoder(r.Body).Decode(&request)
+		images := []string{base64.StdEncoding.EncodeToString([]byte("image"))}
+		// nsfw filter drop all images
+		if request.Prompt != nil && *request.Prompt == "nsfw" {
+			images = []string{}
+		}
+		if request.Prompt != nil && *request.Prompt == "batch" {
+			images = append(images, images[0])
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"images":     images,
+			"parameters": map[string]interface{}{},
+			"info":       `{"seed": 1}`,
+		})
+	}))
+	t.Cleanup(sd.Close)
+	config.ConfigGlobal.SdUrlPrefix = sd.URL
+	downstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	t.Cleanup(downstream.Close)
+	config.ConfigGlobal.Downstream = downstream.URL
+
+	oldOss := module.OssGlobal
+	module.OssGlobal = new(module.OssManagerLocal)
+	t.Cleanup(func() {
+		module.OssGlobal = oldOss
+	})
+	stores := make(map[string]datastore.Datastore)
+	for _, table := range []string{datastore.KTaskTableName, datastore.KModelTableName, datastore.KUserTableName,
+		datastore.KConfigTableName, datastore.KModelServiceTableName} {
+		store := datastore.NewSQLiteDatastore(datastore.NewSQLiteConfig(table))
+		t.Cleanup(func() {
+			store.Close()
+		})
+		stores[table] = store
+	}
+	assert.Nil(t, module.InitUserManager(stores[datastore.KUserTableName]))
+	proxyHandler := handler.NewProxyHandler(stores[datastore.KTaskTableName], stores[datastore.KModelTableName],
+		stores[datastore.KUserTableName], stores[datastore.KConfigTableName], stores[datastore.KModelServiceTableName])
+
+	router := gin.New()
+	router.Use(handler.ApiAuth())
+	handler.RegisterHandlers(router, proxyHandler)
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestClient(t *testing.T) {
+	server := newTestServer(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	c, err := NewClient(server.URL, WithPollInterval(10*time.Millisecond))
+	assert.Nil(t, err)
+
+	// login first
+	_, err = c.Txt2Img(ctx, models.Txt2ImgRequest{StableDiffusionModel: "sd"})
+	var apiErr *APIError
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusGone, apiErr.StatusCode)
+
+	assert.NotNil(t, c.Login(ctx, module.DefaultUser, "wrong"))
+	assert.Nil(t, c.Login(ctx, module.DefaultUser, module.DefaultPasswd))
+	assert.NotEmpty(t, c.Token())
+
+	// sync txt2img
+	submit, err := c.Txt2Img(ctx, models.Txt2ImgRequest{StableDiffusionModel: "sd", Prompt: utils.String("cat")})
+	assert.Nil(t, err)
+	assert.Equal(t, taskFinish, submit.Status)
+	progress, err := c.Progress(ctx, submit.TaskId)
+	assert.Nil(t, err)
+	assert.Equal(t, float32(1), progress.Progress)
+	// finished task report final progress once
+	updates := 0
+	assert.Nil(t, c.WatchProgress(ctx, submit.TaskId, func(progress *models.TaskProgressResponse) {
+		updates++
+		assert.Equal(t, int64(1), *progress.Seq)
+	}))
+	assert.Equal(t, 1, updates)
+	result, err := c.Wait(ctx, submit.TaskId)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"images/admin/" + submit.TaskId + "_1.png"}, *result.Images)
+	image, err := os.ReadFile(filepath.Join(config.ConfigGlobal.OssPath, (*result.Images)[0]))
+	assert.Nil(t, err)
+	assert.Equal(t, "image", string(image))
+	results, err := c.Results(ctx, []string{submit.TaskId, "not-exist"})
+	assert.Nil(t, err)
+	assert.Len(t, results.Results, 1)
+	assert.Equal(t, *result.Images, *results.Results[0].Images)
+	assert.Equal(t, []string{"not-exist"}, results.NotFound)
+
+	// webui return no images
+	submit, err = c.Txt2Img(ctx, models.Txt2ImgRequest{StableDiffusionModel: "sd", Prompt: utils.String("nsfw")})
+	assert.Nil(t, err)
+	assert.Equal(t, taskNoOutput, submit.Status)
+	result, err = c.Wait(ctx, submit.TaskId)
+	assert.ErrorIs(t, err, ErrTaskNoOutput)
+	assert.Equal(t, float64(1), (*result.Info)["seed"])
+
+	// second image of batch upload fail, target path taken by dir
+	assert.Nil(t, os.MkdirAll(filepath.Join(config.ConfigGlobal.OssPath, "images/admin/partial_2.png"), 0755))
+	submit, err = c.Txt2Img(ctx, models.Txt2ImgRequest{StableDiffusionModel: "sd", Prompt: utils.String("batch"),
+		ForceTaskId: "partial"})
+	assert.Nil(t, err)
+	assert.Equal(t, taskPartialUpload, submit.Status)
+	assert.Equal(t, []string{"images/admin/partial_1.png"}, *submit.OssUrl)
+	result, err = c.Wait(ctx, submit.TaskId)
+	assert.ErrorIs(t, err, ErrTaskPartialUpload)
+	var partialErr *PartialUploadError
+	assert.True(t, errors.As(err, &partialErr))
+	assert.Equal(t, []int{1}, partialErr.FailedImages)
+	assert.Equal(t, []string{"images/admin/partial_1.png"}, *result.Images)
+
+	// get or create with fixed seed
+	request := models.Txt2ImgRequest{StableDiffusionModel: "sd", Prompt: utils.String("dog"), Seed: utils.Int64(1)}
+	created, err := c.Txt2ImgCached(ctx, request)
+	assert.Nil(t, err)
+	assert.Nil(t, created.Cached)
+	cached, err := c.Txt2ImgCached(ctx, request)
+	assert.Nil(t, err)
+	assert.Equal(t, created.TaskId, cached.TaskId)
+	assert.True(t, *cached.Cached)
+
+	// async img2img, downstream accept task
+	async, err := NewClient(server.URL, WithToken(c.Token()), WithAsync(), WithPollInterval(10*time.Millisecond))
+	assert.Nil(t, err)
+	submit, err = async.Img2Img(ctx, models.Img2ImgRequest{StableDiffusionModel: "sd"})
+	assert.Nil(t, err)
+	assert.Equal(t, "waiting", submit.Status)
+	waitCtx, waitCancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer waitCancel()
+	_, err = async.Wait(waitCtx, submit.TaskId)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// not found
+	_, err = c.Result(ctx, "not-exist")
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+}
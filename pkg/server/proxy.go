@@ -2,6 +2,7 @@ package server
 
 import (
 	"context"
+	"github.com/devsapp/serverless-stable-diffusion-api/pkg/client"
 	"github.com/devsapp/serverless-stable-diffusion-api/pkg/config"
 	"github.com/devsapp/serverless-stable-diffusion-api/pkg/datastore"
 	"github.com/devsapp/serverless-stable-diffusion-api/pkg/handler"
@@ -20,6 +21,7 @@ type ProxyServer struct {
 	userDataStore  datastore.Datastore
 	funcDataStore  datastore.Datastore
 	configStore    datastore.Datastore
+	proxyHandler   *handler.ProxyHandler
 }
 
 func NewProxyServer(port string, dbType datastore.DatastoreType, mode string) (*ProxyServer, error) {
@@ -28,9 +30,11 @@ func NewProxyServer(port string, dbType datastore.DatastoreType, mode string) (*
 		logrus.Errorf("oss init error %v", err)
 		return nil, err
 	}
+	// publish task event to mns if config
+	module.InitMnsPublisher()
 	tableFactory := datastore.DatastoreFactory{}
 	// init task table
-	taskDataStore := tableFactory.NewTable(dbType, datastore.KTaskTableName)
+	taskDataStore := module.WithTaskNotify(tableFactory.NewTable(dbType, datastore.KTaskTableName))
 	// init model table
 	modelDataStore := tableFactory.NewTable(dbType, datastore.KModelTableName)
 	// init user table
@@ -55,9 +59,17 @@ func NewProxyServer(port string, dbType datastore.DatastoreType, mode string) (*
 		// add config listen task
 		listenTask.AddTask("configTask", module.ConfigListen, module.ConfigEvent)
 	}
+	client.ManagerClientGlobal.EnableBreaker(config.ConfigGlobal.BreakerThreshold,
+		config.ConfigGlobal.GetBreakerCooldown())
 	// init handler
 	proxyHandler := handler.NewProxyHandler(taskDataStore, modelDataStore, userDataStore,
 		configDataStore, funcDataStore)
+	if config.ConfigGlobal.IsServerTypeMatch(config.CONTROL) {
+		// keep warmPool models warm
+		proxyHandler.StartWarmPool()
+	}
+	// purge expired task images and rows
+	proxyHandler.StartJanitor()
 
 	// init router
 	if mode == gin.DebugMode {
@@ -67,13 +79,18 @@ func NewProxyServer(port string, dbType datastore.DatastoreType, mode string) (*
 	}
 	router := gin.New()
 	router.Use(CORSMiddleware())
+	router.Use(handler.SchemaVersion())
 	router.Use(gin.Logger(), gin.Recovery())
 	router.Use(handler.Stat())
+	router.Use(handler.BodyLimit(config.ConfigGlobal.GetMaxRequestBodyBytes()))
 
 	// auth permission check
 	if config.ConfigGlobal.EnableLogin() {
 		router.Use(handler.ApiAuth())
+		router.Use(handler.AdminAuth())
 	}
+	router.Use(proxyHandler.MaintenanceMode())
+	router.Use(handler.UploadPolicy())
 	handler.RegisterHandlers(router, proxyHandler)
 	router.NoRoute(proxyHandler.NoRouterHandler)
 
@@ -87,6 +104,7 @@ func NewProxyServer(port string, dbType datastore.DatastoreType, mode string) (*
 		modelDataStore: modelDataStore,
 		funcDataStore:  funcDataStore,
 		configStore:    configDataStore,
+		proxyHandler:   proxyHandler,
 	}, nil
 }
 
@@ -99,8 +117,21 @@ func (p *ProxyServer) Start() error {
 	return nil
 }
 
-// Close shutdown proxy server, timeout=shutdownTimeout
+// Close drain tasks then shutdown proxy server, each wait at most shutdownTimeout
 func (p *ProxyServer) Close(shutdownTimeout time.Duration) error {
+	if p.proxyHandler != nil {
+		// async tasks return before done, wait them with sync submissions
+		drainCtx, drainCancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		if !p.proxyHandler.Drain(drainCtx) {
+			logrus.Warn("shutdown before all tasks drained")
+		}
+		drainCancel()
+		p.proxyHandler.StopWarmPool()
+		p.proxyHandler.StopJanitor()
+	}
+	if module.FuncManagerGlobal != nil {
+		module.FuncManagerGlobal.Close()
+	}
 	if p.userDataStore != nil {
 		p.userDataStore.Close()
 	}
@@ -131,6 +162,7 @@ func CORSMiddleware() gin.HandlerFunc {
 		c.Writer.Header().Set("Access-Control-Allow-Methods", "*")
 		c.Writer.Header().Set("Access-Control-Allow-Headers", "*")
 		c.Writer.Header().Set("Access-Control-Allow-Credentials", "false")
+		c.Writer.Header().Set("Access-Control-Expose-Headers", "X-API-Schema-Version, X-Sticky-Route")
 		c.Next()
 	}
 }
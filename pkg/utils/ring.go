@@ -0,0 +1,49 @@
+package utils
+
+import "sync"
+
+// LineRing fixed size ring buffer, keep latest lines
+type LineRing struct {
+	lock  sync.Mutex
+	lines []string
+	next  int
+	full  bool
+}
+
+func NewLineRing(size int) *LineRing {
+	return &LineRing{
+		lines: make([]string, size),
+	}
+}
+
+// Add append line, overwrite the oldest when full
+func (r *LineRing) Add(line string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if len(r.lines) == 0 {
+		return
+	}
+	r.lines[r.next] = line
+	r.next = (r.next + 1) % len(r.lines)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// Tail return latest n lines, oldest first
+func (r *LineRing) Tail(n int) []string {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	count := r.next
+	if r.full {
+		count = len(r.lines)
+	}
+	if n > count {
+		n = count
+	}
+	ret := make([]string, 0, n)
+	for i := n; i > 0; i-- {
+		ret = append(ret, r.lines[(r.next-i+len(r.lines))%len(r.lines)])
+	}
+	return ret
+}
@@ -58,14 +58,26 @@ func String(s string) *string {
 	return &s
 }
 
+func Int(v int) *int {
+	return &v
+}
+
 func Int32(v int32) *int32 {
 	return &v
 }
 
+func Int64(v int64) *int64 {
+	return &v
+}
+
 func Float32(v float32) *float32 {
 	return &v
 }
 
+func Float64(v float64) *float64 {
+	return &v
+}
+
 func Bool(v bool) *bool {
 	return &v
 }
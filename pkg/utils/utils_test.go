@@ -42,3 +42,15 @@ func find(L []int, val int) int {
 	}
 	return low
 }
+
+func TestLineRing(t *testing.T) {
+	ring := NewLineRing(3)
+	assert.Empty(t, ring.Tail(2))
+	ring.Add("a")
+	ring.Add("b")
+	assert.Equal(t, []string{"a", "b"}, ring.Tail(5))
+	ring.Add("c")
+	ring.Add("d")
+	assert.Equal(t, []string{"b", "c", "d"}, ring.Tail(3))
+	assert.Equal(t, []string{"c", "d"}, ring.Tail(2))
+}